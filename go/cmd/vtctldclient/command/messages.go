@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mdibaiee/vitess/go/cmd/vtctldclient/cli"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+
+	vtctldatapb "github.com/mdibaiee/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// InspectMessages makes an InspectMessages gRPC call to a vtctld.
+	InspectMessages = &cobra.Command{
+		Use:   "InspectMessages [--limit <limit>] [--json|-j] <keyspace> <table>",
+		Short: "Lists the dead-lettered messages of a message table.",
+		Long: `Lists the dead-lettered messages of a message table from the primary of each shard in the keyspace.
+
+Messages are dead-lettered after vt_max_attempts unacknowledged sends. They are either moved to the
+table named by vt_dead_letter_table, or kept in the message table and no longer sent.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandInspectMessages,
+	}
+	// RequeueMessages makes a RequeueMessages gRPC call to a vtctld.
+	RequeueMessages = &cobra.Command{
+		Use:                   "RequeueMessages <keyspace> <table> <id> [<id> ...]",
+		Short:                 "Requeues the given dead-lettered messages so that they are sent again with a reset attempt count.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(3),
+		RunE:                  commandRequeueMessages,
+	}
+)
+
+var inspectMessagesOptions = struct {
+	Limit int64
+	JSON  bool
+}{}
+
+func commandInspectMessages(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.InspectMessages(commandCtx, &vtctldatapb.InspectMessagesRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Table:    cmd.Flags().Arg(1),
+		Limit:    inspectMessagesOptions.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	qr := sqltypes.Proto3ToResult(resp.Result)
+	switch inspectMessagesOptions.JSON {
+	case true:
+		data, err := cli.MarshalJSON(qr)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+	default:
+		cli.WriteQueryResultTable(cmd.OutOrStdout(), qr)
+	}
+
+	return nil
+}
+
+func commandRequeueMessages(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RequeueMessages(commandCtx, &vtctldatapb.RequeueMessagesRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Table:    cmd.Flags().Arg(1),
+		Ids:      cmd.Flags().Args()[2:],
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Requeued %d message(s)\n", resp.Count)
+	return nil
+}
+
+func init() {
+	InspectMessages.Flags().Int64Var(&inspectMessagesOptions.Limit, "limit", 100, "The maximum number of messages to list from each shard.")
+	InspectMessages.Flags().BoolVarP(&inspectMessagesOptions.JSON, "json", "j", false, "Output the results in JSON instead of a human-readable table.")
+	Root.AddCommand(InspectMessages)
+
+	Root.AddCommand(RequeueMessages)
+}
@@ -64,6 +64,14 @@ func (c fallbackClient) ResolveTransaction(ctx context.Context, dtid string) err
 	return c.fallback.ResolveTransaction(ctx, dtid)
 }
 
+func (c fallbackClient) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	return c.fallback.MessageInspect(ctx, keyspace, name, limit)
+}
+
+func (c fallbackClient) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	return c.fallback.MessageRequeue(ctx, keyspace, name, ids)
+}
+
 func (c fallbackClient) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error {
 	return c.fallback.VStream(ctx, tabletType, vgtid, filter, flags, send)
 }
@@ -75,6 +75,14 @@ func (c *terminalClient) ResolveTransaction(ctx context.Context, dtid string) er
 	return errTerminal
 }
 
+func (c *terminalClient) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	return nil, errTerminal
+}
+
+func (c *terminalClient) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	return 0, errTerminal
+}
+
 func (c *terminalClient) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error {
 	return errTerminal
 }
@@ -133,3 +133,16 @@ func TestPushFrontValue(t *testing.T) {
 	assert.Equal(t, a, l.Front())
 	assert.Equal(t, a, e.prev)
 }
+
+func TestInsertAfterValue(t *testing.T) {
+	l := New[int]()
+	e1 := l.PushBack(1)
+	e3 := l.PushBack(3)
+	e2 := &Element[int]{Value: 2}
+	l.InsertAfterValue(e2, e1)
+	assert.Equal(t, 3, l.Len())
+	assert.Equal(t, e2, e1.Next())
+	assert.Equal(t, e3, e2.Next())
+
+	assert.Panics(t, func() { New[int]().InsertAfterValue(&Element[int]{Value: 4}, e1) })
+}
@@ -174,7 +174,7 @@ func Connect(ctx context.Context, params *ConnParams) (*Conn, error) {
 // Ping implements mysql ping command.
 func (c *Conn) Ping() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComPing
 
@@ -306,6 +306,12 @@ func (c *Conn) clientHandshake(params *ConnParams) error {
 		return err
 	}
 
+	// The packets following the OK packet are compressed, if the server
+	// supports the algorithm we asked for.
+	if err := c.startCompression(); err != nil {
+		return sqlerror.NewSQLError(sqlerror.CRUnknownError, sqlerror.SSUnknownSQLState, "cannot start %s compression: %v", c.compressionAlgorithm, err)
+	}
+
 	// If the server didn't support DbName in its handshake, set
 	// it now. This is what the 'mysql' client does.
 	if capabilities&CapabilityClientConnectWithDB == 0 && params.DbName != "" {
@@ -541,6 +547,32 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 
 	// FIXME(alainjobart) add multi statement.
 
+	// Ask for compression, if the server supports the algorithm.
+	c.compressionAlgorithm = ""
+	switch params.Compression {
+	case CompressionZlib:
+		if capabilities&CapabilityClientCompress != 0 {
+			capabilityFlags |= CapabilityClientCompress
+			c.compressionAlgorithm = CompressionZlib
+			c.compressionLevel = DefaultZlibCompressionLevel
+		}
+	case CompressionZstd:
+		if capabilities&CapabilityClientZstdCompressionAlgorithm != 0 {
+			capabilityFlags |= CapabilityClientZstdCompressionAlgorithm
+			c.compressionAlgorithm = CompressionZstd
+			c.compressionLevel = DefaultZstdCompressionLevel
+		}
+	case "":
+	default:
+		return sqlerror.NewSQLError(sqlerror.CRUnknownError, sqlerror.SSUnknownSQLState, "unknown compression algorithm %q", params.Compression)
+	}
+	if c.compressionAlgorithm != "" && params.CompressionLevel != 0 {
+		if !validCompressionLevel(c.compressionAlgorithm, params.CompressionLevel) {
+			return sqlerror.NewSQLError(sqlerror.CRUnknownError, sqlerror.SSUnknownSQLState, "invalid %s compression level %v", c.compressionAlgorithm, params.CompressionLevel)
+		}
+		c.compressionLevel = params.CompressionLevel
+	}
+
 	length :=
 		4 + // Client capability flags.
 			4 + // Max-packet size.
@@ -564,6 +596,11 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		length++
 	}
 
+	// The zstd compression level.
+	if c.compressionAlgorithm == CompressionZstd {
+		length++
+	}
+
 	data, pos := c.startEphemeralPacketWithHeader(length)
 
 	// Client capability flags.
@@ -600,6 +637,12 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 	// Assume native client during response
 	pos = writeNullString(data, pos, string(c.authPluginName))
 
+	// The zstd compression level comes after the connection attributes,
+	// which we don't send.
+	if c.compressionAlgorithm == CompressionZstd {
+		pos = writeByte(data, pos, byte(c.compressionLevel))
+	}
+
 	// Sanity-check the length.
 	if pos != len(data) {
 		return sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "writeHandshakeResponse41: only packed %v bytes, out of %v allocated", pos, len(data))
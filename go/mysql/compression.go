@@ -0,0 +1,297 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+)
+
+// CompressionAlgorithm is an algorithm of the compressed protocol.
+type CompressionAlgorithm string
+
+// Algorithms of the compressed protocol, named like in the
+// protocol_compression_algorithms variable of MySQL.
+const (
+	CompressionZlib CompressionAlgorithm = "zlib"
+	CompressionZstd CompressionAlgorithm = "zstd"
+)
+
+const (
+	// DefaultZlibCompressionLevel is the zlib level MySQL uses.
+	DefaultZlibCompressionLevel = 6
+
+	// DefaultZstdCompressionLevel is the default zstd level of the MySQL clients.
+	DefaultZstdCompressionLevel = 3
+
+	// compressedHeaderSize is the size of the header of the compressed
+	// packets: the 3 bytes of the length of the payload, the sequence
+	// number, and the 3 bytes of the length of the uncompressed payload.
+	compressedHeaderSize = 7
+
+	// minCompressLength is the size under which the payloads are
+	// sent uncompressed, like MIN_COMPRESS_LENGTH of MySQL.
+	minCompressLength = 50
+)
+
+// ParseCompressionAlgorithm returns the CompressionAlgorithm of its name.
+func ParseCompressionAlgorithm(name string) (CompressionAlgorithm, error) {
+	switch algorithm := CompressionAlgorithm(name); algorithm {
+	case CompressionZlib, CompressionZstd:
+		return algorithm, nil
+	}
+	return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown compression algorithm %q, expected zlib or zstd", name)
+}
+
+// validCompressionLevel returns true if level is a valid compression level
+// of the algorithm.
+func validCompressionLevel(algorithm CompressionAlgorithm, level int) bool {
+	switch algorithm {
+	case CompressionZlib:
+		return level >= zlib.BestSpeed && level <= zlib.BestCompression
+	case CompressionZstd:
+		return level >= 1 && level <= 22
+	}
+	return false
+}
+
+// zstdPacketDecoder decodes the zstd compressed packets, which are never
+// larger than MaxPacketSize once decompressed.
+var zstdPacketDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(MaxPacketSize))
+
+// zstdEncoders are the zstd encoders of each level, shared by the
+// connections. They are safe for concurrent use by EncodeAll.
+var zstdEncoders sync.Map // map[int]*zstd.Encoder
+
+func zstdEncoder(level int) (*zstd.Encoder, error) {
+	if encoder, ok := zstdEncoders.Load(level); ok {
+		return encoder.(*zstd.Encoder), nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := zstdEncoders.LoadOrStore(level, encoder)
+	return actual.(*zstd.Encoder), nil
+}
+
+// compressedIO frames the packets of a connection in compressed packets,
+// once the client and the server negotiated compression in the handshake.
+//
+// It reads the compressed packets of the underlying reader, and serves the
+// stream of the decompressed packets they contain. Every write to it is sent
+// as one or more compressed packets, so that the buffered writer of the
+// connection compresses a whole buffer at once.
+type compressedIO struct {
+	algorithm CompressionAlgorithm
+	level     int
+
+	r io.Reader
+	w io.Writer
+
+	// sequence is the sequence number of the compressed packets, which is
+	// reset with the sequence number of the packets at each command.
+	sequence uint8
+
+	// in is the payload of the last compressed packet read, and data
+	// its payload once decompressed in buf, which is served from pos.
+	in   []byte
+	buf  []byte
+	data []byte
+	pos  int
+	zr   io.ReadCloser
+
+	// out is the last compressed packet written.
+	out     []byte
+	zw      *zlib.Writer
+	zbuf    bytes.Buffer
+	encoder *zstd.Encoder
+}
+
+func newCompressedIO(algorithm CompressionAlgorithm, level int, r io.Reader, w io.Writer) (*compressedIO, error) {
+	z := &compressedIO{
+		algorithm: algorithm,
+		level:     level,
+		r:         r,
+		w:         w,
+	}
+	var err error
+	switch algorithm {
+	case CompressionZlib:
+		z.zw, err = zlib.NewWriterLevel(&z.zbuf, level)
+	case CompressionZstd:
+		z.encoder, err = zstdEncoder(level)
+	default:
+		err = vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unknown compression algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// Read implements io.Reader.
+func (z *compressedIO) Read(p []byte) (int, error) {
+	for z.pos == len(z.data) {
+		if err := z.readPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, z.data[z.pos:])
+	z.pos += n
+	return n, nil
+}
+
+// readPacket reads the next compressed packet.
+func (z *compressedIO) readPacket() error {
+	var header [compressedHeaderSize]byte
+	if _, err := io.ReadFull(z.r, header[:]); err != nil {
+		return err
+	}
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	if sequence := header[3]; sequence != z.sequence {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid compressed sequence, expected %v got %v", z.sequence, sequence)
+	}
+	z.sequence++
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+
+	if cap(z.in) < length {
+		z.in = make([]byte, length)
+	}
+	z.in = z.in[:length]
+	if _, err := io.ReadFull(z.r, z.in); err != nil {
+		return vterrors.Wrapf(err, "io.ReadFull(compressed packet body of length %v) failed", length)
+	}
+	z.pos = 0
+	if uncompressedLength == 0 {
+		// The payload was too small to be compressed.
+		z.data = z.in
+		return nil
+	}
+
+	if cap(z.buf) < uncompressedLength {
+		z.buf = make([]byte, uncompressedLength)
+	}
+	switch z.algorithm {
+	case CompressionZlib:
+		z.data = z.buf[:uncompressedLength]
+		var err error
+		if z.zr == nil {
+			z.zr, err = zlib.NewReader(bytes.NewReader(z.in))
+		} else {
+			err = z.zr.(zlib.Resetter).Reset(bytes.NewReader(z.in), nil)
+		}
+		if err == nil {
+			_, err = io.ReadFull(z.zr, z.data)
+		}
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zlib packet")
+		}
+	case CompressionZstd:
+		data, err := zstdPacketDecoder.DecodeAll(z.in, z.buf[:0])
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zstd packet")
+		}
+		if len(data) != uncompressedLength {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid compressed packet, expected %v bytes got %v", uncompressedLength, len(data))
+		}
+		z.data = data
+	}
+	return nil
+}
+
+// Write implements io.Writer.
+func (z *compressedIO) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > MaxPacketSize {
+			chunk = chunk[:MaxPacketSize]
+		}
+		if err := z.writePacket(chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// writePacket writes data as one compressed packet. The payload is sent
+// uncompressed if it is small or if compression does not make it smaller.
+func (z *compressedIO) writePacket(data []byte) error {
+	out := append(z.out[:0], make([]byte, compressedHeaderSize)...)
+	uncompressedLength := 0
+	if len(data) >= minCompressLength {
+		compressed, err := z.compress(out, data)
+		if err != nil {
+			return err
+		}
+		if len(compressed)-compressedHeaderSize < len(data) {
+			out = compressed
+			uncompressedLength = len(data)
+		}
+	}
+	if uncompressedLength == 0 {
+		out = append(out[:compressedHeaderSize], data...)
+	}
+
+	length := len(out) - compressedHeaderSize
+	out[0] = byte(length)
+	out[1] = byte(length >> 8)
+	out[2] = byte(length >> 16)
+	out[3] = z.sequence
+	out[4] = byte(uncompressedLength)
+	out[5] = byte(uncompressedLength >> 8)
+	out[6] = byte(uncompressedLength >> 16)
+	z.sequence++
+	z.out = out
+
+	if n, err := z.w.Write(out); err != nil {
+		return vterrors.Wrapf(err, "Write(compressed packet) failed")
+	} else if n != len(out) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Write(compressed packet) returned a short write: %v < %v", n, len(out))
+	}
+	return nil
+}
+
+// compress appends the compressed data to dst.
+func (z *compressedIO) compress(dst, data []byte) ([]byte, error) {
+	switch z.algorithm {
+	case CompressionZlib:
+		z.zbuf.Reset()
+		z.zw.Reset(&z.zbuf)
+		if _, err := z.zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := z.zw.Close(); err != nil {
+			return nil, err
+		}
+		return append(dst, z.zbuf.Bytes()...), nil
+	case CompressionZstd:
+		return z.encoder.EncodeAll(data, dst), nil
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unknown compression algorithm %q", z.algorithm)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompressionAlgorithm(t *testing.T) {
+	algorithm, err := ParseCompressionAlgorithm("zstd")
+	require.NoError(t, err)
+	assert.Equal(t, CompressionZstd, algorithm)
+
+	_, err = ParseCompressionAlgorithm("lz4")
+	assert.ErrorContains(t, err, `unknown compression algorithm "lz4"`)
+}
+
+func TestCompressedIO(t *testing.T) {
+	payloads := map[string][]byte{
+		"empty":        {},
+		"small":        []byte("select 1"),
+		"compressible": bytes.Repeat([]byte("select * from t where id = 1;"), 100),
+		"large":        bytes.Repeat([]byte("0123456789abcdef"), MaxPacketSize/8),
+	}
+	levels := map[CompressionAlgorithm]int{
+		CompressionZlib: DefaultZlibCompressionLevel,
+		CompressionZstd: DefaultZstdCompressionLevel,
+	}
+	for algorithm, level := range levels {
+		for name, payload := range payloads {
+			t.Run(string(algorithm)+"/"+name, func(t *testing.T) {
+				var network bytes.Buffer
+				w, err := newCompressedIO(algorithm, level, nil, &network)
+				require.NoError(t, err)
+				n, err := w.Write(payload)
+				require.NoError(t, err)
+				assert.Equal(t, len(payload), n)
+				if len(payload) > minCompressLength {
+					assert.Less(t, network.Len(), len(payload))
+				}
+
+				r, err := newCompressedIO(algorithm, level, &network, nil)
+				require.NoError(t, err)
+				data := make([]byte, len(payload))
+				_, err = io.ReadFull(r, data)
+				require.NoError(t, err)
+				assert.True(t, bytes.Equal(payload, data))
+				assert.Equal(t, w.sequence, r.sequence)
+			})
+		}
+	}
+}
+
+func TestCompressedIOSequence(t *testing.T) {
+	var network bytes.Buffer
+	w, err := newCompressedIO(CompressionZlib, DefaultZlibCompressionLevel, nil, &network)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("first"))
+	require.NoError(t, err)
+
+	r, err := newCompressedIO(CompressionZlib, DefaultZlibCompressionLevel, &network, nil)
+	require.NoError(t, err)
+	r.sequence = 1
+	_, err = r.Read(make([]byte, 5))
+	assert.ErrorContains(t, err, "invalid compressed sequence, expected 1 got 0")
+}
+
+func newCompressionTestListener(t *testing.T, th *testHandler, algorithms ...CompressionAlgorithm) (string, int) {
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+	}}
+	t.Cleanup(authServer.close)
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	l.CompressionAlgorithms = algorithms
+	t.Cleanup(l.Close)
+	go l.Accept()
+
+	return getHostPort(t, l.Addr())
+}
+
+func TestServerCompression(t *testing.T) {
+	th := &testHandler{}
+	host, port := newCompressionTestListener(t, th, CompressionZlib, CompressionZstd)
+
+	query := benchmarkQueryPrefix + strings.Repeat("compressed ", 10000)
+	for _, params := range []*ConnParams{
+		{Host: host, Port: port, Uname: "user1", Pass: "password1"},
+		{Host: host, Port: port, Uname: "user1", Pass: "password1", Compression: CompressionZlib},
+		{Host: host, Port: port, Uname: "user1", Pass: "password1", Compression: CompressionZstd, CompressionLevel: 7},
+	} {
+		t.Run(string(params.Compression), func(t *testing.T) {
+			c, err := Connect(context.Background(), params)
+			require.NoError(t, err)
+			defer c.Close()
+			if params.Compression == "" {
+				assert.Nil(t, c.compression)
+			} else {
+				require.NotNil(t, c.compression)
+				assert.Equal(t, params.Compression, c.compression.algorithm)
+				assert.Equal(t, th.LastConn().compressionAlgorithm, params.Compression)
+			}
+
+			for i := 0; i < 3; i++ {
+				result, err := c.ExecuteFetch(query, 10, false)
+				require.NoError(t, err)
+				require.Len(t, result.Rows, 1)
+				assert.Equal(t, query, result.Rows[0][0].ToString())
+			}
+		})
+	}
+
+	// The zstd level is chosen by the client.
+	c, err := Connect(context.Background(), &ConnParams{Host: host, Port: port, Uname: "user1", Pass: "password1", Compression: CompressionZstd, CompressionLevel: 7})
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.ExecuteFetch("select rows", 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, 7, th.LastConn().compressionLevel)
+
+	// The protocol is not compressed if the server does not support the algorithm.
+	host, port = newCompressionTestListener(t, th, CompressionZlib)
+	c, err = Connect(context.Background(), &ConnParams{Host: host, Port: port, Uname: "user1", Pass: "password1", Compression: CompressionZstd})
+	require.NoError(t, err)
+	defer c.Close()
+	assert.Nil(t, c.compression)
+	_, err = c.ExecuteFetch("select rows", 10, false)
+	require.NoError(t, err)
+}
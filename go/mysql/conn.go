@@ -194,6 +194,15 @@ type Conn struct {
 	// Packet encoding variables.
 	sequence uint8
 
+	// compressionAlgorithm and compressionLevel are the compression
+	// negotiated in the handshake, which starts once it is done.
+	compressionAlgorithm CompressionAlgorithm
+	compressionLevel     int
+
+	// compression frames the packets in compressed packets, once the
+	// compression started. It is nil if the protocol is not compressed.
+	compression *compressedIO
+
 	// ExpectSemiSyncIndicator is applicable when the connection is used for replication (ComBinlogDump).
 	// When 'true', events are assumed to be padded with 2-byte semi-sync information
 	// See https://dev.mysql.com/doc/internals/en/semi-sync-binlog-event.html
@@ -227,6 +236,13 @@ type PrepareData struct {
 	BindVars    map[string]*querypb.BindVariable
 	StatementID uint32
 	ParamsCount uint16
+	// CursorType holds the cursor type flags of the COM_STMT_EXECUTE
+	// being executed.
+	CursorType byte
+
+	// cursor is the cursor opened by the last execution of the
+	// statement, nil if there is none.
+	cursor *cursor
 }
 
 // execResult is an enum signifying the result of executing a query
@@ -260,6 +276,17 @@ func newConn(conn net.Conn, flushDelay time.Duration, truncateErrLen int) *Conn
 	}
 }
 
+// NewAuthConn returns a Conn for a connection accepted by the server of
+// another protocol, like the X Protocol, so that the AuthServer
+// implementations can authenticate its users. The Conn cannot be used to
+// read or write packets.
+func NewAuthConn(conn net.Conn, connectionID uint32) *Conn {
+	return &Conn{
+		conn:         conn,
+		ConnectionID: connectionID,
+	}
+}
+
 // newServerConn should be used to create server connections.
 //
 // It stashes a reference to the listener to be able to determine if
@@ -323,7 +350,7 @@ func (c *Conn) startWriterBuffering() {
 	defer c.bufMu.Unlock()
 
 	c.bufferedWriter = writersPool.Get().(*bufio.Writer)
-	c.bufferedWriter.Reset(c.conn)
+	c.bufferedWriter.Reset(c.getWriter())
 }
 
 // endWriterBuffering must be called to terminate startWriteBuffering.
@@ -371,14 +398,55 @@ func (c *Conn) startFlushTimer() {
 }
 
 // getReader returns reader for connection. It can be *bufio.Reader or net.Conn
-// depending on which buffer size was passed to newServerConn.
+// depending on which buffer size was passed to newServerConn, or the
+// compressedIO reading from them once the compression started.
 func (c *Conn) getReader() io.Reader {
+	if c.compression != nil {
+		return c.compression
+	}
+	return c.getRawReader()
+}
+
+func (c *Conn) getRawReader() io.Reader {
 	if c.bufferedReader != nil {
 		return c.bufferedReader
 	}
 	return c.conn
 }
 
+// getWriter returns the writer of the packets, which is the net.Conn or the
+// compressedIO writing to it once the compression started.
+func (c *Conn) getWriter() io.Writer {
+	if c.compression != nil {
+		return c.compression
+	}
+	return c.conn
+}
+
+// startCompression starts the compression negotiated in the handshake, if
+// any. It must be called once the handshake is done, before the next
+// command.
+func (c *Conn) startCompression() error {
+	if c.compressionAlgorithm == "" {
+		return nil
+	}
+	compression, err := newCompressedIO(c.compressionAlgorithm, c.compressionLevel, c.getRawReader(), c.conn)
+	if err != nil {
+		return err
+	}
+	c.compression = compression
+	return nil
+}
+
+// resetSequence resets the sequence numbers of the packets, and of the
+// compressed packets, for a new command.
+func (c *Conn) resetSequence() {
+	c.sequence = 0
+	if c.compression != nil {
+		c.compression.sequence = 0
+	}
+}
+
 func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	// Note io.ReadFull will return two different types of errors:
 	// 1. if the socket is already closed, and the go runtime knows it,
@@ -611,7 +679,7 @@ func (c *Conn) writePacket(data []byte) error {
 		}()
 	} else {
 		c.bufMu.Unlock()
-		w = c.conn
+		w = c.getWriter()
 	}
 
 	var header [packetHeaderSize]byte
@@ -715,7 +783,7 @@ func (c *Conn) recycleWritePacket() {
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComQuit() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComQuit
@@ -921,7 +989,7 @@ func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
 // handleNextCommand is called in the server loop to process
 // incoming packets.
 func (c *Conn) handleNextCommand(handler Handler) bool {
-	c.sequence = 0
+	c.resetSequence()
 	data, err := c.readEphemeralPacket()
 	if err != nil {
 		// Don't log EOF errors. They cause too much spam.
@@ -938,6 +1006,19 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		return false
 	}
 
+	switch data[0] {
+	case ComStmtFetch, ComStmtClose, ComStmtReset, ComStmtSendLongData, ComPing, ComQuit:
+	case ComStmtExecute:
+		// executing a statement again closes its cursor, there is no need to read it.
+		if stmtID, _, ok := readUint32(data, 1); ok {
+			c.closeCursor(stmtID)
+		}
+		c.materializeCursors()
+	default:
+		// the handler is about to be called: it must not be running a cursor at the same time.
+		c.materializeCursors()
+	}
+
 	switch data[0] {
 	case ComQuit:
 		c.recycleReadPacket()
@@ -957,12 +1038,15 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		return c.handleComPrepare(handler, data)
 	case ComStmtExecute:
 		return c.handleComStmtExecute(handler, data)
+	case ComStmtFetch:
+		return c.handleComStmtFetch(handler, data)
 	case ComStmtSendLongData:
 		return c.handleComStmtSendLongData(data)
 	case ComStmtClose:
 		stmtID, ok := c.parseComStmtClose(data)
 		c.recycleReadPacket()
 		if ok {
+			c.closeCursor(stmtID)
 			delete(c.PrepareData, stmtID)
 		}
 	case ComStmtReset:
@@ -1061,6 +1145,7 @@ func (c *Conn) handleComBinlogDumpGTID(handler Handler, data []byte) (kontinue b
 func (c *Conn) handleComResetConnection(handler Handler) {
 	// Clean up and reset the connection
 	c.recycleReadPacket()
+	c.closeCursors()
 	handler.ComResetConnection(c)
 	// Reset prepared statements
 	c.PrepareData = make(map[uint32]*PrepareData)
@@ -1093,6 +1178,7 @@ func (c *Conn) handleComStmtReset(data []byte) bool {
 			prepare.BindVars[k] = nil
 		}
 	}
+	c.closeCursor(stmtID)
 
 	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
 		log.Error("Error writing ComStmtReset OK packet to client %v: %v", c.ConnectionID, err)
@@ -1140,7 +1226,7 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		}
 	}()
 	queryStart := time.Now()
-	stmtID, _, err := c.parseComStmtExecute(c.PrepareData, data)
+	stmtID, cursorType, err := c.parseComStmtExecute(c.PrepareData, data)
 	c.recycleReadPacket()
 
 	if stmtID != uint32(0) {
@@ -1155,10 +1241,19 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		return c.writeErrorPacketFromErrorAndLog(err)
 	}
 
+	prepare := c.PrepareData[stmtID]
+	prepare.CursorType = cursorType
+	if cursorType&CursorTypeReadOnly != 0 {
+		if !c.executeWithCursor(handler, prepare) {
+			return false
+		}
+		timings.Record(queryTimingKey, queryStart)
+		return true
+	}
+
 	fieldSent := false
 	// sendFinished is set if the response should just be an OK packet.
 	sendFinished := false
-	prepare := c.PrepareData[stmtID]
 	err = handler.ComStmtExecute(c, prepare, func(qr *sqltypes.Result) error {
 		if sendFinished {
 			// Failsafe: Unreachable if server is well-behaved.
@@ -1221,6 +1316,114 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 	return true
 }
 
+// executeWithCursor executes a prepared statement for which the client asked
+// for a read-only cursor. If the statement returns rows, only their fields are
+// sent: the client then reads the rows with COM_STMT_FETCH.
+func (c *Conn) executeWithCursor(handler Handler, prepare *PrepareData) bool {
+	cur := c.openCursor(handler, prepare)
+	qr, ok := cur.next()
+	if !ok {
+		err := cur.err
+		if err == nil || err == io.EOF {
+			err = sqlerror.NewSQLErrorFromError(errors.New("unexpected: query ended without no results and no error"))
+		}
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	if len(qr.Fields) == 0 {
+		// Nothing to fetch, let the handler finish and send the OK packet.
+		cur.materialize()
+		if cur.err != nil {
+			return c.writeErrorPacketFromErrorAndLog(cur.err)
+		}
+		if err := c.writeOKPacket(&PacketOK{
+			affectedRows:     qr.RowsAffected,
+			lastInsertID:     qr.InsertID,
+			statusFlags:      c.StatusFlags,
+			sessionStateData: qr.SessionStateChanges,
+		}); err != nil {
+			log.Errorf("Error writing result to %s: %v", c, err)
+			return false
+		}
+		return true
+	}
+
+	cur.fields = qr.Fields
+	cur.rows = qr.Rows
+	prepare.cursor = cur
+	if err := c.writeColumnDefinitions(qr); err != nil {
+		log.Errorf("Error writing fields to %s: %v", c, err)
+		return false
+	}
+	if err := c.writeCursorStatus(ServerStatusCursorExists, 0); err != nil {
+		log.Errorf("Error writing result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
+func (c *Conn) handleComStmtFetch(handler Handler, data []byte) (kontinue bool) {
+	c.startWriterBuffering()
+	defer func() {
+		if err := c.endWriterBuffering(); err != nil {
+			log.Errorf("conn %v: flush() failed: %v", c.ID(), err)
+			kontinue = false
+		}
+	}()
+
+	stmtID, numRows, ok := c.parseComStmtFetch(data)
+	c.recycleReadPacket()
+	if !ok {
+		return c.writeErrorPacketFromErrorAndLog(sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "error parsing statement fetch: %v", data))
+	}
+	prepare, ok := c.PrepareData[stmtID]
+	if !ok {
+		return c.writeErrorAndLog(sqlerror.ERUnknownStmtHandler, sqlerror.SSUnknownSQLState, "Unknown prepared statement handler (%d) given to mysqld_stmt_fetch", stmtID)
+	}
+	cur := prepare.cursor
+	if cur == nil {
+		return c.writeErrorAndLog(sqlerror.ERStmtHasNoOpenCursor, sqlerror.SSUnknownSQLState, "The statement (%d) has no open cursor.", stmtID)
+	}
+
+	sent := uint32(0)
+	for sent < numRows {
+		if len(cur.rows) == 0 {
+			qr, ok := cur.next()
+			if !ok {
+				break
+			}
+			cur.rows = qr.Rows
+			continue
+		}
+		if err := c.writeBinaryRow(cur.fields, cur.rows[0]); err != nil {
+			log.Errorf("Error writing row to %s: %v", c, err)
+			return false
+		}
+		cur.rows = cur.rows[1:]
+		sent++
+	}
+
+	flags := ServerStatusCursorExists
+	if cur.done && len(cur.rows) == 0 {
+		prepare.cursor = nil
+		if cur.err != nil {
+			if sent == 0 {
+				return c.writeErrorPacketFromErrorAndLog(cur.err)
+			}
+			// We can't send an error in the middle of a stream.
+			// All we can do is abort the send, which will cause a 2013.
+			log.Errorf("Error in the middle of a stream to %s: %v", c, cur.err)
+			return false
+		}
+		flags |= ServerStatusLastRowSent
+	}
+	if err := c.writeCursorStatus(flags, handler.WarningCount(c)); err != nil {
+		log.Errorf("Error writing result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
 func (c *Conn) handleComPrepare(handler Handler, data []byte) (kontinue bool) {
 	c.startWriterBuffering()
 	defer func() {
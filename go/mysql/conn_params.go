@@ -64,6 +64,13 @@ type ConnParams struct {
 	// FlushDelay is the delay after which buffered response will be flushed to the client.
 	FlushDelay time.Duration
 
+	// Compression is the algorithm of the compressed protocol to use, if
+	// the server supports it. The protocol is not compressed if it is empty.
+	Compression CompressionAlgorithm
+	// CompressionLevel is the level of the compression, or 0 for the
+	// default level of the algorithm.
+	CompressionLevel int
+
 	TruncateErrLen int
 }
 
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/vt/log"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+// cursor is a read-only cursor, opened by a COM_STMT_EXECUTE, whose rows
+// the client reads with COM_STMT_FETCH.
+//
+// The ComStmtExecute of the handler runs in its own go routine, and its
+// callback blocks until the connection needs the next result. The connection
+// and the handler take turns: the handler only runs while the connection waits
+// for its next result, so they never use the connection at the same time, and
+// the rows are only read from the handler as fast as the client fetches them.
+type cursor struct {
+	fields []*querypb.Field
+	// rows are the rows received from the handler that were not fetched yet.
+	rows [][]sqltypes.Value
+
+	results chan *sqltypes.Result
+	resume  chan bool
+
+	// suspended is set while the handler waits in its callback to be resumed.
+	suspended bool
+	// done is set once the handler returned, err is what it returned.
+	done bool
+	err  error
+}
+
+// openCursor calls the ComStmtExecute of the handler in its own go routine.
+// The caller must then read the results of the cursor with next.
+func (c *Conn) openCursor(handler Handler, prepare *PrepareData) *cursor {
+	cur := &cursor{
+		results: make(chan *sqltypes.Result),
+		resume:  make(chan bool),
+	}
+	// the handler keeps running after this command, give it its own copy of the statement
+	stmt := *prepare
+	go func() {
+		defer func() {
+			if x := recover(); x != nil {
+				log.Errorf("mysql_server caught panic in cursor of %s:\n%v", c, x)
+				cur.err = fmt.Errorf("panic in cursor: %v", x)
+			}
+			close(cur.results)
+		}()
+		cur.err = handler.ComStmtExecute(c, &stmt, func(qr *sqltypes.Result) error {
+			cur.results <- qr
+			if !<-cur.resume {
+				return io.EOF
+			}
+			return nil
+		})
+	}()
+	return cur
+}
+
+// next resumes the handler and returns its next result. It returns
+// false once the handler returned.
+func (cur *cursor) next() (*sqltypes.Result, bool) {
+	if cur.done {
+		return nil, false
+	}
+	if cur.suspended {
+		cur.resume <- true
+	}
+	qr, ok := <-cur.results
+	cur.suspended = ok
+	cur.done = !ok
+	return qr, ok
+}
+
+// materialize reads all the remaining results of the handler, so that
+// the connection can run other commands while the cursor stays open.
+func (cur *cursor) materialize() {
+	for {
+		qr, ok := cur.next()
+		if !ok {
+			return
+		}
+		cur.rows = append(cur.rows, qr.Rows...)
+	}
+}
+
+// close stops the handler, and waits for it to return.
+func (cur *cursor) close() {
+	if cur.done {
+		return
+	}
+	if cur.suspended {
+		cur.resume <- false
+	}
+	for range cur.results {
+		cur.resume <- false
+	}
+	cur.suspended = false
+	cur.done = true
+	cur.rows = nil
+}
+
+// closeCursor closes the open cursor of a prepared statement.
+func (c *Conn) closeCursor(stmtID uint32) {
+	if prepare := c.PrepareData[stmtID]; prepare != nil && prepare.cursor != nil {
+		prepare.cursor.close()
+		prepare.cursor = nil
+	}
+}
+
+// closeCursors closes the open cursors of all the prepared statements.
+func (c *Conn) closeCursors() {
+	for stmtID := range c.PrepareData {
+		c.closeCursor(stmtID)
+	}
+}
+
+// materializeCursors reads all the results of the open cursors, so that
+// the handler can be called for another command.
+func (c *Conn) materializeCursors() {
+	for _, prepare := range c.PrepareData {
+		if prepare.cursor != nil {
+			prepare.cursor.materialize()
+		}
+	}
+}
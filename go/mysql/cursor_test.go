@@ -0,0 +1,296 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/sqltypes"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+// cursorHandler streams the fields of selectRowsResult, then its rows three times.
+type cursorHandler struct {
+	testRun
+	// sent is the number of results sent to the callback.
+	sent int
+	// err is the error returned by the last callback.
+	err error
+}
+
+func (h *cursorHandler) ComStmtExecute(c *Conn, prepare *PrepareData, callback func(*sqltypes.Result) error) error {
+	h.sent = 0
+	h.err = nil
+	send := func(qr *sqltypes.Result) error {
+		h.sent++
+		h.err = callback(qr)
+		return h.err
+	}
+
+	switch {
+	case strings.Contains(prepare.PrepareStmt, "update"):
+		return send(&sqltypes.Result{RowsAffected: 3})
+	case strings.HasPrefix(prepare.PrepareStmt, "error"):
+		return errors.New("execution failed")
+	}
+	if err := send(selectRowsResult.Metadata()); err != nil {
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		if err := send(&sqltypes.Result{Rows: selectRowsResult.Rows}); err != nil {
+			return err
+		}
+	}
+	if strings.Contains(prepare.PrepareStmt, "error") {
+		return errors.New("stream failed")
+	}
+	return nil
+}
+
+func createStmtExecutePacket(stmtID uint32, cursorType byte) []byte {
+	packet := []byte{0, 0, 0, 0, ComStmtExecute}
+	packet = binary.LittleEndian.AppendUint32(packet, stmtID)
+	packet = append(packet, cursorType)
+	return binary.LittleEndian.AppendUint32(packet, 1) // iteration count
+}
+
+func createStmtFetchPacket(stmtID uint32, numRows uint32) []byte {
+	packet := []byte{0, 0, 0, 0, ComStmtFetch}
+	packet = binary.LittleEndian.AppendUint32(packet, stmtID)
+	return binary.LittleEndian.AppendUint32(packet, numRows)
+}
+
+func TestCursor(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	handler := &cursorHandler{testRun: testRun{t: t}}
+	sConn.PrepareData = map[uint32]*PrepareData{
+		1: {StatementID: 1, PrepareStmt: "select id, name from t", BindVars: map[string]*querypb.BindVariable{}},
+		2: {StatementID: 2, PrepareStmt: "update t set name = 'x'", BindVars: map[string]*querypb.BindVariable{}},
+		3: {StatementID: 3, PrepareStmt: "error", BindVars: map[string]*querypb.BindVariable{}},
+		4: {StatementID: 4, PrepareStmt: "select id from error", BindVars: map[string]*querypb.BindVariable{}},
+	}
+
+	command := func(packet []byte) {
+		t.Helper()
+		cConn.sequence = 0
+		require.NoError(t, cConn.writePacket(packet))
+		require.True(t, sConn.handleNextCommand(handler))
+	}
+	readStatus := func() uint16 {
+		t.Helper()
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		require.True(t, cConn.isEOFPacket(data), "expected an EOF packet: %v", data)
+		_, flags, err := parseEOFPacket(data)
+		require.NoError(t, err)
+		return flags
+	}
+	readRows := func() (rows int, flags uint16) {
+		t.Helper()
+		for {
+			data, err := cConn.ReadPacket()
+			require.NoError(t, err)
+			if cConn.isEOFPacket(data) {
+				_, flags, err := parseEOFPacket(data)
+				require.NoError(t, err)
+				return rows, flags
+			}
+			require.EqualValues(t, OKPacket, data[0], "expected a binary row: %v", data)
+			rows++
+		}
+	}
+	readError := func() error {
+		t.Helper()
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		require.EqualValues(t, ErrPacket, data[0], "expected an error packet: %v", data)
+		return ParseErrorPacket(data)
+	}
+	executeWithCursor := func(stmtID uint32) {
+		t.Helper()
+		command(createStmtExecutePacket(stmtID, CursorTypeReadOnly))
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		require.EqualValues(t, 2, data[0], "column count")
+		for range selectRowsResult.Fields {
+			_, err := cConn.ReadPacket()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, ServerStatusCursorExists, readStatus())
+	}
+
+	t.Run("fetch", func(t *testing.T) {
+		executeWithCursor(1)
+		// only the fields were read from the handler
+		assert.Equal(t, 1, handler.sent)
+
+		command(createStmtFetchPacket(1, 3))
+		rows, flags := readRows()
+		assert.Equal(t, 3, rows)
+		assert.Equal(t, ServerStatusCursorExists, flags)
+		assert.Equal(t, 3, handler.sent)
+
+		command(createStmtFetchPacket(1, 2))
+		rows, flags = readRows()
+		assert.Equal(t, 2, rows)
+		assert.Equal(t, ServerStatusCursorExists, flags)
+
+		command(createStmtFetchPacket(1, 10))
+		rows, flags = readRows()
+		assert.Equal(t, 1, rows)
+		assert.Equal(t, ServerStatusCursorExists|ServerStatusLastRowSent, flags)
+		assert.NoError(t, handler.err)
+
+		command(createStmtFetchPacket(1, 10))
+		assert.EqualError(t, readError(), "The statement (1) has no open cursor. (errno 1421) (sqlstate HY000)")
+		command(createStmtFetchPacket(10, 10))
+		assert.EqualError(t, readError(), "Unknown prepared statement handler (10) given to mysqld_stmt_fetch (errno 1243) (sqlstate HY000)")
+	})
+
+	t.Run("other commands read the cursor", func(t *testing.T) {
+		executeWithCursor(1)
+		command(createStmtFetchPacket(1, 1))
+		rows, _ := readRows()
+		assert.Equal(t, 1, rows)
+
+		cConn.sequence = 0
+		require.NoError(t, cConn.WriteComQuery("select 1"))
+		require.True(t, sConn.handleNextCommand(handler))
+		assert.Equal(t, 4, handler.sent)
+		_, _, _, err := cConn.ReadQueryResult(100, true)
+		require.NoError(t, err)
+
+		command(createStmtFetchPacket(1, 10))
+		rows, flags := readRows()
+		assert.Equal(t, 5, rows)
+		assert.Equal(t, ServerStatusCursorExists|ServerStatusLastRowSent, flags)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		executeWithCursor(1)
+		command(createStmtFetchPacket(1, 1))
+		rows, _ := readRows()
+		assert.Equal(t, 1, rows)
+
+		cConn.sequence = 0
+		require.NoError(t, cConn.writePacket(binary.LittleEndian.AppendUint32([]byte{0, 0, 0, 0, ComStmtClose}, 1)))
+		require.True(t, sConn.handleNextCommand(handler))
+		// the handler was stopped
+		assert.Equal(t, 2, handler.sent)
+		assert.Equal(t, io.EOF, handler.err)
+		assert.NotContains(t, sConn.PrepareData, uint32(1))
+	})
+
+	t.Run("execute again", func(t *testing.T) {
+		sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select id, name from t", BindVars: map[string]*querypb.BindVariable{}}
+		executeWithCursor(1)
+		executeWithCursor(1)
+		command(createStmtFetchPacket(1, 10))
+		rows, flags := readRows()
+		assert.Equal(t, 6, rows)
+		assert.Equal(t, ServerStatusCursorExists|ServerStatusLastRowSent, flags)
+	})
+
+	t.Run("no result set", func(t *testing.T) {
+		command(createStmtExecutePacket(2, CursorTypeReadOnly))
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		packetOK := &PacketOK{}
+		require.NoError(t, cConn.parseOKPacket(packetOK, data))
+		assert.EqualValues(t, 3, packetOK.affectedRows)
+		assert.Nil(t, sConn.PrepareData[2].cursor)
+
+		command(createStmtFetchPacket(2, 10))
+		assert.ErrorContains(t, readError(), "errno 1421")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		command(createStmtExecutePacket(3, CursorTypeReadOnly))
+		assert.EqualError(t, readError(), "unknown error: execution failed (errno 1105) (sqlstate HY000)")
+
+		executeWithCursor(4)
+		command(createStmtFetchPacket(4, 6))
+		rows, flags := readRows()
+		assert.Equal(t, 6, rows)
+		assert.Equal(t, ServerStatusCursorExists, flags)
+		command(createStmtFetchPacket(4, 10))
+		assert.EqualError(t, readError(), "unknown error: stream failed (errno 1105) (sqlstate HY000)")
+
+		// an error after some rows were sent can only abort the connection
+		executeWithCursor(4)
+		cConn.sequence = 0
+		require.NoError(t, cConn.writePacket(createStmtFetchPacket(4, 10)))
+		require.False(t, sConn.handleNextCommand(handler))
+	})
+}
+
+func TestCursorDeprecateEOF(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	sConn.Capabilities |= CapabilityClientDeprecateEOF
+	cConn.Capabilities |= CapabilityClientDeprecateEOF
+	handler := &cursorHandler{testRun: testRun{t: t}}
+	sConn.PrepareData = map[uint32]*PrepareData{
+		1: {StatementID: 1, PrepareStmt: "select id, name from t", BindVars: map[string]*querypb.BindVariable{}},
+	}
+
+	cConn.sequence = 0
+	require.NoError(t, cConn.writePacket(createStmtExecutePacket(1, CursorTypeReadOnly)))
+	require.True(t, sConn.handleNextCommand(handler))
+	for i := 0; i < 1+len(selectRowsResult.Fields); i++ {
+		_, err := cConn.ReadPacket()
+		require.NoError(t, err)
+	}
+	// the column definitions are followed by an OK packet with the EOF header
+	data, err := cConn.ReadPacket()
+	require.NoError(t, err)
+	require.EqualValues(t, EOFPacket, data[0])
+	packetOK := &PacketOK{}
+	require.NoError(t, cConn.parseOKPacket(packetOK, data))
+	assert.Equal(t, ServerStatusCursorExists, packetOK.statusFlags)
+
+	cConn.sequence = 0
+	require.NoError(t, cConn.writePacket(createStmtFetchPacket(1, 10)))
+	require.True(t, sConn.handleNextCommand(handler))
+	for i := 0; i < 6; i++ {
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		require.EqualValues(t, OKPacket, data[0])
+	}
+	data, err = cConn.ReadPacket()
+	require.NoError(t, err)
+	require.EqualValues(t, EOFPacket, data[0])
+	require.NoError(t, cConn.parseOKPacket(packetOK, data))
+	assert.Equal(t, ServerStatusCursorExists|ServerStatusLastRowSent, packetOK.statusFlags)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package geometry implements the spatial data types of MySQL: it parses and
+// formats geometries in their well-known binary and text representations,
+// and computes spatial relations between them in a Cartesian plane.
+package geometry
+
+import (
+	"errors"
+)
+
+// Type is the type of a geometry, as encoded in its well-known binary representation
+type Type uint32
+
+const (
+	Point              Type = 1
+	LineString         Type = 2
+	Polygon            Type = 3
+	MultiPoint         Type = 4
+	MultiLineString    Type = 5
+	MultiPolygon       Type = 6
+	GeometryCollection Type = 7
+)
+
+// String returns the name of the type, the way MySQL prints it
+func (t Type) String() string {
+	switch t {
+	case Point:
+		return "POINT"
+	case LineString:
+		return "LINESTRING"
+	case Polygon:
+		return "POLYGON"
+	case MultiPoint:
+		return "MULTIPOINT"
+	case MultiLineString:
+		return "MULTILINESTRING"
+	case MultiPolygon:
+		return "MULTIPOLYGON"
+	case GeometryCollection:
+		return "GEOMETRYCOLLECTION"
+	default:
+		return "GEOMETRY"
+	}
+}
+
+// ErrInvalid is returned when a geometry cannot be parsed, or when it is not a valid MySQL geometry
+var ErrInvalid = errors.New("invalid GIS data")
+
+// maxDepth is the maximum nesting of geometry collections that we'll parse
+const maxDepth = 64
+
+// Coord is a position in a two-dimensional plane
+type Coord struct {
+	X, Y float64
+}
+
+// Geometry is a spatial value. Which of its fields are set depends on its Type.
+type Geometry struct {
+	// SRID is the spatial reference system of the geometry; it's only set on
+	// the top-level geometry, not on the members of collections
+	SRID uint32
+	Type Type
+	// Coords holds the single coordinate of a Point or the coordinates of a LineString
+	Coords []Coord
+	// Rings holds the exterior ring of a Polygon followed by its interior rings
+	Rings [][]Coord
+	// Geoms holds the members of a MultiPoint, MultiLineString, MultiPolygon or GeometryCollection
+	Geoms []*Geometry
+}
+
+// NewPoint returns a point with the given coordinates in the spatial reference system 0
+func NewPoint(x, y float64) *Geometry {
+	return &Geometry{Type: Point, Coords: []Coord{{X: x, Y: y}}}
+}
+
+// IsEmpty returns whether the geometry has no points, which can only happen
+// for geometry collections with no members other than empty collections
+func (g *Geometry) IsEmpty() bool {
+	if g.Type != GeometryCollection {
+		return false
+	}
+	for _, m := range g.Geoms {
+		if !m.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Dimension returns the inherent dimension of the geometry: 0 for points, 1
+// for lines and 2 for polygons; a collection has the largest dimension of its members
+func (g *Geometry) Dimension() int {
+	switch g.Type {
+	case Point, MultiPoint:
+		return 0
+	case LineString, MultiLineString:
+		return 1
+	case Polygon, MultiPolygon:
+		return 2
+	}
+	dim := 0
+	for _, m := range g.Geoms {
+		dim = max(dim, m.Dimension())
+	}
+	return dim
+}
+
+// memberType returns the type that all the members of the geometry must have, or zero if
+// members can have any type
+func (t Type) memberType() Type {
+	switch t {
+	case MultiPoint:
+		return Point
+	case MultiLineString:
+		return LineString
+	case MultiPolygon:
+		return Polygon
+	}
+	return 0
+}
+
+// validLineString returns whether the coordinates form a MySQL LineString, which requires at least two points
+func validLineString(coords []Coord) bool {
+	return len(coords) >= 2
+}
+
+// validRing returns whether the coordinates form a closed ring of a Polygon
+func validRing(coords []Coord) bool {
+	return len(coords) >= 4 && coords[0] == coords[len(coords)-1]
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geometry
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWKT(t *testing.T) {
+	cases := []struct {
+		in, out string
+		typ     Type
+		dim     int
+		empty   bool
+	}{
+		{in: "POINT(1 2)", out: "POINT(1 2)", typ: Point},
+		{in: " point ( -1.5   2e3 ) ", out: "POINT(-1.5 2000)", typ: Point},
+		{in: "LINESTRING(0 0, 1 1,2 2)", out: "LINESTRING(0 0,1 1,2 2)", typ: LineString, dim: 1},
+		{in: "POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,2 4,4 4,2 2))", out: "POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,2 4,4 4,2 2))", typ: Polygon, dim: 2},
+		{in: "MULTIPOINT(0 0, 1 1)", out: "MULTIPOINT((0 0),(1 1))", typ: MultiPoint},
+		{in: "MULTIPOINT((0 0), (1 1))", out: "MULTIPOINT((0 0),(1 1))", typ: MultiPoint},
+		{in: "MULTILINESTRING((0 0,1 1),(2 2,3 3))", out: "MULTILINESTRING((0 0,1 1),(2 2,3 3))", typ: MultiLineString, dim: 1},
+		{in: "MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))", out: "MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))", typ: MultiPolygon, dim: 2},
+		{in: "GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))", out: "GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))", typ: GeometryCollection, dim: 1},
+		{in: "GEOMCOLLECTION(GEOMETRYCOLLECTION EMPTY)", out: "GEOMETRYCOLLECTION(GEOMETRYCOLLECTION EMPTY)", typ: GeometryCollection, empty: true},
+		{in: "GEOMETRYCOLLECTION EMPTY", out: "GEOMETRYCOLLECTION EMPTY", typ: GeometryCollection, empty: true},
+		{in: "GEOMETRYCOLLECTION()", out: "GEOMETRYCOLLECTION EMPTY", typ: GeometryCollection, empty: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			g, err := ParseWKT(tc.in)
+			require.NoError(t, err)
+			assert.Equal(t, tc.out, string(g.AppendWKT(nil)))
+			assert.Equal(t, tc.typ, g.Type)
+			assert.Equal(t, tc.dim, g.Dimension())
+			assert.Equal(t, tc.empty, g.IsEmpty())
+
+			g.SRID = 4326
+			parsed, err := ParseMySQL(g.AppendMySQL(nil))
+			require.NoError(t, err)
+			assert.Equal(t, g, parsed)
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	wkt := []string{
+		"",
+		"POINT",
+		"POINT()",
+		"POINT(1)",
+		"POINT(1 2 3)",
+		"POINT(1 2",
+		"POINT(1 2) x",
+		"LINESTRING(0 0)",
+		"POLYGON((0 0,1 0,1 1))",
+		"POLYGON((0 0,1 0,1 1,0 1))",
+		"MULTIPOINT()",
+		"CIRCLE(0 0)",
+	}
+	for _, text := range wkt {
+		_, err := ParseWKT(text)
+		assert.ErrorIs(t, err, ErrInvalid, text)
+	}
+
+	wkb := []string{
+		"",
+		"00000000",
+		"0000000001",
+		"000000000201000000",
+		// a point with a NaN coordinate
+		"000000000101000000000000000000f87f0000000000000000",
+		// a multipoint whose member is a linestring
+		"0000000001040000000100000001020000000200000000000000000000000000000000000000000000000000f03f000000000000f03f",
+		// a linestring with an extra byte
+		"0000000001020000000200000000000000000000000000000000000000000000000000f03f000000000000f03f00",
+	}
+	for _, h := range wkb {
+		b, err := hex.DecodeString(h)
+		require.NoError(t, err)
+		_, err = ParseMySQL(b)
+		assert.ErrorIs(t, err, ErrInvalid, h)
+	}
+}
+
+func TestBigEndianWKB(t *testing.T) {
+	b, err := hex.DecodeString("00000000013ff00000000000004000000000000000")
+	require.NoError(t, err)
+	g, err := ParseWKB(b)
+	require.NoError(t, err)
+	assert.Equal(t, NewPoint(1, 2), g)
+}
+
+func mustParse(t *testing.T, text string) *Geometry {
+	g, err := ParseWKT(text)
+	require.NoError(t, err, text)
+	return g
+}
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		dist float64
+	}{
+		{a: "POINT(0 0)", b: "POINT(3 4)", dist: 5},
+		{a: "POINT(0 5)", b: "LINESTRING(-10 0,10 0)", dist: 5},
+		{a: "POINT(20 0)", b: "LINESTRING(-10 0,10 0)", dist: 10},
+		{a: "LINESTRING(0 -1,0 1)", b: "LINESTRING(-1 0,1 0)", dist: 0},
+		{a: "LINESTRING(0 2,0 3)", b: "LINESTRING(-1 0,1 0)", dist: 2},
+		{a: "POINT(5 5)", b: "POLYGON((0 0,10 0,10 10,0 10,0 0))", dist: 0},
+		{a: "POINT(13 14)", b: "POLYGON((0 0,10 0,10 10,0 10,0 0))", dist: 5},
+		{a: "POINT(5 5)", b: "POLYGON((0 0,10 0,10 10,0 10,0 0),(4 4,6 4,6 6,4 6,4 4))", dist: 1},
+		{a: "POLYGON((2 2,3 2,3 3,2 2))", b: "POLYGON((0 0,10 0,10 10,0 10,0 0))", dist: 0},
+		{a: "MULTIPOINT((0 0),(10 10))", b: "GEOMETRYCOLLECTION(POINT(9 10),POINT(100 100))", dist: 1},
+	}
+	for _, tc := range cases {
+		d, ok := Distance(mustParse(t, tc.a), mustParse(t, tc.b))
+		assert.True(t, ok)
+		assert.InDelta(t, tc.dist, d, 1e-12, "%s, %s", tc.a, tc.b)
+
+		d, ok = Distance(mustParse(t, tc.b), mustParse(t, tc.a))
+		assert.True(t, ok)
+		assert.InDelta(t, tc.dist, d, 1e-12, "%s, %s", tc.b, tc.a)
+	}
+
+	_, ok := Distance(NewPoint(0, 0), mustParse(t, "GEOMETRYCOLLECTION EMPTY"))
+	assert.False(t, ok)
+}
+
+func TestContains(t *testing.T) {
+	const square = "POLYGON((0 0,10 0,10 10,0 10,0 0))"
+	const holed = "POLYGON((0 0,10 0,10 10,0 10,0 0),(4 4,6 4,6 6,4 6,4 4))"
+	const concave = "POLYGON((0 0,10 0,10 10,5 2,0 10,0 0))"
+
+	cases := []struct {
+		a, b     string
+		contains bool
+	}{
+		{a: square, b: "POINT(5 5)", contains: true},
+		{a: square, b: "POINT(10 5)", contains: false},
+		{a: square, b: "POINT(11 5)", contains: false},
+		{a: holed, b: "POINT(5 5)", contains: false},
+		{a: holed, b: "POINT(3 3)", contains: true},
+		{a: square, b: "LINESTRING(1 1,9 9)", contains: true},
+		{a: square, b: "LINESTRING(0 0,10 0)", contains: false},
+		{a: square, b: "LINESTRING(0 0,10 10)", contains: true},
+		{a: square, b: "LINESTRING(1 1,11 11)", contains: false},
+		{a: concave, b: "LINESTRING(1 5,9 5)", contains: false},
+		{a: concave, b: "LINESTRING(1 1,9 1)", contains: true},
+		{a: holed, b: "LINESTRING(1 1,9 9)", contains: false},
+		{a: square, b: square, contains: true},
+		{a: square, b: "POLYGON((1 1,2 1,2 2,1 1))", contains: true},
+		{a: holed, b: "POLYGON((3 3,7 3,7 7,3 7,3 3))", contains: false},
+		{a: holed, b: "POLYGON((1 1,3 1,3 3,1 1))", contains: true},
+		{a: "POLYGON((1 1,2 1,2 2,1 1))", b: square, contains: false},
+		{a: "LINESTRING(0 0,10 0)", b: "LINESTRING(2 0,5 0)", contains: true},
+		{a: "LINESTRING(0 0,10 0)", b: "POINT(5 0)", contains: true},
+		{a: "LINESTRING(0 0,10 0)", b: "POINT(0 0)", contains: false},
+		{a: "LINESTRING(0 0,10 0,10 10,0 0)", b: "POINT(0 0)", contains: true},
+		{a: "LINESTRING(0 0,10 0)", b: "LINESTRING(5 0,15 0)", contains: false},
+		{a: "POINT(1 1)", b: "POINT(1 1)", contains: true},
+		{a: "POINT(1 1)", b: "POINT(1 2)", contains: false},
+		{a: "MULTIPOINT((1 1),(2 2))", b: "MULTIPOINT((2 2),(1 1))", contains: true},
+		{a: "LINESTRING(0 0,10 0,10 10,0 10,0 0)", b: square, contains: false},
+		{a: "MULTIPOLYGON(((0 0,5 0,5 5,0 0)),((6 6,7 6,7 7,6 6)))", b: "MULTIPOINT((4 1),(6.5 6.2))", contains: true},
+		{a: square, b: "GEOMETRYCOLLECTION EMPTY", contains: false},
+	}
+	for _, tc := range cases {
+		a, b := mustParse(t, tc.a), mustParse(t, tc.b)
+		assert.Equal(t, tc.contains, Contains(a, b), "ST_Contains(%s, %s)", tc.a, tc.b)
+		assert.Equal(t, tc.contains, Within(b, a), "ST_Within(%s, %s)", tc.b, tc.a)
+	}
+}
@@ -0,0 +1,426 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geometry
+
+import (
+	"math"
+	"slices"
+)
+
+// Distance returns the minimum Cartesian distance between two geometries.
+// It returns false if either geometry is empty, in which case MySQL returns NULL.
+func Distance(a, b *Geometry) (float64, bool) {
+	if a.IsEmpty() || b.IsEmpty() {
+		return 0, false
+	}
+	pa, pb := decompose(a), decompose(b)
+
+	// if any vertex of a geometry lies inside a polygon of the other one, they intersect;
+	// otherwise the closest points of both geometries are on their points and segments
+	if pa.touchesPolygons(pb) || pb.touchesPolygons(pa) {
+		return 0, true
+	}
+
+	dist := math.Inf(1)
+	for _, p := range pa.points {
+		for _, q := range pb.points {
+			dist = min(dist, math.Hypot(p.X-q.X, p.Y-q.Y))
+		}
+		for _, s := range pb.segments {
+			dist = min(dist, pointSegmentDistance(p, s))
+		}
+	}
+	for _, s := range pa.segments {
+		for _, q := range pb.points {
+			dist = min(dist, pointSegmentDistance(q, s))
+		}
+		for _, t := range pb.segments {
+			dist = min(dist, segmentDistance(s, t))
+		}
+	}
+	return dist, true
+}
+
+// Contains returns whether b lies completely inside a: no point of b is in the exterior of a,
+// and at least one point of the interior of b is in the interior of a.
+func Contains(a, b *Geometry) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return false
+	}
+	pa, pb := decompose(a), decompose(b)
+
+	var inner bool
+	check := func(c Coord) bool {
+		switch pa.locate(c) {
+		case exterior:
+			return false
+		case interior:
+			inner = true
+		}
+		return true
+	}
+
+	for _, p := range pb.points {
+		if !check(p) {
+			return false
+		}
+	}
+	for _, s := range pb.segments {
+		for _, m := range pa.split(s) {
+			if !check(m) {
+				return false
+			}
+		}
+	}
+
+	for _, poly := range pb.polygons {
+		// the boundary of the polygon is covered by a; it lies inside a if a point of its
+		// interior does, and if there are no holes or gaps of a inside of it
+		p, ok := interiorPoint(poly)
+		if !ok {
+			continue
+		}
+		if pa.locate(p) != interior {
+			return false
+		}
+		inner = true
+	}
+	if len(pb.polygons) > 0 {
+		for _, s := range pa.ringSegments() {
+			for _, m := range pb.split(s) {
+				if pb.locatePolygons(m) == interior && pa.locate(m) == boundary {
+					return false
+				}
+			}
+		}
+	}
+	return inner
+}
+
+// Within returns whether a lies completely inside b
+func Within(a, b *Geometry) bool {
+	return Contains(b, a)
+}
+
+type segment struct {
+	a, b Coord
+}
+
+type location int
+
+const (
+	exterior location = iota
+	boundary
+	interior
+)
+
+// parts holds the simple components of a geometry
+type parts struct {
+	// points holds all the points of the geometry, including the vertices of its lines and polygons
+	points []Coord
+	// isolated holds the points that are not part of a line or polygon
+	isolated []Coord
+	// segments holds the segments of lines and polygon rings
+	segments []segment
+	lines    [][]Coord
+	polygons [][][]Coord
+}
+
+func decompose(g *Geometry) *parts {
+	var p parts
+	p.add(g)
+	return &p
+}
+
+func (p *parts) add(g *Geometry) {
+	switch g.Type {
+	case Point:
+		p.points = append(p.points, g.Coords...)
+		p.isolated = append(p.isolated, g.Coords...)
+	case LineString:
+		p.points = append(p.points, g.Coords...)
+		p.segments = appendSegments(p.segments, g.Coords)
+		p.lines = append(p.lines, g.Coords)
+	case Polygon:
+		for _, ring := range g.Rings {
+			p.points = append(p.points, ring...)
+			p.segments = appendSegments(p.segments, ring)
+		}
+		p.polygons = append(p.polygons, g.Rings)
+	default:
+		for _, m := range g.Geoms {
+			p.add(m)
+		}
+	}
+}
+
+func appendSegments(segments []segment, coords []Coord) []segment {
+	for i := 1; i < len(coords); i++ {
+		segments = append(segments, segment{coords[i-1], coords[i]})
+	}
+	return segments
+}
+
+func (p *parts) ringSegments() []segment {
+	var segments []segment
+	for _, poly := range p.polygons {
+		for _, ring := range poly {
+			segments = appendSegments(segments, ring)
+		}
+	}
+	return segments
+}
+
+// touchesPolygons returns whether any of the points of p lies inside or on the boundary of a polygon of other
+func (p *parts) touchesPolygons(other *parts) bool {
+	for _, c := range p.points {
+		if other.locatePolygons(c) != exterior {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parts) locatePolygons(c Coord) location {
+	loc := exterior
+	for _, poly := range p.polygons {
+		switch locateInPolygon(c, poly) {
+		case interior:
+			return interior
+		case boundary:
+			loc = boundary
+		}
+	}
+	return loc
+}
+
+// locate returns whether the coordinate is in the interior, on the boundary or in the exterior of
+// the geometry. The boundary of lines follows the mod-2 rule: an endpoint is on the boundary if
+// an odd number of lines end there.
+func (p *parts) locate(c Coord) location {
+	loc := p.locatePolygons(c)
+	if loc == interior {
+		return interior
+	}
+
+	var endpoints int
+	for _, line := range p.lines {
+		first, last := line[0], line[len(line)-1]
+		if c == first || c == last {
+			if c == first {
+				endpoints++
+			}
+			if c == last {
+				endpoints++
+			}
+			continue
+		}
+		for i := 1; i < len(line); i++ {
+			if onSegment(c, segment{line[i-1], line[i]}) {
+				return interior
+			}
+		}
+	}
+	if endpoints > 0 {
+		if endpoints%2 == 0 {
+			return interior
+		}
+		loc = boundary
+	}
+
+	if slices.Contains(p.isolated, c) {
+		return interior
+	}
+	return loc
+}
+
+// split cuts the segment at every point where it meets the geometry, and returns the
+// middle point of each piece. Every piece is either completely inside or completely
+// outside of each of the components of the geometry.
+func (p *parts) split(s segment) []Coord {
+	if s.a == s.b {
+		return []Coord{s.a}
+	}
+	cuts := []float64{0, 1}
+	for _, c := range p.points {
+		if onSegment(c, s) {
+			cuts = append(cuts, s.param(c))
+		}
+	}
+	for _, t := range p.segments {
+		if c, ok := intersection(s, t); ok {
+			cuts = append(cuts, s.param(c))
+		}
+	}
+	slices.Sort(cuts)
+	cuts = slices.Compact(cuts)
+
+	mids := make([]Coord, 0, len(cuts)-1)
+	for i := 1; i < len(cuts); i++ {
+		mids = append(mids, s.at((cuts[i-1]+cuts[i])/2))
+	}
+	return mids
+}
+
+// param returns the position of a point of the segment, from 0 at its start to 1 at its end
+func (s segment) param(c Coord) float64 {
+	dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+	t := ((c.X-s.a.X)*dx + (c.Y-s.a.Y)*dy) / (dx*dx + dy*dy)
+	return min(max(t, 0), 1)
+}
+
+func (s segment) at(t float64) Coord {
+	return Coord{X: s.a.X + t*(s.b.X-s.a.X), Y: s.a.Y + t*(s.b.Y-s.a.Y)}
+}
+
+// cross returns the cross product of the vectors a->b and a->c, which is positive if
+// c is to the left of the line through a and b, negative if it's to its right and 0 if
+// the three points are collinear
+func cross(a, b, c Coord) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func onSegment(c Coord, s segment) bool {
+	return cross(s.a, s.b, c) == 0 &&
+		c.X >= min(s.a.X, s.b.X) && c.X <= max(s.a.X, s.b.X) &&
+		c.Y >= min(s.a.Y, s.b.Y) && c.Y <= max(s.a.Y, s.b.Y)
+}
+
+func sign(f float64) int {
+	switch {
+	case f > 0:
+		return 1
+	case f < 0:
+		return -1
+	}
+	return 0
+}
+
+func segmentsIntersect(s, t segment) bool {
+	d1 := sign(cross(t.a, t.b, s.a))
+	d2 := sign(cross(t.a, t.b, s.b))
+	d3 := sign(cross(s.a, s.b, t.a))
+	d4 := sign(cross(s.a, s.b, t.b))
+	if d1*d2 < 0 && d3*d4 < 0 {
+		return true
+	}
+	return onSegment(s.a, t) || onSegment(s.b, t) || onSegment(t.a, s) || onSegment(t.b, s)
+}
+
+// intersection returns the point where two segments cross each other. Segments that
+// only touch or overlap are already handled by their endpoints, so they are not reported.
+func intersection(s, t segment) (Coord, bool) {
+	d1 := cross(t.a, t.b, s.a)
+	d2 := cross(t.a, t.b, s.b)
+	if sign(d1)*sign(d2) >= 0 || sign(cross(s.a, s.b, t.a))*sign(cross(s.a, s.b, t.b)) >= 0 {
+		return Coord{}, false
+	}
+	return s.at(d1 / (d1 - d2)), true
+}
+
+func pointSegmentDistance(c Coord, s segment) float64 {
+	p := s.a
+	if s.a != s.b {
+		p = s.at(s.param(c))
+	}
+	return math.Hypot(c.X-p.X, c.Y-p.Y)
+}
+
+func segmentDistance(s, t segment) float64 {
+	if segmentsIntersect(s, t) {
+		return 0
+	}
+	return min(
+		pointSegmentDistance(s.a, t),
+		pointSegmentDistance(s.b, t),
+		pointSegmentDistance(t.a, s),
+		pointSegmentDistance(t.b, s),
+	)
+}
+
+func locateInRing(c Coord, ring []Coord) location {
+	inside := false
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		if onSegment(c, segment{a, b}) {
+			return boundary
+		}
+		if (a.Y > c.Y) != (b.Y > c.Y) {
+			x := a.X + (c.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			if c.X < x {
+				inside = !inside
+			}
+		}
+	}
+	if inside {
+		return interior
+	}
+	return exterior
+}
+
+func locateInPolygon(c Coord, rings [][]Coord) location {
+	loc := locateInRing(c, rings[0])
+	if loc != interior {
+		return loc
+	}
+	for _, hole := range rings[1:] {
+		switch locateInRing(c, hole) {
+		case boundary:
+			return boundary
+		case interior:
+			return exterior
+		}
+	}
+	return interior
+}
+
+// interiorPoint returns a point in the interior of a polygon. It cuts the polygon with a
+// horizontal line that doesn't go through any of its vertices, and takes the middle of the
+// first stretch of that line inside the polygon. It returns false if the polygon has no area.
+func interiorPoint(rings [][]Coord) (Coord, bool) {
+	var ys []float64
+	for _, ring := range rings {
+		for _, c := range ring {
+			ys = append(ys, c.Y)
+		}
+	}
+	slices.Sort(ys)
+	ys = slices.Compact(ys)
+
+	for i := 1; i < len(ys); i++ {
+		y := (ys[i-1] + ys[i]) / 2
+		var xs []float64
+		for _, ring := range rings {
+			for j := 1; j < len(ring); j++ {
+				a, b := ring[j-1], ring[j]
+				if (a.Y > y) != (b.Y > y) {
+					xs = append(xs, a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+				}
+			}
+		}
+		slices.Sort(xs)
+		for j := 1; j < len(xs); j += 2 {
+			if xs[j-1] < xs[j] {
+				c := Coord{X: (xs[j-1] + xs[j]) / 2, Y: y}
+				if locateInPolygon(c, rings) == interior {
+					return c, true
+				}
+			}
+		}
+	}
+	return Coord{}, false
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geometry
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+const (
+	wkbBigEndian    = 0
+	wkbLittleEndian = 1
+)
+
+// ParseMySQL parses a geometry in the format MySQL uses to store and send spatial
+// values: a 4-byte little-endian SRID followed by the geometry in well-known binary.
+func ParseMySQL(b []byte) (*Geometry, error) {
+	if len(b) < 4 {
+		return nil, ErrInvalid
+	}
+	g, err := ParseWKB(b[4:])
+	if err != nil {
+		return nil, err
+	}
+	g.SRID = binary.LittleEndian.Uint32(b)
+	return g, nil
+}
+
+// ParseWKB parses a geometry from its well-known binary representation
+func ParseWKB(b []byte) (*Geometry, error) {
+	p := wkbParser{buf: b}
+	g, ok := p.geometry(0, 0)
+	if !ok || len(p.buf) != 0 {
+		return nil, ErrInvalid
+	}
+	return g, nil
+}
+
+type wkbParser struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+func (p *wkbParser) uint32() (uint32, bool) {
+	if len(p.buf) < 4 {
+		return 0, false
+	}
+	v := p.order.Uint32(p.buf)
+	p.buf = p.buf[4:]
+	return v, true
+}
+
+// count reads the number of elements that follow, each of them taking at least size bytes,
+// and makes sure that the buffer is large enough to hold them
+func (p *wkbParser) count(size int) (int, bool) {
+	n, ok := p.uint32()
+	if !ok || uint64(n)*uint64(size) > uint64(len(p.buf)) {
+		return 0, false
+	}
+	return int(n), true
+}
+
+func (p *wkbParser) coords(n int) ([]Coord, bool) {
+	coords := make([]Coord, n)
+	for i := range coords {
+		x := math.Float64frombits(p.order.Uint64(p.buf))
+		y := math.Float64frombits(p.order.Uint64(p.buf[8:]))
+		if !finite(x) || !finite(y) {
+			return nil, false
+		}
+		coords[i] = Coord{X: x, Y: y}
+		p.buf = p.buf[16:]
+	}
+	return coords, true
+}
+
+func (p *wkbParser) lineString() ([]Coord, bool) {
+	n, ok := p.count(16)
+	if !ok {
+		return nil, false
+	}
+	return p.coords(n)
+}
+
+func (p *wkbParser) geometry(depth int, want Type) (*Geometry, bool) {
+	if len(p.buf) < 1 || depth > maxDepth {
+		return nil, false
+	}
+	switch p.buf[0] {
+	case wkbBigEndian:
+		p.order = binary.BigEndian
+	case wkbLittleEndian:
+		p.order = binary.LittleEndian
+	default:
+		return nil, false
+	}
+	p.buf = p.buf[1:]
+
+	typ, ok := p.uint32()
+	if !ok || (want != 0 && Type(typ) != want) {
+		return nil, false
+	}
+
+	g := &Geometry{Type: Type(typ)}
+	switch g.Type {
+	case Point:
+		if len(p.buf) < 16 {
+			return nil, false
+		}
+		g.Coords, ok = p.coords(1)
+	case LineString:
+		g.Coords, ok = p.lineString()
+		ok = ok && validLineString(g.Coords)
+	case Polygon:
+		var n int
+		n, ok = p.count(4)
+		ok = ok && n > 0
+		for i := 0; ok && i < n; i++ {
+			var ring []Coord
+			ring, ok = p.lineString()
+			ok = ok && validRing(ring)
+			g.Rings = append(g.Rings, ring)
+		}
+	case MultiPoint, MultiLineString, MultiPolygon, GeometryCollection:
+		var n int
+		n, ok = p.count(5)
+		ok = ok && (n > 0 || g.Type == GeometryCollection)
+		for i := 0; ok && i < n; i++ {
+			var m *Geometry
+			m, ok = p.geometry(depth+1, g.Type.memberType())
+			g.Geoms = append(g.Geoms, m)
+		}
+	default:
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	return g, true
+}
+
+func finite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// AppendMySQL appends the geometry to dst in the format MySQL uses to store spatial values
+func (g *Geometry) AppendMySQL(dst []byte) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, g.SRID)
+	return g.AppendWKB(dst)
+}
+
+// AppendWKB appends the well-known binary representation of the geometry to dst, in little-endian byte order
+func (g *Geometry) AppendWKB(dst []byte) []byte {
+	dst = append(dst, wkbLittleEndian)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(g.Type))
+	switch g.Type {
+	case Point:
+		dst = appendCoordsWKB(dst, g.Coords)
+	case LineString:
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(g.Coords)))
+		dst = appendCoordsWKB(dst, g.Coords)
+	case Polygon:
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(g.Rings)))
+		for _, ring := range g.Rings {
+			dst = binary.LittleEndian.AppendUint32(dst, uint32(len(ring)))
+			dst = appendCoordsWKB(dst, ring)
+		}
+	default:
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(g.Geoms)))
+		for _, m := range g.Geoms {
+			dst = m.AppendWKB(dst)
+		}
+	}
+	return dst
+}
+
+func appendCoordsWKB(dst []byte, coords []Coord) []byte {
+	for _, c := range coords {
+		dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(c.X))
+		dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(c.Y))
+	}
+	return dst
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geometry
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mdibaiee/vitess/go/mysql/format"
+)
+
+// ParseWKT parses a geometry from its well-known text representation
+func ParseWKT(text string) (*Geometry, error) {
+	p := wktParser{text: text}
+	g, ok := p.geometry(0, 0)
+	p.skipSpace()
+	if !ok || p.pos != len(p.text) {
+		return nil, ErrInvalid
+	}
+	return g, nil
+}
+
+type wktParser struct {
+	text string
+	pos  int
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.text) && isSpace(p.text[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *wktParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.text) && p.text[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) peek(c byte) bool {
+	p.skipSpace()
+	return p.pos < len(p.text) && p.text[p.pos] == c
+}
+
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.text[start:p.pos])
+}
+
+func (p *wktParser) number() (float64, bool) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if (c < '0' || c > '9') && c != '.' && c != '-' && c != '+' && c != 'e' && c != 'E' {
+			break
+		}
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.text[start:p.pos], 64)
+	return f, err == nil && finite(f)
+}
+
+// coord parses the two numbers of a position, which must be separated by whitespace
+func (p *wktParser) coord() (Coord, bool) {
+	x, ok := p.number()
+	if !ok || p.pos == len(p.text) || !isSpace(p.text[p.pos]) {
+		return Coord{}, false
+	}
+	y, ok := p.number()
+	return Coord{X: x, Y: y}, ok
+}
+
+// list parses a parenthesized list of elements separated by commas
+func (p *wktParser) list(elem func() bool) bool {
+	if !p.consume('(') {
+		return false
+	}
+	for {
+		if !elem() {
+			return false
+		}
+		if !p.consume(',') {
+			return p.consume(')')
+		}
+	}
+}
+
+func (p *wktParser) coords() ([]Coord, bool) {
+	var coords []Coord
+	ok := p.list(func() bool {
+		c, ok := p.coord()
+		coords = append(coords, c)
+		return ok
+	})
+	return coords, ok
+}
+
+func (p *wktParser) rings() ([][]Coord, bool) {
+	var rings [][]Coord
+	ok := p.list(func() bool {
+		ring, ok := p.coords()
+		rings = append(rings, ring)
+		return ok && validRing(ring)
+	})
+	return rings, ok
+}
+
+// multiPoint parses the members of a MultiPoint, which can be written either
+// as MULTIPOINT(0 0, 1 1) or as MULTIPOINT((0 0), (1 1))
+func (p *wktParser) multiPoint() ([]*Geometry, bool) {
+	var points []*Geometry
+	ok := p.list(func() bool {
+		var c Coord
+		var ok bool
+		if p.consume('(') {
+			c, ok = p.coord()
+			ok = ok && p.consume(')')
+		} else {
+			c, ok = p.coord()
+		}
+		points = append(points, &Geometry{Type: Point, Coords: []Coord{c}})
+		return ok
+	})
+	return points, ok
+}
+
+func (p *wktParser) geometry(depth int, want Type) (*Geometry, bool) {
+	if depth > maxDepth {
+		return nil, false
+	}
+
+	var g Geometry
+	switch p.word() {
+	case "POINT":
+		g.Type = Point
+	case "LINESTRING":
+		g.Type = LineString
+	case "POLYGON":
+		g.Type = Polygon
+	case "MULTIPOINT":
+		g.Type = MultiPoint
+	case "MULTILINESTRING":
+		g.Type = MultiLineString
+	case "MULTIPOLYGON":
+		g.Type = MultiPolygon
+	case "GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		g.Type = GeometryCollection
+	default:
+		return nil, false
+	}
+	if want != 0 && g.Type != want {
+		return nil, false
+	}
+
+	var ok bool
+	switch g.Type {
+	case Point:
+		var c Coord
+		ok = p.consume('(')
+		if ok {
+			c, ok = p.coord()
+		}
+		ok = ok && p.consume(')')
+		g.Coords = []Coord{c}
+	case LineString:
+		g.Coords, ok = p.coords()
+		ok = ok && validLineString(g.Coords)
+	case Polygon:
+		g.Rings, ok = p.rings()
+	case MultiPoint:
+		g.Geoms, ok = p.multiPoint()
+	case MultiLineString:
+		ok = p.list(func() bool {
+			coords, ok := p.coords()
+			g.Geoms = append(g.Geoms, &Geometry{Type: LineString, Coords: coords})
+			return ok && validLineString(coords)
+		})
+	case MultiPolygon:
+		ok = p.list(func() bool {
+			rings, ok := p.rings()
+			g.Geoms = append(g.Geoms, &Geometry{Type: Polygon, Rings: rings})
+			return ok
+		})
+	case GeometryCollection:
+		if p.word() == "EMPTY" {
+			return &g, true
+		}
+		if p.consume('(') {
+			if p.consume(')') {
+				return &g, true
+			}
+			p.pos--
+		}
+		ok = p.list(func() bool {
+			m, ok := p.geometry(depth+1, 0)
+			g.Geoms = append(g.Geoms, m)
+			return ok
+		})
+	}
+	return &g, ok
+}
+
+// AppendWKT appends the well-known text representation of the geometry to dst
+func (g *Geometry) AppendWKT(dst []byte) []byte {
+	dst = append(dst, g.Type.String()...)
+	switch g.Type {
+	case Point:
+		dst = append(dst, '(')
+		dst = appendCoordWKT(dst, g.Coords[0])
+		dst = append(dst, ')')
+	case LineString:
+		dst = appendCoordsWKT(dst, g.Coords)
+	case Polygon:
+		dst = appendRingsWKT(dst, g.Rings)
+	case MultiPoint:
+		dst = append(dst, '(')
+		for i, m := range g.Geoms {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendCoordsWKT(dst, m.Coords)
+		}
+		dst = append(dst, ')')
+	case MultiLineString:
+		dst = append(dst, '(')
+		for i, m := range g.Geoms {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendCoordsWKT(dst, m.Coords)
+		}
+		dst = append(dst, ')')
+	case MultiPolygon:
+		dst = append(dst, '(')
+		for i, m := range g.Geoms {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendRingsWKT(dst, m.Rings)
+		}
+		dst = append(dst, ')')
+	case GeometryCollection:
+		if len(g.Geoms) == 0 {
+			return append(dst, " EMPTY"...)
+		}
+		dst = append(dst, '(')
+		for i, m := range g.Geoms {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = m.AppendWKT(dst)
+		}
+		dst = append(dst, ')')
+	}
+	return dst
+}
+
+func appendCoordWKT(dst []byte, c Coord) []byte {
+	dst = append(dst, format.FormatFloat(c.X)...)
+	dst = append(dst, ' ')
+	return append(dst, format.FormatFloat(c.Y)...)
+}
+
+func appendCoordsWKT(dst []byte, coords []Coord) []byte {
+	dst = append(dst, '(')
+	for i, c := range coords {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendCoordWKT(dst, c)
+	}
+	return append(dst, ')')
+}
+
+func appendRingsWKT(dst []byte, rings [][]Coord) []byte {
+	dst = append(dst, '(')
+	for i, ring := range rings {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendCoordsWKT(dst, ring)
+	}
+	return append(dst, ')')
+}
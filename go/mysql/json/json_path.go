@@ -119,6 +119,11 @@ func (jp *Path) ContainsWildcards() bool {
 	return false
 }
 
+// IsRoot returns whether the path points to the whole document
+func (jp *Path) IsRoot() bool {
+	return jp.kind == jpDocumentRoot && jp.next == nil
+}
+
 func (jp *Path) arrayOffsets(ary []*Value) (int, int) {
 	from := int(jp.offset0)
 	to := int(jp.offset1)
@@ -217,39 +222,68 @@ func (jp *Path) Match(doc *Value, wrap bool, match func(value *Value)) {
 	m.value(jp, doc)
 }
 
-func (jp *Path) transform(v *Value, t func(pp *Path, vv *Value)) {
-	if v == nil {
-		return
-	}
-	if jp.next == nil {
-		t(jp, v)
-		return
+// update applies the transformation t to the value that the path points to
+// inside of v, and returns the new value for v, which is only different from v
+// if the path points to v itself.
+func (jp *Path) update(v *Value, t Transformation, value *Value) *Value {
+	if jp == nil {
+		if t == Set || t == Replace {
+			return value
+		}
+		return v
 	}
 	switch jp.kind {
 	case jpDocumentRoot:
-		jp.next.transform(v, t)
+		return jp.next.update(v, t, value)
 	case jpMember:
-		if obj, ok := v.Object(); ok {
-			jp.next.transform(obj.Get(jp.name), t)
+		obj, ok := v.Object()
+		if !ok {
+			return v
 		}
-	case jpArrayLocation:
-		if ary, ok := v.Array(); ok {
-			from, to := jp.arrayOffsets(ary)
-			if from != to {
-				panic("range in transformation path expression")
-			}
-			if from >= 0 && from < len(ary) {
-				jp.next.transform(ary[from], t)
+		switch {
+		case jp.next != nil:
+			if child := obj.Get(jp.name); child != nil {
+				obj.Set(jp.name, jp.next.update(child, t, value), Replace)
 			}
-		} else if jp.offset0 == 0 || jp.offset0 == -1 {
+		case t == Remove:
+			obj.Del(jp.name)
+		default:
+			obj.Set(jp.name, value, t)
+		}
+		return v
+	case jpArrayLocation:
+		ary, ok := v.Array()
+		if !ok {
 			/*
 				If the path is evaluated against a value that is not an array,
 				the result of the evaluation is the same as if the value had been
 				wrapped in a single-element array:
 			*/
-			jp.next.transform(v, t)
+			from, _ := jp.arrayOffsets([]*Value{v})
+			switch {
+			case from == 0:
+				return jp.next.update(v, t, value)
+			case jp.next == nil && (t == Set || t == Insert):
+				return NewArray([]*Value{v, value})
+			}
+			return v
 		}
-	case jpMemberAny, jpArrayLocationAny, jpAny:
+		from, to := jp.arrayOffsets(ary)
+		if from != to {
+			panic("range in transformation path expression")
+		}
+		switch {
+		case jp.next != nil:
+			if from >= 0 && from < len(ary) {
+				ary[from] = jp.next.update(ary[from], t, value)
+			}
+		case t == Remove:
+			v.DelArrayItem(from)
+		default:
+			v.SetArrayItem(from, value, t)
+		}
+		return v
+	default:
 		panic("wildcard in transformation path expression")
 	}
 }
@@ -263,38 +297,50 @@ const (
 	Remove
 )
 
-func ApplyTransform(t Transformation, doc *Value, paths []*Path, values []*Value) error {
+// ApplyTransform applies the transformation t with each one of the paths in order, and
+// returns the resulting document. The paths cannot contain wildcards or array ranges.
+// The document is updated in place, so it must be cloned first if it's shared; the
+// result can be a different value than doc if one of the paths points to its root.
+func ApplyTransform(t Transformation, doc *Value, paths []*Path, values []*Value) *Value {
 	if t != Remove && len(paths) != len(values) {
 		panic("missing Values for transformation")
 	}
 	for i, p := range paths {
-		transform := func(pp *Path, vv *Value) {
-			switch pp.kind {
-			case jpArrayLocation:
-				if ary, ok := vv.Array(); ok {
-					from, to := pp.arrayOffsets(ary)
-					if from != to {
-						return
-					}
-					if t == Remove {
-						vv.DelArrayItem(from)
-					} else {
-						vv.SetArrayItem(from, values[i], t)
-					}
-				}
-			case jpMember:
-				if obj, ok := vv.Object(); ok {
-					if t == Remove {
-						obj.Del(pp.name)
-					} else {
-						obj.Set(pp.name, values[i], t)
-					}
-				}
+		var value *Value
+		if t != Remove {
+			value = values[i]
+		}
+		doc = p.update(doc, t, value)
+	}
+	return doc
+}
+
+// WalkPaths calls f for v and every value nested in it, in document order, along with the
+// path that leads to the value from v. The path is only valid until f returns.
+func (v *Value) WalkPaths(f func(path []byte, v *Value)) {
+	v.walkPaths(append(make([]byte, 0, 64), '$'), f)
+}
+
+func (v *Value) walkPaths(path []byte, f func(path []byte, v *Value)) {
+	f(path, v)
+	switch v.Type() {
+	case TypeObject:
+		for _, e := range v.o.kvs {
+			member := append(path, '.')
+			if jpIsIdentifier(e.k) {
+				member = append(member, e.k...)
+			} else {
+				member = escapeString(member, e.k)
 			}
+			e.v.walkPaths(member, f)
+		}
+	case TypeArray:
+		for i, e := range v.a {
+			item := append(path, '[')
+			item = strconv.AppendInt(item, int64(i), 10)
+			e.walkPaths(append(item, ']'), f)
 		}
-		p.transform(doc, transform)
 	}
-	return nil
 }
 
 func MatchPath(rawJSON, rawPath []byte, match func(value *Value)) error {
@@ -171,6 +171,41 @@ func TestTransformations(t *testing.T) {
 			Paths:    []string{`$[2]`, `$[1].b[1]`, `$[1].b[1]`},
 			Expected: `["a", {"b": [true]}]`,
 		},
+		{
+			T:        Set,
+			Document: Document1,
+			Paths:    []string{`$[2][10]`, `$[0][1]`, `$[1].c`},
+			Values:   []string{"30", `"b"`, "null"},
+			Expected: `[["a", "b"], {"b": [true, false], "c": null}, [10, 20, 30]]`,
+		},
+		{
+			T:        Insert,
+			Document: Document1,
+			Paths:    []string{`$[0][0]`, `$[last-5]`, `$[1].b`, `$[1].c`},
+			Values:   []string{"1", "2", "3", "4"},
+			Expected: `["a", {"b": [true, false], "c": 4}, [10, 20], 2]`,
+		},
+		{
+			T:        Replace,
+			Document: Document1,
+			Paths:    []string{`$[5]`, `$[1].c`, `$[0][0]`},
+			Values:   []string{"1", "2", "3"},
+			Expected: `[3, {"b": [true, false]}, [10, 20]]`,
+		},
+		{
+			T:        Set,
+			Document: Document1,
+			Paths:    []string{`$`},
+			Values:   []string{`{"a": 1}`},
+			Expected: `{"a": 1}`,
+		},
+		{
+			T:        Set,
+			Document: `1`,
+			Paths:    []string{`$[1]`},
+			Values:   []string{`2`},
+			Expected: `[1, 2]`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -186,14 +221,31 @@ func TestTransformations(t *testing.T) {
 			values = append(values, json(t, v))
 		}
 
-		err := ApplyTransform(tc.T, doc, paths, values)
-		if err != nil {
-			t.Fatal(err)
-		}
-
+		doc = ApplyTransform(tc.T, doc, paths, values)
 		result := string(doc.MarshalTo(nil))
 		if result != tc.Expected {
 			t.Errorf("bad transformation (%v)\nwant: %s\ngot:  %s", tc.T, tc.Expected, result)
 		}
 	}
 }
+
+func TestWalkPaths(t *testing.T) {
+	doc := json(t, `{"a": [1, {"b c": 2}], "d": "e"}`)
+
+	var got []string
+	doc.WalkPaths(func(path []byte, v *Value) {
+		got = append(got, string(path)+"="+v.String())
+	})
+
+	want := []string{
+		`$={"a": [1, {"b c": 2}], "d": "e"}`,
+		`$.a=[1, {"b c": 2}]`,
+		`$.a[0]=1`,
+		`$.a[1]={"b c": 2}`,
+		`$.a[1]."b c"=2`,
+		`$.d="e"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("bad paths\nwant: %v\ngot:  %v", want, got)
+	}
+}
@@ -178,3 +178,54 @@ func MarshalSQLValue(buf []byte) (*sqltypes.Value, error) {
 	newVal := sqltypes.MakeTrusted(querypb.Type_JSON, jsonVal.MarshalSQLTo(nil))
 	return &newVal, nil
 }
+
+// MarshalPretty appends v to dst in the human-readable format of JSON_PRETTY,
+// with each member of arrays and objects on its own line, indented by two spaces.
+func (v *Value) MarshalPretty(dst []byte) []byte {
+	return v.marshalPretty(dst, 0)
+}
+
+func (v *Value) marshalPretty(dst []byte, depth int) []byte {
+	switch v.Type() {
+	case TypeObject:
+		if len(v.o.kvs) == 0 {
+			return append(dst, "{}"...)
+		}
+		dst = append(dst, '{')
+		for i, e := range v.o.kvs {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendPrettyIndent(dst, depth+1)
+			dst = escapeString(dst, e.k)
+			dst = append(dst, ':', ' ')
+			dst = e.v.marshalPretty(dst, depth+1)
+		}
+		dst = appendPrettyIndent(dst, depth)
+		return append(dst, '}')
+	case TypeArray:
+		if len(v.a) == 0 {
+			return append(dst, "[]"...)
+		}
+		dst = append(dst, '[')
+		for i, e := range v.a {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendPrettyIndent(dst, depth+1)
+			dst = e.marshalPretty(dst, depth+1)
+		}
+		dst = appendPrettyIndent(dst, depth)
+		return append(dst, ']')
+	default:
+		return v.MarshalTo(dst)
+	}
+}
+
+func appendPrettyIndent(dst []byte, depth int) []byte {
+	dst = append(dst, '\n')
+	for i := 0; i < depth; i++ {
+		dst = append(dst, ' ', ' ')
+	}
+	return dst
+}
@@ -55,3 +55,36 @@ func TestMarshalSQLTo(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalPretty(t *testing.T) {
+	testcases := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    `123`,
+			expected: `123`,
+		},
+		{
+			input:    `[]`,
+			expected: `[]`,
+		},
+		{
+			input:    `[1, "a", {}]`,
+			expected: "[\n  1,\n  \"a\",\n  {}\n]",
+		},
+		{
+			input:    `{"a": {"b": [null, true]}, "c": 1}`,
+			expected: "{\n  \"a\": {\n    \"b\": [\n      null,\n      true\n    ]\n  },\n  \"c\": 1\n}",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.input, func(t *testing.T) {
+			var p Parser
+
+			v, err := p.Parse(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(v.MarshalPretty(nil)))
+		})
+	}
+}
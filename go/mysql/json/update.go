@@ -64,26 +64,22 @@ func (o *Object) Set(key string, value *Value, t Transformation) {
 	}
 }
 
-// SetArrayItem sets the value in the array v at idx position.
+// SetArrayItem sets the value in the array v at idx position. Like in MySQL,
+// setting or inserting a value past the bounds of the array appends it.
 //
 // The value must be unchanged during v lifetime.
 func (v *Value) SetArrayItem(idx int, value *Value, t Transformation) {
-	if v == nil || v.t != TypeArray || idx < 0 {
+	if v == nil || v.t != TypeArray {
 		return
 	}
-	switch t {
-	case Insert:
-		if idx < len(v.a) {
-			return
-		}
-		fallthrough
-	case Set:
-		for idx >= len(v.a) {
-			v.a = append(v.a, ValueNull)
+	if idx >= 0 && idx < len(v.a) {
+		if t != Insert {
+			v.a[idx] = value
 		}
+		return
 	}
-	if idx < len(v.a) {
-		v.a[idx] = value
+	if t != Replace {
+		v.a = append(v.a, value)
 	}
 }
 
@@ -96,3 +92,71 @@ func (v *Value) DelArrayItem(n int) {
 	}
 	v.a = append(v.a[:n], v.a[n+1:]...)
 }
+
+// Clone returns a deep copy of v that can be updated without changing v.
+// Scalars cannot be updated, so they are shared between v and its copy.
+func (v *Value) Clone() *Value {
+	switch v.Type() {
+	case TypeObject:
+		kvs := make([]kv, len(v.o.kvs))
+		for i, e := range v.o.kvs {
+			kvs[i] = kv{k: e.k, v: e.v.Clone()}
+		}
+		return &Value{o: Object{kvs: kvs}, t: TypeObject}
+	case TypeArray:
+		a := make([]*Value, len(v.a))
+		for i, e := range v.a {
+			a[i] = e.Clone()
+		}
+		return &Value{a: a, t: TypeArray}
+	default:
+		return v
+	}
+}
+
+// MergePreserve merges two documents like JSON_MERGE_PRESERVE: two objects are merged into
+// one, combining the values of the keys they have in common, and any other two values are
+// concatenated into an array. Both documents are updated in place.
+func MergePreserve(a, b *Value) *Value {
+	if a.Type() == TypeObject && b.Type() == TypeObject {
+		for _, e := range b.o.kvs {
+			if i, found := a.o.find(e.k); found {
+				a.o.kvs[i].v = MergePreserve(a.o.kvs[i].v, e.v)
+			} else {
+				a.o.kvs = slices.Insert(a.o.kvs, i, e)
+			}
+		}
+		return a
+	}
+
+	ary, ok := a.Array()
+	if !ok {
+		ary = []*Value{a}
+	}
+	if more, ok := b.Array(); ok {
+		ary = append(ary, more...)
+	} else {
+		ary = append(ary, b)
+	}
+	return NewArray(ary)
+}
+
+// MergePatch applies patch to target following the semantics of RFC 7396, like
+// JSON_MERGE_PATCH: members of the patch replace the ones in the target, except
+// for null members which remove them. Both documents are updated in place.
+func MergePatch(target, patch *Value) *Value {
+	if patch.Type() != TypeObject {
+		return patch
+	}
+	if target.Type() != TypeObject {
+		target = NewObject(Object{})
+	}
+	for _, e := range patch.o.kvs {
+		if e.v.Type() == TypeNull {
+			target.o.Del(e.k)
+			continue
+		}
+		target.o.Set(e.k, MergePatch(target.o.Get(e.k), e.v), Set)
+	}
+	return target
+}
@@ -19,6 +19,8 @@ package json
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func MustParse(j string) *Value {
@@ -106,3 +108,66 @@ func TestValueDelSet(t *testing.T) {
 		t.Fatalf("unexpected number of items left in the array; got %d; want %d", len(a), 2)
 	}
 }
+
+func TestClone(t *testing.T) {
+	v := MustParse(`{"a": [1, {"b": 2}]}`)
+	c := v.Clone()
+
+	o, _ := c.Object()
+	o.Set("c", ValueTrue, Set)
+	o.Get("a").SetArrayItem(5, ValueNull, Set)
+
+	require.Equal(t, `{"a": [1, {"b": 2}]}`, v.String())
+	require.Equal(t, `{"a": [1, {"b": 2}, null], "c": true}`, c.String())
+}
+
+func TestMerge(t *testing.T) {
+	testcases := []struct {
+		a, b     string
+		preserve string
+		patch    string
+	}{
+		{
+			a:        `[1, 2]`,
+			b:        `[true, false]`,
+			preserve: `[1, 2, true, false]`,
+			patch:    `[true, false]`,
+		},
+		{
+			a:        `{"name": "x"}`,
+			b:        `{"id": 47}`,
+			preserve: `{"id": 47, "name": "x"}`,
+			patch:    `{"id": 47, "name": "x"}`,
+		},
+		{
+			a:        `1`,
+			b:        `true`,
+			preserve: `[1, true]`,
+			patch:    `true`,
+		},
+		{
+			a:        `[1, 2]`,
+			b:        `{"id": 47}`,
+			preserve: `[1, 2, {"id": 47}]`,
+			patch:    `{"id": 47}`,
+		},
+		{
+			a:        `{"a": 1, "b": 2}`,
+			b:        `{"a": 3, "c": 4}`,
+			preserve: `{"a": [1, 3], "b": 2, "c": 4}`,
+			patch:    `{"a": 3, "b": 2, "c": 4}`,
+		},
+		{
+			a:        `{"a": 1, "b": {"c": 2}}`,
+			b:        `{"a": null, "b": {"d": null}, "e": {"f": null}}`,
+			preserve: `{"a": [1, null], "b": {"c": 2, "d": null}, "e": {"f": null}}`,
+			patch:    `{"b": {"c": 2}, "e": {}}`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			require.Equal(t, tc.preserve, MergePreserve(MustParse(tc.a), MustParse(tc.b)).String())
+			require.Equal(t, tc.patch, MergePatch(MustParse(tc.a), MustParse(tc.b)).String())
+		})
+	}
+}
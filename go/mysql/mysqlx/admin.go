@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"strings"
+
+	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
+	"github.com/mdibaiee/vitess/go/sqlescape"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+// notices are the notices that the clients can list, only the warnings can
+// be disabled.
+var notices = []string{"warnings", "account_expired", "generated_insert_id", "rows_affected", "produced_message", "generated_document_ids"}
+
+// adminCommand runs a command of the mysqlx namespace, whose arguments are
+// the fields of an object. The commands on collections are translated to SQL
+// like the CRUD messages.
+func (l *Listener) adminCommand(c *Conn, command string, args []*anyValue) (*sqltypes.Result, error) {
+	var arg *anyValue
+	switch {
+	case len(args) == 1 && args[0].typ == anyObject:
+		arg = args[0]
+	case len(args) > 0:
+		return nil, newInvalidArgument("Invalid type of arguments for '%s', expected an object", command)
+	}
+	str := func(name string, required bool) (string, error) {
+		value := arg.field(name)
+		if value == nil && !required {
+			return "", nil
+		}
+		s, ok := value.str()
+		if !ok {
+			return "", newInvalidArgument("Invalid or missing argument '%s' for '%s'", name, command)
+		}
+		return s, nil
+	}
+	schema := func() (string, error) {
+		schema, err := str("schema", false)
+		if err != nil || schema != "" {
+			return schema, err
+		}
+		if schema = l.handler.CurrentSchema(c); schema == "" {
+			return "", sqlerror.NewSQLError(sqlerror.ERNoDb, sqlerror.SSNoDB, "No database selected")
+		}
+		return schema, nil
+	}
+
+	switch command {
+	case "ping":
+		return &sqltypes.Result{}, nil
+	case "create_collection", "ensure_collection":
+		schema, err := schema()
+		if err != nil {
+			return nil, err
+		}
+		name, err := str("name", true)
+		if err != nil {
+			return nil, err
+		}
+		ifNotExists := command == "ensure_collection"
+		if options := arg.field("options"); options != nil {
+			if options.field("validation") != nil {
+				return nil, newInvalidArgument("Collection validation is not supported")
+			}
+			reuse, _ := options.field("reuse_existing").boolean()
+			ifNotExists = ifNotExists || reuse
+		}
+		return l.handler.Execute(c, createCollectionSQL(schema, name, ifNotExists), nil)
+	case "drop_collection":
+		schema, err := schema()
+		if err != nil {
+			return nil, err
+		}
+		name, err := str("name", true)
+		if err != nil {
+			return nil, err
+		}
+		return l.handler.Execute(c, "DROP TABLE "+sqlescape.EscapeID(schema)+"."+sqlescape.EscapeID(name), nil)
+	case "list_objects":
+		schema, err := schema()
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := str("pattern", false)
+		if err != nil {
+			return nil, err
+		}
+		return l.listObjects(c, schema, pattern)
+	case "enable_notices", "disable_notices":
+		names := arg.field("notice")
+		if names == nil || names.typ != anyArray {
+			return nil, newInvalidArgument("Invalid or missing argument 'notice' for '%s'", command)
+		}
+		enable := command == "enable_notices"
+		warnings := c.warnings
+		for _, value := range names.array {
+			name, _ := value.str()
+			switch {
+			case name == "warnings":
+				warnings = enable
+			case !enable || !knownNotice(name):
+				return nil, newInvalidArgument("Invalid notice name %s", name)
+			}
+		}
+		c.warnings = warnings
+		return &sqltypes.Result{}, nil
+	case "list_notices":
+		result := &sqltypes.Result{Fields: []*querypb.Field{
+			{Name: "notice", Type: sqltypes.VarChar, Charset: uint32(collationUtf8mb4)},
+			{Name: "enabled", Type: sqltypes.Int64},
+		}}
+		for _, name := range notices {
+			enabled := name != "warnings" || c.warnings
+			result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(name), sqltypes.NewInt64(boolToInt64(enabled))})
+		}
+		return result, nil
+	}
+	return nil, sqlerror.NewSQLError(ERXInvalidAdminCommand, sqlerror.SSUnknownSQLState, "Invalid mysqlx command %s", command)
+}
+
+// collationUtf8mb4 is the utf8mb4_0900_ai_ci collation of the results of the admin commands.
+const collationUtf8mb4 = 255
+
+func knownNotice(name string) bool {
+	for _, notice := range notices {
+		if notice == name {
+			return true
+		}
+	}
+	return false
+}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// listObjects lists the tables, views and collections of a schema. The
+// collections are the tables with the doc and _id columns, that only have
+// other generated columns for their indexes and schema validation.
+func (l *Listener) listObjects(c *Conn, schema, pattern string) (*sqltypes.Result, error) {
+	filter := "TABLE_SCHEMA = " + sqltypes.EncodeStringSQL(schema)
+	if pattern != "" {
+		filter += " AND TABLE_NAME LIKE " + sqltypes.EncodeStringSQL(pattern)
+	}
+	tables, err := l.handler.Execute(c, "SELECT TABLE_NAME, TABLE_TYPE FROM information_schema.TABLES WHERE "+filter+" ORDER BY TABLE_NAME", nil)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := l.handler.Execute(c, "SELECT TABLE_NAME, COLUMN_NAME FROM information_schema.COLUMNS WHERE "+filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type documentColumns struct{ doc, id, other bool }
+	tableColumns := make(map[string]*documentColumns)
+	for _, row := range columns.Rows {
+		table := row[0].ToString()
+		cols := tableColumns[table]
+		if cols == nil {
+			cols = &documentColumns{}
+			tableColumns[table] = cols
+		}
+		switch column := row[1].ToString(); {
+		case column == "doc":
+			cols.doc = true
+		case column == idMember:
+			cols.id = true
+		case column == "_json_schema", strings.HasPrefix(column, "$ix_"):
+		default:
+			cols.other = true
+		}
+	}
+
+	result := &sqltypes.Result{Fields: []*querypb.Field{
+		{Name: "name", Type: sqltypes.VarChar, Charset: uint32(collationUtf8mb4)},
+		{Name: "type", Type: sqltypes.VarChar, Charset: uint32(collationUtf8mb4)},
+	}}
+	for _, row := range tables.Rows {
+		name := row[0].ToString()
+		isCollection := false
+		if cols := tableColumns[name]; cols != nil {
+			isCollection = cols.doc && cols.id && !cols.other
+		}
+		typ := "TABLE"
+		switch {
+		case row[1].ToString() == "VIEW" && isCollection:
+			typ = "COLLECTION_VIEW"
+		case row[1].ToString() == "VIEW":
+			typ = "VIEW"
+		case isCollection:
+			typ = "COLLECTION"
+		}
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(name), sqltypes.NewVarChar(typ)})
+	}
+	return result, nil
+}
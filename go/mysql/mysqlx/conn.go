@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mdibaiee/vitess/go/mysql"
+	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
+	"github.com/mdibaiee/vitess/go/vt/log"
+)
+
+const connBufferSize = 16 * 1024
+
+// Conn is a connection between a client and the server, using the X Protocol.
+//
+// Every message is framed by its length as a 4 bytes little endian integer,
+// which counts the type, followed by its type as a byte and its payload.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+
+	// ConnectionID is set by the Listener when the connection is accepted.
+	ConnectionID uint32
+
+	// User and UserData are set once the client is authenticated.
+	User     string
+	UserData mysql.Getter
+
+	// SchemaName is the default schema the client asked for when it authenticated.
+	SchemaName string
+
+	// Attributes are the session_connect_attrs sent by the client.
+	Attributes map[string]string
+
+	// ClientData is a place where the Handler can store its data.
+	ClientData any
+
+	// tlsEnabled is set once the connection was upgraded to TLS.
+	tlsEnabled bool
+
+	// warnings is set if the warnings are sent as notices, which is the default.
+	warnings bool
+
+	// expect is the stack of the open Expect blocks.
+	expect []expectBlock
+
+	closed atomic.Bool
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	closing bool
+}
+
+// expectBlock is a block of messages opened by Mysqlx.Expect.Open.
+type expectBlock struct {
+	noError bool
+	// failed is set once a message of a no_error block failed, the following
+	// messages of the block fail too.
+	failed bool
+}
+
+func newConn(conn net.Conn, connectionID uint32) *Conn {
+	return &Conn{
+		conn:         conn,
+		reader:       bufio.NewReaderSize(conn, connBufferSize),
+		writer:       bufio.NewWriterSize(conn, connBufferSize),
+		ConnectionID: connectionID,
+		warnings:     true,
+	}
+}
+
+// readMessage reads the next message of the client.
+func (c *Conn) readMessage(maxMessageSize int) (byte, []byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := binary.LittleEndian.Uint32(header[:])
+	if length == 0 || int64(length) > int64(maxMessageSize) {
+		return 0, nil, sqlerror.NewSQLError(sqlerror.ERNetPacketTooLarge, sqlerror.SSNetError, "Got a packet bigger than 'mysqlx_max_allowed_packet' bytes")
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, data); err != nil {
+		return 0, nil, err
+	}
+	return data[0], data[1:], nil
+}
+
+// writeMessage buffers a message to the client, until flush is called.
+func (c *Conn) writeMessage(typ byte, payload []byte) error {
+	var header [5]byte
+	binary.LittleEndian.PutUint32(header[:4], uint32(len(payload)+1))
+	header[4] = typ
+	if _, err := c.writer.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := c.writer.Write(payload)
+	return err
+}
+
+func (c *Conn) flush() error {
+	return c.writer.Flush()
+}
+
+// writeError sends an error to the client. It returns an error if it could not.
+func (c *Conn) writeError(err error, fatal bool) error {
+	sqlErr, ok := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+	if !ok {
+		sqlErr = sqlerror.NewSQLError(sqlerror.ERUnknownError, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+	if err := c.writeMessage(ServerError, appendError(nil, sqlErr, fatal)); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// writeOk sends a Mysqlx.Ok to the client.
+func (c *Conn) writeOk(msg string) error {
+	if err := c.writeMessage(ServerOk, appendOk(nil, msg)); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// writeNotice buffers a notice to the client.
+func (c *Conn) writeNotice(typ uint64, scope uint64, payload []byte) error {
+	return c.writeMessage(ServerNotice, appendNotice(nil, typ, scope, payload))
+}
+
+// startTLS upgrades the connection to TLS.
+func (c *Conn) startTLS(config *tls.Config) error {
+	conn := tls.Server(c.conn, config)
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+	c.conn = conn
+	c.reader = bufio.NewReaderSize(conn, connBufferSize)
+	c.writer = bufio.NewWriterSize(conn, connBufferSize)
+	c.tlsEnabled = true
+	return nil
+}
+
+// TLSEnabled returns true if this connection is using TLS.
+func (c *Conn) TLSEnabled() bool {
+	return c.tlsEnabled
+}
+
+// RemoteAddr returns the underlying socket RemoteAddr().
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// String returns a useful identification string for error logging.
+func (c *Conn) String() string {
+	return fmt.Sprintf("X Protocol client %v (%s)", c.ConnectionID, c.RemoteAddr().String())
+}
+
+// Close closes the connection. It can be called from a different go
+// routine to interrupt the current connection.
+func (c *Conn) Close() {
+	if c.closed.CompareAndSwap(false, true) {
+		if err := c.conn.Close(); err != nil {
+			log.Warningf("Error closing %s: %v", c, err)
+		}
+	}
+}
+
+// CancelCtx aborts an existing running query.
+func (c *Conn) CancelCtx() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// UpdateCancelCtx updates the cancel function on the connection.
+func (c *Conn) UpdateCancelCtx(cancel context.CancelFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancel = cancel
+}
+
+// MarkForClose marks the connection for close, once its current message is handled.
+func (c *Conn) MarkForClose() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closing = true
+}
+
+// IsMarkedForClose return true if the connection should be closed.
+func (c *Conn) IsMarkedForClose() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closing
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mysqlx implements the server side of MySQL's X Protocol.
+//
+// Every message of the protocol is a frame made of its length, its type and a
+// protobuf payload. The messages are encoded and decoded by hand, like the
+// packets of the classic protocol in go/mysql. SQL statements are handed to
+// a Handler as they are, while the CRUD messages of the document store are
+// translated to SQL first.
+package mysqlx
+
+// Client message types, from Mysqlx.ClientMessages.Type.
+const (
+	ClientConCapabilitiesGet       = 1
+	ClientConCapabilitiesSet       = 2
+	ClientConClose                 = 3
+	ClientSessAuthenticateStart    = 4
+	ClientSessAuthenticateContinue = 5
+	ClientSessReset                = 6
+	ClientSessClose                = 7
+	ClientSQLStmtExecute           = 12
+	ClientCrudFind                 = 17
+	ClientCrudInsert               = 18
+	ClientCrudUpdate               = 19
+	ClientCrudDelete               = 20
+	ClientExpectOpen               = 24
+	ClientExpectClose              = 25
+)
+
+// Server message types, from Mysqlx.ServerMessages.Type.
+const (
+	ServerOk                        = 0
+	ServerError                     = 1
+	ServerConnCapabilities          = 2
+	ServerSessAuthenticateContinue  = 3
+	ServerSessAuthenticateOk        = 4
+	ServerNotice                    = 11
+	ServerResultsetColumnMetaData   = 12
+	ServerResultsetRow              = 13
+	ServerResultsetFetchDone        = 14
+	ServerResultsetFetchDoneMoreRes = 16
+	ServerSQLStmtExecuteOk          = 17
+)
+
+// Notice types and scopes, from Mysqlx.Notice.Frame.
+const (
+	NoticeWarning             = 1
+	NoticeSessionStateChanged = 3
+
+	NoticeScopeGlobal = 1
+	NoticeScopeLocal  = 2
+)
+
+// Warning levels, from Mysqlx.Notice.Warning.
+const (
+	WarningLevelNote    = 1
+	WarningLevelWarning = 2
+	WarningLevelError   = 3
+)
+
+// Session state parameters, from Mysqlx.Notice.SessionStateChanged.
+const (
+	StateCurrentSchema        = 1
+	StateGeneratedInsertID    = 3
+	StateRowsAffected         = 4
+	StateRowsFound            = 5
+	StateRowsMatched          = 6
+	StateProducedMessage      = 10
+	StateClientIDAssigned     = 11
+	StateGeneratedDocumentIDs = 12
+)
+
+// Column types, from Mysqlx.Resultset.ColumnMetaData.
+const (
+	ColumnTypeSint     = 1
+	ColumnTypeUint     = 2
+	ColumnTypeDouble   = 5
+	ColumnTypeFloat    = 6
+	ColumnTypeBytes    = 7
+	ColumnTypeTime     = 10
+	ColumnTypeDatetime = 12
+	ColumnTypeSet      = 15
+	ColumnTypeEnum     = 16
+	ColumnTypeBit      = 17
+	ColumnTypeDecimal  = 18
+)
+
+// Column flags, from Mysqlx.Resultset.ColumnMetaData.
+const (
+	ColumnFlagUintZerofill  = 0x0001
+	ColumnFlagBytesRightpad = 0x0001
+	ColumnFlagNotNull       = 0x0010
+	ColumnFlagPrimaryKey    = 0x0020
+	ColumnFlagUniqueKey     = 0x0040
+	ColumnFlagMultipleKey   = 0x0080
+	ColumnFlagAutoIncrement = 0x0100
+)
+
+// Content types of the BYTES columns and of the octets scalars, from Mysqlx.Resultset.ContentType_BYTES.
+const (
+	ContentTypeGeometry = 1
+	ContentTypeJSON     = 2
+	ContentTypeXML      = 3
+)
+
+// Error codes of the X Plugin, that the protocol sends on top of the ones of the server.
+const (
+	// ERXBadMessage is ER_X_BAD_MESSAGE.
+	ERXBadMessage = 5000
+	// ERXCapabilitiesPrepareFailed is ER_X_CAPABILITIES_PREPARE_FAILED.
+	ERXCapabilitiesPrepareFailed = 5001
+	// ERXCapabilityNotFound is ER_X_CAPABILITY_NOT_FOUND.
+	ERXCapabilityNotFound = 5002
+	// ERXInvalidArgument is ER_X_INVALID_ARGUMENT.
+	ERXInvalidArgument = 5012
+	// ERXMissingArgument is ER_X_MISSING_ARGUMENT.
+	ERXMissingArgument = 5013
+	// ERXExprBadOperator is ER_X_EXPR_BAD_OPERATOR.
+	ERXExprBadOperator = 5150
+	// ERXExprBadNumArgs is ER_X_EXPR_BAD_NUM_ARGS.
+	ERXExprBadNumArgs = 5151
+	// ERXExprMissingArg is ER_X_EXPR_MISSING_ARG.
+	ERXExprMissingArg = 5152
+	// ERXExprBadTypeValue is ER_X_EXPR_BAD_TYPE_VALUE.
+	ERXExprBadTypeValue = 5153
+	// ERXExprBadValue is ER_X_EXPR_BAD_VALUE.
+	ERXExprBadValue = 5154
+	// ERXInvalidCollection is ER_X_INVALID_COLLECTION.
+	ERXInvalidCollection = 5156
+	// ERXInvalidAdminCommand is ER_X_INVALID_ADMIN_COMMAND.
+	ERXInvalidAdminCommand = 5157
+	// ERXExpectNotOpen is ER_X_EXPECT_NOT_OPEN.
+	ERXExpectNotOpen = 5158
+	// ERXExpectFailed is ER_X_EXPECT_FAILED.
+	ERXExpectFailed = 5159
+	// ERXExpectBadCondition is ER_X_EXPECT_BAD_CONDITION.
+	ERXExpectBadCondition = 5160
+)
+
+// defaultMaxMessageSize is the default largest message the server accepts,
+// like the mysqlx_max_allowed_packet default of the X Plugin.
+const defaultMaxMessageSize = 64 * 1024 * 1024
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Expression types, from Mysqlx.Expr.Expr.Type.
+const (
+	exprIdent       = 1
+	exprLiteral     = 2
+	exprVariable    = 3
+	exprFuncCall    = 4
+	exprOperator    = 5
+	exprPlaceholder = 6
+	exprObject      = 7
+	exprArray       = 8
+)
+
+// Document path item types, from Mysqlx.Expr.DocumentPathItem.Type.
+const (
+	pathMember             = 1
+	pathMemberAsterisk     = 2
+	pathArrayIndex         = 3
+	pathArrayIndexAsterisk = 4
+	pathDoubleAsterisk     = 5
+)
+
+// expr is a Mysqlx.Expr.Expr. FUNC_CALL and OPERATOR expressions both
+// keep their name and parameters in name and params.
+type expr struct {
+	typ        uint64
+	identifier *columnIdentifier
+	variable   string
+	literal    *scalar
+	// schema is the schema of the function of a FUNC_CALL.
+	schema   string
+	name     string
+	params   []*expr
+	position uint64
+	object   []exprField
+	array    []*expr
+}
+
+// exprField is a Mysqlx.Expr.Object.ObjectField.
+type exprField struct {
+	key   string
+	value *expr
+}
+
+// columnIdentifier is a Mysqlx.Expr.ColumnIdentifier.
+type columnIdentifier struct {
+	documentPath []documentPathItem
+	name         string
+	tableName    string
+	schemaName   string
+}
+
+// documentPathItem is a Mysqlx.Expr.DocumentPathItem.
+type documentPathItem struct {
+	typ   uint64
+	value string
+	index uint64
+}
+
+func decodeExpr(data []byte) (*expr, error) {
+	e := &expr{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		var err error
+		switch num {
+		case 1:
+			e.typ = v
+		case 2:
+			e.identifier, err = decodeColumnIdentifier(b)
+		case 3:
+			e.variable = string(b)
+		case 4:
+			e.literal, err = decodeScalar(b)
+		case 5:
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				switch num {
+				case 1:
+					return decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+						switch num {
+						case 1:
+							e.name = string(b)
+						case 2:
+							e.schema = string(b)
+						}
+						return nil
+					})
+				case 2:
+					param, err := decodeExpr(b)
+					e.params = append(e.params, param)
+					return err
+				}
+				return nil
+			})
+		case 6:
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				switch num {
+				case 1:
+					e.name = string(b)
+				case 2:
+					param, err := decodeExpr(b)
+					e.params = append(e.params, param)
+					return err
+				}
+				return nil
+			})
+		case 7:
+			e.position = v
+		case 8:
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				if num != 1 {
+					return nil
+				}
+				var f exprField
+				err := decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+					var err error
+					switch num {
+					case 1:
+						f.key = string(b)
+					case 2:
+						f.value, err = decodeExpr(b)
+					}
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				if f.value == nil {
+					return errBadMessage
+				}
+				e.object = append(e.object, f)
+				return nil
+			})
+		case 9:
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				if num != 1 {
+					return nil
+				}
+				value, err := decodeExpr(b)
+				e.array = append(e.array, value)
+				return err
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case e.typ == exprIdent && e.identifier == nil,
+		e.typ == exprLiteral && e.literal == nil:
+		return nil, errBadMessage
+	}
+	return e, nil
+}
+
+func decodeColumnIdentifier(data []byte) (*columnIdentifier, error) {
+	id := &columnIdentifier{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			item, err := decodeDocumentPathItem(b)
+			id.documentPath = append(id.documentPath, item)
+			return err
+		case 2:
+			id.name = string(b)
+		case 3:
+			id.tableName = string(b)
+		case 4:
+			id.schemaName = string(b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+func decodeDocumentPathItem(data []byte) (documentPathItem, error) {
+	var item documentPathItem
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			item.typ = v
+		case 2:
+			item.value = string(b)
+		case 3:
+			item.index = v
+		}
+		return nil
+	})
+	return item, err
+}
@@ -0,0 +1,550 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
+)
+
+//
+// Client messages
+//
+
+// capability is a Mysqlx.Connection.Capability.
+type capability struct {
+	name  string
+	value *anyValue
+}
+
+// parseCapabilitiesSet decodes a Mysqlx.Connection.CapabilitiesSet.
+func parseCapabilitiesSet(data []byte) ([]capability, error) {
+	var capabilities []capability
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		if num != 1 {
+			return nil
+		}
+		return decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+			if num != 1 {
+				return nil
+			}
+			var c capability
+			err := decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				var err error
+				switch num {
+				case 1:
+					c.name = string(b)
+				case 2:
+					c.value, err = decodeAny(b)
+				}
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if c.value == nil {
+				return errBadMessage
+			}
+			capabilities = append(capabilities, c)
+			return nil
+		})
+	})
+	return capabilities, err
+}
+
+// authenticateStart is a Mysqlx.Session.AuthenticateStart.
+type authenticateStart struct {
+	mechName        string
+	authData        []byte
+	initialResponse []byte
+}
+
+func parseAuthenticateStart(data []byte) (*authenticateStart, error) {
+	msg := &authenticateStart{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			msg.mechName = string(b)
+		case 2:
+			msg.authData = b
+		case 3:
+			msg.initialResponse = b
+		}
+		return nil
+	})
+	return msg, err
+}
+
+// parseAuthenticateContinue decodes a Mysqlx.Session.AuthenticateContinue,
+// and returns its auth_data.
+func parseAuthenticateContinue(data []byte) ([]byte, error) {
+	var authData []byte
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		if num == 1 {
+			authData = b
+		}
+		return nil
+	})
+	return authData, err
+}
+
+// parseSessReset decodes a Mysqlx.Session.Reset, and returns its keep_open.
+func parseSessReset(data []byte) (bool, error) {
+	var keepOpen bool
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		if num == 1 {
+			keepOpen = v != 0
+		}
+		return nil
+	})
+	return keepOpen, err
+}
+
+// stmtExecute is a Mysqlx.Sql.StmtExecute.
+type stmtExecute struct {
+	namespace string
+	stmt      string
+	args      []*anyValue
+}
+
+func parseStmtExecute(data []byte) (*stmtExecute, error) {
+	msg := &stmtExecute{namespace: "sql"}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			msg.stmt = string(b)
+		case 2:
+			arg, err := decodeAny(b)
+			msg.args = append(msg.args, arg)
+			return err
+		case 3:
+			msg.namespace = string(b)
+		}
+		return nil
+	})
+	return msg, err
+}
+
+// Data models of the CRUD messages, from Mysqlx.Crud.DataModel.
+const (
+	dataModelDocument = 1
+	dataModelTable    = 2
+)
+
+// Row locks of Mysqlx.Crud.Find.
+const (
+	lockShared    = 1
+	lockExclusive = 2
+
+	lockNoWait     = 1
+	lockSkipLocked = 2
+)
+
+// Update operations, from Mysqlx.Crud.UpdateOperation.UpdateType.
+const (
+	updateSet         = 1
+	updateItemRemove  = 2
+	updateItemSet     = 3
+	updateItemReplace = 4
+	updateItemMerge   = 5
+	updateArrayInsert = 6
+	updateArrayAppend = 7
+	updateMergePatch  = 8
+)
+
+// crud holds the fields that all the CRUD messages have.
+type crud struct {
+	schema    string
+	table     string
+	dataModel uint64
+	criteria  *expr
+	order     []order
+	rowCount  *expr
+	offset    *expr
+	args      []*scalar
+}
+
+// order is a Mysqlx.Crud.Order.
+type order struct {
+	expr *expr
+	desc bool
+}
+
+// projection is a Mysqlx.Crud.Projection.
+type projection struct {
+	source *expr
+	alias  string
+}
+
+// crudFind is a Mysqlx.Crud.Find.
+type crudFind struct {
+	crud
+	projection       []projection
+	grouping         []*expr
+	groupingCriteria *expr
+	locking          uint64
+	lockingOptions   uint64
+}
+
+// crudInsert is a Mysqlx.Crud.Insert.
+type crudInsert struct {
+	crud
+	columns []string
+	rows    [][]*expr
+	upsert  bool
+}
+
+// updateOperation is a Mysqlx.Crud.UpdateOperation.
+type updateOperation struct {
+	source    *columnIdentifier
+	operation uint64
+	value     *expr
+}
+
+// crudUpdate is a Mysqlx.Crud.Update.
+type crudUpdate struct {
+	crud
+	operations []updateOperation
+}
+
+// crudDelete is a Mysqlx.Crud.Delete.
+type crudDelete struct {
+	crud
+}
+
+func decodeCollection(c *crud, data []byte) error {
+	return decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			c.table = string(b)
+		case 2:
+			c.schema = string(b)
+		}
+		return nil
+	})
+}
+
+func decodeOrder(c *crud, data []byte) error {
+	var o order
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		var err error
+		switch num {
+		case 1:
+			o.expr, err = decodeExpr(b)
+		case 2:
+			o.desc = v == 2
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if o.expr == nil {
+		return errBadMessage
+	}
+	c.order = append(c.order, o)
+	return nil
+}
+
+// decodeLimit decodes a Mysqlx.Crud.Limit, or a Mysqlx.Crud.LimitExpr.
+func decodeLimit(c *crud, data []byte, isExpr bool) error {
+	return decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		e := &expr{typ: exprLiteral, literal: uintScalar(v)}
+		if isExpr {
+			var err error
+			if e, err = decodeExpr(b); err != nil {
+				return err
+			}
+		}
+		switch num {
+		case 1:
+			c.rowCount = e
+		case 2:
+			c.offset = e
+		}
+		return nil
+	})
+}
+
+func decodeArg(c *crud, data []byte) error {
+	arg, err := decodeScalar(data)
+	c.args = append(c.args, arg)
+	return err
+}
+
+func parseCrudFind(data []byte) (*crudFind, error) {
+	msg := &crudFind{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		var err error
+		switch num {
+		case 2:
+			err = decodeCollection(&msg.crud, b)
+		case 3:
+			msg.dataModel = v
+		case 4:
+			var p projection
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				var err error
+				switch num {
+				case 1:
+					p.source, err = decodeExpr(b)
+				case 2:
+					p.alias = string(b)
+				}
+				return err
+			})
+			if err == nil && p.source == nil {
+				err = errBadMessage
+			}
+			msg.projection = append(msg.projection, p)
+		case 5:
+			msg.criteria, err = decodeExpr(b)
+		case 6:
+			err = decodeLimit(&msg.crud, b, false)
+		case 7:
+			err = decodeOrder(&msg.crud, b)
+		case 8:
+			var grouping *expr
+			grouping, err = decodeExpr(b)
+			msg.grouping = append(msg.grouping, grouping)
+		case 9:
+			msg.groupingCriteria, err = decodeExpr(b)
+		case 11:
+			err = decodeArg(&msg.crud, b)
+		case 12:
+			msg.locking = v
+		case 13:
+			msg.lockingOptions = v
+		case 14:
+			err = decodeLimit(&msg.crud, b, true)
+		}
+		return err
+	})
+	return msg, err
+}
+
+func parseCrudInsert(data []byte) (*crudInsert, error) {
+	msg := &crudInsert{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		var err error
+		switch num {
+		case 1:
+			err = decodeCollection(&msg.crud, b)
+		case 2:
+			msg.dataModel = v
+		case 3:
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				if num == 1 {
+					msg.columns = append(msg.columns, string(b))
+				}
+				return nil
+			})
+		case 4:
+			var row []*expr
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				if num != 1 {
+					return nil
+				}
+				field, err := decodeExpr(b)
+				row = append(row, field)
+				return err
+			})
+			msg.rows = append(msg.rows, row)
+		case 5:
+			err = decodeArg(&msg.crud, b)
+		case 6:
+			msg.upsert = v != 0
+		}
+		return err
+	})
+	return msg, err
+}
+
+func parseCrudUpdate(data []byte) (*crudUpdate, error) {
+	msg := &crudUpdate{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		var err error
+		switch num {
+		case 2:
+			err = decodeCollection(&msg.crud, b)
+		case 3:
+			msg.dataModel = v
+		case 4:
+			msg.criteria, err = decodeExpr(b)
+		case 5:
+			err = decodeLimit(&msg.crud, b, false)
+		case 6:
+			err = decodeOrder(&msg.crud, b)
+		case 7:
+			var op updateOperation
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				var err error
+				switch num {
+				case 1:
+					op.source, err = decodeColumnIdentifier(b)
+				case 2:
+					op.operation = v
+				case 3:
+					op.value, err = decodeExpr(b)
+				}
+				return err
+			})
+			if err == nil && op.source == nil {
+				err = errBadMessage
+			}
+			msg.operations = append(msg.operations, op)
+		case 8:
+			err = decodeArg(&msg.crud, b)
+		case 9:
+			err = decodeLimit(&msg.crud, b, true)
+		}
+		return err
+	})
+	return msg, err
+}
+
+func parseCrudDelete(data []byte) (*crudDelete, error) {
+	msg := &crudDelete{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		var err error
+		switch num {
+		case 1:
+			err = decodeCollection(&msg.crud, b)
+		case 2:
+			msg.dataModel = v
+		case 3:
+			msg.criteria, err = decodeExpr(b)
+		case 4:
+			err = decodeLimit(&msg.crud, b, false)
+		case 5:
+			err = decodeOrder(&msg.crud, b)
+		case 6:
+			err = decodeArg(&msg.crud, b)
+		case 7:
+			err = decodeLimit(&msg.crud, b, true)
+		}
+		return err
+	})
+	return msg, err
+}
+
+// Conditions of Mysqlx.Expect.Open.
+const (
+	expectNoError = 1
+
+	expectOpSet   = 0
+	expectOpUnset = 1
+
+	expectCtxCopyPrev = 0
+	expectCtxEmpty    = 1
+)
+
+// expectCondition is a Mysqlx.Expect.Open.Condition.
+type expectCondition struct {
+	key   uint64
+	value []byte
+	op    uint64
+}
+
+// expectOpen is a Mysqlx.Expect.Open.
+type expectOpen struct {
+	op         uint64
+	conditions []expectCondition
+}
+
+func parseExpectOpen(data []byte) (*expectOpen, error) {
+	msg := &expectOpen{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			msg.op = v
+		case 2:
+			var cond expectCondition
+			err := decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				switch num {
+				case 1:
+					cond.key = v
+				case 2:
+					cond.value = b
+				case 3:
+					cond.op = v
+				}
+				return nil
+			})
+			msg.conditions = append(msg.conditions, cond)
+			return err
+		}
+		return nil
+	})
+	return msg, err
+}
+
+//
+// Server messages
+//
+
+// appendOk encodes a Mysqlx.Ok.
+func appendOk(b []byte, msg string) []byte {
+	if msg == "" {
+		return b
+	}
+	return appendStringField(b, 1, msg)
+}
+
+// appendError encodes a Mysqlx.Error.
+func appendError(b []byte, err *sqlerror.SQLError, fatal bool) []byte {
+	if fatal {
+		b = appendVarintField(b, 1, 1)
+	}
+	b = appendVarintField(b, 2, uint64(err.Number()))
+	b = appendStringField(b, 3, err.Message)
+	return appendStringField(b, 4, err.SQLState())
+}
+
+// appendCapabilities encodes a Mysqlx.Connection.Capabilities.
+func appendCapabilities(b []byte, capabilities []capability) []byte {
+	for _, c := range capabilities {
+		field := appendStringField(nil, 1, c.name)
+		field = appendBytesField(field, 2, appendAny(nil, c.value))
+		b = appendBytesField(b, 1, field)
+	}
+	return b
+}
+
+// appendNotice encodes a Mysqlx.Notice.Frame.
+func appendNotice(b []byte, typ uint64, scope uint64, payload []byte) []byte {
+	b = appendVarintField(b, 1, typ)
+	b = appendVarintField(b, 2, scope)
+	return appendBytesField(b, 3, payload)
+}
+
+// appendWarning encodes a Mysqlx.Notice.Warning.
+func appendWarning(b []byte, level uint64, code uint32, msg string) []byte {
+	b = appendVarintField(b, 1, level)
+	b = appendVarintField(b, 2, uint64(code))
+	return appendStringField(b, 3, msg)
+}
+
+// appendSessionStateChanged encodes a Mysqlx.Notice.SessionStateChanged.
+func appendSessionStateChanged(b []byte, param uint64, values ...*scalar) []byte {
+	b = appendVarintField(b, 1, param)
+	for _, v := range values {
+		b = appendBytesField(b, 2, appendScalar(nil, v))
+	}
+	return b
+}
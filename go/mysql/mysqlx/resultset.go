@@ -0,0 +1,265 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/mdibaiee/vitess/go/mysql/datetime"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+)
+
+// columnType returns the Mysqlx.Resultset.ColumnMetaData type and content
+// type of a field.
+func columnType(typ querypb.Type) (uint64, uint64) {
+	switch {
+	case sqltypes.IsSigned(typ):
+		return ColumnTypeSint, 0
+	case sqltypes.IsUnsigned(typ), typ == sqltypes.Year:
+		return ColumnTypeUint, 0
+	}
+	switch typ {
+	case sqltypes.Float32:
+		return ColumnTypeFloat, 0
+	case sqltypes.Float64:
+		return ColumnTypeDouble, 0
+	case sqltypes.Decimal:
+		return ColumnTypeDecimal, 0
+	case sqltypes.Date, sqltypes.Datetime, sqltypes.Timestamp:
+		return ColumnTypeDatetime, 0
+	case sqltypes.Time:
+		return ColumnTypeTime, 0
+	case sqltypes.Enum:
+		return ColumnTypeEnum, 0
+	case sqltypes.Set:
+		return ColumnTypeSet, 0
+	case sqltypes.Bit:
+		return ColumnTypeBit, 0
+	case sqltypes.TypeJSON:
+		return ColumnTypeBytes, ContentTypeJSON
+	case sqltypes.Geometry:
+		return ColumnTypeBytes, ContentTypeGeometry
+	}
+	return ColumnTypeBytes, 0
+}
+
+// columnFlagsOfMySQLFlags are the column flags of the flags of the classic protocol.
+var columnFlagsOfMySQLFlags = []struct {
+	mysqlFlag querypb.MySqlFlag
+	flag      uint64
+}{
+	{querypb.MySqlFlag_NOT_NULL_FLAG, ColumnFlagNotNull},
+	{querypb.MySqlFlag_PRI_KEY_FLAG, ColumnFlagPrimaryKey},
+	{querypb.MySqlFlag_UNIQUE_KEY_FLAG, ColumnFlagUniqueKey},
+	{querypb.MySqlFlag_MULTIPLE_KEY_FLAG, ColumnFlagMultipleKey},
+	{querypb.MySqlFlag_AUTO_INCREMENT_FLAG, ColumnFlagAutoIncrement},
+}
+
+// columnFlags returns the Mysqlx.Resultset.ColumnMetaData flags of a field.
+func columnFlags(field *querypb.Field) uint64 {
+	var flags uint64
+	switch {
+	case field.Flags&uint32(querypb.MySqlFlag_ZEROFILL_FLAG) != 0 && sqltypes.IsUnsigned(field.Type):
+		flags |= ColumnFlagUintZerofill
+	case field.Type == sqltypes.Char || field.Type == sqltypes.Binary:
+		flags |= ColumnFlagBytesRightpad
+	}
+	for _, f := range columnFlagsOfMySQLFlags {
+		if field.Flags&uint32(f.mysqlFlag) != 0 {
+			flags |= f.flag
+		}
+	}
+	return flags
+}
+
+// appendColumnMetaData encodes a field as a Mysqlx.Resultset.ColumnMetaData.
+func appendColumnMetaData(b []byte, field *querypb.Field) []byte {
+	typ, contentType := columnType(field.Type)
+	b = appendVarintField(b, 1, typ)
+	b = appendStringField(b, 2, field.Name)
+	b = appendStringField(b, 3, field.OrgName)
+	b = appendStringField(b, 4, field.Table)
+	b = appendStringField(b, 5, field.OrgTable)
+	b = appendStringField(b, 6, field.Database)
+	b = appendStringField(b, 7, "def")
+	if typ == ColumnTypeBytes || typ == ColumnTypeEnum || typ == ColumnTypeSet {
+		b = appendVarintField(b, 8, uint64(field.Charset))
+	}
+	if typ == ColumnTypeDouble || typ == ColumnTypeFloat || typ == ColumnTypeDecimal || typ == ColumnTypeDatetime || typ == ColumnTypeTime {
+		b = appendVarintField(b, 9, uint64(field.Decimals))
+	}
+	b = appendVarintField(b, 10, uint64(field.ColumnLength))
+	if flags := columnFlags(field); flags != 0 {
+		b = appendVarintField(b, 11, flags)
+	}
+	if contentType != 0 {
+		b = appendVarintField(b, 12, contentType)
+	}
+	return b
+}
+
+// appendRow encodes a row as a Mysqlx.Resultset.Row.
+func appendRow(b []byte, fields []*querypb.Field, row []sqltypes.Value) ([]byte, error) {
+	for i, v := range row {
+		value, err := encodeValue(fields[i].Type, v)
+		if err != nil {
+			return nil, err
+		}
+		b = appendBytesField(b, 1, value)
+	}
+	return b, nil
+}
+
+// encodeValue encodes a value for its column type. The NULL values are empty.
+func encodeValue(typ querypb.Type, v sqltypes.Value) ([]byte, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	raw := v.Raw()
+	invalid := func() ([]byte, error) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid %v value: %q", typ, raw)
+	}
+
+	columnType, _ := columnType(typ)
+	switch columnType {
+	case ColumnTypeSint:
+		i, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return invalid()
+		}
+		return protowire.AppendVarint(nil, protowire.EncodeZigZag(i)), nil
+	case ColumnTypeUint:
+		u, err := strconv.ParseUint(string(raw), 10, 64)
+		if err != nil {
+			return invalid()
+		}
+		return protowire.AppendVarint(nil, u), nil
+	case ColumnTypeDouble:
+		f, err := strconv.ParseFloat(string(raw), 64)
+		if err != nil {
+			return invalid()
+		}
+		return binary.LittleEndian.AppendUint64(nil, math.Float64bits(f)), nil
+	case ColumnTypeFloat:
+		f, err := strconv.ParseFloat(string(raw), 32)
+		if err != nil {
+			return invalid()
+		}
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(float32(f))), nil
+	case ColumnTypeDecimal:
+		b, ok := encodeDecimal(string(raw))
+		if !ok {
+			return invalid()
+		}
+		return b, nil
+	case ColumnTypeDatetime:
+		if typ == sqltypes.Date {
+			d, ok := datetime.ParseDate(string(raw))
+			if !ok {
+				return invalid()
+			}
+			return appendVarints(nil, d.Year(), d.Month(), d.Day()), nil
+		}
+		dt, _, ok := datetime.ParseDateTime(string(raw), -1)
+		if !ok {
+			return invalid()
+		}
+		return appendVarints(nil, dt.Date.Year(), dt.Date.Month(), dt.Date.Day(),
+			dt.Time.Hour(), dt.Time.Minute(), dt.Time.Second(), dt.Time.Nanosecond()/1000), nil
+	case ColumnTypeTime:
+		t, _, state := datetime.ParseTime(string(raw), -1)
+		if state != datetime.TimeOK {
+			return invalid()
+		}
+		var sign byte
+		if t.Neg() {
+			sign = 1
+		}
+		return appendVarints([]byte{sign}, t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/1000), nil
+	case ColumnTypeSet:
+		if len(raw) == 0 {
+			// the empty set
+			return []byte{1}, nil
+		}
+		var b []byte
+		for _, item := range strings.Split(string(raw), ",") {
+			b = protowire.AppendString(b, item)
+		}
+		return b, nil
+	case ColumnTypeBit:
+		var u uint64
+		for _, c := range raw {
+			u = u<<8 | uint64(c)
+		}
+		return protowire.AppendVarint(nil, u), nil
+	}
+	// the strings are terminated by a 0x00 byte, to tell them apart from NULL
+	return append(append(make([]byte, 0, len(raw)+1), raw...), 0), nil
+}
+
+func appendVarints(b []byte, values ...int) []byte {
+	for _, v := range values {
+		b = protowire.AppendVarint(b, uint64(v))
+	}
+	return b
+}
+
+// encodeDecimal encodes a decimal as its scale, followed by its digits in BCD
+// and its sign in the last nibble.
+func encodeDecimal(s string) ([]byte, bool) {
+	sign := byte(0xc)
+	if strings.HasPrefix(s, "-") {
+		sign = 0xd
+		s = s[1:]
+	}
+	var scale int
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		scale = len(s) - dot - 1
+		s = s[:dot] + s[dot+1:]
+	}
+	if s == "" {
+		return nil, false
+	}
+
+	nibbles := make([]byte, 0, len(s)+2)
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return nil, false
+		}
+		nibbles = append(nibbles, s[i]-'0')
+	}
+	nibbles = append(nibbles, sign)
+	if len(nibbles)%2 != 0 {
+		nibbles = append(nibbles, 0)
+	}
+
+	b := make([]byte, 1, 1+len(nibbles)/2)
+	b[0] = byte(scale)
+	for i := 0; i < len(nibbles); i += 2 {
+		b = append(b, nibbles[i]<<4|nibbles[i+1])
+	}
+	return b, true
+}
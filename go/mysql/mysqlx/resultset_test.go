@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/sqltypes"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+func TestEncodeValue(t *testing.T) {
+	tests := []struct {
+		typ  querypb.Type
+		v    sqltypes.Value
+		want []byte
+	}{
+		{sqltypes.Int64, sqltypes.NewInt64(-2), []byte{0x03}},
+		{sqltypes.Int32, sqltypes.NewInt32(300), []byte{0xd8, 0x04}},
+		{sqltypes.Uint64, sqltypes.NewUint64(300), []byte{0xac, 0x02}},
+		{sqltypes.Year, sqltypes.MakeTrusted(sqltypes.Year, []byte("2024")), []byte{0xe8, 0x0f}},
+		{sqltypes.Float64, sqltypes.NewFloat64(1), []byte{0, 0, 0, 0, 0, 0, 0xf0, 0x3f}},
+		{sqltypes.Float32, sqltypes.MakeTrusted(sqltypes.Float32, []byte("1")), []byte{0, 0, 0x80, 0x3f}},
+		{sqltypes.Decimal, sqltypes.NewDecimal("-12.34"), []byte{0x02, 0x12, 0x34, 0xd0}},
+		{sqltypes.Decimal, sqltypes.NewDecimal("1.5"), []byte{0x01, 0x15, 0xc0}},
+		{sqltypes.Date, sqltypes.NewDate("2024-02-29"), []byte{0xe8, 0x0f, 0x02, 0x1d}},
+		{sqltypes.Datetime, sqltypes.NewDatetime("2024-02-29 10:20:30.5"), []byte{0xe8, 0x0f, 0x02, 0x1d, 0x0a, 0x14, 0x1e, 0xa0, 0xc2, 0x1e}},
+		{sqltypes.Time, sqltypes.NewTime("-01:02:03"), []byte{0x01, 0x01, 0x02, 0x03, 0x00}},
+		{sqltypes.Set, sqltypes.MakeTrusted(sqltypes.Set, []byte("a,bc")), []byte{0x01, 'a', 0x02, 'b', 'c'}},
+		{sqltypes.Set, sqltypes.MakeTrusted(sqltypes.Set, nil), []byte{0x01}},
+		{sqltypes.Bit, sqltypes.MakeTrusted(sqltypes.Bit, []byte{0x01, 0x02}), []byte{0x82, 0x02}},
+		{sqltypes.VarChar, sqltypes.NewVarChar("ab"), []byte{'a', 'b', 0}},
+		{sqltypes.VarChar, sqltypes.NewVarChar(""), []byte{0}},
+		{sqltypes.TypeJSON, sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte("{}")), []byte{'{', '}', 0}},
+		{sqltypes.VarChar, sqltypes.NULL, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ.String()+" "+tt.v.String(), func(t *testing.T) {
+			got, err := encodeValue(tt.typ, tt.v)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := encodeValue(sqltypes.Int64, sqltypes.NewVarChar("a"))
+	assert.ErrorContains(t, err, "invalid INT64 value")
+	_, err = encodeValue(sqltypes.Decimal, sqltypes.NewVarChar("1.a"))
+	assert.ErrorContains(t, err, "invalid DECIMAL value")
+}
+
+func TestColumnFlags(t *testing.T) {
+	field := &querypb.Field{
+		Type:  sqltypes.Uint32,
+		Flags: uint32(querypb.MySqlFlag_ZEROFILL_FLAG | querypb.MySqlFlag_NOT_NULL_FLAG | querypb.MySqlFlag_PRI_KEY_FLAG),
+	}
+	assert.Equal(t, uint64(ColumnFlagUintZerofill|ColumnFlagNotNull|ColumnFlagPrimaryKey), columnFlags(field))
+
+	field = &querypb.Field{Type: sqltypes.Char, Flags: uint32(querypb.MySqlFlag_AUTO_INCREMENT_FLAG)}
+	assert.Equal(t, uint64(ColumnFlagBytesRightpad|ColumnFlagAutoIncrement), columnFlags(field))
+}
@@ -0,0 +1,746 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/mdibaiee/vitess/go/mysql"
+	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
+	"github.com/mdibaiee/vitess/go/netutil"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/stats"
+	"github.com/mdibaiee/vitess/go/tb"
+	"github.com/mdibaiee/vitess/go/vt/log"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+// Authentication mechanisms of Mysqlx.Session.AuthenticateStart.
+const (
+	authMySQL41 = "MYSQL41"
+	authPlain   = "PLAIN"
+)
+
+var (
+	connCount  = stats.NewGauge("MysqlxServerConnCount", "Active X Protocol server connections")
+	connAccept = stats.NewCounter("MysqlxServerConnAccepted", "Connections accepted by X Protocol server")
+)
+
+// Handler is the interface used by the server to execute the statements of
+// the clients. SQL statements are executed as they are, the CRUD messages of
+// the document store are translated to SQL first.
+type Handler interface {
+	// NewConnection is called when a connection is authenticated.
+	NewConnection(c *Conn)
+
+	// ConnectionClosed is called when a connection that was authenticated
+	// is closed.
+	ConnectionClosed(c *Conn)
+
+	// ResetSession is called when the client resets its session, which
+	// must be cleaned up like for a new connection.
+	ResetSession(c *Conn)
+
+	// Execute executes a statement. The arguments of its ? placeholders are
+	// bound as v1, v2...
+	Execute(c *Conn, query string, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error)
+
+	// Warnings returns the warnings of the last statement.
+	Warnings(c *Conn) []*querypb.QueryWarning
+
+	// CurrentSchema returns the default schema of the session, which the
+	// admin commands use.
+	CurrentSchema(c *Conn) string
+}
+
+// Listener is the X Protocol server. It accepts connections, authenticates
+// the clients with an AuthServer, and sends their statements to a Handler.
+type Listener struct {
+	authServer mysql.AuthServer
+	handler    Handler
+	listener   net.Listener
+
+	// TLSConfig is the server TLS config. If set, the clients can upgrade
+	// their connections to TLS.
+	TLSConfig atomic.Pointer[tls.Config]
+
+	// AllowClearTextWithoutTLS lets the clients authenticate with the
+	// PLAIN mechanism over connections that do not use TLS.
+	AllowClearTextWithoutTLS atomic.Bool
+
+	// RequireSecureTransport rejects the authentication of the clients
+	// that did not upgrade their connection to TLS.
+	RequireSecureTransport atomic.Bool
+
+	// MaxMessageSize is the size of the largest message the clients can send.
+	MaxMessageSize int
+
+	connReadTimeout  time.Duration
+	connWriteTimeout time.Duration
+
+	connectionID atomic.Uint32
+	shutdown     atomic.Bool
+
+	// The _id of the documents inserted without one are made of the start
+	// time of the listener and of a serial number, like the X Plugin does.
+	documentIDStart  uint32
+	documentIDSerial atomic.Uint64
+}
+
+// NewListener creates a new Listener.
+func NewListener(protocol, address string, authServer mysql.AuthServer, handler Handler, connReadTimeout, connWriteTimeout time.Duration) (*Listener, error) {
+	listener, err := net.Listen(protocol, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromListener(listener, authServer, handler, connReadTimeout, connWriteTimeout), nil
+}
+
+// NewFromListener creates a new Listener from an existing net.Listener.
+func NewFromListener(listener net.Listener, authServer mysql.AuthServer, handler Handler, connReadTimeout, connWriteTimeout time.Duration) *Listener {
+	return &Listener{
+		authServer:       authServer,
+		handler:          handler,
+		listener:         listener,
+		MaxMessageSize:   defaultMaxMessageSize,
+		connReadTimeout:  connReadTimeout,
+		connWriteTimeout: connWriteTimeout,
+		documentIDStart:  uint32(time.Now().Unix()),
+	}
+}
+
+// Addr returns the listener address.
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Accept runs an accept loop until the listener is closed.
+func (l *Listener) Accept() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			// Close() was probably called.
+			return
+		}
+		connAccept.Add(1)
+		go l.handle(conn, l.connectionID.Add(1))
+	}
+}
+
+// Close stops the listener, which prevents accept of any new connections.
+// Existing connections won't be closed.
+func (l *Listener) Close() {
+	l.listener.Close()
+}
+
+// Shutdown closes the listener when the server shuts down. Existing
+// connections won't be closed.
+func (l *Listener) Shutdown() {
+	if l.shutdown.CompareAndSwap(false, true) {
+		l.Close()
+	}
+}
+
+func (l *Listener) handle(conn net.Conn, connectionID uint32) {
+	if l.connReadTimeout != 0 || l.connWriteTimeout != 0 {
+		conn = netutil.NewConnWithTimeouts(conn, l.connReadTimeout, l.connWriteTimeout)
+	}
+	c := newConn(conn, connectionID)
+
+	// Catch panics, and close the connection in any case.
+	defer func() {
+		if x := recover(); x != nil {
+			log.Errorf("mysqlx_server caught panic:\n%v\n%s", x, tb.Stack(4))
+		}
+		c.Close()
+	}()
+
+	connCount.Add(1)
+	defer connCount.Add(-1)
+
+	authenticated := false
+	defer func() {
+		if authenticated {
+			l.handler.ConnectionClosed(c)
+		}
+	}()
+
+	for {
+		if !authenticated {
+			ok, err := l.authenticate(c)
+			if err != nil {
+				if err != io.EOF {
+					log.Infof("Cannot authenticate %s: %v", c, err)
+				}
+				return
+			}
+			if !ok {
+				return
+			}
+			l.handler.NewConnection(c)
+			authenticated = true
+		}
+
+		typ, data, err := c.readMessage(l.MaxMessageSize)
+		if err != nil {
+			if err != io.EOF {
+				log.Infof("Error reading message from %s: %v", c, err)
+			}
+			return
+		}
+		reauthenticate, ok := l.handleMessage(c, typ, data)
+		if !ok || c.IsMarkedForClose() {
+			return
+		}
+		if reauthenticate {
+			authenticated = false
+			l.handler.ConnectionClosed(c)
+		}
+	}
+}
+
+// authenticate reads the messages of the client until it is authenticated.
+// It returns false if the connection must be closed.
+func (l *Listener) authenticate(c *Conn) (bool, error) {
+	for {
+		typ, data, err := c.readMessage(l.MaxMessageSize)
+		if err != nil {
+			return false, err
+		}
+		switch typ {
+		case ClientConCapabilitiesGet:
+			if err := l.writeCapabilities(c); err != nil {
+				return false, err
+			}
+		case ClientConCapabilitiesSet:
+			if err := l.setCapabilities(c, data); err != nil {
+				return false, err
+			}
+		case ClientSessAuthenticateStart:
+			ok, err := l.handleAuthentication(c, data)
+			if err != nil || ok {
+				return ok, err
+			}
+		case ClientConClose:
+			return false, c.writeOk("bye!")
+		default:
+			if err := c.writeError(sqlerror.NewSQLError(sqlerror.ERUnknownComError, sqlerror.SSNetError, "Unexpected message received"), false); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+func (l *Listener) authMechanisms(c *Conn) []string {
+	mechanisms := []string{authMySQL41}
+	if c.TLSEnabled() || l.AllowClearTextWithoutTLS.Load() {
+		mechanisms = append(mechanisms, authPlain)
+	}
+	return mechanisms
+}
+
+func (l *Listener) writeCapabilities(c *Conn) error {
+	var mechanisms []*anyValue
+	for _, m := range l.authMechanisms(c) {
+		mechanisms = append(mechanisms, scalarAny(stringScalar(m)))
+	}
+	capabilities := []capability{
+		{name: "authentication.mechanisms", value: &anyValue{typ: anyArray, array: mechanisms}},
+		{name: "doc.formats", value: scalarAny(stringScalar("text"))},
+		{name: "node_type", value: scalarAny(stringScalar("mysql"))},
+		{name: "client.pwd_expire_ok", value: scalarAny(boolScalar(false))},
+	}
+	if l.TLSConfig.Load() != nil {
+		capabilities = append(capabilities, capability{name: "tls", value: scalarAny(boolScalar(c.TLSEnabled()))})
+	}
+	if err := c.writeMessage(ServerConnCapabilities, appendCapabilities(nil, capabilities)); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// setCapabilities handles a Mysqlx.Connection.CapabilitiesSet, which
+// either sets all its capabilities or none of them.
+func (l *Listener) setCapabilities(c *Conn, data []byte) error {
+	capabilities, err := parseCapabilitiesSet(data)
+	if err != nil {
+		return c.writeError(err, false)
+	}
+
+	var tlsConfig *tls.Config
+	var attributes map[string]string
+	for _, capability := range capabilities {
+		switch capability.name {
+		case "tls":
+			enable, ok := capability.value.boolean()
+			if tlsConfig = l.TLSConfig.Load(); !ok || !enable || tlsConfig == nil || c.TLSEnabled() {
+				return c.writeError(sqlerror.NewSQLError(ERXCapabilitiesPrepareFailed, sqlerror.SSUnknownSQLState, "Capability prepare failed for 'tls'"), false)
+			}
+		case "session_connect_attrs":
+			if capability.value.typ != anyObject {
+				return c.writeError(sqlerror.NewSQLError(ERXCapabilitiesPrepareFailed, sqlerror.SSUnknownSQLState, "Capability prepare failed for 'session_connect_attrs'"), false)
+			}
+			attributes = make(map[string]string, len(capability.value.object))
+			for _, f := range capability.value.object {
+				attributes[f.key], _ = f.value.str()
+			}
+		case "client.pwd_expire_ok", "client.interactive":
+			if _, ok := capability.value.boolean(); !ok {
+				return c.writeError(sqlerror.NewSQLError(ERXCapabilitiesPrepareFailed, sqlerror.SSUnknownSQLState, "Capability prepare failed for '%s'", capability.name), false)
+			}
+		default:
+			return c.writeError(sqlerror.NewSQLError(ERXCapabilityNotFound, sqlerror.SSUnknownSQLState, "Capability '%s' doesn't exist", capability.name), false)
+		}
+	}
+
+	if attributes != nil {
+		c.Attributes = attributes
+	}
+	if err := c.writeOk(""); err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		return c.startTLS(tlsConfig)
+	}
+	return nil
+}
+
+// handleAuthentication runs the authentication started by a
+// Mysqlx.Session.AuthenticateStart. It returns true once the client is
+// authenticated, and an error if the connection must be closed.
+func (l *Listener) handleAuthentication(c *Conn, data []byte) (bool, error) {
+	start, err := parseAuthenticateStart(data)
+	if err != nil {
+		return false, c.writeError(err, false)
+	}
+	if l.RequireSecureTransport.Load() && !c.TLSEnabled() {
+		return false, c.writeError(sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Secure transport required. To log in you must use TCP+SSL."), true)
+	}
+
+	var schema, user string
+	var userData mysql.Getter
+	switch start.mechName {
+	case authMySQL41:
+		salt, err := newSalt()
+		if err != nil {
+			return false, err
+		}
+		if err := c.writeMessage(ServerSessAuthenticateContinue, appendBytesField(nil, 1, salt)); err != nil {
+			return false, err
+		}
+		if err := c.flush(); err != nil {
+			return false, err
+		}
+		typ, data, err := c.readMessage(l.MaxMessageSize)
+		if err != nil {
+			return false, err
+		}
+		if typ != ClientSessAuthenticateContinue {
+			return false, c.writeError(sqlerror.NewSQLError(sqlerror.ERUnknownComError, sqlerror.SSNetError, "Unexpected message received"), true)
+		}
+		authData, err := parseAuthenticateContinue(data)
+		if err != nil {
+			return false, c.writeError(err, true)
+		}
+		var password []byte
+		schema, user, password = splitAuthData(authData)
+		userData, err = l.authenticateMySQL41(c, user, salt, password)
+		if err != nil {
+			return false, c.writeError(err, false)
+		}
+	case authPlain:
+		if !c.TLSEnabled() && !l.AllowClearTextWithoutTLS.Load() {
+			return false, c.writeError(sqlerror.NewSQLError(sqlerror.ERNotSupportedAuthMode, sqlerror.SSUnknownSQLState, "Invalid authentication method PLAIN"), false)
+		}
+		var password []byte
+		schema, user, password = splitAuthData(start.authData)
+		userData, err = l.authenticatePlain(c, user, string(password))
+		if err != nil {
+			return false, c.writeError(err, false)
+		}
+	default:
+		return false, c.writeError(sqlerror.NewSQLError(sqlerror.ERNotSupportedAuthMode, sqlerror.SSUnknownSQLState, "Invalid authentication method %s", start.mechName), false)
+	}
+
+	c.User = user
+	c.UserData = userData
+	c.SchemaName = schema
+	notice := appendSessionStateChanged(nil, StateClientIDAssigned, uintScalar(uint64(c.ConnectionID)))
+	if err := c.writeNotice(NoticeSessionStateChanged, NoticeScopeLocal, notice); err != nil {
+		return false, err
+	}
+	if err := c.writeMessage(ServerSessAuthenticateOk, nil); err != nil {
+		return false, err
+	}
+	return true, c.flush()
+}
+
+// splitAuthData splits the "schema\0user\0password" auth data of the clients.
+func splitAuthData(data []byte) (string, string, []byte) {
+	parts := bytes.SplitN(data, []byte{0}, 3)
+	for len(parts) < 3 {
+		parts = append(parts, nil)
+	}
+	return string(parts[0]), string(parts[1]), parts[2]
+}
+
+func accessDenied(user string) error {
+	return sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+}
+
+// authMethod returns the method of the AuthServer with the given name that handles the user.
+func (l *Listener) authMethod(c *mysql.Conn, user string, name mysql.AuthMethodDescription) mysql.AuthMethod {
+	for _, method := range l.authServer.AuthMethods() {
+		if method.Name() == name && method.HandleUser(c, user) {
+			return method
+		}
+	}
+	return nil
+}
+
+// authenticateMySQL41 checks the answer of a client to the MYSQL41 challenge,
+// which is the mysql_native_password hash of its password as a hex string
+// prefixed with '*', or nothing for an empty password.
+func (l *Listener) authenticateMySQL41(c *Conn, user string, salt, password []byte) (mysql.Getter, error) {
+	authConn := mysql.NewAuthConn(c.conn, c.ConnectionID)
+	method := l.authMethod(authConn, user, mysql.MysqlNativePassword)
+	if method == nil {
+		return nil, accessDenied(user)
+	}
+	var scramble []byte
+	if len(password) > 0 {
+		if password[0] != '*' {
+			return nil, accessDenied(user)
+		}
+		var err error
+		if scramble, err = hex.DecodeString(string(password[1:])); err != nil {
+			return nil, accessDenied(user)
+		}
+	}
+	return method.HandleAuthPluginData(authConn, user, append(salt, 0), scramble, c.RemoteAddr())
+}
+
+// authenticatePlain checks the clear text password of a client, with the
+// clear text method of the AuthServer, or by scrambling it for its
+// mysql_native_password method.
+func (l *Listener) authenticatePlain(c *Conn, user string, password string) (mysql.Getter, error) {
+	authConn := mysql.NewAuthConn(c.conn, c.ConnectionID)
+	if method := l.authMethod(authConn, user, mysql.MysqlClearPassword); method != nil {
+		return method.HandleAuthPluginData(authConn, user, nil, []byte(password+"\x00"), c.RemoteAddr())
+	}
+	if method := l.authMethod(authConn, user, mysql.MysqlNativePassword); method != nil {
+		salt, err := newSalt()
+		if err != nil {
+			return nil, err
+		}
+		var scramble []byte
+		if password != "" {
+			scramble = mysql.ScrambleMysqlNativePassword(salt, []byte(password))
+		}
+		return method.HandleAuthPluginData(authConn, user, append(salt, 0), scramble, c.RemoteAddr())
+	}
+	return nil, accessDenied(user)
+}
+
+// newSalt returns a salt of 20 printable bytes, like the classic protocol.
+func newSalt() ([]byte, error) {
+	salt := make([]byte, 20)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	for i := range salt {
+		salt[i] &= 0x7f
+		if salt[i] == 0 || salt[i] == '$' {
+			salt[i]++
+		}
+	}
+	return salt, nil
+}
+
+// handleMessage handles a message of an authenticated client. It returns
+// false if the connection must be closed, and reauthenticate if the client
+// must authenticate again.
+func (l *Listener) handleMessage(c *Conn, typ byte, data []byte) (reauthenticate bool, ok bool) {
+	var err error
+	switch typ {
+	case ClientConCapabilitiesGet:
+		err = l.writeCapabilities(c)
+	case ClientConClose:
+		_ = c.writeOk("bye!")
+		return false, false
+	case ClientSessClose:
+		_ = c.writeOk("bye!")
+		return false, false
+	case ClientSessReset:
+		var keepOpen bool
+		if keepOpen, err = parseSessReset(data); err == nil {
+			l.handler.ResetSession(c)
+			c.expect = nil
+			c.warnings = true
+			err = c.writeOk("")
+		}
+		return !keepOpen, err == nil
+	case ClientExpectOpen:
+		err = l.handleExpectOpen(c, data)
+	case ClientExpectClose:
+		err = l.handleExpectClose(c)
+	case ClientSQLStmtExecute, ClientCrudFind, ClientCrudInsert, ClientCrudUpdate, ClientCrudDelete:
+		err = l.handleStatement(c, typ, data)
+	default:
+		err = c.writeError(sqlerror.NewSQLError(sqlerror.ERUnknownComError, sqlerror.SSNetError, "Unexpected message received"), false)
+	}
+	if err != nil {
+		log.Infof("Error writing to %s: %v", c, err)
+		return false, false
+	}
+	return false, true
+}
+
+// expectFailed returns the error of the messages of a failed no_error block.
+func (c *Conn) expectFailed() error {
+	if len(c.expect) > 0 && c.expect[len(c.expect)-1].failed {
+		return sqlerror.NewSQLError(ERXExpectFailed, sqlerror.SSUnknownSQLState, "Expectation failed: no_error")
+	}
+	return nil
+}
+
+// writeStatementError sends the error of a message, which fails the current no_error block.
+func (c *Conn) writeStatementError(err error) error {
+	if len(c.expect) > 0 && c.expect[len(c.expect)-1].noError {
+		c.expect[len(c.expect)-1].failed = true
+	}
+	return c.writeError(err, false)
+}
+
+func (l *Listener) handleExpectOpen(c *Conn, data []byte) error {
+	msg, err := parseExpectOpen(data)
+	if err != nil {
+		return c.writeError(err, false)
+	}
+	var block expectBlock
+	if len(c.expect) > 0 {
+		parent := c.expect[len(c.expect)-1]
+		block.failed = parent.failed
+		if msg.op == expectCtxCopyPrev {
+			block.noError = parent.noError
+		}
+	}
+	for _, cond := range msg.conditions {
+		switch cond.key {
+		case expectNoError:
+			block.noError = cond.op == expectOpSet
+		case 2, 3:
+			// field_exist and docid_generated, the fields that the clients
+			// check for exist and the document ids are generated here.
+		default:
+			return c.writeError(sqlerror.NewSQLError(ERXExpectBadCondition, sqlerror.SSUnknownSQLState, "Unknown condition key"), false)
+		}
+	}
+	c.expect = append(c.expect, block)
+	if block.failed {
+		return c.writeError(c.expectFailed(), false)
+	}
+	return c.writeOk("")
+}
+
+func (l *Listener) handleExpectClose(c *Conn) error {
+	if len(c.expect) == 0 {
+		return c.writeError(sqlerror.NewSQLError(ERXExpectNotOpen, sqlerror.SSUnknownSQLState, "Expect block currently not open"), false)
+	}
+	err := c.expectFailed()
+	c.expect = c.expect[:len(c.expect)-1]
+	if err != nil {
+		return c.writeError(err, false)
+	}
+	return c.writeOk("")
+}
+
+// handleStatement executes a SQL statement, an admin command or a CRUD
+// message, and sends its result.
+func (l *Listener) handleStatement(c *Conn, typ byte, data []byte) error {
+	if err := c.expectFailed(); err != nil {
+		return c.writeError(err, false)
+	}
+
+	var result *sqltypes.Result
+	var documentIDs []string
+	var err error
+	switch typ {
+	case ClientSQLStmtExecute:
+		var msg *stmtExecute
+		if msg, err = parseStmtExecute(data); err != nil {
+			break
+		}
+		switch msg.namespace {
+		case "sql", "":
+			var bindVars map[string]*querypb.BindVariable
+			if bindVars, err = stmtBindVars(msg.args); err == nil {
+				result, err = l.handler.Execute(c, msg.stmt, bindVars)
+			}
+		case "mysqlx", "xplugin":
+			result, err = l.adminCommand(c, msg.stmt, msg.args)
+		default:
+			err = newInvalidArgument("Unknown namespace %s", msg.namespace)
+		}
+	case ClientCrudFind:
+		var msg *crudFind
+		var query string
+		if msg, err = parseCrudFind(data); err == nil {
+			if query, err = findSQL(msg); err == nil {
+				result, err = l.handler.Execute(c, query, nil)
+			}
+		}
+	case ClientCrudInsert:
+		var msg *crudInsert
+		var query string
+		if msg, err = parseCrudInsert(data); err == nil {
+			if query, documentIDs, err = insertSQL(msg, l.newDocumentID); err == nil {
+				result, err = l.handler.Execute(c, query, nil)
+			}
+		}
+	case ClientCrudUpdate:
+		var msg *crudUpdate
+		var query string
+		if msg, err = parseCrudUpdate(data); err == nil {
+			if query, err = updateSQL(msg); err == nil {
+				result, err = l.handler.Execute(c, query, nil)
+			}
+		}
+	case ClientCrudDelete:
+		var msg *crudDelete
+		var query string
+		if msg, err = parseCrudDelete(data); err == nil {
+			if query, err = deleteSQL(msg); err == nil {
+				result, err = l.handler.Execute(c, query, nil)
+			}
+		}
+	}
+	if err != nil {
+		return c.writeStatementError(err)
+	}
+	return l.writeResult(c, result, documentIDs)
+}
+
+// stmtBindVars returns the bind variables of the arguments of a
+// Mysqlx.Sql.StmtExecute, which are all scalars.
+func stmtBindVars(args []*anyValue) (map[string]*querypb.BindVariable, error) {
+	bindVars := make(map[string]*querypb.BindVariable, len(args))
+	for i, arg := range args {
+		if arg.typ != anyScalar {
+			return nil, newInvalidArgument("Invalid type of argument %d, expected a scalar", i+1)
+		}
+		var bv *querypb.BindVariable
+		switch s := arg.scalar; s.typ {
+		case scalarSint:
+			bv = sqltypes.Int64BindVariable(s.i)
+		case scalarUint, scalarBool:
+			bv = sqltypes.Uint64BindVariable(s.u)
+		case scalarDouble, scalarFloat:
+			bv = sqltypes.Float64BindVariable(s.f)
+		case scalarOctets:
+			bv = sqltypes.BytesBindVariable(s.b)
+		case scalarString:
+			bv = sqltypes.StringBindVariable(string(s.b))
+		default:
+			bv = sqltypes.NullBindVariable
+		}
+		bindVars["v"+strconv.Itoa(i+1)] = bv
+	}
+	return bindVars, nil
+}
+
+// newDocumentID returns a new _id for a document: the 4 hex digits of a
+// prefix, which is always 0 here, followed by the 8 hex digits of the start
+// time of the listener and the 16 hex digits of a serial number.
+func (l *Listener) newDocumentID() string {
+	return fmt.Sprintf("%04x%08x%016x", 0, l.documentIDStart, l.documentIDSerial.Add(1))
+}
+
+// writeResult sends the result of a statement: its result set if it has one,
+// the notices of its warnings and of the state it changed, and the final
+// Mysqlx.Sql.StmtExecuteOk.
+func (l *Listener) writeResult(c *Conn, result *sqltypes.Result, documentIDs []string) error {
+	if result == nil {
+		result = &sqltypes.Result{}
+	}
+	if len(result.Fields) > 0 {
+		// encode the rows first, the result set cannot be interrupted by an error
+		rows := make([][]byte, 0, len(result.Rows))
+		for _, values := range result.Rows {
+			row, err := appendRow(nil, result.Fields, values)
+			if err != nil {
+				return c.writeStatementError(err)
+			}
+			rows = append(rows, row)
+		}
+		for _, field := range result.Fields {
+			if err := c.writeMessage(ServerResultsetColumnMetaData, appendColumnMetaData(nil, field)); err != nil {
+				return err
+			}
+		}
+		for _, row := range rows {
+			if err := c.writeMessage(ServerResultsetRow, row); err != nil {
+				return err
+			}
+		}
+		if err := c.writeMessage(ServerResultsetFetchDone, nil); err != nil {
+			return err
+		}
+	}
+
+	if c.warnings {
+		for _, warning := range l.handler.Warnings(c) {
+			if err := c.writeNotice(NoticeWarning, NoticeScopeLocal, appendWarning(nil, WarningLevelWarning, warning.Code, warning.Message)); err != nil {
+				return err
+			}
+		}
+	}
+	notices := [][]byte{appendSessionStateChanged(nil, StateRowsAffected, uintScalar(result.RowsAffected))}
+	if result.InsertID != 0 {
+		notices = append(notices, appendSessionStateChanged(nil, StateGeneratedInsertID, uintScalar(result.InsertID)))
+	}
+	if result.Info != "" {
+		notices = append(notices, appendSessionStateChanged(nil, StateProducedMessage, stringScalar(result.Info)))
+	}
+	if len(documentIDs) > 0 {
+		ids := make([]*scalar, 0, len(documentIDs))
+		for _, id := range documentIDs {
+			ids = append(ids, &scalar{typ: scalarOctets, b: []byte(id)})
+		}
+		notices = append(notices, appendSessionStateChanged(nil, StateGeneratedDocumentIDs, ids...))
+	}
+	for _, notice := range notices {
+		if err := c.writeNotice(NoticeSessionStateChanged, NoticeScopeLocal, notice); err != nil {
+			return err
+		}
+	}
+
+	if err := c.writeMessage(ServerSQLStmtExecuteOk, nil); err != nil {
+		return err
+	}
+	return c.flush()
+}
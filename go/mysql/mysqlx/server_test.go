@@ -0,0 +1,297 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/mdibaiee/vitess/go/mysql"
+	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+type testHandler struct {
+	mu       sync.Mutex
+	queries  []string
+	bindVars []map[string]*querypb.BindVariable
+	closed   int
+}
+
+func (th *testHandler) NewConnection(c *Conn) {}
+
+func (th *testHandler) ConnectionClosed(c *Conn) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.closed++
+}
+
+func (th *testHandler) ResetSession(c *Conn) {}
+
+func (th *testHandler) Execute(c *Conn, query string, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.queries = append(th.queries, query)
+	th.bindVars = append(th.bindVars, bindVars)
+	switch {
+	case query == "error":
+		return nil, sqlerror.NewSQLError(sqlerror.ERNoSuchTable, sqlerror.SSUnknownTable, "table t not found")
+	case strings.HasPrefix(query, "select"):
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|name", "int64|varchar"), "1|a", "2|null"), nil
+	}
+	return &sqltypes.Result{RowsAffected: 1}, nil
+}
+
+func (th *testHandler) Warnings(c *Conn) []*querypb.QueryWarning {
+	return []*querypb.QueryWarning{{Code: 1287, Message: "deprecated"}}
+}
+
+func (th *testHandler) CurrentSchema(c *Conn) string {
+	return c.SchemaName
+}
+
+func (th *testHandler) lastQuery() (string, map[string]*querypb.BindVariable) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	return th.queries[len(th.queries)-1], th.bindVars[len(th.bindVars)-1]
+}
+
+// testClient is a minimal X Protocol client.
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+func (tc *testClient) write(typ byte, payload []byte) {
+	header := binary.LittleEndian.AppendUint32(nil, uint32(len(payload)+1))
+	_, err := tc.conn.Write(append(append(header, typ), payload...))
+	require.NoError(tc.t, err)
+}
+
+func (tc *testClient) read() (byte, []byte) {
+	var header [4]byte
+	_, err := io.ReadFull(tc.conn, header[:])
+	require.NoError(tc.t, err)
+	data := make([]byte, binary.LittleEndian.Uint32(header[:]))
+	_, err = io.ReadFull(tc.conn, data)
+	require.NoError(tc.t, err)
+	return data[0], data[1:]
+}
+
+// readUntil reads the messages until one of the given type, and returns the
+// types of all the messages it read.
+func (tc *testClient) readUntil(typ byte) ([]byte, []byte) {
+	var types []byte
+	for {
+		t, data := tc.read()
+		types = append(types, t)
+		if t == typ || t == ServerError {
+			return types, data
+		}
+	}
+}
+
+// readError reads an error and returns its code.
+func (tc *testClient) readError() uint64 {
+	typ, data := tc.read()
+	require.EqualValues(tc.t, ServerError, typ)
+	var code uint64
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		if num == 2 {
+			code = v
+		}
+		return nil
+	})
+	require.NoError(tc.t, err)
+	return code
+}
+
+func (tc *testClient) authenticate(user, password string) byte {
+	tc.write(ClientSessAuthenticateStart, appendStringField(nil, 1, authMySQL41))
+	typ, data := tc.read()
+	require.EqualValues(tc.t, ServerSessAuthenticateContinue, typ)
+	var salt []byte
+	require.NoError(tc.t, decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		salt = b
+		return nil
+	}))
+	scramble := mysql.ScrambleMysqlNativePassword(salt, []byte(password))
+	tc.write(ClientSessAuthenticateContinue, appendStringField(nil, 1, "ks\x00"+user+"\x00*"+strings.ToUpper(hex.EncodeToString(scramble))))
+	types, _ := tc.readUntil(ServerSessAuthenticateOk)
+	return types[len(types)-1]
+}
+
+func (tc *testClient) stmtExecute(stmt string, args ...*scalar) {
+	payload := appendStringField(nil, 1, stmt)
+	for _, arg := range args {
+		payload = appendBytesField(payload, 2, appendAny(nil, scalarAny(arg)))
+	}
+	tc.write(ClientSQLStmtExecute, payload)
+}
+
+func newTestListener(t *testing.T) (*Listener, *testHandler) {
+	authServer := mysql.NewAuthServerStatic("", `{"user1": [{"Password": "password1"}]}`, 0)
+	handler := &testHandler{}
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, handler, 0, 0)
+	require.NoError(t, err)
+	go l.Accept()
+	t.Cleanup(l.Close)
+	return l, handler
+}
+
+func dial(t *testing.T, l *Listener) *testClient {
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return &testClient{t: t, conn: conn}
+}
+
+func TestServerAuthentication(t *testing.T) {
+	l, _ := newTestListener(t)
+	tc := dial(t, l)
+
+	tc.write(ClientConCapabilitiesGet, nil)
+	typ, data := tc.read()
+	require.EqualValues(t, ServerConnCapabilities, typ)
+	assert.Contains(t, string(data), "authentication.mechanisms")
+	assert.Contains(t, string(data), authMySQL41)
+	assert.NotContains(t, string(data), authPlain)
+
+	tc.write(ClientConCapabilitiesSet, appendBytesField(nil, 1, appendBytesField(nil, 1,
+		appendBytesField(appendStringField(nil, 1, "compression"), 2, appendAny(nil, scalarAny(boolScalar(true)))))))
+	assert.EqualValues(t, ERXCapabilityNotFound, tc.readError())
+
+	tc.stmtExecute("select 1")
+	assert.EqualValues(t, sqlerror.ERUnknownComError, tc.readError())
+
+	assert.EqualValues(t, ServerError, tc.authenticate("user1", "bad"))
+	assert.EqualValues(t, ServerSessAuthenticateOk, tc.authenticate("user1", "password1"))
+
+	tc.write(ClientConClose, nil)
+	typ, _ = tc.read()
+	assert.EqualValues(t, ServerOk, typ)
+}
+
+func TestServerStatements(t *testing.T) {
+	l, handler := newTestListener(t)
+	tc := dial(t, l)
+	require.EqualValues(t, ServerSessAuthenticateOk, tc.authenticate("user1", "password1"))
+
+	tc.stmtExecute("select ?", &scalar{typ: scalarSint, i: 5})
+	types, _ := tc.readUntil(ServerSQLStmtExecuteOk)
+	assert.Equal(t, []byte{
+		ServerResultsetColumnMetaData, ServerResultsetColumnMetaData,
+		ServerResultsetRow, ServerResultsetRow, ServerResultsetFetchDone,
+		ServerNotice, ServerNotice, ServerSQLStmtExecuteOk,
+	}, types)
+	query, bindVars := handler.lastQuery()
+	assert.Equal(t, "select ?", query)
+	assert.Equal(t, map[string]*querypb.BindVariable{"v1": sqltypes.Int64BindVariable(5)}, bindVars)
+
+	// the warnings notices can be disabled
+	notice := &anyValue{typ: anyObject, object: []objectField{{key: "notice", value: &anyValue{typ: anyArray, array: []*anyValue{scalarAny(stringScalar("warnings"))}}}}}
+	tc.write(ClientSQLStmtExecute, appendStringField(appendBytesField(appendStringField(nil, 1, "disable_notices"), 2, appendAny(nil, notice)), 3, "mysqlx"))
+	types, _ = tc.readUntil(ServerSQLStmtExecuteOk)
+	assert.Equal(t, []byte{ServerNotice, ServerSQLStmtExecuteOk}, types)
+
+	// a document inserted without _id gets a generated one
+	collection := appendStringField(appendStringField(nil, 1, "users"), 2, "ks")
+	object := appendVarintField(nil, 1, exprObject)
+	object = appendBytesField(object, 8, appendBytesField(nil, 1, appendBytesField(appendStringField(nil, 1, "name"), 2,
+		appendBytesField(appendVarintField(nil, 1, exprLiteral), 4, appendScalar(nil, stringScalar("a"))))))
+	insert := appendBytesField(nil, 1, collection)
+	insert = appendVarintField(insert, 2, dataModelDocument)
+	insert = appendBytesField(insert, 4, appendBytesField(nil, 1, object))
+	tc.write(ClientCrudInsert, insert)
+	types, data := tc.readUntil(ServerSQLStmtExecuteOk)
+	require.EqualValues(t, ServerSQLStmtExecuteOk, types[len(types)-1], "%q", data)
+	query, _ = handler.lastQuery()
+	assert.True(t, strings.HasPrefix(query, "INSERT INTO `ks`.`users` (`doc`) VALUES (JSON_OBJECT('_id','0000"), query)
+
+	tc.write(ClientCrudDelete, appendBytesField(nil, 1, appendStringField(nil, 1, "users")))
+	types, _ = tc.readUntil(ServerSQLStmtExecuteOk)
+	assert.EqualValues(t, ServerSQLStmtExecuteOk, types[len(types)-1])
+	query, _ = handler.lastQuery()
+	assert.Equal(t, "DELETE FROM `users`", query)
+
+	// prepared statements are not supported, the clients fall back to StmtExecute
+	tc.write(40, nil)
+	assert.EqualValues(t, sqlerror.ERUnknownComError, tc.readError())
+}
+
+func TestServerExpect(t *testing.T) {
+	l, handler := newTestListener(t)
+	tc := dial(t, l)
+	require.EqualValues(t, ServerSessAuthenticateOk, tc.authenticate("user1", "password1"))
+
+	noError := appendBytesField(appendVarintField(nil, 1, expectCtxEmpty), 2, appendVarintField(nil, 1, expectNoError))
+	tc.write(ClientExpectOpen, noError)
+	typ, _ := tc.read()
+	require.EqualValues(t, ServerOk, typ)
+
+	tc.stmtExecute("error")
+	assert.EqualValues(t, sqlerror.ERNoSuchTable, tc.readError())
+	tc.stmtExecute("insert")
+	assert.EqualValues(t, ERXExpectFailed, tc.readError())
+	query, _ := handler.lastQuery()
+	assert.Equal(t, "error", query)
+
+	tc.write(ClientExpectClose, nil)
+	assert.EqualValues(t, ERXExpectFailed, tc.readError())
+	tc.write(ClientExpectClose, nil)
+	assert.EqualValues(t, ERXExpectNotOpen, tc.readError())
+
+	tc.stmtExecute("insert")
+	types, _ := tc.readUntil(ServerSQLStmtExecuteOk)
+	assert.EqualValues(t, ServerSQLStmtExecuteOk, types[len(types)-1])
+}
+
+func TestServerSessionReset(t *testing.T) {
+	l, handler := newTestListener(t)
+	tc := dial(t, l)
+	require.EqualValues(t, ServerSessAuthenticateOk, tc.authenticate("user1", "password1"))
+
+	// a reset that does not keep the session open requires a new authentication
+	tc.write(ClientSessReset, nil)
+	typ, _ := tc.read()
+	require.EqualValues(t, ServerOk, typ)
+	tc.stmtExecute("insert")
+	assert.EqualValues(t, sqlerror.ERUnknownComError, tc.readError())
+	require.EqualValues(t, ServerSessAuthenticateOk, tc.authenticate("user1", "password1"))
+
+	tc.write(ClientSessClose, nil)
+	typ, _ = tc.read()
+	assert.EqualValues(t, ServerOk, typ)
+	_, err := tc.conn.Read(make([]byte, 1))
+	assert.True(t, errors.Is(err, io.EOF), "%v", err)
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	assert.Equal(t, 2, handler.closed)
+}
@@ -0,0 +1,893 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
+	"github.com/mdibaiee/vitess/go/sqlescape"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+)
+
+// The document store keeps every document of a collection in the doc
+// column of a table, and its _id in a generated column that is the primary
+// key, like the X Plugin of MySQL does.
+const (
+	docColumn = "`doc`"
+	idMember  = "_id"
+)
+
+// binaryOperators are the operators of Mysqlx.Expr.Operator that are
+// written between their two parameters.
+var binaryOperators = map[string]string{
+	"==":          "=",
+	"!=":          "!=",
+	">":           ">",
+	">=":          ">=",
+	"<":           "<",
+	"<=":          "<=",
+	"&":           "&",
+	"|":           "|",
+	"^":           "^",
+	"<<":          "<<",
+	">>":          ">>",
+	"+":           "+",
+	"-":           "-",
+	"*":           "*",
+	"/":           "/",
+	"div":         "DIV",
+	"%":           "%",
+	"&&":          "AND",
+	"||":          "OR",
+	"xor":         "XOR",
+	"is":          "IS",
+	"is_not":      "IS NOT",
+	"regexp":      "REGEXP",
+	"not_regexp":  "NOT REGEXP",
+	"sounds_like": "SOUNDS LIKE",
+}
+
+// unaryOperators are the operators of Mysqlx.Expr.Operator that are
+// written before their only parameter.
+var unaryOperators = map[string]string{
+	"!":          "NOT ",
+	"not":        "NOT ",
+	"~":          "~",
+	"sign_plus":  "+",
+	"sign_minus": "-",
+}
+
+var (
+	castTypeRegexp     = regexp.MustCompile(`^(?i)(BINARY|CHAR|DECIMAL)(\(\d+(,\d+)?\))?$|^(?i)(DATE|DATETIME|TIME|JSON|SIGNED|UNSIGNED)( INTEGER)?$`)
+	intervalUnitRegexp = regexp.MustCompile(`^(?i)(MICROSECOND|SECOND|MINUTE|HOUR|DAY|WEEK|MONTH|QUARTER|YEAR|SECOND_MICROSECOND|MINUTE_MICROSECOND|MINUTE_SECOND|HOUR_MICROSECOND|HOUR_SECOND|HOUR_MINUTE|DAY_MICROSECOND|DAY_SECOND|DAY_MINUTE|DAY_HOUR|YEAR_MONTH)$`)
+	functionNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	memberNameRegexp   = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+)
+
+func newInvalidArgument(format string, args ...any) error {
+	return sqlerror.NewSQLError(ERXInvalidArgument, sqlerror.SSUnknownSQLState, format, args...)
+}
+
+// generator translates the CRUD messages to SQL statements.
+type generator struct {
+	buf       strings.Builder
+	dataModel uint64
+	args      []*scalar
+}
+
+func newGenerator(c *crud) *generator {
+	return &generator{dataModel: c.dataModel, args: c.args}
+}
+
+func (g *generator) document() bool {
+	return g.dataModel != dataModelTable
+}
+
+func (g *generator) write(s ...string) {
+	for _, s := range s {
+		g.buf.WriteString(s)
+	}
+}
+
+func (g *generator) table(c *crud) error {
+	if c.table == "" {
+		return sqlerror.NewSQLError(ERXInvalidCollection, sqlerror.SSUnknownSQLState, "Invalid name of table/collection")
+	}
+	if c.schema != "" {
+		g.write(sqlescape.EscapeID(c.schema), ".")
+	}
+	g.write(sqlescape.EscapeID(c.table))
+	return nil
+}
+
+func (g *generator) where(c *crud) error {
+	if c.criteria == nil {
+		return nil
+	}
+	g.write(" WHERE ")
+	return g.expr(c.criteria)
+}
+
+func (g *generator) orderBy(c *crud) error {
+	for i, o := range c.order {
+		if i == 0 {
+			g.write(" ORDER BY ")
+		} else {
+			g.write(", ")
+		}
+		if err := g.expr(o.expr); err != nil {
+			return err
+		}
+		if o.desc {
+			g.write(" DESC")
+		}
+	}
+	return nil
+}
+
+// limit writes the LIMIT clause, the statements that change rows cannot have an offset.
+func (g *generator) limit(c *crud, allowOffset bool) error {
+	if c.offset != nil && !allowOffset && !(c.offset.typ == exprLiteral && c.offset.literal.typ == scalarUint && c.offset.literal.u == 0) {
+		return newInvalidArgument("Invalid parameter: non-zero offset value not allowed for this operation")
+	}
+	if c.rowCount == nil {
+		return nil
+	}
+	g.write(" LIMIT ")
+	if c.offset != nil && allowOffset {
+		if err := g.expr(c.offset); err != nil {
+			return err
+		}
+		g.write(", ")
+	}
+	return g.expr(c.rowCount)
+}
+
+func (g *generator) expr(e *expr) error {
+	switch e.typ {
+	case exprIdent:
+		return g.columnIdentifier(e.identifier)
+	case exprLiteral:
+		g.literal(e.literal)
+	case exprVariable:
+		return sqlerror.NewSQLError(ERXExprBadTypeValue, sqlerror.SSUnknownSQLState, "Mysqlx::Expr::Expr::VARIABLE is not supported yet")
+	case exprFuncCall:
+		return g.functionCall(e)
+	case exprOperator:
+		return g.operator(e)
+	case exprPlaceholder:
+		arg, err := g.placeholder(e)
+		if err != nil {
+			return err
+		}
+		g.literal(arg)
+	case exprObject:
+		g.write("JSON_OBJECT(")
+		for i, f := range e.object {
+			if i > 0 {
+				g.write(",")
+			}
+			g.write(sqltypes.EncodeStringSQL(f.key), ",")
+			if err := g.expr(f.value); err != nil {
+				return err
+			}
+		}
+		g.write(")")
+	case exprArray:
+		g.write("JSON_ARRAY(")
+		if err := g.exprList(e.array); err != nil {
+			return err
+		}
+		g.write(")")
+	default:
+		return sqlerror.NewSQLError(ERXExprBadTypeValue, sqlerror.SSUnknownSQLState, "Invalid value for Mysqlx::Expr::Expr_Type %d", e.typ)
+	}
+	return nil
+}
+
+func (g *generator) exprList(exprs []*expr) error {
+	for i, e := range exprs {
+		if i > 0 {
+			g.write(",")
+		}
+		if err := g.expr(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) placeholder(e *expr) (*scalar, error) {
+	if e.position >= uint64(len(g.args)) {
+		return nil, sqlerror.NewSQLError(ERXExprBadValue, sqlerror.SSUnknownSQLState, "Invalid value of placeholder")
+	}
+	return g.args[e.position], nil
+}
+
+func (g *generator) literal(s *scalar) {
+	switch s.typ {
+	case scalarSint:
+		g.write(strconv.FormatInt(s.i, 10))
+	case scalarUint:
+		g.write(strconv.FormatUint(s.u, 10))
+	case scalarDouble:
+		g.write(strconv.FormatFloat(s.f, 'g', -1, 64))
+	case scalarFloat:
+		g.write(strconv.FormatFloat(s.f, 'g', -1, 32))
+	case scalarBool:
+		if s.u != 0 {
+			g.write("TRUE")
+		} else {
+			g.write("FALSE")
+		}
+	case scalarOctets:
+		if s.contentType == ContentTypeJSON {
+			g.write("CAST(", sqltypes.EncodeStringSQL(string(s.b)), " AS JSON)")
+		} else {
+			g.write(sqltypes.EncodeStringSQL(string(s.b)))
+		}
+	case scalarString:
+		g.write(sqltypes.EncodeStringSQL(string(s.b)))
+	default:
+		g.write("NULL")
+	}
+}
+
+// column returns the column of an identifier, documents are in the doc column.
+func (g *generator) column(id *columnIdentifier) string {
+	if id.name == "" {
+		return docColumn
+	}
+	var col strings.Builder
+	if id.schemaName != "" {
+		sqlescape.WriteEscapeID(&col, id.schemaName)
+		col.WriteByte('.')
+	}
+	if id.tableName != "" {
+		sqlescape.WriteEscapeID(&col, id.tableName)
+		col.WriteByte('.')
+	}
+	sqlescape.WriteEscapeID(&col, id.name)
+	return col.String()
+}
+
+func (g *generator) columnIdentifier(id *columnIdentifier) error {
+	if id.name == "" && !g.document() {
+		return newInvalidArgument("Column name is required if data model is TABLE")
+	}
+	col := g.column(id)
+	if len(id.documentPath) == 0 {
+		g.write(col)
+		return nil
+	}
+	path, err := documentPath(id.documentPath)
+	if err != nil {
+		return err
+	}
+	g.write("JSON_EXTRACT(", col, ",", sqltypes.EncodeStringSQL(path), ")")
+	return nil
+}
+
+// documentPath returns the JSON path of a document path.
+func documentPath(items []documentPathItem) (string, error) {
+	var path strings.Builder
+	path.WriteByte('$')
+	for _, item := range items {
+		switch item.typ {
+		case pathMember:
+			path.WriteByte('.')
+			if memberNameRegexp.MatchString(item.value) {
+				path.WriteString(item.value)
+			} else {
+				path.WriteString(strconv.Quote(item.value))
+			}
+		case pathMemberAsterisk:
+			path.WriteString(".*")
+		case pathArrayIndex:
+			path.WriteString("[" + strconv.FormatUint(item.index, 10) + "]")
+		case pathArrayIndexAsterisk:
+			path.WriteString("[*]")
+		case pathDoubleAsterisk:
+			path.WriteString("**")
+		default:
+			return "", sqlerror.NewSQLError(ERXExprBadTypeValue, sqlerror.SSUnknownSQLState, "Invalid value for Mysqlx::Expr::DocumentPathItem::Type %d", item.typ)
+		}
+	}
+	return path.String(), nil
+}
+
+func (g *generator) functionCall(e *expr) error {
+	if !functionNameRegexp.MatchString(e.name) {
+		return newInvalidArgument("Invalid function name '%s'", e.name)
+	}
+	if e.schema != "" {
+		g.write(sqlescape.EscapeID(e.schema), ".")
+	}
+	g.write(e.name, "(")
+	if err := g.exprList(e.params); err != nil {
+		return err
+	}
+	g.write(")")
+	return nil
+}
+
+// json writes an expression as a JSON value, for the JSON functions.
+func (g *generator) json(e *expr) error {
+	literal := e.literal
+	if e.typ == exprPlaceholder {
+		var err error
+		if literal, err = g.placeholder(e); err != nil {
+			return err
+		}
+	}
+	switch {
+	case e.typ == exprIdent && len(e.identifier.documentPath) > 0, e.typ == exprObject, e.typ == exprArray:
+		return g.expr(e)
+	case literal != nil && (literal.typ == scalarString || literal.typ == scalarOctets && literal.contentType != ContentTypeJSON):
+		g.write("JSON_QUOTE(")
+		g.literal(literal)
+		g.write(")")
+	case literal != nil:
+		g.write("CAST(")
+		g.literal(literal)
+		g.write(" AS JSON)")
+	default:
+		g.write("CAST(")
+		if err := g.expr(e); err != nil {
+			return err
+		}
+		g.write(" AS JSON)")
+	}
+	return nil
+}
+
+// literalParam returns the value of a literal parameter of an operator, like the type of a cast.
+func (g *generator) literalParam(e *expr) (string, bool) {
+	literal := e.literal
+	if e.typ == exprPlaceholder {
+		literal, _ = g.placeholder(e)
+	} else if e.typ != exprLiteral {
+		return "", false
+	}
+	if literal == nil || (literal.typ != scalarOctets && literal.typ != scalarString) {
+		return "", false
+	}
+	return string(literal.b), true
+}
+
+func (g *generator) operator(e *expr) error {
+	numParams := func(want ...int) error {
+		for _, n := range want {
+			if len(e.params) == n {
+				return nil
+			}
+		}
+		return sqlerror.NewSQLError(ERXExprBadNumArgs, sqlerror.SSUnknownSQLState, "Invalid number of arguments for operator '%s'", e.name)
+	}
+
+	if op, ok := binaryOperators[e.name]; ok {
+		if e.name == "*" && len(e.params) == 0 {
+			g.write("*")
+			return nil
+		}
+		if err := numParams(2); err != nil {
+			return err
+		}
+		g.write("(")
+		if err := g.expr(e.params[0]); err != nil {
+			return err
+		}
+		g.write(" ", op, " ")
+		if err := g.expr(e.params[1]); err != nil {
+			return err
+		}
+		g.write(")")
+		return nil
+	}
+	if op, ok := unaryOperators[e.name]; ok {
+		if err := numParams(1); err != nil {
+			return err
+		}
+		g.write("(", op)
+		if err := g.expr(e.params[0]); err != nil {
+			return err
+		}
+		g.write(")")
+		return nil
+	}
+
+	not := ""
+	name := e.name
+	if strings.HasPrefix(name, "not_") {
+		not = "NOT "
+		name = strings.TrimPrefix(name, "not_")
+	}
+	switch name {
+	case "like":
+		if err := numParams(2, 3); err != nil {
+			return err
+		}
+		g.write("(")
+		if err := g.expr(e.params[0]); err != nil {
+			return err
+		}
+		g.write(" ", not, "LIKE ")
+		if err := g.expr(e.params[1]); err != nil {
+			return err
+		}
+		if len(e.params) == 3 {
+			g.write(" ESCAPE ")
+			if err := g.expr(e.params[2]); err != nil {
+				return err
+			}
+		}
+		g.write(")")
+	case "in":
+		if len(e.params) < 2 {
+			return numParams(2)
+		}
+		g.write("(")
+		if err := g.expr(e.params[0]); err != nil {
+			return err
+		}
+		g.write(" ", not, "IN (")
+		if err := g.exprList(e.params[1:]); err != nil {
+			return err
+		}
+		g.write("))")
+	case "between":
+		if err := numParams(3); err != nil {
+			return err
+		}
+		g.write("(")
+		if err := g.expr(e.params[0]); err != nil {
+			return err
+		}
+		g.write(" ", not, "BETWEEN ")
+		if err := g.expr(e.params[1]); err != nil {
+			return err
+		}
+		g.write(" AND ")
+		if err := g.expr(e.params[2]); err != nil {
+			return err
+		}
+		g.write(")")
+	case "cont_in", "overlaps":
+		if err := numParams(2); err != nil {
+			return err
+		}
+		target, candidate := e.params[1], e.params[0]
+		function := "JSON_CONTAINS("
+		if name == "overlaps" {
+			target, candidate = e.params[0], e.params[1]
+			function = "JSON_OVERLAPS("
+		}
+		g.write(not, function)
+		if err := g.json(target); err != nil {
+			return err
+		}
+		g.write(",")
+		if err := g.json(candidate); err != nil {
+			return err
+		}
+		g.write(")")
+	case "cast":
+		if err := numParams(2); err != nil || not != "" {
+			return sqlerror.NewSQLError(ERXExprBadOperator, sqlerror.SSUnknownSQLState, "Invalid operator %s", e.name)
+		}
+		typ, ok := g.literalParam(e.params[1])
+		if !ok || !castTypeRegexp.MatchString(typ) {
+			return sqlerror.NewSQLError(ERXExprBadTypeValue, sqlerror.SSUnknownSQLState, "Invalid cast type")
+		}
+		g.write("CAST(")
+		if err := g.expr(e.params[0]); err != nil {
+			return err
+		}
+		g.write(" AS ", strings.ToUpper(typ), ")")
+	case "date_add", "date_sub":
+		if err := numParams(3); err != nil || not != "" {
+			return sqlerror.NewSQLError(ERXExprBadOperator, sqlerror.SSUnknownSQLState, "Invalid operator %s", e.name)
+		}
+		unit, ok := g.literalParam(e.params[2])
+		if !ok || !intervalUnitRegexp.MatchString(unit) {
+			return sqlerror.NewSQLError(ERXExprBadValue, sqlerror.SSUnknownSQLState, "Invalid value of interval unit")
+		}
+		g.write(strings.ToUpper(name), "(")
+		if err := g.expr(e.params[0]); err != nil {
+			return err
+		}
+		g.write(", INTERVAL ")
+		if err := g.expr(e.params[1]); err != nil {
+			return err
+		}
+		g.write(" ", strings.ToUpper(unit), ")")
+	case "default":
+		if err := numParams(0); err != nil || not != "" {
+			return sqlerror.NewSQLError(ERXExprBadOperator, sqlerror.SSUnknownSQLState, "Invalid operator %s", e.name)
+		}
+		g.write("DEFAULT")
+	default:
+		return sqlerror.NewSQLError(ERXExprBadOperator, sqlerror.SSUnknownSQLState, "Invalid operator %s", e.name)
+	}
+	return nil
+}
+
+// findSQL translates a Mysqlx.Crud.Find to a SELECT.
+func findSQL(msg *crudFind) (string, error) {
+	g := newGenerator(&msg.crud)
+	g.write("SELECT ")
+	switch {
+	case len(msg.projection) == 0 && g.document():
+		g.write(docColumn)
+	case len(msg.projection) == 0:
+		g.write("*")
+	case g.document():
+		g.write("JSON_OBJECT(")
+		for i, p := range msg.projection {
+			alias := p.alias
+			if alias == "" {
+				alias = projectionName(p.source)
+			}
+			if alias == "" {
+				return "", newInvalidArgument("Invalid projection target name")
+			}
+			if i > 0 {
+				g.write(",")
+			}
+			g.write(sqltypes.EncodeStringSQL(alias), ",")
+			if err := g.expr(p.source); err != nil {
+				return "", err
+			}
+		}
+		g.write(") AS ", docColumn)
+	default:
+		for i, p := range msg.projection {
+			if i > 0 {
+				g.write(",")
+			}
+			if err := g.expr(p.source); err != nil {
+				return "", err
+			}
+			if p.alias != "" {
+				g.write(" AS ", sqlescape.EscapeID(p.alias))
+			}
+		}
+	}
+	g.write(" FROM ")
+	if err := g.table(&msg.crud); err != nil {
+		return "", err
+	}
+	if err := g.where(&msg.crud); err != nil {
+		return "", err
+	}
+	if len(msg.grouping) > 0 {
+		g.write(" GROUP BY ")
+		if err := g.exprList(msg.grouping); err != nil {
+			return "", err
+		}
+	}
+	if msg.groupingCriteria != nil {
+		g.write(" HAVING ")
+		if err := g.expr(msg.groupingCriteria); err != nil {
+			return "", err
+		}
+	}
+	if err := g.orderBy(&msg.crud); err != nil {
+		return "", err
+	}
+	if err := g.limit(&msg.crud, true); err != nil {
+		return "", err
+	}
+	switch msg.locking {
+	case lockShared:
+		g.write(" FOR SHARE")
+	case lockExclusive:
+		g.write(" FOR UPDATE")
+	}
+	if msg.locking != 0 {
+		switch msg.lockingOptions {
+		case lockNoWait:
+			g.write(" NOWAIT")
+		case lockSkipLocked:
+			g.write(" SKIP LOCKED")
+		}
+	}
+	return g.buf.String(), nil
+}
+
+// projectionName returns the name of a document projection without alias,
+// which is the last member of its path.
+func projectionName(e *expr) string {
+	if e.typ != exprIdent {
+		return ""
+	}
+	if path := e.identifier.documentPath; len(path) > 0 {
+		if last := path[len(path)-1]; last.typ == pathMember {
+			return last.value
+		}
+		return ""
+	}
+	return e.identifier.name
+}
+
+// insertSQL translates a Mysqlx.Crud.Insert to an INSERT. The documents
+// without an _id get one from newID, insertSQL returns their ids.
+func insertSQL(msg *crudInsert, newID func() string) (string, []string, error) {
+	g := newGenerator(&msg.crud)
+	if len(msg.rows) == 0 {
+		return "", nil, sqlerror.NewSQLError(ERXMissingArgument, sqlerror.SSUnknownSQLState, "Missing row data for Insert")
+	}
+	g.write("INSERT INTO ")
+	if err := g.table(&msg.crud); err != nil {
+		return "", nil, err
+	}
+	switch {
+	case g.document():
+		if len(msg.columns) > 0 {
+			return "", nil, newInvalidArgument("Invalid number of arguments, expected value for document column")
+		}
+		g.write(" (", docColumn, ")")
+	case len(msg.columns) > 0:
+		g.write(" (", strings.Join(sqlescape.EscapeIDs(msg.columns), ","), ")")
+	}
+
+	var ids []string
+	g.write(" VALUES ")
+	for i, row := range msg.rows {
+		if i > 0 {
+			g.write(",")
+		}
+		g.write("(")
+		if !g.document() {
+			if len(msg.columns) > 0 && len(row) != len(msg.columns) {
+				return "", nil, newInvalidArgument("Wrong number of fields in row being inserted")
+			}
+			if err := g.exprList(row); err != nil {
+				return "", nil, err
+			}
+			g.write(")")
+			continue
+		}
+		if len(row) != 1 {
+			return "", nil, newInvalidArgument("Wrong number of fields in row being inserted")
+		}
+		id, err := g.insertDocument(row[0], newID)
+		if err != nil {
+			return "", nil, err
+		}
+		if id != "" {
+			ids = append(ids, id)
+		}
+		g.write(")")
+	}
+	if msg.upsert {
+		if !g.document() {
+			return "", nil, newInvalidArgument("Unable update on duplicate key for TABLE data model")
+		}
+		g.write(" ON DUPLICATE KEY UPDATE ", docColumn, " = VALUES(", docColumn, ")")
+	}
+	return g.buf.String(), ids, nil
+}
+
+// insertDocument writes a document to insert. When it has no _id, it
+// returns the one it was given.
+func (g *generator) insertDocument(e *expr, newID func() string) (string, error) {
+	literal := e.literal
+	switch e.typ {
+	case exprObject:
+		for _, f := range e.object {
+			if f.key == idMember {
+				return "", g.expr(e)
+			}
+		}
+		id := newID()
+		doc := &expr{typ: exprObject, object: append([]exprField{{key: idMember, value: &expr{typ: exprLiteral, literal: stringScalar(id)}}}, e.object...)}
+		return id, g.expr(doc)
+	case exprPlaceholder:
+		var err error
+		if literal, err = g.placeholder(e); err != nil {
+			return "", err
+		}
+	case exprLiteral:
+	default:
+		return "", g.expr(e)
+	}
+
+	if literal.typ != scalarString && literal.typ != scalarOctets {
+		return "", newInvalidArgument("Invalid data, expecting a document")
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(literal.b, &doc); err != nil {
+		return "", newInvalidArgument("Invalid data, expecting a document")
+	}
+	if _, ok := doc[idMember]; ok {
+		g.literal(literal)
+		return "", nil
+	}
+	id := newID()
+	g.write("JSON_SET(")
+	g.literal(literal)
+	g.write(",'$._id',", sqltypes.EncodeStringSQL(id), ")")
+	return id, nil
+}
+
+// updateSQL translates a Mysqlx.Crud.Update to an UPDATE.
+func updateSQL(msg *crudUpdate) (string, error) {
+	g := newGenerator(&msg.crud)
+	if len(msg.operations) == 0 {
+		return "", sqlerror.NewSQLError(ERXMissingArgument, sqlerror.SSUnknownSQLState, "Invalid parameter: list of expressions to update is empty")
+	}
+
+	// the operations on the same column are nested, in their order
+	var columns []string
+	values := make(map[string]string)
+	for _, op := range msg.operations {
+		column := g.column(op.source)
+		value, ok := values[column]
+		if !ok {
+			columns = append(columns, column)
+			value = column
+		}
+		var err error
+		if values[column], err = newGenerator(&msg.crud).updateOperation(op, value); err != nil {
+			return "", err
+		}
+	}
+
+	g.write("UPDATE ")
+	if err := g.table(&msg.crud); err != nil {
+		return "", err
+	}
+	for i, column := range columns {
+		if i == 0 {
+			g.write(" SET ")
+		} else {
+			g.write(",")
+		}
+		g.write(column, " = ", values[column])
+	}
+	if err := g.where(&msg.crud); err != nil {
+		return "", err
+	}
+	if err := g.orderBy(&msg.crud); err != nil {
+		return "", err
+	}
+	if err := g.limit(&msg.crud, false); err != nil {
+		return "", err
+	}
+	return g.buf.String(), nil
+}
+
+// updateOperation returns the new value of a column after an operation,
+// given its current value. It must be called on a new generator.
+func (g *generator) updateOperation(op updateOperation, current string) (string, error) {
+	path, err := documentPath(op.source.documentPath)
+	if err != nil {
+		return "", err
+	}
+	if g.document() && op.source.name != "" {
+		return "", newInvalidArgument("Invalid column name to update")
+	}
+	if g.document() && len(op.source.documentPath) > 0 && op.source.documentPath[0].typ == pathMember && op.source.documentPath[0].value == idMember {
+		return "", newInvalidArgument("Forbidden update operation on '$._id' member")
+	}
+
+	value := func(json bool) error {
+		if op.value == nil {
+			return sqlerror.NewSQLError(ERXMissingArgument, sqlerror.SSUnknownSQLState, "Missing value for update operation")
+		}
+		if json {
+			return g.json(op.value)
+		}
+		return g.expr(op.value)
+	}
+	withPath := func(function string) error {
+		if len(op.source.documentPath) == 0 {
+			return newInvalidArgument("Invalid member location")
+		}
+		g.write(function, "(", current, ",", sqltypes.EncodeStringSQL(path))
+		if function != "JSON_REMOVE" {
+			g.write(",")
+			if err := value(false); err != nil {
+				return err
+			}
+		}
+		g.write(")")
+		return nil
+	}
+
+	switch op.operation {
+	case updateSet:
+		if g.document() || len(op.source.documentPath) > 0 {
+			return "", newInvalidArgument("Invalid type of update operation for document")
+		}
+		err = value(false)
+	case updateItemRemove:
+		err = withPath("JSON_REMOVE")
+	case updateItemSet:
+		err = withPath("JSON_SET")
+	case updateItemReplace:
+		err = withPath("JSON_REPLACE")
+	case updateArrayInsert:
+		err = withPath("JSON_ARRAY_INSERT")
+	case updateArrayAppend:
+		err = withPath("JSON_ARRAY_APPEND")
+	case updateItemMerge, updateMergePatch:
+		function := "JSON_MERGE_PRESERVE("
+		if op.operation == updateMergePatch {
+			function = "JSON_MERGE_PATCH("
+		}
+		// the _id of the documents cannot change
+		keepID := g.document() && op.operation == updateMergePatch
+		if keepID {
+			g.write("JSON_SET(")
+		}
+		g.write(function, current, ",")
+		if err = value(true); err != nil {
+			break
+		}
+		g.write(")")
+		if keepID {
+			g.write(",'$._id',JSON_EXTRACT(", docColumn, ",'$._id'))")
+		}
+	default:
+		return "", newInvalidArgument("Invalid type of update operation")
+	}
+	if err != nil {
+		return "", err
+	}
+	return g.buf.String(), nil
+}
+
+// deleteSQL translates a Mysqlx.Crud.Delete to a DELETE.
+func deleteSQL(msg *crudDelete) (string, error) {
+	g := newGenerator(&msg.crud)
+	g.write("DELETE FROM ")
+	if err := g.table(&msg.crud); err != nil {
+		return "", err
+	}
+	if err := g.where(&msg.crud); err != nil {
+		return "", err
+	}
+	if err := g.orderBy(&msg.crud); err != nil {
+		return "", err
+	}
+	if err := g.limit(&msg.crud, false); err != nil {
+		return "", err
+	}
+	return g.buf.String(), nil
+}
+
+// createCollectionSQL returns the CREATE TABLE of a collection.
+func createCollectionSQL(schema, name string, ifNotExists bool) string {
+	var sql strings.Builder
+	sql.WriteString("CREATE TABLE ")
+	if ifNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	if schema != "" {
+		sqlescape.WriteEscapeID(&sql, schema)
+		sql.WriteByte('.')
+	}
+	sqlescape.WriteEscapeID(&sql, name)
+	sql.WriteString(" (" + docColumn + " JSON, `_id` VARBINARY(32) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(" + docColumn + ", '$._id'))) STORED PRIMARY KEY) CHARSET utf8mb4 ENGINE=InnoDB")
+	return sql.String()
+}
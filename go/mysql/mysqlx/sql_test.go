@@ -0,0 +1,275 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func member(names ...string) *expr {
+	id := &columnIdentifier{}
+	for _, name := range names {
+		id.documentPath = append(id.documentPath, documentPathItem{typ: pathMember, value: name})
+	}
+	return &expr{typ: exprIdent, identifier: id}
+}
+
+func column(name string) *expr {
+	return &expr{typ: exprIdent, identifier: &columnIdentifier{name: name}}
+}
+
+func literal(s *scalar) *expr {
+	return &expr{typ: exprLiteral, literal: s}
+}
+
+func str(s string) *expr {
+	return literal(stringScalar(s))
+}
+
+func sint(i int64) *expr {
+	return literal(&scalar{typ: scalarSint, i: i})
+}
+
+func operator(name string, params ...*expr) *expr {
+	return &expr{typ: exprOperator, name: name, params: params}
+}
+
+func placeholder(position uint64) *expr {
+	return &expr{typ: exprPlaceholder, position: position}
+}
+
+func TestFindSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *crudFind
+		want string
+		err  string
+	}{{
+		name: "all documents",
+		msg:  &crudFind{crud: crud{schema: "ks", table: "users", dataModel: dataModelDocument}},
+		want: "SELECT `doc` FROM `ks`.`users`",
+	}, {
+		name: "criteria, order and limit",
+		msg: &crudFind{crud: crud{
+			table:     "users",
+			dataModel: dataModelDocument,
+			criteria: operator("&&",
+				operator(">=", member("age"), placeholder(0)),
+				operator("like", member("name", "first"), str("a%"))),
+			order:    []order{{expr: member("age"), desc: true}, {expr: member("_id")}},
+			rowCount: literal(uintScalar(10)),
+			offset:   literal(uintScalar(20)),
+			args:     []*scalar{{typ: scalarSint, i: 18}},
+		}},
+		want: "SELECT `doc` FROM `users` WHERE ((JSON_EXTRACT(`doc`,'$.age') >= 18) AND (JSON_EXTRACT(`doc`,'$.name.first') LIKE 'a%')) ORDER BY JSON_EXTRACT(`doc`,'$.age') DESC, JSON_EXTRACT(`doc`,'$._id') LIMIT 20, 10",
+	}, {
+		name: "projection",
+		msg: &crudFind{
+			crud: crud{table: "users", dataModel: dataModelDocument},
+			projection: []projection{
+				{source: member("name", "first")},
+				{source: operator("+", member("age"), sint(1)), alias: "next age"},
+				{source: &expr{typ: exprFuncCall, name: "count", params: []*expr{operator("*")}}, alias: "count"},
+			},
+			grouping:         []*expr{member("name", "first"), member("age")},
+			groupingCriteria: operator(">", &expr{typ: exprFuncCall, name: "count", params: []*expr{operator("*")}}, sint(1)),
+		},
+		want: "SELECT JSON_OBJECT('first',JSON_EXTRACT(`doc`,'$.name.first'),'next age',(JSON_EXTRACT(`doc`,'$.age') + 1),'count',count(*)) AS `doc` FROM `users` GROUP BY JSON_EXTRACT(`doc`,'$.name.first'),JSON_EXTRACT(`doc`,'$.age') HAVING (count(*) > 1)",
+	}, {
+		name: "projection without a name",
+		msg: &crudFind{
+			crud:       crud{table: "users", dataModel: dataModelDocument},
+			projection: []projection{{source: sint(1)}},
+		},
+		err: "Invalid projection target name",
+	}, {
+		name: "table",
+		msg: &crudFind{
+			crud: crud{
+				table:     "users",
+				dataModel: dataModelTable,
+				criteria:  operator("in", column("id"), sint(1), sint(2)),
+			},
+			projection:     []projection{{source: column("id")}, {source: column("name"), alias: "n"}},
+			locking:        lockExclusive,
+			lockingOptions: lockSkipLocked,
+		},
+		want: "SELECT `id`,`name` AS `n` FROM `users` WHERE (`id` IN (1,2)) FOR UPDATE SKIP LOCKED",
+	}, {
+		name: "json operators",
+		msg: &crudFind{crud: crud{
+			table:     "users",
+			dataModel: dataModelDocument,
+			criteria: operator("||",
+				operator("cont_in", str("admin"), member("roles")),
+				operator("not_overlaps", member("tags"), &expr{typ: exprArray, array: []*expr{str("a"), literal(boolScalar(true))}})),
+		}},
+		want: "SELECT `doc` FROM `users` WHERE (JSON_CONTAINS(JSON_EXTRACT(`doc`,'$.roles'),JSON_QUOTE('admin')) OR NOT JSON_OVERLAPS(JSON_EXTRACT(`doc`,'$.tags'),JSON_ARRAY('a',TRUE)))",
+	}, {
+		name: "cast, intervals and member names",
+		msg: &crudFind{crud: crud{
+			table:     "users",
+			dataModel: dataModelDocument,
+			criteria: operator("&&",
+				operator("==", operator("cast", member("a b"), literal(&scalar{typ: scalarOctets, b: []byte("signed")})), sint(1)),
+				operator("<", member("created"), operator("date_sub", &expr{typ: exprFuncCall, name: "now"}, sint(1), str("DAY")))),
+		}},
+		want: "SELECT `doc` FROM `users` WHERE ((CAST(JSON_EXTRACT(`doc`,'$.\\\"a b\\\"') AS SIGNED) = 1) AND (JSON_EXTRACT(`doc`,'$.created') < DATE_SUB(now(), INTERVAL 1 DAY)))",
+	}, {
+		name: "bad cast",
+		msg: &crudFind{crud: crud{
+			table:     "users",
+			dataModel: dataModelDocument,
+			criteria:  operator("cast", member("a"), str("signed) or (1")),
+		}},
+		err: "Invalid cast type",
+	}, {
+		name: "bad operator",
+		msg: &crudFind{crud: crud{
+			table:     "users",
+			dataModel: dataModelDocument,
+			criteria:  operator("drop", member("a")),
+		}},
+		err: "Invalid operator drop",
+	}, {
+		name: "missing placeholder",
+		msg: &crudFind{crud: crud{
+			table:     "users",
+			dataModel: dataModelDocument,
+			criteria:  operator("==", member("a"), placeholder(1)),
+		}},
+		err: "Invalid value of placeholder",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := findSQL(tt.msg)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sql)
+		})
+	}
+}
+
+func TestInsertSQL(t *testing.T) {
+	ids := 0
+	newID := func() string {
+		ids++
+		return []string{"", "id1", "id2"}[ids]
+	}
+	msg := &crudInsert{
+		crud: crud{table: "users", dataModel: dataModelDocument, args: []*scalar{stringScalar(`{"name": "c"}`)}},
+		rows: [][]*expr{
+			{{typ: exprObject, object: []exprField{{key: "name", value: str("a")}}}},
+			{{typ: exprObject, object: []exprField{{key: "_id", value: str("b")}, {key: "tags", value: &expr{typ: exprArray}}}}},
+			{placeholder(0)},
+		},
+		upsert: true,
+	}
+	sql, documentIDs, err := insertSQL(msg, newID)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`doc`) VALUES (JSON_OBJECT('_id','id1','name','a')),(JSON_OBJECT('_id','b','tags',JSON_ARRAY())),(JSON_SET('{\\\"name\\\": \\\"c\\\"}','$._id','id2')) ON DUPLICATE KEY UPDATE `doc` = VALUES(`doc`)", sql)
+	assert.Equal(t, []string{"id1", "id2"}, documentIDs)
+
+	msg = &crudInsert{
+		crud:    crud{table: "users", dataModel: dataModelTable},
+		columns: []string{"id", "name"},
+		rows:    [][]*expr{{sint(1), str("a")}, {sint(2), operator("default")}},
+	}
+	sql, documentIDs, err = insertSQL(msg, newID)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`id`,`name`) VALUES (1,'a'),(2,DEFAULT)", sql)
+	assert.Empty(t, documentIDs)
+
+	msg.rows = [][]*expr{{sint(1)}}
+	_, _, err = insertSQL(msg, newID)
+	assert.ErrorContains(t, err, "Wrong number of fields in row being inserted")
+
+	msg = &crudInsert{
+		crud: crud{table: "users", dataModel: dataModelDocument},
+		rows: [][]*expr{{str("[1, 2]")}},
+	}
+	_, _, err = insertSQL(msg, newID)
+	assert.ErrorContains(t, err, "Invalid data, expecting a document")
+}
+
+func TestUpdateSQL(t *testing.T) {
+	path := func(names ...string) *columnIdentifier {
+		return member(names...).identifier
+	}
+	msg := &crudUpdate{
+		crud: crud{
+			table:     "users",
+			dataModel: dataModelDocument,
+			criteria:  operator("==", member("_id"), str("id1")),
+			rowCount:  literal(uintScalar(1)),
+		},
+		operations: []updateOperation{
+			{source: path("name"), operation: updateItemSet, value: str("b")},
+			{source: path("old"), operation: updateItemRemove},
+			{source: path("tags"), operation: updateArrayAppend, value: str("x")},
+			{source: &columnIdentifier{}, operation: updateMergePatch, value: &expr{typ: exprObject, object: []exprField{{key: "a", value: sint(1)}}}},
+		},
+	}
+	sql, err := updateSQL(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE `users` SET `doc` = JSON_SET(JSON_MERGE_PATCH(JSON_ARRAY_APPEND(JSON_REMOVE(JSON_SET(`doc`,'$.name','b'),'$.old'),'$.tags','x'),JSON_OBJECT('a',1)),'$._id',JSON_EXTRACT(`doc`,'$._id')) WHERE (JSON_EXTRACT(`doc`,'$._id') = 'id1') LIMIT 1", sql)
+
+	msg.operations = []updateOperation{{source: path("_id"), operation: updateItemSet, value: str("b")}}
+	_, err = updateSQL(msg)
+	assert.ErrorContains(t, err, "Forbidden update operation on '$._id' member")
+
+	msg = &crudUpdate{
+		crud: crud{table: "users", dataModel: dataModelTable, order: []order{{expr: column("id")}}},
+		operations: []updateOperation{
+			{source: &columnIdentifier{name: "name"}, operation: updateSet, value: str("b")},
+			{source: &columnIdentifier{name: "info", documentPath: path("a").documentPath}, operation: updateItemReplace, value: sint(1)},
+		},
+	}
+	sql, err = updateSQL(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE `users` SET `name` = 'b',`info` = JSON_REPLACE(`info`,'$.a',1) ORDER BY `id`", sql)
+
+	msg.offset = literal(uintScalar(1))
+	_, err = updateSQL(msg)
+	assert.ErrorContains(t, err, "non-zero offset value not allowed")
+}
+
+func TestDeleteSQL(t *testing.T) {
+	sql, err := deleteSQL(&crudDelete{crud: crud{
+		schema:    "ks",
+		table:     "users",
+		dataModel: dataModelDocument,
+		criteria:  operator("not_in", member("age"), sint(1), sint(2)),
+		rowCount:  literal(uintScalar(2)),
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `ks`.`users` WHERE (JSON_EXTRACT(`doc`,'$.age') NOT IN (1,2)) LIMIT 2", sql)
+
+	_, err = deleteSQL(&crudDelete{crud: crud{dataModel: dataModelDocument}})
+	assert.ErrorContains(t, err, "Invalid name of table/collection")
+}
+
+func TestCreateCollectionSQL(t *testing.T) {
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS `ks`.`users` (`doc` JSON, `_id` VARBINARY(32) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(`doc`, '$._id'))) STORED PRIMARY KEY) CHARSET utf8mb4 ENGINE=InnoDB", createCollectionSQL("ks", "users", true))
+	assert.Equal(t, "CREATE TABLE `users` (`doc` JSON, `_id` VARBINARY(32) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(`doc`, '$._id'))) STORED PRIMARY KEY) CHARSET utf8mb4 ENGINE=InnoDB", createCollectionSQL("", "users", false))
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
+)
+
+// errBadMessage is returned for the messages that cannot be decoded.
+var errBadMessage = sqlerror.NewSQLError(ERXBadMessage, sqlerror.SSUnknownSQLState, "Invalid message")
+
+// decodeFields calls fn for every field of a protobuf message. The value of
+// the varint and fixed fields is in v, the value of the length delimited ones
+// is in b.
+func decodeFields(data []byte, fn func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return errBadMessage
+		}
+		data = data[n:]
+
+		var v uint64
+		var b []byte
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var v32 uint32
+			v32, n = protowire.ConsumeFixed32(data)
+			v = uint64(v32)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			b, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return errBadMessage
+		}
+		data = data[n:]
+		if err := fn(num, typ, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendBytesField(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendStringField(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// Scalar types, from Mysqlx.Datatypes.Scalar.Type.
+const (
+	scalarSint   = 1
+	scalarUint   = 2
+	scalarNull   = 3
+	scalarOctets = 4
+	scalarDouble = 5
+	scalarFloat  = 6
+	scalarBool   = 7
+	scalarString = 8
+)
+
+// Any types, from Mysqlx.Datatypes.Any.Type.
+const (
+	anyScalar = 1
+	anyObject = 2
+	anyArray  = 3
+)
+
+// scalar is a Mysqlx.Datatypes.Scalar.
+type scalar struct {
+	typ uint64
+	// i holds the SINT values, u the UINT and BOOL ones, f the DOUBLE and FLOAT ones.
+	i int64
+	u uint64
+	f float64
+	// b holds the OCTETS and STRING values.
+	b []byte
+	// contentType is the content type of the OCTETS values.
+	contentType uint64
+}
+
+// anyValue is a Mysqlx.Datatypes.Any.
+type anyValue struct {
+	typ    uint64
+	scalar *scalar
+	object []objectField
+	array  []*anyValue
+}
+
+// objectField is a Mysqlx.Datatypes.Object.ObjectField.
+type objectField struct {
+	key   string
+	value *anyValue
+}
+
+// field returns the value of a field of an OBJECT, or nil.
+func (a *anyValue) field(key string) *anyValue {
+	if a == nil {
+		return nil
+	}
+	for _, f := range a.object {
+		if f.key == key {
+			return f.value
+		}
+	}
+	return nil
+}
+
+// str returns the value of a STRING or OCTETS scalar.
+func (a *anyValue) str() (string, bool) {
+	if a == nil || a.typ != anyScalar || (a.scalar.typ != scalarString && a.scalar.typ != scalarOctets) {
+		return "", false
+	}
+	return string(a.scalar.b), true
+}
+
+// boolean returns the value of a BOOL scalar.
+func (a *anyValue) boolean() (bool, bool) {
+	if a == nil || a.typ != anyScalar || a.scalar.typ != scalarBool {
+		return false, false
+	}
+	return a.scalar.u != 0, true
+}
+
+func decodeScalar(data []byte) (*scalar, error) {
+	s := &scalar{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			s.typ = v
+		case 2:
+			s.i = protowire.DecodeZigZag(v)
+		case 3, 8:
+			s.u = v
+		case 5, 9:
+			return decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				switch num {
+				case 1:
+					s.b = b
+				case 2:
+					s.contentType = v
+				}
+				return nil
+			})
+		case 6:
+			s.f = math.Float64frombits(v)
+		case 7:
+			s.f = float64(math.Float32frombits(uint32(v)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.typ < scalarSint || s.typ > scalarString {
+		return nil, errBadMessage
+	}
+	return s, nil
+}
+
+func decodeAny(data []byte) (*anyValue, error) {
+	a := &anyValue{}
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+		var err error
+		switch num {
+		case 1:
+			a.typ = v
+		case 2:
+			a.scalar, err = decodeScalar(b)
+		case 3:
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				if num != 1 {
+					return nil
+				}
+				var f objectField
+				err := decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+					var err error
+					switch num {
+					case 1:
+						f.key = string(b)
+					case 2:
+						f.value, err = decodeAny(b)
+					}
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				if f.value == nil {
+					return errBadMessage
+				}
+				a.object = append(a.object, f)
+				return nil
+			})
+		case 4:
+			err = decodeFields(b, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) error {
+				if num != 1 {
+					return nil
+				}
+				value, err := decodeAny(b)
+				a.array = append(a.array, value)
+				return err
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if a.typ == anyScalar && a.scalar == nil {
+		return nil, errBadMessage
+	}
+	return a, nil
+}
+
+func appendScalar(b []byte, s *scalar) []byte {
+	b = appendVarintField(b, 1, s.typ)
+	switch s.typ {
+	case scalarSint:
+		b = appendVarintField(b, 2, protowire.EncodeZigZag(s.i))
+	case scalarUint:
+		b = appendVarintField(b, 3, s.u)
+	case scalarBool:
+		b = appendVarintField(b, 8, s.u)
+	case scalarDouble:
+		b = protowire.AppendTag(b, 6, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(s.f))
+	case scalarOctets:
+		value := appendBytesField(nil, 1, s.b)
+		if s.contentType != 0 {
+			value = appendVarintField(value, 2, s.contentType)
+		}
+		b = appendBytesField(b, 5, value)
+	case scalarString:
+		b = appendBytesField(b, 9, appendBytesField(nil, 1, s.b))
+	}
+	return b
+}
+
+func appendAny(b []byte, a *anyValue) []byte {
+	b = appendVarintField(b, 1, a.typ)
+	switch a.typ {
+	case anyScalar:
+		b = appendBytesField(b, 2, appendScalar(nil, a.scalar))
+	case anyObject:
+		var object []byte
+		for _, f := range a.object {
+			field := appendStringField(nil, 1, f.key)
+			field = appendBytesField(field, 2, appendAny(nil, f.value))
+			object = appendBytesField(object, 1, field)
+		}
+		b = appendBytesField(b, 3, object)
+	case anyArray:
+		var array []byte
+		for _, value := range a.array {
+			array = appendBytesField(array, 1, appendAny(nil, value))
+		}
+		b = appendBytesField(b, 4, array)
+	}
+	return b
+}
+
+func stringScalar(s string) *scalar {
+	return &scalar{typ: scalarString, b: []byte(s)}
+}
+
+func uintScalar(u uint64) *scalar {
+	return &scalar{typ: scalarUint, u: u}
+}
+
+func boolScalar(v bool) *scalar {
+	s := &scalar{typ: scalarBool}
+	if v {
+		s.u = 1
+	}
+	return s
+}
+
+func scalarAny(s *scalar) *anyValue {
+	return &anyValue{typ: anyScalar, scalar: s}
+}
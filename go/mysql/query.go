@@ -55,7 +55,7 @@ const (
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) WriteComQuery(query string) error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(len(query) + 1)
 	data[pos] = ComQuery
@@ -928,6 +928,15 @@ func (c *Conn) parseComStmtReset(data []byte) (uint32, bool) {
 	return val, ok
 }
 
+func (c *Conn) parseComStmtFetch(data []byte) (uint32, uint32, bool) {
+	stmtID, pos, ok := readUint32(data, 1)
+	if !ok {
+		return 0, 0, false
+	}
+	numRows, _, ok := readUint32(data, pos)
+	return stmtID, numRows, ok
+}
+
 func (c *Conn) parseComInitDB(data []byte) string {
 	return string(data[1:])
 }
@@ -1013,6 +1022,23 @@ func (c *Conn) writeRow(row []sqltypes.Value) error {
 // writeFields writes the fields of a Result. It should be called only
 // if there are valid columns in the result.
 func (c *Conn) writeFields(result *sqltypes.Result) error {
+	if err := c.writeColumnDefinitions(result); err != nil {
+		return err
+	}
+
+	// Now send an EOF packet.
+	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
+		// With CapabilityClientDeprecateEOF, we do not send this EOF.
+		if err := c.writeEOFPacket(c.StatusFlags, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeColumnDefinitions writes the number of fields of a Result
+// and their definitions, without the EOF packet that follows them.
+func (c *Conn) writeColumnDefinitions(result *sqltypes.Result) error {
 	// Send the number of fields first.
 	if err := c.sendColumnCount(uint64(len(result.Fields))); err != nil {
 		return err
@@ -1024,14 +1050,6 @@ func (c *Conn) writeFields(result *sqltypes.Result) error {
 			return err
 		}
 	}
-
-	// Now send an EOF packet.
-	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
-		// With CapabilityClientDeprecateEOF, we do not send this EOF.
-		if err := c.writeEOFPacket(c.StatusFlags, 0); err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
@@ -1073,6 +1091,19 @@ func (c *Conn) writeEndResult(more bool, affectedRows, lastInsertID uint64, warn
 	return nil
 }
 
+// writeCursorStatus concludes a response about a cursor with an EOF,
+// or an OK packet, that carries the status flags of the cursor.
+func (c *Conn) writeCursorStatus(cursorFlags uint16, warnings uint16) error {
+	flags := c.StatusFlags | cursorFlags
+	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
+		return c.writeEOFPacket(flags, warnings)
+	}
+	return c.writeOKPacketWithEOFHeader(&PacketOK{
+		statusFlags: flags,
+		warnings:    warnings,
+	})
+}
+
 // PacketComStmtPrepareOK contains the COM_STMT_PREPARE_OK packet details
 type PacketComStmtPrepareOK struct {
 	status       uint8
@@ -112,7 +112,12 @@ type Handler interface {
 	ComPrepare(c *Conn, query string, bindVars map[string]*querypb.BindVariable) ([]*querypb.Field, error)
 
 	// ComStmtExecute is called when a connection receives a statement
-	// execute query.
+	// execute query. When the client asks for a read-only cursor, the
+	// CursorType of the statement has CursorTypeReadOnly set: the callback
+	// then blocks until the client fetches the rows of the result, so the
+	// handler should stream them rather than buffer them. It is still
+	// guaranteed to return before any other method is called, but
+	// WarningCount.
 	ComStmtExecute(c *Conn, prepare *PrepareData, callback func(*sqltypes.Result) error) error
 
 	// ComRegisterReplica is called when a connection receives a ComRegisterReplica request
@@ -207,6 +212,15 @@ type Listener struct {
 	// RequireSecureTransport configures the server to reject connections from insecure clients
 	RequireSecureTransport bool
 
+	// CompressionAlgorithms are the algorithms of the compressed protocol
+	// the server advertises. The protocol is never compressed if it is empty.
+	// zstd is preferred to zlib when the client supports both.
+	CompressionAlgorithms []CompressionAlgorithm
+
+	// ZlibCompressionLevel is the level of the zlib compressed protocol.
+	// The zstd level is chosen by the client.
+	ZlibCompressionLevel int
+
 	// PreHandleFunc is called for each incoming connection, immediately after
 	// accepting a new connection. By default it's no-op. Useful for custom
 	// connection inspection or TLS termination. The returned connection is
@@ -386,12 +400,14 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	// Tell the handler about the connection coming and going.
 	l.handler.NewConnection(c)
 	defer l.handler.ConnectionClosed(c)
+	// Stop the handler of the open cursors before.
+	defer c.closeCursors()
 
 	// Adjust the count of open connections
 	defer connCount.Add(-1)
 
 	// First build and send the server handshake packet.
-	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil)
+	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil, l.compressionCapabilities())
 	if err != nil {
 		if err != io.EOF {
 			log.Errorf("Cannot send HandshakeV10 packet to %s: %v", c, err)
@@ -534,6 +550,13 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		return
 	}
 
+	// The packets following the OK packet are compressed, if the client
+	// asked for it.
+	if err := c.startCompression(); err != nil {
+		log.Errorf("Cannot start %s compression for %s: %v", c.compressionAlgorithm, c, err)
+		return
+	}
+
 	// Record how long we took to establish the connection
 	timings.Record(connectTimingKey, acceptTime)
 
@@ -570,9 +593,24 @@ func (l *Listener) Shutdown() {
 	}
 }
 
+// compressionCapabilities returns the capability flags of the
+// compression algorithms the server supports.
+func (l *Listener) compressionCapabilities() uint32 {
+	var capabilities uint32
+	for _, algorithm := range l.CompressionAlgorithms {
+		switch algorithm {
+		case CompressionZlib:
+			capabilities |= CapabilityClientCompress
+		case CompressionZstd:
+			capabilities |= CapabilityClientZstdCompressionAlgorithm
+		}
+	}
+	return capabilities
+}
+
 // writeHandshakeV10 writes the Initial Handshake Packet, server side.
 // It returns the salt data.
-func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool) ([]byte, error) {
+func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool, compression uint32) ([]byte, error) {
 	capabilities := CapabilityClientLongPassword |
 		CapabilityClientFoundRows |
 		CapabilityClientLongFlag |
@@ -589,6 +627,7 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 	if enableTLS {
 		capabilities |= CapabilityClientSSL
 	}
+	capabilities |= int(compression)
 
 	// Grab the default auth method. This can only be either
 	// mysql_native_password or caching_sha2_password. Both
@@ -805,9 +844,29 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 	}
 
 	// Decode connection attributes send by the client
+	connAttrsOK := true
 	if clientFlags&CapabilityClientConnAttr != 0 {
-		if _, _, err := parseConnAttrs(data, pos); err != nil {
+		var err error
+		if _, pos, err = parseConnAttrs(data, pos); err != nil {
 			log.Warningf("Decode connection attributes send by the client: %v", err)
+			connAttrsOK = false
+		}
+	}
+
+	// Compression, preferring zstd to zlib. The zstd level comes last.
+	compression := l.compressionCapabilities()
+	switch {
+	case clientFlags&compression&CapabilityClientZstdCompressionAlgorithm != 0:
+		c.compressionAlgorithm = CompressionZstd
+		c.compressionLevel = DefaultZstdCompressionLevel
+		if level, _, ok := readByte(data, pos); ok && connAttrsOK && validCompressionLevel(CompressionZstd, int(level)) {
+			c.compressionLevel = int(level)
+		}
+	case clientFlags&compression&CapabilityClientCompress != 0:
+		c.compressionAlgorithm = CompressionZlib
+		c.compressionLevel = l.ZlibCompressionLevel
+		if !validCompressionLevel(CompressionZlib, c.compressionLevel) {
+			c.compressionLevel = DefaultZlibCompressionLevel
 		}
 	}
 
@@ -37,6 +37,10 @@ var (
 	// ErrCtxTimeout is returned if a ctx is already expired by the time the connection pool is used
 	ErrCtxTimeout = vterrors.New(vtrpcpb.Code_DEADLINE_EXCEEDED, "connection pool context already expired")
 
+	// ErrShed is returned when the pool has too many clients waiting for a connection, and the
+	// client has been shed to make room for clients with a higher priority
+	ErrShed = vterrors.New(vtrpcpb.Code_RESOURCE_EXHAUSTED, "connection pool has too many waiters: request shed in favor of higher priority requests")
+
 	// ErrConnPoolClosed is returned when trying to get a connection from a closed conn pool
 	ErrConnPoolClosed = vterrors.New(vtrpcpb.Code_INTERNAL, "connection pool is closed")
 
@@ -53,6 +57,7 @@ type Metrics struct {
 	idleClosed           atomic.Int64
 	diffSetting          atomic.Int64
 	resetSetting         atomic.Int64
+	shedCount            atomic.Int64
 }
 
 func (m *Metrics) MaxLifetimeClosed() int64 {
@@ -87,6 +92,10 @@ func (m *Metrics) ResetSettingCount() int64 {
 	return m.resetSetting.Load()
 }
 
+func (m *Metrics) ShedCount() int64 {
+	return m.shedCount.Load()
+}
+
 type Connector[C Connection] func(ctx context.Context) (C, error)
 type RefreshCheck func() (bool, error)
 
@@ -96,6 +105,9 @@ type Config[C Connection] struct {
 	MaxLifetime     time.Duration
 	RefreshInterval time.Duration
 	LogWait         func(time.Time)
+	// MaxWaiters is the maximum number of clients waiting for a connection; when it's
+	// reached, the clients with the lowest priority are shed. 0 means no limit.
+	MaxWaiters int
 }
 
 // stackMask is the number of connection setting stacks minus one;
@@ -162,7 +174,7 @@ func NewPool[C Connection](config *Config[C]) *ConnPool[C] {
 	pool.config.idleTimeout.Store(config.IdleTimeout.Nanoseconds())
 	pool.config.refreshInterval.Store(config.RefreshInterval.Nanoseconds())
 	pool.config.logWait = config.LogWait
-	pool.wait.init()
+	pool.wait.init(config.MaxWaiters)
 
 	return pool
 }
@@ -352,9 +364,20 @@ func (pool *ConnPool[C]) recordWait(start time.Time) {
 	}
 }
 
+// waitError returns the error for a client that failed to wait for a connection
+func (pool *ConnPool[C]) waitError(err error) error {
+	if err == ErrShed {
+		pool.Metrics.shedCount.Add(1)
+		return ErrShed
+	}
+	return ErrTimeout
+}
+
 // Get returns a connection from the pool with the given Setting applied.
 // If there are no connections in the pool to be returned, Get blocks until one
-// is returned, or until the given ctx is cancelled.
+// is returned, or until the given ctx is cancelled. The clients waiting for
+// a connection are served according to the priority in their ctx, see
+// NewContextWithPriority.
 // The connection must be returned to the pool once it's not needed by calling Pooled.Recycle
 func (pool *ConnPool[C]) Get(ctx context.Context, setting *Setting) (*Pooled[C], error) {
 	if ctx.Err() != nil {
@@ -510,7 +533,7 @@ func (pool *ConnPool[C]) get(ctx context.Context) (*Pooled[C], error) {
 		start := time.Now()
 		conn, err = pool.wait.waitForConn(ctx, nil)
 		if err != nil {
-			return nil, ErrTimeout
+			return nil, pool.waitError(err)
 		}
 		pool.recordWait(start)
 	}
@@ -567,7 +590,7 @@ func (pool *ConnPool[C]) getWithSetting(ctx context.Context, setting *Setting) (
 		start := time.Now()
 		conn, err = pool.wait.waitForConn(ctx, setting)
 		if err != nil {
-			return nil, ErrTimeout
+			return nil, pool.waitError(err)
 		}
 		pool.recordWait(start)
 	}
@@ -759,4 +782,10 @@ func (pool *ConnPool[C]) RegisterStats(stats *servenv.Exporter, name string) {
 	stats.NewCounterFunc(name+"ResetSetting", "Number of times pool reset the setting", func() int64 {
 		return pool.Metrics.ResetSettingCount()
 	})
+	stats.NewCounterFunc(name+"ShedCount", "Tablet server conn pool requests shed in favor of higher priority requests", func() int64 {
+		return pool.Metrics.ShedCount()
+	})
+	stats.NewGaugeFunc(name+"Waiting", "Tablet server conn pool requests waiting for a connection", func() int64 {
+		return int64(pool.wait.waiting())
+	})
 }
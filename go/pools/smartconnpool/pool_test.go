@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -1080,3 +1081,155 @@ func TestApplySettingsFailure(t *testing.T) {
 		p.put(r)
 	}
 }
+
+// waitInOrder starts a client waiting for a connection for each of the given priorities,
+// one after the other, and returns the order in which they are served. The clients return
+// their connection right away.
+func waitInOrder(t *testing.T, p *ConnPool[*TestConn], priorities []int) <-chan int {
+	served := make(chan int, len(priorities))
+	for i, priority := range priorities {
+		waiting := p.wait.waiting()
+		go func() {
+			ctx := NewContextWithPriority(context.Background(), priority)
+			conn, err := p.Get(ctx, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			served <- i
+			conn.Recycle()
+		}()
+		require.Eventually(t, func() bool {
+			return p.wait.waiting() == waiting+1
+		}, time.Second, time.Millisecond)
+	}
+	return served
+}
+
+func TestPriority(t *testing.T) {
+	var state TestState
+
+	ctx := context.Background()
+	p := NewPool(&Config[*TestConn]{
+		Capacity:    1,
+		IdleTimeout: time.Second,
+	}).Open(newConnector(&state), nil)
+	defer p.Close()
+
+	conn, err := p.Get(ctx, nil)
+	require.NoError(t, err)
+
+	// a client with the lowest priority waits first, then many clients with the highest priority
+	priorities := []int{MaxPriority}
+	for i := 0; i < 150; i++ {
+		priorities = append(priorities, 0)
+	}
+	served := waitInOrder(t, p, priorities)
+	conn.Recycle()
+
+	var order []int
+	for range priorities {
+		order = append(order, <-served)
+	}
+	// the high priority clients are served first, but the low priority client doesn't starve:
+	// it's served after 101 of them because of their relative weights
+	assert.Equal(t, 0, order[101])
+	for i, served := range order {
+		if i != 101 {
+			assert.NotZero(t, served)
+		}
+	}
+}
+
+func TestPriorityExpiredWaiters(t *testing.T) {
+	var state TestState
+
+	ctx := context.Background()
+	p := NewPool(&Config[*TestConn]{
+		Capacity:    1,
+		IdleTimeout: time.Second,
+	}).Open(newConnector(&state), nil)
+	defer p.Close()
+
+	conn, err := p.Get(ctx, nil)
+	require.NoError(t, err)
+
+	// a burst of clients with the lowest priority time out while waiting
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(NewContextWithPriority(ctx, MaxPriority), 10*time.Millisecond)
+			defer cancel()
+			_, err := p.Get(ctx, nil)
+			assert.ErrorIs(t, err, ErrTimeout)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		p.wait.expire(false)
+		return p.wait.waiting() == 0
+	}, time.Second, time.Millisecond)
+	wg.Wait()
+
+	// the next client with the lowest priority still gets its share amongst the high priority
+	// clients: it isn't charged for the waiters that left without a connection
+	priorities := []int{MaxPriority}
+	for i := 0; i < 150; i++ {
+		priorities = append(priorities, 0)
+	}
+	served := waitInOrder(t, p, priorities)
+	conn.Recycle()
+
+	var order []int
+	for range priorities {
+		order = append(order, <-served)
+	}
+	assert.Equal(t, 0, order[101])
+}
+
+func TestShed(t *testing.T) {
+	var state TestState
+
+	ctx := context.Background()
+	p := NewPool(&Config[*TestConn]{
+		Capacity:    1,
+		IdleTimeout: time.Second,
+		MaxWaiters:  2,
+	}).Open(newConnector(&state), nil)
+	defer p.Close()
+
+	conn, err := p.Get(ctx, nil)
+	require.NoError(t, err)
+
+	shed := make(chan error, 1)
+	go func() {
+		_, err := p.Get(NewContextWithPriority(ctx, MaxPriority), nil)
+		shed <- err
+	}()
+	require.Eventually(t, func() bool {
+		return p.wait.waiting() == 1
+	}, time.Second, time.Millisecond)
+	waitInOrder(t, p, []int{DefaultPriority})
+
+	// the waitlist is full, and the new client doesn't have a higher priority than any waiter
+	_, err = p.Get(NewContextWithPriority(ctx, MaxPriority), nil)
+	assert.ErrorIs(t, err, ErrShed)
+
+	// a client with a higher priority takes the place of the lowest priority waiter
+	served := make(chan struct{})
+	go func() {
+		conn, err := p.Get(NewContextWithPriority(ctx, 0), nil)
+		if assert.NoError(t, err) {
+			close(served)
+			conn.Recycle()
+		}
+	}()
+	assert.ErrorIs(t, <-shed, ErrShed)
+	assert.EqualValues(t, 2, p.Metrics.ShedCount())
+
+	conn.Recycle()
+	<-served
+	require.Eventually(t, func() bool {
+		return p.wait.waiting() == 0 && p.InUse() == 0
+	}, time.Second, time.Millisecond)
+}
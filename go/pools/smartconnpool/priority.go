@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smartconnpool
+
+import "context"
+
+const (
+	// MaxPriority is the lowest priority a client of the pool can have; 0 is the highest
+	// priority. This is the same range as the PRIORITY query directive.
+	MaxPriority = 100
+
+	// DefaultPriority is the priority of the clients whose context carries no priority
+	DefaultPriority = MaxPriority / 2
+
+	// priorityStrideScale is the virtual time it takes to serve a waiter of weight 1,
+	// i.e. a waiter with MaxPriority. See waitlist.enqueue.
+	priorityStrideScale = 1 << 20
+)
+
+type priorityKey struct{}
+
+// NewContextWithPriority returns a context carrying the given priority. When the pool is
+// exhausted, the clients waiting for a connection are served by priority with weighted
+// fairness, and the clients with the lowest priority are shed first.
+func NewContextWithPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, priorityKey{}, clampPriority(priority))
+}
+
+// PriorityFromContext returns the priority carried by the context, or DefaultPriority
+// if there's none
+func PriorityFromContext(ctx context.Context) int {
+	if priority, ok := ctx.Value(priorityKey{}).(int); ok {
+		return priority
+	}
+	return DefaultPriority
+}
+
+func clampPriority(priority int) int {
+	return min(max(priority, 0), MaxPriority)
+}
+
+// priorityStride returns how much virtual time serving a waiter with the given priority
+// takes: the weight of a waiter is inversely proportional to its priority value, so that
+// under contention the waiters with priority 0 get 101 connections for each one given
+// to the waiters with MaxPriority.
+func priorityStride(priority int) uint64 {
+	weight := uint64(MaxPriority + 1 - priority)
+	return priorityStrideScale / weight
+}
@@ -37,12 +37,35 @@ type waiter[C Connection] struct {
 	sema semaphore
 	// age is the amount of cycles this client has been on the waitlist
 	age uint32
+	// shed is set when the client has been removed from the waitlist to make room
+	// for a client with a higher priority
+	shed bool
+	// priority is the priority of the client, from 0 (highest) to MaxPriority (lowest)
+	priority int
+	// tag is the virtual time at which the client should be served; the waitlist
+	// is sorted by tag
+	tag uint64
 }
 
 type waitlist[C Connection] struct {
 	nodes sync.Pool
 	mu    sync.Mutex
 	list  list.List[waiter[C]]
+
+	// maxWaiters is the maximum number of clients in the waitlist, or 0 if there's no limit
+	maxWaiters int
+	// sched is the scheduling state of the waitlist. It's allocated separately to keep the
+	// ConnPool within 512 bytes: larger allocations are not 16-byte aligned, which the atomic
+	// operations of its connStacks require.
+	sched *waitlistSched
+}
+
+// waitlistSched is the state of the weighted fair scheduling of the waiters
+type waitlistSched struct {
+	// vtime is the virtual time of the waitlist: the tag of the last client that was served
+	vtime uint64
+	// lastTag is the tag of the last client of each priority that joined the waitlist
+	lastTag [MaxPriority + 1]uint64
 }
 
 // waitForConn blocks until a connection with the given Setting is returned by another client,
@@ -50,15 +73,35 @@ type waitlist[C Connection] struct {
 // The returned connection may _not_ have the requested Setting. This function can
 // also return a `nil` connection even if our context has expired, if the pool has
 // forced an expiration of all waiters in the waitlist.
+// If the waitlist is full, either this client or the waiter with the lowest priority is
+// shed, and ErrShed is returned to it.
 func (wl *waitlist[C]) waitForConn(ctx context.Context, setting *Setting) (*Pooled[C], error) {
 	elem := wl.nodes.Get().(*list.Element[waiter[C]])
-	elem.Value = waiter[C]{setting: setting, conn: nil, ctx: ctx}
+	elem.Value = waiter[C]{setting: setting, conn: nil, ctx: ctx, priority: PriorityFromContext(ctx)}
+
+	var shed *list.Element[waiter[C]]
 
 	wl.mu.Lock()
-	// add ourselves as a waiter at the end of the waitlist
-	wl.list.PushBackValue(elem)
+	if wl.maxWaiters > 0 && wl.list.Len() >= wl.maxWaiters {
+		// the waitlist is full: make room by shedding the waiter with the lowest priority,
+		// unless we don't have a higher priority than that waiter ourselves
+		shed = wl.lowestPriority()
+		if shed == nil || shed.Value.priority <= elem.Value.priority {
+			wl.mu.Unlock()
+			wl.nodes.Put(elem)
+			return nil, ErrShed
+		}
+		wl.list.Remove(shed)
+		shed.Value.shed = true
+		wl.resetLastTag(shed.Value.priority)
+	}
+	wl.enqueue(elem)
 	wl.mu.Unlock()
 
+	if shed != nil {
+		shed.Value.sema.notify(false)
+	}
+
 	// block on our waiter's semaphore until somebody can hand over a connection to us
 	elem.Value.sema.wait()
 
@@ -66,14 +109,73 @@ func (wl *waitlist[C]) waitForConn(ctx context.Context, setting *Setting) (*Pool
 	// over to us, or nothing if we've been waken up forcefully. save the conn before
 	// we return our waiter to the pool of waiters for reuse.
 	conn := elem.Value.conn
+	wasShed := elem.Value.shed
 	wl.nodes.Put(elem)
 
 	if conn != nil {
 		return conn, nil
 	}
+	if wasShed {
+		return nil, ErrShed
+	}
 	return nil, ctx.Err()
 }
 
+// enqueue adds a waiter to the waitlist. This must be called with wl.mu held.
+//
+// The waiters are served with weighted fairness by stride scheduling: each waiter is tagged
+// with the virtual time at which it should be served, which is one stride after the previous
+// waiter of its priority, or after the current virtual time of the waitlist if there's none.
+// The waiters with a higher priority have a shorter stride, so they get most of the
+// connections, but the waiters with a lower priority still get their share and don't starve.
+// Waiters of a single priority are served in FIFO order. When a waiter leaves the waitlist
+// without a connection, its priority is no longer charged for it: see resetLastTag.
+func (wl *waitlist[C]) enqueue(elem *list.Element[waiter[C]]) {
+	w := &elem.Value
+	w.tag = max(wl.sched.vtime, wl.sched.lastTag[w.priority]) + priorityStride(w.priority)
+	wl.sched.lastTag[w.priority] = w.tag
+
+	// waiters are usually appended at the back of the waitlist, so look for our
+	// position starting from there
+	e := wl.list.Back()
+	for e != nil && e.Value.tag > w.tag {
+		e = e.Prev()
+	}
+	if e == nil {
+		wl.list.PushFrontValue(elem)
+	} else {
+		wl.list.InsertAfterValue(elem, e)
+	}
+}
+
+// resetLastTag recomputes the tag of the last waiter of the given priority after some of its
+// waiters have been removed from the waitlist without being served, so that the waiters joining
+// later don't queue behind them. This must be called with wl.mu held.
+func (wl *waitlist[C]) resetLastTag(priority int) {
+	// the waitlist is sorted by tag, so the last waiter of this priority has its highest tag;
+	// the waiters that have been served already have tags that are behind the virtual time
+	var last uint64
+	for e := wl.list.Back(); e != nil; e = e.Prev() {
+		if e.Value.priority == priority {
+			last = e.Value.tag
+			break
+		}
+	}
+	wl.sched.lastTag[priority] = last
+}
+
+// lowestPriority returns the waiter with the lowest priority, and the one that would be served
+// last amongst those. This must be called with wl.mu held.
+func (wl *waitlist[C]) lowestPriority() *list.Element[waiter[C]] {
+	var lowest *list.Element[waiter[C]]
+	for e := wl.list.Back(); e != nil; e = e.Prev() {
+		if lowest == nil || e.Value.priority > lowest.Value.priority {
+			lowest = e
+		}
+	}
+	return lowest
+}
+
 // expire removes and wakes any expired waiter in the waitlist.
 // if force is true, it'll wake and remove all the waiters.
 func (wl *waitlist[C]) expire(force bool) {
@@ -81,7 +183,10 @@ func (wl *waitlist[C]) expire(force bool) {
 		return
 	}
 
-	var expired []*list.Element[waiter[C]]
+	var (
+		expired    []*list.Element[waiter[C]]
+		priorities [MaxPriority + 1]bool
+	)
 
 	wl.mu.Lock()
 	// iterate the waitlist looking for waiters with an expired Context,
@@ -90,9 +195,15 @@ func (wl *waitlist[C]) expire(force bool) {
 		if force || e.Value.ctx.Err() != nil {
 			wl.list.Remove(e)
 			expired = append(expired, e)
+			priorities[e.Value.priority] = true
 			continue
 		}
 	}
+	for priority, removed := range priorities {
+		if removed {
+			wl.resetLastTag(priority)
+		}
+	}
 	wl.mu.Unlock()
 
 	// once all the expired waiters have been removed from the waitlist, wake them up one by one
@@ -122,9 +233,10 @@ func (wl *waitlist[D]) tryReturnConnSlow(conn *Pooled[D]) bool {
 	target = wl.list.Front()
 	// iterate through the waitlist looking for either waiters that have been
 	// here too long, or a waiter that is looking exactly for the same Setting
-	// as the one we have in our connection.
+	// as the one we have in our connection. waiters with a lower priority than
+	// the front of the waitlist cannot skip ahead for their Setting.
 	for e := target; e != nil; e = e.Next() {
-		if e.Value.age > maxAge || e.Value.setting == connSetting {
+		if e.Value.age > maxAge || (e.Value.setting == connSetting && e.Value.priority <= target.Value.priority) {
 			target = e
 			break
 		}
@@ -137,6 +249,7 @@ func (wl *waitlist[D]) tryReturnConnSlow(conn *Pooled[D]) bool {
 	}
 	if target != nil {
 		wl.list.Remove(target)
+		wl.sched.vtime = max(wl.sched.vtime, target.Value.tag)
 	}
 	wl.mu.Unlock()
 
@@ -154,7 +267,9 @@ func (wl *waitlist[D]) tryReturnConnSlow(conn *Pooled[D]) bool {
 	return true
 }
 
-func (wl *waitlist[C]) init() {
+func (wl *waitlist[C]) init(maxWaiters int) {
+	wl.maxWaiters = maxWaiters
+	wl.sched = &waitlistSched{}
 	wl.nodes.New = func() any {
 		return &list.Element[waiter[C]]{}
 	}
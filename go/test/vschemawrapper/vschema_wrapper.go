@@ -47,6 +47,7 @@ type VSchemaWrapper struct {
 	Dest                  key.Destination
 	SysVarEnabled         bool
 	ForeignKeyChecksState *bool
+	QueryFingerprint      string
 	Version               plancontext.PlannerVersion
 	EnableViews           bool
 	TestBuilder           func(query string, vschema plancontext.VSchema, keyspace string) (*engine.Plan, error)
@@ -155,6 +156,10 @@ func (vw *VSchemaWrapper) GetForeignKeyChecksState() *bool {
 	return vw.ForeignKeyChecksState
 }
 
+func (vw *VSchemaWrapper) GetQueryFingerprint() string {
+	return vw.QueryFingerprint
+}
+
 func (vw *VSchemaWrapper) AllKeyspace() ([]*vindexes.Keyspace, error) {
 	if vw.Keyspace == nil {
 		return nil, vterrors.VT13001("keyspace not available")
@@ -3016,6 +3016,27 @@ type (
 		Arg Expr
 	}
 
+	// GroupingFunc represents GROUPING(), which tells if the grouping columns
+	// passed to it are part of a super-aggregate row produced by WITH ROLLUP.
+	// It is treated as an aggregation, since it can only be evaluated together with the grouping.
+	// see https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_grouping
+	GroupingFunc struct {
+		Exprs Exprs
+	}
+
+	// JSONArrayAgg represents JSON_ARRAYAGG(), which aggregates a result set as a single JSON array.
+	// see https://dev.mysql.com/doc/refman/8.0/en/aggregate-functions.html#function_json-arrayagg
+	JSONArrayAgg struct {
+		Arg Expr
+	}
+
+	// JSONObjectAgg represents JSON_OBJECTAGG(), which aggregates key-value pairs as a single JSON object.
+	// see https://dev.mysql.com/doc/refman/8.0/en/aggregate-functions.html#function_json-objectagg
+	JSONObjectAgg struct {
+		Key   Expr
+		Value Expr
+	}
+
 	// RegexpInstrExpr represents REGEXP_INSTR()
 	// For more information, see https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
 	RegexpInstrExpr struct {
@@ -3257,6 +3278,9 @@ func (*CountStar) IsExpr()                          {}
 func (*Count) IsExpr()                              {}
 func (*GroupConcatExpr) IsExpr()                    {}
 func (*AnyValue) IsExpr()                           {}
+func (*GroupingFunc) IsExpr()                       {}
+func (*JSONArrayAgg) IsExpr()                       {}
+func (*JSONObjectAgg) IsExpr()                      {}
 func (*BitAnd) IsExpr()                             {}
 func (*BitOr) IsExpr()                              {}
 func (*BitXor) IsExpr()                             {}
@@ -3306,6 +3330,9 @@ func (*ConvertUsingExpr) iCallable()                   {}
 func (*MatchExpr) iCallable()                          {}
 func (*GroupConcatExpr) iCallable()                    {}
 func (*AnyValue) iCallable()                           {}
+func (*GroupingFunc) iCallable()                       {}
+func (*JSONArrayAgg) iCallable()                       {}
+func (*JSONObjectAgg) iCallable()                      {}
 func (*JSONSchemaValidFuncExpr) iCallable()            {}
 func (*JSONSchemaValidationReportFuncExpr) iCallable() {}
 func (*JSONPrettyExpr) iCallable()                     {}
@@ -3387,6 +3414,9 @@ func (varP *VarPop) GetArg() Expr               { return varP.Arg }
 func (varS *VarSamp) GetArg() Expr              { return varS.Arg }
 func (variance *Variance) GetArg() Expr         { return variance.Arg }
 func (av *AnyValue) GetArg() Expr               { return av.Arg }
+func (grp *GroupingFunc) GetArg() Expr          { return grp.Exprs[0] }
+func (agg *JSONArrayAgg) GetArg() Expr          { return agg.Arg }
+func (agg *JSONObjectAgg) GetArg() Expr         { return agg.Key }
 
 func (sum *Sum) GetArgs() Exprs                   { return Exprs{sum.Arg} }
 func (min *Min) GetArgs() Exprs                   { return Exprs{min.Arg} }
@@ -3406,6 +3436,9 @@ func (varP *VarPop) GetArgs() Exprs               { return Exprs{varP.Arg} }
 func (varS *VarSamp) GetArgs() Exprs              { return Exprs{varS.Arg} }
 func (variance *Variance) GetArgs() Exprs         { return Exprs{variance.Arg} }
 func (av *AnyValue) GetArgs() Exprs               { return Exprs{av.Arg} }
+func (grp *GroupingFunc) GetArgs() Exprs          { return grp.Exprs }
+func (agg *JSONArrayAgg) GetArgs() Exprs          { return Exprs{agg.Arg} }
+func (agg *JSONObjectAgg) GetArgs() Exprs         { return Exprs{agg.Key, agg.Value} }
 
 func (min *Min) SetArg(expr Expr)                   { min.Arg = expr }
 func (sum *Sum) SetArg(expr Expr)                   { sum.Arg = expr }
@@ -3425,6 +3458,9 @@ func (varP *VarPop) SetArg(expr Expr)               { varP.Arg = expr }
 func (varS *VarSamp) SetArg(expr Expr)              { varS.Arg = expr }
 func (variance *Variance) SetArg(expr Expr)         { variance.Arg = expr }
 func (av *AnyValue) SetArg(expr Expr)               { av.Arg = expr }
+func (grp *GroupingFunc) SetArg(expr Expr)          { grp.Exprs = Exprs{expr} }
+func (agg *JSONArrayAgg) SetArg(expr Expr)          { agg.Arg = expr }
+func (agg *JSONObjectAgg) SetArg(expr Expr)         { agg.Key = expr }
 
 func (min *Min) SetArgs(exprs Exprs) error           { return setFuncArgs(min, exprs, "MIN") }
 func (sum *Sum) SetArgs(exprs Exprs) error           { return setFuncArgs(sum, exprs, "SUM") }
@@ -3451,6 +3487,13 @@ func (grpConcat *GroupConcatExpr) SetArgs(exprs Exprs) error {
 	grpConcat.Exprs = exprs
 	return nil
 }
+func (grp *GroupingFunc) SetArgs(exprs Exprs) error {
+	grp.Exprs = exprs
+	return nil
+}
+func (agg *JSONArrayAgg) SetArgs(exprs Exprs) error {
+	return setFuncArgs(agg, exprs, "JSON_ARRAYAGG")
+}
 
 func (sum *Sum) IsDistinct() bool                   { return sum.Distinct }
 func (min *Min) IsDistinct() bool                   { return min.Distinct }
@@ -3484,6 +3527,9 @@ func (*VarPop) AggrName() string          { return "var_pop" }
 func (*VarSamp) AggrName() string         { return "var_samp" }
 func (*Variance) AggrName() string        { return "variance" }
 func (*AnyValue) AggrName() string        { return "any_value" }
+func (*GroupingFunc) AggrName() string    { return "grouping" }
+func (*JSONArrayAgg) AggrName() string    { return "json_arrayagg" }
+func (*JSONObjectAgg) AggrName() string   { return "json_objectagg" }
 
 // Exprs represents a list of value expressions.
 // It's not a valid expression because it's not parenthesized.
@@ -209,6 +209,8 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfGroupBy(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *GroupingFunc:
+		return CloneRefOfGroupingFunc(in)
 	case IdentifierCI:
 		return CloneIdentifierCI(in)
 	case IdentifierCS:
@@ -233,6 +235,8 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfIntroducerExpr(in)
 	case *IsExpr:
 		return CloneRefOfIsExpr(in)
+	case *JSONArrayAgg:
+		return CloneRefOfJSONArrayAgg(in)
 	case *JSONArrayExpr:
 		return CloneRefOfJSONArrayExpr(in)
 	case *JSONAttributesExpr:
@@ -245,6 +249,8 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfJSONExtractExpr(in)
 	case *JSONKeysExpr:
 		return CloneRefOfJSONKeysExpr(in)
+	case *JSONObjectAgg:
+		return CloneRefOfJSONObjectAgg(in)
 	case *JSONObjectExpr:
 		return CloneRefOfJSONObjectExpr(in)
 	case *JSONObjectParam:
@@ -1566,6 +1572,16 @@ func CloneRefOfGroupConcatExpr(n *GroupConcatExpr) *GroupConcatExpr {
 	return &out
 }
 
+// CloneRefOfGroupingFunc creates a deep clone of the input.
+func CloneRefOfGroupingFunc(n *GroupingFunc) *GroupingFunc {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Exprs = CloneExprs(n.Exprs)
+	return &out
+}
+
 // CloneIdentifierCI creates a deep clone of the input.
 func CloneIdentifierCI(n IdentifierCI) IdentifierCI {
 	return *CloneRefOfIdentifierCI(&n)
@@ -1692,6 +1708,16 @@ func CloneRefOfIsExpr(n *IsExpr) *IsExpr {
 	return &out
 }
 
+// CloneRefOfJSONArrayAgg creates a deep clone of the input.
+func CloneRefOfJSONArrayAgg(n *JSONArrayAgg) *JSONArrayAgg {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Arg = CloneExpr(n.Arg)
+	return &out
+}
+
 // CloneRefOfJSONArrayExpr creates a deep clone of the input.
 func CloneRefOfJSONArrayExpr(n *JSONArrayExpr) *JSONArrayExpr {
 	if n == nil {
@@ -1759,6 +1785,17 @@ func CloneRefOfJSONKeysExpr(n *JSONKeysExpr) *JSONKeysExpr {
 	return &out
 }
 
+// CloneRefOfJSONObjectAgg creates a deep clone of the input.
+func CloneRefOfJSONObjectAgg(n *JSONObjectAgg) *JSONObjectAgg {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Key = CloneExpr(n.Key)
+	out.Value = CloneExpr(n.Value)
+	return &out
+}
+
 // CloneRefOfJSONObjectExpr creates a deep clone of the input.
 func CloneRefOfJSONObjectExpr(n *JSONObjectExpr) *JSONObjectExpr {
 	if n == nil {
@@ -3460,6 +3497,12 @@ func CloneAggrFunc(in AggrFunc) AggrFunc {
 		return CloneRefOfCountStar(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *GroupingFunc:
+		return CloneRefOfGroupingFunc(in)
+	case *JSONArrayAgg:
+		return CloneRefOfJSONArrayAgg(in)
+	case *JSONObjectAgg:
+		return CloneRefOfJSONObjectAgg(in)
 	case *Max:
 		return CloneRefOfMax(in)
 	case *Min:
@@ -3598,12 +3641,16 @@ func CloneCallable(in Callable) Callable {
 		return CloneRefOfGeomPropertyFuncExpr(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *GroupingFunc:
+		return CloneRefOfGroupingFunc(in)
 	case *InsertExpr:
 		return CloneRefOfInsertExpr(in)
 	case *IntervalDateExpr:
 		return CloneRefOfIntervalDateExpr(in)
 	case *IntervalFuncExpr:
 		return CloneRefOfIntervalFuncExpr(in)
+	case *JSONArrayAgg:
+		return CloneRefOfJSONArrayAgg(in)
 	case *JSONArrayExpr:
 		return CloneRefOfJSONArrayExpr(in)
 	case *JSONAttributesExpr:
@@ -3616,6 +3663,8 @@ func CloneCallable(in Callable) Callable {
 		return CloneRefOfJSONExtractExpr(in)
 	case *JSONKeysExpr:
 		return CloneRefOfJSONKeysExpr(in)
+	case *JSONObjectAgg:
+		return CloneRefOfJSONObjectAgg(in)
 	case *JSONObjectExpr:
 		return CloneRefOfJSONObjectExpr(in)
 	case *JSONOverlapsExpr:
@@ -3894,6 +3943,8 @@ func CloneExpr(in Expr) Expr {
 		return CloneRefOfGeomPropertyFuncExpr(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *GroupingFunc:
+		return CloneRefOfGroupingFunc(in)
 	case *InsertExpr:
 		return CloneRefOfInsertExpr(in)
 	case *IntervalDateExpr:
@@ -3904,6 +3955,8 @@ func CloneExpr(in Expr) Expr {
 		return CloneRefOfIntroducerExpr(in)
 	case *IsExpr:
 		return CloneRefOfIsExpr(in)
+	case *JSONArrayAgg:
+		return CloneRefOfJSONArrayAgg(in)
 	case *JSONArrayExpr:
 		return CloneRefOfJSONArrayExpr(in)
 	case *JSONAttributesExpr:
@@ -3916,6 +3969,8 @@ func CloneExpr(in Expr) Expr {
 		return CloneRefOfJSONExtractExpr(in)
 	case *JSONKeysExpr:
 		return CloneRefOfJSONKeysExpr(in)
+	case *JSONObjectAgg:
+		return CloneRefOfJSONObjectAgg(in)
 	case *JSONObjectExpr:
 		return CloneRefOfJSONObjectExpr(in)
 	case *JSONOverlapsExpr:
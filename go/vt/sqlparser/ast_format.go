@@ -2752,6 +2752,18 @@ func (node *AnyValue) Format(buf *TrackedBuffer) {
 	buf.astPrintf(node, "any_value(%v)", node.Arg)
 }
 
+func (node *GroupingFunc) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "grouping(%v)", node.Exprs)
+}
+
+func (node *JSONArrayAgg) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "json_arrayagg(%v)", node.Arg)
+}
+
+func (node *JSONObjectAgg) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "json_objectagg(%v, %v)", node.Key, node.Value)
+}
+
 func (node *Avg) Format(buf *TrackedBuffer) {
 	buf.WriteString("avg(")
 	if node.Distinct {
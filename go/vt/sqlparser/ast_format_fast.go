@@ -3628,6 +3628,26 @@ func (node *AnyValue) FormatFast(buf *TrackedBuffer) {
 	buf.WriteByte(')')
 }
 
+func (node *GroupingFunc) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("grouping(")
+	node.Exprs.FormatFast(buf)
+	buf.WriteByte(')')
+}
+
+func (node *JSONArrayAgg) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("json_arrayagg(")
+	buf.printExpr(node, node.Arg, true)
+	buf.WriteByte(')')
+}
+
+func (node *JSONObjectAgg) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("json_objectagg(")
+	buf.printExpr(node, node.Key, true)
+	buf.WriteString(", ")
+	buf.printExpr(node, node.Value, true)
+	buf.WriteByte(')')
+}
+
 func (node *Avg) FormatFast(buf *TrackedBuffer) {
 	buf.WriteString("avg(")
 	if node.Distinct {
@@ -2175,6 +2175,11 @@ func ContainsAggregation(e SQLNode) bool {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case AggrFunc:
+			if IsWindowFunc(node) {
+				// aggregate functions with an OVER clause are window functions,
+				// but their arguments can still contain aggregations
+				return true, nil
+			}
 			hasAggregates = true
 			return false, io.EOF
 		}
@@ -2193,6 +2198,16 @@ func setFuncArgs(aggr AggrFunc, exprs Exprs, name string) error {
 	return nil
 }
 
+// SetArgs sets both the key and the value of JSON_OBJECTAGG, which is the only
+// aggregation that always takes two arguments.
+func (agg *JSONObjectAgg) SetArgs(exprs Exprs) error {
+	if len(exprs) != 2 {
+		return vterrors.VT13001(fmt.Sprintf("JSON_OBJECTAGG takes two arguments, got %d", len(exprs)))
+	}
+	agg.Key, agg.Value = exprs[0], exprs[1]
+	return nil
+}
+
 // GetFirstSelect gets the first select statement
 func GetFirstSelect(selStmt SelectStatement) *Select {
 	if selStmt == nil {
@@ -2790,3 +2805,131 @@ func (lock Lock) GetHighestOrderLock(newLock Lock) Lock {
 func Clone[K SQLNode](x K) K {
 	return CloneSQLNode(x).(K)
 }
+
+// GetOverClause returns the OVER clause of the given expression if it is being used as a window function.
+// It returns nil for anything that is not a window function.
+func GetOverClause(e SQLNode) *OverClause {
+	switch node := e.(type) {
+	case *Count:
+		return node.OverClause
+	case *CountStar:
+		return node.OverClause
+	case *Avg:
+		return node.OverClause
+	case *Max:
+		return node.OverClause
+	case *Min:
+		return node.OverClause
+	case *Sum:
+		return node.OverClause
+	case *BitAnd:
+		return node.OverClause
+	case *BitOr:
+		return node.OverClause
+	case *BitXor:
+		return node.OverClause
+	case *Std:
+		return node.OverClause
+	case *StdDev:
+		return node.OverClause
+	case *StdPop:
+		return node.OverClause
+	case *StdSamp:
+		return node.OverClause
+	case *VarPop:
+		return node.OverClause
+	case *VarSamp:
+		return node.OverClause
+	case *Variance:
+		return node.OverClause
+	case *ArgumentLessWindowExpr:
+		return node.OverClause
+	case *FirstOrLastValueExpr:
+		return node.OverClause
+	case *NtileExpr:
+		return node.OverClause
+	case *NTHValueExpr:
+		return node.OverClause
+	case *LagLeadExpr:
+		return node.OverClause
+	}
+	return nil
+}
+
+// IsWindowFunc returns true if the given expression is a window function, i.e. it has an OVER clause
+func IsWindowFunc(e SQLNode) bool {
+	return GetOverClause(e) != nil
+}
+
+// ContainsWindowFunc returns true if the expression contains a window function
+func ContainsWindowFunc(e SQLNode) bool {
+	hasWindow := false
+	_ = Walk(func(node SQLNode) (kontinue bool, err error) {
+		switch node.(type) {
+		case *Offset, *Subquery:
+			return false, nil
+		}
+		if IsWindowFunc(node) {
+			hasWindow = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, e)
+	return hasWindow
+}
+
+// ResolveWindowSpec returns the window specification used by the given OVER clause,
+// following references to named windows defined in the WINDOW clause of the query.
+// It returns false if a referenced window name could not be found.
+func ResolveWindowSpec(over *OverClause, windows NamedWindows) (*WindowSpecification, bool) {
+	if over.WindowSpec == nil {
+		return resolveNamedWindow(over.WindowName, windows, 0)
+	}
+	return resolveWindowSpec(over.WindowSpec, windows, 0)
+}
+
+func resolveWindowSpec(spec *WindowSpecification, windows NamedWindows, depth int) (*WindowSpecification, bool) {
+	if spec.Name.IsEmpty() {
+		return spec, true
+	}
+
+	// the window specification is building on top of a named window
+	base, found := resolveNamedWindow(spec.Name, windows, depth+1)
+	if !found {
+		return nil, false
+	}
+	resolved := &WindowSpecification{
+		PartitionClause: base.PartitionClause,
+		OrderClause:     base.OrderClause,
+		FrameClause:     base.FrameClause,
+	}
+	if len(spec.PartitionClause) > 0 {
+		resolved.PartitionClause = spec.PartitionClause
+	}
+	if len(spec.OrderClause) > 0 {
+		resolved.OrderClause = spec.OrderClause
+	}
+	if spec.FrameClause != nil {
+		resolved.FrameClause = spec.FrameClause
+	}
+	return resolved, true
+}
+
+func resolveNamedWindow(name IdentifierCI, windows NamedWindows, depth int) (*WindowSpecification, bool) {
+	definitions := 0
+	for _, nw := range windows {
+		definitions += len(nw.Windows)
+	}
+	if depth > definitions {
+		// the named windows are referencing each other in a cycle
+		return nil, false
+	}
+	for _, nw := range windows {
+		for _, def := range nw.Windows {
+			if def.Name.Equal(name) {
+				return resolveWindowSpec(def.WindowSpec, windows, depth)
+			}
+		}
+	}
+	return nil, false
+}
@@ -58,6 +58,8 @@ const (
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
+	// DirectiveResultCache lets vtgate cache the results of a read-only query.
+	DirectiveResultCache = "RESULT_CACHE"
 
 	// MaxPriorityValue specifies the maximum value allowed for the priority query directive. Valid priority values are
 	// between zero and MaxPriorityValue.
@@ -554,6 +556,11 @@ func AllowScatterDirective(stmt Statement) bool {
 	return checkDirective(stmt, DirectiveAllowScatter)
 }
 
+// ResultCacheDirective returns true if the result cache directive is set to true in query.
+func ResultCacheDirective(stmt Statement) bool {
+	return checkDirective(stmt, DirectiveResultCache)
+}
+
 // ForeignKeyChecksState returns the state of foreign_key_checks variable if it is part of a SET_VAR optimizer hint in the comments.
 func ForeignKeyChecksState(stmt Statement) *bool {
 	cmt, ok := stmt.(Commented)
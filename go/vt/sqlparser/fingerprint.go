@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlparser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+// Fingerprint returns an identifier for the shape of the given statement.
+// It is computed from a normalized copy of the statement without its comments,
+// where every bind variable is anonymous, so statements that only differ in
+// their literal values, the names of their bind variables or the length of
+// their IN lists share a fingerprint.
+func Fingerprint(stmt Statement) string {
+	stmt = CloneStatement(stmt)
+	if cmt, ok := stmt.(Commented); ok {
+		cmt.SetComments(nil)
+	}
+	// literals that can't be normalized are part of the fingerprint
+	_ = Normalize(stmt, NewReservedVars("fp", BindVars{}), map[string]*querypb.BindVariable{})
+	stmt = Rewrite(stmt, func(cursor *Cursor) bool {
+		switch cursor.Node().(type) {
+		case *Argument:
+			cursor.Replace(NewArgument("?"))
+		case ListArg:
+			cursor.Replace(ListArg("?"))
+		}
+		return true
+	}, nil).(Statement)
+
+	sum := sha256.Sum256([]byte(CanonicalString(stmt)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// FingerprintQuery parses the given query and returns its Fingerprint.
+func (p *Parser) FingerprintQuery(sql string) (string, error) {
+	stmt, err := p.Parse(sql)
+	if err != nil {
+		return "", err
+	}
+	return Fingerprint(stmt), nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+func TestFingerprint(t *testing.T) {
+	parser := NewTestParser()
+	fingerprint := func(sql string) string {
+		fp, err := parser.FingerprintQuery(sql)
+		require.NoError(t, err)
+		return fp
+	}
+
+	base := fingerprint("select a from t where x = 1 and y in (1, 2)")
+	assert.Len(t, base, 16)
+
+	same := []string{
+		"select a from t where x = 2 and y in (3)",
+		"SELECT a FROM t WHERE x = 'abc' AND y IN (4, 5, 6)",
+		"select /*vt+ PLANNER=left2right */ a from t where x = 1 and y in (1, 2)",
+		"select a from t where x = :x and y in ::list",
+	}
+	for _, sql := range same {
+		assert.Equal(t, base, fingerprint(sql), sql)
+	}
+
+	different := []string{
+		"select b from t where x = 1 and y in (1, 2)",
+		"select a from t where x = 1",
+		"select a from t where x > 1 and y in (1, 2)",
+		"select straight_join a from t where x = 1 and y in (1, 2)",
+	}
+	for _, sql := range different {
+		assert.NotEqual(t, base, fingerprint(sql), sql)
+	}
+}
+
+func TestFingerprintOfNormalizedStatement(t *testing.T) {
+	parser := NewTestParser()
+	stmt, reserved, err := parser.Parse2("select a from t where x = 1 and y = 'z'")
+	require.NoError(t, err)
+	original := Fingerprint(stmt)
+
+	err = Normalize(stmt, NewReservedVars("vtg", reserved), map[string]*querypb.BindVariable{})
+	require.NoError(t, err)
+	assert.Equal(t, original, Fingerprint(stmt))
+	// the statement itself is not modified
+	assert.Equal(t, "select a from t where x = :x /* INT64 */ and y = :y /* VARCHAR */", String(stmt))
+}
@@ -309,7 +309,7 @@ var keywords = []keyword{
 	{"gtid_subtract", GTID_SUBTRACT},
 	{"grant", UNUSED},
 	{"group", GROUP},
-	{"grouping", UNUSED},
+	{"grouping", GROUPING},
 	{"groups", UNUSED},
 	{"group_concat", GROUP_CONCAT},
 	{"hash", HASH},
@@ -356,6 +356,7 @@ var keywords = []keyword{
 	{"join", JOIN},
 	{"json", JSON},
 	{"json_array", JSON_ARRAY},
+	{"json_arrayagg", JSON_ARRAYAGG},
 	{"json_array_append", JSON_ARRAY_APPEND},
 	{"json_array_insert", JSON_ARRAY_INSERT},
 	{"json_contains", JSON_CONTAINS},
@@ -369,6 +370,7 @@ var keywords = []keyword{
 	{"json_merge_patch", JSON_MERGE_PATCH},
 	{"json_merge_preserve", JSON_MERGE_PRESERVE},
 	{"json_object", JSON_OBJECT},
+	{"json_objectagg", JSON_OBJECTAGG},
 	{"json_overlaps", JSON_OVERLAPS},
 	{"json_pretty", JSON_PRETTY},
 	{"json_remove", JSON_REMOVE},
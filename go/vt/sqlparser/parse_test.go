@@ -2716,6 +2716,17 @@ var (
 		output: "select `name`, group_concat(distinct id, score order by id desc separator ':' limit 10, 2) from t group by `name`",
 	}, {
 		input: "select foo, any_value(id) from tbl group by foo",
+	}, {
+		input: "select a, b, grouping(a), grouping(a, b), count(*) from t group by a, b with rollup",
+	}, {
+		input:  "select a, count(*) from t group by a with rollup having GROUPING(a) = 1",
+		output: "select a, count(*) from t group by a with rollup having grouping(a) = 1",
+	}, {
+		input:  "select foo, JSON_ARRAYAGG(id), JSON_OBJECTAGG(k, v) from tbl group by foo",
+		output: "select foo, json_arrayagg(id), json_objectagg(k, v) from tbl group by foo",
+	}, {
+		input:  "select json_arrayagg from t",
+		output: "select `json_arrayagg` from t",
 	}, {
 		input: "select * from t partition (p0)",
 	}, {
@@ -3787,7 +3798,7 @@ var (
 		output: "select `time`, subject, variance(val) over ( partition by `time`, subject) as window_result from observations group by `time`, subject",
 	}, {
 		input:  "SELECT id, coalesce( (SELECT Json_arrayagg(Json_array(id)) FROM (SELECT *, Row_number() over (ORDER BY users.order ASC) FROM unsharded as users WHERE users.purchaseorderid = orders.id) users), json_array()) AS users, coalesce( (SELECT json_arrayagg(json_array(id)) FROM (SELECT *, row_number() over (ORDER BY tests.order ASC) FROM unsharded as tests WHERE tests.purchaseorderid = orders.id) tests), json_array()) AS tests FROM unsharded as orders WHERE orders.id = 'xxx'",
-		output: "select id, coalesce((select Json_arrayagg(json_array(id)) from (select *, row_number() over ( order by users.`order` asc) from unsharded as users where users.purchaseorderid = orders.id) as users), json_array()) as users, coalesce((select json_arrayagg(json_array(id)) from (select *, row_number() over ( order by tests.`order` asc) from unsharded as tests where tests.purchaseorderid = orders.id) as tests), json_array()) as tests from unsharded as orders where orders.id = 'xxx'",
+		output: "select id, coalesce((select json_arrayagg(json_array(id)) from (select *, row_number() over ( order by users.`order` asc) from unsharded as users where users.purchaseorderid = orders.id) as users), json_array()) as users, coalesce((select json_arrayagg(json_array(id)) from (select *, row_number() over ( order by tests.`order` asc) from unsharded as tests where tests.purchaseorderid = orders.id) as tests), json_array()) as tests from unsharded as orders where orders.id = 'xxx'",
 	}, {
 		input: `kill connection 18446744073709551615`,
 	}, {
@@ -370,7 +370,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> JSON_ARRAY JSON_OBJECT JSON_QUOTE
 %token <str> JSON_DEPTH JSON_TYPE JSON_LENGTH JSON_VALID
 %token <str> JSON_ARRAY_APPEND JSON_ARRAY_INSERT JSON_INSERT JSON_MERGE JSON_MERGE_PATCH JSON_MERGE_PRESERVE JSON_REMOVE JSON_REPLACE JSON_SET JSON_UNQUOTE
-%token <str> COUNT AVG MAX MIN SUM GROUP_CONCAT BIT_AND BIT_OR BIT_XOR STD STDDEV STDDEV_POP STDDEV_SAMP VAR_POP VAR_SAMP VARIANCE ANY_VALUE
+%token <str> COUNT AVG MAX MIN SUM GROUP_CONCAT BIT_AND BIT_OR BIT_XOR STD STDDEV STDDEV_POP STDDEV_SAMP VAR_POP VAR_SAMP VARIANCE ANY_VALUE GROUPING JSON_ARRAYAGG JSON_OBJECTAGG
 %token <str> REGEXP_INSTR REGEXP_LIKE REGEXP_REPLACE REGEXP_SUBSTR
 %token <str> ExtractValue UpdateXML
 %token <str> GET_LOCK RELEASE_LOCK RELEASE_ALL_LOCKS IS_FREE_LOCK IS_USED_LOCK
@@ -384,7 +384,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> MATCH AGAINST BOOLEAN LANGUAGE WITH QUERY EXPANSION WITHOUT VALIDATION ROLLUP
 
 // MySQL reserved words that are unused by this grammar will map to this token.
-%token <str> UNUSED ARRAY BYTE CUME_DIST DESCRIPTION DENSE_RANK EMPTY EXCEPT FIRST_VALUE GROUPING GROUPS JSON_TABLE LAG LAST_VALUE LATERAL LEAD
+%token <str> UNUSED ARRAY BYTE CUME_DIST DESCRIPTION DENSE_RANK EMPTY EXCEPT FIRST_VALUE GROUPS JSON_TABLE LAG LAST_VALUE LATERAL LEAD
 %token <str> NTH_VALUE NTILE OF OVER PERCENT_RANK RANK RECURSIVE ROW_NUMBER SYSTEM WINDOW
 %token <str> ACTIVE ADMIN AUTOEXTEND_SIZE BUCKETS CLONE COLUMN_FORMAT COMPONENT DEFINITION ENFORCED ENGINE_ATTRIBUTE EXCLUDE FOLLOWING GET_MASTER_PUBLIC_KEY HISTOGRAM HISTORY
 %token <str> INACTIVE INVISIBLE LOCKED MASTER_COMPRESSION_ALGORITHMS MASTER_PUBLIC_KEY_PATH MASTER_TLS_CIPHERSUITES MASTER_ZSTD_COMPRESSION_LEVEL
@@ -6057,6 +6057,18 @@ UTC_DATE func_paren_opt
   {
     $$ = &AnyValue{Arg:$3}
   }
+| GROUPING openb expression_list closeb
+  {
+    $$ = &GroupingFunc{Exprs: $3}
+  }
+| JSON_ARRAYAGG openb expression closeb
+  {
+    $$ = &JSONArrayAgg{Arg: $3}
+  }
+| JSON_OBJECTAGG openb expression ',' expression closeb
+  {
+    $$ = &JSONObjectAgg{Key: $3, Value: $5}
+  }
 | TIMESTAMPADD openb timestampadd_interval ',' expression ',' expression closeb
   {
     $$ = &IntervalDateExpr{Syntax: IntervalDateExprTimestampadd, Date: $7, Interval: $5, Unit: $3}
@@ -8317,6 +8329,7 @@ non_reserved_keyword:
 | ISOLATION
 | JSON
 | JSON_ARRAY %prec FUNCTION_CALL_NON_KEYWORD
+| JSON_ARRAYAGG %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_ARRAY_APPEND %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_ARRAY_INSERT %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_CONTAINS %prec FUNCTION_CALL_NON_KEYWORD
@@ -8329,6 +8342,7 @@ non_reserved_keyword:
 | JSON_MERGE_PATCH %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_MERGE_PRESERVE %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_OBJECT %prec FUNCTION_CALL_NON_KEYWORD
+| JSON_OBJECTAGG %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_OVERLAPS %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_PRETTY %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_QUOTE %prec FUNCTION_CALL_NON_KEYWORD
@@ -57,7 +57,8 @@ var (
 	off     = "0"
 	utf8mb4 = "'utf8mb4'"
 
-	ForeignKeyChecks = "foreign_key_checks"
+	ForeignKeyChecks  = "foreign_key_checks"
+	GroupConcatMaxLen = "group_concat_max_len"
 
 	Autocommit                  = SystemVariable{Name: "autocommit", IsBoolean: true, Default: on}
 	Charset                     = SystemVariable{Name: "charset", Default: utf8mb4, IdentifierAsString: true}
@@ -189,7 +190,7 @@ var (
 		{Name: "eq_range_index_dive_limit", SupportSetVar: true},
 		{Name: "explicit_defaults_for_timestamp"},
 		{Name: ForeignKeyChecks, IsBoolean: true, SupportSetVar: true},
-		{Name: "group_concat_max_len", SupportSetVar: true},
+		{Name: GroupConcatMaxLen, SupportSetVar: true},
 		{Name: "information_schema_stats_expiry"},
 		{Name: "max_heap_table_size", SupportSetVar: true},
 		{Name: "max_seeks_for_key", SupportSetVar: true},
@@ -81,6 +81,7 @@ const (
 	ExternalClustersFile   = "ExternalClusters"
 	ShardRoutingRulesFile  = "ShardRoutingRules"
 	CommonRoutingRulesFile = "Rules"
+	PlanBaselinesFile      = "PlanBaselines"
 )
 
 // Path for all object types.
@@ -210,6 +210,14 @@ func (ts *Server) RebuildSrvVSchema(ctx context.Context, cells []string) error {
 	}
 	srvVSchema.KeyspaceRoutingRules = krr
 
+	pb, err := ts.GetPlanBaselines(ctx)
+	if err != nil {
+		return fmt.Errorf("GetPlanBaselines failed: %v", err)
+	}
+	if len(pb.Baselines) > 0 {
+		srvVSchema.PlanBaselines = pb
+	}
+
 	// now save the SrvVSchema in all cells in parallel
 	for _, cell := range cells {
 		wg.Add(1)
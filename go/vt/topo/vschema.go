@@ -159,6 +159,41 @@ func (ts *Server) GetShardRoutingRules(ctx context.Context) (*vschemapb.ShardRou
 	return srr, nil
 }
 
+// SavePlanBaselines saves the vtgate plan baselines into the topo.
+func (ts *Server) SavePlanBaselines(ctx context.Context, baselines *vschemapb.PlanBaselines) error {
+	data, err := baselines.MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		if err := ts.globalCell.Delete(ctx, PlanBaselinesFile, nil); err != nil && !IsErrType(err, NoNode) {
+			return err
+		}
+		return nil
+	}
+
+	_, err = ts.globalCell.Update(ctx, PlanBaselinesFile, data, nil)
+	return err
+}
+
+// GetPlanBaselines fetches the vtgate plan baselines from the topo.
+func (ts *Server) GetPlanBaselines(ctx context.Context) (*vschemapb.PlanBaselines, error) {
+	baselines := &vschemapb.PlanBaselines{}
+	data, _, err := ts.globalCell.Get(ctx, PlanBaselinesFile)
+	if err != nil {
+		if IsErrType(err, NoNode) {
+			return baselines, nil
+		}
+		return nil, err
+	}
+	err = baselines.UnmarshalVT(data)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid plan baselines: %q", data)
+	}
+	return baselines, nil
+}
+
 // CreateKeyspaceRoutingRules wraps the underlying Conn.Create.
 func (ts *Server) CreateKeyspaceRoutingRules(ctx context.Context, value *vschemapb.KeyspaceRoutingRules) error {
 	data, err := value.MarshalVT()
@@ -164,6 +164,16 @@ func (f *fakeVTGateService) ResolveTransaction(ctx context.Context, dtid string)
 	return nil
 }
 
+// MessageInspect is part of the VTGateService interface
+func (f *fakeVTGateService) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	return nil, nil
+}
+
+// MessageRequeue is part of the VTGateService interface
+func (f *fakeVTGateService) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	return 0, nil
+}
+
 func (f *fakeVTGateService) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error {
 	return nil
 }
@@ -579,6 +579,21 @@ func (itc *internalTabletConn) MessageAck(ctx context.Context, target *querypb.T
 	return count, tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
 }
 
+// MessageInspect is part of queryservice.QueryService
+func (itc *internalTabletConn) MessageInspect(ctx context.Context, target *querypb.Target, name string, limit int64) (*sqltypes.Result, error) {
+	qr, err := itc.tablet.qsc.QueryService().MessageInspect(ctx, target, name, limit)
+	if err != nil {
+		return nil, tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
+	}
+	return qr, nil
+}
+
+// MessageRequeue is part of queryservice.QueryService
+func (itc *internalTabletConn) MessageRequeue(ctx context.Context, target *querypb.Target, name string, ids []*querypb.Value) (int64, error) {
+	count, err := itc.tablet.qsc.QueryService().MessageRequeue(ctx, target, name, ids)
+	return count, tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
+}
+
 // HandlePanic is part of the QueryService interface.
 func (itc *internalTabletConn) HandlePanic(err *error) {
 }
@@ -56,6 +56,15 @@ func (client *gRPCVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context, i
 	return client.c.ApplyKeyspaceRoutingRules(ctx, in, opts...)
 }
 
+// ApplyPlanBaselines is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ApplyPlanBaselines(ctx context.Context, in *vtctldatapb.ApplyPlanBaselinesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyPlanBaselinesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ApplyPlanBaselines(ctx, in, opts...)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -371,6 +380,15 @@ func (client *gRPCVtctldClient) GetPermissions(ctx context.Context, in *vtctldat
 	return client.c.GetPermissions(ctx, in, opts...)
 }
 
+// GetPlanBaselines is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetPlanBaselines(ctx context.Context, in *vtctldatapb.GetPlanBaselinesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPlanBaselinesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetPlanBaselines(ctx, in, opts...)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -533,6 +551,15 @@ func (client *gRPCVtctldClient) InitShardPrimary(ctx context.Context, in *vtctld
 	return client.c.InitShardPrimary(ctx, in, opts...)
 }
 
+// InspectMessages is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) InspectMessages(ctx context.Context, in *vtctldatapb.InspectMessagesRequest, opts ...grpc.CallOption) (*vtctldatapb.InspectMessagesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.InspectMessages(ctx, in, opts...)
+}
+
 // LaunchSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) LaunchSchemaMigration(ctx context.Context, in *vtctldatapb.LaunchSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.LaunchSchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -749,6 +776,15 @@ func (client *gRPCVtctldClient) ReparentTablet(ctx context.Context, in *vtctldat
 	return client.c.ReparentTablet(ctx, in, opts...)
 }
 
+// RequeueMessages is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RequeueMessages(ctx context.Context, in *vtctldatapb.RequeueMessagesRequest, opts ...grpc.CallOption) (*vtctldatapb.RequeueMessagesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RequeueMessages(ctx, in, opts...)
+}
+
 // ReshardCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ReshardCreate(ctx context.Context, in *vtctldatapb.ReshardCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
 	if client.c == nil {
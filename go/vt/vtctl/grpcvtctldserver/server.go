@@ -43,6 +43,7 @@ import (
 	"github.com/mdibaiee/vitess/go/trace"
 	"github.com/mdibaiee/vitess/go/vt/callerid"
 	"github.com/mdibaiee/vitess/go/vt/concurrency"
+	"github.com/mdibaiee/vitess/go/vt/grpcclient"
 	hk "github.com/mdibaiee/vitess/go/vt/hook"
 	"github.com/mdibaiee/vitess/go/vt/key"
 	"github.com/mdibaiee/vitess/go/vt/log"
@@ -64,6 +65,9 @@ import (
 	"github.com/mdibaiee/vitess/go/vt/vtenv"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/queryservice"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletconn"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/throttle/base"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tmclient"
 
 	logutilpb "github.com/mdibaiee/vitess/go/vt/proto/logutil"
@@ -83,6 +87,10 @@ const (
 
 	// DefaultWaitReplicasTimeout is the default value for waitReplicasTimeout, which is used when calling method ApplySchema.
 	DefaultWaitReplicasTimeout = 10 * time.Second
+
+	// DefaultInspectMessagesLimit is the number of dead-lettered messages InspectMessages
+	// returns from each shard when the request doesn't set a limit.
+	DefaultInspectMessagesLimit = 100
 )
 
 // VtctldServer implements the Vtctld RPC service protocol.
@@ -166,6 +174,77 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// ApplyPlanBaselines is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyPlanBaselines(ctx context.Context, req *vtctldatapb.ApplyPlanBaselinesRequest) (resp *vtctldatapb.ApplyPlanBaselinesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyPlanBaselines")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("skip_rebuild", req.SkipRebuild)
+	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
+
+	baselines := req.PlanBaselines
+	if baselines == nil {
+		baselines = &vschemapb.PlanBaselines{}
+	}
+	if err = s.fingerprintPlanBaselines(baselines); err != nil {
+		return nil, err
+	}
+
+	if err = s.ts.SavePlanBaselines(ctx, baselines); err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ApplyPlanBaselinesResponse{
+		PlanBaselines: baselines,
+	}
+
+	if req.SkipRebuild {
+		log.Warningf("Skipping rebuild of SrvVSchema, will need to run RebuildVSchemaGraph for changes to take effect")
+		return resp, nil
+	}
+
+	if err = s.ts.RebuildSrvVSchema(ctx, req.RebuildCells); err != nil {
+		err = vterrors.Wrapf(err, "RebuildSrvVSchema(%v) failed: %v", req.RebuildCells, err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// fingerprintPlanBaselines validates the given baselines and fills in the
+// fingerprint of the baselines that were only given a query.
+func (s *VtctldServer) fingerprintPlanBaselines(baselines *vschemapb.PlanBaselines) error {
+	seen := make(map[string]bool, len(baselines.Baselines))
+	for _, baseline := range baselines.Baselines {
+		if baseline.Query != "" {
+			fp, err := s.ws.SQLParser().FingerprintQuery(baseline.Query)
+			if err != nil {
+				return vterrors.Wrapf(err, "invalid query for plan baseline %q", baseline.Query)
+			}
+			if baseline.Fingerprint != "" && baseline.Fingerprint != fp {
+				return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan baseline fingerprint %s does not match the fingerprint %s of query %q", baseline.Fingerprint, fp, baseline.Query)
+			}
+			baseline.Fingerprint = fp
+		}
+		if baseline.Fingerprint == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan baseline must have a query or a fingerprint")
+		}
+		if seen[baseline.Fingerprint] {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate plan baseline for fingerprint %s", baseline.Fingerprint)
+		}
+		seen[baseline.Fingerprint] = true
+
+		switch baseline.Planner {
+		case querypb.ExecuteOptions_DEFAULT_PLANNER, querypb.ExecuteOptions_Gen4, querypb.ExecuteOptions_Gen4Greedy, querypb.ExecuteOptions_Gen4Left2Right:
+		default:
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan baseline for fingerprint %s uses unsupported planner %s", baseline.Fingerprint, baseline.Planner)
+		}
+	}
+	return nil
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -1573,6 +1652,23 @@ func (s *VtctldServer) GetPermissions(ctx context.Context, req *vtctldatapb.GetP
 	}, nil
 }
 
+// GetPlanBaselines is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetPlanBaselines(ctx context.Context, req *vtctldatapb.GetPlanBaselinesRequest) (resp *vtctldatapb.GetPlanBaselinesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetPlanBaselines")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	pb, err := s.ts.GetPlanBaselines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetPlanBaselinesResponse{
+		PlanBaselines: pb,
+	}, nil
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetRoutingRules(ctx context.Context, req *vtctldatapb.GetRoutingRulesRequest) (resp *vtctldatapb.GetRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetRoutingRules")
@@ -1930,6 +2026,17 @@ func (s *VtctldServer) UpdateThrottlerConfig(ctx context.Context, req *vtctldata
 	if req.CheckAsCheckSelf && req.CheckAsCheckShard {
 		return nil, fmt.Errorf("--check-as-check-self and --check-as-check-shard are mutually exclusive")
 	}
+	if req.MetricName != "" {
+		if _, err := base.ParseMetricName(req.MetricName); err != nil {
+			return nil, err
+		}
+	}
+	if req.AppName == "" && len(req.AppCheckedMetrics) > 0 {
+		return nil, fmt.Errorf("--app-metrics requires --app-name")
+	}
+	if _, err := base.ParseScopedMetrics(req.AppCheckedMetrics); err != nil {
+		return nil, err
+	}
 
 	update := func(throttlerConfig *topodatapb.ThrottlerConfig) *topodatapb.ThrottlerConfig {
 		if throttlerConfig == nil {
@@ -1938,16 +2045,41 @@ func (s *VtctldServer) UpdateThrottlerConfig(ctx context.Context, req *vtctldata
 		if throttlerConfig.ThrottledApps == nil {
 			throttlerConfig.ThrottledApps = make(map[string]*topodatapb.ThrottledAppRule)
 		}
-		if req.CustomQuerySet {
+		switch {
+		case req.MetricName != "":
+			// threshold of a specific metric. A zero threshold restores the default threshold of the metric.
+			if req.CustomQuerySet {
+				throttlerConfig.CustomQuery = req.CustomQuery
+			}
+			if throttlerConfig.MetricThresholds == nil {
+				throttlerConfig.MetricThresholds = make(map[string]float64)
+			}
+			if req.Threshold == 0 {
+				delete(throttlerConfig.MetricThresholds, req.MetricName)
+			} else {
+				throttlerConfig.MetricThresholds[req.MetricName] = req.Threshold
+			}
+		case req.CustomQuerySet:
 			// custom query provided
 			throttlerConfig.CustomQuery = req.CustomQuery
 			throttlerConfig.Threshold = req.Threshold // allowed to be zero/negative because who knows what kind of custom query this is
-		} else {
+		default:
 			// no custom query, throttler works by querying replication lag. We only allow positive values
 			if req.Threshold > 0 {
 				throttlerConfig.Threshold = req.Threshold
 			}
 		}
+		if req.AppName != "" {
+			// metrics checked by an app. No metrics restores the default metric for the app.
+			if throttlerConfig.AppCheckedMetrics == nil {
+				throttlerConfig.AppCheckedMetrics = make(map[string]*topodatapb.ThrottlerConfig_MetricNames)
+			}
+			if len(req.AppCheckedMetrics) == 0 {
+				delete(throttlerConfig.AppCheckedMetrics, req.AppName)
+			} else {
+				throttlerConfig.AppCheckedMetrics[req.AppName] = &topodatapb.ThrottlerConfig_MetricNames{Names: req.AppCheckedMetrics}
+			}
+		}
 		if req.Enable {
 			throttlerConfig.Enabled = true
 		}
@@ -2622,6 +2754,81 @@ func (s *VtctldServer) InitShardPrimaryLocked(
 	return nil
 }
 
+// InspectMessages is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) InspectMessages(ctx context.Context, req *vtctldatapb.InspectMessagesRequest) (resp *vtctldatapb.InspectMessagesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.InspectMessages")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table", req.Table)
+	span.Annotate("limit", req.Limit)
+
+	if req.Table == "" {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message table name is required")
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultInspectMessagesLimit
+	}
+
+	result := &sqltypes.Result{}
+	err = s.forEachMessagePrimary(ctx, req.Keyspace, func(conn queryservice.QueryService, target *querypb.Target) error {
+		qr, err := conn.MessageInspect(ctx, target, req.Table, limit)
+		if err != nil {
+			return err
+		}
+		result.AppendResult(qr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.InspectMessagesResponse{Result: sqltypes.ResultToProto3(result)}, nil
+}
+
+// forEachMessagePrimary calls fn with a connection to the primary of each shard
+// in the keyspace, one shard at a time.
+func (s *VtctldServer) forEachMessagePrimary(ctx context.Context, keyspace string, fn func(conn queryservice.QueryService, target *querypb.Target) error) error {
+	shards, err := s.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+
+	for _, shard := range shards {
+		si, err := s.ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return err
+		}
+		if !si.HasPrimary() {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %v/%v has no primary", keyspace, shard)
+		}
+		ti, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+		if err != nil {
+			return err
+		}
+
+		conn, err := tabletconn.GetDialer()(ctx, ti.Tablet, grpcclient.FailFast(false))
+		if err != nil {
+			return err
+		}
+		target := &querypb.Target{
+			Keyspace:   keyspace,
+			Shard:      shard,
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}
+		err = fn(conn, target)
+		conn.Close(ctx)
+		if err != nil {
+			return vterrors.Wrapf(err, "shard %v/%v", keyspace, shard)
+		}
+	}
+	return nil
+}
+
 // LaunchSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) LaunchSchemaMigration(ctx context.Context, req *vtctldatapb.LaunchSchemaMigrationRequest) (resp *vtctldatapb.LaunchSchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.LaunchSchemaMigration")
@@ -3271,6 +3478,48 @@ func (s *VtctldServer) ReparentTablet(ctx context.Context, req *vtctldatapb.Repa
 	}, nil
 }
 
+// RequeueMessages is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RequeueMessages(ctx context.Context, req *vtctldatapb.RequeueMessagesRequest) (resp *vtctldatapb.RequeueMessagesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RequeueMessages")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table", req.Table)
+	span.Annotate("ids", strings.Join(req.Ids, ","))
+
+	if req.Table == "" {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message table name is required")
+		return nil, err
+	}
+	if len(req.Ids) == 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "at least one message id is required")
+		return nil, err
+	}
+
+	// The ids are not routed: each primary requeues the ones it has.
+	ids := make([]*querypb.Value, 0, len(req.Ids))
+	for _, id := range req.Ids {
+		ids = append(ids, &querypb.Value{Type: sqltypes.VarBinary, Value: []byte(id)})
+	}
+
+	var count int64
+	err = s.forEachMessagePrimary(ctx, req.Keyspace, func(conn queryservice.QueryService, target *querypb.Target) error {
+		n, err := conn.MessageRequeue(ctx, target, req.Table, ids)
+		if err != nil {
+			return err
+		}
+		count += n
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.RequeueMessagesResponse{Count: count}, nil
+}
+
 // ReshardCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCreateRequest) (resp *vtctldatapb.WorkflowStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ReshardCreate")
@@ -25,6 +25,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -38,9 +39,11 @@ import (
 	"github.com/mdibaiee/vitess/go/protoutil"
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	"github.com/mdibaiee/vitess/go/test/utils"
+	"github.com/mdibaiee/vitess/go/vt/grpcclient"
 	hk "github.com/mdibaiee/vitess/go/vt/hook"
 	"github.com/mdibaiee/vitess/go/vt/mysqlctl/backupstorage"
 	"github.com/mdibaiee/vitess/go/vt/proto/vttime"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/topo"
 	"github.com/mdibaiee/vitess/go/vt/topo/memorytopo"
 	"github.com/mdibaiee/vitess/go/vt/topo/topoproto"
@@ -48,6 +51,10 @@ import (
 	"github.com/mdibaiee/vitess/go/vt/vtctl/localvtctldclient"
 	"github.com/mdibaiee/vitess/go/vt/vtctl/schematools"
 	"github.com/mdibaiee/vitess/go/vt/vtenv"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/queryservice"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/sandboxconn"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletconn"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletconntest"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tmclient"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tmclienttest"
 
@@ -60,6 +67,7 @@ import (
 	vschemapb "github.com/mdibaiee/vitess/go/vt/proto/vschema"
 	vtctldatapb "github.com/mdibaiee/vitess/go/vt/proto/vtctldata"
 	vtctlservicepb "github.com/mdibaiee/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 )
 
 func init() {
@@ -76,8 +84,21 @@ func init() {
 	tmclient.RegisterTabletManagerClientFactory("grpcvtctldserver.test", func() tmclient.TabletManagerClient {
 		return nil
 	})
+
+	// The messaging RPCs talk to the query service of the primaries directly,
+	// so tests register the connection each tablet should get in messageConns.
+	tabletconntest.SetProtocol("go.vt.vtctl.grpcvtctldserver.messages", "grpcvtctldserver.test")
+	tabletconn.RegisterDialer("grpcvtctldserver.test", func(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		conn, ok := messageConns.Load(topoproto.TabletAliasString(tablet.Alias))
+		if !ok {
+			return nil, fmt.Errorf("no connection for tablet %v", topoproto.TabletAliasString(tablet.Alias))
+		}
+		return conn.(queryservice.QueryService), nil
+	})
 }
 
+var messageConns sync.Map
+
 func TestPanicHandler(t *testing.T) {
 	t.Parallel()
 
@@ -232,6 +253,174 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestApplyPlanBaselines(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	query := "select * from t1 join t2 on t1.id = t2.t1_id where t1.x = 1"
+	fp, err := sqlparser.NewTestParser().FingerprintQuery(query)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		cells             []string
+		req               *vtctldatapb.ApplyPlanBaselinesRequest
+		expectedBaselines *vschemapb.PlanBaselines
+		topoDown          bool
+		shouldErr         bool
+	}{
+		{
+			name:  "success",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{
+						{
+							Query:           query,
+							JoinOrder:       []string{"t2", "t1"},
+							DisableHashJoin: true,
+						},
+						{
+							Fingerprint: "0123456789abcdef",
+							Planner:     querypb.ExecuteOptions_Gen4Left2Right,
+						},
+					},
+				},
+			},
+			expectedBaselines: &vschemapb.PlanBaselines{
+				Baselines: []*vschemapb.PlanBaseline{
+					{
+						Fingerprint:     fp,
+						Query:           query,
+						JoinOrder:       []string{"t2", "t1"},
+						DisableHashJoin: true,
+					},
+					{
+						Fingerprint: "0123456789abcdef",
+						Planner:     querypb.ExecuteOptions_Gen4Left2Right,
+					},
+				},
+			},
+		},
+		{
+			name:  "fingerprint mismatch",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{Fingerprint: "0123456789abcdef", Query: query}},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name:  "neither query nor fingerprint",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{JoinOrder: []string{"t1"}}},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name:  "duplicate fingerprint",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{Query: query}, {Fingerprint: fp}},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name:  "unsupported planner",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{Query: query, Planner: querypb.ExecuteOptions_V3}},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name:  "rebuild failed (bad cell)",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{Query: query}},
+				},
+				RebuildCells: []string{"zone1", "zone2"},
+			},
+			shouldErr: true,
+		},
+		{
+			name:  "rebuild skipped",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{Query: query}},
+				},
+				SkipRebuild:  true,
+				RebuildCells: []string{"zone1", "zone2"},
+			},
+			expectedBaselines: &vschemapb.PlanBaselines{
+				Baselines: []*vschemapb.PlanBaseline{{Fingerprint: fp, Query: query}},
+			},
+		},
+		{
+			name:              "clear",
+			cells:             []string{"zone1"},
+			req:               &vtctldatapb.ApplyPlanBaselinesRequest{},
+			expectedBaselines: &vschemapb.PlanBaselines{},
+		},
+		{
+			name:      "topo down",
+			cells:     []string{"zone1"},
+			req:       &vtctldatapb.ApplyPlanBaselinesRequest{},
+			topoDown:  true,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, factory := memorytopo.NewServerAndFactory(ctx, tt.cells...)
+			if tt.topoDown {
+				factory.SetError(errors.New("topo down for testing"))
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.ApplyPlanBaselines(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err, "ApplyPlanBaselines(%+v) failed", tt.req)
+			utils.MustMatch(t, tt.expectedBaselines, resp.PlanBaselines)
+
+			pb, err := ts.GetPlanBaselines(ctx)
+			require.NoError(t, err, "failed to get plan baselines from topo to compare")
+			utils.MustMatch(t, tt.expectedBaselines, pb)
+
+			if tt.req.SkipRebuild {
+				return
+			}
+			srv, err := ts.GetSrvVSchema(ctx, "zone1")
+			require.NoError(t, err)
+			if len(tt.expectedBaselines.Baselines) == 0 {
+				assert.Nil(t, srv.PlanBaselines)
+			} else {
+				utils.MustMatch(t, tt.expectedBaselines, srv.PlanBaselines)
+			}
+		})
+	}
+}
+
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -5723,6 +5912,79 @@ func TestGetPermissions(t *testing.T) {
 	}
 }
 
+func TestGetPlanBaselines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		topoDown  bool
+		pbIn      *vschemapb.PlanBaselines
+		expected  *vschemapb.PlanBaselines
+		shouldErr bool
+	}{
+		{
+			name: "success",
+			pbIn: &vschemapb.PlanBaselines{
+				Baselines: []*vschemapb.PlanBaseline{
+					{
+						Fingerprint: "0123456789abcdef",
+						JoinOrder:   []string{"t2", "t1"},
+					},
+				},
+			},
+			expected: &vschemapb.PlanBaselines{
+				Baselines: []*vschemapb.PlanBaseline{
+					{
+						Fingerprint: "0123456789abcdef",
+						JoinOrder:   []string{"t2", "t1"},
+					},
+				},
+			},
+		},
+		{
+			name:     "no plan baselines",
+			pbIn:     nil,
+			expected: &vschemapb.PlanBaselines{},
+		},
+		{
+			name:      "topo error",
+			topoDown:  true,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts, factory := memorytopo.NewServerAndFactory(ctx)
+			if tt.pbIn != nil {
+				err := ts.SavePlanBaselines(ctx, tt.pbIn)
+				require.NoError(t, err, "could not save plan baselines: %+v", tt.pbIn)
+			}
+
+			if tt.topoDown {
+				factory.SetError(errors.New("topo down for testing"))
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.GetPlanBaselines(ctx, &vtctldatapb.GetPlanBaselinesRequest{})
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp.PlanBaselines)
+		})
+	}
+}
+
 func TestGetRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -7937,6 +8199,69 @@ func TestGetVSchema(t *testing.T) {
 	})
 }
 
+func TestInspectMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	sbc1, sbc2 := addMessagePrimaries(ctx, t, ts, "zone1", "inspect", 100)
+
+	fields := sqltypes.MakeTestFields("id|message", "varbinary|varchar")
+	sbc1.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(fields, "1|a")})
+	sbc2.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(fields, "2|b", "3|c")})
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	resp, err := vtctld.InspectMessages(ctx, &vtctldatapb.InspectMessagesRequest{
+		Keyspace: "inspect",
+		Table:    "msg",
+	})
+	require.NoError(t, err)
+	want := sqltypes.MakeTestResult(fields, "1|a", "2|b", "3|c")
+	utils.MustMatch(t, sqltypes.ResultToProto3(want), resp.Result)
+
+	_, err = vtctld.InspectMessages(ctx, &vtctldatapb.InspectMessagesRequest{Keyspace: "inspect"})
+	assert.ErrorContains(t, err, "message table name is required")
+
+	sbc2.MustFailCodes[vtrpcpb.Code_FAILED_PRECONDITION] = 1
+	_, err = vtctld.InspectMessages(ctx, &vtctldatapb.InspectMessagesRequest{
+		Keyspace: "inspect",
+		Table:    "msg",
+	})
+	assert.ErrorContains(t, err, "shard inspect/80-")
+}
+
+// addMessagePrimaries adds the primaries of a keyspace with two shards, and
+// registers the query service connections the messaging RPCs will get for them.
+func addMessagePrimaries(ctx context.Context, t *testing.T, ts *topo.Server, cell string, keyspace string, uid uint32) (*sandboxconn.SandboxConn, *sandboxconn.SandboxConn) {
+	t.Helper()
+
+	var sbcs []*sandboxconn.SandboxConn
+	for i, shard := range []string{"-80", "80-"} {
+		tablet := &topodatapb.Tablet{
+			Alias: &topodatapb.TabletAlias{
+				Cell: cell,
+				Uid:  uid + uint32(i),
+			},
+			Keyspace: keyspace,
+			Shard:    shard,
+			Type:     topodatapb.TabletType_PRIMARY,
+		}
+		testutil.AddTablet(ctx, t, ts, tablet, &testutil.AddTabletOptions{AlsoSetShardPrimary: true})
+
+		sbc := sandboxconn.NewSandboxConn(tablet)
+		alias := topoproto.TabletAliasString(tablet.Alias)
+		messageConns.Store(alias, sbc)
+		t.Cleanup(func() { messageConns.Delete(alias) })
+		sbcs = append(sbcs, sbc)
+	}
+	return sbcs[0], sbcs[1]
+}
+
 func TestLaunchSchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -10378,6 +10703,41 @@ func TestReparentTablet(t *testing.T) {
 	}
 }
 
+func TestRequeueMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	sbc1, sbc2 := addMessagePrimaries(ctx, t, ts, "zone1", "requeue", 200)
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	resp, err := vtctld.RequeueMessages(ctx, &vtctldatapb.RequeueMessagesRequest{
+		Keyspace: "requeue",
+		Table:    "msg",
+		Ids:      []string{"1", "2"},
+	})
+	require.NoError(t, err)
+	// The sandbox connections requeue every id they're given.
+	assert.EqualValues(t, 4, resp.Count)
+	wantIDs := []*querypb.Value{
+		{Type: sqltypes.VarBinary, Value: []byte("1")},
+		{Type: sqltypes.VarBinary, Value: []byte("2")},
+	}
+	utils.MustMatch(t, wantIDs, sbc1.MessageIDs)
+	utils.MustMatch(t, wantIDs, sbc2.MessageIDs)
+
+	_, err = vtctld.RequeueMessages(ctx, &vtctldatapb.RequeueMessagesRequest{
+		Keyspace: "requeue",
+		Table:    "msg",
+	})
+	assert.ErrorContains(t, err, "at least one message id is required")
+}
+
 func TestRestoreFromBackup(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -44,6 +44,11 @@ func (client *localVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context,
 	return client.s.ApplyKeyspaceRoutingRules(ctx, in)
 }
 
+// ApplyPlanBaselines is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ApplyPlanBaselines(ctx context.Context, in *vtctldatapb.ApplyPlanBaselinesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyPlanBaselinesResponse, error) {
+	return client.s.ApplyPlanBaselines(ctx, in)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	return client.s.ApplyRoutingRules(ctx, in)
@@ -311,6 +316,11 @@ func (client *localVtctldClient) GetPermissions(ctx context.Context, in *vtctlda
 	return client.s.GetPermissions(ctx, in)
 }
 
+// GetPlanBaselines is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetPlanBaselines(ctx context.Context, in *vtctldatapb.GetPlanBaselinesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPlanBaselinesResponse, error) {
+	return client.s.GetPlanBaselines(ctx, in)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	return client.s.GetRoutingRules(ctx, in)
@@ -401,6 +411,11 @@ func (client *localVtctldClient) InitShardPrimary(ctx context.Context, in *vtctl
 	return client.s.InitShardPrimary(ctx, in)
 }
 
+// InspectMessages is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) InspectMessages(ctx context.Context, in *vtctldatapb.InspectMessagesRequest, opts ...grpc.CallOption) (*vtctldatapb.InspectMessagesResponse, error) {
+	return client.s.InspectMessages(ctx, in)
+}
+
 // LaunchSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) LaunchSchemaMigration(ctx context.Context, in *vtctldatapb.LaunchSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.LaunchSchemaMigrationResponse, error) {
 	return client.s.LaunchSchemaMigration(ctx, in)
@@ -521,6 +536,11 @@ func (client *localVtctldClient) ReparentTablet(ctx context.Context, in *vtctlda
 	return client.s.ReparentTablet(ctx, in)
 }
 
+// RequeueMessages is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RequeueMessages(ctx context.Context, in *vtctldatapb.RequeueMessagesRequest, opts ...grpc.CallOption) (*vtctldatapb.RequeueMessagesResponse, error) {
+	return client.s.RequeueMessages(ctx, in)
+}
+
 // ReshardCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ReshardCreate(ctx context.Context, in *vtctldatapb.ReshardCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
 	return client.s.ReshardCreate(ctx, in)
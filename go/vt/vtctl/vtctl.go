@@ -706,7 +706,7 @@ var commands = []commandGroup{
 			{
 				name:   "UpdateThrottlerConfig",
 				method: commandUpdateThrottlerConfig,
-				params: "[--enable|--disable] [--threshold=<float64>] [--custom-query=<query>] [--check-as-check-self|--check-as-check-shard] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt] <keyspace>",
+				params: "[--enable|--disable] [--metric-name=<name>] [--threshold=<float64>] [--custom-query=<query>] [--check-as-check-self|--check-as-check-shard] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt] [--app-name=<name> --app-metrics=<metrics>] <keyspace>",
 				help:   "Update the table throttler configuration for all cells and tablets of a given keyspace",
 			},
 			{
@@ -3597,7 +3597,8 @@ func commandGetSrvKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) (err error) {
 	enable := subFlags.Bool("enable", false, "Enable the throttler")
 	disable := subFlags.Bool("disable", false, "Disable the throttler")
-	threshold := subFlags.Float64("threshold", 0, "threshold for the either default check (replication lag seconds) or custom check")
+	threshold := subFlags.Float64("threshold", 0, "threshold for the either default check (replication lag seconds) or custom check, or for the metric in --metric-name")
+	metricName := subFlags.String("metric-name", "", "name of the metric for which --threshold applies: lag, threads_running, history_list_length, loadavg or custom. A zero threshold restores the default threshold of the metric")
 	customQuery := subFlags.String("custom-query", "", "custom throttler check query")
 	checkAsCheckSelf := subFlags.Bool("check-as-check-self", false, "/throttler/check requests behave as is /throttler/check-self was called")
 	checkAsCheckShard := subFlags.Bool("check-as-check-shard", false, "use standard behavior for /throttler/check requests")
@@ -3606,6 +3607,8 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 	throttledAppRatio := subFlags.Float64("throttle-app-ratio", throttle.DefaultThrottleRatio, "ratio to throttle app (app specififed in --throttled-app)")
 	throttledAppDuration := subFlags.Duration("throttle-app-duration", throttle.DefaultAppThrottleDuration, "duration after which throttled app rule expires (app specified in --throttled-app)")
 	throttledAppExempt := subFlags.Bool("throttle-app-exempt", false, "exempt this app from being at all throttled. WARNING: use with extreme care, as this is likely to push metrics beyond the throttler's threshold, and starve other apps (app specified in --throttled-app)")
+	appName := subFlags.String("app-name", "", "an app name for which to assign the metrics in --app-metrics")
+	appMetrics := subFlags.StringSlice("app-metrics", nil, "metrics checked by the app in --app-name, optionally scoped, e.g. 'lag,self/threads_running'. No metrics restores the default metric for the app")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3632,6 +3635,9 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 	if subFlags.Changed("throttle-app-exempt") && *throttledApp == "" {
 		return fmt.Errorf("--throttle-app-exempt requires --throttle-app")
 	}
+	if subFlags.Changed("app-metrics") && *appName == "" {
+		return fmt.Errorf("--app-metrics requires --app-name")
+	}
 
 	keyspace := subFlags.Arg(0)
 
@@ -3644,6 +3650,9 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 		Threshold:         *threshold,
 		CheckAsCheckSelf:  *checkAsCheckSelf,
 		CheckAsCheckShard: *checkAsCheckShard,
+		MetricName:        *metricName,
+		AppName:           *appName,
+		AppCheckedMetrics: *appMetrics,
 	}
 	if *throttledApp != "" {
 		req.ThrottledApp = &topodatapb.ThrottledAppRule{
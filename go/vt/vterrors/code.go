@@ -59,6 +59,11 @@ var (
 	VT03031 = errorWithoutState("VT03031", vtrpcpb.Code_INVALID_ARGUMENT, "EXPLAIN is only supported for single keyspace", "EXPLAIN has to be sent down as a single query to the underlying MySQL, and this is not possible if it uses tables from multiple keyspaces")
 	VT03032 = errorWithState("VT03032", vtrpcpb.Code_INVALID_ARGUMENT, NonUpdateableTable, "the target table %s of the UPDATE is not updatable", "You cannot update a table that is not a real MySQL table.")
 	VT03033 = errorWithState("VT03033", vtrpcpb.Code_INVALID_ARGUMENT, ViewWrongList, "In definition of view, derived table or common table expression, SELECT list and column names list have different column counts", "The table column list and derived column list have different column counts.")
+	VT03034 = errorWithoutState("VT03034", vtrpcpb.Code_INVALID_ARGUMENT, "window name '%s' is not defined", "The OVER clause references a named window that is not defined in the WINDOW clause of the query.")
+	VT03035 = errorWithoutState("VT03035", vtrpcpb.Code_INVALID_ARGUMENT, "recursive common table expression '%s' %s", "The recursive common table expression is not valid. It needs to be a UNION of one or more non-recursive query blocks followed by one recursive query block that references the CTE exactly once in its FROM clause.")
+	VT03036 = errorWithoutState("VT03036", vtrpcpb.Code_INVALID_ARGUMENT, "argument #%d of GROUPING function is not in GROUP BY", "Every argument of the GROUPING function has to be one of the expressions of the GROUP BY clause.")
+	VT03037 = errorWithoutState("VT03037", vtrpcpb.Code_INVALID_ARGUMENT, "GROUPING function can only be used with GROUP BY WITH ROLLUP", "The GROUPING function tells super-aggregate rows apart from the regular ones, so it needs a GROUP BY WITH ROLLUP clause.")
+	VT03038 = errorWithoutState("VT03038", vtrpcpb.Code_INVALID_ARGUMENT, "every table function must have an alias", "A JSON_TABLE expression in the FROM clause needs an alias, so its columns can be referenced.")
 
 	VT05001 = errorWithState("VT05001", vtrpcpb.Code_NOT_FOUND, DbDropExists, "cannot drop database '%s'; database does not exists", "The given database does not exist; Vitess cannot drop it.")
 	VT05002 = errorWithState("VT05002", vtrpcpb.Code_NOT_FOUND, BadDb, "cannot alter database '%s'; unknown database", "The given database does not exist; Vitess cannot alter it.")
@@ -96,6 +101,7 @@ var (
 	VT09022 = errorWithoutState("VT09022", vtrpcpb.Code_FAILED_PRECONDITION, "Destination does not have exactly one shard: %v", "Cannot send query to multiple shards.")
 	VT09023 = errorWithoutState("VT09023", vtrpcpb.Code_FAILED_PRECONDITION, "could not map %v to a keyspace id", "Unable to determine the shard for the given row.")
 	VT09024 = errorWithoutState("VT09024", vtrpcpb.Code_FAILED_PRECONDITION, "could not map %v to a unique keyspace id: %v", "Unable to determine the shard for the given row.")
+	VT09025 = errorWithoutState("VT09025", vtrpcpb.Code_FAILED_PRECONDITION, "recursive query aborted after %d iterations", "The recursive common table expression produced rows for more iterations than allowed by the --cte-max-recursion-depth flag. Try increasing the flag value, or check the query for infinite recursion.")
 
 	VT10001 = errorWithoutState("VT10001", vtrpcpb.Code_ABORTED, "foreign key constraints are not allowed", "Foreign key constraints are not allowed, see https://vitess.io/blog/2021-06-15-online-ddl-why-no-fk/.")
 
@@ -148,6 +154,11 @@ var (
 		VT03031,
 		VT03032,
 		VT03033,
+		VT03034,
+		VT03035,
+		VT03036,
+		VT03037,
+		VT03038,
 		VT05001,
 		VT05002,
 		VT05003,
@@ -181,6 +192,7 @@ var (
 		VT09022,
 		VT09023,
 		VT09024,
+		VT09025,
 		VT10001,
 		VT12001,
 		VT12002,
@@ -120,6 +120,9 @@ const (
 	CharacterSetMismatch
 	WrongParametersToNativeFct
 
+	// spatial errors
+	GISInvalidData
+
 	// No state should be added below NumOfStates
 	NumOfStates
 )
@@ -18,13 +18,19 @@ package engine
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"strconv"
+	"unicode/utf8"
 
 	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/mysql/json"
 	"github.com/mdibaiee/vitess/go/slice"
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	binlogdatapb "github.com/mdibaiee/vitess/go/vt/proto/binlogdata"
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
 	. "github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
@@ -50,6 +56,23 @@ type AggregateParams struct {
 	// not what we use to aggregate at the engine primitive level.
 	OrigOpcode AggregateOpcode
 
+	// GroupingKeys is used only for the grouping opcode. It holds the offsets
+	// into the GroupByKeys of the primitive for each argument of GROUPING().
+	GroupingKeys []int `json:",omitempty"`
+
+	// DistinctCols is used only for distinct opcodes that can't rely on the input
+	// being sorted by their argument, like when a query has several distinct
+	// aggregations. The values of these columns are then kept in a hash set per group.
+	DistinctCols []CheckCol `json:",omitempty"`
+
+	// ArgCols holds the offsets of the arguments that follow Col, for the group_concat and
+	// json_objectagg aggregations that are computed from the values of every row of the group.
+	ArgCols []int `json:",omitempty"`
+
+	// OrderBy and Separator are used only for group_concat. A nil Separator means the default one.
+	OrderBy   evalengine.Comparison `json:",omitempty"`
+	Separator *string               `json:",omitempty"`
+
 	CollationEnv *collations.Environment
 }
 
@@ -76,9 +99,21 @@ func (ap *AggregateParams) String() string {
 	if ap.WAssigned() {
 		keyCol = fmt.Sprintf("%s|%d", keyCol, ap.WCol)
 	}
+	for _, col := range ap.ArgCols {
+		keyCol += ", " + strconv.Itoa(col)
+	}
 	if sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()) {
 		keyCol += " COLLATE " + ap.CollationEnv.LookupName(ap.Type.Collation())
 	}
+	if len(ap.DistinctCols) > 0 {
+		keyCol = "hash " + GenericJoin(ap.DistinctCols, checkColToString)
+	}
+	if len(ap.OrderBy) > 0 {
+		keyCol += " order by " + GenericJoin(ap.OrderBy, orderByParamsToString)
+	}
+	if ap.Separator != nil {
+		keyCol += " separator " + sqltypes.EncodeStringSQL(*ap.Separator)
+	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
 		dispOrigOp = "_" + ap.OrigOpcode.String()
@@ -108,9 +143,21 @@ type aggregatorDistinct struct {
 	coll         collations.ID
 	collationEnv *collations.Environment
 	values       *evalengine.EnumSetValues
+
+	// seen is used instead of last when the input is not sorted by the distinct columns
+	seen *probeTable
 }
 
 func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
+	if a.seen != nil {
+		for _, col := range a.seen.checkCols {
+			if row[col.Col].IsNull() {
+				return true, nil
+			}
+		}
+		newRow, err := a.seen.exists(row)
+		return newRow == nil, err
+	}
 	if a.column >= 0 {
 		last := a.last
 		next := row[a.column]
@@ -132,6 +179,9 @@ func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
 
 func (a *aggregatorDistinct) reset() {
 	a.last = sqltypes.NULL
+	if a.seen != nil {
+		clear(a.seen.seenRows)
+	}
 }
 
 type aggregatorCount struct {
@@ -254,36 +304,220 @@ func (a *aggregatorScalar) reset() {
 	a.init = false
 }
 
+// aggregatorGroupConcat concatenates the values of the group. When it is given several arguments
+// or an ORDER BY it receives the arguments of every row, otherwise it might also be concatenating
+// the values that were already aggregated by the shards, which gives the same result.
 type aggregatorGroupConcat struct {
-	from  int
-	type_ sqltypes.Type
-
+	from      int
+	args      []int
+	orderBy   evalengine.Comparison
+	separator []byte
+	maxLen    int
+	type_     sqltypes.Type
+	distinct  aggregatorDistinct
+
+	// rows holds the rows of the group in the order of orderBy, when there is one
+	rows   []sqltypes.Row
 	concat []byte
 	n      int
 }
 
-func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
+func (a *aggregatorGroupConcat) add(row []sqltypes.Value) (err error) {
 	if row[a.from].IsNull() {
 		return nil
 	}
-	if a.n > 0 {
-		a.concat = append(a.concat, ',')
+	for _, col := range a.args {
+		if row[col].IsNull() {
+			return nil
+		}
+	}
+	if ret, err := a.distinct.shouldReturn(row); ret {
+		return err
 	}
-	a.concat = append(a.concat, row[a.from].Raw()...)
+	if len(a.orderBy) == 0 {
+		a.concat = a.appendRow(a.concat, row, a.n > 0)
+		a.n++
+		return nil
+	}
+
+	defer evalengine.PanicHandler(&err)
+	idx := sort.Search(len(a.rows), func(i int) bool {
+		return a.orderBy.Compare(row, a.rows[i]) < 0
+	})
+	a.rows = slices.Insert(a.rows, idx, row)
 	a.n++
 	return nil
 }
 
+func (a *aggregatorGroupConcat) appendRow(dst []byte, row []sqltypes.Value, separate bool) []byte {
+	if len(dst) > a.maxLen {
+		// the result is going to be truncated anyway
+		return dst
+	}
+	if separate {
+		dst = append(dst, a.separator...)
+	}
+	dst = append(dst, row[a.from].Raw()...)
+	for _, col := range a.args {
+		dst = append(dst, row[col].Raw()...)
+	}
+	return dst
+}
+
 func (a *aggregatorGroupConcat) finish() sqltypes.Value {
 	if a.n == 0 {
 		return sqltypes.NULL
 	}
-	return sqltypes.MakeTrusted(a.type_, a.concat)
+	concat := a.concat
+	if len(a.orderBy) > 0 {
+		concat = nil
+		for i, row := range a.rows {
+			concat = a.appendRow(concat, row, i > 0)
+		}
+	}
+	return sqltypes.MakeTrusted(a.type_, a.truncate(concat))
+}
+
+// groupConcatLimit returns the number of bytes group_concat values are truncated to
+func groupConcatLimit(groupConcatMaxLen int64) int {
+	if groupConcatMaxLen <= 0 {
+		return math.MaxInt
+	}
+	return int(min(groupConcatMaxLen, math.MaxInt))
+}
+
+// truncate cuts the result to group_concat_max_len bytes, without splitting a character in two
+func (a *aggregatorGroupConcat) truncate(concat []byte) []byte {
+	if len(concat) <= a.maxLen {
+		return concat
+	}
+	concat = concat[:a.maxLen]
+	if sqltypes.IsBinary(a.type_) {
+		return concat
+	}
+	last := len(concat) - 1
+	for last > 0 && !utf8.RuneStart(concat[last]) {
+		last--
+	}
+	if last >= 0 && !utf8.FullRune(concat[last:]) {
+		concat = concat[:last]
+	}
+	return concat
 }
 
 func (a *aggregatorGroupConcat) reset() {
 	a.n = 0
 	a.concat = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
+	a.rows = nil
+	a.distinct.reset()
+}
+
+// aggregatorJSONArray builds the array of JSON_ARRAYAGG, either from the value of every row,
+// or by concatenating the arrays that were already aggregated by the shards.
+type aggregatorJSONArray struct {
+	from   int
+	merge  bool
+	values []*json.Value
+}
+
+func (a *aggregatorJSONArray) add(row []sqltypes.Value) error {
+	if !a.merge {
+		val, err := jsonFromSQL(row[a.from])
+		if err != nil {
+			return err
+		}
+		a.values = append(a.values, val)
+		return nil
+	}
+
+	if row[a.from].IsNull() {
+		return nil
+	}
+	doc, err := json.NewFromSQL(row[a.from])
+	if err != nil {
+		return err
+	}
+	values, ok := doc.Array()
+	if !ok {
+		return vterrors.VT13001(fmt.Sprintf("expected a JSON array to merge, got: %s", row[a.from].RawStr()))
+	}
+	a.values = append(a.values, values...)
+	return nil
+}
+
+func (a *aggregatorJSONArray) finish() sqltypes.Value {
+	if len(a.values) == 0 {
+		return sqltypes.NULL
+	}
+	return sqltypes.MakeTrusted(sqltypes.TypeJSON, json.NewArray(a.values).ToRawBytes())
+}
+
+func (a *aggregatorJSONArray) reset() {
+	a.values = nil
+}
+
+// aggregatorJSONObject builds the object of JSON_OBJECTAGG, either from the key and the value
+// of every row, or by merging the objects that were already aggregated by the shards.
+// Like in MySQL, the last value seen for a duplicate key wins.
+type aggregatorJSONObject struct {
+	from  int
+	value int
+	merge bool
+
+	obj json.Object
+	n   int
+}
+
+func (a *aggregatorJSONObject) add(row []sqltypes.Value) error {
+	if !a.merge {
+		key := row[a.from]
+		if key.IsNull() {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "JSON documents may not contain NULL member names.")
+		}
+		val, err := jsonFromSQL(row[a.value])
+		if err != nil {
+			return err
+		}
+		a.obj.Set(key.ToString(), val, json.Set)
+		a.n++
+		return nil
+	}
+
+	if row[a.from].IsNull() {
+		return nil
+	}
+	doc, err := json.NewFromSQL(row[a.from])
+	if err != nil {
+		return err
+	}
+	obj, ok := doc.Object()
+	if !ok {
+		return vterrors.VT13001(fmt.Sprintf("expected a JSON object to merge, got: %s", row[a.from].RawStr()))
+	}
+	obj.Visit(func(key string, val *json.Value) {
+		a.obj.Set(key, val, json.Set)
+	})
+	a.n++
+	return nil
+}
+
+func (a *aggregatorJSONObject) finish() sqltypes.Value {
+	if a.n == 0 {
+		return sqltypes.NULL
+	}
+	return sqltypes.MakeTrusted(sqltypes.TypeJSON, a.obj.MarshalTo(nil))
+}
+
+func (a *aggregatorJSONObject) reset() {
+	a.obj = json.Object{}
+	a.n = 0
+}
+
+func jsonFromSQL(v sqltypes.Value) (*json.Value, error) {
+	if v.IsNull() {
+		return json.ValueNull, nil
+	}
+	return json.NewFromSQL(v)
 }
 
 type aggregatorGtid struct {
@@ -309,6 +543,21 @@ func (a *aggregatorGtid) reset() {
 	a.shards = a.shards[:0] // safe to reuse because only the serialized form of a.shards is returned
 }
 
+// aggregatorGrouping is the aggregator for GROUPING(). Regular groups are
+// never super-aggregate rows, so it always returns 0; the rollup levels
+// above them overwrite the value with the right bitmask.
+type aggregatorGrouping struct{}
+
+func (a *aggregatorGrouping) add([]sqltypes.Value) error {
+	return nil
+}
+
+func (a *aggregatorGrouping) finish() sqltypes.Value {
+	return sqltypes.NewInt64(0)
+}
+
+func (a *aggregatorGrouping) reset() {}
+
 type aggregationState []aggregator
 
 func (a aggregationState) add(row []sqltypes.Value) error {
@@ -352,7 +601,23 @@ func isComparable(typ sqltypes.Type) bool {
 	return false
 }
 
-func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
+func newAggregatorDistinct(aggr *AggregateParams, column int) aggregatorDistinct {
+	distinct := aggregatorDistinct{
+		column:       column,
+		coll:         aggr.Type.Collation(),
+		collationEnv: aggr.CollationEnv,
+		values:       aggr.Type.Values(),
+	}
+	if len(aggr.DistinctCols) > 0 {
+		distinct.seen = newProbeTable(aggr.DistinctCols, aggr.CollationEnv)
+	}
+	return distinct
+}
+
+// newAggregation creates the aggregation state for the given fields. The values produced
+// by group_concat are truncated to groupConcatMaxLen bytes, unless it is 0 because the
+// effective group_concat_max_len is not known.
+func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, groupConcatMaxLen int64) (aggregationState, []*querypb.Field, error) {
 	fields = slice.Map(fields, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
 
 	agstate := make([]aggregator, len(fields))
@@ -382,13 +647,8 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 
 		case AggregateCount, AggregateCountDistinct:
 			ag = &aggregatorCount{
-				from: aggr.Col,
-				distinct: aggregatorDistinct{
-					column:       distinct,
-					coll:         aggr.Type.Collation(),
-					collationEnv: aggr.CollationEnv,
-					values:       aggr.Type.Values(),
-				},
+				from:     aggr.Col,
+				distinct: newAggregatorDistinct(aggr, distinct),
 			}
 
 		case AggregateSum, AggregateSumDistinct:
@@ -401,14 +661,9 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 			}
 
 			ag = &aggregatorSum{
-				from: aggr.Col,
-				sum:  sum,
-				distinct: aggregatorDistinct{
-					column:       distinct,
-					coll:         aggr.Type.Collation(),
-					collationEnv: aggr.CollationEnv,
-					values:       aggr.Type.Values(),
-				},
+				from:     aggr.Col,
+				sum:      sum,
+				distinct: newAggregatorDistinct(aggr, distinct),
 			}
 
 		case AggregateMin:
@@ -434,7 +689,41 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 			ag = &aggregatorScalar{from: aggr.Col}
 
 		case AggregateGroupConcat:
-			ag = &aggregatorGroupConcat{from: aggr.Col, type_: targetType}
+			separator := ","
+			if aggr.Separator != nil {
+				separator = *aggr.Separator
+			}
+			ag = &aggregatorGroupConcat{
+				from:      aggr.Col,
+				args:      aggr.ArgCols,
+				orderBy:   aggr.OrderBy,
+				separator: []byte(separator),
+				maxLen:    groupConcatLimit(groupConcatMaxLen),
+				type_:     targetType,
+				distinct:  newAggregatorDistinct(aggr, -1),
+			}
+
+		case AggregateJSONArrayAgg:
+			ag = &aggregatorJSONArray{
+				from:  aggr.Col,
+				merge: aggr.OrigOpcode == AggregateJSONArrayAgg,
+			}
+
+		case AggregateJSONObjectAgg:
+			obj := &aggregatorJSONObject{
+				from:  aggr.Col,
+				merge: aggr.OrigOpcode == AggregateJSONObjectAgg,
+			}
+			if !obj.merge {
+				if len(aggr.ArgCols) != 1 {
+					return nil, nil, vterrors.VT13001("json_objectagg without the offset of its value")
+				}
+				obj.value = aggr.ArgCols[0]
+			}
+			ag = obj
+
+		case AggregateGrouping:
+			ag = &aggregatorGrouping{}
 
 		default:
 			panic("BUG: unexpected Aggregation opcode")
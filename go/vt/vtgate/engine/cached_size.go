@@ -35,7 +35,7 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(224)
 	}
 	// field Type github.com/mdibaiee/vitess/go/vt/vtgate/evalengine.Type
 	size += cached.Type.CachedSize(false)
@@ -47,6 +47,30 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	// field Original *github.com/mdibaiee/vitess/go/vt/sqlparser.AliasedExpr
 	size += cached.Original.CachedSize(true)
+	// field GroupingKeys []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.GroupingKeys)) * int64(8))
+	}
+	// field DistinctCols []github.com/mdibaiee/vitess/go/vt/vtgate/engine.CheckCol
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.DistinctCols)) * int64(48))
+		for _, elem := range cached.DistinctCols {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field ArgCols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ArgCols)) * int64(8))
+	}
+	// field OrderBy github.com/mdibaiee/vitess/go/vt/vtgate/evalengine.Comparison
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.OrderBy)) * int64(56))
+		for _, elem := range cached.OrderBy {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field Separator *string
+	size += hack.RuntimeAllocSize(int64(16))
 	// field CollationEnv *github.com/mdibaiee/vitess/go/mysql/collations.Environment
 	size += cached.CollationEnv.CachedSize(true)
 	return size
@@ -561,6 +585,76 @@ func (cached *InsertSelect) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *JSONTable) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(96)
+	}
+	// field Input github.com/mdibaiee/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field JSONExpr github.com/mdibaiee/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.JSONExpr.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ASTJSONExpr github.com/mdibaiee/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.ASTJSONExpr.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Path string
+	size += hack.RuntimeAllocSize(int64(len(cached.Path)))
+	// field Columns []*github.com/mdibaiee/vitess/go/vt/vtgate/engine.JSONTableColumn
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(8))
+		for _, elem := range cached.Columns {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
+func (cached *JSONTableColumn) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(112)
+	}
+	// field Name string
+	size += hack.RuntimeAllocSize(int64(len(cached.Name)))
+	// field Type github.com/mdibaiee/vitess/go/vt/vtgate/evalengine.Type
+	size += cached.Type.CachedSize(false)
+	// field Path string
+	size += hack.RuntimeAllocSize(int64(len(cached.Path)))
+	// field OnEmpty *github.com/mdibaiee/vitess/go/vt/vtgate/engine.JSONTableResponse
+	size += cached.OnEmpty.CachedSize(true)
+	// field OnError *github.com/mdibaiee/vitess/go/vt/vtgate/engine.JSONTableResponse
+	size += cached.OnError.CachedSize(true)
+	// field Nested []*github.com/mdibaiee/vitess/go/vt/vtgate/engine.JSONTableColumn
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Nested)) * int64(8))
+		for _, elem := range cached.Nested {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
+func (cached *JSONTableResponse) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(24)
+	}
+	// field Default string
+	size += hack.RuntimeAllocSize(int64(len(cached.Default)))
+	return size
+}
 
 //go:nocheckptr
 func (cached *Join) CachedSize(alloc bool) int64 {
@@ -801,7 +895,7 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(144)
+		size += int64(160)
 	}
 	// field Original string
 	size += hack.RuntimeAllocSize(int64(len(cached.Original)))
@@ -857,6 +951,47 @@ func (cached *Projection) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+
+//go:nocheckptr
+func (cached *RecurseCTE) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field Seed github.com/mdibaiee/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Seed.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Term github.com/mdibaiee/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Term.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Vars map[string]int
+	if cached.Vars != nil {
+		size += int64(48)
+		hmap := reflect.ValueOf(cached.Vars)
+		numBuckets := int(math.Pow(2, float64((*(*uint8)(unsafe.Pointer(hmap.Pointer() + uintptr(9)))))))
+		numOldBuckets := (*(*uint16)(unsafe.Pointer(hmap.Pointer() + uintptr(10))))
+		size += hack.RuntimeAllocSize(int64(numOldBuckets * 208))
+		if len(cached.Vars) > 0 || numBuckets > 1 {
+			size += hack.RuntimeAllocSize(int64(numBuckets * 208))
+		}
+		for k := range cached.Vars {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+		}
+	}
+	// field CheckCols []github.com/mdibaiee/vitess/go/vt/vtgate/engine.CheckCol
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.CheckCols)) * int64(48))
+		for _, elem := range cached.CheckCols {
+			size += elem.CachedSize(false)
+		}
+	}
+	return size
+}
 func (cached *RenameFields) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1074,7 +1209,7 @@ func (cached *SemiJoin) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(48)
+		size += int64(128)
 	}
 	// field Left github.com/mdibaiee/vitess/go/vt/vtgate/engine.Primitive
 	if cc, ok := cached.Left.(cachedObject); ok {
@@ -1098,6 +1233,20 @@ func (cached *SemiJoin) CachedSize(alloc bool) int64 {
 			size += hack.RuntimeAllocSize(int64(len(k)))
 		}
 	}
+	// field SubqueryResult string
+	size += hack.RuntimeAllocSize(int64(len(cached.SubqueryResult)))
+	// field HasValues string
+	size += hack.RuntimeAllocSize(int64(len(cached.HasValues)))
+	// field Filter github.com/mdibaiee/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Filter.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ASTFilter github.com/mdibaiee/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.ASTFilter.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ValueCol *int
+	size += hack.RuntimeAllocSize(int64(8))
 	return size
 }
 func (cached *Send) CachedSize(alloc bool) int64 {
@@ -1299,12 +1448,18 @@ func (cached *UncorrelatedSubquery) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(80)
+		size += int64(128)
 	}
 	// field SubqueryResult string
 	size += hack.RuntimeAllocSize(int64(len(cached.SubqueryResult)))
 	// field HasValues string
 	size += hack.RuntimeAllocSize(int64(len(cached.HasValues)))
+	// field MaxResult string
+	size += hack.RuntimeAllocSize(int64(len(cached.MaxResult)))
+	// field HasNulls string
+	size += hack.RuntimeAllocSize(int64(len(cached.HasNulls)))
+	// field ComparisonType github.com/mdibaiee/vitess/go/vt/vtgate/evalengine.Type
+	size += cached.ComparisonType.CachedSize(false)
 	// field Subquery github.com/mdibaiee/vitess/go/vt/vtgate/engine.Primitive
 	if cc, ok := cached.Subquery.(cachedObject); ok {
 		size += cc.CachedSize(true)
@@ -1549,6 +1704,59 @@ func (cached *VitessMetadata) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Value)))
 	return size
 }
+func (cached *Window) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Functions []*github.com/mdibaiee/vitess/go/vt/vtgate/engine.WindowFunc
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Functions)) * int64(8))
+		for _, elem := range cached.Functions {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Input github.com/mdibaiee/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
+func (cached *WindowFunc) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(112)
+	}
+	// field Aggregate *github.com/mdibaiee/vitess/go/vt/vtgate/engine.AggregateParams
+	size += cached.Aggregate.CachedSize(true)
+	// field Partition github.com/mdibaiee/vitess/go/vt/vtgate/evalengine.Comparison
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Partition)) * int64(56))
+		for _, elem := range cached.Partition {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field OrderBy github.com/mdibaiee/vitess/go/vt/vtgate/evalengine.Comparison
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.OrderBy)) * int64(56))
+		for _, elem := range cached.OrderBy {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field Frame *github.com/mdibaiee/vitess/go/vt/vtgate/engine.WindowFrame
+	if cached.Frame != nil {
+		size += hack.RuntimeAllocSize(int64(40))
+	}
+	// field Alias string
+	size += hack.RuntimeAllocSize(int64(len(cached.Alias)))
+	return size
+}
 
 //go:nocheckptr
 func (cached *shardRoute) CachedSize(alloc bool) int64 {
@@ -216,6 +216,10 @@ func (cc CheckCol) SwitchToWeightString() CheckCol {
 	}
 }
 
+func checkColToString(in any) string {
+	return in.(CheckCol).String()
+}
+
 func (cc CheckCol) String() string {
 	var collation string
 	if cc.Type.Valid() && sqltypes.IsText(cc.Type.Type()) && cc.Type.Collation() != collations.Unknown {
@@ -40,6 +40,10 @@ type DMLWithInput struct {
 	DMLs       []Primitive
 	OutputCols [][]int
 	BVList     []map[string]int
+
+	// MoveRows is set when the DMLs delete the input rows and insert them again with new values,
+	// which can place them on a different shard. The rows affected are then the rows deleted.
+	MoveRows bool
 }
 
 func (dml *DMLWithInput) RouteType() string {
@@ -80,9 +84,12 @@ func (dml *DMLWithInput) TryExecute(ctx context.Context, vcursor VCursor, bindVa
 			return nil, err
 		}
 
-		if res == nil {
+		switch {
+		case res == nil:
 			res = qr
-		} else {
+		case dml.MoveRows:
+			// the inserted rows are the deleted rows, which are already counted
+		default:
 			res.RowsAffected += qr.RowsAffected
 		}
 	}
@@ -184,6 +191,9 @@ func (dml *DMLWithInput) description() PrimitiveDescription {
 	if len(bvList) > 0 {
 		other["BindVars"] = bvList
 	}
+	if dml.MoveRows {
+		other["MoveRows"] = true
+	}
 	return PrimitiveDescription{
 		OperatorType:     "DMLWithInput",
 		TargetTabletType: topodatapb.TabletType_PRIMARY,
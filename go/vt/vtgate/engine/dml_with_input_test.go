@@ -256,3 +256,34 @@ func TestUpdateWithInputNonLiteral(t *testing.T) {
 	})
 	assert.EqualValues(t, 3, qr.RowsAffected)
 }
+
+// TestDMLWithInputMoveRows tests that rows which are deleted and inserted again are counted by the delete.
+func TestDMLWithInputMoveRows(t *testing.T) {
+	input := &fakePrimitive{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("id|col", "int64|varchar"),
+			"1|a", "2|b"),
+	}}
+	del := &fakePrimitive{results: []*sqltypes.Result{{RowsAffected: 2}}}
+	ins := &fakePrimitive{results: []*sqltypes.Result{{RowsAffected: 1}, {RowsAffected: 1}}}
+
+	dml := &DMLWithInput{
+		Input:      input,
+		DMLs:       []Primitive{del, ins},
+		OutputCols: [][]int{{0}, nil},
+		BVList:     []map[string]int{nil, {"t_id": 0, "t_col": 1}},
+		MoveRows:   true,
+	}
+
+	vc := newDMLTestVCursor("-20", "20-")
+	qr, err := dml.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, qr.RowsAffected)
+	del.ExpectLog(t, []string{
+		`Execute dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"} false`,
+	})
+	ins.ExpectLog(t, []string{
+		`Execute dml_vals: type:TUPLE values:{type:TUPLE} t_col: type:VARCHAR value:"a" t_id: type:INT64 value:"1" false`,
+		`Execute dml_vals: type:TUPLE values:{type:TUPLE} t_col: type:VARCHAR value:"b" t_id: type:INT64 value:"2" false`,
+	})
+}
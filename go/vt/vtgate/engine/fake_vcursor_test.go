@@ -48,6 +48,8 @@ import (
 
 var testMaxMemoryRows = 100
 var testIgnoreMaxMemoryRows = false
+var testCTEMaxRecursionDepth = 10
+var testSpillBudget *DiskBudget
 
 var _ VCursor = (*noopVCursor)(nil)
 var _ SessionActions = (*noopVCursor)(nil)
@@ -143,6 +145,10 @@ func (t *noopVCursor) TimeZone() *time.Location {
 	return nil
 }
 
+func (t *noopVCursor) GroupConcatMaxLen() int64 {
+	return 0
+}
+
 func (t *noopVCursor) SQLMode() string {
 	return config.DefaultSQLMode
 }
@@ -337,6 +343,14 @@ func (t *noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
 
+func (t *noopVCursor) CTEMaxRecursionDepth() int {
+	return testCTEMaxRecursionDepth
+}
+
+func (t *noopVCursor) SpillBudget() *DiskBudget {
+	return testSpillBudget
+}
+
 func (t *noopVCursor) GetKeyspace() string {
 	return ""
 }
@@ -18,7 +18,10 @@ package engine
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"math"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -93,6 +96,10 @@ type (
 
 // TryExecute implements the Primitive interface
 func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		return executeStreamed(ctx, vcursor, hj, bindVars, wantfields)
+	}
+
 	lresult, err := vcursor.ExecutePrimitive(ctx, hj.Left, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -133,9 +140,13 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 
 // TryStreamExecute implements the Primitive interface
 func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
-	// build the probe table from the LHS result
-	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	// fetch the LHS rows, moving them to partitions on disk if there are too many of them to keep in memory
 	var lfields []*querypb.Field
+	var lrows []sqltypes.Row
+	var spill *hashJoinSpill
+	defer func() {
+		spill.close()
+	}()
 	var mu sync.Mutex
 	err := vcursor.StreamExecutePrimitive(ctx, hj.Left, bindVars, wantfields, func(result *sqltypes.Result) error {
 		mu.Lock()
@@ -143,13 +154,21 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 		if len(lfields) == 0 && len(result.Fields) != 0 {
 			lfields = result.Fields
 		}
-		for _, current := range result.Rows {
-			err := pt.addLeftRow(current)
-			if err != nil {
-				return err
-			}
+		if spill != nil {
+			return spill.addLeftRows(result.Rows)
 		}
-		return nil
+		lrows = append(lrows, result.Rows...)
+		if !vcursor.ExceedsMaxMemoryRows(len(lrows)) {
+			return nil
+		}
+		budget := vcursor.SpillBudget()
+		if budget == nil {
+			return nil
+		}
+		spill = hj.newSpill(budget)
+		err := spill.addLeftRows(lrows)
+		lrows = nil
+		return err
 	})
 	if err != nil {
 		return err
@@ -158,6 +177,19 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 	var sendFields atomic.Bool
 	sendFields.Store(wantfields)
 
+	if spill != nil {
+		return hj.streamSpilled(ctx, vcursor, bindVars, spill, lfields, &sendFields, callback)
+	}
+
+	// build the probe table from the LHS result
+	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	for _, current := range lrows {
+		err := pt.addLeftRow(current)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, sendFields.Load(), func(result *sqltypes.Result) error {
 		mu.Lock()
 		defer mu.Unlock()
@@ -201,6 +233,83 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 	return nil
 }
 
+// streamSpilled joins the rows after the LHS has been partitioned to disk. The RHS is partitioned the same way,
+// and every partition is then joined in memory. The joined rows are written to disk along with the position
+// of their RHS row, so that they can be sent in the same order as when the join is done in memory.
+func (hj *HashJoin) streamSpilled(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	spill *hashJoinSpill,
+	lfields []*querypb.Field,
+	sendFields *atomic.Bool,
+	callback func(*sqltypes.Result) error,
+) error {
+	var mu sync.Mutex
+	var seq int64
+	err := vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, sendFields.Load(), func(result *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(result.Fields) != 0 && sendFields.CompareAndSwap(true, false) {
+			if err := callback(&sqltypes.Result{Fields: joinFields(lfields, result.Fields, hj.Cols)}); err != nil {
+				return err
+			}
+		}
+		for _, row := range result.Rows {
+			seq++
+			if err := spill.addRightRow(seq, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if hj.Opcode == LeftJoin && sendFields.CompareAndSwap(true, false) {
+		rres, err := hj.Right.GetFields(ctx, vcursor, bindVars)
+		if err != nil {
+			return err
+		}
+		if err := callback(&sqltypes.Result{Fields: joinFields(lfields, rres.Fields, hj.Cols)}); err != nil {
+			return err
+		}
+	}
+
+	if err := spill.joinPartitions(vcursor); err != nil {
+		return err
+	}
+
+	sources := make([]rowSource, 0, len(spill.output))
+	for _, out := range spill.output {
+		source, err := spillFileSource(out)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+	intType := evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)
+	bySeqAndChunk := evalengine.Comparison{
+		{Col: 0, WeightStringCol: -1, Type: intType, CollationEnv: hj.CollationEnv},
+		{Col: 1, WeightStringCol: -1, Type: intType, CollationEnv: hj.CollationEnv},
+	}
+	err = mergeSpilled(sources, bySeqAndChunk, math.MaxInt, func(rows []sqltypes.Row) error {
+		for i, row := range rows {
+			rows[i] = row[2:]
+		}
+		return callback(&sqltypes.Result{Rows: rows})
+	})
+	if err != nil || spill.unmatched == nil {
+		return err
+	}
+
+	// the LHS rows that didn't match anything are sent last
+	return sendSpillFile(spill.unmatched, func(rows []sqltypes.Row) error {
+		return callback(&sqltypes.Result{Rows: rows})
+	})
+}
+
 // RouteType implements the Primitive interface
 func (hj *HashJoin) RouteType() string {
 	return "HashJoin"
@@ -330,3 +439,190 @@ func (pt *hashJoinProbeTable) notFetched() (rows []sqltypes.Row) {
 	}
 	return
 }
+
+// hashJoinPartitions is the number of partitions the inputs of a hash join are split into when they are spilled to disk
+const hashJoinPartitions = 64
+
+// hashJoinSpill holds the partitions of a hash join whose LHS did not fit in memory.
+// Rows are assigned to a partition using the hash of their join column,
+// so rows that can match each other always end up in the same partition.
+type hashJoinSpill struct {
+	hj     *HashJoin
+	budget *DiskBudget
+	hasher *hashJoinProbeTable
+
+	left, right, output []*spillFile
+	unmatched           *spillFile
+}
+
+func (hj *HashJoin) newSpill(budget *DiskBudget) *hashJoinSpill {
+	return &hashJoinSpill{
+		hj:     hj,
+		budget: budget,
+		hasher: newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values),
+		left:   make([]*spillFile, hashJoinPartitions),
+		right:  make([]*spillFile, hashJoinPartitions),
+	}
+}
+
+func (s *hashJoinSpill) partition(val sqltypes.Value) (int, error) {
+	hash, err := s.hasher.hash(val)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint64(hash[:8]) % hashJoinPartitions), nil
+}
+
+func (s *hashJoinSpill) write(files []*spillFile, idx int, row sqltypes.Row) (err error) {
+	if files[idx] == nil {
+		files[idx], err = s.budget.newSpillFile()
+		if err != nil {
+			return err
+		}
+	}
+	return files[idx].write(row)
+}
+
+func (s *hashJoinSpill) addLeftRows(rows []sqltypes.Row) error {
+	for _, row := range rows {
+		idx, err := s.partition(row[s.hj.LHSKey])
+		if err != nil {
+			return err
+		}
+		if err := s.write(s.left, idx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRightRow writes the row to its partition, prefixed with the position of the row in the RHS
+func (s *hashJoinSpill) addRightRow(seq int64, row sqltypes.Row) error {
+	val := row[s.hj.RHSKey]
+	if val.IsNull() {
+		// NULL never matches anything, and the RHS rows are only sent if they do
+		return nil
+	}
+	idx, err := s.partition(val)
+	if err != nil {
+		return err
+	}
+	return s.write(s.right, idx, append(sqltypes.Row{sqltypes.NewInt64(seq)}, row...))
+}
+
+// joinPartitions joins the partitions one at a time. The LHS of a partition is loaded in chunks of at most
+// MaxMemoryRows rows, since rows with the same join column can not be split over several partitions.
+// The joined rows of every chunk are written to their own output file, prefixed with the position
+// of the RHS row they were joined with and with the position of the chunk, counting backwards.
+// The probe table returns the last added row first, so the rows of the last chunk must come first.
+func (s *hashJoinSpill) joinPartitions(vcursor VCursor) error {
+	for idx := range s.left {
+		if s.left[idx] == nil {
+			continue
+		}
+		r, err := s.left[idx].reader()
+		if err != nil {
+			return err
+		}
+		for chunk := int64(0); ; chunk-- {
+			pt, done, err := s.loadChunk(vcursor, r)
+			if err != nil {
+				return err
+			}
+			if len(pt.innerMap) > 0 {
+				if err := s.probe(pt, s.right[idx], sqltypes.NewInt64(chunk)); err != nil {
+					return err
+				}
+				if err := s.addUnmatched(pt); err != nil {
+					return err
+				}
+			}
+			if done {
+				break
+			}
+		}
+		s.left[idx].close()
+		s.left[idx] = nil
+		if s.right[idx] != nil {
+			s.right[idx].close()
+			s.right[idx] = nil
+		}
+	}
+	return nil
+}
+
+// loadChunk builds a probe table from the next rows of the LHS partition
+func (s *hashJoinSpill) loadChunk(vcursor VCursor, r *spillReader) (pt *hashJoinProbeTable, done bool, err error) {
+	hj := s.hj
+	pt = newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	for rows := 1; !vcursor.ExceedsMaxMemoryRows(rows); rows++ {
+		row, err := r.next()
+		if err == io.EOF {
+			return pt, true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if err := pt.addLeftRow(row); err != nil {
+			return nil, false, err
+		}
+	}
+	return pt, false, nil
+}
+
+func (s *hashJoinSpill) probe(pt *hashJoinProbeTable, right *spillFile, chunk sqltypes.Value) error {
+	if right == nil {
+		return nil
+	}
+	var out *spillFile
+	err := readSpillFile(right, func(row sqltypes.Row) error {
+		matches, err := pt.get(row[1:])
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			if out == nil {
+				out, err = s.budget.newSpillFile()
+				if err != nil {
+					return err
+				}
+				s.output = append(s.output, out)
+			}
+			if err := out.write(append(sqltypes.Row{row[0], chunk}, match...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *hashJoinSpill) addUnmatched(pt *hashJoinProbeTable) (err error) {
+	if s.hj.Opcode != LeftJoin {
+		return nil
+	}
+	for _, row := range pt.notFetched() {
+		if s.unmatched == nil {
+			s.unmatched, err = s.budget.newSpillFile()
+			if err != nil {
+				return err
+			}
+		}
+		if err := s.unmatched.write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *hashJoinSpill) close() {
+	if s == nil {
+		return
+	}
+	closeSpillFiles(s.left)
+	closeSpillFiles(s.right)
+	closeSpillFiles(s.output)
+	if s.unmatched != nil {
+		s.unmatched.close()
+	}
+}
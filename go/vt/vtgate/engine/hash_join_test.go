@@ -18,12 +18,14 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/mdibaiee/vitess/go/mysql/collations"
 	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/test/utils"
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 )
@@ -172,3 +174,80 @@ func typeForOffset(i int) evalengine.Type {
 		panic(i)
 	}
 }
+
+func TestHashJoinSpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	testMaxMemoryRows = 5
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillBudget = nil
+	}()
+
+	// every join value is used by more LHS rows than fit in memory
+	var lrows, rrows []string
+	for i := 0; i < 40; i++ {
+		if i%9 == 0 {
+			lrows = append(lrows, fmt.Sprintf("null|l%d", i))
+			continue
+		}
+		lrows = append(lrows, fmt.Sprintf("%d|l%d", i%5, i))
+	}
+	for i := 0; i < 30; i++ {
+		if i%7 == 0 {
+			rrows = append(rrows, fmt.Sprintf("null|r%d", i))
+			continue
+		}
+		rrows = append(rrows, fmt.Sprintf("%d|r%d", i%17, i))
+	}
+	input := func(names string, rows []string) Primitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(sqltypes.MakeTestFields(names, "int64|varchar"), rows...),
+			},
+		}
+	}
+
+	for _, opcode := range []JoinOpcode{InnerJoin, LeftJoin} {
+		t.Run(opcode.String(), func(t *testing.T) {
+			jn := &HashJoin{
+				Opcode:         opcode,
+				Cols:           []int{-1, -2, 1, 2},
+				LHSKey:         0,
+				RHSKey:         0,
+				Collation:      collations.CollationBinaryID,
+				ComparisonType: sqltypes.Int64,
+				CollationEnv:   collations.MySQL8(),
+			}
+
+			testSpillBudget = nil
+			jn.Left, jn.Right = input("col1|col2", lrows), input("col3|col4", rrows)
+			want, err := wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+
+			testSpillBudget = NewDiskBudget(t.TempDir(), 0, nil)
+			before := spilledBytes.Get()
+			jn.Left, jn.Right = input("col1|col2", lrows), input("col3|col4", rrows)
+			got, err := wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			require.Greater(t, spilledBytes.Get(), before)
+			require.Zero(t, testSpillBudget.Used())
+
+			// the non-streaming execution spills the same way
+			before = spilledBytes.Get()
+			jn.Left, jn.Right = input("col1|col2", lrows), input("col3|col4", rrows)
+			executed, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			require.Greater(t, spilledBytes.Get(), before)
+			require.Zero(t, testSpillBudget.Used())
+
+			if opcode == InnerJoin {
+				utils.MustMatch(t, want, got)
+				utils.MustMatch(t, want, executed)
+				return
+			}
+			// the rows without a match are not sent in any particular order
+			expectResultAnyOrder(t, got, want)
+			expectResultAnyOrder(t, executed, want)
+		})
+	}
+}
@@ -54,6 +54,11 @@ type Join struct {
 
 // TryExecute performs a non-streaming exec.
 func (jn *Join) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		// the streaming path does not buffer the joined rows
+		return executeStreamed(ctx, vcursor, jn, bindVars, wantfields)
+	}
+
 	joinVars := make(map[string]*querypb.BindVariable)
 	lresult, err := vcursor.ExecutePrimitive(ctx, jn.Left, bindVars, wantfields)
 	if err != nil {
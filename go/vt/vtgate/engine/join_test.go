@@ -140,10 +140,13 @@ func TestJoinExecuteMaxMemoryRows(t *testing.T) {
 
 	testCases := []struct {
 		ignoreMaxMemoryRows bool
+		spill               bool
 		err                 string
 	}{
-		{true, ""},
-		{false, "in-memory row count exceeded allowed limit of 3"},
+		{true, false, ""},
+		{false, false, "in-memory row count exceeded allowed limit of 3"},
+		// when spilling is allowed the join goes through the streaming path, which does not buffer the rows
+		{false, true, ""},
 	}
 	for _, test := range testCases {
 		leftPrim := &fakePrimitive{
@@ -195,8 +198,13 @@ func TestJoinExecuteMaxMemoryRows(t *testing.T) {
 			},
 		}
 		testIgnoreMaxMemoryRows = test.ignoreMaxMemoryRows
+		testSpillBudget = nil
+		if test.spill {
+			testSpillBudget = NewDiskBudget(t.TempDir(), 0, nil)
+		}
 		_, err := jn.TryExecute(context.Background(), &noopVCursor{}, bv, true)
-		if testIgnoreMaxMemoryRows {
+		testSpillBudget = nil
+		if test.err == "" {
 			require.NoError(t, err)
 		} else {
 			require.EqualError(t, err, test.err)
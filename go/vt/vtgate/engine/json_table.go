@@ -0,0 +1,457 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mdibaiee/vitess/go/mysql/json"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*JSONTable)(nil)
+
+// JSONTable is a primitive that evaluates a JSON_TABLE expression for every row of its input.
+// The output rows contain the columns of the JSON table, followed by the columns of the
+// input row that the JSON document was read from.
+type JSONTable struct {
+	noTxNeeded
+
+	Input Primitive
+
+	// JSONExpr is evaluated against every input row to produce the JSON document
+	JSONExpr    evalengine.Expr
+	ASTJSONExpr sqlparser.Expr
+
+	// Path is the path that produces the rows of the table
+	Path    string
+	Columns []*JSONTableColumn
+
+	// Outer is set when the JSON table is on the right side of a LEFT JOIN.
+	// An input row that produces no rows is then returned once, with NULL in the JSON table columns.
+	Outer bool
+}
+
+// JSONTableColumn is a column of a JSON_TABLE, or a NESTED PATH clause with its own columns
+type JSONTableColumn struct {
+	Name string
+	Type evalengine.Type
+
+	// Ordinality is set for FOR ORDINALITY columns
+	Ordinality bool
+	// Exists is set for EXISTS PATH columns
+	Exists bool
+	Path   string
+
+	// OnEmpty and OnError are nil when the column returns NULL in those cases
+	OnEmpty *JSONTableResponse
+	OnError *JSONTableResponse
+
+	// Nested is the list of columns of a NESTED PATH clause, which uses Path
+	Nested []*JSONTableColumn
+}
+
+// JSONTableResponse is what a JSON_TABLE column produces when the path finds no value, or an invalid one
+type JSONTableResponse struct {
+	Type sqlparser.JtOnResponseType
+	// Default is the JSON text of the DEFAULT value
+	Default string
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (jt *JSONTable) RouteType() string {
+	return jt.Input.RouteType()
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (jt *JSONTable) GetKeyspaceName() string {
+	return jt.Input.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (jt *JSONTable) GetTableName() string {
+	return jt.Input.GetTableName()
+}
+
+// Inputs implements the Primitive interface
+func (jt *JSONTable) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{jt.Input}, nil
+}
+
+// GetFields implements the Primitive interface
+func (jt *JSONTable) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := jt.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: jt.fields(qr.Fields)}, nil
+}
+
+// TryExecute implements the Primitive interface
+func (jt *JSONTable) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	qr, err := vcursor.ExecutePrimitive(ctx, jt.Input, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	eval, err := jt.newEvaluator(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := eval.rows(qr.Rows)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: jt.fields(qr.Fields), Rows: rows}, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (jt *JSONTable) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	eval, err := jt.newEvaluator(ctx, vcursor, bindVars)
+	if err != nil {
+		return err
+	}
+	var mu sync.Mutex
+	return vcursor.StreamExecutePrimitive(ctx, jt.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		rows, err := eval.rows(qr.Rows)
+		if err != nil {
+			return err
+		}
+		return callback(&sqltypes.Result{Fields: jt.fields(qr.Fields), Rows: rows})
+	})
+}
+
+func (jt *JSONTable) fields(input []*querypb.Field) []*querypb.Field {
+	if input == nil {
+		return nil
+	}
+	var out []*querypb.Field
+	var add func(cols []*JSONTableColumn)
+	add = func(cols []*JSONTableColumn) {
+		for _, col := range cols {
+			if col.Nested != nil {
+				add(col.Nested)
+				continue
+			}
+			out = append(out, col.Type.ToField(col.Name))
+		}
+	}
+	add(jt.Columns)
+	return append(out, input...)
+}
+
+func (jt *JSONTable) description() PrimitiveDescription {
+	other := map[string]any{
+		"JSONExpr": sqlparser.String(jt.ASTJSONExpr),
+		"Path":     jt.Path,
+		"Columns":  jsonTableColumnsToString(jt.Columns),
+	}
+	if jt.Outer {
+		other["Outer"] = true
+	}
+	return PrimitiveDescription{
+		OperatorType: "JSONTable",
+		Other:        other,
+	}
+}
+
+func jsonTableColumnsToString(cols []*JSONTableColumn) string {
+	return GenericJoin(cols, func(input any) string {
+		col := input.(*JSONTableColumn)
+		switch {
+		case col.Nested != nil:
+			return fmt.Sprintf("nested path '%s' columns(%s)", col.Path, jsonTableColumnsToString(col.Nested))
+		case col.Ordinality:
+			return col.Name + " for ordinality"
+		case col.Exists:
+			return fmt.Sprintf("%s %s exists path '%s'", col.Name, strings.ToLower(col.Type.Type().String()), col.Path)
+		default:
+			return fmt.Sprintf("%s %s path '%s'", col.Name, strings.ToLower(col.Type.Type().String()), col.Path)
+		}
+	})
+}
+
+// jsonTableEvaluator produces the rows of a JSON_TABLE, using the paths of the primitive
+// parsed once per execution
+type jsonTableEvaluator struct {
+	jt      *JSONTable
+	env     *evalengine.ExpressionEnv
+	sqlmode evalengine.SQLMode
+	root    *jsonTableLevel
+}
+
+// jsonTableLevel is the row path of the JSON_TABLE, or the path of a NESTED PATH clause,
+// together with the columns defined at that level
+type jsonTableLevel struct {
+	path   *json.Path
+	cols   []*JSONTableColumn
+	paths  []*json.Path
+	nested []*jsonTableLevel
+	// width is the number of output columns of this level, including the nested ones
+	width int
+}
+
+func (jt *JSONTable) newEvaluator(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*jsonTableEvaluator, error) {
+	root, err := newJSONTableLevel(jt.Path, jt.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonTableEvaluator{
+		jt:      jt,
+		env:     evalengine.NewExpressionEnv(ctx, bindVars, vcursor),
+		sqlmode: evalengine.ParseSQLMode(vcursor.SQLMode()),
+		root:    root,
+	}, nil
+}
+
+func newJSONTableLevel(path string, cols []*JSONTableColumn) (*jsonTableLevel, error) {
+	parsePath := func(path string) (*json.Path, error) {
+		var p json.PathParser
+		jp, err := p.ParseBytes([]byte(path))
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid JSON path expression '%s' in JSON_TABLE: %v", path, err)
+		}
+		return jp, nil
+	}
+
+	var err error
+	level := &jsonTableLevel{
+		cols:   cols,
+		paths:  make([]*json.Path, len(cols)),
+		nested: make([]*jsonTableLevel, len(cols)),
+	}
+	if level.path, err = parsePath(path); err != nil {
+		return nil, err
+	}
+	for i, col := range cols {
+		switch {
+		case col.Nested != nil:
+			if level.nested[i], err = newJSONTableLevel(col.Path, col.Nested); err != nil {
+				return nil, err
+			}
+			level.width += level.nested[i].width
+		case col.Ordinality:
+			level.width++
+		default:
+			if level.paths[i], err = parsePath(col.Path); err != nil {
+				return nil, err
+			}
+			level.width++
+		}
+	}
+	return level, nil
+}
+
+func (e *jsonTableEvaluator) rows(input []sqltypes.Row) ([]sqltypes.Row, error) {
+	var out []sqltypes.Row
+	for _, row := range input {
+		e.env.Row = row
+		res, err := e.env.Evaluate(e.jt.JSONExpr)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := res.JSON("json_table")
+		if err != nil {
+			return nil, err
+		}
+
+		var jtRows []sqltypes.Row
+		if doc != nil {
+			for i, match := range e.root.match(doc) {
+				rows, err := e.levelRows(e.root, match, i+1)
+				if err != nil {
+					return nil, err
+				}
+				jtRows = append(jtRows, rows...)
+			}
+		}
+		if len(jtRows) == 0 && e.jt.Outer {
+			jtRows = append(jtRows, nullRow(e.root.width))
+		}
+		for _, jtRow := range jtRows {
+			out = append(out, append(jtRow, row...))
+		}
+	}
+	return out, nil
+}
+
+func (l *jsonTableLevel) match(doc *json.Value) []*json.Value {
+	var matches []*json.Value
+	l.path.Match(doc, true, func(value *json.Value) {
+		matches = append(matches, value)
+	})
+	return matches
+}
+
+// levelRows returns the rows produced by one value matched by the path of the level.
+// Sibling NESTED PATH clauses produce their rows one after the other, with NULL in the
+// columns of the other clauses. If none of them match, a single row is produced.
+func (e *jsonTableEvaluator) levelRows(l *jsonTableLevel, value *json.Value, ordinality int) ([]sqltypes.Row, error) {
+	base := nullRow(l.width)
+	type nestedAt struct {
+		level  *jsonTableLevel
+		offset int
+	}
+	var nested []nestedAt
+
+	offset := 0
+	for i, col := range l.cols {
+		switch {
+		case col.Nested != nil:
+			nested = append(nested, nestedAt{level: l.nested[i], offset: offset})
+			offset += l.nested[i].width
+			continue
+		case col.Ordinality:
+			base[offset] = sqltypes.NewUint32(uint32(ordinality))
+		default:
+			v, err := e.columnValue(col, l.paths[i], value)
+			if err != nil {
+				return nil, err
+			}
+			base[offset] = v
+		}
+		offset++
+	}
+
+	var out []sqltypes.Row
+	for _, n := range nested {
+		for i, match := range n.level.match(value) {
+			rows, err := e.levelRows(n.level, match, i+1)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range rows {
+				row := sqltypes.CopyRow(base)
+				copy(row[n.offset:], r)
+				out = append(out, row)
+			}
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, base)
+	}
+	return out, nil
+}
+
+func (e *jsonTableEvaluator) columnValue(col *JSONTableColumn, path *json.Path, value *json.Value) (sqltypes.Value, error) {
+	var matches []*json.Value
+	path.Match(value, true, func(v *json.Value) {
+		matches = append(matches, v)
+	})
+
+	if col.Exists {
+		exists := int64(0)
+		if len(matches) > 0 {
+			exists = 1
+		}
+		return evalengine.CoerceTo(sqltypes.NewInt64(exists), col.Type, e.sqlmode)
+	}
+
+	switch len(matches) {
+	case 0:
+		return e.respond(col, col.OnEmpty, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValue, "Missing value for JSON_TABLE column '%s'", col.Name))
+	case 1:
+		v, err := jsonToSQLValue(matches[0], col, e.sqlmode)
+		if err != nil {
+			return e.respond(col, col.OnError, err)
+		}
+		return v, nil
+	default:
+		return e.respond(col, col.OnError, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValue, "More than one value was matched for JSON_TABLE column '%s'", col.Name))
+	}
+}
+
+// respond returns the value of a column for the ON EMPTY or ON ERROR clause.
+// err is returned for ERROR ON EMPTY and ERROR ON ERROR.
+func (e *jsonTableEvaluator) respond(col *JSONTableColumn, response *JSONTableResponse, err error) (sqltypes.Value, error) {
+	if response == nil {
+		return sqltypes.NULL, nil
+	}
+	switch response.Type {
+	case sqlparser.ErrorJSONType:
+		return sqltypes.NULL, err
+	case sqlparser.DefaultJSONType:
+		var p json.Parser
+		v, err := p.Parse(response.Default)
+		if err != nil {
+			return sqltypes.NULL, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid default value for JSON_TABLE column '%s': %v", col.Name, err)
+		}
+		return jsonToSQLValue(v, col, e.sqlmode)
+	default:
+		return sqltypes.NULL, nil
+	}
+}
+
+// jsonToSQLValue converts a JSON value to the type of the JSON_TABLE column
+func jsonToSQLValue(v *json.Value, col *JSONTableColumn, sqlmode evalengine.SQLMode) (sqltypes.Value, error) {
+	typ := col.Type.Type()
+	if typ == sqltypes.TypeJSON {
+		return sqltypes.MakeTrusted(sqltypes.TypeJSON, v.MarshalTo(nil)), nil
+	}
+
+	var val sqltypes.Value
+	switch v.Type() {
+	case json.TypeNull:
+		return sqltypes.NULL, nil
+	case json.TypeObject, json.TypeArray:
+		return sqltypes.NULL, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValue, "Can't store an array or an object in the scalar column '%s' of JSON_TABLE", col.Name)
+	case json.TypeString:
+		str, _ := v.StringBytes()
+		val = sqltypes.MakeTrusted(sqltypes.VarChar, str)
+	case json.TypeNumber:
+		switch v.NumberType() {
+		case json.NumberTypeSigned:
+			val = sqltypes.MakeTrusted(sqltypes.Int64, []byte(v.Raw()))
+		case json.NumberTypeUnsigned:
+			val = sqltypes.MakeTrusted(sqltypes.Uint64, []byte(v.Raw()))
+		case json.NumberTypeDecimal:
+			val = sqltypes.MakeTrusted(sqltypes.Decimal, []byte(v.Raw()))
+		default:
+			val = sqltypes.MakeTrusted(sqltypes.Float64, []byte(v.Raw()))
+		}
+	case json.TypeBoolean:
+		b, _ := v.Bool()
+		switch {
+		case sqltypes.IsText(typ):
+			val = sqltypes.NewVarChar(fmt.Sprintf("%t", b))
+		case b:
+			val = sqltypes.NewInt64(1)
+		default:
+			val = sqltypes.NewInt64(0)
+		}
+	default:
+		val = sqltypes.MakeTrusted(sqltypes.VarChar, []byte(v.Raw()))
+	}
+	return evalengine.CoerceTo(val, col.Type, sqlmode)
+}
+
+func nullRow(width int) sqltypes.Row {
+	row := make(sqltypes.Row, width)
+	for i := range row {
+		row[i] = sqltypes.NULL
+	}
+	return row
+}
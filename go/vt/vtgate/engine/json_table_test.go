@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestJSONTable(t *testing.T) {
+	utf8mb4 := collations.MySQL8().DefaultConnectionCharset()
+	intType := evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)
+	textType := evalengine.NewType(sqltypes.VarChar, utf8mb4)
+	ordType := evalengine.NewType(sqltypes.Uint32, collations.CollationBinaryID)
+
+	tcases := []struct {
+		name    string
+		path    string
+		columns []*JSONTableColumn
+		outer   bool
+		docs    []string
+		expRows string
+		expErr  string
+	}{{
+		name: "ordinality and path columns",
+		path: "$[*]",
+		columns: []*JSONTableColumn{
+			{Name: "id", Type: ordType, Ordinality: true},
+			{Name: "a", Type: intType, Path: "$.a"},
+			{Name: "b", Type: textType, Path: "$.b"},
+		},
+		docs:    []string{`[{"a": 1, "b": "x"}, {"a": 2}]`},
+		expRows: `[[UINT32(1) INT64(1) VARCHAR("x")] [UINT32(2) INT64(2) NULL]]`,
+	}, {
+		name: "exists path and booleans",
+		path: "$[*]",
+		columns: []*JSONTableColumn{
+			{Name: "has_a", Type: intType, Exists: true, Path: "$.a"},
+			{Name: "a", Type: textType, Path: "$.a"},
+		},
+		docs:    []string{`[{"a": true}, {}]`},
+		expRows: `[[INT64(1) VARCHAR("true")] [INT64(0) NULL]]`,
+	}, {
+		name: "sibling nested paths produce rows one after the other",
+		path: "$",
+		columns: []*JSONTableColumn{
+			{Name: "name", Type: textType, Path: "$.name"},
+			{Path: "$.x[*]", Nested: []*JSONTableColumn{
+				{Name: "x", Type: intType, Path: "$"},
+			}},
+			{Path: "$.y[*]", Nested: []*JSONTableColumn{
+				{Name: "yid", Type: ordType, Ordinality: true},
+				{Name: "y", Type: intType, Path: "$"},
+			}},
+		},
+		docs:    []string{`{"name": "n", "x": [1, 2], "y": [3]}`, `{"name": "m"}`},
+		expRows: `[[VARCHAR("n") INT64(1) NULL NULL] [VARCHAR("n") INT64(2) NULL NULL] [VARCHAR("n") NULL UINT32(1) INT64(3)] [VARCHAR("m") NULL NULL NULL]]`,
+	}, {
+		name: "default on empty and on error",
+		path: "$[*]",
+		columns: []*JSONTableColumn{
+			{Name: "a", Type: intType, Path: "$.a",
+				OnEmpty: &JSONTableResponse{Type: sqlparser.DefaultJSONType, Default: "42"},
+				OnError: &JSONTableResponse{Type: sqlparser.DefaultJSONType, Default: "-1"}},
+		},
+		docs:    []string{`[{}, {"a": [1]}, {"a": 7}]`},
+		expRows: `[[INT64(42)] [INT64(-1)] [INT64(7)]]`,
+	}, {
+		name: "error on empty",
+		path: "$[*]",
+		columns: []*JSONTableColumn{
+			{Name: "a", Type: intType, Path: "$.a", OnEmpty: &JSONTableResponse{Type: sqlparser.ErrorJSONType}},
+		},
+		docs:   []string{`[{}]`},
+		expErr: "Missing value for JSON_TABLE column 'a'",
+	}, {
+		name: "json columns keep objects",
+		path: "$[*]",
+		columns: []*JSONTableColumn{
+			{Name: "j", Type: evalengine.NewType(sqltypes.TypeJSON, collations.CollationBinaryID), Path: "$"},
+		},
+		docs:    []string{`[{"a": 1}, [2]]`},
+		expRows: `[[JSON("{\"a\": 1}")] [JSON("[2]")]]`,
+	}, {
+		name: "no rows",
+		path: "$[*]",
+		columns: []*JSONTableColumn{
+			{Name: "a", Type: intType, Path: "$"},
+		},
+		docs:    []string{`[]`, "NULL"},
+		expRows: `[]`,
+	}, {
+		name: "outer returns rows without matches",
+		path: "$[*]",
+		columns: []*JSONTableColumn{
+			{Name: "a", Type: intType, Path: "$"},
+		},
+		outer:   true,
+		docs:    []string{`[]`, "NULL", `[5]`},
+		expRows: `[[NULL] [NULL] [INT64(5)]]`,
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := sqltypes.MakeTestFields("doc|id", "varchar|int64")
+			var rows []string
+			for _, doc := range tc.docs {
+				rows = append(rows, doc+"|10")
+			}
+			input := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, rows...)}}
+
+			jt := &JSONTable{
+				Input:       input,
+				JSONExpr:    evalengine.NewColumn(0, textType, nil),
+				ASTJSONExpr: sqlparser.NewColName("doc"),
+				Path:        tc.path,
+				Columns:     tc.columns,
+				Outer:       tc.outer,
+			}
+			qr, err := jt.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expRows, fmt.Sprintf("%v", withoutInput(qr.Rows)))
+			for _, row := range qr.Rows {
+				require.Len(t, row, len(qr.Fields))
+				require.Equal(t, "INT64(10)", row[len(row)-1].String())
+			}
+
+			input.rewind()
+			var streamed []sqltypes.Row
+			err = jt.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+				streamed = append(streamed, qr.Rows...)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expRows, fmt.Sprintf("%v", withoutInput(streamed)))
+		})
+	}
+}
+
+// withoutInput removes the two input columns from the rows produced by JSONTable
+func withoutInput(rows []sqltypes.Row) []sqltypes.Row {
+	out := make([]sqltypes.Row, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row[:len(row)-2])
+	}
+	return out
+}
+
+func TestJSONTableFields(t *testing.T) {
+	jt := &JSONTable{
+		Input: &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("doc", "varchar"))}},
+		Columns: []*JSONTableColumn{
+			{Name: "id", Type: evalengine.NewType(sqltypes.Uint32, collations.CollationBinaryID), Ordinality: true},
+			{Path: "$.x[*]", Nested: []*JSONTableColumn{
+				{Name: "x", Type: evalengine.NewType(sqltypes.Int32, collations.CollationBinaryID), Path: "$"},
+			}},
+		},
+	}
+	qr, err := jt.GetFields(context.Background(), &noopVCursor{}, nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Fields, 3)
+	require.Equal(t, "id", qr.Fields[0].Name)
+	require.Equal(t, sqltypes.Uint32, qr.Fields[0].Type)
+	require.Equal(t, "x", qr.Fields[1].Name)
+	require.Equal(t, sqltypes.Int32, qr.Fields[1].Type)
+	require.Equal(t, "doc", qr.Fields[2].Name)
+}
@@ -61,6 +61,10 @@ func (ms *MemorySort) GetTableName() string {
 
 // TryExecute satisfies the Primitive interface.
 func (ms *MemorySort) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		return executeStreamed(ctx, vcursor, ms, bindVars, wantfields)
+	}
+
 	count, err := ms.fetchCount(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -93,10 +97,9 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 		return callback(qr.Truncate(ms.TruncateColumnCount))
 	}
 
-	sorter := &evalengine.Sorter{
-		Compare: ms.OrderBy,
-		Limit:   count,
-	}
+	// the sorter writes sorted runs of rows to disk when they do not fit in memory
+	sorter := newExternalSorter(vcursor, ms.OrderBy, count)
+	defer sorter.close()
 
 	var mu sync.Mutex
 	err = vcursor.StreamExecutePrimitive(ctx, ms.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
@@ -107,18 +110,14 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 				return err
 			}
 		}
-		for _, row := range qr.Rows {
-			sorter.Push(row)
-		}
-		if vcursor.ExceedsMaxMemoryRows(sorter.Len()) {
-			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
-		}
-		return nil
+		return sorter.push(qr.Rows)
 	})
 	if err != nil {
 		return err
 	}
-	return cb(&sqltypes.Result{Rows: sorter.Sorted()})
+	return sorter.send(func(rows []sqltypes.Row) error {
+		return cb(&sqltypes.Result{Rows: rows})
+	})
 }
 
 // GetFields satisfies the Primitive interface.
@@ -18,6 +18,7 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -703,3 +704,95 @@ func TestMemorySortStreamAsync(t *testing.T) {
 [VARBINARY("c") DECIMAL(4)] [VARBINARY("c") DECIMAL(4)] [VARBINARY("c") DECIMAL(4)]]`,
 		qr.Rows))
 }
+
+func TestMemorySortSpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	testMaxMemoryRows = 7
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillBudget = nil
+	}()
+
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3",
+		"int64|varchar|decimal",
+	)
+	var rows []string
+	for i := 0; i < 50; i++ {
+		id := i * 37 % 50
+		if id%4 == 0 {
+			rows = append(rows, fmt.Sprintf("%d|null|%d.5", id, id))
+		} else {
+			rows = append(rows, fmt.Sprintf("%d|name %d|%d.5", id, id%3, id))
+		}
+	}
+	input := func() Primitive {
+		return &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, rows...)}}
+	}
+
+	tcases := []struct {
+		name    string
+		limit   int64
+		rows    int
+		spilled bool
+	}{
+		{name: "no limit", rows: 50, spilled: true},
+		{name: "limit over max memory rows", limit: 23, rows: 23, spilled: true},
+		{name: "limit under max memory rows", limit: 4, rows: 4},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &MemorySort{
+				OrderBy: []evalengine.OrderByParams{{
+					Col:             0,
+					WeightStringCol: -1,
+					Desc:            true,
+					Type:            evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+				}},
+				Input: input(),
+			}
+			bv := map[string]*querypb.BindVariable{}
+			if tc.limit > 0 {
+				ms.UpperLimit = evalengine.NewBindVar("__upper_limit", evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID))
+				bv["__upper_limit"] = sqltypes.Int64BindVariable(tc.limit)
+			}
+
+			testSpillBudget = nil
+			want, err := ms.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+			require.NoError(t, err)
+			require.Len(t, want.Rows, tc.rows)
+
+			testSpillBudget = NewDiskBudget(t.TempDir(), 0, nil)
+			ms.Input = input()
+			before := spilledBytes.Get()
+			got, err := wrapStreamExecute(ms, &noopVCursor{}, bv, true)
+			require.NoError(t, err)
+			utils.MustMatch(t, want.Rows, got.Rows)
+			require.Equal(t, tc.spilled, spilledBytes.Get() > before)
+			require.Zero(t, testSpillBudget.Used())
+
+			// the non-streaming execution spills the same way
+			ms.Input = input()
+			before = spilledBytes.Get()
+			got, err = ms.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+			require.NoError(t, err)
+			utils.MustMatch(t, want.Rows, got.Rows)
+			require.Equal(t, tc.spilled, spilledBytes.Get() > before)
+			require.Zero(t, testSpillBudget.Used())
+		})
+	}
+
+	t.Run("disk budget exceeded", func(t *testing.T) {
+		testSpillBudget = NewDiskBudget(t.TempDir(), 100, nil)
+		ms := &MemorySort{
+			OrderBy: []evalengine.OrderByParams{{
+				Col:             0,
+				WeightStringCol: -1,
+			}},
+			Input: input(),
+		}
+		_, err := wrapStreamExecute(ms, &noopVCursor{}, nil, true)
+		require.EqualError(t, err, "temporary disk usage exceeded allowed limit of 100 bytes")
+		require.Zero(t, testSpillBudget.Used())
+	})
+}
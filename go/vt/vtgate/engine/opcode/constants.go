@@ -36,6 +36,7 @@ const (
 	PulloutNotIn
 	PulloutExists
 	PulloutNotExists
+	PulloutMinMax
 )
 
 var pulloutName = map[PulloutOpcode]string{
@@ -44,6 +45,7 @@ var pulloutName = map[PulloutOpcode]string{
 	PulloutNotIn:     "PulloutNotIn",
 	PulloutExists:    "PulloutExists",
 	PulloutNotExists: "PulloutNotExists",
+	PulloutMinMax:    "PulloutMinMax",
 }
 
 func (code PulloutOpcode) String() string {
@@ -77,7 +79,10 @@ const (
 	AggregateCountStar
 	AggregateGroupConcat
 	AggregateAvg
-	AggregateUDF  // This is an opcode used to represent UDFs
+	AggregateUDF      // This is an opcode used to represent UDFs
+	AggregateGrouping // GROUPING() of a WITH ROLLUP query, computed by the rollup itself
+	AggregateJSONArrayAgg
+	AggregateJSONObjectAgg
 	_NumOfOpCodes // This line must be last of the opcodes!
 )
 
@@ -97,6 +102,9 @@ var SupportedAggregates = map[string]AggregateOpcode{
 	"count_star":     AggregateCountStar,
 	"any_value":      AggregateAnyValue,
 	"group_concat":   AggregateGroupConcat,
+	"grouping":       AggregateGrouping,
+	"json_arrayagg":  AggregateJSONArrayAgg,
+	"json_objectagg": AggregateJSONObjectAgg,
 }
 
 var AggregateName = map[AggregateOpcode]string{
@@ -111,6 +119,9 @@ var AggregateName = map[AggregateOpcode]string{
 	AggregateGroupConcat:   "group_concat",
 	AggregateAnyValue:      "any_value",
 	AggregateAvg:           "avg",
+	AggregateGrouping:      "grouping",
+	AggregateJSONArrayAgg:  "json_arrayagg",
+	AggregateJSONObjectAgg: "json_objectagg",
 }
 
 func (code AggregateOpcode) String() string {
@@ -150,10 +161,12 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 			return sqltypes.Decimal
 		}
 		return sqltypes.Float64
-	case AggregateCount, AggregateCountStar, AggregateCountDistinct:
+	case AggregateCount, AggregateCountStar, AggregateCountDistinct, AggregateGrouping:
 		return sqltypes.Int64
 	case AggregateGtid:
 		return sqltypes.VarChar
+	case AggregateJSONArrayAgg, AggregateJSONObjectAgg:
+		return sqltypes.TypeJSON
 	case AggregateUDF:
 		return sqltypes.Unknown
 	default:
@@ -163,7 +176,7 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 
 func (code AggregateOpcode) Nullable() bool {
 	switch code {
-	case AggregateCount, AggregateCountStar:
+	case AggregateCount, AggregateCountStar, AggregateGrouping:
 		return false
 	default:
 		return true
@@ -200,3 +213,93 @@ func (code AggregateOpcode) IsDistinct() bool {
 		return false
 	}
 }
+
+// WindowOpcode is the opcode for a window function evaluated by the Window primitive.
+type WindowOpcode int
+
+// These constants list the possible window function opcodes.
+const (
+	WindowUnassigned = WindowOpcode(iota)
+	WindowRowNumber
+	WindowRank
+	WindowDenseRank
+	WindowPercentRank
+	WindowCumeDist
+	WindowNtile
+	WindowLag
+	WindowLead
+	WindowFirstValue
+	WindowLastValue
+	WindowNthValue
+	WindowAggregate // an aggregate function used with an OVER clause
+)
+
+// SupportedWindowFunctions maps the list of window functions
+// that can be evaluated at the vtgate level to their opcodes.
+var SupportedWindowFunctions = map[string]WindowOpcode{
+	"row_number":   WindowRowNumber,
+	"rank":         WindowRank,
+	"dense_rank":   WindowDenseRank,
+	"percent_rank": WindowPercentRank,
+	"cume_dist":    WindowCumeDist,
+	"ntile":        WindowNtile,
+	"lag":          WindowLag,
+	"lead":         WindowLead,
+	"first_value":  WindowFirstValue,
+	"last_value":   WindowLastValue,
+	"nth_value":    WindowNthValue,
+}
+
+var WindowName = map[WindowOpcode]string{
+	WindowRowNumber:   "row_number",
+	WindowRank:        "rank",
+	WindowDenseRank:   "dense_rank",
+	WindowPercentRank: "percent_rank",
+	WindowCumeDist:    "cume_dist",
+	WindowNtile:       "ntile",
+	WindowLag:         "lag",
+	WindowLead:        "lead",
+	WindowFirstValue:  "first_value",
+	WindowLastValue:   "last_value",
+	WindowNthValue:    "nth_value",
+	WindowAggregate:   "aggregate",
+}
+
+func (code WindowOpcode) String() string {
+	name := WindowName[code]
+	if name == "" {
+		name = "ERROR"
+	}
+	return name
+}
+
+// MarshalJSON serializes the WindowOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code WindowOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// SQLType returns the type produced by the window function, given the type of its argument.
+func (code WindowOpcode) SQLType(typ querypb.Type) querypb.Type {
+	switch code {
+	case WindowRowNumber, WindowRank, WindowDenseRank, WindowNtile:
+		return sqltypes.Uint64
+	case WindowPercentRank, WindowCumeDist:
+		return sqltypes.Float64
+	case WindowLag, WindowLead, WindowFirstValue, WindowLastValue, WindowNthValue:
+		return typ
+	default:
+		return sqltypes.Unknown
+	}
+}
+
+// UsesFrame returns true if the result of the function depends on the window frame.
+// Ranking functions always operate on the whole partition.
+func (code WindowOpcode) UsesFrame() bool {
+	switch code {
+	case WindowFirstValue, WindowLastValue, WindowNthValue, WindowAggregate:
+		return true
+	default:
+		return false
+	}
+}
@@ -52,6 +52,9 @@ func TestType(t *testing.T) {
 		{AggregateCount, sqltypes.Int32, sqltypes.Int64},
 		{AggregateCountStar, sqltypes.Int64, sqltypes.Int64},
 		{AggregateGtid, sqltypes.VarChar, sqltypes.VarChar},
+		{AggregateGrouping, sqltypes.Int64, sqltypes.Int64},
+		{AggregateJSONArrayAgg, sqltypes.Int64, sqltypes.TypeJSON},
+		{AggregateJSONObjectAgg, sqltypes.VarChar, sqltypes.TypeJSON},
 	}
 
 	for _, tc := range tt {
@@ -83,6 +86,7 @@ func TestNeedsListArg(t *testing.T) {
 		{PulloutNotIn, true},
 		{PulloutExists, false},
 		{PulloutNotExists, false},
+		{PulloutMinMax, false},
 	}
 
 	for _, tc := range tt {
@@ -103,6 +107,7 @@ func TestPulloutOpcode_MarshalJSON(t *testing.T) {
 		{PulloutNotIn, "\"PulloutNotIn\""},
 		{PulloutExists, "\"PulloutExists\""},
 		{PulloutNotExists, "\"PulloutNotExists\""},
+		{PulloutMinMax, "\"PulloutMinMax\""},
 	}
 
 	for _, tc := range tt {
@@ -25,6 +25,7 @@ import (
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	. "github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 )
 
@@ -49,6 +50,11 @@ type OrderedAggregate struct {
 	// from the result received. If 0, no truncation happens.
 	TruncateColumnCount int `json:",omitempty"`
 
+	// WithRollup adds the super-aggregate rows of GROUP BY ... WITH ROLLUP
+	// to the output. The input has to be sorted by the GroupByKeys in the
+	// order they were specified in the GROUP BY clause.
+	WithRollup bool `json:",omitempty"`
+
 	// Input is the primitive that will feed into this Primitive.
 	Input Primitive
 }
@@ -96,6 +102,11 @@ func (oa *OrderedAggregate) GetTableName() string {
 
 // TryExecute is a Primitive function.
 func (oa *OrderedAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		// aggregate the rows as they are streamed, so that the input does not have to fit in memory
+		return executeStreamed(ctx, vcursor, oa, bindVars, true)
+	}
+
 	qr, err := oa.execute(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -142,11 +153,14 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	if err != nil {
 		return nil, err
 	}
+	if oa.WithRollup {
+		return oa.executeRollup(result, vcursor.GroupConcatMaxLen())
+	}
 	if len(oa.Aggregates) == 0 {
 		return oa.executeGroupBy(result)
 	}
 
-	agg, fields, err := newAggregation(result.Fields, oa.Aggregates)
+	agg, fields, err := newAggregation(result.Fields, oa.Aggregates, vcursor.GroupConcatMaxLen())
 	if err != nil {
 		return nil, err
 	}
@@ -182,6 +196,39 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	return out, nil
 }
 
+func (oa *OrderedAggregate) executeRollup(result *sqltypes.Result, groupConcatMaxLen int64) (*sqltypes.Result, error) {
+	levels, fields, err := oa.newRollup(result.Fields, groupConcatMaxLen)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &sqltypes.Result{
+		Fields: fields,
+		Rows:   make([][]sqltypes.Value, 0, len(result.Rows)),
+	}
+
+	var currentKey []sqltypes.Value
+	for _, row := range result.Rows {
+		var changed int
+
+		currentKey, changed, err = oa.nextRollupGroup(currentKey, row)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Rows = append(out.Rows, oa.finishRollup(levels, changed)...)
+		if err := levels.add(row); err != nil {
+			return nil, err
+		}
+	}
+
+	if currentKey != nil {
+		out.Rows = append(out.Rows, oa.finishRollup(levels, -1)...)
+	}
+
+	return out, nil
+}
+
 func (oa *OrderedAggregate) executeStreamGroupBy(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, callback func(*sqltypes.Result) error) error {
 	cb := func(qr *sqltypes.Result) error {
 		return callback(qr.Truncate(oa.TruncateColumnCount))
@@ -235,6 +282,9 @@ func (oa *OrderedAggregate) executeStreamGroupBy(ctx context.Context, vcursor VC
 
 // TryStreamExecute is a Primitive function.
 func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	if oa.WithRollup {
+		return oa.executeStreamRollup(ctx, vcursor, bindVars, callback)
+	}
 	if len(oa.Aggregates) == 0 {
 		return oa.executeStreamGroupBy(ctx, vcursor, bindVars, callback)
 	}
@@ -251,7 +301,7 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 		var err error
 
 		if agg == nil && len(qr.Fields) != 0 {
-			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates)
+			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates, vcursor.GroupConcatMaxLen())
 			if err != nil {
 				return err
 			}
@@ -299,6 +349,124 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 	return nil
 }
 
+func (oa *OrderedAggregate) executeStreamRollup(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, callback func(*sqltypes.Result) error) error {
+	cb := func(qr *sqltypes.Result) error {
+		return callback(qr.Truncate(oa.TruncateColumnCount))
+	}
+
+	var levels rollupLevels
+	var fields []*querypb.Field
+	var currentKey []sqltypes.Value
+
+	visitor := func(qr *sqltypes.Result) error {
+		var err error
+
+		if levels == nil && len(qr.Fields) != 0 {
+			levels, fields, err = oa.newRollup(qr.Fields, vcursor.GroupConcatMaxLen())
+			if err != nil {
+				return err
+			}
+			if err = cb(&sqltypes.Result{Fields: fields}); err != nil {
+				return err
+			}
+		}
+
+		// This code is similar to the one in executeRollup.
+		for _, row := range qr.Rows {
+			var changed int
+
+			currentKey, changed, err = oa.nextRollupGroup(currentKey, row)
+			if err != nil {
+				return err
+			}
+
+			if rows := oa.finishRollup(levels, changed); len(rows) > 0 {
+				if err := cb(&sqltypes.Result{Rows: rows}); err != nil {
+					return err
+				}
+			}
+
+			if err := levels.add(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	/* we need the input fields types to correctly calculate the output types */
+	err := vcursor.StreamExecutePrimitive(ctx, oa.Input, bindVars, true, visitor)
+	if err != nil {
+		return err
+	}
+
+	if currentKey != nil {
+		if err := cb(&sqltypes.Result{Rows: oa.finishRollup(levels, -1)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollupLevels holds one aggregation state per level of a WITH ROLLUP query.
+// The state at index i aggregates the rows grouped by the first i grouping
+// keys, so the last one holds the regular groups and the first one the grand total.
+type rollupLevels []aggregationState
+
+func (oa *OrderedAggregate) newRollup(fields []*querypb.Field, groupConcatMaxLen int64) (rollupLevels, []*querypb.Field, error) {
+	levels := make(rollupLevels, len(oa.GroupByKeys)+1)
+	var outFields []*querypb.Field
+	for i := range levels {
+		agg, aggFields, err := newAggregation(fields, oa.Aggregates, groupConcatMaxLen)
+		if err != nil {
+			return nil, nil, err
+		}
+		levels[i], outFields = agg, aggFields
+	}
+	return levels, outFields, nil
+}
+
+func (r rollupLevels) add(row []sqltypes.Value) error {
+	for _, agg := range r {
+		if err := agg.add(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishRollup yields and resets all the levels above the given one, starting with the
+// regular group. Since the super-aggregate rows are produced at vtgate, the grouping
+// columns they are not grouped by are set to NULL, and GROUPING() is computed here.
+func (oa *OrderedAggregate) finishRollup(levels rollupLevels, above int) [][]sqltypes.Value {
+	var rows [][]sqltypes.Value
+	for level := len(levels) - 1; level > above; level-- {
+		row := levels[level].finish()
+		levels[level].reset()
+
+		for _, gb := range oa.GroupByKeys[level:] {
+			row[gb.KeyCol] = sqltypes.NULL
+			if gb.WeightStringCol >= 0 {
+				row[gb.WeightStringCol] = sqltypes.NULL
+			}
+		}
+		for _, aggr := range oa.Aggregates {
+			if aggr.Opcode != AggregateGrouping {
+				continue
+			}
+			var bits int64
+			for _, key := range aggr.GroupingKeys {
+				bits <<= 1
+				if key >= level {
+					bits |= 1
+				}
+			}
+			row[aggr.Col] = sqltypes.NewInt64(bits)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
 // GetFields is a Primitive function.
 func (oa *OrderedAggregate) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	qr, err := oa.Input.GetFields(ctx, vcursor, bindVars)
@@ -306,7 +474,7 @@ func (oa *OrderedAggregate) GetFields(ctx context.Context, vcursor VCursor, bind
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, oa.Aggregates)
+	_, fields, err := newAggregation(qr.Fields, oa.Aggregates, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -330,30 +498,58 @@ func (oa *OrderedAggregate) nextGroupBy(currentKey, nextRow []sqltypes.Value) (n
 		return nextRow, false, nil
 	}
 
-	for _, gb := range oa.GroupByKeys {
+	changed, err := oa.firstChangedKey(currentKey, nextRow)
+	if err != nil {
+		return nil, false, err
+	}
+	if changed < len(oa.GroupByKeys) {
+		return nextRow, true, nil
+	}
+	return currentKey, false, nil
+}
+
+// nextRollupGroup is like nextGroupBy, but returns the index of the first grouping key
+// that changed. If the row belongs to the current group, len(oa.GroupByKeys) is returned.
+func (oa *OrderedAggregate) nextRollupGroup(currentKey, nextRow []sqltypes.Value) (nextKey []sqltypes.Value, changed int, err error) {
+	if currentKey == nil {
+		return nextRow, len(oa.GroupByKeys), nil
+	}
+
+	changed, err = oa.firstChangedKey(currentKey, nextRow)
+	if err != nil {
+		return nil, 0, err
+	}
+	if changed < len(oa.GroupByKeys) {
+		return nextRow, changed, nil
+	}
+	return currentKey, changed, nil
+}
+
+func (oa *OrderedAggregate) firstChangedKey(currentKey, nextRow []sqltypes.Value) (int, error) {
+	for i, gb := range oa.GroupByKeys {
 		v1 := currentKey[gb.KeyCol]
 		v2 := nextRow[gb.KeyCol]
 		if v1.TinyWeightCmp(v2) != 0 {
-			return nextRow, true, nil
+			return i, nil
 		}
 
 		cmp, err := evalengine.NullsafeCompare(v1, v2, gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 		if err != nil {
 			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
 			if !isCollationErr || gb.WeightStringCol == -1 {
-				return nil, false, err
+				return 0, err
 			}
-			gb.KeyCol = gb.WeightStringCol
+			// compare the weight strings instead, but keep KeyCol as is: WITH ROLLUP has to set it to NULL
 			cmp, err = evalengine.NullsafeCompare(currentKey[gb.WeightStringCol], nextRow[gb.WeightStringCol], gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 			if err != nil {
-				return nil, false, err
+				return 0, err
 			}
 		}
 		if cmp != 0 {
-			return nextRow, true, nil
+			return i, nil
 		}
 	}
-	return currentKey, false, nil
+	return len(oa.GroupByKeys), nil
 }
 func aggregateParamsToString(in any) string {
 	return in.(*AggregateParams).String()
@@ -373,6 +569,9 @@ func (oa *OrderedAggregate) description() PrimitiveDescription {
 	if oa.TruncateColumnCount > 0 {
 		other["ResultColumns"] = oa.TruncateColumnCount
 	}
+	if oa.WithRollup {
+		other["WithRollup"] = true
+	}
 	return PrimitiveDescription{
 		OperatorType: "Aggregate",
 		Variant:      "Ordered",
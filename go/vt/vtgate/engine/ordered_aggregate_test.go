@@ -1164,3 +1164,401 @@ func TestGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+// groupConcatMaxLenVCursor is a vcursor with a custom group_concat_max_len
+type groupConcatMaxLenVCursor struct {
+	noopVCursor
+	maxLen int64
+}
+
+func (vc *groupConcatMaxLenVCursor) GroupConcatMaxLen() int64 {
+	return vc.maxLen
+}
+
+// TestGroupConcatArguments tests group_concat that is computed from the arguments of every row.
+func TestGroupConcatArguments(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3|c4",
+		"int64|varchar|varchar|int64",
+	)
+	input := sqltypes.MakeTestResult(fields,
+		"10|a|x|3", "10|b|y|1", "10|c|null|2", "10|a|x|4",
+		"20|d|z|1",
+		"30|null|x|1",
+	)
+	separator := " - "
+
+	tcases := []struct {
+		name     string
+		aggr     *AggregateParams
+		maxLen   int64
+		expected []string
+	}{{
+		name:     "several arguments",
+		aggr:     &AggregateParams{Opcode: AggregateGroupConcat, Col: 1, ArgCols: []int{2}},
+		expected: []string{"10|ax,by,ax", "20|dz", "30|null"},
+	}, {
+		name: "order by and separator",
+		aggr: &AggregateParams{
+			Opcode:    AggregateGroupConcat,
+			Col:       1,
+			ArgCols:   []int{2},
+			OrderBy:   evalengine.Comparison{{Col: 3, WeightStringCol: -1, Desc: true}},
+			Separator: &separator,
+		},
+		expected: []string{"10|ax - ax - by", "20|dz", "30|null"},
+	}, {
+		name: "distinct",
+		aggr: &AggregateParams{
+			Opcode:  AggregateGroupConcat,
+			Col:     1,
+			ArgCols: []int{2},
+			OrderBy: evalengine.Comparison{{Col: 1, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID)}},
+			DistinctCols: []CheckCol{
+				{Col: 1, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID)},
+				{Col: 2, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID)},
+			},
+		},
+		expected: []string{"10|ax,by", "20|dz", "30|null"},
+	}, {
+		name:     "truncated to group_concat_max_len",
+		aggr:     &AggregateParams{Opcode: AggregateGroupConcat, Col: 1, ArgCols: []int{2}},
+		maxLen:   4,
+		expected: []string{"10|ax,b", "20|dz", "30|null"},
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			tcase.aggr.Alias = "group_concat"
+			tcase.aggr.CollationEnv = collations.MySQL8()
+			for i := range tcase.aggr.DistinctCols {
+				tcase.aggr.DistinctCols[i].CollationEnv = collations.MySQL8()
+			}
+			for i := range tcase.aggr.OrderBy {
+				tcase.aggr.OrderBy[i].CollationEnv = collations.MySQL8()
+			}
+			vc := &groupConcatMaxLenVCursor{maxLen: tcase.maxLen}
+
+			oa := &OrderedAggregate{
+				Aggregates:          []*AggregateParams{tcase.aggr},
+				GroupByKeys:         []*GroupByParams{{KeyCol: 0}},
+				TruncateColumnCount: 2,
+				Input:               &fakePrimitive{results: []*sqltypes.Result{input}},
+			}
+			qr, err := oa.TryExecute(context.Background(), vc, nil, false)
+			require.NoError(t, err)
+			expected := sqltypes.MakeTestResult(sqltypes.MakeTestFields("c1|group_concat", "int64|text"), tcase.expected...)
+			utils.MustMatch(t, expected.Rows, qr.Rows)
+		})
+	}
+}
+
+// TestGroupConcatUnknownMaxLen tests that group_concat is not truncated when group_concat_max_len is not known.
+func TestGroupConcatUnknownMaxLen(t *testing.T) {
+	fields := sqltypes.MakeTestFields("c1|c2", "int64|varchar")
+	var rows []string
+	for i := 0; i < 500; i++ {
+		rows = append(rows, "1|abc")
+	}
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{NewAggregateParam(AggregateGroupConcat, 1, "group_concat", collations.MySQL8())},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, rows...)}},
+	}
+	qr, err := oa.TryExecute(context.Background(), &groupConcatMaxLenVCursor{}, nil, false)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows[0][1].Raw(), 500*4-1)
+}
+
+// TestJSONAggregations tests json_arrayagg and json_objectagg, both computed from the values of every row
+// and by merging the partial aggregations of the shards.
+func TestJSONAggregations(t *testing.T) {
+	tcases := []struct {
+		name     string
+		aggr     *AggregateParams
+		fields   string
+		input    []string
+		expected []string
+	}{{
+		name:     "json_arrayagg",
+		aggr:     &AggregateParams{Opcode: AggregateJSONArrayAgg, Col: 1},
+		fields:   "int64|varchar",
+		input:    []string{"10|a", "10|null", "10|b", "20|c"},
+		expected: []string{`10|["a", null, "b"]`, `20|["c"]`},
+	}, {
+		name:     "json_arrayagg of partial aggregations",
+		aggr:     &AggregateParams{Opcode: AggregateJSONArrayAgg, OrigOpcode: AggregateJSONArrayAgg, Col: 1},
+		fields:   "int64|json",
+		input:    []string{`10|[1, 2]`, `10|null`, `10|[{"a": 1}]`, `20|["c"]`},
+		expected: []string{`10|[1, 2, {"a": 1}]`, `20|["c"]`},
+	}, {
+		name:     "json_objectagg",
+		aggr:     &AggregateParams{Opcode: AggregateJSONObjectAgg, Col: 1, ArgCols: []int{2}},
+		fields:   "int64|varchar|int64",
+		input:    []string{"10|b|1", "10|a|null", "10|b|3", "20|c|4"},
+		expected: []string{`10|{"a": null, "b": 3}`, `20|{"c": 4}`},
+	}, {
+		name:     "json_objectagg of partial aggregations",
+		aggr:     &AggregateParams{Opcode: AggregateJSONObjectAgg, OrigOpcode: AggregateJSONObjectAgg, Col: 1},
+		fields:   "int64|json",
+		input:    []string{`10|{"b": 1}`, `10|{"a": [1], "b": 2}`, `20|null`},
+		expected: []string{`10|{"a": [1], "b": 2}`, `20|null`},
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			names := "c1|c2"
+			if tcase.aggr.ArgCols != nil {
+				names += "|c3"
+			}
+			fields := sqltypes.MakeTestFields(names, tcase.fields)
+			oa := &OrderedAggregate{
+				Aggregates:          []*AggregateParams{tcase.aggr},
+				GroupByKeys:         []*GroupByParams{{KeyCol: 0}},
+				TruncateColumnCount: 2,
+				Input:               &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, tcase.input...)}},
+			}
+			qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+			require.NoError(t, err)
+			assert.Equal(t, sqltypes.TypeJSON, qr.Fields[1].Type)
+			expected := sqltypes.MakeTestResult(sqltypes.MakeTestFields("c1|c2", "int64|json"), tcase.expected...)
+			utils.MustMatch(t, expected.Rows, qr.Rows)
+		})
+	}
+
+	t.Run("null key", func(t *testing.T) {
+		fields := sqltypes.MakeTestFields("c1|c2|c3", "int64|varchar|int64")
+		oa := &ScalarAggregate{
+			Aggregates: []*AggregateParams{{Opcode: AggregateJSONObjectAgg, Col: 1, ArgCols: []int{2}}},
+			Input:      &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "10|null|1")}},
+		}
+		_, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+		require.EqualError(t, err, "JSON documents may not contain NULL member names.")
+	})
+}
+
+func TestOrderedAggregateRollup(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|b|sum(c)|grouping(a)|grouping(a, b)",
+		"varbinary|varbinary|decimal|int64|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"x|1|1|0|0",
+			"x|1|2|0|0",
+			"x|2|3|0|0",
+			"y|1|4|0|0",
+		)},
+	}
+
+	oa := &OrderedAggregate{
+		Aggregates: []*AggregateParams{
+			NewAggregateParam(AggregateSum, 2, "", collations.MySQL8()),
+			{Opcode: AggregateGrouping, Col: 3, GroupingKeys: []int{0}},
+			{Opcode: AggregateGrouping, Col: 4, GroupingKeys: []int{0, 1}},
+		},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}, {KeyCol: 1, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       fp,
+	}
+
+	result, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		fields,
+		"x|1|3|0|0",
+		"x|2|3|0|0",
+		"x|null|6|0|1",
+		"y|1|4|0|0",
+		"y|null|4|0|1",
+		"null|null|10|1|3",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
+
+func TestOrderedAggregateStreamRollup(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|b|sum(c)|grouping(b)",
+		"varbinary|varbinary|decimal|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"x|1|1|0",
+			"x|1|2|0",
+			"x|2|3|0",
+			"y|1|4|0",
+		)},
+	}
+
+	oa := &OrderedAggregate{
+		Aggregates: []*AggregateParams{
+			NewAggregateParam(AggregateSum, 2, "", collations.MySQL8()),
+			{Opcode: AggregateGrouping, Col: 3, GroupingKeys: []int{1}},
+		},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}, {KeyCol: 1, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       fp,
+	}
+
+	var results []*sqltypes.Result
+	err := oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		results = append(results, qr)
+		return nil
+	})
+	require.NoError(t, err)
+
+	wantResults := sqltypes.MakeTestStreamingResults(
+		fields,
+		"x|1|3|0",
+		"---",
+		"x|2|3|0",
+		"x|null|6|1",
+		"---",
+		"y|1|4|0",
+		"y|null|4|1",
+		"null|null|10|1",
+	)
+	utils.MustMatch(t, wantResults, results)
+}
+
+func TestOrderedAggregateRollupWithoutAggregates(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|weight_string(a)",
+		"varchar|varbinary",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"x|X",
+			"x|X",
+			"y|Y",
+		)},
+	}
+
+	oa := &OrderedAggregate{
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: 1}},
+		WithRollup:  true,
+		Input:       fp,
+	}
+
+	result, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		fields,
+		"x|X",
+		"y|Y",
+		"null|null",
+	)
+	utils.MustMatch(t, wantResult, result)
+
+	// no input rows means there is no grand total either
+	fp.rewind()
+	fp.results = []*sqltypes.Result{sqltypes.MakeTestResult(fields)}
+	result, err = oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields), result)
+}
+
+func TestOrderedAggregateRollupHashDistinct(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|count(distinct b)",
+		"varbinary|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"x|1",
+			"x|2",
+			"y|2",
+			"y|3",
+			"y|null",
+		)},
+	}
+
+	countB := NewAggregateParam(AggregateCountDistinct, 1, "", collations.MySQL8())
+	countB.DistinctCols = []CheckCol{{Col: 1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), CollationEnv: collations.MySQL8()}}
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{countB},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       fp,
+	}
+
+	result, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		fields,
+		"x|2",
+		"y|2",
+		"null|3",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
+
+func TestAggregateSpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	testMaxMemoryRows = 7
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillBudget = nil
+	}()
+
+	fields := sqltypes.MakeTestFields("col|val", "int64|int64")
+	var rows []string
+	for i := 0; i < 50; i++ {
+		rows = append(rows, fmt.Sprintf("%d|%d", i*37%6, i))
+	}
+	// the input is sorted in memory, the way the planner sorts the input of an ordered aggregation
+	input := func() Primitive {
+		return &MemorySort{
+			OrderBy: evalengine.Comparison{{Col: 0, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)}},
+			Input:   &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, rows...)}},
+		}
+	}
+
+	tcases := []struct {
+		name      string
+		primitive func() Primitive
+		want      *sqltypes.Result
+	}{{
+		name: "ordered",
+		primitive: func() Primitive {
+			return &OrderedAggregate{
+				Aggregates:  []*AggregateParams{NewAggregateParam(AggregateSum, 1, "", collations.MySQL8())},
+				GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}},
+				Input:       input(),
+			}
+		},
+		want: sqltypes.MakeTestResult(sqltypes.MakeTestFields("col|val", "int64|decimal"),
+			"0|216", "1|225", "2|184", "3|192", "4|200", "5|208"),
+	}, {
+		name: "scalar",
+		primitive: func() Primitive {
+			return &ScalarAggregate{
+				Aggregates: []*AggregateParams{NewAggregateParam(AggregateSum, 1, "", collations.MySQL8())},
+				Input:      input(),
+			}
+		},
+		want: sqltypes.MakeTestResult(sqltypes.MakeTestFields("col|val", "int64|decimal"), "0|1225"),
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			testSpillBudget = nil
+			_, err := wrapStreamExecute(tc.primitive(), &noopVCursor{}, nil, true)
+			require.EqualError(t, err, "in-memory row count exceeded allowed limit of 7")
+
+			testSpillBudget = NewDiskBudget(t.TempDir(), 0, nil)
+			before := spilledBytes.Get()
+			result, err := tc.primitive().TryExecute(context.Background(), &noopVCursor{}, nil, true)
+			require.NoError(t, err)
+			utils.MustMatch(t, tc.want.Rows, result.Rows)
+			require.Greater(t, spilledBytes.Get(), before)
+			require.Zero(t, testSpillBudget.Used())
+		})
+	}
+}
@@ -38,6 +38,7 @@ type Plan struct {
 	BindVarNeeds *sqlparser.BindVarNeeds // Stores BindVars needed to be provided as part of expression rewriting
 	Warnings     []*query.QueryWarning   // Warnings that need to be yielded every time this query runs
 	TablesUsed   []string                // TablesUsed is the list of tables that this plan will query
+	ResultCache  bool                    // ResultCache is set when vtgate may cache the results of this plan
 
 	ExecCount    uint64 // Count of times this plan was executed
 	ExecTime     uint64 // Total execution time
@@ -87,6 +88,7 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsReturned uint64                `json:",omitempty"`
 		Errors       uint64                `json:",omitempty"`
 		TablesUsed   []string              `json:",omitempty"`
+		ResultCache  bool                  `json:",omitempty"`
 	}{
 		QueryType:    p.Type.String(),
 		Original:     p.Original,
@@ -98,6 +100,7 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsReturned: atomic.LoadUint64(&p.RowsReturned),
 		Errors:       atomic.LoadUint64(&p.Errors),
 		TablesUsed:   p.TablesUsed,
+		ResultCache:  p.ResultCache,
 	}
 
 	b := new(bytes.Buffer)
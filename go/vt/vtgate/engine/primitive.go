@@ -56,6 +56,13 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// CTEMaxRecursionDepth returns the maximum number of iterations allowed for a recursive CTE
+		CTEMaxRecursionDepth() int
+
+		// SpillBudget returns the budget for writing rows that do not fit in memory to temporary files,
+		// or nil when primitives are not allowed to spill to disk
+		SpillBudget() *DiskBudget
+
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
@@ -91,6 +98,9 @@ type (
 		Environment() *vtenv.Environment
 		TimeZone() *time.Location
 		SQLMode() string
+		// GroupConcatMaxLen returns the maximum length in bytes of the values produced by GROUP_CONCAT,
+		// or 0 if it is not known and the values must not be truncated
+		GroupConcatMaxLen() int64
 
 		ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard, query *querypb.BoundQuery, lockFuncType sqlparser.LockingFuncType) (*sqltypes.Result, error)
 
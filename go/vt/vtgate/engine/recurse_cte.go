@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*RecurseCTE)(nil)
+
+// RecurseCTE is used to evaluate recursive common table expressions.
+// The Seed is executed once to produce the initial rows. The Term is then executed
+// once for every row produced by the previous iteration, with the columns of that row
+// available as bind variables, until an iteration produces no new rows.
+type RecurseCTE struct {
+	// Seed is the non-recursive part of the CTE
+	Seed Primitive
+	// Term is the recursive part of the CTE
+	Term Primitive
+
+	// Vars maps the bind variables used by the Term to the column offsets of the rows of the previous iteration
+	Vars map[string]int
+
+	// CheckCols is set when the CTE uses UNION DISTINCT. Rows that have already
+	// been produced are discarded, and are not used for the next iteration.
+	CheckCols []CheckCol `json:",omitempty"`
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (r *RecurseCTE) RouteType() string {
+	return "RecurseCTE"
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (r *RecurseCTE) GetKeyspaceName() string {
+	if r.Seed.GetKeyspaceName() == r.Term.GetKeyspaceName() {
+		return r.Seed.GetKeyspaceName()
+	}
+	return r.Seed.GetKeyspaceName() + "_" + r.Term.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (r *RecurseCTE) GetTableName() string {
+	return r.Seed.GetTableName()
+}
+
+// NeedsTransaction implements the Primitive interface
+func (r *RecurseCTE) NeedsTransaction() bool {
+	return r.Seed.NeedsTransaction() || r.Term.NeedsTransaction()
+}
+
+// Inputs implements the Primitive interface
+func (r *RecurseCTE) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{r.Seed, r.Term}, nil
+}
+
+// GetFields implements the Primitive interface
+func (r *RecurseCTE) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return r.Seed.GetFields(ctx, vcursor, bindVars)
+}
+
+// TryExecute implements the Primitive interface
+func (r *RecurseCTE) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		return executeStreamed(ctx, vcursor, r, bindVars, wantfields)
+	}
+
+	res, err := vcursor.ExecutePrimitive(ctx, r.Seed, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+
+	pt := r.newProbeTable(vcursor)
+	seed, err := r.discardSeen(pt, res.Rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &sqltypes.Result{Fields: res.Fields}
+	result.Rows = append(result.Rows, seed...)
+	err = r.recurse(ctx, vcursor, bindVars, pt, &spillBuffer{vcursor: vcursor, rows: seed, len: len(seed)}, func(rows []sqltypes.Row) error {
+		result.Rows = append(result.Rows, rows...)
+		if vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+			return errMaxMemoryRows(vcursor)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (r *RecurseCTE) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+	seed := &spillBuffer{vcursor: vcursor}
+	pt := r.newProbeTable(vcursor)
+	err := vcursor.StreamExecutePrimitive(ctx, r.Seed, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		rows, err := r.discardSeen(pt, qr.Rows)
+		if err != nil {
+			return err
+		}
+		if err := seed.add(rows); err != nil {
+			return err
+		}
+		return callback(&sqltypes.Result{Fields: qr.Fields, Rows: rows})
+	})
+	if err != nil {
+		seed.close()
+		return err
+	}
+
+	return r.recurse(ctx, vcursor, bindVars, pt, seed, func(rows []sqltypes.Row) error {
+		return callback(&sqltypes.Result{Rows: rows})
+	})
+}
+
+// recurse runs the iterations of the Term, starting with the rows produced by the Seed.
+// The rows produced by each iteration are handed to the callback before the next iteration starts.
+// The rows of an iteration are spilled to disk when they do not fit in memory.
+func (r *RecurseCTE) recurse(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	pt *probeTable,
+	rows *spillBuffer,
+	callback func([]sqltypes.Row) error,
+) error {
+	defer func() {
+		rows.close()
+	}()
+
+	maxDepth := vcursor.CTEMaxRecursionDepth()
+	for depth := 1; rows.len > 0; depth++ {
+		produced := &spillBuffer{vcursor: vcursor}
+		err := rows.forEach(func(row sqltypes.Row) error {
+			qr, err := r.executeTerm(ctx, vcursor, bindVars, row)
+			if err != nil {
+				return err
+			}
+			newRows, err := r.discardSeen(pt, qr.Rows)
+			if err != nil {
+				return err
+			}
+			return produced.add(newRows)
+		})
+		rows.close()
+		rows = produced
+		if err != nil {
+			return err
+		}
+		if rows.len > 0 && depth > maxDepth {
+			return vterrors.VT09025(depth)
+		}
+		if rows.len > 0 {
+			if err := rows.send(callback); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *RecurseCTE) executeTerm(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, row sqltypes.Row) (*sqltypes.Result, error) {
+	termVars := make(map[string]*querypb.BindVariable, len(r.Vars))
+	for k, col := range r.Vars {
+		termVars[k] = sqltypes.ValueBindVariable(row[col])
+	}
+	return vcursor.ExecutePrimitive(ctx, r.Term, combineVars(bindVars, termVars), false)
+}
+
+func (r *RecurseCTE) newProbeTable(vcursor VCursor) *probeTable {
+	if r.CheckCols == nil {
+		return nil
+	}
+	return newProbeTable(r.CheckCols, vcursor.Environment().CollationEnv())
+}
+
+// discardSeen removes the rows that have already been produced when the CTE uses UNION DISTINCT
+func (r *RecurseCTE) discardSeen(pt *probeTable, rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	if pt == nil {
+		return rows, nil
+	}
+	var out []sqltypes.Row
+	for _, row := range rows {
+		newRow, err := pt.exists(row)
+		if err != nil {
+			return nil, err
+		}
+		if newRow != nil {
+			out = append(out, newRow)
+		}
+	}
+	return out, nil
+}
+
+func (r *RecurseCTE) description() PrimitiveDescription {
+	other := map[string]any{
+		"JoinVars": orderedStringIntMap(r.Vars),
+	}
+	var colls []string
+	for _, checkCol := range r.CheckCols {
+		colls = append(colls, checkCol.String())
+	}
+	if colls != nil {
+		other["Collations"] = colls
+	}
+	variant := "UnionAll"
+	if r.CheckCols != nil {
+		variant = "Union"
+	}
+	return PrimitiveDescription{
+		OperatorType: "RecurseCTE",
+		Variant:      variant,
+		Other:        other,
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/test/utils"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestRecurseCTEUnionAll(t *testing.T) {
+	fields := sqltypes.MakeTestFields("n", "int64")
+	seed := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "1")},
+	}
+	term := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "2"),
+			sqltypes.MakeTestResult(fields, "3"),
+			sqltypes.MakeTestResult(fields),
+		},
+	}
+	cte := &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"n": 0},
+	}
+
+	result, err := cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields, "1", "2", "3"), result)
+	term.ExpectLog(t, []string{
+		`Execute n: type:INT64 value:"1" false`,
+		`Execute n: type:INT64 value:"2" false`,
+		`Execute n: type:INT64 value:"3" false`,
+	})
+
+	seed.rewind()
+	term.rewind()
+	result, err = wrapStreamExecute(cte, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields, "1", "2", "3"), result)
+}
+
+func TestRecurseCTEUnionDistinct(t *testing.T) {
+	fields := sqltypes.MakeTestFields("n", "int64")
+	seed := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "1", "1", "2")},
+	}
+	term := &fakePrimitive{
+		results: []*sqltypes.Result{
+			// first iteration, for the rows 1 and 2
+			sqltypes.MakeTestResult(fields, "2", "3"),
+			sqltypes.MakeTestResult(fields, "3"),
+			// second iteration, for the row 3
+			sqltypes.MakeTestResult(fields, "1"),
+		},
+	}
+	cte := &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"n": 0},
+		CheckCols: []CheckCol{{
+			Col:          0,
+			Type:         evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+			CollationEnv: collations.MySQL8(),
+		}},
+	}
+
+	result, err := cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields, "1", "2", "3"), result)
+	term.ExpectLog(t, []string{
+		`Execute n: type:INT64 value:"1" false`,
+		`Execute n: type:INT64 value:"2" false`,
+		`Execute n: type:INT64 value:"3" false`,
+	})
+}
+
+func TestRecurseCTEMaxRecursionDepth(t *testing.T) {
+	fields := sqltypes.MakeTestFields("n", "int64")
+	seed := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "0")},
+	}
+	term := &fakePrimitive{}
+	for i := 1; i <= testCTEMaxRecursionDepth+1; i++ {
+		term.results = append(term.results, sqltypes.MakeTestResult(fields, strconv.Itoa(i)))
+	}
+	cte := &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"n": 0},
+	}
+
+	_, err := cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "VT09025: recursive query aborted after 11 iterations")
+}
+
+func TestRecurseCTESpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	testMaxMemoryRows = 7
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillBudget = nil
+	}()
+
+	fields := sqltypes.MakeTestFields("n", "int64")
+	var seedRows, want []string
+	term := &fakePrimitive{}
+	for i := 0; i < 10; i++ {
+		seedRows = append(seedRows, strconv.Itoa(i))
+		term.results = append(term.results, sqltypes.MakeTestResult(fields, strconv.Itoa(i+10)))
+	}
+	for i := 0; i < 10; i++ {
+		term.results = append(term.results, sqltypes.MakeTestResult(fields))
+	}
+	for i := 0; i < 20; i++ {
+		want = append(want, strconv.Itoa(i))
+	}
+	seed := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, seedRows...)},
+	}
+	cte := &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"n": 0},
+	}
+
+	_, err := wrapStreamExecute(cte, &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "in-memory row count exceeded allowed limit of 7")
+
+	testSpillBudget = NewDiskBudget(t.TempDir(), 0, nil)
+	for _, stream := range []bool{true, false} {
+		seed.rewind()
+		term.rewind()
+		before := spilledBytes.Get()
+		var result *sqltypes.Result
+		if stream {
+			result, err = wrapStreamExecute(cte, &noopVCursor{}, nil, true)
+		} else {
+			result, err = cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+		}
+		require.NoError(t, err)
+		utils.MustMatch(t, sqltypes.MakeTestResult(fields, want...), result)
+		require.Greater(t, spilledBytes.Get(), before)
+		require.Zero(t, testSpillBudget.Used())
+	}
+}
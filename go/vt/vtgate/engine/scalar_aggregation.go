@@ -64,7 +64,7 @@ func (sa *ScalarAggregate) GetFields(ctx context.Context, vcursor VCursor, bindV
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, sa.Aggregates)
+	_, fields, err := newAggregation(qr.Fields, sa.Aggregates, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -80,12 +80,17 @@ func (sa *ScalarAggregate) NeedsTransaction() bool {
 
 // TryExecute implements the Primitive interface
 func (sa *ScalarAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		// aggregate the rows as they are streamed, so that the input does not have to fit in memory
+		return executeStreamed(ctx, vcursor, sa, bindVars, true)
+	}
+
 	result, err := vcursor.ExecutePrimitive(ctx, sa.Input, bindVars, true)
 	if err != nil {
 		return nil, err
 	}
 
-	agg, fields, err := newAggregation(result.Fields, sa.Aggregates)
+	agg, fields, err := newAggregation(result.Fields, sa.Aggregates, vcursor.GroupConcatMaxLen())
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +128,7 @@ func (sa *ScalarAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor
 
 		if agg == nil && len(result.Fields) != 0 {
 			var err error
-			agg, fields, err = newAggregation(result.Fields, sa.Aggregates)
+			agg, fields, err = newAggregation(result.Fields, sa.Aggregates, vcursor.GroupConcatMaxLen())
 			if err != nil {
 				return err
 			}
@@ -28,6 +28,7 @@ import (
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	"github.com/mdibaiee/vitess/go/test/utils"
 	. "github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 )
 
 func TestEmptyRows(outer *testing.T) {
@@ -296,6 +297,61 @@ func TestScalarDistinctAggrOnEngine(t *testing.T) {
 	require.Equal(t, `[[INT64(4) DECIMAL(1300)]]`, fmt.Sprintf("%v", results.Rows))
 }
 
+func TestScalarHashDistinctAggrOnEngine(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|a|c|b",
+		"int64|int64|int64|varchar",
+	)
+
+	// the input is not sorted by any of the distinct columns
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(
+		fields,
+		"1|1|10|x",
+		"2|2|20|y",
+		"1|1|10|X",
+		"3|3|30|null",
+		"2|2|20|x",
+		"1|1|null|x",
+	)}}
+
+	hashOn := func(cols ...int) []CheckCol {
+		var checkCols []CheckCol
+		for _, col := range cols {
+			typ := evalengine.NewType(fields[col].Type, collations.CollationBinaryID)
+			if col == 3 {
+				typ = evalengine.NewType(sqltypes.VarChar, collations.MySQL8().DefaultConnectionCharset())
+			}
+			checkCols = append(checkCols, CheckCol{Col: col, Type: typ, CollationEnv: collations.MySQL8()})
+		}
+		return checkCols
+	}
+
+	countA := NewAggregateParam(AggregateCountDistinct, 0, "count(distinct a)", collations.MySQL8())
+	countA.DistinctCols = hashOn(0)
+	countAB := NewAggregateParam(AggregateCountDistinct, 1, "count(distinct a, b)", collations.MySQL8())
+	countAB.DistinctCols = hashOn(1, 3)
+	sumC := NewAggregateParam(AggregateSumDistinct, 2, "sum(distinct c)", collations.MySQL8())
+	sumC.DistinctCols = hashOn(2)
+
+	oa := &ScalarAggregate{
+		Aggregates:          []*AggregateParams{countA, countAB, sumC},
+		TruncateColumnCount: 3,
+		Input:               fp,
+	}
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, `[[INT64(3) INT64(3) DECIMAL(60)]]`, fmt.Sprintf("%v", qr.Rows))
+
+	fp.rewind()
+	results := &sqltypes.Result{}
+	err = oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		results.Rows = append(results.Rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, `[[INT64(3) INT64(3) DECIMAL(60)]]`, fmt.Sprintf("%v", results.Rows))
+}
+
 func TestScalarDistinctPushedDown(t *testing.T) {
 	fields := sqltypes.MakeTestFields(
 		"count(distinct value)|sum(distinct value)",
@@ -18,9 +18,14 @@ package engine
 
 import (
 	"context"
+	"slices"
+	"sync"
 
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	. "github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 )
 
 var _ Primitive = (*SemiJoin)(nil)
@@ -35,6 +40,25 @@ type SemiJoin struct {
 	// be built from the LHS result before invoking
 	// the RHS subquery.
 	Vars map[string]int `json:",omitempty"`
+
+	// The fields below are used for correlated subqueries that need the values produced by the RHS,
+	// and not only whether it produced any rows. When none of them are set, the LHS rows that
+	// have matching rows on the RHS are returned.
+
+	// Opcode decides how the RHS result is exposed, in the same way as for UncorrelatedSubquery.
+	// SubqueryResult and HasValues are the names of the bind variables the result is exposed as.
+	Opcode         PulloutOpcode `json:",omitempty"`
+	SubqueryResult string        `json:",omitempty"`
+	HasValues      string        `json:",omitempty"`
+
+	// Filter is evaluated against every LHS row, with the RHS result available as bind variables.
+	// Only the rows for which it is true are returned.
+	Filter    evalengine.Expr `json:",omitempty"`
+	ASTFilter sqlparser.Expr  `json:",omitempty"`
+
+	// ValueCol is the offset of the LHS column that is replaced with the value produced by a
+	// scalar subquery. All LHS rows are returned.
+	ValueCol *int `json:",omitempty"`
 }
 
 // TryExecute performs a non-streaming exec.
@@ -45,6 +69,12 @@ func (jn *SemiJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 		return nil, err
 	}
 	result := &sqltypes.Result{Fields: lresult.Fields}
+	if wantfields {
+		result.Fields, err = jn.valueField(ctx, vcursor, bindVars, lresult.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
 	for _, lrow := range lresult.Rows {
 		for k, col := range jn.Vars {
 			joinVars[k] = sqltypes.ValueBindVariable(lrow[col])
@@ -53,8 +83,12 @@ func (jn *SemiJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 		if err != nil {
 			return nil, err
 		}
-		if len(rresult.Rows) > 0 {
-			result.Rows = append(result.Rows, lrow)
+		row, err := jn.outputRow(ctx, vcursor, bindVars, lrow, rresult)
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			result.Rows = append(result.Rows, row)
 		}
 	}
 	return result, nil
@@ -62,6 +96,9 @@ func (jn *SemiJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 
 // TryStreamExecute performs a streaming exec.
 func (jn *SemiJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	if jn.needsSubqueryResult() {
+		return jn.streamExecuteWithSubqueryResult(ctx, vcursor, bindVars, wantfields, callback)
+	}
 	joinVars := make(map[string]*querypb.BindVariable)
 	err := vcursor.StreamExecutePrimitive(ctx, jn.Left, bindVars, wantfields, func(lresult *sqltypes.Result) error {
 		result := &sqltypes.Result{Fields: lresult.Fields}
@@ -86,9 +123,122 @@ func (jn *SemiJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 	return err
 }
 
+func (jn *SemiJoin) streamExecuteWithSubqueryResult(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+	joinVars := make(map[string]*querypb.BindVariable)
+	return vcursor.StreamExecutePrimitive(ctx, jn.Left, bindVars, wantfields, func(lresult *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		result := &sqltypes.Result{Fields: lresult.Fields}
+		if lresult.Fields != nil {
+			var err error
+			result.Fields, err = jn.valueField(ctx, vcursor, bindVars, lresult.Fields)
+			if err != nil {
+				return err
+			}
+		}
+		for _, lrow := range lresult.Rows {
+			for k, col := range jn.Vars {
+				joinVars[k] = sqltypes.ValueBindVariable(lrow[col])
+			}
+			rresult, err := vcursor.ExecutePrimitive(ctx, jn.Right, combineVars(bindVars, joinVars), false)
+			if err != nil {
+				return err
+			}
+			row, err := jn.outputRow(ctx, vcursor, bindVars, lrow, rresult)
+			if err != nil {
+				return err
+			}
+			if row != nil {
+				result.Rows = append(result.Rows, row)
+			}
+		}
+		return callback(result)
+	})
+}
+
+func (jn *SemiJoin) needsSubqueryResult() bool {
+	return jn.Filter != nil || jn.ValueCol != nil
+}
+
+// outputRow returns the row that should be returned for the given LHS row, or nil if the row is filtered out
+func (jn *SemiJoin) outputRow(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, lrow sqltypes.Row, rresult *sqltypes.Result) (sqltypes.Row, error) {
+	if !jn.needsSubqueryResult() {
+		if len(rresult.Rows) > 0 {
+			return lrow, nil
+		}
+		return nil, nil
+	}
+
+	subqueryVars := combineVars(bindVars, nil)
+	if err := addSubqueryResult(jn.Opcode, rresult, jn.SubqueryResult, jn.HasValues, subqueryVars); err != nil {
+		return nil, err
+	}
+
+	if jn.ValueCol != nil {
+		name := jn.SubqueryResult
+		if jn.Opcode == PulloutExists {
+			name = jn.HasValues
+		}
+		value, err := sqltypes.BindVariableToValue(subqueryVars[name])
+		if err != nil {
+			return nil, err
+		}
+		row := slices.Clone(lrow)
+		row[*jn.ValueCol] = value
+		return row, nil
+	}
+
+	env := evalengine.NewExpressionEnv(ctx, subqueryVars, vcursor)
+	env.Row = lrow
+	evalResult, err := env.Evaluate(jn.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if evalResult.ToBoolean() {
+		return lrow, nil
+	}
+	return nil, nil
+}
+
+// valueField replaces the field of the value column with the field produced by the RHS
+func (jn *SemiJoin) valueField(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, fields []*querypb.Field) ([]*querypb.Field, error) {
+	if jn.ValueCol == nil {
+		return fields, nil
+	}
+	fields = slices.Clone(fields)
+	if jn.Opcode == PulloutExists {
+		fields[*jn.ValueCol] = &querypb.Field{Name: fields[*jn.ValueCol].Name, Type: sqltypes.Int64}
+		return fields, nil
+	}
+	joinVars := make(map[string]*querypb.BindVariable)
+	for k := range jn.Vars {
+		joinVars[k] = sqltypes.NullBindVariable
+	}
+	rresult, err := jn.Right.GetFields(ctx, vcursor, combineVars(bindVars, joinVars))
+	if err != nil {
+		return nil, err
+	}
+	if len(rresult.Fields) != 1 {
+		return nil, errSqColumn
+	}
+	field := rresult.Fields[0].CloneVT()
+	field.Name = fields[*jn.ValueCol].Name
+	fields[*jn.ValueCol] = field
+	return fields, nil
+}
+
 // GetFields fetches the field info.
 func (jn *SemiJoin) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
-	return jn.Left.GetFields(ctx, vcursor, bindVars)
+	lresult, err := jn.Left.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := jn.valueField(ctx, vcursor, bindVars, lresult.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: fields}, nil
 }
 
 // Inputs returns the input primitives for this SemiJoin
@@ -130,8 +280,30 @@ func (jn *SemiJoin) description() PrimitiveDescription {
 	if len(jn.Vars) > 0 {
 		other["JoinVars"] = orderedStringIntMap(jn.Vars)
 	}
+	if !jn.needsSubqueryResult() {
+		return PrimitiveDescription{
+			OperatorType: "SemiJoin",
+			Other:        other,
+		}
+	}
+
+	var pulloutVars []string
+	if jn.HasValues != "" {
+		pulloutVars = append(pulloutVars, jn.HasValues)
+	}
+	if jn.SubqueryResult != "" {
+		pulloutVars = append(pulloutVars, jn.SubqueryResult)
+	}
+	other["PulloutVars"] = pulloutVars
+	if jn.ASTFilter != nil {
+		other["Predicate"] = sqlparser.String(jn.ASTFilter)
+	}
+	if jn.ValueCol != nil {
+		other["ValueColumn"] = *jn.ValueCol
+	}
 	return PrimitiveDescription{
 		OperatorType: "SemiJoin",
+		Variant:      jn.Opcode.String(),
 		Other:        other,
 	}
 }
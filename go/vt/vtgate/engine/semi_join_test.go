@@ -20,13 +20,18 @@ import (
 	"context"
 	"testing"
 
+	"github.com/mdibaiee/vitess/go/mysql/collations"
 	"github.com/mdibaiee/vitess/go/test/utils"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vtenv"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/mdibaiee/vitess/go/sqltypes"
 
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	. "github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
 )
 
 func TestSemiJoinExecute(t *testing.T) {
@@ -159,3 +164,156 @@ func TestSemiJoinStreamExecute(t *testing.T) {
 		"4|d|dd",
 	))
 }
+
+func TestSemiJoinExecuteFilter(t *testing.T) {
+	leftFields := sqltypes.MakeTestFields(
+		"col1|col2",
+		"int64|varchar",
+	)
+	leftPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				leftFields,
+				"1|a",
+				"2|b",
+				"3|c",
+			),
+		},
+	}
+	rightFields := sqltypes.MakeTestFields(
+		"col3",
+		"int64",
+	)
+	rightPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				rightFields,
+				"1",
+				"4",
+			),
+			sqltypes.MakeTestResult(
+				rightFields,
+			),
+			sqltypes.MakeTestResult(
+				rightFields,
+				"5",
+			),
+		},
+	}
+
+	// col1 NOT IN (subquery)
+	ast, err := sqlparser.NewTestParser().ParseExpr("not :__sq_has_values or col1 not in ::__sq1")
+	require.NoError(t, err)
+	filter, err := evalengine.Translate(ast, &evalengine.Config{
+		ResolveColumn: evalengine.FieldResolver(leftFields).Column,
+		Collation:     collations.MySQL8().DefaultConnectionCharset(),
+		Environment:   vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+
+	jn := &SemiJoin{
+		Left:  leftPrim,
+		Right: rightPrim,
+		Vars: map[string]int{
+			"bv": 1,
+		},
+		Opcode:         PulloutNotIn,
+		SubqueryResult: "__sq1",
+		HasValues:      "__sq_has_values",
+		Filter:         filter,
+		ASTFilter:      ast,
+	}
+	r, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	rightPrim.ExpectLog(t, []string{
+		`Execute bv: type:VARCHAR value:"a" false`,
+		`Execute bv: type:VARCHAR value:"b" false`,
+		`Execute bv: type:VARCHAR value:"c" false`,
+	})
+	utils.MustMatch(t, sqltypes.MakeTestResult(
+		leftFields,
+		"2|b",
+		"3|c",
+	), r)
+}
+
+func TestSemiJoinExecuteValue(t *testing.T) {
+	leftPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields(
+					"col1|null",
+					"int64|null_type",
+				),
+				"1|null",
+				"2|null",
+				"3|null",
+			),
+		},
+	}
+	rightFields := sqltypes.MakeTestFields(
+		"col3",
+		"varchar",
+	)
+	rightPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				rightFields,
+			),
+			sqltypes.MakeTestResult(
+				rightFields,
+				"a",
+			),
+			sqltypes.MakeTestResult(
+				rightFields,
+				"b",
+			),
+			sqltypes.MakeTestResult(
+				rightFields,
+				"c",
+				"d",
+			),
+		},
+	}
+
+	valueCol := 1
+	jn := &SemiJoin{
+		Left:  leftPrim,
+		Right: rightPrim,
+		Vars: map[string]int{
+			"bv": 0,
+		},
+		Opcode:         PulloutValue,
+		SubqueryResult: "__sq1",
+		ValueCol:       &valueCol,
+	}
+	r, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	rightPrim.ExpectLog(t, []string{
+		`Execute bv: type:INT64 value:"1" false`,
+		`Execute bv: type:INT64 value:"2" false`,
+		`Execute bv: type:INT64 value:"3" false`,
+	})
+	utils.MustMatch(t, [][]sqltypes.Value{
+		{sqltypes.NewInt64(1), sqltypes.NULL},
+		{sqltypes.NewInt64(2), sqltypes.NewVarChar("a")},
+		{sqltypes.NewInt64(3), sqltypes.NewVarChar("b")},
+	}, r.Rows)
+
+	// a scalar subquery returning more than one row is an error
+	leftPrim.rewind()
+	_, err = jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, "subquery returned more than one row")
+
+	// the type of the value column comes from the subquery
+	leftPrim.rewind()
+	rightPrim.results = []*sqltypes.Result{sqltypes.MakeTestResult(rightFields)}
+	rightPrim.rewind()
+	r, err = jn.GetFields(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{})
+	require.NoError(t, err)
+	rightPrim.ExpectLog(t, []string{
+		`GetFields bv: `,
+		`Execute bv:  true`,
+	})
+	utils.MustMatch(t, sqltypes.MakeTestFields("col1|null", "int64|varchar"), r.Fields)
+}
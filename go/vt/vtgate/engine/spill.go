@@ -0,0 +1,476 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/stats"
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+)
+
+var spilledBytes = stats.NewCounter("SpilledBytes", "Number of bytes written to temporary files by primitives that spilled their rows to disk")
+
+// spillBatchSize is the number of rows sent to the callback at a time when a primitive streams rows that were spilled to disk
+const spillBatchSize = 1000
+
+// DiskBudget limits how many bytes primitives can write to temporary files when their rows
+// do not fit in memory. A budget can have a parent, so that the bytes used by a single query
+// are also counted against the budget of the whole process.
+type DiskBudget struct {
+	dir    string
+	limit  int64
+	used   atomic.Int64
+	parent *DiskBudget
+}
+
+// NewDiskBudget creates a budget for temporary files created in dir. If dir is empty, the default
+// directory for temporary files is used. A limit of zero or less means that there is no limit.
+func NewDiskBudget(dir string, limit int64, parent *DiskBudget) *DiskBudget {
+	return &DiskBudget{
+		dir:    dir,
+		limit:  limit,
+		parent: parent,
+	}
+}
+
+// Used returns the number of bytes that are currently written to temporary files under this budget
+func (b *DiskBudget) Used() int64 {
+	return b.used.Load()
+}
+
+func (b *DiskBudget) reserve(n int64) error {
+	used := b.used.Add(n)
+	if b.limit > 0 && used > b.limit {
+		b.used.Add(-n)
+		return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "temporary disk usage exceeded allowed limit of %d bytes", b.limit)
+	}
+	if b.parent != nil {
+		if err := b.parent.reserve(n); err != nil {
+			b.used.Add(-n)
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *DiskBudget) release(n int64) {
+	for ; b != nil; b = b.parent {
+		b.used.Add(-n)
+	}
+}
+
+// spillFile is a temporary file holding rows that did not fit in memory.
+// The file is removed as soon as it's created, so it disappears when it is closed or when the process exits.
+type spillFile struct {
+	budget *DiskBudget
+	file   *os.File
+	w      *bufio.Writer
+	buf    []byte
+	size   int64
+}
+
+func (b *DiskBudget) newSpillFile() (*spillFile, error) {
+	file, err := os.CreateTemp(b.dir, "vtgate-spill-")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(file.Name()); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &spillFile{
+		budget: b,
+		file:   file,
+		w:      bufio.NewWriter(file),
+	}, nil
+}
+
+// write appends a row to the file. Every value is stored as its type, followed by the length of the value, or -1 for NULL, and its bytes.
+func (f *spillFile) write(row sqltypes.Row) error {
+	buf := binary.AppendUvarint(f.buf[:0], uint64(len(row)))
+	for _, v := range row {
+		buf = binary.AppendUvarint(buf, uint64(v.Type()))
+		if v.IsNull() {
+			buf = binary.AppendVarint(buf, -1)
+			continue
+		}
+		raw := v.Raw()
+		buf = binary.AppendVarint(buf, int64(len(raw)))
+		buf = append(buf, raw...)
+	}
+	f.buf = buf
+
+	if err := f.budget.reserve(int64(len(buf))); err != nil {
+		return err
+	}
+	f.size += int64(len(buf))
+	spilledBytes.Add(int64(len(buf)))
+	_, err := f.w.Write(buf)
+	return err
+}
+
+// reader flushes the file and returns a reader that starts at the first row of the file
+func (f *spillFile) reader() (*spillReader, error) {
+	if err := f.w.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &spillReader{r: bufio.NewReader(f.file)}, nil
+}
+
+func (f *spillFile) close() {
+	f.file.Close()
+	f.budget.release(f.size)
+	f.size = 0
+}
+
+func closeSpillFiles(files []*spillFile) {
+	for _, f := range files {
+		if f != nil {
+			f.close()
+		}
+	}
+}
+
+type spillReader struct {
+	r *bufio.Reader
+}
+
+// next returns the next row of the file, or io.EOF when all the rows have been read
+func (r *spillReader) next() (sqltypes.Row, error) {
+	cols, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, err
+	}
+	row := make(sqltypes.Row, cols)
+	for i := range row {
+		typ, err := binary.ReadUvarint(r.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		size, err := binary.ReadVarint(r.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if size < 0 {
+			row[i] = sqltypes.NULL
+			continue
+		}
+		val := make([]byte, size)
+		if _, err := io.ReadFull(r.r, val); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		row[i] = sqltypes.MakeTrusted(querypb.Type(typ), val)
+	}
+	return row, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// readSpillFile calls f for every row in the file
+func readSpillFile(file *spillFile, f func(sqltypes.Row) error) error {
+	r, err := file.reader()
+	if err != nil {
+		return err
+	}
+	for {
+		row, err := r.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(row); err != nil {
+			return err
+		}
+	}
+}
+
+// sendSpillFile sends all the rows in the file to the callback in batches
+func sendSpillFile(file *spillFile, callback func([]sqltypes.Row) error) error {
+	batch := make([]sqltypes.Row, 0, spillBatchSize)
+	err := readSpillFile(file, func(row sqltypes.Row) error {
+		batch = append(batch, row)
+		if len(batch) < spillBatchSize {
+			return nil
+		}
+		err := callback(batch)
+		batch = make([]sqltypes.Row, 0, spillBatchSize)
+		return err
+	})
+	if err != nil || len(batch) == 0 {
+		return err
+	}
+	return callback(batch)
+}
+
+// rowSource is a sorted run of rows that is merged with other runs by mergeSpilled
+type rowSource func() (sqltypes.Row, error)
+
+func spillFileSource(f *spillFile) (rowSource, error) {
+	r, err := f.reader()
+	if err != nil {
+		return nil, err
+	}
+	return r.next, nil
+}
+
+func sliceSource(rows []sqltypes.Row) rowSource {
+	return func() (sqltypes.Row, error) {
+		if len(rows) == 0 {
+			return nil, io.EOF
+		}
+		row := rows[0]
+		rows = rows[1:]
+		return row, nil
+	}
+}
+
+// mergeSpilled merges runs of rows that are each sorted by cmp, and sends the rows to the callback
+// in batches, stopping after limit rows
+func mergeSpilled(sources []rowSource, cmp evalengine.Comparison, limit int, callback func([]sqltypes.Row) error) (err error) {
+	defer evalengine.PanicHandler(&err)
+
+	merge := &evalengine.Merger{Compare: cmp}
+	for i, source := range sources {
+		row, err := source()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		merge.Push(row, i)
+	}
+	merge.Init()
+
+	batch := make([]sqltypes.Row, 0, spillBatchSize)
+	for merge.Len() != 0 && limit > 0 {
+		row, source := merge.Pop()
+		batch = append(batch, row)
+		limit--
+		if len(batch) == spillBatchSize {
+			if err := callback(batch); err != nil {
+				return err
+			}
+			batch = make([]sqltypes.Row, 0, spillBatchSize)
+		}
+
+		next, err := sources[source]()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		merge.Push(next, source)
+	}
+	if len(batch) > 0 {
+		return callback(batch)
+	}
+	return nil
+}
+
+// spillRows writes the rows to a new temporary file
+func spillRows(budget *DiskBudget, rows []sqltypes.Row) (*spillFile, error) {
+	file, err := budget.newSpillFile()
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if err := file.write(row); err != nil {
+			file.close()
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+func errMaxMemoryRows(vcursor VCursor) error {
+	return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+}
+
+// externalSorter sorts rows that may not fit in memory. When it holds more rows than allowed in memory,
+// it sorts them and writes them to a temporary file as a run, and the runs are merged once all the rows are pushed.
+type externalSorter struct {
+	vcursor VCursor
+	compare evalengine.Comparison
+	limit   int
+
+	sorter *evalengine.Sorter
+	runs   []*spillFile
+}
+
+func newExternalSorter(vcursor VCursor, compare evalengine.Comparison, limit int) *externalSorter {
+	return &externalSorter{
+		vcursor: vcursor,
+		compare: compare,
+		limit:   limit,
+		sorter:  &evalengine.Sorter{Compare: compare, Limit: limit},
+	}
+}
+
+// push adds the rows to the sorter, spilling them to disk if there are too many of them to keep in memory.
+// It fails if spilling to disk is disabled.
+func (s *externalSorter) push(rows []sqltypes.Row) error {
+	for _, row := range rows {
+		s.sorter.Push(row)
+	}
+	if !s.vcursor.ExceedsMaxMemoryRows(s.sorter.Len()) {
+		return nil
+	}
+	budget := s.vcursor.SpillBudget()
+	if budget == nil {
+		return errMaxMemoryRows(s.vcursor)
+	}
+	run, err := spillRows(budget, s.sorter.Sorted())
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run)
+	s.sorter = &evalengine.Sorter{Compare: s.compare, Limit: s.limit}
+	return nil
+}
+
+// send sends the sorted rows to the callback, merging the runs on disk with the rows that are still in memory
+func (s *externalSorter) send(callback func([]sqltypes.Row) error) error {
+	if len(s.runs) == 0 {
+		return callback(s.sorter.Sorted())
+	}
+	sources := []rowSource{sliceSource(s.sorter.Sorted())}
+	for _, run := range s.runs {
+		source, err := spillFileSource(run)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+	return mergeSpilled(sources, s.compare, s.limit, callback)
+}
+
+func (s *externalSorter) close() {
+	closeSpillFiles(s.runs)
+	s.runs = nil
+}
+
+// spillBuffer holds rows in memory until there are more of them than allowed, and then moves them to a temporary file.
+type spillBuffer struct {
+	vcursor VCursor
+	rows    []sqltypes.Row
+	file    *spillFile
+	len     int
+}
+
+// add appends the rows to the buffer. It fails if the rows do not fit in memory and spilling to disk is disabled.
+func (b *spillBuffer) add(rows []sqltypes.Row) error {
+	b.len += len(rows)
+	if b.file != nil {
+		for _, row := range rows {
+			if err := b.file.write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	b.rows = append(b.rows, rows...)
+	if !b.vcursor.ExceedsMaxMemoryRows(len(b.rows)) {
+		return nil
+	}
+	budget := b.vcursor.SpillBudget()
+	if budget == nil {
+		return errMaxMemoryRows(b.vcursor)
+	}
+	file, err := spillRows(budget, b.rows)
+	if err != nil {
+		return err
+	}
+	b.file = file
+	b.rows = nil
+	return nil
+}
+
+// forEach calls f for every row of the buffer, in the order they were added
+func (b *spillBuffer) forEach(f func(sqltypes.Row) error) error {
+	if b.file != nil {
+		return readSpillFile(b.file, f)
+	}
+	for _, row := range b.rows {
+		if err := f(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send sends all the rows of the buffer to the callback, in batches if they were spilled to disk
+func (b *spillBuffer) send(callback func([]sqltypes.Row) error) error {
+	if b.file != nil {
+		return sendSpillFile(b.file, callback)
+	}
+	return callback(b.rows)
+}
+
+func (b *spillBuffer) close() {
+	if b.file != nil {
+		b.file.close()
+		b.file = nil
+	}
+	b.rows = nil
+	b.len = 0
+}
+
+// executeStreamed collects the results of the streaming execution of a primitive. When spilling to disk is allowed,
+// the primitives that buffer rows execute through their streaming path, which spills, instead of holding all the rows
+// of their inputs in memory.
+func executeStreamed(ctx context.Context, vcursor VCursor, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	result := &sqltypes.Result{}
+	var mu sync.Mutex
+	err := primitive.TryStreamExecute(ctx, vcursor, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if result.Fields == nil && len(qr.Fields) != 0 {
+			result.Fields = qr.Fields
+		}
+		result.Rows = append(result.Rows, qr.Rows...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
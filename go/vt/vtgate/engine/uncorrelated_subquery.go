@@ -17,13 +17,16 @@ limitations under the License.
 package engine
 
 import (
+	"bytes"
 	"context"
 
+	"github.com/mdibaiee/vitess/go/mysql/collations"
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
 	. "github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 )
 
 var _ Primitive = (*UncorrelatedSubquery)(nil)
@@ -37,6 +40,14 @@ type UncorrelatedSubquery struct {
 	SubqueryResult string
 	HasValues      string
 
+	// MaxResult and HasNulls are only used by PulloutMinMax. SubqueryResult then holds the smallest
+	// non-NULL value returned by the subquery, MaxResult the largest one, and HasNulls tells if any NULL was returned
+	MaxResult string
+	HasNulls  string
+	// ComparisonType is the type of the value compared with the subquery by PulloutMinMax. The smallest and
+	// largest values are the ones MySQL finds when comparing them with a value of this type.
+	ComparisonType evalengine.Type
+
 	Subquery Primitive
 	Outer    Primitive
 }
@@ -100,6 +111,11 @@ func (ps *UncorrelatedSubquery) GetFields(ctx context.Context, vcursor VCursor,
 		}
 	case PulloutExists:
 		combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(0)
+	case PulloutMinMax:
+		combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(0)
+		combinedVars[ps.SubqueryResult] = sqltypes.NullBindVariable
+		combinedVars[ps.MaxResult] = sqltypes.NullBindVariable
+		combinedVars[ps.HasNulls] = sqltypes.Int64BindVariable(0)
 	}
 	return ps.Outer.GetFields(ctx, vcursor, combinedVars)
 }
@@ -119,7 +135,9 @@ func (ps *UncorrelatedSubquery) execSubquery(ctx context.Context, vcursor VCurso
 	for k, v := range bindVars {
 		subqueryBindVars[k] = v
 	}
-	result, err := vcursor.ExecutePrimitive(ctx, ps.Subquery, subqueryBindVars, false)
+	// the field types are needed to know how to compare the values of the subquery
+	wantfields := ps.Opcode == PulloutMinMax
+	result, err := vcursor.ExecutePrimitive(ctx, ps.Subquery, subqueryBindVars, wantfields)
 	if err != nil {
 		return nil, err
 	}
@@ -127,27 +145,41 @@ func (ps *UncorrelatedSubquery) execSubquery(ctx context.Context, vcursor VCurso
 	for k, v := range bindVars {
 		combinedVars[k] = v
 	}
-	switch ps.Opcode {
+	if ps.Opcode == PulloutMinMax {
+		if err := ps.addMinMaxResult(vcursor, result, combinedVars); err != nil {
+			return nil, err
+		}
+		return combinedVars, nil
+	}
+	if err := addSubqueryResult(ps.Opcode, result, ps.SubqueryResult, ps.HasValues, combinedVars); err != nil {
+		return nil, err
+	}
+	return combinedVars, nil
+}
+
+// addSubqueryResult adds the result of a subquery to the bind variables, in the shape expected by the opcode
+func addSubqueryResult(opcode PulloutOpcode, result *sqltypes.Result, subqueryResult, hasValues string, bindVars map[string]*querypb.BindVariable) error {
+	switch opcode {
 	case PulloutValue:
 		switch len(result.Rows) {
 		case 0:
-			combinedVars[ps.SubqueryResult] = sqltypes.NullBindVariable
+			bindVars[subqueryResult] = sqltypes.NullBindVariable
 		case 1:
-			combinedVars[ps.SubqueryResult] = sqltypes.ValueBindVariable(result.Rows[0][0])
+			bindVars[subqueryResult] = sqltypes.ValueBindVariable(result.Rows[0][0])
 		default:
-			return nil, errSqRow
+			return errSqRow
 		}
 	case PulloutIn, PulloutNotIn:
 		switch len(result.Rows) {
 		case 0:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(0)
+			bindVars[hasValues] = sqltypes.Int64BindVariable(0)
 			// Add a bogus value. It will not be checked.
-			combinedVars[ps.SubqueryResult] = &querypb.BindVariable{
+			bindVars[subqueryResult] = &querypb.BindVariable{
 				Type:   querypb.Type_TUPLE,
 				Values: []*querypb.Value{sqltypes.ValueToProto(sqltypes.NewInt64(0))},
 			}
 		default:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(1)
+			bindVars[hasValues] = sqltypes.Int64BindVariable(1)
 			values := &querypb.BindVariable{
 				Type:   querypb.Type_TUPLE,
 				Values: make([]*querypb.Value, len(result.Rows)),
@@ -155,17 +187,92 @@ func (ps *UncorrelatedSubquery) execSubquery(ctx context.Context, vcursor VCurso
 			for i, v := range result.Rows {
 				values.Values[i] = sqltypes.ValueToProto(v[0])
 			}
-			combinedVars[ps.SubqueryResult] = values
+			bindVars[subqueryResult] = values
 		}
 	case PulloutExists:
 		switch len(result.Rows) {
 		case 0:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(0)
+			bindVars[hasValues] = sqltypes.Int64BindVariable(0)
 		default:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(1)
+			bindVars[hasValues] = sqltypes.Int64BindVariable(1)
 		}
 	}
-	return combinedVars, nil
+	return nil
+}
+
+// addMinMaxResult adds the smallest and largest non-NULL values of the subquery result to the bind variables,
+// together with flags telling if the subquery returned any rows and if any of them were NULL.
+// This is all that is needed to evaluate ANY/ALL comparisons against the subquery.
+func (ps *UncorrelatedSubquery) addMinMaxResult(vcursor VCursor, result *sqltypes.Result, bindVars map[string]*querypb.BindVariable) error {
+	collationID := vcursor.ConnCollation()
+	if len(result.Fields) > 0 && sqltypes.IsText(result.Fields[0].Type) && result.Fields[0].Charset != 0 {
+		collationID = collations.ID(result.Fields[0].Charset)
+	}
+	collationEnv := vcursor.Environment().CollationEnv()
+
+	compare := func(v1, v2 sqltypes.Value) (int, error) {
+		return evalengine.NullsafeCompare(v1, v2, collationEnv, collationID, nil)
+	}
+	if ps.ComparisonType.Valid() && len(result.Fields) > 0 {
+		valueType := evalengine.NewTypeFromField(result.Fields[0])
+		if sqltypes.IsText(result.Fields[0].Type) && result.Fields[0].Charset == 0 {
+			valueType = evalengine.NewType(result.Fields[0].Type, collationID)
+		}
+		typ, err := evalengine.CoerceTypes(ps.ComparisonType, valueType, collationEnv)
+		if err != nil {
+			return err
+		}
+		if typ.Type() != sqltypes.Null && !typ.Equal(&valueType) {
+			// the values are ordered like they are when compared with the outer value,
+			// which can be different from their own order, e.g. '10' < '9' but 10 > 9
+			sqlmode := evalengine.ParseSQLMode(vcursor.SQLMode())
+			compare = func(v1, v2 sqltypes.Value) (int, error) {
+				w1, _, err := evalengine.WeightString(nil, v1, typ.Type(), typ.Collation(), 0, 0, nil, sqlmode)
+				if err != nil {
+					return 0, err
+				}
+				w2, _, err := evalengine.WeightString(nil, v2, typ.Type(), typ.Collation(), 0, 0, nil, sqlmode)
+				if err != nil {
+					return 0, err
+				}
+				return bytes.Compare(w1, w2), nil
+			}
+		}
+	}
+
+	minValue, maxValue := sqltypes.NULL, sqltypes.NULL
+	hasNulls := false
+	for _, row := range result.Rows {
+		v := row[0]
+		if v.IsNull() {
+			hasNulls = true
+			continue
+		}
+		if minValue.IsNull() {
+			minValue, maxValue = v, v
+			continue
+		}
+		cmp, err := compare(v, minValue)
+		if err != nil {
+			return err
+		}
+		if cmp < 0 {
+			minValue = v
+		}
+		cmp, err = compare(v, maxValue)
+		if err != nil {
+			return err
+		}
+		if cmp > 0 {
+			maxValue = v
+		}
+	}
+
+	bindVars[ps.HasValues] = sqltypes.BoolBindVariable(len(result.Rows) > 0)
+	bindVars[ps.SubqueryResult] = sqltypes.ValueBindVariable(minValue)
+	bindVars[ps.MaxResult] = sqltypes.ValueBindVariable(maxValue)
+	bindVars[ps.HasNulls] = sqltypes.BoolBindVariable(hasNulls)
+	return nil
 }
 
 func (ps *UncorrelatedSubquery) description() PrimitiveDescription {
@@ -177,9 +284,15 @@ func (ps *UncorrelatedSubquery) description() PrimitiveDescription {
 	if ps.SubqueryResult != "" {
 		pulloutVars = append(pulloutVars, ps.SubqueryResult)
 	}
+	if ps.MaxResult != "" {
+		pulloutVars = append(pulloutVars, ps.MaxResult, ps.HasNulls)
+	}
 	if len(pulloutVars) > 0 {
 		other["PulloutVars"] = pulloutVars
 	}
+	if ps.ComparisonType.Valid() {
+		other["ComparisonType"] = ps.ComparisonType.Type().String()
+	}
 	return PrimitiveDescription{
 		OperatorType: "UncorrelatedSubquery",
 		Variant:      ps.Opcode.String(),
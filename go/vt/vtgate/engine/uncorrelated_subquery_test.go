@@ -23,9 +23,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/mdibaiee/vitess/go/mysql/collations"
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	. "github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 )
 
 func TestPulloutSubqueryValueGood(t *testing.T) {
@@ -230,6 +232,66 @@ func TestPulloutSubqueryExistsNone(t *testing.T) {
 	ufp.ExpectLog(t, []string{`Execute has_values: type:INT64 value:"0" false`})
 }
 
+func TestPulloutSubqueryMinMax(t *testing.T) {
+	tcases := []struct {
+		name           string
+		sqResult       *sqltypes.Result
+		comparisonType evalengine.Type
+		expected       string
+	}{{
+		name:     "numbers",
+		sqResult: sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1", "int64"), "3", "null", "-1", "10", "2"),
+		expected: `Execute has_nulls: type:INT64 value:"1" has_values: type:INT64 value:"1" max: type:INT64 value:"10" min: type:INT64 value:"-1" false`,
+	}, {
+		name:     "strings",
+		sqResult: sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1", "varchar"), "b", "c", "A"),
+		expected: `Execute has_nulls: type:INT64 value:"0" has_values: type:INT64 value:"1" max: type:VARCHAR value:"c" min: type:VARCHAR value:"A" false`,
+	}, {
+		name:           "strings compared with a number",
+		sqResult:       sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1", "varchar"), "9", "10", "-2.5"),
+		comparisonType: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+		expected:       `Execute has_nulls: type:INT64 value:"0" has_values: type:INT64 value:"1" max: type:VARCHAR value:"10" min: type:VARCHAR value:"-2.5" false`,
+	}, {
+		name:           "numbers compared with a string",
+		sqResult:       sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1", "int64"), "9", "10", "3"),
+		comparisonType: evalengine.NewType(sqltypes.VarChar, collations.MySQL8().DefaultConnectionCharset()),
+		expected:       `Execute has_nulls: type:INT64 value:"0" has_values: type:INT64 value:"1" max: type:INT64 value:"10" min: type:INT64 value:"3" false`,
+	}, {
+		name:     "only nulls",
+		sqResult: sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1", "int64"), "null"),
+		expected: `Execute has_nulls: type:INT64 value:"1" has_values: type:INT64 value:"1" max:  min:  false`,
+	}, {
+		name:     "no rows",
+		sqResult: sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1", "int64")),
+		expected: `Execute has_nulls: type:INT64 value:"0" has_values: type:INT64 value:"0" max:  min:  false`,
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			sfp := &fakePrimitive{
+				results: []*sqltypes.Result{tc.sqResult},
+			}
+			ufp := &fakePrimitive{
+				results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("col", "int64"), "0")},
+			}
+			ps := &UncorrelatedSubquery{
+				Opcode:         PulloutMinMax,
+				SubqueryResult: "min",
+				MaxResult:      "max",
+				HasValues:      "has_values",
+				HasNulls:       "has_nulls",
+				ComparisonType: tc.comparisonType,
+				Subquery:       sfp,
+				Outer:          ufp,
+			}
+
+			_, err := ps.TryExecute(context.Background(), &noopVCursor{}, make(map[string]*querypb.BindVariable), false)
+			require.NoError(t, err)
+			sfp.ExpectLog(t, []string{`Execute  true`})
+			ufp.ExpectLog(t, []string{tc.expected})
+		})
+	}
+}
+
 func TestPulloutSubqueryError(t *testing.T) {
 	sfp := &fakePrimitive{
 		sendErr: errors.New("err"),
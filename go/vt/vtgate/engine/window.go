@@ -0,0 +1,594 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+// Window is a primitive that evaluates window functions over the rows of its input.
+// All rows are buffered in memory, unless they do not fit and can be spilled to disk, see
+// evaluatePartitions. The output rows contain one column per window function, followed by
+// all the columns produced by the input.
+type Window struct {
+	Functions []*WindowFunc
+	Input     Primitive
+}
+
+// WindowFunc describes a single window function evaluated by the Window primitive.
+type WindowFunc struct {
+	Opcode opcode.WindowOpcode
+
+	// Col is the offset of the argument of the function, -1 if the function has no argument.
+	Col int
+	// DefaultCol is the offset of the default value used by LAG and LEAD, -1 if not given.
+	DefaultCol int
+	// N is the bucket count for NTILE, the offset for LAG and LEAD, and the row number for NTH_VALUE.
+	N int64
+
+	// Aggregate is used when Opcode is WindowAggregate
+	Aggregate *AggregateParams
+
+	Partition evalengine.Comparison
+	OrderBy   evalengine.Comparison
+
+	// Frame is nil when the function uses the default frame.
+	Frame *WindowFrame
+
+	Alias string `json:",omitempty"`
+}
+
+// WindowFrame is the frame clause of a window specification.
+type WindowFrame struct {
+	Unit  sqlparser.FrameUnitType
+	Start WindowFrameBound
+	End   WindowFrameBound
+}
+
+// WindowFrameBound is the start or end of a window frame.
+type WindowFrameBound struct {
+	Type   sqlparser.FramePointType
+	Offset int64
+}
+
+// RouteType returns a description of the query routing type used by the primitive.
+func (w *Window) RouteType() string {
+	return w.Input.RouteType()
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (w *Window) GetKeyspaceName() string {
+	return w.Input.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (w *Window) GetTableName() string {
+	return w.Input.GetTableName()
+}
+
+// NeedsTransaction implements the Primitive interface
+func (w *Window) NeedsTransaction() bool {
+	return w.Input.NeedsTransaction()
+}
+
+// Inputs implements the Primitive interface
+func (w *Window) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{w.Input}, nil
+}
+
+// GetFields implements the Primitive interface
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: w.fields(qr.Fields)}, nil
+}
+
+// TryExecute implements the Primitive interface
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		return executeStreamed(ctx, vcursor, w, bindVars, true)
+	}
+
+	qr, err := vcursor.ExecutePrimitive(ctx, w.Input, bindVars, true)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := w.evaluate(qr.Fields, qr.Rows)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: w.fields(qr.Fields), Rows: rows}, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) (err error) {
+	defer evalengine.PanicHandler(&err)
+
+	var mu sync.Mutex
+	var fields []*querypb.Field
+	var rows []sqltypes.Row
+	// sorter is set once the rows do not fit in memory, it sorts them by partition on disk
+	var sorter *externalSorter
+	defer func() {
+		if sorter != nil {
+			sorter.close()
+		}
+	}()
+	var seq int64
+	err = vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, true, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(qr.Fields) != 0 && fields == nil {
+			fields = qr.Fields
+			if wantfields {
+				if err := callback(&sqltypes.Result{Fields: w.fields(fields)}); err != nil {
+					return err
+				}
+			}
+		}
+		if sorter != nil {
+			return sorter.push(withSequence(qr.Rows, &seq))
+		}
+		rows = append(rows, qr.Rows...)
+		if !vcursor.ExceedsMaxMemoryRows(len(rows)) {
+			return nil
+		}
+		if vcursor.SpillBudget() == nil || !w.partitioned() {
+			return errMaxMemoryRows(vcursor)
+		}
+		sorter = newExternalSorter(vcursor, w.partitionOrder(len(fields)), math.MaxInt)
+		err := sorter.push(withSequence(rows, &seq))
+		rows = nil
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if sorter != nil {
+		return w.evaluatePartitions(vcursor, fields, sorter, callback)
+	}
+	out, err := w.evaluate(fields, rows)
+	if err != nil {
+		return err
+	}
+	return callback(&sqltypes.Result{Rows: out})
+}
+
+// partitioned returns whether all the window functions are partitioned the same way. Their rows can then
+// be spilled to disk when they do not fit in memory, and evaluated one partition at a time.
+func (w *Window) partitioned() bool {
+	first := w.Functions[0].Partition
+	if len(first) == 0 {
+		return false
+	}
+	for _, f := range w.Functions[1:] {
+		if !slices.EqualFunc(first, f.Partition, func(a, b evalengine.OrderByParams) bool {
+			return a.Col == b.Col && a.Desc == b.Desc && a.Type.Collation() == b.Type.Collation()
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// partitionOrder sorts the rows by partition, and by their position in the input within a partition.
+// The position of the rows is stored in the column after the input columns.
+func (w *Window) partitionOrder(seqCol int) evalengine.Comparison {
+	return append(slices.Clone(w.Functions[0].Partition), evalengine.OrderByParams{
+		Col:             seqCol,
+		WeightStringCol: -1,
+		Type:            evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+	})
+}
+
+// withSequence appends the position of every row in the input to a copy of the row
+func withSequence(rows []sqltypes.Row, seq *int64) []sqltypes.Row {
+	out := make([]sqltypes.Row, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, append(row[:len(row):len(row)], sqltypes.NewInt64(*seq)))
+		*seq++
+	}
+	return out
+}
+
+// evaluatePartitions evaluates the window functions one partition at a time, reading the rows sorted by partition
+// from the sorter. The partitions come out in the same order as when all the rows are evaluated together, and the
+// rows of a partition keep their input order, so the output is the same as the one of evaluate. A single partition
+// must still fit in memory.
+func (w *Window) evaluatePartitions(vcursor VCursor, fields []*querypb.Field, sorter *externalSorter, callback func(*sqltypes.Result) error) error {
+	partition := w.Functions[0].Partition
+	var part []sqltypes.Row
+	flush := func() error {
+		if len(part) == 0 {
+			return nil
+		}
+		out, err := w.evaluate(fields, part)
+		if err != nil {
+			return err
+		}
+		part = nil
+		return callback(&sqltypes.Result{Rows: out})
+	}
+	err := sorter.send(func(rows []sqltypes.Row) error {
+		for _, row := range rows {
+			row = row[:len(row)-1]
+			if len(part) > 0 && partition.Compare(part[0], row) != 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			part = append(part, row)
+			if vcursor.ExceedsMaxMemoryRows(len(part)) {
+				return errMaxMemoryRows(vcursor)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+func (w *Window) fields(input []*querypb.Field) []*querypb.Field {
+	if input == nil {
+		return nil
+	}
+	out := make([]*querypb.Field, 0, len(w.Functions)+len(input))
+	for _, f := range w.Functions {
+		out = append(out, &querypb.Field{
+			Name: f.Alias,
+			Type: f.sqlType(input),
+		})
+	}
+	return append(out, input...)
+}
+
+func (f *WindowFunc) sqlType(input []*querypb.Field) querypb.Type {
+	if f.Opcode == opcode.WindowAggregate {
+		typ := sqltypes.Unknown
+		if f.Aggregate.Col < len(input) {
+			typ = input[f.Aggregate.Col].Type
+		}
+		return f.Aggregate.typ(typ)
+	}
+	typ := sqltypes.Unknown
+	if f.Col >= 0 && f.Col < len(input) {
+		typ = input[f.Col].Type
+	}
+	return f.Opcode.SQLType(typ)
+}
+
+// evaluate computes all the window functions and returns the output rows.
+// The rows are returned in the order used to evaluate the last window function.
+func (w *Window) evaluate(fields []*querypb.Field, rows []sqltypes.Row) (out []sqltypes.Row, err error) {
+	defer evalengine.PanicHandler(&err)
+
+	results := make([][]sqltypes.Value, len(rows))
+	for i := range results {
+		results[i] = make([]sqltypes.Value, len(w.Functions))
+	}
+
+	var order []int
+	for fIdx, f := range w.Functions {
+		order = f.sortedOrder(rows)
+		for _, part := range f.partitions(rows, order) {
+			if err := f.evaluatePartition(fields, rows, part, func(i int, v sqltypes.Value) {
+				results[i][fIdx] = v
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	out = make([]sqltypes.Row, 0, len(rows))
+	for _, i := range order {
+		out = append(out, append(results[i], rows[i]...))
+	}
+	return out, nil
+}
+
+// sortedOrder returns the row indexes sorted by the partition and then by the order of the window.
+func (f *WindowFunc) sortedOrder(rows []sqltypes.Row) []int {
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if cmp := f.Partition.Compare(rows[a], rows[b]); cmp != 0 {
+			return cmp
+		}
+		return f.OrderBy.Compare(rows[a], rows[b])
+	})
+	return order
+}
+
+// partitions splits the sorted row indexes into partitions
+func (f *WindowFunc) partitions(rows []sqltypes.Row, order []int) [][]int {
+	var parts [][]int
+	start := 0
+	for i := 1; i <= len(order); i++ {
+		if i == len(order) || f.Partition.Compare(rows[order[start]], rows[order[i]]) != 0 {
+			parts = append(parts, order[start:i])
+			start = i
+		}
+	}
+	return parts
+}
+
+// peers returns, for every position in the partition, the start and end of its peer group.
+// Rows are peers when they are equal according to the ORDER BY of the window.
+func (f *WindowFunc) peers(rows []sqltypes.Row, part []int) (starts, ends []int) {
+	starts = make([]int, len(part))
+	ends = make([]int, len(part))
+	start := 0
+	for i := 1; i <= len(part); i++ {
+		if i == len(part) || f.OrderBy.Compare(rows[part[start]], rows[part[i]]) != 0 {
+			for j := start; j < i; j++ {
+				starts[j] = start
+				ends[j] = i
+			}
+			start = i
+		}
+	}
+	return
+}
+
+func (f *WindowFunc) evaluatePartition(fields []*querypb.Field, rows []sqltypes.Row, part []int, set func(int, sqltypes.Value)) error {
+	size := len(part)
+	peerStart, peerEnd := f.peers(rows, part)
+
+	switch f.Opcode {
+	case opcode.WindowRowNumber:
+		for pos, i := range part {
+			set(i, sqltypes.NewUint64(uint64(pos+1)))
+		}
+	case opcode.WindowRank:
+		for pos, i := range part {
+			set(i, sqltypes.NewUint64(uint64(peerStart[pos]+1)))
+		}
+	case opcode.WindowDenseRank:
+		var rank uint64
+		for pos, i := range part {
+			if pos == peerStart[pos] {
+				rank++
+			}
+			set(i, sqltypes.NewUint64(rank))
+		}
+	case opcode.WindowPercentRank:
+		for pos, i := range part {
+			var v float64
+			if size > 1 {
+				v = float64(peerStart[pos]) / float64(size-1)
+			}
+			set(i, sqltypes.NewFloat64(v))
+		}
+	case opcode.WindowCumeDist:
+		for pos, i := range part {
+			set(i, sqltypes.NewFloat64(float64(peerEnd[pos])/float64(size)))
+		}
+	case opcode.WindowNtile:
+		// the first size % N buckets get one more row than the rest
+		buckets := int(f.N)
+		small, extra := size/buckets, size%buckets
+		for pos, i := range part {
+			var bucket int
+			if pos < extra*(small+1) {
+				bucket = pos / (small + 1)
+			} else {
+				bucket = extra + (pos-extra*(small+1))/small
+			}
+			set(i, sqltypes.NewUint64(uint64(bucket+1)))
+		}
+	case opcode.WindowLag, opcode.WindowLead:
+		offset := int(f.N)
+		if f.Opcode == opcode.WindowLag {
+			offset = -offset
+		}
+		for pos, i := range part {
+			switch target := pos + offset; {
+			case target >= 0 && target < size:
+				set(i, rows[part[target]][f.Col])
+			case f.DefaultCol >= 0:
+				set(i, rows[i][f.DefaultCol])
+			default:
+				set(i, sqltypes.NULL)
+			}
+		}
+	case opcode.WindowFirstValue, opcode.WindowLastValue, opcode.WindowNthValue:
+		for pos, i := range part {
+			start, end := f.frame(pos, size, peerStart, peerEnd)
+			target := -1
+			switch f.Opcode {
+			case opcode.WindowFirstValue:
+				target = start
+			case opcode.WindowLastValue:
+				target = end - 1
+			case opcode.WindowNthValue:
+				target = start + int(f.N) - 1
+			}
+			if target >= start && target < end {
+				set(i, rows[part[target]][f.Col])
+			} else {
+				set(i, sqltypes.NULL)
+			}
+		}
+	case opcode.WindowAggregate:
+		agg, err := newWindowAggregator(fields, f.Aggregate)
+		if err != nil {
+			return err
+		}
+		prevStart, prevEnd := 0, 0
+		for pos, i := range part {
+			start, end := f.frame(pos, size, peerStart, peerEnd)
+			from := prevEnd
+			if start != prevStart || end < prevEnd {
+				// the frame moved, so we have to start over
+				agg.reset()
+				from = start
+			}
+			for j := from; j < end; j++ {
+				if err := agg.add(rows[part[j]]); err != nil {
+					return err
+				}
+			}
+			prevStart, prevEnd = start, end
+			set(i, agg.finish())
+		}
+	default:
+		return fmt.Errorf("BUG: unexpected window function %s", f.Opcode.String())
+	}
+	return nil
+}
+
+// frame returns the [start, end) positions inside the partition that make up the
+// frame of the row at position pos.
+func (f *WindowFunc) frame(pos, size int, peerStart, peerEnd []int) (start, end int) {
+	if f.Frame == nil {
+		if len(f.OrderBy) == 0 {
+			// without ORDER BY, all rows in the partition are peers
+			return 0, size
+		}
+		// RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+		return 0, peerEnd[pos]
+	}
+
+	bound := func(b WindowFrameBound, isStart bool) int {
+		switch b.Type {
+		case sqlparser.UnboundedPrecedingType:
+			return 0
+		case sqlparser.UnboundedFollowingType:
+			return size
+		case sqlparser.CurrentRowType:
+			if f.Frame.Unit == sqlparser.FrameRangeType {
+				if isStart {
+					return peerStart[pos]
+				}
+				return peerEnd[pos]
+			}
+			if isStart {
+				return pos
+			}
+			return pos + 1
+		case sqlparser.ExprPrecedingType:
+			if isStart {
+				return pos - int(b.Offset)
+			}
+			return pos - int(b.Offset) + 1
+		case sqlparser.ExprFollowingType:
+			if isStart {
+				return pos + int(b.Offset)
+			}
+			return pos + int(b.Offset) + 1
+		}
+		return 0
+	}
+
+	start = min(max(bound(f.Frame.Start, true), 0), size)
+	end = min(max(bound(f.Frame.End, false), 0), size)
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+func newWindowAggregator(fields []*querypb.Field, aggr *AggregateParams) (aggregator, error) {
+	if aggr.Opcode == opcode.AggregateCountStar {
+		return &aggregatorCountStar{}, nil
+	}
+	state, _, err := newAggregation(fields, []*AggregateParams{aggr}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return state[aggr.Col], nil
+}
+
+func (f *WindowFunc) String() string {
+	var fn string
+	switch f.Opcode {
+	case opcode.WindowAggregate:
+		fn = fmt.Sprintf("%s(%d)", f.Aggregate.Opcode.String(), f.Aggregate.Col)
+		if f.Aggregate.Opcode == opcode.AggregateCountStar {
+			fn = "count(*)"
+		}
+	case opcode.WindowNtile:
+		fn = fmt.Sprintf("ntile(%d)", f.N)
+	case opcode.WindowLag, opcode.WindowLead:
+		fn = fmt.Sprintf("%s(%d, %d)", f.Opcode.String(), f.Col, f.N)
+		if f.DefaultCol >= 0 {
+			fn = fmt.Sprintf("%s(%d, %d, %d)", f.Opcode.String(), f.Col, f.N, f.DefaultCol)
+		}
+	case opcode.WindowNthValue:
+		fn = fmt.Sprintf("nth_value(%d, %d)", f.Col, f.N)
+	case opcode.WindowFirstValue, opcode.WindowLastValue:
+		fn = fmt.Sprintf("%s(%d)", f.Opcode.String(), f.Col)
+	default:
+		fn = f.Opcode.String() + "()"
+	}
+
+	var spec []string
+	if len(f.Partition) > 0 {
+		spec = append(spec, "partition by "+GenericJoin(f.Partition, orderByParamsToString))
+	}
+	if len(f.OrderBy) > 0 {
+		spec = append(spec, "order by "+GenericJoin(f.OrderBy, orderByParamsToString))
+	}
+	if f.Frame != nil {
+		spec = append(spec, f.Frame.String())
+	}
+	return fmt.Sprintf("%s over (%s)", fn, strings.Join(spec, " "))
+}
+
+func (wf *WindowFrame) String() string {
+	bound := func(b WindowFrameBound) string {
+		switch b.Type {
+		case sqlparser.ExprPrecedingType, sqlparser.ExprFollowingType:
+			return fmt.Sprintf("%d %s", b.Offset, b.Type.ToString())
+		}
+		return b.Type.ToString()
+	}
+	return fmt.Sprintf("%s between %s and %s", strings.ToLower(wf.Unit.ToString()), strings.ToLower(bound(wf.Start)), strings.ToLower(bound(wf.End)))
+}
+
+func windowFuncToString(i any) string {
+	return i.(*WindowFunc).String()
+}
+
+func (w *Window) description() PrimitiveDescription {
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other: map[string]any{
+			"Functions": GenericJoin(w.Functions, windowFuncToString),
+		},
+	}
+}
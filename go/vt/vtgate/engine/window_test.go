@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/test/utils"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+)
+
+func windowTestInput() *fakePrimitive {
+	return &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("dept|salary", "int64|int64"),
+			"2|20",
+			"1|10",
+			"1|30",
+			"2|20",
+			"1|20",
+		)},
+	}
+}
+
+var (
+	byDept   = evalengine.Comparison{{Col: 0, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)}}
+	bySalary = evalengine.Comparison{{Col: 1, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)}}
+)
+
+func TestWindowRanking(t *testing.T) {
+	w := &Window{
+		Functions: []*WindowFunc{
+			{Opcode: opcode.WindowRowNumber, Col: -1, DefaultCol: -1, Partition: byDept, OrderBy: bySalary, Alias: "row_number()"},
+			{Opcode: opcode.WindowRank, Col: -1, DefaultCol: -1, Partition: byDept, OrderBy: bySalary, Alias: "rank()"},
+			{Opcode: opcode.WindowDenseRank, Col: -1, DefaultCol: -1, OrderBy: bySalary, Alias: "dense_rank()"},
+			{Opcode: opcode.WindowNtile, Col: -1, DefaultCol: -1, N: 2, Partition: byDept, OrderBy: bySalary, Alias: "ntile(2)"},
+		},
+		Input: windowTestInput(),
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"row_number()|rank()|dense_rank()|ntile(2)|dept|salary",
+			"uint64|uint64|uint64|uint64|int64|int64",
+		),
+		"1|1|1|1|1|10",
+		"2|2|2|1|1|20",
+		"3|3|3|2|1|30",
+		"1|1|2|1|2|20",
+		"2|1|2|2|2|20",
+	)
+	utils.MustMatch(t, want, result)
+}
+
+func TestWindowValueFunctions(t *testing.T) {
+	w := &Window{
+		Functions: []*WindowFunc{
+			{Opcode: opcode.WindowLag, Col: 1, DefaultCol: -1, N: 1, Partition: byDept, OrderBy: bySalary, Alias: "lag"},
+			{Opcode: opcode.WindowLead, Col: 1, DefaultCol: 0, N: 1, Partition: byDept, OrderBy: bySalary, Alias: "lead"},
+			{Opcode: opcode.WindowFirstValue, Col: 1, DefaultCol: -1, Partition: byDept, OrderBy: bySalary, Alias: "first"},
+			{Opcode: opcode.WindowLastValue, Col: 1, DefaultCol: -1, Partition: byDept, OrderBy: bySalary, Alias: "last"},
+		},
+		Input: windowTestInput(),
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"lag|lead|first|last|dept|salary",
+			"int64|int64|int64|int64|int64|int64",
+		),
+		"null|20|10|10|1|10",
+		"10|30|10|20|1|20",
+		"20|1|10|30|1|30",
+		"null|20|20|20|2|20",
+		"20|2|20|20|2|20",
+	)
+	utils.MustMatch(t, want, result)
+}
+
+func TestWindowAggregateFrames(t *testing.T) {
+	sum := NewAggregateParam(opcode.AggregateSum, 1, "sum", collations.MySQL8())
+	w := &Window{
+		Functions: []*WindowFunc{{
+			Opcode:     opcode.WindowAggregate,
+			Col:        -1,
+			DefaultCol: -1,
+			Aggregate:  sum,
+			OrderBy:    bySalary,
+			Alias:      "running",
+		}, {
+			Opcode:     opcode.WindowAggregate,
+			Col:        -1,
+			DefaultCol: -1,
+			Aggregate:  NewAggregateParam(opcode.AggregateCountStar, 0, "cnt", collations.MySQL8()),
+			Partition:  byDept,
+			Alias:      "cnt",
+		}, {
+			Opcode:     opcode.WindowAggregate,
+			Col:        -1,
+			DefaultCol: -1,
+			Aggregate:  NewAggregateParam(opcode.AggregateMax, 1, "moving", collations.MySQL8()),
+			OrderBy:    bySalary,
+			Frame: &WindowFrame{
+				Unit:  sqlparser.FrameRowsType,
+				Start: WindowFrameBound{Type: sqlparser.ExprPrecedingType, Offset: 1},
+				End:   WindowFrameBound{Type: sqlparser.CurrentRowType},
+			},
+			Alias: "moving",
+		}},
+		Input: windowTestInput(),
+	}
+
+	result, err := wrapStreamExecute(w, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"running|cnt|moving|dept|salary",
+			"decimal|int64|int64|int64|int64",
+		),
+		"10|3|10|1|10",
+		"70|2|20|2|20",
+		"70|2|20|2|20",
+		"70|3|20|1|20",
+		"100|3|30|1|30",
+	)
+	utils.MustMatch(t, want, result)
+}
+
+func TestWindowSpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	testMaxMemoryRows = 7
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillBudget = nil
+	}()
+
+	var rows []string
+	for i := 0; i < 40; i++ {
+		rows = append(rows, fmt.Sprintf("%d|%d", i*3%10, i%9))
+	}
+	newWindow := func(partition evalengine.Comparison) *Window {
+		return &Window{
+			Functions: []*WindowFunc{
+				{Opcode: opcode.WindowRowNumber, Col: -1, DefaultCol: -1, Partition: byDept, OrderBy: bySalary, Alias: "row_number()"},
+				{Opcode: opcode.WindowLag, Col: 1, DefaultCol: -1, N: 1, Partition: byDept, OrderBy: bySalary, Alias: "lag"},
+				{
+					Opcode:     opcode.WindowAggregate,
+					Col:        -1,
+					DefaultCol: -1,
+					Aggregate:  NewAggregateParam(opcode.AggregateSum, 1, "sum", collations.MySQL8()),
+					Partition:  partition,
+					Alias:      "sum",
+				},
+			},
+			Input: &fakePrimitive{results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(sqltypes.MakeTestFields("dept|salary", "int64|int64"), rows...),
+			}},
+		}
+	}
+
+	testSpillBudget = nil
+	want, err := newWindow(byDept).TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	require.Len(t, want.Rows, 40)
+
+	testSpillBudget = NewDiskBudget(t.TempDir(), 0, nil)
+	before := spilledBytes.Get()
+	got, err := wrapStreamExecute(newWindow(byDept), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, got)
+	require.Greater(t, spilledBytes.Get(), before)
+	require.Zero(t, testSpillBudget.Used())
+
+	before = spilledBytes.Get()
+	got, err = newWindow(byDept).TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, got)
+	require.Greater(t, spilledBytes.Get(), before)
+
+	// the rows can only be evaluated one partition at a time if all the functions use the same partitions
+	_, err = wrapStreamExecute(newWindow(nil), &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "in-memory row count exceeded allowed limit of 7")
+}
+
+func TestWindowDescription(t *testing.T) {
+	w := &Window{
+		Functions: []*WindowFunc{{
+			Opcode:     opcode.WindowLag,
+			Col:        1,
+			DefaultCol: -1,
+			N:          2,
+			Partition:  byDept,
+			OrderBy:    bySalary,
+			Frame: &WindowFrame{
+				Unit:  sqlparser.FrameRowsType,
+				Start: WindowFrameBound{Type: sqlparser.UnboundedPrecedingType},
+				End:   WindowFrameBound{Type: sqlparser.ExprFollowingType, Offset: 3},
+			},
+		}},
+	}
+	require.Equal(t, "lag(1, 2) over (partition by 0 ASC order by 1 ASC rows between unbounded preceding and 3 following)", w.Functions[0].String())
+}
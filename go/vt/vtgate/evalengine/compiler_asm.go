@@ -44,6 +44,7 @@ import (
 	"github.com/mdibaiee/vitess/go/mysql/datetime"
 	"github.com/mdibaiee/vitess/go/mysql/decimal"
 	"github.com/mdibaiee/vitess/go/mysql/fastparse"
+	"github.com/mdibaiee/vitess/go/mysql/geometry"
 	"github.com/mdibaiee/vitess/go/mysql/hex"
 	"github.com/mdibaiee/vitess/go/mysql/icuregex"
 	"github.com/mdibaiee/vitess/go/mysql/json"
@@ -2168,6 +2169,15 @@ func (asm *assembler) Fn_JSON_ARRAY(args int) {
 	}, "FN JSON_ARRAY (SP-%d)...(SP-1)", args)
 }
 
+func (asm *assembler) Fn_JSON_CONTAINS(args int) {
+	asm.adjustStack(-(args - 1))
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = builtin_JSON_CONTAINS(env.vm.stack[env.vm.sp-args : env.vm.sp])
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN JSON_CONTAINS (SP-%d)...(SP-1)", args)
+}
+
 func (asm *assembler) Fn_JSON_CONTAINS_PATH(match jsonMatch, paths []*json.Path) {
 	switch match {
 	case jsonMatchOne:
@@ -2279,6 +2289,43 @@ func (asm *assembler) Fn_JSON_KEYS(jp *json.Path) {
 	}
 }
 
+func (asm *assembler) Fn_JSON_MEMBER_OF() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		value := env.vm.stack[env.vm.sp-2].(*evalJSON)
+		doc := env.vm.stack[env.vm.sp-1].(*evalJSON)
+		var member bool
+		member, env.vm.err = jsonMemberOf(value, doc)
+		env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalBool(member)
+		env.vm.sp--
+		return 1
+	}, "FN JSON_MEMBER_OF (SP-2), (SP-1)")
+}
+
+func (asm *assembler) Fn_JSON_MERGE(method string, patch bool, args int) {
+	asm.adjustStack(-(args - 1))
+	asm.emit(func(env *ExpressionEnv) int {
+		docs := make([]*evalJSON, 0, args)
+		for sp := env.vm.sp - args; sp < env.vm.sp; sp++ {
+			doc, _ := env.vm.stack[sp].(*evalJSON)
+			docs = append(docs, doc)
+		}
+		env.vm.stack[env.vm.sp-args] = builtin_JSON_MERGE(patch, docs)
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN %s (SP-%d)...(SP-1)", method, args)
+}
+
+func (asm *assembler) Fn_JSON_MODIFY(method string, t json.Transformation, args int) {
+	asm.adjustStack(-(args - 1))
+	asm.emit(func(env *ExpressionEnv) int {
+		doc := env.vm.stack[env.vm.sp-args].(*evalJSON)
+		env.vm.stack[env.vm.sp-args], env.vm.err = builtin_JSON_MODIFY(t, doc, env.vm.stack[env.vm.sp-args+1:env.vm.sp])
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN %s (SP-%d)...(SP-1)", method, args)
+}
+
 func (asm *assembler) Fn_JSON_OBJECT(args int) {
 	asm.adjustStack(-(args - 1))
 	asm.emit(func(env *ExpressionEnv) int {
@@ -2300,6 +2347,40 @@ func (asm *assembler) Fn_JSON_OBJECT(args int) {
 	}, "FN JSON_ARRAY (SP-%d)...(SP-1)", args)
 }
 
+func (asm *assembler) Fn_JSON_OVERLAPS() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		a := env.vm.stack[env.vm.sp-2].(*evalJSON)
+		b := env.vm.stack[env.vm.sp-1].(*evalJSON)
+		var overlaps bool
+		overlaps, env.vm.err = jsonOverlaps(a, b)
+		env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalBool(overlaps)
+		env.vm.sp--
+		return 1
+	}, "FN JSON_OVERLAPS (SP-2), (SP-1)")
+}
+
+func (asm *assembler) Fn_JSON_PRETTY() {
+	asm.emit(func(env *ExpressionEnv) int {
+		doc := env.vm.stack[env.vm.sp-1].(*evalJSON)
+		b := env.vm.arena.newEvalBytesEmpty()
+		b.tt = int16(sqltypes.Blob)
+		b.col = collationJSON
+		b.bytes = doc.MarshalPretty(nil)
+		env.vm.stack[env.vm.sp-1] = b
+		return 1
+	}, "FN JSON_PRETTY (SP-1)")
+}
+
+func (asm *assembler) Fn_JSON_SEARCH(args int) {
+	asm.adjustStack(-(args - 1))
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = builtin_JSON_SEARCH(env.vm.stack[env.vm.sp-args : env.vm.sp])
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN JSON_SEARCH (SP-%d)...(SP-1)", args)
+}
+
 func (asm *assembler) Fn_JSON_UNQUOTE() {
 	asm.emit(func(env *ExpressionEnv) int {
 		j := env.vm.stack[env.vm.sp-1].(*evalJSON)
@@ -2316,6 +2397,16 @@ func (asm *assembler) Fn_JSON_UNQUOTE() {
 	}, "FN JSON_UNQUOTE (SP-1)")
 }
 
+func (asm *assembler) Fn_JSON_VALUE() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		doc := env.vm.stack[env.vm.sp-2].(*evalJSON)
+		env.vm.stack[env.vm.sp-2], env.vm.err = builtin_JSON_VALUE(doc, env.vm.stack[env.vm.sp-1])
+		env.vm.sp--
+		return 1
+	}, "FN JSON_VALUE (SP-2), (SP-1)")
+}
+
 func (asm *assembler) Fn_CHAR_LENGTH() {
 	asm.emit(func(env *ExpressionEnv) int {
 		arg := env.vm.stack[env.vm.sp-1].(*evalBytes)
@@ -5076,3 +5167,90 @@ func (asm *assembler) Introduce(offset int, t sqltypes.Type, col collations.Type
 		return 1
 	}, "INTRODUCE (SP-1)")
 }
+
+func (asm *assembler) Fn_ST_GEOMFROM(srid, wkb bool, typ geometry.Type, fname string) {
+	if srid {
+		asm.adjustStack(-1)
+		asm.emit(func(env *ExpressionEnv) int {
+			raw := env.vm.stack[env.vm.sp-2]
+			srid := env.vm.stack[env.vm.sp-1].(*evalInt64)
+			env.vm.stack[env.vm.sp-2], env.vm.err = geomFrom(raw.ToRawBytes(), srid.i, wkb, typ, fname)
+			env.vm.sp--
+			return 1
+		}, "FN %s (SP-2), INT64(SP-1)", fname)
+	} else {
+		asm.emit(func(env *ExpressionEnv) int {
+			raw := env.vm.stack[env.vm.sp-1]
+			env.vm.stack[env.vm.sp-1], env.vm.err = geomFrom(raw.ToRawBytes(), 0, wkb, typ, fname)
+			return 1
+		}, "FN %s (SP-1)", fname)
+	}
+}
+
+func (asm *assembler) Fn_POINT() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		x := env.vm.stack[env.vm.sp-2].(*evalFloat)
+		y := env.vm.stack[env.vm.sp-1].(*evalFloat)
+		env.vm.stack[env.vm.sp-2] = newEvalGeometry(geometry.NewPoint(x.f, y.f))
+		env.vm.sp--
+		return 1
+	}, "FN POINT FLOAT64(SP-2), FLOAT64(SP-1)")
+}
+
+func (asm *assembler) Fn_ST_AS(wkb bool, col collations.TypedCollation, fname string) {
+	asm.emit(func(env *ExpressionEnv) int {
+		var g *geometry.Geometry
+		g, env.vm.err = evalToGeometry(env.vm.stack[env.vm.sp-1], fname)
+		if env.vm.err == nil {
+			env.vm.stack[env.vm.sp-1] = geomFormat(g, wkb, col)
+		}
+		return 1
+	}, "FN %s GEOMETRY(SP-1)", fname)
+}
+
+func (asm *assembler) Fn_ST_PROPERTY(prop geomProperty, col collations.TypedCollation, fname string) {
+	asm.emit(func(env *ExpressionEnv) int {
+		var g *geometry.Geometry
+		g, env.vm.err = evalToGeometry(env.vm.stack[env.vm.sp-1], fname)
+		if env.vm.err == nil {
+			env.vm.stack[env.vm.sp-1] = geomPropertyValue(g, prop, col)
+		}
+		return 1
+	}, "FN %s GEOMETRY(SP-1)", fname)
+}
+
+func (asm *assembler) Fn_ST_COORD(y, set bool, fname string) {
+	if set {
+		asm.adjustStack(-1)
+		asm.emit(func(env *ExpressionEnv) int {
+			val := env.vm.stack[env.vm.sp-1].(*evalFloat)
+			env.vm.stack[env.vm.sp-2], env.vm.err = pointCoord(env.vm.stack[env.vm.sp-2], y, val, fname)
+			env.vm.sp--
+			return 1
+		}, "FN %s GEOMETRY(SP-2), FLOAT64(SP-1)", fname)
+	} else {
+		asm.emit(func(env *ExpressionEnv) int {
+			env.vm.stack[env.vm.sp-1], env.vm.err = pointCoord(env.vm.stack[env.vm.sp-1], y, nil, fname)
+			return 1
+		}, "FN %s GEOMETRY(SP-1)", fname)
+	}
+}
+
+func (asm *assembler) Fn_ST_DISTANCE(fname string) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2], env.vm.err = stDistance(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], fname)
+		env.vm.sp--
+		return 1
+	}, "FN ST_DISTANCE GEOMETRY(SP-2), GEOMETRY(SP-1)")
+}
+
+func (asm *assembler) Fn_ST_CONTAINS(within bool, fname string) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2], env.vm.err = stContains(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], within, fname)
+		env.vm.sp--
+		return 1
+	}, "FN %s GEOMETRY(SP-2), GEOMETRY(SP-1)", fname)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/mdibaiee/vitess/go/mysql/decimal"
 	"github.com/mdibaiee/vitess/go/mysql/fastparse"
 	"github.com/mdibaiee/vitess/go/mysql/json"
+	"github.com/mdibaiee/vitess/go/sqltypes"
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	"github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
@@ -97,6 +98,37 @@ func (asm *assembler) PushBVar_bin(key string) {
 	}, "PUSH VARBINARY(:%q)", key)
 }
 
+func push_geometry(env *ExpressionEnv, raw []byte) int {
+	env.vm.stack[env.vm.sp] = newEvalRaw(sqltypes.Geometry, raw, collationBinary)
+	env.vm.sp++
+	return 1
+}
+
+func (asm *assembler) PushColumn_geometry(offset int) {
+	asm.adjustStack(1)
+
+	asm.emit(func(env *ExpressionEnv) int {
+		col := env.Row[offset]
+		if col.IsNull() {
+			return push_null(env)
+		}
+		return push_geometry(env, col.Raw())
+	}, "PUSH GEOMETRY(:%d)", offset)
+}
+
+func (asm *assembler) PushBVar_geometry(key string) {
+	asm.adjustStack(1)
+
+	asm.emit(func(env *ExpressionEnv) int {
+		var bvar *querypb.BindVariable
+		bvar, env.vm.err = env.lookupBindVar(key)
+		if env.vm.err != nil {
+			return 0
+		}
+		return push_geometry(env, bvar.Value)
+	}, "PUSH GEOMETRY(:%q)", key)
+}
+
 func push_d(env *ExpressionEnv, raw []byte) int {
 	var dec decimal.Decimal
 	dec, env.vm.err = decimal.NewFromMySQL(raw)
@@ -723,6 +723,55 @@ func TestCompilerSingle(t *testing.T) {
 			expression: `cast(_utf32 0x0000FF as binary)`,
 			result:     `VARBINARY("\x00\x00\x00\xff")`,
 		},
+		{
+			expression: `ST_AsText(column0)`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Geometry, []byte("\x00\x00\x00\x00\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0?\x00\x00\x00\x00\x00\x00\x00@"))},
+			result:     `VARCHAR("POINT(1 2)")`,
+		},
+		{
+			expression: `ST_Distance(column0, ST_GeomFromText('LINESTRING(4 -10, 4 10)'))`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Geometry, []byte("\x00\x00\x00\x00\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0?\x00\x00\x00\x00\x00\x00\x00@"))},
+			result:     `FLOAT64(3)`,
+		},
+		{
+			expression: `ST_Within(column0, ST_GeomFromText('POLYGON((0 0, 10 0, 10 10, 0 10, 0 0))'))`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Geometry, []byte("\x00\x00\x00\x00\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0?\x00\x00\x00\x00\x00\x00\x00@"))},
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `ST_Y(column0)`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Geometry, []byte("\x00\x00\x00\x00\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0?\x00\x00\x00\x00\x00\x00\x00@"))},
+			result:     `FLOAT64(2)`,
+		},
+		{
+			expression: `POINT(1, 2)`,
+			result:     `GEOMETRY("\x00\x00\x00\x00\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0?\x00\x00\x00\x00\x00\x00\x00@")`,
+			typeWanted: evalengine.NewTypeEx(sqltypes.Geometry, collations.CollationBinaryID, false, 0, 0, nil),
+		},
+		{
+			expression: `ST_GeometryType(ST_GeomFromText('MULTIPOINT(0 0, 1 1)'))`,
+			result:     `VARCHAR("MULTIPOINT")`,
+		},
+		{
+			expression: `JSON_SET(column0, '$.b', column1)`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"a": 1}`)), sqltypes.NULL},
+			result:     `JSON("{\"a\": 1, \"b\": null}")`,
+		},
+		{
+			expression: `JSON_SET(column0, column1, 2)`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"a": 1}`)), sqltypes.NULL},
+			result:     `NULL`,
+		},
+		{
+			expression: `column0 MEMBER OF ('[1, 2]')`,
+			values:     []sqltypes.Value{sqltypes.NewInt64(2)},
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `JSON_VALUE(column0, '$.a')`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"a": "x"}`))},
+			result:     `VARCHAR("x")`,
+		},
 	}
 
 	tz, _ := time.LoadLocation("Europe/Madrid")
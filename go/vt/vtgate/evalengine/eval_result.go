@@ -22,6 +22,7 @@ import (
 	"github.com/mdibaiee/vitess/go/mysql/collations"
 	"github.com/mdibaiee/vitess/go/mysql/collations/charset"
 	"github.com/mdibaiee/vitess/go/mysql/collations/colldata"
+	"github.com/mdibaiee/vitess/go/mysql/json"
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
@@ -60,6 +61,16 @@ func (er EvalResult) String() string {
 	return er.Value(er.collationEnv.DefaultConnectionCharset()).String()
 }
 
+// JSON returns the result as a JSON document. Strings are parsed as JSON text.
+// It returns nil for a NULL result. fn is the name of the function that receives
+// the document, and it is used in the error returned for values that are not JSON.
+func (er EvalResult) JSON(fn string) (*json.Value, error) {
+	if er.v == nil {
+		return nil, nil
+	}
+	return intoJSON(fn, er.v)
+}
+
 // TupleValues allows for retrieval of the value we expose for public consumption
 func (er EvalResult) TupleValues() []sqltypes.Value {
 	switch v := er.v.(type) {
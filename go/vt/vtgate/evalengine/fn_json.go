@@ -17,7 +17,12 @@ limitations under the License.
 package evalengine
 
 import (
+	"bytes"
+	"slices"
+	"unicode/utf8"
+
 	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/mysql/collations/colldata"
 	"github.com/mdibaiee/vitess/go/mysql/json"
 	"github.com/mdibaiee/vitess/go/slice"
 	"github.com/mdibaiee/vitess/go/sqltypes"
@@ -57,6 +62,40 @@ type (
 	builtinJSONKeys struct {
 		CallExpr
 	}
+
+	builtinJSONModify struct {
+		CallExpr
+		Transformation json.Transformation
+	}
+
+	builtinJSONMerge struct {
+		CallExpr
+		Patch bool
+	}
+
+	builtinJSONContains struct {
+		CallExpr
+	}
+
+	builtinJSONOverlaps struct {
+		CallExpr
+	}
+
+	builtinJSONMemberOf struct {
+		CallExpr
+	}
+
+	builtinJSONSearch struct {
+		CallExpr
+	}
+
+	builtinJSONValue struct {
+		CallExpr
+	}
+
+	builtinJSONPretty struct {
+		CallExpr
+	}
 )
 
 var _ IR = (*builtinJSONExtract)(nil)
@@ -67,8 +106,17 @@ var _ IR = (*builtinJSONDepth)(nil)
 var _ IR = (*builtinJSONLength)(nil)
 var _ IR = (*builtinJSONContainsPath)(nil)
 var _ IR = (*builtinJSONKeys)(nil)
+var _ IR = (*builtinJSONModify)(nil)
+var _ IR = (*builtinJSONMerge)(nil)
+var _ IR = (*builtinJSONContains)(nil)
+var _ IR = (*builtinJSONOverlaps)(nil)
+var _ IR = (*builtinJSONMemberOf)(nil)
+var _ IR = (*builtinJSONSearch)(nil)
+var _ IR = (*builtinJSONValue)(nil)
+var _ IR = (*builtinJSONPretty)(nil)
 
 var errInvalidPathForTransform = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "In this situation, path expressions may not contain the * and ** tokens or an array range.")
+var errJSONVacuousPath = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "The path expression '$' is not allowed in this context.")
 
 func (call *builtinJSONExtract) eval(env *ExpressionEnv) (eval, error) {
 	args, err := call.args(env)
@@ -511,3 +559,662 @@ func (call *builtinJSONKeys) compile(c *compiler) (ctype, error) {
 	c.asm.Fn_JSON_KEYS(jp)
 	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
 }
+
+// compileJSONDoc parses the JSON document at the top of the stack, which must have
+// been checked for NULL already
+func (c *compiler) compileJSONDoc(fn string, doct ctype) error {
+	if doct.Type == sqltypes.Null {
+		return nil
+	}
+	_, err := c.compileParseJSON(fn, doct, 1)
+	return err
+}
+
+// compileJSONValue converts the value at the top of the stack into JSON; NULL values
+// are left as they are, and they become JSON nulls when the function is evaluated
+func (c *compiler) compileJSONValue(ct ctype) error {
+	skip := c.compileNullCheck1(ct)
+	_, err := c.compileArgToJSON(ct, 1)
+	c.asm.jumpDestination(skip)
+	return err
+}
+
+func (call *builtinJSONModify) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+	doc, err := intoJSON(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return builtin_JSON_MODIFY(call.Transformation, doc, args[1:])
+}
+
+// builtin_JSON_MODIFY implements JSON_SET, JSON_INSERT and JSON_REPLACE, whose arguments
+// are pairs of paths and values, and JSON_REMOVE, whose arguments are only paths
+func builtin_JSON_MODIFY(t json.Transformation, doc *json.Value, args []eval) (eval, error) {
+	step := 2
+	if t == json.Remove {
+		step = 1
+	}
+	for i := 0; i < len(args); i += step {
+		if args[i] == nil {
+			return nil, nil
+		}
+	}
+
+	paths := make([]*json.Path, 0, len(args)/step)
+	values := make([]*json.Value, 0, len(args)/step)
+	for i := 0; i < len(args); i += step {
+		path, err := intoJSONPath(args[i])
+		if err != nil {
+			return nil, err
+		}
+		if path.ContainsWildcards() {
+			return nil, errInvalidPathForTransform
+		}
+		if t == json.Remove && path.IsRoot() {
+			return nil, errJSONVacuousPath
+		}
+		paths = append(paths, path)
+
+		if t != json.Remove {
+			value, err := argToJSON(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value.Clone())
+		}
+	}
+	return json.ApplyTransform(t, doc.Clone(), paths, values), nil
+}
+
+func (call *builtinJSONModify) compile(c *compiler) (ctype, error) {
+	doct, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	skips := []*jump{c.compileNullCheckArg(doct, 0)}
+	if err := c.compileJSONDoc(call.Method, doct); err != nil {
+		return ctype{}, err
+	}
+
+	for i, arg := range call.Arguments[1:] {
+		ct, err := arg.compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+		if call.Transformation != json.Remove && i%2 == 1 {
+			if err := c.compileJSONValue(ct); err != nil {
+				return ctype{}, err
+			}
+			continue
+		}
+		skips = append(skips, c.compileNullCheckArg(ct, i+1))
+	}
+
+	c.asm.Fn_JSON_MODIFY(call.Method, call.Transformation, len(call.Arguments))
+	c.asm.jumpDestination(skips...)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+func (call *builtinJSONMerge) eval(env *ExpressionEnv) (eval, error) {
+	docs := make([]*json.Value, 0, len(call.Arguments))
+	for _, arg := range call.Arguments {
+		arg, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if arg == nil {
+			if !call.Patch {
+				return nil, nil
+			}
+			docs = append(docs, nil)
+			continue
+		}
+		doc, err := intoJSON(call.Method, arg)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return builtin_JSON_MERGE(call.Patch, docs), nil
+}
+
+// builtin_JSON_MERGE merges all the documents from left to right. A NULL document makes
+// the result of JSON_MERGE_PATCH NULL, unless it is followed by a document that is not an
+// object, since such a patch replaces the whole document.
+func builtin_JSON_MERGE(patch bool, docs []*json.Value) eval {
+	var merged *json.Value
+	var null bool
+	for i, doc := range docs {
+		switch {
+		case doc == nil:
+			null = true
+		case i == 0:
+			merged = doc.Clone()
+		case patch && doc.Type() != json.TypeObject:
+			merged, null = doc.Clone(), false
+		case null:
+		case patch:
+			merged = json.MergePatch(merged, doc.Clone())
+		default:
+			merged = json.MergePreserve(merged, doc.Clone())
+		}
+	}
+	if null {
+		return nil
+	}
+	return merged
+}
+
+func (call *builtinJSONMerge) compile(c *compiler) (ctype, error) {
+	var skips []*jump
+	for i, arg := range call.Arguments {
+		ct, err := arg.compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+		if call.Patch {
+			skip := c.compileNullCheck1(ct)
+			if err := c.compileJSONDoc(call.Method, ct); err != nil {
+				return ctype{}, err
+			}
+			c.asm.jumpDestination(skip)
+			continue
+		}
+		skips = append(skips, c.compileNullCheckArg(ct, i))
+		if err := c.compileJSONDoc(call.Method, ct); err != nil {
+			return ctype{}, err
+		}
+	}
+
+	c.asm.Fn_JSON_MERGE(call.Method, call.Patch, len(call.Arguments))
+	c.asm.jumpDestination(skips...)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+// jsonDocs evaluates the arguments of a function in order and parses the first n of them
+// as JSON documents. It returns nil if any of the arguments is NULL.
+func (call *CallExpr) jsonDocs(env *ExpressionEnv, n int) ([]eval, error) {
+	args := make([]eval, 0, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		arg, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if arg == nil {
+			return nil, nil
+		}
+		if i < n {
+			arg, err = intoJSON(call.Method, arg)
+			if err != nil {
+				return nil, err
+			}
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// compileJSONDocs compiles the arguments of a function that takes JSON documents as its first
+// n arguments, and returns NULL if any of the arguments is NULL
+func (call *CallExpr) compileJSONDocs(c *compiler, n int) ([]*jump, error) {
+	skips := make([]*jump, 0, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		ct, err := arg.compile(c)
+		if err != nil {
+			return nil, err
+		}
+		skips = append(skips, c.compileNullCheckArg(ct, i))
+		if i < n {
+			if err := c.compileJSONDoc(call.Method, ct); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return skips, nil
+}
+
+func (call *builtinJSONContains) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.jsonDocs(env, 2)
+	if err != nil || args == nil {
+		return nil, err
+	}
+	return builtin_JSON_CONTAINS(args)
+}
+
+func builtin_JSON_CONTAINS(args []eval) (eval, error) {
+	target := args[0].(*json.Value)
+	candidate := args[1].(*json.Value)
+	if len(args) == 3 {
+		path, err := intoJSONPath(args[2])
+		if err != nil {
+			return nil, err
+		}
+		if path.ContainsWildcards() {
+			return nil, errInvalidPathForTransform
+		}
+		var match *json.Value
+		path.Match(target, true, func(value *json.Value) {
+			match = value
+		})
+		if match == nil {
+			return nil, nil
+		}
+		target = match
+	}
+
+	contains, err := jsonContains(target, candidate)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalBool(contains), nil
+}
+
+// jsonContains returns whether the candidate document is contained in the target, following the
+// rules of JSON_CONTAINS: a scalar is contained in an equal scalar, an array is contained in an
+// array if all of its elements are, a non-array is contained in an array if it's contained in any
+// of its elements, and an object is contained in an object if the target has all of its keys and
+// each one of their values is contained in the corresponding value of the target.
+func jsonContains(target, candidate *json.Value) (bool, error) {
+	switch target.Type() {
+	case json.TypeArray:
+		elems, _ := target.Array()
+		candidates, ok := candidate.Array()
+		if !ok {
+			candidates = []*json.Value{candidate}
+		}
+		for _, c := range candidates {
+			var found bool
+			for _, e := range elems {
+				var err error
+				switch c.Type() {
+				case json.TypeArray, json.TypeObject:
+					if e.Type() == c.Type() {
+						found, err = jsonContains(e, c)
+					}
+				default:
+					found, err = jsonEqual(e, c)
+				}
+				if err != nil {
+					return false, err
+				}
+				if found {
+					break
+				}
+			}
+			if !found {
+				return false, nil
+			}
+		}
+		return true, nil
+	case json.TypeObject:
+		if candidate.Type() != json.TypeObject {
+			return false, nil
+		}
+		obj, _ := target.Object()
+		keys, _ := candidate.Object()
+		for _, key := range keys.Keys() {
+			value := obj.Get(key)
+			if value == nil {
+				return false, nil
+			}
+			contains, err := jsonContains(value, keys.Get(key))
+			if err != nil || !contains {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		return jsonEqual(target, candidate)
+	}
+}
+
+func jsonEqual(a, b *json.Value) (bool, error) {
+	cmp, err := compareJSONValue(a, b)
+	return cmp == 0, err
+}
+
+func (call *builtinJSONContains) compile(c *compiler) (ctype, error) {
+	skips, err := call.compileJSONDocs(c, 2)
+	if err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_CONTAINS(len(call.Arguments))
+	c.asm.jumpDestination(skips...)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagIsBoolean | flagNullable}, nil
+}
+
+func (call *builtinJSONOverlaps) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.jsonDocs(env, 2)
+	if err != nil || args == nil {
+		return nil, err
+	}
+	overlaps, err := jsonOverlaps(args[0].(*json.Value), args[1].(*json.Value))
+	if err != nil {
+		return nil, err
+	}
+	return newEvalBool(overlaps), nil
+}
+
+// jsonOverlaps returns whether two documents have anything in common, following the rules of
+// JSON_OVERLAPS: two arrays overlap if they have any element in common, a non-array overlaps
+// with an array if it's equal to any of its elements, two objects overlap if they have any
+// key-value pair in common, and two scalars overlap if they're equal.
+func jsonOverlaps(a, b *json.Value) (bool, error) {
+	if a.Type() != json.TypeArray && b.Type() == json.TypeArray {
+		a, b = b, a
+	}
+	switch a.Type() {
+	case json.TypeArray:
+		elems, _ := a.Array()
+		others, ok := b.Array()
+		if !ok {
+			others = []*json.Value{b}
+		}
+		for _, e := range elems {
+			for _, o := range others {
+				if eq, err := jsonEqual(e, o); err != nil || eq {
+					return eq, err
+				}
+			}
+		}
+		return false, nil
+	case json.TypeObject:
+		if b.Type() != json.TypeObject {
+			return false, nil
+		}
+		obj, _ := a.Object()
+		other, _ := b.Object()
+		for _, key := range obj.Keys() {
+			value := other.Get(key)
+			if value == nil {
+				continue
+			}
+			if eq, err := jsonEqual(obj.Get(key), value); err != nil || eq {
+				return eq, err
+			}
+		}
+		return false, nil
+	default:
+		if b.Type() == json.TypeObject {
+			return false, nil
+		}
+		return jsonEqual(a, b)
+	}
+}
+
+func (call *builtinJSONOverlaps) compile(c *compiler) (ctype, error) {
+	skips, err := call.compileJSONDocs(c, 2)
+	if err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_OVERLAPS()
+	c.asm.jumpDestination(skips...)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagIsBoolean | flagNullable}, nil
+}
+
+func (call *builtinJSONMemberOf) eval(env *ExpressionEnv) (eval, error) {
+	value, err := call.Arguments[0].eval(env)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	jvalue, err := argToJSON(value)
+	if err != nil {
+		return nil, err
+	}
+	ary, err := call.Arguments[1].eval(env)
+	if err != nil || ary == nil {
+		return nil, err
+	}
+	doc, err := intoJSON(call.Method, ary)
+	if err != nil {
+		return nil, err
+	}
+	member, err := jsonMemberOf(jvalue, doc)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalBool(member), nil
+}
+
+// jsonMemberOf returns whether the value is an element of the array; a document
+// that is not an array is treated like an array with only that element
+func jsonMemberOf(value, doc *json.Value) (bool, error) {
+	elems, ok := doc.Array()
+	if !ok {
+		elems = []*json.Value{doc}
+	}
+	for _, e := range elems {
+		if eq, err := jsonEqual(e, value); err != nil || eq {
+			return eq, err
+		}
+	}
+	return false, nil
+}
+
+func (call *builtinJSONMemberOf) compile(c *compiler) (ctype, error) {
+	value, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	skip1 := c.compileNullCheckArg(value, 0)
+	if _, err := c.compileArgToJSON(value, 1); err != nil {
+		return ctype{}, err
+	}
+
+	doc, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	skip2 := c.compileNullCheckArg(doc, 1)
+	if err := c.compileJSONDoc(call.Method, doc); err != nil {
+		return ctype{}, err
+	}
+
+	c.asm.Fn_JSON_MEMBER_OF()
+	c.asm.jumpDestination(skip1, skip2)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagIsBoolean | flagNullable}, nil
+}
+
+func (call *builtinJSONSearch) eval(env *ExpressionEnv) (eval, error) {
+	args := make([]eval, 0, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		arg, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case i == 0 && arg != nil:
+			arg, err = intoJSON(call.Method, arg)
+			if err != nil {
+				return nil, err
+			}
+		case arg == nil && i != 3:
+			// only the escape character can be NULL, in which case the default one is used
+			return nil, nil
+		}
+		args = append(args, arg)
+	}
+	return builtin_JSON_SEARCH(args)
+}
+
+var errJSONSearchEscape = vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongArguments, "Incorrect arguments to ESCAPE")
+
+func builtin_JSON_SEARCH(args []eval) (eval, error) {
+	doc := args[0].(*json.Value)
+	match, err := intoOneOrAll("JSON_SEARCH", evalToBinary(args[1]).string())
+	if err != nil {
+		return nil, err
+	}
+	search, err := evalToVarchar(args[2], collationJSON.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+
+	escape := '\\'
+	if len(args) > 3 && args[3] != nil {
+		esc, err := evalToVarchar(args[3], collationJSON.Collation, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(esc.bytes) > 0 {
+			r, size := utf8.DecodeRune(esc.bytes)
+			if size != len(esc.bytes) {
+				return nil, errJSONSearchEscape
+			}
+			escape = r
+		}
+	}
+
+	// when paths are given, only the values inside of the values they point to are searched
+	var roots map[*json.Value]bool
+	if len(args) > 4 {
+		roots = make(map[*json.Value]bool)
+		for _, arg := range args[4:] {
+			path, err := intoJSONPath(arg)
+			if err != nil {
+				return nil, err
+			}
+			path.Match(doc, true, func(value *json.Value) {
+				roots[value] = true
+			})
+		}
+	}
+
+	pattern := colldata.Lookup(collationJSON.Collation).Wildcard(search.bytes, 0, 0, escape)
+
+	var prefixes []string
+	var found []*json.Value
+	doc.WalkPaths(func(path []byte, value *json.Value) {
+		if match == jsonMatchOne && len(found) > 0 {
+			return
+		}
+		if roots != nil {
+			if roots[value] {
+				prefixes = append(prefixes, string(path))
+			}
+			if !slices.ContainsFunc(prefixes, func(prefix string) bool { return isPathPrefix(path, prefix) }) {
+				return
+			}
+		}
+		if str, ok := value.StringBytes(); ok && pattern.Match(str) {
+			found = append(found, json.NewString(string(path)))
+		}
+	})
+
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return found[0], nil
+	default:
+		return json.NewArray(found), nil
+	}
+}
+
+// isPathPrefix returns whether the value at path is the one at prefix or nested inside of it
+func isPathPrefix(path []byte, prefix string) bool {
+	if !bytes.HasPrefix(path, []byte(prefix)) {
+		return false
+	}
+	return len(path) == len(prefix) || path[len(prefix)] == '.' || path[len(prefix)] == '['
+}
+
+func (call *builtinJSONSearch) compile(c *compiler) (ctype, error) {
+	skips := make([]*jump, 0, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		ct, err := arg.compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+		if i == 3 {
+			continue
+		}
+		skips = append(skips, c.compileNullCheckArg(ct, i))
+		if i == 0 {
+			if err := c.compileJSONDoc(call.Method, ct); err != nil {
+				return ctype{}, err
+			}
+		}
+	}
+	c.asm.Fn_JSON_SEARCH(len(call.Arguments))
+	c.asm.jumpDestination(skips...)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+func (call *builtinJSONValue) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.jsonDocs(env, 1)
+	if err != nil || args == nil {
+		return nil, err
+	}
+	return builtin_JSON_VALUE(args[0].(*json.Value), args[1])
+}
+
+// builtin_JSON_VALUE extracts the scalar that the path points to, as text. Missing values
+// and values that are not scalars result in NULL, which is the default behavior of MySQL
+// when there are no ON EMPTY or ON ERROR clauses.
+func builtin_JSON_VALUE(doc *json.Value, arg eval) (eval, error) {
+	path, err := intoJSONPath(arg)
+	if err != nil {
+		return nil, err
+	}
+	if path.ContainsWildcards() {
+		return nil, errInvalidPathForTransform
+	}
+
+	var match *json.Value
+	path.Match(doc, true, func(value *json.Value) {
+		match = value
+	})
+
+	var text []byte
+	switch match.Type() {
+	case json.TypeNull, json.TypeArray, json.TypeObject:
+		return nil, nil
+	case json.TypeString:
+		text, _ = match.StringBytes()
+	case json.TypeDate:
+		text = []byte(match.MarshalDate())
+	case json.TypeDateTime:
+		text = []byte(match.MarshalDateTime())
+	case json.TypeTime:
+		text = []byte(match.MarshalTime())
+	default:
+		text = match.MarshalTo(nil)
+	}
+	return newEvalRaw(sqltypes.VarChar, text, collationJSON), nil
+}
+
+func (call *builtinJSONValue) compile(c *compiler) (ctype, error) {
+	skips, err := call.compileJSONDocs(c, 1)
+	if err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_VALUE()
+	c.asm.jumpDestination(skips...)
+	return ctype{Type: sqltypes.VarChar, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+func (call *builtinJSONPretty) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.jsonDocs(env, 1)
+	if err != nil || args == nil {
+		return nil, err
+	}
+	return newEvalRaw(sqltypes.Blob, args[0].(*json.Value).MarshalPretty(nil), collationJSON), nil
+}
+
+func (call *builtinJSONPretty) compile(c *compiler) (ctype, error) {
+	skips, err := call.compileJSONDocs(c, 1)
+	if err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_PRETTY()
+	c.asm.jumpDestination(skips...)
+	return ctype{Type: sqltypes.Blob, Flag: flagNullable, Col: collationJSON}, nil
+}
@@ -0,0 +1,435 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/mysql/geometry"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+)
+
+// Spatial values are stored the same way MySQL stores them: the SRID of the geometry
+// followed by its well-known binary representation. We only know how to work with
+// geometries in the Cartesian SRID 0, because the definitions of all the other spatial
+// reference systems are only available in the catalog of the MySQL server.
+
+type (
+	builtinGeomFrom struct {
+		CallExpr
+		// Type is the type that the geometry must have, or zero for any type
+		Type geometry.Type
+		WKB  bool
+	}
+
+	builtinPoint struct {
+		CallExpr
+	}
+
+	builtinGeomFormat struct {
+		CallExpr
+		WKB     bool
+		collate collations.ID
+	}
+
+	builtinGeomProperty struct {
+		CallExpr
+		Property geomProperty
+		collate  collations.ID
+	}
+
+	builtinPointCoord struct {
+		CallExpr
+		Y bool
+	}
+
+	builtinSTDistance struct {
+		CallExpr
+	}
+
+	builtinSTContains struct {
+		CallExpr
+		Within bool
+	}
+
+	geomProperty int8
+)
+
+const (
+	geomIsEmpty geomProperty = iota
+	geomDimension
+	geomGeometryType
+)
+
+var _ IR = (*builtinGeomFrom)(nil)
+var _ IR = (*builtinPoint)(nil)
+var _ IR = (*builtinGeomFormat)(nil)
+var _ IR = (*builtinGeomProperty)(nil)
+var _ IR = (*builtinPointCoord)(nil)
+var _ IR = (*builtinSTDistance)(nil)
+var _ IR = (*builtinSTContains)(nil)
+
+func errGISInvalidData(fname string) error {
+	return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.GISInvalidData, "Invalid GIS data provided to function %s.", fname)
+}
+
+func errUnsupportedSRID(fname string, srid int64) error {
+	return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s: geometries in the spatial reference system %d are not supported", fname, srid)
+}
+
+func newEvalGeometry(g *geometry.Geometry) *evalBytes {
+	return newEvalRaw(sqltypes.Geometry, g.AppendMySQL(nil), collationBinary)
+}
+
+func evalToGeometry(e eval, fname string) (*geometry.Geometry, error) {
+	g, err := geometry.ParseMySQL(e.ToRawBytes())
+	if err != nil {
+		return nil, errGISInvalidData(fname)
+	}
+	if g.SRID != 0 {
+		return nil, errUnsupportedSRID(fname, int64(g.SRID))
+	}
+	return g, nil
+}
+
+func geomFrom(raw []byte, srid int64, wkb bool, typ geometry.Type, fname string) (eval, error) {
+	if srid != 0 {
+		return nil, errUnsupportedSRID(fname, srid)
+	}
+	var g *geometry.Geometry
+	var err error
+	if wkb {
+		g, err = geometry.ParseWKB(raw)
+	} else {
+		g, err = geometry.ParseWKT(string(raw))
+	}
+	if err != nil || (typ != 0 && g.Type != typ) {
+		return nil, errGISInvalidData(fname)
+	}
+	return newEvalGeometry(g), nil
+}
+
+func (call *builtinGeomFrom) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	var srid int64
+	if len(args) > 1 {
+		srid = evalToInt64(args[1]).i
+	}
+	return geomFrom(args[0].ToRawBytes(), srid, call.WKB, call.Type, call.Method)
+}
+
+func (call *builtinGeomFrom) compile(c *compiler) (ctype, error) {
+	raw, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	srid := ctype{Type: sqltypes.Int64, Col: collationNumeric}
+	if len(call.Arguments) > 1 {
+		srid, err = call.Arguments[1].compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+	}
+
+	var skip *jump
+	if len(call.Arguments) > 1 {
+		skip = c.compileNullCheck2(raw, srid)
+		c.compileToInt64(srid, 1)
+	} else {
+		skip = c.compileNullCheck1(raw)
+	}
+
+	c.asm.Fn_ST_GEOMFROM(len(call.Arguments) > 1, call.WKB, call.Type, call.Method)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Geometry, Col: collationBinary, Flag: nullableFlags(raw.Flag | srid.Flag)}, nil
+}
+
+func (call *builtinPoint) eval(env *ExpressionEnv) (eval, error) {
+	x, y, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if x == nil || y == nil {
+		return nil, nil
+	}
+	fx, _ := evalToFloat(x)
+	fy, _ := evalToFloat(y)
+	return newEvalGeometry(geometry.NewPoint(fx.f, fy.f)), nil
+}
+
+func (call *builtinPoint) compile(c *compiler) (ctype, error) {
+	x, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	y, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(x, y)
+	c.compileToFloat(x, 2)
+	c.compileToFloat(y, 1)
+	c.asm.Fn_POINT()
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Geometry, Col: collationBinary, Flag: nullableFlags(x.Flag | y.Flag)}, nil
+}
+
+func geomFormat(g *geometry.Geometry, wkb bool, col collations.TypedCollation) eval {
+	if wkb {
+		return newEvalBinary(g.AppendWKB(nil))
+	}
+	return newEvalText(g.AppendWKT(nil), col)
+}
+
+func (call *builtinGeomFormat) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil || arg == nil {
+		return nil, err
+	}
+	g, err := evalToGeometry(arg, call.Method)
+	if err != nil {
+		return nil, err
+	}
+	return geomFormat(g, call.WKB, typedCoercionCollation(sqltypes.VarChar, call.collate)), nil
+}
+
+func (call *builtinGeomFormat) compile(c *compiler) (ctype, error) {
+	arg, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck1(arg)
+	ct := ctype{Type: sqltypes.VarBinary, Col: collationBinary, Flag: nullableFlags(arg.Flag)}
+	if !call.WKB {
+		ct.Type = sqltypes.VarChar
+		ct.Col = typedCoercionCollation(sqltypes.VarChar, c.collation)
+	}
+	c.asm.Fn_ST_AS(call.WKB, ct.Col, call.Method)
+	c.asm.jumpDestination(skip)
+	return ct, nil
+}
+
+func geomPropertyValue(g *geometry.Geometry, prop geomProperty, col collations.TypedCollation) eval {
+	switch prop {
+	case geomIsEmpty:
+		return newEvalBool(g.IsEmpty())
+	case geomDimension:
+		return newEvalInt64(int64(g.Dimension()))
+	default:
+		return newEvalText([]byte(g.Type.String()), col)
+	}
+}
+
+func (call *builtinGeomProperty) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil || arg == nil {
+		return nil, err
+	}
+	g, err := evalToGeometry(arg, call.Method)
+	if err != nil {
+		return nil, err
+	}
+	return geomPropertyValue(g, call.Property, typedCoercionCollation(sqltypes.VarChar, call.collate)), nil
+}
+
+func (call *builtinGeomProperty) compile(c *compiler) (ctype, error) {
+	arg, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck1(arg)
+	ct := ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: nullableFlags(arg.Flag)}
+	switch call.Property {
+	case geomIsEmpty:
+		ct.Flag |= flagIsBoolean
+	case geomGeometryType:
+		ct.Type = sqltypes.VarChar
+		ct.Col = typedCoercionCollation(sqltypes.VarChar, c.collation)
+	}
+	c.asm.Fn_ST_PROPERTY(call.Property, ct.Col, call.Method)
+	c.asm.jumpDestination(skip)
+	return ct, nil
+}
+
+// pointCoord returns the X or Y coordinate of a point or, if set is not nil, a copy of the point
+// with the coordinate replaced by set
+func pointCoord(arg eval, y bool, set *evalFloat, fname string) (eval, error) {
+	g, err := evalToGeometry(arg, fname)
+	if err != nil {
+		return nil, err
+	}
+	if g.Type != geometry.Point {
+		return nil, errGISInvalidData(fname)
+	}
+	c := g.Coords[0]
+	if set == nil {
+		if y {
+			return newEvalFloat(c.Y), nil
+		}
+		return newEvalFloat(c.X), nil
+	}
+	if y {
+		c.Y = set.f
+	} else {
+		c.X = set.f
+	}
+	return newEvalGeometry(geometry.NewPoint(c.X, c.Y)), nil
+}
+
+func (call *builtinPointCoord) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	var set *evalFloat
+	if len(args) > 1 {
+		set, _ = evalToFloat(args[1])
+	}
+	return pointCoord(args[0], call.Y, set, call.Method)
+}
+
+func (call *builtinPointCoord) compile(c *compiler) (ctype, error) {
+	arg, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	if len(call.Arguments) == 1 {
+		skip := c.compileNullCheck1(arg)
+		c.asm.Fn_ST_COORD(call.Y, false, call.Method)
+		c.asm.jumpDestination(skip)
+		return ctype{Type: sqltypes.Float64, Col: collationNumeric, Flag: nullableFlags(arg.Flag)}, nil
+	}
+
+	set, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(arg, set)
+	c.compileToFloat(set, 1)
+	c.asm.Fn_ST_COORD(call.Y, true, call.Method)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Geometry, Col: collationBinary, Flag: nullableFlags(arg.Flag | set.Flag)}, nil
+}
+
+func geometryArgs(arg1, arg2 eval, fname string) (*geometry.Geometry, *geometry.Geometry, error) {
+	g1, err := evalToGeometry(arg1, fname)
+	if err != nil {
+		return nil, nil, err
+	}
+	g2, err := evalToGeometry(arg2, fname)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g1, g2, nil
+}
+
+func stDistance(arg1, arg2 eval, fname string) (eval, error) {
+	g1, g2, err := geometryArgs(arg1, arg2, fname)
+	if err != nil {
+		return nil, err
+	}
+	dist, ok := geometry.Distance(g1, g2)
+	if !ok {
+		return nil, nil
+	}
+	return newEvalFloat(dist), nil
+}
+
+func (call *builtinSTDistance) eval(env *ExpressionEnv) (eval, error) {
+	arg1, arg2, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if arg1 == nil || arg2 == nil {
+		return nil, nil
+	}
+	return stDistance(arg1, arg2, call.Method)
+}
+
+func (call *builtinSTDistance) compile(c *compiler) (ctype, error) {
+	arg1, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	arg2, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(arg1, arg2)
+	c.asm.Fn_ST_DISTANCE(call.Method)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Float64, Col: collationNumeric, Flag: flagNullable}, nil
+}
+
+func stContains(arg1, arg2 eval, within bool, fname string) (eval, error) {
+	g1, g2, err := geometryArgs(arg1, arg2, fname)
+	if err != nil {
+		return nil, err
+	}
+	if within {
+		return newEvalBool(geometry.Within(g1, g2)), nil
+	}
+	return newEvalBool(geometry.Contains(g1, g2)), nil
+}
+
+func (call *builtinSTContains) eval(env *ExpressionEnv) (eval, error) {
+	arg1, arg2, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if arg1 == nil || arg2 == nil {
+		return nil, nil
+	}
+	return stContains(arg1, arg2, call.Within, call.Method)
+}
+
+func (call *builtinSTContains) compile(c *compiler) (ctype, error) {
+	arg1, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	arg2, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(arg1, arg2)
+	c.asm.Fn_ST_CONTAINS(call.Within, call.Method)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: nullableFlags(arg1.Flag|arg2.Flag) | flagIsBoolean}, nil
+}
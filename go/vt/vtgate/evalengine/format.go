@@ -278,6 +278,13 @@ func (c *builtinWeightString) format(buf *sqlparser.TrackedBuffer) {
 	buf.WriteByte(')')
 }
 
+func (c *builtinJSONMemberOf) format(buf *sqlparser.TrackedBuffer) {
+	formatExpr(buf, c, c.Arguments[0], true)
+	buf.WriteLiteral(" member of (")
+	formatExpr(buf, c, c.Arguments[1], true)
+	buf.WriteByte(')')
+}
+
 func (n *NegateExpr) format(buf *sqlparser.TrackedBuffer) {
 	buf.WriteByte('-')
 	formatExpr(buf, n, n.Inner, true)
@@ -33,6 +33,11 @@ var Cases = []TestCase{
 	{Run: JSONPathOperations},
 	{Run: JSONArray},
 	{Run: JSONObject},
+	{Run: JSONModify},
+	{Run: JSONMerge},
+	{Run: JSONSearchOperations},
+	{Run: JSONValue},
+	{Run: JSONPretty},
 	{Run: CharsetConversionOperators},
 	{Run: CaseExprWithPredicate},
 	{Run: CaseExprWithValue},
@@ -172,6 +177,12 @@ var Cases = []TestCase{
 	{Run: RegexpInstr},
 	{Run: RegexpSubstr},
 	{Run: RegexpReplace},
+	{Run: FnSTGeomFromText},
+	{Run: FnSTAsText},
+	{Run: FnSTGeometryProperties},
+	{Run: FnSTPointCoords},
+	{Run: FnSTDistance},
+	{Run: FnSTContains},
 }
 
 func JSONPathOperations(yield Query) {
@@ -212,6 +223,234 @@ func JSONObject(yield Query) {
 	yield("JSON_OBJECT()", nil)
 }
 
+func JSONModify(yield Query) {
+	for _, obj := range inputJSONObjects {
+		for _, path := range inputJSONPaths {
+			yield(fmt.Sprintf("JSON_REMOVE('%s', '%s')", obj, path), nil)
+			for _, fn := range []string{"JSON_SET", "JSON_INSERT", "JSON_REPLACE"} {
+				yield(fmt.Sprintf("%s('%s', '%s', 1)", fn, obj, path), nil)
+				yield(fmt.Sprintf("%s('%s', '%s', '[1]', '$[5]', JSON_ARRAY(true))", fn, obj, path), nil)
+			}
+		}
+		yield(fmt.Sprintf("JSON_REMOVE('%s', '$[0]', NULL)", obj), nil)
+		yield(fmt.Sprintf("JSON_SET('%s', '$[last-10]', NULL, '$[1][1]', 2)", obj), nil)
+	}
+
+	for _, val := range inputJSONPrimitives {
+		yield(fmt.Sprintf("JSON_SET('[1, {\"a\": 2}]', '$[1].b', %s)", val), nil)
+		yield(fmt.Sprintf("JSON_INSERT('[1, {\"a\": 2}]', '$[1].a', %s, '$[0][1]', %s)", val, val), nil)
+		yield(fmt.Sprintf("JSON_REPLACE('[1, {\"a\": 2}]', '$[1].a', %s, '$[1][0]', %s)", val, val), nil)
+	}
+
+	mysqlDocSamples := []string{
+		`JSON_SET('{ "a": 1, "b": [2, 3]}', '$.a', 10, '$.c', '[true, false]')`,
+		`JSON_INSERT('{ "a": 1, "b": [2, 3]}', '$.a', 10, '$.c', '[true, false]')`,
+		`JSON_REPLACE('{ "a": 1, "b": [2, 3]}', '$.a', 10, '$.c', '[true, false]')`,
+		`JSON_SET('{ "a": 1, "b": [2, 3]}', '$.a', 10, '$.c', CAST('[true, false]' AS JSON))`,
+		`JSON_REMOVE('["a", ["b", "c"], "d"]', '$[1]')`,
+		`JSON_SET(NULL, '$.a', 1)`,
+		`JSON_SET('{}', NULL, 1)`,
+		`JSON_SET('{}', '$.a', NULL)`,
+		`JSON_SET('{}', '$.*', 1)`,
+		`JSON_SET('[1, 2]', '$[0 to 1]', 1)`,
+		`JSON_SET('{"a": 1}', '$', 2)`,
+		`JSON_REMOVE('{"a": 1}', '$')`,
+		`JSON_REMOVE('{"a": 1}', '$.a', '$.*')`,
+		`JSON_SET('invalid', '$.a', 1)`,
+		`JSON_SET('{}', 'invalid', 1)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func JSONMerge(yield Query) {
+	for _, fn := range []string{"JSON_MERGE_PRESERVE", "JSON_MERGE_PATCH"} {
+		for _, a := range inputJSONObjects {
+			for _, b := range inputJSONObjects {
+				yield(fmt.Sprintf("%s('%s', '%s')", fn, a, b), nil)
+			}
+		}
+		for _, a := range inputJSONPrimitives {
+			yield(fmt.Sprintf("%s(%s, '{\"a\": null, \"b\": 2}', '[1]')", fn, a), nil)
+			yield(fmt.Sprintf("%s('{\"a\": null, \"b\": 2}', %s)", fn, a), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`JSON_MERGE_PRESERVE('[1, 2]', '[true, false]')`,
+		`JSON_MERGE_PRESERVE('{"name": "x"}', '{"id": 47}')`,
+		`JSON_MERGE_PRESERVE('1', 'true')`,
+		`JSON_MERGE_PRESERVE('[1, 2]', '{"id": 47}')`,
+		`JSON_MERGE_PRESERVE('{ "a": 1, "b": 2 }', '{ "a": 3, "c": 4 }')`,
+		`JSON_MERGE_PRESERVE('{ "a": 1, "b": 2 }','{ "a": 3, "c": 4 }', '{ "a": 5, "d": 6 }')`,
+		`JSON_MERGE('[1, 2]', '[true, false]')`,
+		`JSON_MERGE_PATCH('[1, 2]', '[true, false]')`,
+		`JSON_MERGE_PATCH('{"name": "x"}', '{"id": 47}')`,
+		`JSON_MERGE_PATCH('1', 'true')`,
+		`JSON_MERGE_PATCH('[1, 2]', '{"id": 47}')`,
+		`JSON_MERGE_PATCH('{ "a": 1, "b":2 }','{ "a": 3, "c":4 }')`,
+		`JSON_MERGE_PATCH('{ "a": 1, "b":2 }','{ "a": 3, "c":4 }','{ "a": 5, "d":6 }')`,
+		`JSON_MERGE_PATCH('{"a":1, "b":2}', '{"b":null}')`,
+		`JSON_MERGE_PATCH('{"a":{"x":1}}', '{"a":{"y":2}}')`,
+		`JSON_MERGE_PATCH(NULL, '{"a": 1}')`,
+		`JSON_MERGE_PATCH(NULL, '[1]')`,
+		`JSON_MERGE_PATCH('{"a": 1}', NULL, '{"b": 2}', 'true')`,
+		`JSON_MERGE_PRESERVE(NULL, 'invalid')`,
+		`JSON_MERGE_PRESERVE('invalid', NULL)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func JSONSearchOperations(yield Query) {
+	for _, a := range inputJSONObjects {
+		for _, b := range inputJSONObjects {
+			yield(fmt.Sprintf("JSON_CONTAINS('%s', '%s')", a, b), nil)
+			yield(fmt.Sprintf("JSON_OVERLAPS('%s', '%s')", a, b), nil)
+		}
+		for _, path := range inputJSONPaths {
+			yield(fmt.Sprintf("JSON_CONTAINS('%s', '\"foo\"', '%s')", a, path), nil)
+			yield(fmt.Sprintf("JSON_CONTAINS('%s', '1', '%s')", a, path), nil)
+			yield(fmt.Sprintf("JSON_SEARCH('%s', 'one', '%%o%%', NULL, '%s')", a, path), nil)
+			yield(fmt.Sprintf("JSON_SEARCH('%s', 'all', '1%%', NULL, '%s')", a, path), nil)
+		}
+		for _, val := range inputJSONPrimitives {
+			yield(fmt.Sprintf("%s MEMBER OF ('%s')", val, a), nil)
+			yield(fmt.Sprintf("JSON_SEARCH('%s', 'all', %s)", a, val), nil)
+		}
+	}
+
+	for _, a := range inputJSONPrimitives {
+		for _, b := range inputJSONPrimitives {
+			yield(fmt.Sprintf("%s MEMBER OF (JSON_ARRAY(%s))", a, b), nil)
+			yield(fmt.Sprintf("JSON_OVERLAPS(JSON_ARRAY(%s), JSON_ARRAY(%s))", a, b), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`JSON_CONTAINS('{"a": 1, "b": 2, "c": {"d": 4}}', '1', '$.a')`,
+		`JSON_CONTAINS('{"a": 1, "b": 2, "c": {"d": 4}}', '1', '$.b')`,
+		`JSON_CONTAINS('{"a": 1, "b": 2, "c": {"d": 4}}', '{"d": 4}', '$.a')`,
+		`JSON_CONTAINS('{"a": 1, "b": 2, "c": {"d": 4}}', '{"d": 4}', '$.c')`,
+		`JSON_CONTAINS('[1, [2, 3]]', '[[2]]')`,
+		`JSON_CONTAINS('[1, [2, 3]]', '[2]')`,
+		`JSON_CONTAINS('{"a": [1, 2]}', '{"a": 1}')`,
+		`JSON_CONTAINS('[1, 2]', '[]')`,
+		`JSON_CONTAINS('[1, 2]', '1.0')`,
+		`JSON_CONTAINS('[1, 2]', '"1"')`,
+		`JSON_CONTAINS('[1]', '1', '$[*]')`,
+		`JSON_CONTAINS('[1]', 1)`,
+		`JSON_CONTAINS(NULL, '1')`,
+		`JSON_OVERLAPS("[1,3,5,7]", "[2,5,7]")`,
+		`JSON_OVERLAPS("[1,3,5,7]", "[2,6,8]")`,
+		`JSON_OVERLAPS('[[1,2],[3,4],5]', '[1,[2,3],[4,5]]')`,
+		`JSON_OVERLAPS('[[1,2],[3,4],5]', '[[1,2],[3,4],5]')`,
+		`JSON_OVERLAPS('{"a":1,"b":10,"d":10}', '{"c":1,"e":10,"f":1,"d":10}')`,
+		`JSON_OVERLAPS('{"a":1,"b":10,"d":10}', '{"a":5,"e":10,"f":1,"d":20}')`,
+		`JSON_OVERLAPS('5', '5')`,
+		`JSON_OVERLAPS('5', '6')`,
+		`JSON_OVERLAPS('[4,5,"6",7]', '6')`,
+		`JSON_OVERLAPS('[4,5,6,7]', '"6"')`,
+		`JSON_OVERLAPS('[4,5,{"a":1}]', '{"a":1}')`,
+		`JSON_OVERLAPS('{"a":1}', '1')`,
+		`17 MEMBER OF('[23, "abc", 17, "ab", 10]')`,
+		`'ab' MEMBER OF('[23, "abc", 17, "ab", 10]')`,
+		`7 MEMBER OF('[23, "abc", 17, "ab", 10]')`,
+		`'a' MEMBER OF('[23, "abc", 17, "ab", 10]')`,
+		`'7' MEMBER OF('[23, "abc", 7, "ab", 10]')`,
+		`17 MEMBER OF('17')`,
+		`CAST('[4,5]' AS JSON) MEMBER OF('[[3,4],[4,5]]')`,
+		`JSON_ARRAY(4,5) MEMBER OF('[[3,4],[4,5]]')`,
+		`NULL MEMBER OF('[1]')`,
+		`1 MEMBER OF(NULL)`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'one', 'abc')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', 'abc')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', 'ghi')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '10')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '10', NULL, '$')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '10', NULL, '$[*]')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '10', NULL, '$**.k')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '10', NULL, '$[*][0].k')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '10', NULL, '$[1]')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '10', NULL, '$[1][0]')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', 'abc', NULL, '$[2]')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '%a%')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '%b%')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '%b%', NULL, '$[0]')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '%b%', NULL, '$[2]')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '%b%', NULL, '$[1]')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '%b%', '', '$[1]')`,
+		`JSON_SEARCH('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '%b%', '', '$[3]')`,
+		`JSON_SEARCH('["a%c", "abc"]', 'all', 'a|%c', '|')`,
+		`JSON_SEARCH('["a%c", "abc"]', 'all', 'a\%c')`,
+		`JSON_SEARCH('["a%c", "abc"]', 'all', 'a%c', 'ab')`,
+		`JSON_SEARCH('["abc"]', 'ALL', 'abc')`,
+		`JSON_SEARCH('["abc"]', 'any', 'abc')`,
+		`JSON_SEARCH('["abc"]', 'one', 'ABC')`,
+		`JSON_SEARCH('{"a b": "x", "c": {"d": ["x"]}}', 'all', 'x')`,
+		`JSON_SEARCH(NULL, 'one', 'abc')`,
+		`JSON_SEARCH('["abc"]', NULL, 'abc')`,
+		`JSON_SEARCH('["abc"]', 'one', NULL)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func JSONValue(yield Query) {
+	for _, obj := range inputJSONObjects {
+		for _, path := range inputJSONPaths {
+			yield(fmt.Sprintf("JSON_VALUE('%s', '%s')", obj, path), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`JSON_VALUE('{"fname": "Joe", "lname": "Palmer"}', '$.fname')`,
+		`JSON_VALUE('{"item": "shoes", "price": "49.95"}', '$.price')`,
+		`JSON_VALUE('{"item": "shoes", "price": 49.95}', '$.price')`,
+		`JSON_VALUE('{"a": null, "b": true, "c": 1.5e0}', '$.a')`,
+		`JSON_VALUE('{"a": null, "b": true, "c": 1.5e0}', '$.b')`,
+		`JSON_VALUE('{"a": null, "b": true, "c": 1.5e0}', '$.c')`,
+		`JSON_VALUE(CAST(CAST('2024-01-02' AS DATE) AS JSON), '$')`,
+		`JSON_VALUE('[1, 2]', '$[*]')`,
+		`JSON_VALUE(NULL, '$')`,
+		`JSON_VALUE('[1, 2]', NULL)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func JSONPretty(yield Query) {
+	for _, obj := range inputJSONObjects {
+		yield(fmt.Sprintf("JSON_PRETTY('%s')", obj), nil)
+	}
+	for _, val := range inputJSONPrimitives {
+		yield(fmt.Sprintf("JSON_PRETTY(JSON_ARRAY(%s, JSON_OBJECT('a', %s)))", val, val), nil)
+	}
+
+	mysqlDocSamples := []string{
+		`JSON_PRETTY('123')`,
+		`JSON_PRETTY("[1,3,5]")`,
+		`JSON_PRETTY('{"a":"10","b":"15","x":"25"}')`,
+		`JSON_PRETTY('["a",1,{"key1": "value1"},"5",     "77" , {"key2":["value3","valueX", "valueY"]},"j", "2"   ]')`,
+		`JSON_PRETTY('{}')`,
+		`JSON_PRETTY('[]')`,
+		`JSON_PRETTY(NULL)`,
+		`JSON_PRETTY('invalid')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
 func CharsetConversionOperators(yield Query) {
 	var introducers = []string{
 		"", "_latin1", "_utf8mb4", "_utf8", "_binary",
@@ -2666,3 +2905,149 @@ func RegexpReplace(yield Query) {
 		yield(q, nil)
 	}
 }
+
+func FnSTGeomFromText(yield Query) {
+	for _, g := range inputGeometries {
+		yield(fmt.Sprintf("ST_GeomFromText(%s)", g), nil)
+		yield(fmt.Sprintf("ST_GeomFromText(%s, 0)", g), nil)
+		yield(fmt.Sprintf("ST_PointFromText(%s)", g), nil)
+		yield(fmt.Sprintf("ST_PolygonFromText(%s)", g), nil)
+		yield(fmt.Sprintf("ST_GeomCollFromText(%s)", g), nil)
+		yield(fmt.Sprintf("ST_GeomFromWKB(ST_AsBinary(ST_GeomFromText(%s)))", g), nil)
+		yield(fmt.Sprintf("ST_LineStringFromWKB(ST_AsWKB(ST_GeomFromText(%s)))", g), nil)
+	}
+
+	mysqlDocSamples := []string{
+		`ST_GeomFromText(NULL)`,
+		`ST_GeomFromText('POINT(1 2)', NULL)`,
+		`ST_GeomFromText('')`,
+		`ST_GeomFromText('POINT(1)')`,
+		`ST_GeomFromText('LINESTRING(0 0)')`,
+		`ST_GeomFromText('POLYGON((0 0,1 0,1 1))')`,
+		`ST_GeomFromText('POINT(1 2) foo')`,
+		`ST_GeomFromText(1)`,
+		`ST_GeomFromWKB(NULL)`,
+		`ST_GeomFromWKB('foobar')`,
+		`ST_GeomFromWKB(X'0101000000000000000000F03F0000000000000040')`,
+		`ST_GeomFromWKB(X'00000000013FF00000000000004000000000000000')`,
+		`POINT(1, 2)`,
+		`POINT('1.5', 2e0)`,
+		`POINT(NULL, 2)`,
+		`POINT(1, NULL)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnSTAsText(yield Query) {
+	for _, g := range inputGeometries {
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromText(%s))", g), nil)
+		yield(fmt.Sprintf("ST_AsWKT(ST_GeomFromText(%s))", g), nil)
+		yield(fmt.Sprintf("HEX(ST_AsBinary(ST_GeomFromText(%s)))", g), nil)
+	}
+
+	mysqlDocSamples := []string{
+		`ST_AsText(NULL)`,
+		`ST_AsText('foobar')`,
+		`ST_AsText(POINT(1, 2))`,
+		`ST_AsText(POINT(1e20, -0.000001))`,
+		`ST_AsText(POINT(0.1, 1/3))`,
+		`ST_AsBinary(NULL)`,
+		`ST_AsBinary(1)`,
+		`ST_AsText(ST_GeomFromText('MULTIPOINT((1 2),(3 4))'))`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnSTGeometryProperties(yield Query) {
+	for _, g := range inputGeometries {
+		yield(fmt.Sprintf("ST_GeometryType(ST_GeomFromText(%s))", g), nil)
+		yield(fmt.Sprintf("ST_IsEmpty(ST_GeomFromText(%s))", g), nil)
+		yield(fmt.Sprintf("ST_Dimension(ST_GeomFromText(%s))", g), nil)
+	}
+
+	mysqlDocSamples := []string{
+		`ST_GeometryType(NULL)`,
+		`ST_IsEmpty(NULL)`,
+		`ST_Dimension(NULL)`,
+		`ST_GeometryType('foobar')`,
+		`ST_IsEmpty(1)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnSTPointCoords(yield Query) {
+	for _, g := range inputGeometries {
+		yield(fmt.Sprintf("ST_X(ST_GeomFromText(%s))", g), nil)
+		yield(fmt.Sprintf("ST_Y(ST_GeomFromText(%s))", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_X(ST_GeomFromText(%s), 10))", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_Y(ST_GeomFromText(%s), 10))", g), nil)
+	}
+
+	for _, num := range radianInputs {
+		yield(fmt.Sprintf("ST_X(POINT(%s, 1))", num), nil)
+		yield(fmt.Sprintf("ST_Y(POINT(1, %s))", num), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_Y(POINT(1, 2), %s))", num), nil)
+	}
+
+	mysqlDocSamples := []string{
+		`ST_X(NULL)`,
+		`ST_Y(NULL)`,
+		`ST_X(POINT(1, 2), NULL)`,
+		`ST_X(NULL, 1)`,
+		`ST_X('foobar')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnSTDistance(yield Query) {
+	for _, g1 := range inputGeometries {
+		for _, g2 := range inputGeometries {
+			yield(fmt.Sprintf("ST_Distance(ST_GeomFromText(%s), ST_GeomFromText(%s))", g1, g2), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`ST_Distance(NULL, POINT(1, 1))`,
+		`ST_Distance(POINT(1, 1), NULL)`,
+		`ST_Distance(POINT(1, 1), 'foobar')`,
+		`ST_Distance(POINT(1, 1), POINT(2, 2))`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnSTContains(yield Query) {
+	for _, g1 := range inputGeometries {
+		for _, g2 := range inputGeometries {
+			yield(fmt.Sprintf("ST_Contains(ST_GeomFromText(%s), ST_GeomFromText(%s))", g1, g2), nil)
+			yield(fmt.Sprintf("ST_Within(ST_GeomFromText(%s), ST_GeomFromText(%s))", g1, g2), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`ST_Contains(NULL, POINT(1, 1))`,
+		`ST_Within(POINT(1, 1), NULL)`,
+		`ST_Contains(POINT(1, 1), 'foobar')`,
+		`ST_Contains(ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0))'), POINT(5, 5))`,
+		`ST_Within(POINT(5, 5), ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0))'))`,
+		`ST_Within(POINT(10, 5), ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0))'))`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
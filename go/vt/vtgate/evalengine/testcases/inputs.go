@@ -411,3 +411,16 @@ var inputIntervals = []string{"day",
 	"second_microsecond",
 	"year_month",
 }
+
+var inputGeometries = []string{
+	`'POINT(1 2)'`,
+	`'POINT(-1.5 2.25)'`,
+	`'LINESTRING(0 0,5 5,10 0)'`,
+	`'POLYGON((0 0,10 0,10 10,0 10,0 0))'`,
+	`'POLYGON((0 0,10 0,10 10,0 10,0 0),(4 4,6 4,6 6,4 6,4 4))'`,
+	`'MULTIPOINT(1 1,5 5,20 20)'`,
+	`'MULTILINESTRING((1 1,2 2),(8 8,9 9))'`,
+	`'MULTIPOLYGON(((1 1,2 1,2 2,1 1)),((20 20,21 20,21 21,20 20)))'`,
+	`'GEOMETRYCOLLECTION(POINT(5 5),LINESTRING(1 1,3 3))'`,
+	`'GEOMETRYCOLLECTION EMPTY'`,
+}
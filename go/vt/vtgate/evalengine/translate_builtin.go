@@ -21,6 +21,8 @@ import (
 	"strings"
 
 	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/mysql/geometry"
+	"github.com/mdibaiee/vitess/go/mysql/json"
 	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
@@ -648,6 +650,22 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 			return nil, argError(method)
 		}
 		return &builtinReplace{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "st_distance":
+		switch len(args) {
+		case 2:
+			return &builtinSTDistance{CallExpr: call}, nil
+		case 3:
+			// distances in a unit other than the one of the spatial reference system
+			// are only supported for geographic systems
+			return nil, translateExprNotSupported(fn)
+		default:
+			return nil, argError(method)
+		}
+	case "st_contains", "st_within":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinSTContains{CallExpr: call, Within: method == "st_within"}, nil
 	default:
 		return nil, translateExprNotSupported(fn)
 	}
@@ -768,6 +786,219 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 			Method:    "JSON_KEYS",
 		}}, nil
 
+	case *sqlparser.JSONValueModifierExpr:
+		var t json.Transformation
+		var method string
+		switch call.Type {
+		case sqlparser.JSONSetType:
+			t, method = json.Set, "JSON_SET"
+		case sqlparser.JSONInsertType:
+			t, method = json.Insert, "JSON_INSERT"
+		case sqlparser.JSONReplaceType:
+			t, method = json.Replace, "JSON_REPLACE"
+		default:
+			return nil, translateExprNotSupported(call)
+		}
+		exprs := []sqlparser.Expr{call.JSONDoc}
+		for _, param := range call.Params {
+			exprs = append(exprs, param.Key, param.Value)
+		}
+		args, err := ast.translateFuncArgs(exprs)
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONModify{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    method,
+		}, Transformation: t}, nil
+
+	case *sqlparser.JSONRemoveExpr:
+		args, err := ast.translateFuncArgs(append([]sqlparser.Expr{call.JSONDoc}, call.PathList...))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONModify{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_REMOVE",
+		}, Transformation: json.Remove}, nil
+
+	case *sqlparser.JSONValueMergeExpr:
+		args, err := ast.translateFuncArgs(append([]sqlparser.Expr{call.JSONDoc}, call.JSONDocList...))
+		if err != nil {
+			return nil, err
+		}
+		var method string
+		switch call.Type {
+		case sqlparser.JSONMergeType:
+			method = "JSON_MERGE"
+		case sqlparser.JSONMergePatchType:
+			method = "JSON_MERGE_PATCH"
+		case sqlparser.JSONMergePreserveType:
+			method = "JSON_MERGE_PRESERVE"
+		}
+		return &builtinJSONMerge{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    method,
+		}, Patch: call.Type == sqlparser.JSONMergePatchType}, nil
+
+	case *sqlparser.JSONContainsExpr:
+		if len(call.PathList) > 1 {
+			return nil, argError("json_contains")
+		}
+		args, err := ast.translateFuncArgs(append([]sqlparser.Expr{call.Target, call.Candidate}, call.PathList...))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONContains{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_CONTAINS",
+		}}, nil
+
+	case *sqlparser.JSONOverlapsExpr:
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.JSONDoc1, call.JSONDoc2})
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONOverlaps{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_OVERLAPS",
+		}}, nil
+
+	case *sqlparser.MemberOfExpr:
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.Value, call.JSONArr})
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONMemberOf{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "MEMBER OF",
+		}}, nil
+
+	case *sqlparser.JSONSearchExpr:
+		exprs := []sqlparser.Expr{call.JSONDoc, call.OneOrAll, call.SearchStr}
+		if call.EscapeChar != nil {
+			exprs = append(exprs, call.EscapeChar)
+			exprs = append(exprs, call.PathList...)
+		}
+		args, err := ast.translateFuncArgs(exprs)
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONSearch{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_SEARCH",
+		}}, nil
+
+	case *sqlparser.JSONValueExpr:
+		if call.ReturningType != nil || call.EmptyOnResponse != nil || call.ErrorOnResponse != nil {
+			return nil, translateExprNotSupported(call)
+		}
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.JSONDoc, call.Path})
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONValue{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_VALUE",
+		}}, nil
+
+	case *sqlparser.JSONPrettyExpr:
+		arg, err := ast.translateExpr(call.JSONVal)
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONPretty{CallExpr: CallExpr{
+			Arguments: []IR{arg},
+			Method:    "JSON_PRETTY",
+		}}, nil
+
+	case *sqlparser.PointExpr:
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.XCordinate, call.YCordinate})
+		if err != nil {
+			return nil, err
+		}
+		return &builtinPoint{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "point",
+		}}, nil
+
+	case *sqlparser.GeomFromTextExpr:
+		if call.AxisOrderOpt != nil {
+			return nil, translateExprNotSupported(call)
+		}
+		args, err := ast.translateFuncArgs(optionalArgs(call.WktText, call.Srid))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinGeomFrom{
+			CallExpr: CallExpr{Arguments: args, Method: call.Type.ToString()},
+			Type:     geomFromTypes[call.Type],
+		}, nil
+
+	case *sqlparser.GeomFromWKBExpr:
+		if call.AxisOrderOpt != nil {
+			return nil, translateExprNotSupported(call)
+		}
+		args, err := ast.translateFuncArgs(optionalArgs(call.WkbBlob, call.Srid))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinGeomFrom{
+			CallExpr: CallExpr{Arguments: args, Method: call.Type.ToString()},
+			Type:     geomFromTypes[call.Type],
+			WKB:      true,
+		}, nil
+
+	case *sqlparser.GeomFormatExpr:
+		if call.AxisOrderOpt != nil {
+			return nil, translateExprNotSupported(call)
+		}
+		arg, err := ast.translateExpr(call.Geom)
+		if err != nil {
+			return nil, err
+		}
+		return &builtinGeomFormat{
+			CallExpr: CallExpr{Arguments: []IR{arg}, Method: call.FormatType.ToString()},
+			WKB:      call.FormatType == sqlparser.BinaryFormat,
+			collate:  ast.cfg.Collation,
+		}, nil
+
+	case *sqlparser.GeomPropertyFuncExpr:
+		var prop geomProperty
+		switch call.Property {
+		case sqlparser.IsEmpty:
+			prop = geomIsEmpty
+		case sqlparser.Dimension:
+			prop = geomDimension
+		case sqlparser.GeometryType:
+			prop = geomGeometryType
+		default:
+			return nil, translateExprNotSupported(call)
+		}
+		arg, err := ast.translateExpr(call.Geom)
+		if err != nil {
+			return nil, err
+		}
+		return &builtinGeomProperty{
+			CallExpr: CallExpr{Arguments: []IR{arg}, Method: call.Property.ToString()},
+			Property: prop,
+			collate:  ast.cfg.Collation,
+		}, nil
+
+	case *sqlparser.PointPropertyFuncExpr:
+		// latitudes and longitudes only exist in geographic spatial reference systems
+		if call.Property != sqlparser.XCordinate && call.Property != sqlparser.YCordinate {
+			return nil, translateExprNotSupported(call)
+		}
+		args, err := ast.translateFuncArgs(optionalArgs(call.Point, call.ValueToSet))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinPointCoord{
+			CallExpr: CallExpr{Arguments: args, Method: call.Property.ToString()},
+			Y:        call.Property == sqlparser.YCordinate,
+		}, nil
+
 	case *sqlparser.CurTimeFuncExpr:
 		if call.Fsp > 6 {
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Too-big precision %d specified for '%s'. Maximum is 6.", call.Fsp, call.Name.String())
@@ -1110,6 +1341,29 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 	}
 }
 
+// optionalArgs returns the arguments of a function that has optional trailing arguments
+func optionalArgs(args ...sqlparser.Expr) []sqlparser.Expr {
+	for i, arg := range args {
+		if arg == nil {
+			return args[:i]
+		}
+	}
+	return args
+}
+
+// geomFromTypes maps the ST_*FromText and ST_*FromWKB functions to the type of geometry they
+// return; both kinds of functions are listed in the same order.
+var geomFromTypes = [...]geometry.Type{
+	sqlparser.GeometryFromText:           0,
+	sqlparser.GeometryCollectionFromText: geometry.GeometryCollection,
+	sqlparser.PointFromText:              geometry.Point,
+	sqlparser.LineStringFromText:         geometry.LineString,
+	sqlparser.PolygonFromText:            geometry.Polygon,
+	sqlparser.MultiPointFromText:         geometry.MultiPoint,
+	sqlparser.MultiPolygonFromText:       geometry.MultiPolygon,
+	sqlparser.MultiLinestringFromText:    geometry.MultiLineString,
+}
+
 func builtinJSONExtractUnquoteRewrite(left IR, right IR) (IR, error) {
 	extract, err := builtinJSONExtractRewrite(left, right)
 	if err != nil {
@@ -116,6 +116,9 @@ type Executor struct {
 	plans *PlanCache
 	epoch atomic.Uint32
 
+	// results caches the results of read-only queries, nil if the result cache is disabled
+	results *resultCache
+
 	normalize       bool
 	warnShardedOnly bool
 
@@ -1041,6 +1044,18 @@ func (e *Executor) MessageStream(ctx context.Context, keyspace string, shard str
 	return formatError(err)
 }
 
+// MessageInspect returns the dead-lettered messages of a message table.
+func (e *Executor) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	qr, err := e.resolver.MessageInspect(ctx, keyspace, name, limit)
+	return qr, formatError(err)
+}
+
+// MessageRequeue requeues dead-lettered messages of a message table.
+func (e *Executor) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	count, err := e.resolver.MessageRequeue(ctx, keyspace, name, ids)
+	return count, formatError(err)
+}
+
 // VSchema returns the VSchema.
 func (e *Executor) VSchema() *vindexes.VSchema {
 	e.mu.Lock()
@@ -1114,6 +1129,7 @@ func (e *Executor) getPlan(
 		return nil, err
 	}
 	vcursor.SetPriority(priority)
+	vcursor.SetQueryFingerprint(plancontext.BaselineFingerprint(vcursor.vschema, stmt))
 
 	setVarComment, err := prepareSetVarComment(vcursor, stmt)
 	if err != nil {
@@ -1175,6 +1191,7 @@ func (e *Executor) buildStatement(
 
 	plan.Warnings = vcursor.warnings
 	vcursor.warnings = nil
+	plan.ResultCache = e.results != nil && resultCacheable(stmt, plan, vcursor.vschema)
 
 	err = e.checkThatPlanIsValid(stmt, plan)
 	return plan, err
@@ -1613,6 +1630,9 @@ func (e *Executor) Close() {
 	}
 	topo.Close()
 	e.plans.Close()
+	if e.results != nil {
+		e.results.Close()
+	}
 }
 
 func (e *Executor) environment() *vtenv.Environment {
@@ -33,7 +33,7 @@ import (
 	vtgatepb "github.com/mdibaiee/vitess/go/vt/proto/vtgate"
 	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
-	_ "github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/sandboxconn"
 )
 
@@ -1998,6 +1998,160 @@ func TestInsertLookupOwnedGenerator(t *testing.T) {
 	utils.MustMatch(t, wantResult, result)
 }
 
+func TestReplaceLookupOwned(t *testing.T) {
+	executor, sbc1, sbc2, sbclookup, ctx := createExecutorEnv(t)
+	executor.vschema.Keyspaces["TestExecutor"].Tables["music"].PrimaryKey = sqlparser.Columns{sqlparser.NewIdentifierCI("id")}
+
+	// music 5 currently belongs to user 1, which lives on a different shard than user 3
+	sbclookup.SetResults([]*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("music_id|user_id", "int64|int64"), "5|1"),
+	})
+	sbc1.SetResults([]*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("user_id|id", "int64|int64"), "1|5"),
+		{RowsAffected: 1},
+	})
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+	}
+	_, err := executorExec(ctx, executor, session, "replace into music(user_id, id) values (3, 5)", nil)
+	require.NoError(t, err)
+
+	// the replaced row is deleted from its old shard
+	assertQueries(t, sbc1, []*querypb.BoundQuery{{
+		Sql:           "select user_id, id from music where (id) in ((5)) for update",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}, {
+		Sql:           "delete from music where (id) in ((5))",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}})
+	// and the new row is inserted in the shard of the new user
+	assertQueries(t, sbc2, []*querypb.BoundQuery{{
+		Sql: "insert into music(user_id, id) values (:_user_id_0, :_id_0)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"_user_id_0": sqltypes.Int64BindVariable(3),
+			"_id_0":      sqltypes.Int64BindVariable(5),
+		},
+	}})
+	// the lookup vindex entry is moved from the old user to the new one
+	vals, _ := sqltypes.BuildBindVariable([]int64{5})
+	assertQueries(t, sbclookup, []*querypb.BoundQuery{{
+		Sql:           "select music_id, user_id from music_user_map where music_id in ::music_id for update",
+		BindVariables: map[string]*querypb.BindVariable{"music_id": vals},
+	}, {
+		Sql: "delete from music_user_map where music_id = :music_id and user_id = :user_id",
+		BindVariables: map[string]*querypb.BindVariable{
+			"music_id": sqltypes.Int64BindVariable(5),
+			"user_id":  sqltypes.Uint64BindVariable(1),
+		},
+	}, {
+		Sql: "insert into music_user_map(music_id, user_id) values (:music_id_0, :user_id_0)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"music_id_0": sqltypes.Int64BindVariable(5),
+			"user_id_0":  sqltypes.Uint64BindVariable(3),
+		},
+	}})
+}
+
+func TestUpdatePrimaryVindexMovesRow(t *testing.T) {
+	tcases := []struct {
+		name   string
+		txMode vtgatepb.TransactionMode
+	}{{
+		// the executor of the tests is configured to commit with 2PC by default
+		name:   "two phase commit",
+		txMode: vtgatepb.TransactionMode_UNSPECIFIED,
+	}, {
+		name:   "multi-shard commit",
+		txMode: vtgatepb.TransactionMode_MULTI,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			executor, sbc1, sbc2, sbclookup, ctx := createExecutorEnv(t)
+			music := executor.vschema.Keyspaces["TestExecutor"].Tables["music"]
+			music.PrimaryKey = sqlparser.Columns{sqlparser.NewIdentifierCI("id")}
+			music.Columns = []vindexes.Column{{Name: sqlparser.NewIdentifierCI("user_id")}, {Name: sqlparser.NewIdentifierCI("id")}, {Name: sqlparser.NewIdentifierCI("col")}}
+			music.ColumnListAuthoritative = true
+
+			// music 5 belongs to user 1, which lives on a different shard than user 3
+			sbclookup.SetResults([]*sqltypes.Result{
+				sqltypes.MakeTestResult(sqltypes.MakeTestFields("music_id|user_id", "int64|int64"), "5|1"),
+				sqltypes.MakeTestResult(sqltypes.MakeTestFields("music_id|user_id", "int64|int64"), "5|1"),
+			})
+			sbc1.SetResults([]*sqltypes.Result{
+				sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|col", "int64|varchar"), "5|a"),
+				sqltypes.MakeTestResult(sqltypes.MakeTestFields("user_id|id", "int64|int64"), "1|5"),
+				{RowsAffected: 1},
+			})
+			session := &vtgatepb.Session{
+				TargetString:    "@primary",
+				Autocommit:      true,
+				TransactionMode: tcase.txMode,
+			}
+			qr, err := executorExec(ctx, executor, session, "update music set user_id = 3 where id = 5", nil)
+			require.NoError(t, err)
+			require.EqualValues(t, 1, qr.RowsAffected)
+			require.False(t, session.InTransaction)
+
+			dmlVals, _ := sqltypes.BuildBindVariable([]int64{5})
+			// the row is read and deleted from the shard of the old user
+			assertQueries(t, sbc1, []*querypb.BoundQuery{{
+				Sql:           "select music.id, music.col from music where id = 5 for update",
+				BindVariables: map[string]*querypb.BindVariable{},
+			}, {
+				Sql:           "select user_id, id from music where music.id in ::dml_vals for update",
+				BindVariables: map[string]*querypb.BindVariable{"dml_vals": dmlVals},
+			}, {
+				Sql:           "delete from music where music.id in ::dml_vals",
+				BindVariables: map[string]*querypb.BindVariable{"__vals": dmlVals, "dml_vals": dmlVals},
+			}})
+			// and inserted in the shard of the new user
+			assertQueries(t, sbc2, []*querypb.BoundQuery{{
+				Sql: "insert into music(user_id, id, col) values (:_user_id_0, :_id_0, :music_col)",
+				BindVariables: map[string]*querypb.BindVariable{
+					"_user_id_0": sqltypes.Int64BindVariable(3),
+					"_id_0":      sqltypes.Int64BindVariable(5),
+					"music_col":  sqltypes.StringBindVariable("a"),
+				},
+			}})
+			// the lookup vindex entry is moved from the old user to the new one
+			assertQueries(t, sbclookup, []*querypb.BoundQuery{{
+				Sql:           "select music_id, user_id from music_user_map where music_id in ::music_id",
+				BindVariables: map[string]*querypb.BindVariable{"music_id": dmlVals},
+			}, {
+				Sql:           "select music_id, user_id from music_user_map where music_id in ::music_id for update",
+				BindVariables: map[string]*querypb.BindVariable{"music_id": dmlVals},
+			}, {
+				Sql: "delete from music_user_map where music_id = :music_id and user_id = :user_id",
+				BindVariables: map[string]*querypb.BindVariable{
+					"music_id": sqltypes.Int64BindVariable(5),
+					"user_id":  sqltypes.Uint64BindVariable(1),
+				},
+			}, {
+				Sql: "insert into music_user_map(music_id, user_id) values (:music_id_0, :user_id_0)",
+				BindVariables: map[string]*querypb.BindVariable{
+					"music_id_0": sqltypes.Int64BindVariable(5),
+					"user_id_0":  sqltypes.Uint64BindVariable(3),
+				},
+			}})
+
+			// all the changes are committed together
+			if tcase.txMode == vtgatepb.TransactionMode_MULTI {
+				for _, sbc := range []*sandboxconn.SandboxConn{sbc1, sbc2, sbclookup} {
+					assert.EqualValues(t, 1, sbc.CommitCount.Load())
+				}
+				return
+			}
+			assert.EqualValues(t, 1, sbclookup.CreateTransactionCount.Load())
+			assert.EqualValues(t, 1, sbclookup.StartCommitCount.Load())
+			for _, sbc := range []*sandboxconn.SandboxConn{sbc1, sbc2} {
+				assert.EqualValues(t, 1, sbc.PrepareCount.Load())
+				assert.EqualValues(t, 1, sbc.CommitPreparedCount.Load())
+				assert.EqualValues(t, 0, sbc.CommitCount.Load())
+			}
+		})
+	}
+}
+
 func TestInsertLookupUnowned(t *testing.T) {
 	executor, sbc, _, sbclookup, ctx := createExecutorEnv(t)
 
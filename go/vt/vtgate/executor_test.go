@@ -2284,6 +2284,28 @@ func TestExecutorVExplain(t *testing.T) {
 	require.Equal(t, expected, fmt.Sprintf("%v", result.Rows))
 }
 
+func TestExecutorVExplainPlanBaseline(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	executor.normalize = true
+
+	fp, err := sqlparser.NewTestParser().FingerprintQuery("select id from user where name = 'apa'")
+	require.NoError(t, err)
+	executor.VSchema().PlanBaselines = map[string]*vschemapb.PlanBaseline{
+		fp: {Fingerprint: fp, DisableHashJoin: true},
+	}
+
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+	}
+	result, err := executorExec(ctx, executor, session, "vexplain plan select id from user where name = 'bob'", nil)
+	require.NoError(t, err)
+	require.Contains(t, result.Rows[0][0].ToString(), fmt.Sprintf(`"PlanBaseline": "%s"`, fp))
+
+	result, err = executorExec(ctx, executor, session, "vexplain plan select id, name from user where name = 'bob'", nil)
+	require.NoError(t, err)
+	require.NotContains(t, result.Rows[0][0].ToString(), "PlanBaseline")
+}
+
 func TestExecutorOtherAdmin(t *testing.T) {
 	executor, sbc1, sbc2, sbclookup, _ := createExecutorEnv(t)
 
@@ -187,6 +187,16 @@ func (conn *FakeVTGateConn) ResolveTransaction(ctx context.Context, dtid string)
 	return nil
 }
 
+// MessageInspect please see vtgateconn.Impl.MessageInspect
+func (conn *FakeVTGateConn) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	panic("not implemented")
+}
+
+// MessageRequeue please see vtgateconn.Impl.MessageRequeue
+func (conn *FakeVTGateConn) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	panic("not implemented")
+}
+
 // VStream streams binlog events.
 func (conn *FakeVTGateConn) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
 	filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags) (vtgateconn.VStreamReader, error) {
@@ -243,6 +243,34 @@ func (conn *vtgateConn) ResolveTransaction(ctx context.Context, dtid string) err
 	return vterrors.FromGRPC(err)
 }
 
+func (conn *vtgateConn) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	request := &vtgatepb.MessageInspectRequest{
+		CallerId: callerid.EffectiveCallerIDFromContext(ctx),
+		Keyspace: keyspace,
+		Name:     name,
+		Limit:    limit,
+	}
+	response, err := conn.c.MessageInspect(ctx, request)
+	if err != nil {
+		return nil, vterrors.FromGRPC(err)
+	}
+	return sqltypes.Proto3ToResult(response.Result), nil
+}
+
+func (conn *vtgateConn) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	request := &vtgatepb.MessageRequeueRequest{
+		CallerId: callerid.EffectiveCallerIDFromContext(ctx),
+		Keyspace: keyspace,
+		Name:     name,
+		Ids:      ids,
+	}
+	response, err := conn.c.MessageRequeue(ctx, request)
+	if err != nil {
+		return 0, vterrors.FromGRPC(err)
+	}
+	return response.Count, nil
+}
+
 type vstreamAdapter struct {
 	stream vtgateservicepb.Vitess_VStreamClient
 }
@@ -246,6 +246,36 @@ func (f *fakeVTGateService) ResolveTransaction(ctx context.Context, dtid string)
 	return nil
 }
 
+// MessageInspect is part of the VTGateService interface
+func (f *fakeVTGateService) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	if f.hasError {
+		return nil, errTestVtGateError
+	}
+	if f.panics {
+		panic(fmt.Errorf("test forced panic"))
+	}
+	f.checkCallerID(ctx, "MessageInspect")
+	if keyspace != messageKeyspace || name != messageName || limit != 10 {
+		return nil, fmt.Errorf("MessageInspect: unexpected request %s.%s limit %d", keyspace, name, limit)
+	}
+	return &result1, nil
+}
+
+// MessageRequeue is part of the VTGateService interface
+func (f *fakeVTGateService) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	if f.hasError {
+		return 0, errTestVtGateError
+	}
+	if f.panics {
+		panic(fmt.Errorf("test forced panic"))
+	}
+	f.checkCallerID(ctx, "MessageRequeue")
+	if keyspace != messageKeyspace || name != messageName || !sqltypes.Proto3ValuesEqual(ids, messageIDs) {
+		return 0, fmt.Errorf("MessageRequeue: unexpected request %s.%s ids %v", keyspace, name, ids)
+	}
+	return int64(len(ids)), nil
+}
+
 func (f *fakeVTGateService) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error {
 	panic("unimplemented")
 }
@@ -293,12 +323,17 @@ func RunTests(t *testing.T, impl vtgateconn.Impl, fakeServer vtgateservice.VTGat
 	testExecuteBatch(t, session)
 	testPrepare(t, session)
 
+	testMessageInspect(t, conn)
+	testMessageRequeue(t, conn)
+
 	// force a panic at every call, then test that works
 	fs.panics = true
 	testExecutePanic(t, session)
 	testExecuteBatchPanic(t, session)
 	testStreamExecutePanic(t, session)
 	testPreparePanic(t, session)
+	testMessageInspectPanic(t, conn)
+	testMessageRequeuePanic(t, conn)
 	fs.panics = false
 }
 
@@ -318,6 +353,8 @@ func RunErrorTests(t *testing.T, fakeServer vtgateservice.VTGateService) {
 	testExecuteBatchError(t, session, fs)
 	testStreamExecuteError(t, session, fs)
 	testPrepareError(t, session, fs)
+	testMessageInspectError(t, conn)
+	testMessageRequeueError(t, conn)
 	fs.hasError = false
 }
 
@@ -600,3 +637,51 @@ var streamResultFields = sqltypes.Result{
 }
 
 var dtid2 = "aa"
+
+var (
+	messageKeyspace = "ks"
+	messageName     = "msg"
+	messageIDs      = []*querypb.Value{{Type: sqltypes.VarChar, Value: []byte("1")}}
+)
+
+func testMessageInspect(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	qr, err := conn.MessageInspect(ctx, messageKeyspace, messageName, 10)
+	require.NoError(t, err)
+	if !qr.Equal(&result1) {
+		t.Errorf("Unexpected result from MessageInspect: got\n%#v want\n%#v", qr, &result1)
+	}
+}
+
+func testMessageInspectError(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	_, err := conn.MessageInspect(ctx, messageKeyspace, messageName, 10)
+	verifyError(t, err, "MessageInspect")
+}
+
+func testMessageInspectPanic(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	_, err := conn.MessageInspect(ctx, messageKeyspace, messageName, 10)
+	expectPanic(t, err)
+}
+
+func testMessageRequeue(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	count, err := conn.MessageRequeue(ctx, messageKeyspace, messageName, messageIDs)
+	require.NoError(t, err)
+	if count != 1 {
+		t.Errorf("Unexpected result from MessageRequeue: got %v want 1", count)
+	}
+}
+
+func testMessageRequeueError(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	_, err := conn.MessageRequeue(ctx, messageKeyspace, messageName, messageIDs)
+	verifyError(t, err, "MessageRequeue")
+}
+
+func testMessageRequeuePanic(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	_, err := conn.MessageRequeue(ctx, messageKeyspace, messageName, messageIDs)
+	expectPanic(t, err)
+}
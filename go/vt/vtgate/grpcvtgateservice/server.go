@@ -259,6 +259,32 @@ func (vtg *VTGate) ResolveTransaction(ctx context.Context, request *vtgatepb.Res
 	return nil, vterrors.ToGRPC(vtgErr)
 }
 
+// MessageInspect is the RPC version of vtgateservice.VTGateService method
+func (vtg *VTGate) MessageInspect(ctx context.Context, request *vtgatepb.MessageInspectRequest) (response *vtgatepb.MessageInspectResponse, err error) {
+	defer vtg.server.HandlePanic(&err)
+	ctx = withCallerIDContext(ctx, request.CallerId)
+	qr, vtgErr := vtg.server.MessageInspect(ctx, request.Keyspace, request.Name, request.Limit)
+	if vtgErr != nil {
+		return nil, vterrors.ToGRPC(vtgErr)
+	}
+	return &vtgatepb.MessageInspectResponse{
+		Result: sqltypes.ResultToProto3(qr),
+	}, nil
+}
+
+// MessageRequeue is the RPC version of vtgateservice.VTGateService method
+func (vtg *VTGate) MessageRequeue(ctx context.Context, request *vtgatepb.MessageRequeueRequest) (response *vtgatepb.MessageRequeueResponse, err error) {
+	defer vtg.server.HandlePanic(&err)
+	ctx = withCallerIDContext(ctx, request.CallerId)
+	count, vtgErr := vtg.server.MessageRequeue(ctx, request.Keyspace, request.Name, request.Ids)
+	if vtgErr != nil {
+		return nil, vterrors.ToGRPC(vtgErr)
+	}
+	return &vtgatepb.MessageRequeueResponse{
+		Count: count,
+	}, nil
+}
+
 // VStream is the RPC version of vtgateservice.VTGateService method
 func (vtg *VTGate) VStream(request *vtgatepb.VStreamRequest, stream vtgateservicepb.Vitess_VStreamServer) (err error) {
 	defer vtg.server.HandlePanic(&err)
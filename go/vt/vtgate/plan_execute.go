@@ -317,6 +317,18 @@ func (e *Executor) executePlan(
 	execStart time.Time,
 ) (*sqltypes.Result, error) {
 
+	var cacheKey ResultCacheKey
+	cacheResult := false
+	if plan.ResultCache && e.results != nil {
+		cacheKey, cacheResult = e.results.key(ctx, vcursor, plan, bindVars)
+		if cacheResult {
+			if qr, ok := e.results.get(cacheKey); ok {
+				e.setLogStats(logStats, plan, vcursor, execStart, nil, qr)
+				return qr, nil
+			}
+		}
+	}
+
 	// 4: Execute!
 	qr, err := vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
 
@@ -327,6 +339,9 @@ func (e *Executor) executePlan(
 	if err != nil {
 		return nil, e.rollbackExecIfNeeded(ctx, safeSession, bindVars, logStats, err)
 	}
+	if cacheResult {
+		e.results.set(cacheKey, qr)
+	}
 	return qr, nil
 }
 
@@ -82,6 +82,12 @@ func TestBuilder(query string, vschema plancontext.VSchema, keyspace string) (*e
 				vw.ForeignKeyChecksState = oldVal
 			}()
 		}
+		// Store the fingerprint of the statement before it is rewritten, like we do for vcursor.
+		oldFp := vw.QueryFingerprint
+		vw.QueryFingerprint = plancontext.BaselineFingerprint(vw.V, stmt)
+		defer func() {
+			vw.QueryFingerprint = oldFp
+		}()
 	}
 	result, err := sqlparser.RewriteAST(stmt, keyspace, sqlparser.SQLSelectLimitUnset, "", nil, vschema.GetForeignKeyChecksState(), vschema)
 	if err != nil {
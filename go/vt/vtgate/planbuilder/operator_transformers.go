@@ -18,6 +18,7 @@ package planbuilder
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -34,6 +35,7 @@ import (
 	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/operators"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/semantics"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -63,6 +65,12 @@ func transformToPrimitive(ctx *plancontext.PlanningContext, op operators.Operato
 		return transformAggregator(ctx, op)
 	case *operators.Distinct:
 		return transformDistinct(ctx, op)
+	case *operators.Window:
+		return transformWindow(ctx, op)
+	case *operators.JSONTable:
+		return transformJSONTable(ctx, op)
+	case *operators.RecurseCTE:
+		return transformRecurseCTE(ctx, op)
 	case *operators.FkCascade:
 		return transformFkCascade(ctx, op)
 	case *operators.FkVerify:
@@ -102,6 +110,7 @@ func transformDMLWithInput(ctx *plancontext.PlanningContext, op *operators.DMLWi
 		Input:      input,
 		OutputCols: op.Offsets,
 		BVList:     op.BvList,
+		MoveRows:   op.MoveRows,
 	}, nil
 }
 
@@ -248,15 +257,32 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 			Opcode:         op.FilterType,
 			SubqueryResult: op.SubqueryValueName,
 			HasValues:      op.HasValuesName,
+			MaxResult:      op.MaxValueName,
+			HasNulls:       op.HasNullsName,
+			ComparisonType: op.ComparisonType,
 			Subquery:       inner,
 			Outer:          outer,
 		}, nil
 	}
 
+	if op.FilterWithOffsets == nil && op.ValueOffset == nil {
+		return &engine.SemiJoin{
+			Left:  outer,
+			Right: inner,
+			Vars:  op.Vars,
+		}, nil
+	}
+
 	return &engine.SemiJoin{
-		Left:  outer,
-		Right: inner,
-		Vars:  op.Vars,
+		Left:           outer,
+		Right:          inner,
+		Vars:           op.Vars,
+		Opcode:         op.FilterType,
+		SubqueryResult: op.SubqueryValueName,
+		HasValues:      op.HasValuesName,
+		Filter:         op.FilterWithOffsets,
+		ASTFilter:      op.ASTFilter,
+		ValueCol:       op.ValueOffset,
 	}, nil
 }
 
@@ -292,9 +318,6 @@ func transformFkVerify(ctx *plancontext.PlanningContext, fkv *operators.FkVerify
 }
 
 func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggregator) (engine.Primitive, error) {
-	if op.WithRollup {
-		return nil, vterrors.VT12001("GROUP BY WITH ROLLUP not supported for sharded queries")
-	}
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
 		return nil, err
@@ -307,12 +330,33 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		if aggr.OpCode == opcode.AggregateUnassigned {
 			return nil, vterrors.VT12001(fmt.Sprintf("in scatter query: aggregation function '%s'", sqlparser.String(aggr.Original)))
 		}
+		if op.WithRollup {
+			if err := checkRollupAggregation(ctx, op, aggr); err != nil {
+				return nil, err
+			}
+		}
 		aggrParam := engine.NewAggregateParam(aggr.OpCode, aggr.ColOffset, aggr.Alias, ctx.VSchema.Environment().CollationEnv())
 		aggrParam.Expr = aggr.Func
 		aggrParam.Original = aggr.Original
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		aggrParam.DistinctCols = aggr.DistinctCols
+		aggrParam.ArgCols = aggr.ArgOffsets
+		aggrParam.OrderBy = aggr.OrderBy
+		if gc, ok := aggr.Func.(*sqlparser.GroupConcatExpr); ok && gc.Separator != "" {
+			separator, err := sqltypes.DecodeStringSQL(gc.Separator)
+			if err != nil {
+				return nil, err
+			}
+			aggrParam.Separator = &separator
+		}
+		if aggr.OpCode == opcode.AggregateGrouping {
+			aggrParam.GroupingKeys, err = groupingKeysFor(ctx, op, aggr)
+			if err != nil {
+				return nil, err
+			}
+		}
 		aggregates = append(aggregates, aggrParam)
 	}
 
@@ -339,10 +383,54 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		Aggregates:          aggregates,
 		GroupByKeys:         groupByKeys,
 		TruncateColumnCount: op.ResultColumns,
+		WithRollup:          op.WithRollup,
 		Input:               src,
 	}, nil
 }
 
+// checkRollupAggregation makes sure the aggregation can be computed for the super-aggregate rows
+// of WITH ROLLUP, which are produced by merging the rows of the regular groups
+func checkRollupAggregation(ctx *plancontext.PlanningContext, op *operators.Aggregator, aggr operators.Aggr) error {
+	if aggr.OpCode != opcode.AggregateAnyValue {
+		return nil
+	}
+
+	// the grouping columns are set to NULL in the super-aggregate rows, but expressions using them are not
+	usesGrouping := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		expr, ok := node.(sqlparser.Expr)
+		if !ok || usesGrouping {
+			return !usesGrouping, nil
+		}
+		usesGrouping = slices.ContainsFunc(op.Grouping, func(gb operators.GroupBy) bool {
+			return ctx.SemTable.EqualsExprWithDeps(gb.Inner, expr)
+		})
+		return !usesGrouping, nil
+	}, aggr.Original.Expr)
+	if usesGrouping {
+		return vterrors.VT12001(fmt.Sprintf("expression using a grouping column with GROUP BY WITH ROLLUP in scatter query: %s", sqlparser.String(aggr.Original)))
+	}
+	return nil
+}
+
+// groupingKeysFor returns the offsets into the grouping of the aggregator for every argument of GROUPING()
+func groupingKeysFor(ctx *plancontext.PlanningContext, op *operators.Aggregator, aggr operators.Aggr) ([]int, error) {
+	if !op.WithRollup {
+		return nil, vterrors.VT03037()
+	}
+	var keys []int
+	for idx, arg := range aggr.Func.GetArgs() {
+		key := slices.IndexFunc(op.Grouping, func(gb operators.GroupBy) bool {
+			return ctx.SemTable.EqualsExprWithDeps(gb.Inner, arg)
+		})
+		if key < 0 {
+			return nil, vterrors.VT03036(idx + 1)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct) (engine.Primitive, error) {
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -356,6 +444,61 @@ func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct)
 	}, nil
 }
 
+func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (engine.Primitive, error) {
+	src, err := transformToPrimitive(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	collationEnv := ctx.VSchema.Environment().CollationEnv()
+	comparison := func(exprs []sqlparser.Expr, offsets []operators.WindowOffset, desc func(int) bool) evalengine.Comparison {
+		var cmp evalengine.Comparison
+		for idx, expr := range exprs {
+			typ, _ := ctx.TypeForExpr(expr)
+			cmp = append(cmp, evalengine.OrderByParams{
+				Col:             offsets[idx].Offset,
+				WeightStringCol: offsets[idx].WOffset,
+				Desc:            desc(idx),
+				Type:            typ,
+				CollationEnv:    collationEnv,
+			})
+		}
+		return cmp
+	}
+
+	prim := &engine.Window{Input: src}
+	for _, f := range op.Funcs {
+		wf := &engine.WindowFunc{
+			Opcode:     f.OpCode,
+			Col:        f.ArgOffset,
+			DefaultCol: f.DefaultOffset,
+			N:          f.N,
+			Partition: comparison(f.Spec.PartitionClause, f.PartitionOffsets, func(int) bool {
+				return false
+			}),
+			Frame: f.Frame,
+			Alias: sqlparser.String(f.Original),
+		}
+		orderExprs := slice.Map(f.Spec.OrderClause, func(o *sqlparser.Order) sqlparser.Expr { return o.Expr })
+		wf.OrderBy = comparison(orderExprs, f.OrderOffsets, func(idx int) bool {
+			return f.Spec.OrderClause[idx].Direction == sqlparser.DescOrder
+		})
+		if f.OpCode == opcode.WindowAggregate {
+			col := f.ArgOffset
+			if col < 0 {
+				// COUNT(*) doesn't read any column
+				col = 0
+			}
+			wf.Aggregate = engine.NewAggregateParam(f.AggrOpCode, col, wf.Alias, collationEnv)
+			if f.Arg != nil {
+				wf.Aggregate.Type, _ = ctx.TypeForExpr(f.Arg)
+			}
+		}
+		prim.Functions = append(prim.Functions, wf)
+	}
+	return prim, nil
+}
+
 func transformOrdering(ctx *plancontext.PlanningContext, op *operators.Ordering) (engine.Primitive, error) {
 	plan, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -455,6 +598,99 @@ func newSimpleProjection(cols []int, colNames []string, src engine.Primitive) en
 	}
 }
 
+func transformJSONTable(ctx *plancontext.PlanningContext, op *operators.JSONTable) (engine.Primitive, error) {
+	src, err := transformToPrimitive(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := jsonTableLiteral(op.AST.Filter, "path")
+	if err != nil {
+		return nil, err
+	}
+	columns, err := jsonTableColumns(ctx, op.AST.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &engine.JSONTable{
+		Input:       src,
+		JSONExpr:    op.JSONExpr,
+		ASTJSONExpr: op.AST.Expr,
+		Path:        path,
+		Columns:     columns,
+		Outer:       op.Outer,
+	}, nil
+}
+
+func jsonTableColumns(ctx *plancontext.PlanningContext, defs []*sqlparser.JtColumnDefinition) ([]*engine.JSONTableColumn, error) {
+	collationEnv := ctx.VSchema.Environment().CollationEnv()
+	var columns []*engine.JSONTableColumn
+	for _, def := range defs {
+		switch {
+		case def.JtOrdinal != nil:
+			columns = append(columns, &engine.JSONTableColumn{
+				Name:       def.JtOrdinal.Name.String(),
+				Type:       evalengine.NewType(sqltypes.Uint32, collations.CollationBinaryID),
+				Ordinality: true,
+			})
+		case def.JtPath != nil:
+			path, err := jsonTableLiteral(def.JtPath.Path, "path")
+			if err != nil {
+				return nil, err
+			}
+			col := &engine.JSONTableColumn{
+				Name:   def.JtPath.Name.String(),
+				Type:   semantics.JSONTableColumnType(def.JtPath, collationEnv),
+				Exists: def.JtPath.JtColExists,
+				Path:   path,
+			}
+			if col.OnEmpty, err = jsonTableResponse(def.JtPath.EmptyOnResponse); err != nil {
+				return nil, err
+			}
+			if col.OnError, err = jsonTableResponse(def.JtPath.ErrorOnResponse); err != nil {
+				return nil, err
+			}
+			columns = append(columns, col)
+		case def.JtNestedPath != nil:
+			path, err := jsonTableLiteral(def.JtNestedPath.Path, "path")
+			if err != nil {
+				return nil, err
+			}
+			nested, err := jsonTableColumns(ctx, def.JtNestedPath.Columns)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, &engine.JSONTableColumn{Path: path, Nested: nested})
+		}
+	}
+	return columns, nil
+}
+
+func jsonTableResponse(response *sqlparser.JtOnResponse) (*engine.JSONTableResponse, error) {
+	if response == nil || response.ResponseType == sqlparser.NullJSONType {
+		return nil, nil
+	}
+	res := &engine.JSONTableResponse{Type: response.ResponseType}
+	if response.ResponseType == sqlparser.DefaultJSONType {
+		def, err := jsonTableLiteral(response.Expr, "default value")
+		if err != nil {
+			return nil, err
+		}
+		res.Default = def
+	}
+	return res, nil
+}
+
+// jsonTableLiteral returns the value of a string literal used by a JSON_TABLE evaluated at the vtgate level
+func jsonTableLiteral(expr sqlparser.Expr, usage string) (string, error) {
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.StrVal {
+		return "", vterrors.VT12001(fmt.Sprintf("JSON_TABLE with a %s that is not a string literal: %s", usage, sqlparser.String(expr)))
+	}
+	return lit.Val, nil
+}
+
 func transformFilter(ctx *plancontext.PlanningContext, op *operators.Filter) (engine.Primitive, error) {
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -672,16 +908,20 @@ func autoIncGenerate(gen *operators.Generate) *engine.Generate {
 
 func generateInsertShardedQuery(ins *sqlparser.Insert) (prefix string, mids sqlparser.Values, suffix sqlparser.OnDup) {
 	mids, isValues := ins.Rows.(sqlparser.Values)
-	prefixFormat := "insert %v%sinto %v%v "
+	prefixFormat := "%s %v%sinto %v%v "
 	if isValues {
 		// the mid values are filled differently
 		// with select uses sqlparser.String for sqlparser.Values
 		// with rows uses string.
 		prefixFormat += "values "
 	}
+	action := sqlparser.InsertStr
+	if ins.Action == sqlparser.ReplaceAct {
+		action = sqlparser.ReplaceStr
+	}
 	prefixBuf := sqlparser.NewTrackedBuffer(dmlFormatter)
 	prefixBuf.Myprintf(prefixFormat,
-		ins.Comments, ins.Ignore.ToString(),
+		action, ins.Comments, ins.Ignore.ToString(),
 		ins.Table, ins.Columns, ins.RowAlias)
 	prefix = prefixBuf.String()
 
@@ -853,6 +1093,23 @@ func transformUnionPlan(ctx *plancontext.PlanningContext, op *operators.Union) (
 	return engine.NewConcatenate(sources, nil), nil
 }
 
+func transformRecurseCTE(ctx *plancontext.PlanningContext, op *operators.RecurseCTE) (engine.Primitive, error) {
+	seed, err := transformToPrimitive(ctx, op.Seed)
+	if err != nil {
+		return nil, err
+	}
+	term, err := transformToPrimitive(ctx, op.Term)
+	if err != nil {
+		return nil, err
+	}
+	return &engine.RecurseCTE{
+		Seed:      seed,
+		Term:      term,
+		Vars:      op.Vars,
+		CheckCols: op.CheckCols,
+	}, nil
+}
+
 func transformLimit(ctx *plancontext.PlanningContext, op *operators.Limit) (engine.Primitive, error) {
 	plan, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -307,18 +307,27 @@ func (ts *tableSorter) Len() int {
 
 // Less implements the Sort interface
 func (ts *tableSorter) Less(i, j int) bool {
-	lhs := ts.sel.From[i]
-	rhs := ts.sel.From[j]
-	left, ok := lhs.(*sqlparser.AliasedTableExpr)
+	left, ok := ts.tableOffset(ts.sel.From[i])
 	if !ok {
 		return i < j
 	}
-	right, ok := rhs.(*sqlparser.AliasedTableExpr)
+	right, ok := ts.tableOffset(ts.sel.From[j])
 	if !ok {
 		return i < j
 	}
 
-	return ts.tbl.TableSetFor(left).TableOffset() < ts.tbl.TableSetFor(right).TableOffset()
+	return left < right
+}
+
+func (ts *tableSorter) tableOffset(expr sqlparser.TableExpr) (int, bool) {
+	switch expr := expr.(type) {
+	case *sqlparser.AliasedTableExpr:
+		return ts.tbl.TableSetFor(expr).TableOffset(), true
+	case *sqlparser.JSONTableExpr:
+		_, id := ts.tbl.JSONTableFor(expr)
+		return id.TableOffset(), true
+	}
+	return 0, false
 }
 
 // Swap implements the Sort interface
@@ -348,6 +357,7 @@ func stripDownQuery(from, to sqlparser.SelectStatement) {
 		toNode.OrderBy = node.OrderBy
 		toNode.Comments = node.Comments
 		toNode.Limit = node.Limit
+		toNode.Windows = node.Windows
 		toNode.SelectExprs = node.SelectExprs
 		for _, expr := range toNode.SelectExprs {
 			removeKeyspaceFromSelectExpr(expr)
@@ -393,6 +403,8 @@ func buildQuery(op Operator, qb *queryBuilder) {
 	case *Distinct:
 		buildQuery(op.Source, qb)
 		qb.asSelectStatement().MakeDistinct()
+	case *JSONTable:
+		buildJSONTable(op, qb)
 	case *Update:
 		buildUpdate(op, qb)
 	case *Delete:
@@ -586,6 +598,16 @@ func buildDerived(op *Horizon, qb *queryBuilder) {
 	panic(fmt.Sprintf("unknown select statement type: %T", stmt))
 }
 
+// derivedTableID returns the table id the derived table is registered with in the generated query.
+// A LATERAL derived table has to stay after the tables it references when the tables are sorted,
+// so it is registered with its own table id.
+func derivedTableID(op *Horizon) semantics.TableSet {
+	if op.Lateral {
+		return *op.TableId
+	}
+	return TableID(op)
+}
+
 func buildDerivedUnion(op *Horizon, qb *queryBuilder, union *sqlparser.Union) {
 	opQuery, ok := op.Query.(*sqlparser.Union)
 	if !ok {
@@ -596,8 +618,9 @@ func buildDerivedUnion(op *Horizon, qb *queryBuilder, union *sqlparser.Union) {
 	union.OrderBy = opQuery.OrderBy
 	union.Distinct = opQuery.Distinct
 
-	qb.addTableExpr(op.Alias, op.Alias, TableID(op), &sqlparser.DerivedTable{
-		Select: union,
+	qb.addTableExpr(op.Alias, op.Alias, derivedTableID(op), &sqlparser.DerivedTable{
+		Select:  union,
+		Lateral: op.Lateral,
 	}, nil, op.ColumnAliases)
 }
 
@@ -611,15 +634,57 @@ func buildDerivedSelect(op *Horizon, qb *queryBuilder, sel *sqlparser.Select) {
 	sel.GroupBy = opQuery.GroupBy
 	sel.Having = mergeHaving(sel.Having, opQuery.Having)
 	sel.SelectExprs = opQuery.SelectExprs
+	sel.Windows = opQuery.Windows
 	sel.Distinct = opQuery.Distinct
-	qb.addTableExpr(op.Alias, op.Alias, TableID(op), &sqlparser.DerivedTable{
-		Select: sel,
+	qb.addTableExpr(op.Alias, op.Alias, derivedTableID(op), &sqlparser.DerivedTable{
+		Select:  sel,
+		Lateral: op.Lateral,
 	}, nil, op.ColumnAliases)
 	for _, col := range op.Columns {
 		qb.addProjection(&sqlparser.AliasedExpr{Expr: col})
 	}
 }
 
+func buildJSONTable(op *JSONTable, qb *queryBuilder) {
+	buildQuery(op.Source, qb)
+
+	stmt := qb.stmt.(FromStatement)
+	from := stmt.GetFrom()
+	if isDualFrom(from) {
+		// dual can't be used together with other tables
+		from = nil
+	}
+
+	jtExpr := op.AST
+	if !op.Outer || len(from) == 0 {
+		stmt.SetFrom(append(from, jtExpr))
+		return
+	}
+
+	var lhs sqlparser.TableExpr = &sqlparser.ParenTableExpr{Exprs: from}
+	if len(from) == 1 {
+		lhs = from[0]
+	}
+	stmt.SetFrom([]sqlparser.TableExpr{&sqlparser.JoinTableExpr{
+		LeftExpr:  lhs,
+		RightExpr: jtExpr,
+		Join:      sqlparser.LeftJoinType,
+		Condition: &sqlparser.JoinCondition{On: sqlparser.BoolVal(true)},
+	}})
+}
+
+func isDualFrom(from []sqlparser.TableExpr) bool {
+	if len(from) != 1 {
+		return false
+	}
+	tbl, ok := from[0].(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return false
+	}
+	name, ok := tbl.Expr.(sqlparser.TableName)
+	return ok && name.Qualifier.IsEmpty() && name.Name.String() == "dual"
+}
+
 func buildHorizon(op *Horizon, qb *queryBuilder) {
 	buildQuery(op.Source, qb)
 	stripDownQuery(op.Query, qb.asSelectStatement())
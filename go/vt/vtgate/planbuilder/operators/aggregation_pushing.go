@@ -27,20 +27,14 @@ import (
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
 )
 
-func errDistinctAggrWithMultiExpr(f sqlparser.AggrFunc) {
-	if f == nil {
-		panic(vterrors.VT12001("distinct aggregation function with multiple expressions"))
-	}
-	panic(vterrors.VT12001(fmt.Sprintf("distinct aggregation function with multiple expressions '%s'", sqlparser.String(f))))
-}
-
 func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator) (output Operator, applyResult *ApplyResult) {
 	if aggregator.Pushed {
 		return aggregator, NoRewrite
 	}
 
-	// this rewrite is always valid, and we should do it whenever possible
-	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || overlappingUniqueVindex(ctx, aggregator.Grouping)) {
+	// this rewrite is always valid, and we should do it whenever possible.
+	// WITH ROLLUP is the exception: every shard would produce its own super-aggregate rows
+	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || !aggregator.WithRollup && overlappingUniqueVindex(ctx, aggregator.Grouping)) {
 		return Swap(aggregator, route, "push down aggregation under route - remove original")
 	}
 
@@ -55,6 +49,11 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		return splitAvgAggregations(ctx, aggregator)
 	}
 
+	if slices.ContainsFunc(aggregator.Aggregations, Aggr.needsAllRows) {
+		// we can't split this aggregation, so it has to be computed at the vtgate level
+		return aggregator, NoRewrite
+	}
+
 	switch src := aggregator.Source.(type) {
 	case *Route:
 		// if we have a single sharded route, we can push it down
@@ -141,6 +140,10 @@ func aggregateTheAggregate(a *Aggregator, i int) {
 		// Think of it as we are SUMming together a bunch of distributed COUNTs.
 		aggr.OriginalOpCode, aggr.OpCode = aggr.OpCode, opcode.AggregateSum
 		a.Aggregations[i] = aggr
+	case opcode.AggregateJSONArrayAgg, opcode.AggregateJSONObjectAgg:
+		// the JSON documents produced below are merged into one, instead of being aggregated as values
+		aggr.OriginalOpCode = aggr.OpCode
+		a.Aggregations[i] = aggr
 	}
 }
 
@@ -170,45 +173,56 @@ func pushAggregationThroughRoute(
 // pushAggregations splits aggregations between the original aggregator and the one we are pushing down
 func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator, aggrBelowRoute *Aggregator) {
 	canPushDistinctAggr, distinctExprs := checkIfWeCanPush(ctx, aggregator)
+	hashedDistinct := !canPushDistinctAggr && aggregator.needsHashedDistinct(ctx)
 
 	distinctAggrGroupByAdded := false
 
 	for i, aggr := range aggregator.Aggregations {
+		if aggr.OpCode == opcode.AggregateGrouping {
+			// there is no rollup below the route, so we only need a placeholder column there
+			placeholder := aeWrap(aggr.getPushColumn())
+			aggrBelowRoute.Columns[aggr.ColOffset] = placeholder
+			anyValue := NewAggr(opcode.AggregateAnyValue, nil, placeholder, "")
+			anyValue.ColOffset = aggr.ColOffset
+			aggrBelowRoute.Aggregations = append(aggrBelowRoute.Aggregations, anyValue)
+			continue
+		}
 		if !aggr.Distinct || canPushDistinctAggr {
 			aggrBelowRoute.Aggregations = append(aggrBelowRoute.Aggregations, aggr)
 			aggregateTheAggregate(aggregator, i)
 			continue
 		}
 
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(aggr.Func)
-		}
-
 		// We handle a distinct aggregation by turning it into a group by and
 		// doing the aggregating on the vtgate level instead
-		aeDistinctExpr := aeWrap(distinctExprs[0])
+		aeDistinctExpr := aeWrap(aggr.Func.GetArg())
 		aggrBelowRoute.Columns[aggr.ColOffset] = aeDistinctExpr
 
 		// We handle a distinct aggregation by turning it into a group by and
 		// doing the aggregating on the vtgate level instead
 		// Adding to group by can be done only once even though there are multiple distinct aggregation with same expression.
 		if !distinctAggrGroupByAdded {
-			groupBy := NewGroupBy(distinctExprs[0])
-			groupBy.ColOffset = aggr.ColOffset
-			aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
+			for _, expr := range distinctExprs {
+				groupBy := NewGroupBy(expr)
+				if ctx.SemTable.EqualsExprWithDeps(expr, aggr.Func.GetArg()) {
+					groupBy.ColOffset = aggr.ColOffset
+				}
+				aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
+			}
 			distinctAggrGroupByAdded = true
 		}
 	}
 
-	if !canPushDistinctAggr {
+	if !canPushDistinctAggr && !hashedDistinct {
 		aggregator.DistinctExpr = distinctExprs[0]
 	}
 }
 
+// checkIfWeCanPush returns true if all the distinct aggregations can be pushed down as they are,
+// and all the distinct expressions used by them
 func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, sqlparser.Exprs) {
 	canPush := true
 	var distinctExprs sqlparser.Exprs
-	var differentExpr *sqlparser.AliasedExpr
 
 	for _, aggr := range aggregator.Aggregations {
 		if !aggr.Distinct {
@@ -226,21 +240,13 @@ func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		if !hasUniqVindex {
 			canPush = false
 		}
-		if len(distinctExprs) == 0 {
-			distinctExprs = args
-		}
-		for idx, expr := range distinctExprs {
-			if !ctx.SemTable.EqualsExpr(expr, args[idx]) {
-				differentExpr = aggr.Original
-				break
+		for _, arg := range args {
+			if !ctx.SemTable.ContainsExpr(arg, distinctExprs) {
+				distinctExprs = append(distinctExprs, arg)
 			}
 		}
 	}
 
-	if !canPush && differentExpr != nil {
-		panic(vterrors.VT12001(fmt.Sprintf("only one DISTINCT aggregation is allowed in a SELECT: %s", sqlparser.String(differentExpr))))
-	}
-
 	return canPush, distinctExprs
 }
 
@@ -546,10 +552,9 @@ func splitAggrColumnsToLeftAndRight(
 	// Distinct aggregation cannot be pushed down in the join.
 	// We keep node of the distinct aggregation expression to be used later for ordering.
 	if !canPushDistinctAggr {
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(nil)
+		if !aggregator.needsHashedDistinct(ctx) {
+			aggregator.DistinctExpr = distinctExprs[0]
 		}
-		aggregator.DistinctExpr = distinctExprs[0]
 		return nil, errAbortAggrPushing
 	}
 
@@ -132,15 +132,15 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 		return ab.handleAggrWithCountStarMultiplier(ctx, aggr)
 	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue:
 		return ab.handlePushThroughAggregation(ctx, aggr)
-	case opcode.AggregateGroupConcat:
-		f := aggr.Func.(*sqlparser.GroupConcatExpr)
-		if f.Distinct || len(f.OrderBy) > 0 || f.Separator != "" {
-			panic("fail here")
-		}
+	case opcode.AggregateGroupConcat, opcode.AggregateJSONArrayAgg, opcode.AggregateJSONObjectAgg:
 		// this needs special handling, currently aborting the push of function
 		// and later will try pushing the column instead.
 		// TODO: this should be handled better by pushing the function down.
 		return errAbortAggrPushing
+	case opcode.AggregateGrouping:
+		// GROUPING() is computed by the rollup above the join, so neither side has to produce it
+		ab.proj.addUnexploredExpr(aggr.Original, aggr.getPushColumn())
+		return nil
 	case opcode.AggregateUnassigned:
 		panic(vterrors.VT12001(fmt.Sprintf("in scatter query: aggregation function '%s'", sqlparser.String(aggr.Original))))
 	case opcode.AggregateGtid:
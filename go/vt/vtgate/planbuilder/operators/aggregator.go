@@ -24,7 +24,9 @@ import (
 	"github.com/mdibaiee/vitess/go/slice"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/semantics"
 )
@@ -376,9 +378,64 @@ func (a *Aggregator) planOffsets(ctx *plancontext.PlanningContext) Operator {
 		offset := a.internalAddColumn(ctx, aeWrap(weightStringFor(arg)), true)
 		a.Aggregations[idx].WSOffset = offset
 	}
+
+	a.planHashedDistinctOffsets(ctx)
 	return nil
 }
 
+// needsHashedDistinct returns true when the distinct aggregations can't be computed by sorting the
+// input on a single distinct expression, and have to keep the values they have seen per group instead.
+func (a *Aggregator) needsHashedDistinct(ctx *plancontext.PlanningContext) bool {
+	var distinctExpr sqlparser.Expr
+	for _, aggr := range a.Aggregations {
+		if !aggr.isDistinct() {
+			continue
+		}
+		args := aggr.Func.GetArgs()
+		switch {
+		case a.WithRollup || len(args) != 1:
+			// the super-aggregate rows of WITH ROLLUP merge groups that are not sorted on the distinct expression
+			return true
+		case aggr.OpCode == opcode.AggregateGroupConcat:
+			// GROUP_CONCAT only skips the values it has seen, it doesn't rely on sorted input
+			return true
+		case distinctExpr == nil:
+			distinctExpr = args[0]
+		case !ctx.SemTable.EqualsExprWithDeps(distinctExpr, args[0]):
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Aggregator) planHashedDistinctOffsets(ctx *plancontext.PlanningContext) {
+	if !a.needsHashedDistinct(ctx) {
+		return
+	}
+	for idx, aggr := range a.Aggregations {
+		if !aggr.isDistinct() {
+			continue
+		}
+		var cols []engine.CheckCol
+		for _, arg := range aggr.Func.GetArgs() {
+			var wsCol *int
+			offset := a.internalAddColumn(ctx, aeWrap(arg), a.Pushed)
+			if ctx.SemTable.NeedsWeightString(arg) {
+				wsOffset := a.internalAddColumn(ctx, aeWrap(weightStringFor(arg)), a.Pushed)
+				wsCol = &wsOffset
+			}
+			typ, _ := ctx.TypeForExpr(arg)
+			cols = append(cols, engine.CheckCol{
+				Col:          offset,
+				WsCol:        wsCol,
+				Type:         typ,
+				CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+			})
+		}
+		a.Aggregations[idx].DistinctCols = cols
+	}
+}
+
 func (aggr Aggr) setPushColumn(exprs sqlparser.Exprs) {
 	if aggr.Func == nil {
 		if len(exprs) > 1 {
@@ -400,10 +457,14 @@ func (aggr Aggr) getPushColumn() sqlparser.Expr {
 		return aggr.Original.Expr
 	case opcode.AggregateCountStar:
 		return sqlparser.NewIntLiteral("1")
-	case opcode.AggregateGroupConcat:
-		if len(aggr.Func.GetArgs()) > 1 {
-			panic(vterrors.VT12001("group_concat with more than 1 column"))
-		}
+	case opcode.AggregateGrouping:
+		// GROUPING() is computed by the rollup at the vtgate level, the input only needs a placeholder
+		return sqlparser.NewIntLiteral("0")
+	case opcode.AggregateCountDistinct:
+		// the other arguments are added as columns of their own, see planHashedDistinctOffsets
+		return aggr.Func.GetArg()
+	case opcode.AggregateGroupConcat, opcode.AggregateJSONObjectAgg:
+		// the other arguments are added as columns of their own, see planArgumentOffsets
 		return aggr.Func.GetArg()
 	default:
 		if len(aggr.Func.GetArgs()) > 1 {
@@ -419,6 +480,8 @@ func (aggr Aggr) getPushColumnExprs() sqlparser.Exprs {
 		return sqlparser.Exprs{aggr.Original.Expr}
 	case opcode.AggregateCountStar:
 		return sqlparser.Exprs{sqlparser.NewIntLiteral("1")}
+	case opcode.AggregateGrouping:
+		return sqlparser.Exprs{sqlparser.NewIntLiteral("0")}
 	default:
 		return aggr.Func.GetArgs()
 	}
@@ -441,6 +504,45 @@ func (a *Aggregator) planOffsetsNotPushed(ctx *plancontext.PlanningContext) {
 	}
 
 	a.pushRemainingGroupingColumnsAndWeightStrings(ctx)
+	a.planArgumentOffsets(ctx)
+	a.planHashedDistinctOffsets(ctx)
+}
+
+// planArgumentOffsets adds the columns needed by the aggregations that use more than their first argument:
+// the other arguments of GROUP_CONCAT and JSON_OBJECTAGG, and the ORDER BY of GROUP_CONCAT
+func (a *Aggregator) planArgumentOffsets(ctx *plancontext.PlanningContext) {
+	for idx, aggr := range a.Aggregations {
+		if aggr.OpCode != opcode.AggregateGroupConcat && aggr.OpCode != opcode.AggregateJSONObjectAgg {
+			continue
+		}
+		for _, arg := range aggr.Func.GetArgs()[1:] {
+			offset := a.internalAddColumn(ctx, aeWrap(arg), false)
+			a.Aggregations[idx].ArgOffsets = append(a.Aggregations[idx].ArgOffsets, offset)
+		}
+
+		gc, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+		if !ok {
+			continue
+		}
+		if gc.Limit != nil {
+			panic(vterrors.VT12001(fmt.Sprintf("LIMIT in GROUP_CONCAT in cross-shard query: %s", sqlparser.String(aggr.Original))))
+		}
+		for _, order := range gc.OrderBy {
+			offset := a.internalAddColumn(ctx, aeWrap(order.Expr), false)
+			wsOffset := -1
+			if ctx.SemTable.NeedsWeightString(order.Expr) {
+				wsOffset = a.internalAddColumn(ctx, aeWrap(weightStringFor(order.Expr)), false)
+			}
+			typ, _ := ctx.TypeForExpr(order.Expr)
+			a.Aggregations[idx].OrderBy = append(a.Aggregations[idx].OrderBy, evalengine.OrderByParams{
+				Col:             offset,
+				WeightStringCol: wsOffset,
+				Desc:            order.Direction == sqlparser.DescOrder,
+				Type:            typ,
+				CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+			})
+		}
+	}
 }
 
 func (a *Aggregator) addIfAggregationColumn(ctx *plancontext.PlanningContext, colIdx int) int {
@@ -529,6 +631,8 @@ func (a *Aggregator) SplitAggregatorBelowOperators(ctx *plancontext.PlanningCont
 	newOp.Pushed = false
 	newOp.Original = false
 	newOp.DT = nil
+	// the super-aggregate rows can only be computed once all the partial aggregates are merged
+	newOp.WithRollup = false
 
 	// We need to make sure that the columns are cloned so that the original operator is not affected
 	// by the changes we make to the new operator
@@ -88,6 +88,32 @@ func addWherePredsToSubQueryBuilder(ctx *plancontext.PlanningContext, expr sqlpa
 	return op
 }
 
+// useClonedDerivedTables points the semantic state of the derived tables in `original` to their copies in `clone`.
+// Cloning a statement copies the AliasedTableExpr of every derived table, since they contain expressions,
+// and without this the copied derived tables would not be found in the semantic table.
+func useClonedDerivedTables(ctx *plancontext.PlanningContext, original, clone sqlparser.TableExprs) {
+	orgTbls := derivedTableExprs(original)
+	clonedTbls := derivedTableExprs(clone)
+	for idx, orgTbl := range orgTbls {
+		if idx >= len(clonedTbls) || orgTbl == clonedTbls[idx] {
+			continue
+		}
+		ctx.SemTable.ReplaceTableSetFor(ctx.SemTable.TableSetFor(orgTbl), clonedTbls[idx])
+	}
+}
+
+func derivedTableExprs(exprs sqlparser.TableExprs) (result []*sqlparser.AliasedTableExpr) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if tbl, ok := node.(*sqlparser.AliasedTableExpr); ok {
+			if _, isDerived := tbl.Expr.(*sqlparser.DerivedTable); isDerived {
+				result = append(result, tbl)
+			}
+		}
+		return true, nil
+	}, exprs)
+	return
+}
+
 // cloneASTAndSemState clones the AST and the semantic state of the input node.
 func cloneASTAndSemState[T sqlparser.SQLNode](ctx *plancontext.PlanningContext, original T) T {
 	return sqlparser.CopyOnRewrite(original, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
@@ -148,6 +174,11 @@ func createOperatorFromUnion(ctx *plancontext.PlanningContext, node *sqlparser.U
 	if isRHSUnion {
 		panic(vterrors.VT12001("nesting of UNIONs on the right-hand side"))
 	}
+	if term, isSel := node.Right.(*sqlparser.Select); isSel {
+		if cteID, cteTable := findCTETable(ctx, term); cteTable != nil {
+			return createRecursiveCTE(ctx, node, term, cteID, cteTable)
+		}
+	}
 	opLHS := translateQueryToOp(ctx, node.Left)
 	opRHS := translateQueryToOp(ctx, node.Right)
 	lexprs := ctx.SemTable.SelectExprs(node.Left)
@@ -216,12 +247,17 @@ func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlpar
 		return getOperatorFromJoinTableExpr(ctx, tableExpr)
 	case *sqlparser.ParenTableExpr:
 		return crossJoin(ctx, tableExpr.Exprs)
+	case *sqlparser.JSONTableExpr:
+		return newJSONTable(ctx, nil, tableExpr, false)
 	default:
 		panic(vterrors.VT13001(fmt.Sprintf("unable to use: %T table type", tableExpr)))
 	}
 }
 
 func getOperatorFromJoinTableExpr(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr) Operator {
+	if jtExpr, ok := tableExpr.RightExpr.(*sqlparser.JSONTableExpr); ok {
+		return createJSONTableJoin(ctx, tableExpr, jtExpr)
+	}
 	lhs := getOperatorFromTableExpr(ctx, tableExpr.LeftExpr, false)
 	rhs := getOperatorFromTableExpr(ctx, tableExpr.RightExpr, false)
 
@@ -246,6 +282,10 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 			panic(err)
 		}
 
+		if _, isCTE := tableInfo.(*semantics.CTETable); isCTE {
+			return createDualCTETable(ctx, tableID)
+		}
+
 		if vt, isVindex := tableInfo.(*semantics.VindexTable); isVindex {
 			solves := tableID
 			return &Vindex{
@@ -274,6 +314,7 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 			horizon.TableId = &tableID
 			horizon.Alias = tableExpr.As.String()
 			horizon.ColumnAliases = tableExpr.Columns
+			horizon.Lateral = tbl.Lateral && isCorrelated(ctx, tbl.Select, TableID(inner))
 			qp := CreateQPFromSelectStatement(ctx, tbl.Select)
 			horizon.QP = qp
 		}
@@ -284,9 +325,26 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 	}
 }
 
+// isCorrelated returns true if the statement uses columns from tables it doesn't introduce itself
+func isCorrelated(ctx *plancontext.PlanningContext, stmt sqlparser.SelectStatement, inner semantics.TableSet) bool {
+	correlated := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if col, ok := node.(*sqlparser.ColName); ok && !ctx.SemTable.RecursiveDeps(col).IsSolvedBy(inner) {
+			correlated = true
+		}
+		return !correlated, nil
+	}, stmt)
+	return correlated
+}
+
 func crossJoin(ctx *plancontext.PlanningContext, exprs sqlparser.TableExprs) Operator {
 	var output Operator
 	for _, tableExpr := range exprs {
+		if jtExpr, ok := tableExpr.(*sqlparser.JSONTableExpr); ok {
+			// JSON_TABLE reads its document from the tables that come before it
+			output = newJSONTable(ctx, output, jtExpr, false)
+			continue
+		}
 		op := getOperatorFromTableExpr(ctx, tableExpr, len(exprs) == 1)
 		if output == nil {
 			output = op
@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
+)
+
+/*
+The cost model uses the table statistics collected by the schema tracker to estimate how many rows
+every operator produces, and what it costs to produce them. The statistics are collected from a single
+shard, so the row counts are per shard. The routing cost of a route stands in for the number of shards
+it is sent to: a route to a single shard costs 1, while a scatter costs 20.
+
+The cost of a route is the cost of sending the query to its shards plus the rows it returns.
+An ApplyJoin executes its RHS once for every row coming from the LHS, while a HashJoin executes
+both sides once and has to hold and probe all their rows at the vtgate level.
+*/
+
+const (
+	// shardQueryCost is the cost of sending a query to a single shard, in rows
+	shardQueryCost = 100
+
+	// the selectivities used for predicates when the statistics can't tell us anything better
+	defaultEqualSelectivity = 0.1
+	defaultRangeSelectivity = 1.0 / 3
+)
+
+// estimate is the estimated number of rows an operator produces, and the cost of producing them
+type estimate struct {
+	rows, cost float64
+}
+
+// estimateOf estimates the rows and cost of the operator. It returns false if the operator
+// uses a table that has no statistics, or an operator that the cost model does not know.
+func estimateOf(ctx *plancontext.PlanningContext, op Operator) (estimate, bool) {
+	switch op := op.(type) {
+	case *Route:
+		rows, ok := estimateRows(ctx, op.Source)
+		if !ok {
+			return estimate{}, false
+		}
+		shards := float64(max(op.Routing.Cost(), 1))
+		rows *= shards
+		return estimate{rows: rows, cost: shards*shardQueryCost + rows}, true
+	case *ApplyJoin:
+		lhs, ok := estimateOf(ctx, op.LHS)
+		if !ok {
+			return estimate{}, false
+		}
+		// the RHS contains the join predicates, so this is the estimate for a single row from the LHS
+		rhs, ok := estimateOf(ctx, op.RHS)
+		if !ok {
+			return estimate{}, false
+		}
+		rows := lhs.rows * rhs.rows
+		if op.LeftJoin {
+			rows = max(rows, lhs.rows)
+		}
+		return estimate{rows: rows, cost: lhs.cost + lhs.rows*rhs.cost}, true
+	case *HashJoin:
+		lhs, ok := estimateOf(ctx, op.LHS)
+		if !ok {
+			return estimate{}, false
+		}
+		rhs, ok := estimateOf(ctx, op.RHS)
+		if !ok {
+			return estimate{}, false
+		}
+		rows := lhs.rows * rhs.rows
+		for _, cmp := range op.JoinComparisons {
+			rows *= joinSelectivity(ctx, cmp.LHS, cmp.RHS)
+		}
+		if op.LeftJoin {
+			rows = max(rows, lhs.rows)
+		}
+		return estimate{rows: rows, cost: lhs.cost + rhs.cost + lhs.rows + rhs.rows}, true
+	default:
+		return passThroughEstimate(ctx, op, estimateOf)
+	}
+}
+
+// estimateRows estimates the number of rows produced by the operators inside a route, per shard
+func estimateRows(ctx *plancontext.PlanningContext, op Operator) (float64, bool) {
+	switch op := op.(type) {
+	case *Table:
+		if op.VTable == nil || op.VTable.Stats == nil {
+			return 0, false
+		}
+		rows := float64(op.VTable.Stats.Rows)
+		if op.QTable != nil {
+			for _, pred := range op.QTable.Predicates {
+				rows *= selectivity(ctx, pred)
+			}
+		}
+		return rows, true
+	case *Filter:
+		rows, ok := estimateRows(ctx, op.Source)
+		for _, pred := range op.Predicates {
+			rows *= selectivity(ctx, pred)
+		}
+		return rows, ok
+	case *ApplyJoin:
+		lhs, ok := estimateRows(ctx, op.LHS)
+		if !ok {
+			return 0, false
+		}
+		rhs, ok := estimateRows(ctx, op.RHS)
+		if !ok {
+			return 0, false
+		}
+		rows := lhs * rhs
+		if op.LeftJoin {
+			rows = max(rows, lhs)
+		}
+		return rows, true
+	default:
+		e, ok := passThroughEstimate(ctx, op, func(ctx *plancontext.PlanningContext, op Operator) (estimate, bool) {
+			rows, ok := estimateRows(ctx, op)
+			return estimate{rows: rows}, ok
+		})
+		return e.rows, ok
+	}
+}
+
+// passThroughEstimate estimates operators with a single input as producing the rows of that input,
+// and unions as producing the rows of all their inputs
+func passThroughEstimate(ctx *plancontext.PlanningContext, op Operator, f func(*plancontext.PlanningContext, Operator) (estimate, bool)) (estimate, bool) {
+	inputs := op.Inputs()
+	if len(inputs) == 0 {
+		return estimate{}, false
+	}
+	if _, isUnion := op.(*Union); !isUnion && len(inputs) > 1 {
+		return estimate{}, false
+	}
+	var total estimate
+	for _, input := range inputs {
+		e, ok := f(ctx, input)
+		if !ok {
+			return estimate{}, false
+		}
+		total.rows += e.rows
+		total.cost += e.cost
+	}
+	return total, true
+}
+
+// selectivity estimates the fraction of rows that pass the predicate
+func selectivity(ctx *plancontext.PlanningContext, expr sqlparser.Expr) float64 {
+	switch expr := expr.(type) {
+	case *sqlparser.AndExpr:
+		return selectivity(ctx, expr.Left) * selectivity(ctx, expr.Right)
+	case *sqlparser.OrExpr:
+		l, r := selectivity(ctx, expr.Left), selectivity(ctx, expr.Right)
+		return l + r - l*r
+	case *sqlparser.NotExpr:
+		return 1 - selectivity(ctx, expr.Expr)
+	case *sqlparser.ComparisonExpr:
+		return comparisonSelectivity(ctx, expr)
+	case *sqlparser.IsExpr:
+		return defaultEqualSelectivity
+	default:
+		return defaultRangeSelectivity
+	}
+}
+
+func comparisonSelectivity(ctx *plancontext.PlanningContext, cmp *sqlparser.ComparisonExpr) float64 {
+	switch cmp.Operator {
+	case sqlparser.EqualOp, sqlparser.NullSafeEqualOp:
+		return joinSelectivity(ctx, cmp.Left, cmp.Right)
+	case sqlparser.NotEqualOp:
+		return 1 - joinSelectivity(ctx, cmp.Left, cmp.Right)
+	case sqlparser.InOp, sqlparser.NotInOp:
+		values := 1.0
+		if tuple, ok := cmp.Right.(sqlparser.ValTuple); ok {
+			values = float64(len(tuple))
+		}
+		in := min(1, values*equalSelectivity(ctx, cmp.Left))
+		if cmp.Operator == sqlparser.NotInOp {
+			return 1 - in
+		}
+		return in
+	default:
+		return defaultRangeSelectivity
+	}
+}
+
+// joinSelectivity estimates the fraction of rows for which the two expressions are equal.
+// When both are columns, the one with the most distinct values decides the selectivity.
+func joinSelectivity(ctx *plancontext.PlanningContext, a, b sqlparser.Expr) float64 {
+	return min(equalSelectivity(ctx, a), equalSelectivity(ctx, b))
+}
+
+// equalSelectivity estimates the fraction of rows that have a single value of the expression
+func equalSelectivity(ctx *plancontext.PlanningContext, expr sqlparser.Expr) float64 {
+	col, ok := expr.(*sqlparser.ColName)
+	if !ok {
+		// a literal or an argument has a single value, so it doesn't restrict the rows on its own
+		return 1
+	}
+	tableInfo, err := ctx.SemTable.TableInfoForExpr(col)
+	if err != nil {
+		return defaultEqualSelectivity
+	}
+	vTbl := tableInfo.GetVindexTable()
+	if vTbl == nil || vTbl.Stats == nil {
+		return defaultEqualSelectivity
+	}
+	distinct := distinctValues(vTbl, col.Name)
+	if distinct == 0 {
+		return defaultEqualSelectivity
+	}
+	return 1 / float64(distinct)
+}
+
+// distinctValues returns the estimated number of distinct values of the column in a shard, 0 if it is unknown
+func distinctValues(tbl *vindexes.Table, col sqlparser.IdentifierCI) uint64 {
+	if len(tbl.PrimaryKey) == 1 && tbl.PrimaryKey[0].Equal(col) {
+		return max(tbl.Stats.Rows, 1)
+	}
+	for _, cv := range tbl.ColumnVindexes {
+		if cv.IsUnique() && len(cv.Columns) == 1 && cv.Columns[0].Equal(col) {
+			return max(tbl.Stats.Rows, 1)
+		}
+	}
+	return tbl.Stats.Cardinality[col.Lowered()]
+}
+
+// hasStats returns true if all the tables of the query graph have statistics
+func hasStats(ctx *plancontext.PlanningContext, qg *QueryGraph) bool {
+	for _, qt := range qg.Tables {
+		tableInfo, err := ctx.SemTable.TableInfoFor(qt.ID)
+		if err != nil {
+			return false
+		}
+		vTbl := tableInfo.GetVindexTable()
+		if vTbl == nil || vTbl.Stats == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// joinCost returns the cost the greedy planner compares join plans with. Without statistics,
+// it falls back to the routing costs of the plan.
+func joinCost(ctx *plancontext.PlanningContext, op Operator, withStats bool) float64 {
+	if withStats {
+		if e, ok := estimateOf(ctx, op); ok {
+			return e.cost
+		}
+	}
+	return float64(CostOf(op))
+}
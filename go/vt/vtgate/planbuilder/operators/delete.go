@@ -118,6 +118,7 @@ func deleteWithInputPlanningRequired(childFks []vindexes.ChildFKInfo, deleteStmt
 
 func createDeleteWithInputOp(ctx *plancontext.PlanningContext, del *sqlparser.Delete) (op Operator) {
 	delClone := ctx.SemTable.Clone(del).(*sqlparser.Delete)
+	useClonedDerivedTables(ctx, del.TableExprs, delClone.TableExprs)
 	del.Limit = nil
 	del.OrderBy = nil
 
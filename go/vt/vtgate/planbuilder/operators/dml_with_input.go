@@ -35,6 +35,9 @@ type DMLWithInput struct {
 	updList []updList
 	BvList  []map[string]int
 
+	// MoveRows is set when the DMLs delete the input rows and insert them again with new values
+	MoveRows bool
+
 	noColumns
 	noPredicates
 }
@@ -40,6 +40,7 @@ type Horizon struct {
 	TableId       *semantics.TableSet
 	Alias         string
 	ColumnAliases sqlparser.Columns // derived tables can have their column aliases specified outside the subquery
+	Lateral       bool              // LATERAL derived tables can reference columns of the tables before them
 
 	// QP contains the QueryProjection for this op
 	QP *QueryProjection
@@ -91,6 +92,14 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 		h.Source = h.Source.AddPredicate(ctx, expr)
 		return h
 	}
+	if _, isRecursive := h.Source.(*RecurseCTE); isRecursive {
+		// the rows produced by one iteration are the input to the next, so we can't filter the inputs
+		return newFilter(h, expr)
+	}
+	if sel, isSel := h.Query.(*sqlparser.Select); isSel && hasWindowFuncs(sel) {
+		// filtering the rows before the window functions are evaluated would change their results
+		return newFilter(h, expr)
+	}
 	tableInfo, err := ctx.SemTable.TableInfoForExpr(expr)
 	if err != nil {
 		if errors.Is(err, semantics.ErrNotSingleTable) {
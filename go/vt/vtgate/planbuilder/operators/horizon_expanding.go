@@ -171,11 +171,16 @@ func createProjectionFromSelect(ctx *plancontext.PlanningContext, horizon *Horiz
 		}
 	}
 
+	src := horizon.src()
+	if sel, isSel := horizon.selectStatement().(*sqlparser.Select); isSel && hasWindowFuncs(sel) {
+		src = planWindowFunctions(ctx, qp, sel, src)
+	}
+
 	if qp.NeedsAggregation() {
-		return createProjectionWithAggr(ctx, qp, dt, horizon.src())
+		return createProjectionWithAggr(ctx, qp, dt, src)
 	}
 
-	projX := createProjectionWithoutAggr(ctx, qp, horizon.src())
+	projX := createProjectionWithoutAggr(ctx, qp, src)
 	projX.DT = dt
 	return projX
 }
@@ -305,6 +310,9 @@ func createProjectionWithoutAggr(ctx *plancontext.PlanningContext, qp *QueryProj
 	for _, ae := range aes {
 		org := ctx.SemTable.Clone(ae).(*sqlparser.AliasedExpr)
 		expr := ae.Expr
+		if _, isWindow := src.(*Window); isWindow {
+			expr = splitWindowAvg(expr)
+		}
 		newExpr, subqs := sqc.pullOutValueSubqueries(ctx, expr, outerID, false)
 		if newExpr == nil {
 			// there was no subquery in this expression
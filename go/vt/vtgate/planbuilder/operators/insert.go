@@ -109,39 +109,58 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 
 	vTbl, routing := buildVindexTableForDML(ctx, tableInfo, qt, ins, "insert")
 
-	deleteBeforeInsert := false
-	if ins.Action == sqlparser.ReplaceAct &&
-		(ctx.SemTable.ForeignKeysPresent() || vTbl.Keyspace.Sharded) &&
-		(len(vTbl.PrimaryKey) > 0 || len(vTbl.UniqueKeys) > 0) {
-		// this needs a delete before insert as there can be row clash which needs to be deleted first.
-		ins.Action = sqlparser.InsertAct
-		deleteBeforeInsert = true
+	if ins.Action != sqlparser.ReplaceAct ||
+		!(ctx.SemTable.ForeignKeysPresent() || vTbl.Keyspace.Sharded) {
+		return checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
 	}
 
-	insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
-
-	if !deleteBeforeInsert {
-		return insOp
+	if len(vTbl.PrimaryKey) == 0 && len(vTbl.UniqueKeys) == 0 {
+		// without knowing the keys of the table, we can't find the rows that will be replaced.
+		// MySQL can still replace them as long as they are on the same shard as the new row,
+		// but the lookup vindex entries of the replaced rows would be left behind.
+		if hasOwnedVindexes(vTbl) {
+			panic(vterrors.VT12001("REPLACE INTO with owned vindexes on a table without primary or unique keys in the schema"))
+		}
+		return checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
 	}
 
+	// this needs a delete before insert as there can be row clash which needs to be deleted first.
+	// the delete takes care of the vindex entries of the replaced rows, even when they are on a different shard than the new rows.
 	rows, isRows := ins.Rows.(sqlparser.Values)
 	if !isRows {
 		panic(vterrors.VT12001("REPLACE INTO using select statement"))
 	}
 
+	// the delete has to be created before the insert, since planning the insert replaces the values in the rows with arguments
 	pkCompExpr := pkCompExpression(vTbl, ins, rows)
 	uniqKeyCompExprs := uniqKeyCompExpressions(vTbl, ins, rows)
 	whereExpr := getWhereCondExpr(append(uniqKeyCompExprs, pkCompExpr))
+	if whereExpr == nil {
+		// the new rows can't clash with any of the keys we know about
+		return checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
+	}
 
 	delStmt := &sqlparser.Delete{
 		Comments:   ins.Comments,
 		TableExprs: sqlparser.TableExprs{sqlparser.Clone(ins.Table)},
-		Where:      sqlparser.NewWhere(sqlparser.WhereClause, whereExpr),
+		Where:      sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.Clone(whereExpr)),
 	}
 	delOp := createOpFromStmt(ctx, delStmt, false, "")
+	// the clashing rows are deleted first, so the rows can simply be inserted
+	ins.Action = sqlparser.InsertAct
+	insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
 	return &Sequential{Sources: []Operator{delOp, insOp}}
 }
 
+func hasOwnedVindexes(vTbl *vindexes.Table) bool {
+	for _, colVindex := range vTbl.ColumnVindexes {
+		if colVindex.Owned {
+			return true
+		}
+	}
+	return false
+}
+
 func checkAndCreateInsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.Table, routing Routing) Operator {
 	insOp := createInsertOperator(ctx, ins, vTbl, routing)
 
@@ -201,6 +220,10 @@ func pkCompExpression(vTbl *vindexes.Table, ins *sqlparser.Insert, rows sqlparse
 		return nil
 	}
 	pIndexes, pColTuple := findPKIndexes(vTbl, ins)
+	if len(pIndexes) == 0 {
+		// the primary key is not fully supplied and has no default, so it can't clash with existing rows
+		return nil
+	}
 
 	var pValTuple sqlparser.ValTuple
 	for _, row := range rows {
@@ -228,6 +251,10 @@ func findPKIndexes(vTbl *vindexes.Table, ins *sqlparser.Insert) (pIndexes []pCom
 		var def sqlparser.Expr
 		idx := ins.Columns.FindColumn(pCol)
 		if idx == -1 {
+			if vTbl.AutoIncrement != nil && vTbl.AutoIncrement.Column.Equal(pCol) {
+				// a new value is generated for the column, so it can't match an existing row
+				return nil, nil
+			}
 			def = findDefault(vTbl, pCol)
 			if def == nil {
 				// If default value is empty, nothing to compare as it will always be false.
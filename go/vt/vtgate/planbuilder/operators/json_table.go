@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mdibaiee/vitess/go/slice"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/semantics"
+)
+
+// JSONTable is a JSON_TABLE expression in the FROM clause. The JSON document is read from the rows of the source.
+// When the source is a route, the JSON_TABLE is merged into it and evaluated by MySQL.
+// Otherwise, it is evaluated at the vtgate level for every row of the source.
+// The columns produced by this operator are the columns of the JSON table, followed by the columns of the source.
+type JSONTable struct {
+	Source  Operator
+	TableID semantics.TableSet
+	AST     *sqlparser.JSONTableExpr
+
+	// Columns are all the columns of the JSON table, including the ones in NESTED PATH clauses
+	Columns []*sqlparser.ColName
+
+	// Outer is set when the JSON table is on the right hand side of a LEFT JOIN
+	Outer bool
+
+	// JSONExpr is the JSON document expression, with the columns replaced by offsets into the source
+	JSONExpr evalengine.Expr
+}
+
+func newJSONTable(ctx *plancontext.PlanningContext, src Operator, expr *sqlparser.JSONTableExpr, outer bool) *JSONTable {
+	tableInfo, tableID := ctx.SemTable.JSONTableFor(expr)
+	if tableInfo == nil {
+		panic(vterrors.VT13001("could not find the JSON_TABLE in the semantic table"))
+	}
+	if src == nil {
+		// the JSON table doesn't read from any other table, so we let MySQL evaluate it against dual
+		src = createDualCTETable(ctx, semantics.EmptyTableSet())
+	}
+	if outer {
+		ctx.OuterTables = ctx.OuterTables.Merge(tableID)
+	}
+	sqlparser.RemoveKeyspaceInCol(expr.Expr)
+
+	var columns []*sqlparser.ColName
+	for _, name := range tableInfo.ColumnNames() {
+		col := sqlparser.NewColNameWithQualifier(name, sqlparser.NewTableName(expr.Alias.String()))
+		ctx.SemTable.Direct[col] = tableID
+		ctx.SemTable.Recursive[col] = tableID
+		columns = append(columns, col)
+	}
+	return &JSONTable{
+		Source:  src,
+		TableID: tableID,
+		AST:     expr,
+		Columns: columns,
+		Outer:   outer,
+	}
+}
+
+// createJSONTableJoin plans a JSON_TABLE that is on the right hand side of a JOIN
+func createJSONTableJoin(ctx *plancontext.PlanningContext, join *sqlparser.JoinTableExpr, expr *sqlparser.JSONTableExpr) Operator {
+	lhs := getOperatorFromTableExpr(ctx, join.LeftExpr, false)
+	if join.Condition != nil && len(join.Condition.Using) > 0 {
+		panic(vterrors.VT12001("JOIN with JSON_TABLE using the USING clause"))
+	}
+	var on sqlparser.Expr
+	if join.Condition != nil {
+		on = join.Condition.On
+	}
+
+	switch join.Join {
+	case sqlparser.NormalJoinType, sqlparser.StraightJoinType:
+		return addJoinPredicates(ctx, on, newJSONTable(ctx, lhs, expr, false))
+	case sqlparser.LeftJoinType:
+		if on != nil && !isTrueLiteral(on) {
+			panic(vterrors.VT12001(fmt.Sprintf("LEFT JOIN with JSON_TABLE using a join condition: %s", sqlparser.String(on))))
+		}
+		return newJSONTable(ctx, lhs, expr, true)
+	default:
+		panic(vterrors.VT12001(fmt.Sprintf("%s with JSON_TABLE", strings.ToUpper(join.Join.ToString()))))
+	}
+}
+
+func isTrueLiteral(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case sqlparser.BoolVal:
+		return bool(expr)
+	case *sqlparser.Literal:
+		return expr.Type == sqlparser.IntVal && expr.Val == "1"
+	}
+	return false
+}
+
+// tryPushJSONTable merges the JSON table into the route of its source, or pushes it to the side of the join
+// that the JSON document is read from
+func tryPushJSONTable(ctx *plancontext.PlanningContext, in *JSONTable) (Operator, *ApplyResult) {
+	switch src := in.Source.(type) {
+	case *Route:
+		return Swap(in, src, "push JSON_TABLE into route")
+	case *ApplyJoin:
+		deps := ctx.SemTable.RecursiveDeps(in.AST.Expr)
+		switch {
+		case deps.IsSolvedBy(TableID(src.LHS)):
+			in.Source, src.LHS = src.LHS, in
+			return src, Rewrote("push JSON_TABLE to the LHS of the join")
+		case src.JoinType.IsInner() && deps.IsSolvedBy(TableID(src.RHS)):
+			// for outer joins, rows with a missing RHS would be removed by the JSON table,
+			// so we can only do this for inner joins
+			in.Source, src.RHS = src.RHS, in
+			return src, Rewrote("push JSON_TABLE to the RHS of the join")
+		}
+	}
+	return in, NoRewrite
+}
+
+// Clone implements the Operator interface
+func (jt *JSONTable) Clone(inputs []Operator) Operator {
+	klone := *jt
+	klone.Source = inputs[0]
+	klone.Columns = slices.Clone(jt.Columns)
+	return &klone
+}
+
+// Inputs implements the Operator interface
+func (jt *JSONTable) Inputs() []Operator {
+	return []Operator{jt.Source}
+}
+
+// SetInputs implements the Operator interface
+func (jt *JSONTable) SetInputs(operators []Operator) {
+	jt.Source = operators[0]
+}
+
+func (jt *JSONTable) introducesTableID() semantics.TableSet {
+	return jt.TableID
+}
+
+// AddPredicate implements the Operator interface.
+// Predicates that only use the source are pushed down, the rest are evaluated on top of the JSON table.
+func (jt *JSONTable) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	if ctx.SemTable.RecursiveDeps(expr).IsSolvedBy(TableID(jt.Source)) {
+		jt.Source = jt.Source.AddPredicate(ctx, expr)
+		return jt
+	}
+	return newFilter(jt, expr)
+}
+
+// findColumn returns the offset of a column of the JSON table, or -1 if the expression is not one
+func (jt *JSONTable) findColumn(ctx *plancontext.PlanningContext, expr sqlparser.Expr) int {
+	col, ok := expr.(*sqlparser.ColName)
+	if !ok || ctx.SemTable.DirectDeps(col) != jt.TableID {
+		return -1
+	}
+	return slices.IndexFunc(jt.Columns, func(c *sqlparser.ColName) bool {
+		return col.Name.Equal(c.Name)
+	})
+}
+
+func (jt *JSONTable) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, expr *sqlparser.AliasedExpr) int {
+	if offset := jt.findColumn(ctx, expr.Expr); offset >= 0 {
+		return offset
+	}
+	if !ctx.SemTable.RecursiveDeps(expr.Expr).IsSolvedBy(TableID(jt.Source)) {
+		panic(vterrors.VT12001(fmt.Sprintf("expression using both JSON_TABLE columns and other columns: %s", sqlparser.String(expr.Expr))))
+	}
+	return len(jt.Columns) + jt.Source.AddColumn(ctx, reuse, gb, expr)
+}
+
+func (jt *JSONTable) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if offset < len(jt.Columns) {
+		panic(vterrors.VT12001(fmt.Sprintf("weight_string of JSON_TABLE column: %s", sqlparser.String(jt.Columns[offset]))))
+	}
+	return len(jt.Columns) + jt.Source.AddWSColumn(ctx, offset-len(jt.Columns), underRoute)
+}
+
+func (jt *JSONTable) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	if offset := jt.findColumn(ctx, expr); offset >= 0 {
+		return offset
+	}
+	if !ctx.SemTable.RecursiveDeps(expr).IsSolvedBy(TableID(jt.Source)) {
+		return -1
+	}
+	offset := jt.Source.FindCol(ctx, expr, underRoute)
+	if offset < 0 {
+		return offset
+	}
+	return len(jt.Columns) + offset
+}
+
+func (jt *JSONTable) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return append(slice.Map(jt.Columns, colNameToExpr), jt.Source.GetColumns(ctx)...)
+}
+
+func (jt *JSONTable) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, jt)
+}
+
+func (jt *JSONTable) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (jt *JSONTable) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	cfg := &evalengine.Config{
+		ResolveType: ctx.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	}
+
+	rewritten := useOffsets(ctx, jt.AST.Expr, jt)
+	eexpr, err := evalengine.Translate(rewritten, cfg)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), evalengine.ErrTranslateExprNotSupported) {
+			panic(vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s: %s", evalengine.ErrTranslateExprNotSupported, sqlparser.String(jt.AST.Expr)))
+		}
+		panic(err)
+	}
+	jt.JSONExpr = eexpr
+	return nil
+}
+
+func (jt *JSONTable) ShortDescription() string {
+	return fmt.Sprintf("%s %s", jt.AST.Alias.String(), sqlparser.String(jt.AST.Filter))
+}
@@ -64,7 +64,7 @@ func mustFetchFromInput(ctx *plancontext.PlanningContext, e sqlparser.SQLNode) b
 	case *sqlparser.FuncExpr:
 		return fun.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
 	default:
-		return false
+		return sqlparser.IsWindowFunc(e)
 	}
 }
 
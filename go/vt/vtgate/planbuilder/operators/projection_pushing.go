@@ -130,6 +130,11 @@ func pushProjectionToOuter(ctx *plancontext.PlanningContext, p *Projection, sq *
 		return p, NoRewrite
 	}
 
+	if sq.isCorrelatedArgument() {
+		// the value of the subquery is only available after the subquery has run for the outer row
+		return p, NoRewrite
+	}
+
 	outer := TableID(sq.Outer)
 	for _, pe := range ap {
 		_, isOffset := pe.Info.(Offset)
@@ -37,6 +37,7 @@ func planQuery(ctx *plancontext.PlanningContext, root Operator) Operator {
 	}
 
 	output := runPhases(ctx, root)
+	checkLateralJoins(output)
 	output = planOffsets(ctx, output)
 
 	if DebugOperatorTree {
@@ -96,6 +97,8 @@ func runRewriters(ctx *plancontext.PlanningContext, root Operator) Operator {
 			return pushOrMergeSubQueryContainer(ctx, in)
 		case *QueryGraph:
 			return optimizeQueryGraph(ctx, in)
+		case *JSONTable:
+			return tryPushJSONTable(ctx, in)
 		case *LockAndComment:
 			return pushLockAndComment(in)
 		case *Delete:
@@ -180,7 +183,8 @@ func pushLockAndComment(l *LockAndComment) (Operator, *ApplyResult) {
 func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (Operator, *ApplyResult) {
 	if in.IsDerived() {
 		newOp, result := pushDerived(ctx, in)
-		if result != NoRewrite {
+		if result != NoRewrite || in.Lateral {
+			// LATERAL derived tables are never expanded, they have to be merged with the tables they reference
 			return newOp, result
 		}
 	}
@@ -210,7 +214,8 @@ func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (Operato
 		!needsOrdering &&
 		!qp.NeedsAggregation() &&
 		!in.selectStatement().IsDistinct() &&
-		in.selectStatement().GetLimit() == nil
+		in.selectStatement().GetLimit() == nil &&
+		(!isSel || windowsCanBePushed(ctx, sel, rb))
 
 	if canPush {
 		return Swap(in, rb, "push horizon into route")
@@ -487,8 +492,9 @@ func setUpperLimit(in *Limit) (Operator, *ApplyResult) {
 	var result *ApplyResult
 	shouldVisit := func(op Operator) VisitRule {
 		switch op := op.(type) {
-		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery:
-			// we can't push limits down on either side
+		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery, *Window, *JSONTable:
+			// we can't push limits down on either side, window functions need to see all rows,
+			// and a JSON table can produce any number of rows for each row of its source
 			return SkipChildren
 		case *Route:
 			newSrc := &Limit{
@@ -525,6 +531,10 @@ func tryPushOrdering(ctx *plancontext.PlanningContext, in *Ordering) (Operator,
 	case *Projection:
 		return pushOrderingUnderProjection(ctx, in, src)
 	case *Aggregator:
+		if src.WithRollup {
+			// the rollup levels follow the GROUP BY order, and the super-aggregate rows need sorting too
+			return in, NoRewrite
+		}
 		if !src.QP.AlignGroupByAndOrderBy(ctx) && !overlaps(ctx, in.Order, src.Grouping) {
 			return in, NoRewrite
 		}
@@ -724,7 +734,21 @@ func tryPushFilter(ctx *plancontext.PlanningContext, in *Filter) (Operator, *App
 }
 
 func pushFilterUnderProjection(ctx *plancontext.PlanningContext, filter *Filter, projection *Projection) (Operator, *ApplyResult) {
+	dt := projection.DT
+	switch projection.Source.(type) {
+	case *Window:
+		if dt != nil {
+			// the window functions of a derived table have to be evaluated before its rows are filtered
+			return filter, NoRewrite
+		}
+	case *RecurseCTE:
+		// the columns of a recursive CTE are produced by all iterations, not by the expressions of the seed query
+		dt = nil
+	}
+	predicates := make([]sqlparser.Expr, 0, len(filter.Predicates))
 	for _, p := range filter.Predicates {
+		// below a derived table, the predicate has to use the expressions the derived table is made of
+		p = dt.RewriteExpression(ctx, p)
 		cantPush := false
 		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
 			if !mustFetchFromInput(ctx, node) {
@@ -742,7 +766,9 @@ func pushFilterUnderProjection(ctx *plancontext.PlanningContext, filter *Filter,
 		if cantPush {
 			return filter, NoRewrite
 		}
+		predicates = append(predicates, p)
 	}
+	filter.Predicates = predicates
 	return Swap(filter, projection, "push filter under projection")
 
 }
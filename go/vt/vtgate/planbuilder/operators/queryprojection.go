@@ -26,6 +26,7 @@ import (
 
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -87,6 +88,15 @@ type (
 		ColOffset int
 		WSOffset  int
 
+		// DistinctCols is only filled in during offset planning, for distinct aggregations
+		// that keep the values they have seen instead of relying on sorted input
+		DistinctCols []engine.CheckCol
+
+		// ArgOffsets and OrderBy are only filled in during offset planning, for aggregations that
+		// are computed at the vtgate level from more than one argument or in a specific order
+		ArgOffsets []int
+		OrderBy    evalengine.Comparison
+
 		SubQueryExpression []*SubQuery
 	}
 )
@@ -95,6 +105,18 @@ func (aggr Aggr) NeedsWeightString(ctx *plancontext.PlanningContext) bool {
 	return aggr.OpCode.NeedsComparableValues() && ctx.SemTable.NeedsWeightString(aggr.Func.GetArg())
 }
 
+// isDistinct returns true if the aggregation has to skip the values it has already seen
+func (aggr Aggr) isDistinct() bool {
+	return aggr.OpCode.IsDistinct() || aggr.OpCode == opcode.AggregateGroupConcat && aggr.Distinct
+}
+
+// needsAllRows returns true for a GROUP_CONCAT that can't be assembled from the values
+// produced for separate parts of the group, and has to see all the rows of the group instead
+func (aggr Aggr) needsAllRows() bool {
+	gc, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+	return ok && (gc.Distinct || len(gc.OrderBy) > 0 || gc.Limit != nil)
+}
+
 func (aggr Aggr) GetTypeCollation(ctx *plancontext.PlanningContext) evalengine.Type {
 	if aggr.Func == nil {
 		return evalengine.Type{}
@@ -205,7 +227,8 @@ func (qp *QueryProjection) addSelectExpressions(ctx *plancontext.PlanningContext
 func IsAggr(ctx *plancontext.PlanningContext, e sqlparser.SQLNode) bool {
 	switch node := e.(type) {
 	case sqlparser.AggrFunc:
-		return true
+		// aggregate functions used as window functions don't aggregate rows
+		return !sqlparser.IsWindowFunc(node)
 	case *sqlparser.FuncExpr:
 		return node.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
 	}
@@ -221,6 +244,9 @@ func ContainsAggr(ctx *plancontext.PlanningContext, e sqlparser.SQLNode) (hasAgg
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case sqlparser.AggrFunc:
+			if sqlparser.IsWindowFunc(node) {
+				return true, nil
+			}
 			hasAggr = true
 			return false, io.EOF
 		case *sqlparser.Subquery:
@@ -483,7 +509,7 @@ func (qp *QueryProjection) extractAggr(
 		if !isExpr {
 			return true
 		}
-		if aggr, isAggr := node.(sqlparser.AggrFunc); isAggr {
+		if aggr, isAggr := node.(sqlparser.AggrFunc); isAggr && !sqlparser.IsWindowFunc(aggr) {
 			ae := aeWrap(aggr)
 			if aggr == aliasedExpr.Expr {
 				ae = aliasedExpr
@@ -0,0 +1,278 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mdibaiee/vitess/go/slice"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/semantics"
+)
+
+// RecurseCTE is used to evaluate a recursive common table expression.
+// The Seed is evaluated once, and then the Term is evaluated repeatedly, using the
+// rows produced by the previous iteration, until no new rows are produced.
+type RecurseCTE struct {
+	Seed, Term Operator
+
+	// Vars are the bind variables the Term uses to access the columns of the previous iteration
+	Vars map[string]int
+
+	distinct bool
+
+	// columnNames are the names of the columns of the CTE, as seen by the query using it
+	columnNames []string
+
+	unionColumns              sqlparser.SelectExprs
+	unionColumnsAsAlisedExprs []*sqlparser.AliasedExpr
+
+	// This is only filled in during offset planning
+	CheckCols []engine.CheckCol
+}
+
+var _ Operator = (*RecurseCTE)(nil)
+
+// createRecursiveCTE creates the operator for the UNION of a recursive CTE. The columns of the CTE table
+// used in the recursive query block are replaced with arguments, and the CTE table itself is planned as dual.
+func createRecursiveCTE(ctx *plancontext.PlanningContext, node *sqlparser.Union, term *sqlparser.Select, cteID semantics.TableSet, cteTable *semantics.CTETable) Operator {
+	if len(node.OrderBy) > 0 || node.Limit != nil {
+		panic(vterrors.VT12001("ORDER BY or LIMIT over UNION in recursive common table expression"))
+	}
+
+	columns := cteTable.ColumnNames()
+	vars := map[string]int{}
+	argNames := map[int]string{}
+	_ = sqlparser.Rewrite(term, nil, func(cursor *sqlparser.Cursor) bool {
+		col, ok := cursor.Node().(*sqlparser.ColName)
+		if !ok || ctx.SemTable.DirectDeps(col) != cteID {
+			return true
+		}
+		offset := slices.IndexFunc(columns, func(name string) bool {
+			return strings.EqualFold(name, col.Name.String())
+		})
+		if offset == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the recursive common table expression", sqlparser.String(col))))
+		}
+		bvName, found := argNames[offset]
+		if !found {
+			bvName = ctx.ReservedVars.ReserveColName(col)
+			argNames[offset] = bvName
+			vars[bvName] = offset
+		}
+		typ, _ := ctx.TypeForExpr(col)
+		cursor.Replace(sqlparser.NewTypedArgument(bvName, typ.Type()))
+		return true
+	})
+
+	seed := translateQueryToOp(ctx, node.Left)
+	termOp := translateQueryToOp(ctx, term)
+	return newHorizon(&RecurseCTE{
+		Seed:         seed,
+		Term:         termOp,
+		Vars:         vars,
+		distinct:     node.Distinct,
+		columnNames:  columns,
+		unionColumns: ctx.SemTable.SelectExprs(node),
+	}, node)
+}
+
+// findCTETable returns the table info for the CTE table, if the select is the recursive query block of a CTE
+func findCTETable(ctx *plancontext.PlanningContext, sel *sqlparser.Select) (id semantics.TableSet, cteTable *semantics.CTETable) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			if _, isTable := node.Expr.(sqlparser.TableName); !isTable {
+				return false, nil
+			}
+			tableID := ctx.SemTable.TableSetFor(node)
+			tableInfo, err := ctx.SemTable.TableInfoFor(tableID)
+			if err != nil {
+				return false, nil
+			}
+			if tbl, ok := tableInfo.(*semantics.CTETable); ok {
+				id, cteTable = tableID, tbl
+			}
+			return false, nil
+		case sqlparser.TableExprs, *sqlparser.JoinTableExpr, *sqlparser.ParenTableExpr:
+			return true, nil
+		}
+		return false, nil
+	}, sqlparser.TableExprs(sel.From))
+	return id, cteTable
+}
+
+// createDualCTETable plans the reference to the CTE in the recursive query block. The columns of the
+// CTE are supplied by bind variables, so the table can be replaced with dual, which can be merged with any route.
+func createDualCTETable(ctx *plancontext.PlanningContext, tableID semantics.TableSet) Operator {
+	dual := sqlparser.NewTableName("dual")
+	qt := &QueryTable{
+		ID:    tableID,
+		Alias: &sqlparser.AliasedTableExpr{Expr: dual},
+		Table: dual,
+	}
+	return findVSchemaTableAndCreateRoute(ctx, qt, dual, false /*planAlternates*/)
+}
+
+// Clone implements the Operator interface
+func (r *RecurseCTE) Clone(inputs []Operator) Operator {
+	return &RecurseCTE{
+		Seed:                      inputs[0],
+		Term:                      inputs[1],
+		Vars:                      r.Vars,
+		distinct:                  r.distinct,
+		columnNames:               r.columnNames,
+		unionColumns:              r.unionColumns,
+		unionColumnsAsAlisedExprs: r.unionColumnsAsAlisedExprs,
+		CheckCols:                 slices.Clone(r.CheckCols),
+	}
+}
+
+// Inputs implements the Operator interface
+func (r *RecurseCTE) Inputs() []Operator {
+	return []Operator{r.Seed, r.Term}
+}
+
+// SetInputs implements the Operator interface
+func (r *RecurseCTE) SetInputs(operators []Operator) {
+	r.Seed = operators[0]
+	r.Term = operators[1]
+}
+
+// AddPredicate implements the Operator interface.
+// Predicates can't be pushed to the inputs, since they would change what the next iteration sees.
+func (r *RecurseCTE) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(r, expr)
+}
+
+func (r *RecurseCTE) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, expr *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := r.FindCol(ctx, expr.Expr, false)
+		if offset >= 0 {
+			return offset
+		}
+	}
+	cols := r.GetColumns(ctx)
+
+	switch e := expr.Expr.(type) {
+	case *sqlparser.ColName:
+		// here we deal with pure column access on top of the CTE
+		offset := slices.IndexFunc(r.columnNames, func(name string) bool {
+			return e.Name.EqualString(name)
+		})
+		if offset == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the recursive common table expression", sqlparser.String(e))))
+		}
+		return offset
+	case *sqlparser.WeightStringFuncExpr:
+		argIdx := slices.IndexFunc(cols, func(expr *sqlparser.AliasedExpr) bool {
+			return ctx.SemTable.EqualsExprWithDeps(e.Expr, expr.Expr)
+		})
+		if argIdx == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the argument to the weight_string function: %s", sqlparser.String(e.Expr))))
+		}
+		return r.AddWSColumn(ctx, argIdx, false)
+	default:
+		panic(vterrors.VT13001(fmt.Sprintf("only columns are expected on a recursive common table expression - got %s", sqlparser.String(expr))))
+	}
+}
+
+// AddWSColumn implements the Operator interface. The weight_string column is added to both
+// inputs, so that the rows produced by the Seed and the Term have the same shape.
+func (r *RecurseCTE) AddWSColumn(ctx *plancontext.PlanningContext, offset int, _ bool) int {
+	seedOffset := r.Seed.AddWSColumn(ctx, offset, false)
+	termOffset := r.Term.AddWSColumn(ctx, offset, false)
+	if seedOffset != termOffset {
+		panic(vterrors.VT12001("weight_string offsets did not line up for recursive common table expression"))
+	}
+	return seedOffset
+}
+
+func (r *RecurseCTE) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for idx, col := range r.GetColumns(ctx) {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col.Expr) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (r *RecurseCTE) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	if r.unionColumnsAsAlisedExprs == nil {
+		allOk := true
+		r.unionColumnsAsAlisedExprs = slice.Map(r.unionColumns, func(from sqlparser.SelectExpr) *sqlparser.AliasedExpr {
+			expr, ok := from.(*sqlparser.AliasedExpr)
+			allOk = allOk && ok
+			return expr
+		})
+		if !allOk {
+			panic(vterrors.VT09015())
+		}
+	}
+
+	// if the inputs have more columns than we expect, we need to expose them so that
+	// the results can be truncated to the expected result columns
+	columns := r.Seed.GetColumns(ctx)
+	for len(columns) > len(r.unionColumnsAsAlisedExprs) {
+		r.unionColumnsAsAlisedExprs = append(r.unionColumnsAsAlisedExprs, aeWrap(sqlparser.NewIntLiteral("0")))
+	}
+	return r.unionColumnsAsAlisedExprs
+}
+
+func (r *RecurseCTE) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, r)
+}
+
+func (r *RecurseCTE) ShortDescription() string {
+	desc := fmt.Sprintf("%v", r.Vars)
+	if r.distinct {
+		return "DISTINCT " + desc
+	}
+	return desc
+}
+
+func (r *RecurseCTE) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+// planOffsets adds the columns needed to find rows that have already been produced, when the CTE uses UNION DISTINCT
+func (r *RecurseCTE) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	if !r.distinct {
+		return nil
+	}
+	for idx, col := range r.GetColumns(ctx) {
+		e := col.Expr
+		var wsCol *int
+		if ctx.SemTable.NeedsWeightString(e) {
+			offset := r.AddWSColumn(ctx, idx, false)
+			wsCol = &offset
+		}
+		typ, _ := ctx.TypeForExpr(e)
+		r.CheckCols = append(r.CheckCols, engine.CheckCol{
+			Col:          idx,
+			WsCol:        wsCol,
+			Type:         typ,
+			CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	return nil
+}
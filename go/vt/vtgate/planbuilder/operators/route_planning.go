@@ -19,6 +19,8 @@ package operators
 import (
 	"bytes"
 	"io"
+	"slices"
+	"strings"
 
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	topodatapb "github.com/mdibaiee/vitess/go/vt/proto/topodata"
@@ -41,10 +43,15 @@ type (
 func pushDerived(ctx *plancontext.PlanningContext, op *Horizon) (Operator, *ApplyResult) {
 	innerRoute, ok := op.Source.(*Route)
 	if !ok {
+		if op.Lateral {
+			return pushCorrelatedFilter(op)
+		}
 		return op, NoRewrite
 	}
 
-	if !(innerRoute.Routing.OpCode() == engine.EqualUnique) && !op.IsMergeable(ctx) {
+	// a LATERAL derived table is evaluated once per row of the tables before it, so it can only be
+	// planned by merging it with them. optimizeJoin makes sure that this merge is possible.
+	if !op.Lateral && !(innerRoute.Routing.OpCode() == engine.EqualUnique) && !op.IsMergeable(ctx) {
 		// no need to check anything if we are sure that we will only hit a single shard
 		return op, NoRewrite
 	}
@@ -52,13 +59,109 @@ func pushDerived(ctx *plancontext.PlanningContext, op *Horizon) (Operator, *Appl
 	return Swap(op, op.Source, "push derived under route")
 }
 
+// pushCorrelatedFilter moves the predicates that reference the tables before a LATERAL derived table into its route.
+// They can't be solved by the route on its own, so the normal filter pushing leaves them on top of it.
+func pushCorrelatedFilter(op *Horizon) (Operator, *ApplyResult) {
+	filter, ok := op.Source.(*Filter)
+	if !ok {
+		return op, NoRewrite
+	}
+	route, ok := filter.Source.(*Route)
+	if !ok {
+		return op, NoRewrite
+	}
+	filter.Source, route.Source = route.Source, filter
+	op.Source = route
+	return op, Rewrote("push correlated filter of LATERAL derived table into route")
+}
+
 func optimizeJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
-	return mergeOrJoin(ctx, op.LHS, op.RHS, sqlparser.SplitAndExpression(nil, op.Predicate), op.JoinType)
+	joinPredicates := sqlparser.SplitAndExpression(nil, op.Predicate)
+	correlated, lateralPredicates := lateralJoinPredicates(ctx, op)
+	if !correlated {
+		return mergeOrJoin(ctx, op.LHS, op.RHS, joinPredicates, op.JoinType)
+	}
+
+	if lhsRoute, _ := operatorsToRoutes(op.LHS, op.RHS); lhsRoute == nil {
+		// the derived table has not been pushed into a route yet
+		return op, NoRewrite
+	}
+
+	// the correlated predicates are already part of the derived table, we only use them to decide if we can merge
+	newPlan := mergeJoinInputs(ctx, op.LHS, op.RHS, append(lateralPredicates, joinPredicates...), newJoinMerge(joinPredicates, op.JoinType))
+	if newPlan == nil {
+		panic(unmergedLateralError())
+	}
+	return newPlan, Rewrote("merge LATERAL derived table into route")
+}
+
+func unmergedLateralError() error {
+	return vterrors.VT12001("LATERAL derived table that can't be merged with the tables it references")
+}
+
+// checkLateralJoins fails the planning if a logical join is left in the tree after all the phases have run.
+// optimizeJoin only leaves a join unplanned when it has a LATERAL derived table that is waiting to be merged.
+func checkLateralJoins(op Operator) {
+	_ = Visit(op, func(op Operator) error {
+		if _, isJoin := op.(*Join); isJoin {
+			panic(unmergedLateralError())
+		}
+		return nil
+	})
+}
+
+// lateralJoinPredicates checks if the RHS of the join contains a LATERAL derived table that references the LHS.
+// If it does, the predicates of the derived table that use the LHS are returned.
+func lateralJoinPredicates(ctx *plancontext.PlanningContext, op *Join) (correlated bool, predicates []sqlparser.Expr) {
+	lhsID := TableID(op.LHS)
+	TableID(op.RHS).ForEachTable(func(offset int) {
+		derived := lateralDerivedTable(ctx, semantics.SingleTableSet(offset))
+		if derived == nil {
+			return
+		}
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			col, ok := node.(*sqlparser.ColName)
+			if ok && ctx.SemTable.RecursiveDeps(col).IsOverlapping(lhsID) {
+				correlated = true
+			}
+			return !correlated, nil
+		}, derived.Select)
+
+		sel, ok := derived.Select.(*sqlparser.Select)
+		if !ok || sel.Where == nil {
+			return
+		}
+		for _, pred := range sqlparser.SplitAndExpression(nil, sel.Where.Expr) {
+			if ctx.SemTable.RecursiveDeps(pred).IsOverlapping(lhsID) {
+				predicates = append(predicates, pred)
+			}
+		}
+	})
+	return
+}
+
+// lateralDerivedTable returns the AST of the table if it is a LATERAL derived table, and nil otherwise
+func lateralDerivedTable(ctx *plancontext.PlanningContext, id semantics.TableSet) *sqlparser.DerivedTable {
+	tableInfo, err := ctx.SemTable.TableInfoFor(id)
+	if err != nil {
+		return nil
+	}
+	dt, ok := tableInfo.(*semantics.DerivedTable)
+	if !ok || dt.ASTNode == nil {
+		return nil
+	}
+	derived, ok := dt.ASTNode.Expr.(*sqlparser.DerivedTable)
+	if !ok || !derived.Lateral {
+		return nil
+	}
+	return derived
 }
 
 func optimizeQueryGraph(ctx *plancontext.PlanningContext, op *QueryGraph) (result Operator, changed *ApplyResult) {
 
 	switch {
+	case ctx.PlanBaseline != nil && len(ctx.PlanBaseline.JoinOrder) > 0:
+		result = joinOrderSolve(ctx, op, ctx.PlanBaseline.JoinOrder)
 	case ctx.PlannerVersion == querypb.ExecuteOptions_Gen4Left2Right:
 		result = leftToRightSolve(ctx, op)
 	default:
@@ -132,18 +235,54 @@ func buildVindexTableForDML(
 		The greedy planner will plan a query by finding first finding the best route plan for every table.
 	    Then, iteratively, it finds the cheapest join that can be produced between the remaining plans,
 		and removes the two inputs to this cheapest plan and instead adds the join.
-		As an optimization, it first only considers joining tables that have predicates defined between them
+		As an optimization, it first only considers joining tables that have predicates defined between them.
+		When all the tables have statistics, the joins are compared using the estimated number of rows
+		they produce, see cost_estimation.go
 */
 func greedySolve(ctx *plancontext.PlanningContext, qg *QueryGraph) Operator {
 	routeOps := seedOperatorList(ctx, qg)
 	planCache := opCacheMap{}
 
-	return mergeRoutes(ctx, qg, routeOps, planCache, false)
+	return mergeRoutes(ctx, qg, routeOps, planCache, false, hasStats(ctx, qg))
 }
 
 func leftToRightSolve(ctx *plancontext.PlanningContext, qg *QueryGraph) Operator {
-	plans := seedOperatorList(ctx, qg)
+	return joinLeftToRight(ctx, qg, seedOperatorList(ctx, qg))
+}
 
+// joinOrderSolve joins the tables of the query graph in the order forced by a plan baseline.
+// Tables are listed by alias or name. The tables that are not listed are joined last,
+// in the order they appear in the query.
+func joinOrderSolve(ctx *plancontext.PlanningContext, qg *QueryGraph, order []string) Operator {
+	seeds := seedOperatorList(ctx, qg)
+	positions := make([]int, len(seeds))
+	for i, table := range qg.Tables {
+		name := table.Alias.As.String()
+		if name == "" {
+			name = table.Table.Name.String()
+		}
+		positions[i] = slices.IndexFunc(order, func(listed string) bool {
+			return strings.EqualFold(listed, name)
+		})
+		if positions[i] < 0 {
+			positions[i] = len(order)
+		}
+	}
+	idx := make([]int, len(seeds))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		return positions[a] - positions[b]
+	})
+	plans := make([]Operator, 0, len(seeds))
+	for _, i := range idx {
+		plans = append(plans, seeds[i])
+	}
+	return joinLeftToRight(ctx, qg, plans)
+}
+
+func joinLeftToRight(ctx *plancontext.PlanningContext, qg *QueryGraph, plans []Operator) Operator {
 	var acc Operator
 	for _, plan := range plans {
 		if acc == nil {
@@ -194,12 +333,12 @@ func createInfSchemaRoute(ctx *plancontext.PlanningContext, table *QueryTable) O
 	}
 }
 
-func mergeRoutes(ctx *plancontext.PlanningContext, qg *QueryGraph, physicalOps []Operator, planCache opCacheMap, crossJoinsOK bool) Operator {
+func mergeRoutes(ctx *plancontext.PlanningContext, qg *QueryGraph, physicalOps []Operator, planCache opCacheMap, crossJoinsOK, withStats bool) Operator {
 	if len(physicalOps) == 0 {
 		return nil
 	}
 	for len(physicalOps) > 1 {
-		bestTree, lIdx, rIdx := findBestJoin(ctx, qg, physicalOps, planCache, crossJoinsOK)
+		bestTree, lIdx, rIdx := findBestJoin(ctx, qg, physicalOps, planCache, crossJoinsOK, withStats)
 		// if we found a plan, we'll replace the two plans that were joined with the join plan created
 		if bestTree != nil {
 			// we remove one plan, and replace the other
@@ -233,8 +372,9 @@ func findBestJoin(
 	qg *QueryGraph,
 	plans []Operator,
 	planCache opCacheMap,
-	crossJoinsOK bool,
+	crossJoinsOK, withStats bool,
 ) (bestPlan Operator, lIdx int, rIdx int) {
+	var bestCost float64
 	for i, lhs := range plans {
 		for j, rhs := range plans {
 			if i == j {
@@ -248,8 +388,9 @@ func findBestJoin(
 				continue
 			}
 			plan := getJoinFor(ctx, planCache, lhs, rhs, joinPredicates)
-			if bestPlan == nil || CostOf(plan) < CostOf(bestPlan) {
-				bestPlan = plan
+			cost := joinCost(ctx, plan, withStats)
+			if bestPlan == nil || cost < bestCost {
+				bestPlan, bestCost = plan, cost
 				// remember which plans we based on, so we can remove them later
 				lIdx = i
 				rIdx = j
@@ -311,9 +452,54 @@ func mergeOrJoin(ctx *plancontext.PlanningContext, lhs, rhs Operator, joinPredic
 
 	join := NewApplyJoin(ctx, Clone(lhs), Clone(rhs), nil, joinType)
 	newOp := pushJoinPredicates(ctx, joinPredicates, join)
+	if hashJoin := cheaperHashJoin(ctx, lhs, rhs, newOp, joinPredicates, joinType); hashJoin != nil {
+		return hashJoin, Rewrote("use a hash join because it is estimated to be cheaper")
+	}
 	return newOp, Rewrote("logical join to applyJoin ")
 }
 
+// cheaperHashJoin returns a HashJoin between lhs and rhs, if the statistics of the tables estimate it
+// to be cheaper than the given ApplyJoin. It returns nil if the estimates are unknown or the predicates
+// can't be evaluated by a hash join: it needs a single comparison, with known types on both sides.
+func cheaperHashJoin(ctx *plancontext.PlanningContext, lhs, rhs, applyJoin Operator, joinPredicates []sqlparser.Expr, joinType sqlparser.JoinType) Operator {
+	if len(joinPredicates) != 1 || joinType == sqlparser.StraightJoinType {
+		return nil
+	}
+	if ctx.PlanBaseline != nil && ctx.PlanBaseline.DisableHashJoin {
+		return nil
+	}
+	cmp, ok := joinPredicates[0].(*sqlparser.ComparisonExpr)
+	if !ok || !canBeSolvedWithHashJoin(cmp.Operator) {
+		return nil
+	}
+	lID, rID := TableID(lhs), TableID(rhs)
+	lDeps, rDeps := ctx.SemTable.RecursiveDeps(cmp.Left), ctx.SemTable.RecursiveDeps(cmp.Right)
+	if !(lDeps.IsSolvedBy(lID) && rDeps.IsSolvedBy(rID)) && !(lDeps.IsSolvedBy(rID) && rDeps.IsSolvedBy(lID)) {
+		return nil
+	}
+	if _, found := ctx.TypeForExpr(cmp.Left); !found {
+		return nil
+	}
+	if _, found := ctx.TypeForExpr(cmp.Right); !found {
+		return nil
+	}
+
+	apply, ok := estimateOf(ctx, applyJoin)
+	if !ok {
+		return nil
+	}
+	join := NewHashJoin(Clone(lhs), Clone(rhs), !joinType.IsInner())
+	for _, pred := range joinPredicates {
+		join.AddJoinPredicate(ctx, pred)
+	}
+	hash, ok := estimateOf(ctx, join)
+	if !ok || hash.cost >= apply.cost {
+		return nil
+	}
+	ctx.SemTable.QuerySignature.HashJoin = true
+	return join
+}
+
 func operatorsToRoutes(a, b Operator) (*Route, *Route) {
 	aRoute, ok := a.(*Route)
 	if !ok {
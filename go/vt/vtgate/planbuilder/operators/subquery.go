@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strings"
 
 	"github.com/mdibaiee/vitess/go/slice"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/semantics"
 )
@@ -45,6 +48,9 @@ type SubQuery struct {
 	JoinColumns       []applyJoinColumn    // Broken up join predicates.
 	SubqueryValueName string               // Value name returned by the subquery (uncorrelated queries).
 	HasValuesName     string               // Argument name passed to the subquery (uncorrelated queries).
+	MaxValueName      string               // Argument with the largest value of the subquery (ANY/ALL comparisons).
+	HasNullsName      string               // Argument telling if the subquery returned NULLs (ANY/ALL comparisons).
+	ComparisonType    evalengine.Type      // Type of the value compared with the subquery (ANY/ALL comparisons).
 
 	// Fields related to correlated subqueries:
 	Vars    map[string]int // Arguments copied from outer to inner, set during offset planning.
@@ -55,6 +61,15 @@ type SubQuery struct {
 
 	// IsArgument is set to true if the subquery puts the
 	IsArgument bool
+
+	// correlatedFilter is the predicate using the result of a correlated subquery.
+	// It is evaluated for every row of the outer side, after the subquery has run for that row.
+	correlatedFilter sqlparser.Expr
+
+	// This is only filled in during offset planning
+	FilterWithOffsets evalengine.Expr
+	ASTFilter         sqlparser.Expr
+	ValueOffset       *int
 }
 
 func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) Operator {
@@ -69,6 +84,25 @@ func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) Operator {
 			sq.Vars[lhsExpr.Name] = offset
 		}
 	}
+
+	if sq.correlatedFilter == nil {
+		return nil
+	}
+	cfg := &evalengine.Config{
+		ResolveType: ctx.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	}
+	rewritten := useOffsets(ctx, sq.correlatedFilter, sq)
+	eexpr, err := evalengine.Translate(rewritten, cfg)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), evalengine.ErrTranslateExprNotSupported) {
+			panic(vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s: %s", evalengine.ErrTranslateExprNotSupported, sqlparser.String(sq.correlatedFilter)))
+		}
+		panic(err)
+	}
+	sq.FilterWithOffsets = eexpr
+	sq.ASTFilter = sq.correlatedFilter
 	return nil
 }
 
@@ -177,6 +211,9 @@ func (sq *SubQuery) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparse
 }
 
 func (sq *SubQuery) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bool, addToGroupBy bool, ae *sqlparser.AliasedExpr) int {
+	if sq.isCorrelatedArgument() && sq.isSubqueryValue(ae.Expr) {
+		return sq.valueColumn(ctx)
+	}
 	ae = sqlparser.Clone(ae)
 	// we need to rewrite the column name to an argument if it's the same as the subquery column name
 	ae.Expr = rewriteColNameToArgument(ctx, ae.Expr, []*SubQuery{sq}, sq)
@@ -188,6 +225,9 @@ func (sq *SubQuery) AddWSColumn(ctx *plancontext.PlanningContext, offset int, un
 }
 
 func (sq *SubQuery) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	if sq.isCorrelatedArgument() && sq.isSubqueryValue(expr) {
+		return sq.valueColumn(ctx)
+	}
 	return sq.Outer.FindCol(ctx, expr, underRoute)
 }
 
@@ -211,13 +251,33 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Ope
 	if !sq.TopLevel {
 		panic(subqueryNotAtTopErr)
 	}
-	if sq.correlated && sq.FilterType != opcode.PulloutExists {
+	if sq.correlated && len(sq.Predicates) == 0 && sq.FilterType != opcode.PulloutExists {
 		panic(correlatedSubqueryErr)
 	}
+	if len(sq.Predicates) > 0 {
+		// the values the subquery needs must be produced by the outer side
+		available := TableID(outer).Merge(TableID(sq.Subquery))
+		for _, pred := range sq.Predicates {
+			if !ctx.SemTable.RecursiveDeps(pred).IsSolvedBy(available) {
+				panic(correlatedOuterTablesErr)
+			}
+			if sqlparser.ContainsAggregation(pred) {
+				panic(correlatedAggregationErr)
+			}
+		}
+		for _, col := range sq.OuterExpressionsNeeded(ctx, outer) {
+			if hiddenByDerivedTable(ctx, outer, col) {
+				panic(correlatedDerivedTableErr)
+			}
+		}
+	}
+	if len(sq.Predicates) > 0 && sq.FilterType == opcode.PulloutMinMax {
+		panic(vterrors.VT12001("correlated ANY/ALL/SOME comparison that cannot be merged into a single route"))
+	}
 	if sq.IsArgument {
 		if len(sq.GetMergePredicates()) > 0 {
 			// this means that we have a correlated subquery on our hands
-			panic(correlatedSubqueryErr)
+			return sq.settleCorrelatedArgument(outer)
 		}
 		sq.SubqueryValueName = sq.ArgName
 		return outer
@@ -225,7 +285,50 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Ope
 	return sq.settleFilter(ctx, outer)
 }
 
-var correlatedSubqueryErr = vterrors.VT12001("correlated subquery is only supported for EXISTS")
+// settleCorrelatedArgument plans a correlated subquery that is used as a value, and not as a filter.
+// The subquery is run for every outer row, and the value it produces is returned in a column of the outer row.
+func (sq *SubQuery) settleCorrelatedArgument(outer Operator) Operator {
+	switch sq.FilterType {
+	case opcode.PulloutValue:
+		sq.SubqueryValueName = sq.ArgName
+	case opcode.PulloutExists:
+		sq.addLimit()
+		sq.HasValuesName = sq.ArgName
+	default:
+		panic(vterrors.VT12001("correlated IN or NOT IN subquery used as a value"))
+	}
+	return outer
+}
+
+// isCorrelatedArgument returns true if the value produced by this subquery is different for every outer row
+func (sq *SubQuery) isCorrelatedArgument() bool {
+	return sq.IsArgument && len(sq.Predicates) > 0
+}
+
+// valueColumn returns the offset of the outer column that will be replaced with the value produced by the subquery
+func (sq *SubQuery) valueColumn(ctx *plancontext.PlanningContext) int {
+	if sq.ValueOffset == nil {
+		offset := sq.Outer.AddColumn(ctx, false, false, aeWrap(&sqlparser.NullVal{}))
+		sq.ValueOffset = &offset
+	}
+	return *sq.ValueOffset
+}
+
+// isSubqueryValue returns true if the expression is the column or argument that replaced this subquery
+func (sq *SubQuery) isSubqueryValue(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		return expr.Qualifier.IsEmpty() && expr.Name.EqualString(sq.ArgName)
+	case *sqlparser.Argument:
+		return expr.Name == sq.ArgName
+	}
+	return false
+}
+
+var correlatedSubqueryErr = vterrors.VT12001("correlated subquery without comparisons between the inner and outer query")
+var correlatedOuterTablesErr = vterrors.VT12001("correlated subquery using tables that are not available on the outer side")
+var correlatedAggregationErr = vterrors.VT12001("aggregation in the predicates of a correlated subquery")
+var correlatedDerivedTableErr = vterrors.VT12001("correlated subquery inside a derived table")
 var subqueryNotAtTopErr = vterrors.VT12001("unmergable subquery can not be inside complex expression")
 
 func (sq *SubQuery) addLimit() {
@@ -238,10 +341,8 @@ func (sq *SubQuery) addLimit() {
 }
 
 func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operator) Operator {
-	if len(sq.Predicates) > 0 {
-		if sq.FilterType != opcode.PulloutExists {
-			panic(correlatedSubqueryErr)
-		}
+	if len(sq.Predicates) > 0 && sq.FilterType == opcode.PulloutExists {
+		// the semi join only keeps the outer rows that the subquery returns rows for
 		sq.addLimit()
 		return outer
 	}
@@ -252,18 +353,20 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 		return s
 	}
 	post := func(cursor *sqlparser.CopyOnWriteCursor) {
-		node := cursor.Node()
-		if _, ok := node.(*sqlparser.Subquery); !ok {
-			return
-		}
-
-		var arg sqlparser.Expr
-		if sq.FilterType.NeedsListArg() {
-			arg = sqlparser.NewListArg(sq.ArgName)
-		} else {
-			arg = sqlparser.NewArgument(sq.ArgName)
+		switch node := cursor.Node().(type) {
+		case *sqlparser.Subquery:
+			var arg sqlparser.Expr
+			if sq.FilterType.NeedsListArg() {
+				arg = sqlparser.NewListArg(sq.ArgName)
+			} else {
+				arg = sqlparser.NewArgument(sq.ArgName)
+			}
+			cursor.Replace(arg)
+		case *sqlparser.ComparisonExpr:
+			if node.Modifier != sqlparser.Missing {
+				cursor.Replace(sq.rewriteQuantifiedComparison(ctx, node))
+			}
 		}
-		cursor.Replace(arg)
 	}
 	rhsPred := sqlparser.CopyOnRewrite(sq.Original, dontEnterSubqueries, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
 
@@ -285,13 +388,121 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 			Right: rhsPred,
 		})
 		sq.SubqueryValueName = sq.ArgName
-	case opcode.PulloutValue:
+	case opcode.PulloutValue, opcode.PulloutMinMax:
 		predicates = append(predicates, rhsPred)
 		sq.SubqueryValueName = sq.ArgName
 	}
+	if len(sq.Predicates) > 0 {
+		// the subquery has to run once for every outer row, so the
+		// predicate is evaluated by the semi join and not by a filter
+		sq.correlatedFilter = sqlparser.AndExpressions(predicates...)
+		return outer
+	}
 	return newFilter(outer, predicates...)
 }
 
+// rewriteQuantifiedComparison rewrites a comparison using ANY, SOME or ALL, after the subquery has been
+// replaced by an argument. `= ANY` and `<> ALL` become IN and NOT IN. The other comparisons are rewritten
+// to only use the smallest and largest non-NULL values of the subquery, while keeping the NULL semantics:
+//
+//	x > ANY (subquery) => :has_values and (x > :min or :has_nulls and null)
+//	x > ALL (subquery) => not :has_values or x > :max and (not :has_nulls or null)
+func (sq *SubQuery) rewriteQuantifiedComparison(ctx *plancontext.PlanningContext, cmp *sqlparser.ComparisonExpr) sqlparser.Expr {
+	switch sq.FilterType {
+	case opcode.PulloutIn:
+		return &sqlparser.ComparisonExpr{Operator: sqlparser.InOp, Left: cmp.Left, Right: cmp.Right}
+	case opcode.PulloutNotIn:
+		return &sqlparser.ComparisonExpr{Operator: sqlparser.NotInOp, Left: cmp.Left, Right: cmp.Right}
+	case opcode.PulloutMinMax:
+	default:
+		panic(vterrors.VT13001(fmt.Sprintf("unexpected opcode for ANY/ALL/SOME comparison: %s", sq.FilterType.String())))
+	}
+
+	// the smallest and largest values depend on how they are compared with the outer value
+	typ, found := typeOfComparedValue(ctx, cmp.Left)
+	if !found {
+		panic(vterrors.VT12001("ANY/ALL/SOME comparison of a value with an unknown type"))
+	}
+	sq.ComparisonType = typ
+
+	sq.HasValuesName = ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
+	sq.MaxValueName = ctx.ReservedVars.ReserveVariable(maxValueSubQueryBaseName)
+	sq.HasNullsName = ctx.ReservedVars.ReserveVariable(hasNullsSubQueryBaseName)
+
+	minArg := sqlparser.NewArgument(sq.ArgName)
+	maxArg := sqlparser.NewArgument(sq.MaxValueName)
+	compare := func(arg sqlparser.Expr) sqlparser.Expr {
+		return &sqlparser.ComparisonExpr{Operator: cmp.Operator, Left: cmp.Left, Right: arg}
+	}
+
+	// for ANY, it's enough that the comparison is true for the value that is the easiest to satisfy.
+	// for ALL, it has to be true for the value that is the hardest to satisfy.
+	var value sqlparser.Expr
+	switch cmp.Operator {
+	case sqlparser.GreaterThanOp, sqlparser.GreaterEqualOp:
+		if cmp.Modifier == sqlparser.Any {
+			value = compare(minArg)
+		} else {
+			value = compare(maxArg)
+		}
+	case sqlparser.LessThanOp, sqlparser.LessEqualOp:
+		if cmp.Modifier == sqlparser.Any {
+			value = compare(maxArg)
+		} else {
+			value = compare(minArg)
+		}
+	case sqlparser.NotEqualOp:
+		// x <> ANY: there is a value different from x
+		value = &sqlparser.OrExpr{Left: compare(minArg), Right: compare(maxArg)}
+	case sqlparser.EqualOp:
+		// x = ALL: all the values are equal to x
+		value = &sqlparser.AndExpr{Left: compare(minArg), Right: compare(maxArg)}
+	default:
+		panic(vterrors.VT13001(fmt.Sprintf("unexpected operator for ANY/ALL/SOME comparison: %s", cmp.Operator.ToString())))
+	}
+
+	hasValues := sqlparser.NewArgument(sq.HasValuesName)
+	hasNulls := sqlparser.NewArgument(sq.HasNullsName)
+	if cmp.Modifier == sqlparser.Any {
+		// when no value satisfies the comparison, any NULL in the subquery makes the result NULL
+		return &sqlparser.AndExpr{
+			Left: hasValues,
+			Right: &sqlparser.OrExpr{
+				Left:  value,
+				Right: &sqlparser.AndExpr{Left: hasNulls, Right: &sqlparser.NullVal{}},
+			},
+		}
+	}
+	// when all the values satisfy the comparison, any NULL in the subquery makes the result NULL
+	return &sqlparser.OrExpr{
+		Left: sqlparser.NewNotExpr(hasValues),
+		Right: &sqlparser.AndExpr{
+			Left:  value,
+			Right: &sqlparser.OrExpr{Left: sqlparser.NewNotExpr(hasNulls), Right: &sqlparser.NullVal{}},
+		},
+	}
+}
+
+const (
+	maxValueSubQueryBaseName = "__sq_max"
+	hasNullsSubQueryBaseName = "__sq_has_nulls"
+)
+
+// hiddenByDerivedTable returns true if the column belongs to a table inside a derived table,
+// which means it is not visible outside the derived table
+func hiddenByDerivedTable(ctx *plancontext.PlanningContext, op Operator, col *sqlparser.ColName) bool {
+	deps := ctx.SemTable.RecursiveDeps(col)
+	hidden := false
+	_ = Visit(op, func(this Operator) error {
+		p, ok := this.(*Projection)
+		if ok && p.isDerived() && !deps.IsSolvedBy(p.DT.TableID) && deps.IsSolvedBy(TableID(p.Source)) {
+			hidden = true
+		}
+		return nil
+	})
+	return hidden
+}
+
 func dontEnterSubqueries(node, _ sqlparser.SQLNode) bool {
 	if _, ok := node.(*sqlparser.Subquery); ok {
 		return false
@@ -324,3 +535,28 @@ func (sq *SubQuery) rewriteColNameToArgument(expr sqlparser.Expr) sqlparser.Expr
 
 	return sqlparser.Rewrite(expr, pre, nil).(sqlparser.Expr)
 }
+
+// typeOfComparedValue returns the type of the value compared with the subquery of an ANY/ALL comparison.
+// Expressions that are not typed by the semantic analysis are typed by evalengine, as long as the types
+// of all the columns they use are known.
+func typeOfComparedValue(ctx *plancontext.PlanningContext, expr sqlparser.Expr) (evalengine.Type, bool) {
+	if typ, found := ctx.TypeForExpr(expr); found {
+		return typ, true
+	}
+	cfg := &evalengine.Config{
+		ResolveColumn: func(*sqlparser.ColName) (int, error) { return 0, nil },
+		ResolveType:   ctx.TypeForExpr,
+		Collation:     ctx.SemTable.Collation,
+		Environment:   ctx.VSchema.Environment(),
+	}
+	eexpr, err := evalengine.Translate(expr, cfg)
+	if err != nil {
+		return evalengine.Type{}, false
+	}
+	// the columns without a known type make this fail, since there is no row to take their type from
+	typ, err := evalengine.EmptyExpressionEnv(ctx.VSchema.Environment()).TypeOf(eexpr)
+	if err != nil {
+		return evalengine.Type{}, false
+	}
+	return typ, true
+}
@@ -18,6 +18,7 @@ package operators
 
 import (
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/semantics"
@@ -165,6 +166,9 @@ func createSubquery(
 	original = cloneASTAndSemState(ctx, original)
 	originalSq := cloneASTAndSemState(ctx, subq)
 	subqID := findTablesContained(ctx, subq.Select)
+	// when the subquery is nested inside another subquery, the outer tables
+	// we get also contain the tables of this subquery, which are not outer tables for it
+	outerID = outerID.Remove(subqID)
 	totalID := subqID.Merge(outerID)
 	sqc := &SubQueryBuilder{totalID: totalID, subqID: subqID, outerID: outerID}
 
@@ -269,14 +273,21 @@ func createComparisonSubQuery(
 	}
 
 	filterType := opcode.PulloutValue
-	switch parent.Operator {
-	case sqlparser.InOp:
+	switch {
+	case parent.Modifier != sqlparser.Missing:
+		filterType = quantifiedComparisonOpcode(parent)
+	case parent.Operator == sqlparser.InOp:
 		filterType = opcode.PulloutIn
-	case sqlparser.NotInOp:
+	case parent.Operator == sqlparser.NotInOp:
 		filterType = opcode.PulloutNotIn
 	}
 
 	subquery := createSubquery(ctx, original, subq, outerID, parent, name, filterType, false)
+	if filterType == opcode.PulloutMinMax {
+		// the outer rows are not compared with a single inner row,
+		// so there is no predicate we can use to merge the two sides
+		return subquery
+	}
 
 	// if we are comparing with a column from the inner subquery,
 	// we add this extra predicate to check if the two sides are mergable or not
@@ -291,6 +302,22 @@ func createComparisonSubQuery(
 	return subquery
 }
 
+// quantifiedComparisonOpcode returns the pullout opcode used to plan a comparison using ANY, SOME or ALL.
+// `= ANY` is the same as IN and `<> ALL` is the same as NOT IN, all the other comparisons
+// only need the smallest and largest values of the subquery.
+func quantifiedComparisonOpcode(cmp *sqlparser.ComparisonExpr) opcode.PulloutOpcode {
+	switch {
+	case cmp.Modifier == sqlparser.Any && cmp.Operator == sqlparser.EqualOp:
+		return opcode.PulloutIn
+	case cmp.Modifier == sqlparser.All && cmp.Operator == sqlparser.NotEqualOp:
+		return opcode.PulloutNotIn
+	}
+	if _, isTuple := cmp.Left.(sqlparser.ValTuple); isTuple {
+		panic(vterrors.VT12001("ANY/ALL/SOME comparison of a tuple with a subquery"))
+	}
+	return opcode.PulloutMinMax
+}
+
 func (sqb *SubQueryBuilder) pullOutValueSubqueries(
 	ctx *plancontext.PlanningContext,
 	expr sqlparser.Expr,
@@ -327,6 +354,9 @@ func getOpCodeFromParent(parent sqlparser.SQLNode) *opcode.PulloutOpcode {
 	case *sqlparser.ExistsExpr:
 		return nil
 	case *sqlparser.ComparisonExpr:
+		if parent.Modifier != sqlparser.Missing {
+			panic(vterrors.VT12001("ANY/ALL/SOME comparison operator used as a value"))
+		}
 		switch parent.Operator {
 		case sqlparser.InOp:
 			code = opcode.PulloutIn
@@ -42,6 +42,11 @@ func isMergeable(ctx *plancontext.PlanningContext, query sqlparser.SelectStateme
 
 	switch node := query.(type) {
 	case *sqlparser.Select:
+		if !windowsCanBePushed(ctx, node, op) {
+			// window functions need to see all the rows of their partition
+			return false
+		}
+
 		if node.GroupBy != nil && len(node.GroupBy.Exprs) > 0 {
 			// iff we are grouping, we need to check that we can perform the grouping inside a single shard, and we check that
 			// by checking that one of the grouping expressions used is a unique single column vindex.
@@ -232,6 +237,8 @@ func tryPushSubQueryInJoin(
 	for _, predicate := range inner.GetMergePredicates() {
 		deps = deps.Merge(ctx.SemTable.RecursiveDeps(predicate))
 	}
+	// the expression using the subquery has to be evaluated where its columns are available as well
+	deps = deps.Merge(ctx.SemTable.RecursiveDeps(inner.Original))
 	deps = deps.Remove(innerID)
 
 	// in general, we don't want to push down uncorrelated subqueries into the RHS of a join,
@@ -109,6 +109,14 @@ func createOperatorFromUpdate(ctx *plancontext.PlanningContext, updStmt *sqlpars
 		return createUpdateWithInputOp(ctx, updStmt)
 	}
 
+	// Changing the primary vindex columns can move the rows to a different shard,
+	// so the rows have to be deleted and inserted again instead of being updated in place.
+	if len(childFks) == 0 && len(parentFks) == 0 {
+		if vindex := changedPrimaryVindex(ctx, updStmt); vindex != nil {
+			return createMoveRowsUpdateOp(ctx, updStmt, vindex)
+		}
+	}
+
 	var updClone *sqlparser.Update
 	var targetTbl TargetTable
 	op, targetTbl, updClone = createUpdateOperator(ctx, updStmt)
@@ -165,6 +173,7 @@ type updList []updColumn
 
 func createUpdateWithInputOp(ctx *plancontext.PlanningContext, upd *sqlparser.Update) (op Operator) {
 	updClone := ctx.SemTable.Clone(upd).(*sqlparser.Update)
+	useClonedDerivedTables(ctx, upd.TableExprs, updClone.TableExprs)
 	upd.Limit = nil
 
 	// Prepare the update expressions list
@@ -214,6 +223,164 @@ func createUpdateWithInputOp(ctx *plancontext.PlanningContext, upd *sqlparser.Up
 	return op
 }
 
+// changedPrimaryVindex returns the primary vindex of the updated table if the update changes any of its columns
+func changedPrimaryVindex(ctx *plancontext.PlanningContext, upd *sqlparser.Update) *vindexes.ColumnVindex {
+	ti, err := ctx.SemTable.TableInfoFor(ctx.SemTable.Targets)
+	if err != nil {
+		return nil
+	}
+	vTbl := ti.GetVindexTable()
+	if vTbl == nil || !vTbl.Keyspace.Sharded || len(vTbl.ColumnVindexes) == 0 {
+		return nil
+	}
+	primary := vTbl.ColumnVindexes[0]
+	for _, ue := range upd.Exprs {
+		if slices.ContainsFunc(primary.Columns, ue.Name.Name.Equal) {
+			return primary
+		}
+	}
+	return nil
+}
+
+// createMoveRowsUpdateOp plans an update that changes the primary vindex columns of the table.
+// The rows are read from the table, deleted using their primary key and inserted again with the updated values.
+// The delete and the insert maintain the lookup vindexes of the table, also when the row ends up on another shard.
+func createMoveRowsUpdateOp(ctx *plancontext.PlanningContext, upd *sqlparser.Update, vindex *vindexes.ColumnVindex) Operator {
+	unsupported := func(reason string) error {
+		return vterrors.VT12001(fmt.Sprintf("you cannot UPDATE primary vindex columns %s; invalid update on vindex: %v", reason, vindex.Name))
+	}
+
+	target := ctx.SemTable.Targets
+	ti, err := ctx.SemTable.TableInfoFor(target)
+	if err != nil {
+		panic(vterrors.VT13001(err.Error()))
+	}
+	vTbl := ti.GetVindexTable()
+	ate := ti.GetAliasedTableExpr()
+	switch {
+	case bool(upd.Ignore):
+		panic(unsupported("with UPDATE IGNORE"))
+	case len(upd.TableExprs) != 1 || upd.TableExprs[0] != ate:
+		panic(unsupported("in a multi-table update"))
+	case !vTbl.ColumnListAuthoritative:
+		panic(unsupported("on a table without an authoritative column list"))
+	case len(vTbl.PrimaryKey) == 0:
+		panic(unsupported("on a table without a primary key"))
+	}
+	tableName, isTable := ate.Expr.(sqlparser.TableName)
+	if !isTable {
+		panic(vterrors.VT13001(fmt.Sprintf("expected a table name: %s", sqlparser.String(ate.Expr))))
+	}
+	tblName, err := ti.Name()
+	if err != nil {
+		panic(err)
+	}
+
+	newColumn := func(name sqlparser.IdentifierCI) *sqlparser.ColName {
+		col := sqlparser.NewColNameWithQualifier(name.String(), tblName)
+		ctx.SemTable.Direct[col] = target
+		ctx.SemTable.Recursive[col] = target
+		return col
+	}
+
+	for idx, ue := range upd.Exprs {
+		if !slices.ContainsFunc(vTbl.Columns, func(col vindexes.Column) bool { return col.Name.Equal(ue.Name.Name) }) {
+			panic(vterrors.VT03019(sqlparser.String(ue.Name)))
+		}
+		// MySQL evaluates the assignments from left to right, so an expression using a column that was
+		// updated by an earlier assignment sees the new value, while we only have the old row at hand.
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			switch node := node.(type) {
+			case *sqlparser.Subquery:
+				panic(unsupported("to the result of a subquery"))
+			case *sqlparser.ColName:
+				for _, prev := range upd.Exprs[:idx] {
+					if ctx.SemTable.EqualsExprWithDeps(node, prev.Name) {
+						panic(vterrors.VT12001(
+							fmt.Sprintf("'%s' column referenced in update expression '%s' is itself updated", sqlparser.String(prev.Name), sqlparser.String(ue.Expr))))
+					}
+				}
+			}
+			return true, nil
+		}, ue.Expr)
+	}
+
+	// The new row is built from the values of the old row. Every column that is not updated is copied as is,
+	// unless MySQL assigns it a value on update, and the update expressions are evaluated with the columns
+	// of the old row passed in as bind variables. Generated columns are left for MySQL to compute.
+	var uList updList
+	var insCols sqlparser.Columns
+	var insValues sqlparser.ValTuple
+	for _, col := range vTbl.Columns {
+		idx := slices.IndexFunc(upd.Exprs, func(ue *sqlparser.UpdateExpr) bool { return ue.Name.Name.Equal(col.Name) })
+		if col.Generated {
+			if idx >= 0 {
+				panic(unsupported(fmt.Sprintf("together with the generated column %s", col.Name.String())))
+			}
+			continue
+		}
+		colName := newColumn(col.Name)
+		var expr sqlparser.Expr = colName
+		switch {
+		case idx >= 0:
+			expr = upd.Exprs[idx].Expr
+		case col.OnUpdate != nil:
+			expr = sqlparser.Clone(col.OnUpdate)
+		}
+		jc := breakExpressionInLHSandRHS(ctx, expr, target)
+		uList = append(uList, updColumn{updCol: colName, jc: jc})
+		insCols = append(insCols, col.Name)
+		insValues = append(insValues, jc.RHSExpr)
+	}
+
+	var pkCols []*sqlparser.ColName
+	var pkTuple sqlparser.ValTuple
+	for _, col := range vTbl.PrimaryKey {
+		colName := newColumn(col)
+		pkCols = append(pkCols, colName)
+		pkTuple = append(pkTuple, sqlparser.Clone(colName))
+	}
+	var lhs sqlparser.Expr = pkTuple
+	if len(pkTuple) == 1 {
+		lhs = pkTuple[0]
+	}
+	del := &sqlparser.Delete{
+		TableExprs: sqlparser.TableExprs{sqlparser.Clone(ate)},
+		Where:      sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.NewComparisonExpr(sqlparser.InOp, lhs, sqlparser.ListArg(engine.DmlVals), nil)),
+	}
+	ins := &sqlparser.Insert{
+		Action:  sqlparser.InsertAct,
+		Table:   sqlparser.NewAliasedTableExpr(sqlparser.Clone(tableName), ""),
+		Columns: insCols,
+		Rows:    sqlparser.Values{insValues},
+	}
+
+	updClone := ctx.SemTable.Clone(upd).(*sqlparser.Update)
+	selectStmt := &sqlparser.Select{
+		SelectExprs: slice.Map(pkCols, func(col *sqlparser.ColName) sqlparser.SelectExpr { return aeWrap(col) }),
+		From:        updClone.TableExprs,
+		Where:       updClone.Where,
+		OrderBy:     updClone.OrderBy,
+		Limit:       updClone.Limit,
+		Lock:        sqlparser.ForUpdateLock,
+	}
+
+	var op Operator = &DMLWithInput{
+		Source:   createOperatorFromSelect(ctx, selectStmt),
+		DML:      []Operator{createOpFromStmt(ctx, del, false, ""), createOpFromStmt(ctx, ins, false, "")},
+		cols:     [][]*sqlparser.ColName{pkCols, nil},
+		updList:  []updList{nil, uList},
+		MoveRows: true,
+	}
+	if upd.Comments != nil {
+		op = &LockAndComment{
+			Source:   op,
+			Comments: upd.Comments,
+		}
+	}
+	return op
+}
+
 func prepareUpdateExpressionList(ctx *plancontext.PlanningContext, upd *sqlparser.Update) map[semantics.TableSet]updList {
 	// Any update expression requiring column value from any other table is rewritten to take it as bindvar column.
 	// E.g. UPDATE t1 join t2 on t1.col = t2.col SET t1.col = t2.col + 1 where t2.col = 10;
@@ -0,0 +1,460 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mdibaiee/vitess/go/slice"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+type (
+	// Window evaluates window functions at the vtgate level. It is used when the
+	// partitions of a window can span multiple shards, so MySQL can't evaluate them.
+	// The columns produced by this operator are the window functions, followed by
+	// the columns of the source.
+	Window struct {
+		Source Operator
+		Funcs  []*WindowFunc
+
+		offsetPlanned bool
+	}
+
+	// WindowFunc is a single window function evaluated by the Window operator
+	WindowFunc struct {
+		// Original is the window function, as it was written in the query
+		Original sqlparser.Expr
+		// Spec is the window specification with all named windows resolved
+		Spec *sqlparser.WindowSpecification
+
+		OpCode     opcode.WindowOpcode
+		AggrOpCode opcode.AggregateOpcode
+		Arg        sqlparser.Expr
+		Default    sqlparser.Expr
+		N          int64
+		Frame      *engine.WindowFrame
+
+		// These are filled in during offset planning
+		ArgOffset, DefaultOffset int
+		PartitionOffsets         []WindowOffset
+		OrderOffsets             []WindowOffset
+	}
+
+	// WindowOffset is the offset of a PARTITION BY or ORDER BY expression,
+	// and the offset of its weight_string if one is needed
+	WindowOffset struct {
+		Offset, WOffset int
+	}
+)
+
+func hasWindowFuncs(sel *sqlparser.Select) bool {
+	return sqlparser.ContainsWindowFunc(sel.SelectExprs) || sqlparser.ContainsWindowFunc(sel.OrderBy)
+}
+
+// planWindowFunctions decides where the window functions of the query will be evaluated.
+// If MySQL can evaluate them, the named windows are inlined so that the window functions can
+// be sent down as part of the projection. Otherwise, a Window operator is added on top of the source.
+func planWindowFunctions(ctx *plancontext.PlanningContext, qp *QueryProjection, sel *sqlparser.Select, src Operator) Operator {
+	rb, isRoute := src.(*Route)
+	if isRoute && rb.IsSingleShard() {
+		inlineNamedWindows(sel)
+		return src
+	}
+
+	if qp.NeedsAggregation() {
+		panic(vterrors.VT12001("window functions together with aggregation on a sharded keyspace"))
+	}
+
+	if isRoute && windowsCanBePushed(ctx, sel, rb) {
+		inlineNamedWindows(sel)
+		return src
+	}
+
+	var exprs []sqlparser.Expr
+	for _, se := range qp.SelectExprs {
+		ae, ok := se.Col.(*sqlparser.AliasedExpr)
+		if !ok {
+			panic(vterrors.VT09015())
+		}
+		exprs = append(exprs, splitWindowAvg(ae.Expr))
+	}
+	for _, order := range qp.OrderExprs {
+		exprs = append(exprs, splitWindowAvg(order.SimplifiedExpr))
+	}
+	return newWindow(ctx, src, exprs, sel.Windows)
+}
+
+func newWindow(ctx *plancontext.PlanningContext, src Operator, exprs []sqlparser.Expr, windows sqlparser.NamedWindows) *Window {
+	w := &Window{Source: src}
+	for _, expr := range exprs {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			switch node := node.(type) {
+			case *sqlparser.Subquery:
+				return false, nil
+			case sqlparser.Expr:
+				if !sqlparser.IsWindowFunc(node) {
+					return true, nil
+				}
+				if w.findFunc(ctx, node) < 0 {
+					w.Funcs = append(w.Funcs, newWindowFunc(node, windows))
+				}
+				return false, nil
+			}
+			return true, nil
+		}, expr)
+	}
+	return w
+}
+
+func newWindowFunc(expr sqlparser.Expr, windows sqlparser.NamedWindows) *WindowFunc {
+	over := sqlparser.GetOverClause(expr)
+	spec, ok := sqlparser.ResolveWindowSpec(over, windows)
+	if !ok {
+		name := over.WindowName
+		if over.WindowSpec != nil {
+			name = over.WindowSpec.Name
+		}
+		panic(vterrors.VT03034(name.String()))
+	}
+	wf := &WindowFunc{
+		Original:      expr,
+		Spec:          spec,
+		Frame:         newWindowFrame(spec.FrameClause),
+		ArgOffset:     -1,
+		DefaultOffset: -1,
+	}
+
+	switch node := expr.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch node.Type {
+		case sqlparser.CumeDistExprType:
+			wf.OpCode = opcode.WindowCumeDist
+		case sqlparser.DenseRankExprType:
+			wf.OpCode = opcode.WindowDenseRank
+		case sqlparser.PercentRankExprType:
+			wf.OpCode = opcode.WindowPercentRank
+		case sqlparser.RankExprType:
+			wf.OpCode = opcode.WindowRank
+		case sqlparser.RowNumberExprType:
+			wf.OpCode = opcode.WindowRowNumber
+		}
+	case *sqlparser.NtileExpr:
+		wf.OpCode = opcode.WindowNtile
+		wf.N = windowIntArgument(node.N, "NTILE")
+		if wf.N <= 0 {
+			panic(vterrors.VT12001("NTILE with a bucket count that is not a positive integer"))
+		}
+	case *sqlparser.LagLeadExpr:
+		wf.OpCode = opcode.WindowLag
+		if node.Type == sqlparser.LeadExprType {
+			wf.OpCode = opcode.WindowLead
+		}
+		checkNullTreatment(node.NullTreatmentClause)
+		wf.Arg = node.Expr
+		wf.Default = node.Default
+		wf.N = 1
+		if node.N != nil {
+			wf.N = windowIntArgument(node.N, strings.ToUpper(wf.OpCode.String()))
+		}
+	case *sqlparser.FirstOrLastValueExpr:
+		wf.OpCode = opcode.WindowFirstValue
+		if node.Type == sqlparser.LastValueExprType {
+			wf.OpCode = opcode.WindowLastValue
+		}
+		checkNullTreatment(node.NullTreatmentClause)
+		wf.Arg = node.Expr
+	case *sqlparser.NTHValueExpr:
+		wf.OpCode = opcode.WindowNthValue
+		checkNullTreatment(node.NullTreatmentClause)
+		if node.FromFirstLastClause != nil && node.FromFirstLastClause.Type == sqlparser.FromLastType {
+			panic(vterrors.VT12001("NTH_VALUE with FROM LAST"))
+		}
+		wf.Arg = node.Expr
+		wf.N = windowIntArgument(node.N, "NTH_VALUE")
+		if wf.N <= 0 {
+			panic(vterrors.VT12001("NTH_VALUE with a row number that is not a positive integer"))
+		}
+	case sqlparser.AggrFunc:
+		wf.OpCode = opcode.WindowAggregate
+		if sqlparser.IsDistinct(node) {
+			panic(vterrors.VT12001(fmt.Sprintf("DISTINCT in window function: %s", sqlparser.String(expr))))
+		}
+		switch node.(type) {
+		case *sqlparser.CountStar:
+			wf.AggrOpCode = opcode.AggregateCountStar
+		case *sqlparser.Count, *sqlparser.Sum, *sqlparser.Min, *sqlparser.Max:
+			wf.AggrOpCode = opcode.SupportedAggregates[node.AggrName()]
+			wf.Arg = node.GetArg()
+		default:
+			panic(vterrors.VT12001(fmt.Sprintf("window function on a sharded keyspace: %s", sqlparser.String(expr))))
+		}
+	default:
+		panic(vterrors.VT13001(fmt.Sprintf("unexpected window function %T", expr)))
+	}
+	return wf
+}
+
+// newWindowFrame converts the frame clause into the frame used by the engine,
+// and makes sure we are able to evaluate it at the vtgate level
+func newWindowFrame(frame *sqlparser.FrameClause) *engine.WindowFrame {
+	if frame == nil {
+		return nil
+	}
+	bound := func(point *sqlparser.FramePoint) engine.WindowFrameBound {
+		if point == nil {
+			// a frame with only a start has CURRENT ROW as the end
+			return engine.WindowFrameBound{Type: sqlparser.CurrentRowType}
+		}
+		b := engine.WindowFrameBound{Type: point.Type}
+		if point.Expr != nil {
+			if frame.Unit == sqlparser.FrameRangeType {
+				panic(vterrors.VT12001("RANGE frame with an offset on a sharded keyspace"))
+			}
+			b.Offset = windowIntArgument(point.Expr, "ROWS frame")
+		}
+		return b
+	}
+	return &engine.WindowFrame{
+		Unit:  frame.Unit,
+		Start: bound(frame.Start),
+		End:   bound(frame.End),
+	}
+}
+
+func checkNullTreatment(clause *sqlparser.NullTreatmentClause) {
+	if clause != nil && clause.Type == sqlparser.IgnoreNullsType {
+		panic(vterrors.VT12001("IGNORE NULLS in window function"))
+	}
+}
+
+// windowIntArgument returns the value of an integer literal used as an argument to a window function
+func windowIntArgument(expr sqlparser.Expr, usage string) int64 {
+	lit, ok := expr.(*sqlparser.Literal)
+	if ok && lit.Type == sqlparser.IntVal {
+		if val, err := strconv.ParseInt(lit.Val, 10, 64); err == nil {
+			return val
+		}
+	}
+	panic(vterrors.VT12001(fmt.Sprintf("%s with a non-literal argument on a sharded keyspace: %s", usage, sqlparser.String(expr))))
+}
+
+// splitWindowAvg rewrites AVG window functions into a SUM divided by a COUNT over the same window
+func splitWindowAvg(expr sqlparser.Expr) sqlparser.Expr {
+	return sqlparser.CopyOnRewrite(expr, func(node, _ sqlparser.SQLNode) bool {
+		_, isSubq := node.(*sqlparser.Subquery)
+		return !isSubq
+	}, func(cursor *sqlparser.CopyOnWriteCursor) {
+		avg, ok := cursor.Node().(*sqlparser.Avg)
+		if !ok || avg.OverClause == nil {
+			return
+		}
+		if avg.Distinct {
+			panic(vterrors.VT12001(fmt.Sprintf("DISTINCT in window function: %s", sqlparser.String(avg))))
+		}
+		cursor.Replace(&sqlparser.BinaryExpr{
+			Operator: sqlparser.DivOp,
+			Left:     &sqlparser.Sum{Arg: avg.Arg, OverClause: avg.OverClause},
+			Right:    &sqlparser.Count{Args: sqlparser.Exprs{avg.Arg}, OverClause: avg.OverClause},
+		})
+	}, nil).(sqlparser.Expr)
+}
+
+// windowsCanBePushed returns true if all the window functions used by the query can be
+// evaluated by MySQL. This is the case when every window is partitioned by a column with
+// a unique vindex, since all rows of a partition will then live on the same shard.
+func windowsCanBePushed(ctx *plancontext.PlanningContext, sel *sqlparser.Select, op Operator) bool {
+	canPush := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Subquery:
+			return false, nil
+		case *sqlparser.OverClause:
+			spec, ok := sqlparser.ResolveWindowSpec(node, sel.Windows)
+			if !ok || !slices.ContainsFunc(spec.PartitionClause, func(expr sqlparser.Expr) bool {
+				vindex := findColumnVindex(ctx, op, expr)
+				return vindex != nil && vindex.IsUnique()
+			}) {
+				canPush = false
+				return false, io.EOF
+			}
+		}
+		return true, nil
+	}, sel.SelectExprs, sel.OrderBy)
+	return canPush
+}
+
+// inlineNamedWindows replaces references to named windows with the window specification they refer to.
+// This is needed when the query is rebuilt from its parts, since the WINDOW clause is not kept.
+func inlineNamedWindows(sel *sqlparser.Select) {
+	if len(sel.Windows) == 0 {
+		return
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Subquery:
+			return false, nil
+		case *sqlparser.OverClause:
+			spec, ok := sqlparser.ResolveWindowSpec(node, sel.Windows)
+			if !ok {
+				name := node.WindowName
+				if node.WindowSpec != nil {
+					name = node.WindowSpec.Name
+				}
+				panic(vterrors.VT03034(name.String()))
+			}
+			node.WindowName = sqlparser.IdentifierCI{}
+			node.WindowSpec = spec
+		}
+		return true, nil
+	}, sel.SelectExprs, sel.OrderBy)
+	sel.Windows = nil
+}
+
+func (w *Window) findFunc(ctx *plancontext.PlanningContext, expr sqlparser.Expr) int {
+	for i, f := range w.Funcs {
+		if ctx.SemTable.EqualsExprWithDeps(f.Original, expr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *Window) Clone(inputs []Operator) Operator {
+	funcs := slice.Map(w.Funcs, func(f *WindowFunc) *WindowFunc {
+		clone := *f
+		clone.PartitionOffsets = slices.Clone(f.PartitionOffsets)
+		clone.OrderOffsets = slices.Clone(f.OrderOffsets)
+		return &clone
+	})
+	return &Window{
+		Source:        inputs[0],
+		Funcs:         funcs,
+		offsetPlanned: w.offsetPlanned,
+	}
+}
+
+func (w *Window) Inputs() []Operator {
+	return []Operator{w.Source}
+}
+
+func (w *Window) SetInputs(operators []Operator) {
+	w.Source = operators[0]
+}
+
+// AddPredicate implements the Operator interface.
+// Predicates can't be pushed through the window, since that would change which rows the window functions see.
+func (w *Window) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(w, expr)
+}
+
+func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, expr *sqlparser.AliasedExpr) int {
+	if offset := w.findFunc(ctx, expr.Expr); offset >= 0 {
+		return offset
+	}
+	if sqlparser.ContainsWindowFunc(expr.Expr) {
+		panic(vterrors.VT12001(fmt.Sprintf("window function that is not part of the SELECT list or ORDER BY: %s", sqlparser.String(expr))))
+	}
+	return len(w.Funcs) + w.Source.AddColumn(ctx, reuse, gb, expr)
+}
+
+func (w *Window) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if offset < len(w.Funcs) {
+		panic(vterrors.VT12001(fmt.Sprintf("weight_string of window function: %s", sqlparser.String(w.Funcs[offset].Original))))
+	}
+	return len(w.Funcs) + w.Source.AddWSColumn(ctx, offset-len(w.Funcs), underRoute)
+}
+
+func (w *Window) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	if offset := w.findFunc(ctx, expr); offset >= 0 {
+		return offset
+	}
+	if sqlparser.ContainsWindowFunc(expr) {
+		return -1
+	}
+	offset := w.Source.FindCol(ctx, expr, underRoute)
+	if offset < 0 {
+		return offset
+	}
+	return len(w.Funcs) + offset
+}
+
+func (w *Window) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	cols := slice.Map(w.Funcs, func(f *WindowFunc) *sqlparser.AliasedExpr {
+		return aeWrap(f.Original)
+	})
+	return append(cols, w.Source.GetColumns(ctx)...)
+}
+
+func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, w)
+}
+
+func (w *Window) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	if w.offsetPlanned {
+		return nil
+	}
+	defer func() {
+		w.offsetPlanned = true
+	}()
+
+	// the source offsets are all relative to the source, since that is what the engine primitive sees
+	addColumn := func(expr sqlparser.Expr) int {
+		return w.Source.AddColumn(ctx, true, false, aeWrap(expr))
+	}
+	addWithWS := func(expr sqlparser.Expr) WindowOffset {
+		offset := WindowOffset{Offset: addColumn(expr), WOffset: -1}
+		if ctx.SemTable.NeedsWeightString(expr) {
+			offset.WOffset = addColumn(&sqlparser.WeightStringFuncExpr{Expr: expr})
+		}
+		return offset
+	}
+
+	for _, f := range w.Funcs {
+		if f.Arg != nil {
+			f.ArgOffset = addColumn(f.Arg)
+		}
+		if f.Default != nil {
+			f.DefaultOffset = addColumn(f.Default)
+		}
+		for _, expr := range f.Spec.PartitionClause {
+			f.PartitionOffsets = append(f.PartitionOffsets, addWithWS(expr))
+		}
+		for _, order := range f.Spec.OrderClause {
+			f.OrderOffsets = append(f.OrderOffsets, addWithWS(order.Expr))
+		}
+	}
+	return nil
+}
+
+func (w *Window) ShortDescription() string {
+	return strings.Join(slice.Map(w.Funcs, func(f *WindowFunc) string {
+		return sqlparser.String(f.Original)
+	}), ", ")
+}
@@ -36,7 +36,9 @@ import (
 	"github.com/mdibaiee/vitess/go/test/utils"
 	"github.com/mdibaiee/vitess/go/test/vschemawrapper"
 	"github.com/mdibaiee/vitess/go/vt/key"
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	topodatapb "github.com/mdibaiee/vitess/go/vt/proto/topodata"
+	vschemapb "github.com/mdibaiee/vitess/go/vt/proto/vschema"
 	"github.com/mdibaiee/vitess/go/vt/sidecardb"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/topo/memorytopo"
@@ -83,8 +85,19 @@ func (s *planTestSuite) TestPlan() {
 	}
 	s.addPKs(vschemaWrapper.V, "user", []string{"user", "music"})
 	s.addPKsProvided(vschemaWrapper.V, "user", []string{"user_extra"}, []string{"id", "user_id"})
+	s.addPKsProvided(vschemaWrapper.V, "user", []string{"authoritative"}, []string{"user_id"})
+	s.addPKsProvided(vschemaWrapper.V, "user", []string{"authoritative_generated"}, []string{"user_id"})
+	s.addPKsProvided(vschemaWrapper.V, "user", []string{"samecolvin"}, []string{"col"})
 	s.addPKsProvided(vschemaWrapper.V, "ordering", []string{"order"}, []string{"oid", "region_id"})
 	s.addPKsProvided(vschemaWrapper.V, "ordering", []string{"order_event"}, []string{"oid", "ename"})
+	s.setColumnOptions(vschemaWrapper.V, "user", "authoritative_generated", func(col *vindexes.Column) {
+		switch col.Name.String() {
+		case "col1_upper":
+			col.Generated = true
+		case "updated_at":
+			col.OnUpdate = &sqlparser.CurTimeFuncExpr{Name: sqlparser.NewIdentifierCI("current_timestamp")}
+		}
+	})
 
 	// You will notice that some tests expect user.Id instead of user.id.
 	// This is because we now pre-create vindex columns in the symbol
@@ -260,6 +273,15 @@ func (s *planTestSuite) addPKsProvided(vschema *vindexes.VSchema, ks string, tbl
 	}
 }
 
+// setColumnOptions sets the options the schema tracker would find in the table definition, which the vschema can't express
+func (s *planTestSuite) setColumnOptions(vschema *vindexes.VSchema, ks, tblName string, setOptions func(col *vindexes.Column)) {
+	tbl, err := vschema.FindTable(ks, tblName)
+	require.NoError(s.T(), err)
+	for i := range tbl.Columns {
+		setOptions(&tbl.Columns[i])
+	}
+}
+
 func (s *planTestSuite) TestSystemTables57() {
 	// first we move everything to use 5.7 logic
 	env, err := vtenv.New(vtenv.Options{
@@ -293,6 +315,68 @@ func (s *planTestSuite) TestViews() {
 	s.testFile("view_cases.json", vschemaWrapper, false)
 }
 
+// TestCostBasedPlanning tests that the planner orders joins and chooses hash joins
+// using the table statistics collected by the schema tracker.
+func (s *planTestSuite) TestCostBasedPlanning() {
+	vschema := loadSchema(s.T(), "vschemas/schema.json", true)
+	s.addPKs(vschema, "user", []string{"user", "music"})
+	tables := vschema.Keyspaces["user"].Tables
+	tables["user"].Stats = &vindexes.TableStats{Rows: 100000, Cardinality: map[string]uint64{"col": 100000}}
+	tables["user_extra"].Stats = &vindexes.TableStats{Rows: 1000000, Cardinality: map[string]uint64{"col": 1000}}
+	tables["music"].Stats = &vindexes.TableStats{Rows: 50, Cardinality: map[string]uint64{"col": 5}}
+	vschema.Keyspaces["main"].Tables["unsharded"].Stats = &vindexes.TableStats{Rows: 10}
+	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
+		V:           vschema,
+		TestBuilder: TestBuilder,
+		Env:         vtenv.NewTestEnv(),
+	}
+
+	s.testFile("cost_based_cases.json", vschemaWrapper, false)
+}
+
+// TestPlanBaselines tests that plan baselines pin the join order, planner
+// and hash join use of the queries sharing their fingerprint.
+func (s *planTestSuite) TestPlanBaselines() {
+	vschema := loadSchema(s.T(), "vschemas/schema.json", true)
+	s.addPKs(vschema, "user", []string{"user", "music"})
+	tables := vschema.Keyspaces["user"].Tables
+	tables["user"].Stats = &vindexes.TableStats{Rows: 100000, Cardinality: map[string]uint64{"col": 100000}}
+	tables["user_extra"].Stats = &vindexes.TableStats{Rows: 1000000, Cardinality: map[string]uint64{"col": 1000}}
+	tables["music"].Stats = &vindexes.TableStats{Rows: 50, Cardinality: map[string]uint64{"col": 5}}
+
+	env := vtenv.NewTestEnv()
+	baselines := []*vschemapb.PlanBaseline{{
+		Query:           "select u.col, ue.col from user u join user_extra ue on u.col = ue.col",
+		DisableHashJoin: true,
+	}, {
+		Query:           "select u.col, m.col from user u join music m on u.col = m.col where m.id = 1",
+		JoinOrder:       []string{"u", "m"},
+		DisableHashJoin: true,
+	}, {
+		Query:           "select u.col, ue.col, m.col from user u join user_extra ue on u.col = ue.col join music m on ue.col = m.col",
+		Planner:         querypb.ExecuteOptions_Gen4Left2Right,
+		DisableHashJoin: true,
+	}, {
+		Query:           "select u.col, m.col, last_insert_id() from user u join music m on u.col = m.col where m.id = 1",
+		JoinOrder:       []string{"u", "m"},
+		DisableHashJoin: true,
+	}}
+	vschema.PlanBaselines = make(map[string]*vschemapb.PlanBaseline, len(baselines))
+	for _, baseline := range baselines {
+		fp, err := env.Parser().FingerprintQuery(baseline.Query)
+		require.NoError(s.T(), err)
+		baseline.Fingerprint = fp
+		vschema.PlanBaselines[fp] = baseline
+	}
+	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
+		V:           vschema,
+		TestBuilder: TestBuilder,
+		Env:         env,
+	}
+
+	s.testFile("plan_baseline_cases.json", vschemaWrapper, false)
+}
+
 func (s *planTestSuite) TestOne() {
 	reset := operators.EnableDebugPrinting()
 	defer reset()
@@ -18,10 +18,12 @@ package plancontext
 
 import (
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	vschemapb "github.com/mdibaiee/vitess/go/vt/proto/vschema"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/semantics"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
 )
 
 type PlanningContext struct {
@@ -62,6 +64,10 @@ type PlanningContext struct {
 	// OuterTables contains the tables that are outer to the current query
 	// Used to set the nullable flag on the columns
 	OuterTables semantics.TableSet
+
+	// PlanBaseline is the baseline pinned for the statement, if any. It forces
+	// the planner version, join order and hash join use of the plan.
+	PlanBaseline *vschemapb.PlanBaseline
 }
 
 // CreatePlanningContext initializes a new PlanningContext with the given parameters.
@@ -86,6 +92,11 @@ func CreatePlanningContext(stmt sqlparser.Statement,
 	// record any warning as planner warning.
 	vschema.PlannerWarning(semTable.Warning)
 
+	baseline := FindPlanBaseline(vschema, stmt)
+	if baseline != nil && baseline.Planner != querypb.ExecuteOptions_DEFAULT_PLANNER {
+		version = baseline.Planner
+	}
+
 	return &PlanningContext{
 		ReservedVars:      reservedVars,
 		SemTable:          semTable,
@@ -95,9 +106,42 @@ func CreatePlanningContext(stmt sqlparser.Statement,
 		PlannerVersion:    version,
 		ReservedArguments: map[sqlparser.Expr]string{},
 		Statement:         stmt,
+		PlanBaseline:      baseline,
 	}, nil
 }
 
+// FindPlanBaseline returns the plan baseline pinned for the fingerprint of the
+// given statement, or nil if there is none.
+func FindPlanBaseline(vschema VSchema, stmt sqlparser.Statement) *vschemapb.PlanBaseline {
+	switch stmt.(type) {
+	case sqlparser.SelectStatement, *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
+	default:
+		return nil
+	}
+	vs := vschema.GetVSchema()
+	if vs == nil || len(vs.PlanBaselines) == 0 {
+		return nil
+	}
+	fp := vschema.GetQueryFingerprint()
+	if fp == "" {
+		fp = sqlparser.Fingerprint(stmt)
+	}
+	return vs.FindPlanBaseline(fp)
+}
+
+// BaselineFingerprint returns the fingerprint the plan baselines of the statement are looked up with,
+// or an empty string if the vschema has no plan baselines. It has to be computed before the statement
+// is rewritten, since vtctld fingerprints the queries of the baselines as they were written.
+func BaselineFingerprint(vs *vindexes.VSchema, stmt sqlparser.Statement) string {
+	if vs == nil || len(vs.PlanBaselines) == 0 {
+		return ""
+	}
+	if explain, ok := stmt.(*sqlparser.VExplainStmt); ok {
+		stmt = explain.Statement
+	}
+	return sqlparser.Fingerprint(stmt)
+}
+
 // GetReservedArgumentFor retrieves a reserved argument name for a given expression.
 // If the expression already has a reserved argument, it returns that name;
 // otherwise, it reserves a new name based on the expression type.
@@ -64,6 +64,10 @@ type VSchema interface {
 
 	GetForeignKeyChecksState() *bool
 
+	// GetQueryFingerprint returns the fingerprint of the statement before it was rewritten,
+	// or an empty string if it was not computed. See BaselineFingerprint.
+	GetQueryFingerprint() string
+
 	// GetVSchema returns the latest cached vindexes.VSchema
 	GetVSchema() *vindexes.VSchema
 
@@ -19,7 +19,6 @@ package planbuilder
 import (
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
-	"github.com/mdibaiee/vitess/go/vt/vterrors"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/engine"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/operators"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -32,10 +31,6 @@ func gen4UpdateStmtPlanner(
 	reservedVars *sqlparser.ReservedVars,
 	vschema plancontext.VSchema,
 ) (*planResult, error) {
-	if updStmt.With != nil {
-		return nil, vterrors.VT12001("WITH expression in UPDATE statement")
-	}
-
 	ctx, err := plancontext.CreatePlanningContext(updStmt, reservedVars, vschema, version)
 	if err != nil {
 		return nil, err
@@ -88,6 +88,12 @@ func buildVExplainVtgatePlan(ctx context.Context, explainStatement sqlparser.Sta
 		return nil, err
 	}
 	description := engine.PrimitiveToPlanDescription(innerInstruction.primitive)
+	if baseline := plancontext.FindPlanBaseline(vschema, explainStatement); baseline != nil {
+		if description.Other == nil {
+			description.Other = map[string]any{}
+		}
+		description.Other["PlanBaseline"] = baseline.Fingerprint
+	}
 	output, err := json.MarshalIndent(description, "", "\t")
 	if err != nil {
 		return nil, err
@@ -32,6 +32,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/spf13/pflag"
 
+	"github.com/mdibaiee/vitess/go/mysql/mysqlx"
 	"github.com/mdibaiee/vitess/go/mysql/replication"
 	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
 
@@ -77,6 +78,12 @@ var (
 	mysqlDefaultWorkload     int32
 
 	mysqlServerFlushDelay = 100 * time.Millisecond
+
+	mysqlServerCompressionAlgorithms []string
+	mysqlServerZlibCompressionLevel  = mysql.DefaultZlibCompressionLevel
+
+	mysqlxServerPort        = -1
+	mysqlxServerBindAddress string
 )
 
 func registerPluginFlags(fs *pflag.FlagSet) {
@@ -101,7 +108,11 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&mysqlConnBufferPooling, "mysql-server-pool-conn-read-buffers", mysqlConnBufferPooling, "If set, the server will pool incoming connection read buffers")
 	fs.DurationVar(&mysqlKeepAlivePeriod, "mysql-server-keepalive-period", mysqlKeepAlivePeriod, "TCP period between keep-alives")
 	fs.DurationVar(&mysqlServerFlushDelay, "mysql_server_flush_delay", mysqlServerFlushDelay, "Delay after which buffered response will be flushed to the client.")
+	fs.StringSliceVar(&mysqlServerCompressionAlgorithms, "mysql_server_compression_algorithms", mysqlServerCompressionAlgorithms, "Compression algorithms of the MySQL protocol the server accepts on tcp connections (zlib, zstd). The protocol is not compressed if empty.")
+	fs.IntVar(&mysqlServerZlibCompressionLevel, "mysql_server_zlib_compression_level", mysqlServerZlibCompressionLevel, "Level of the zlib compressed MySQL protocol, from 1 to 9. The zstd level is chosen by the clients.")
 	fs.StringVar(&mysqlDefaultWorkloadName, "mysql_default_workload", mysqlDefaultWorkloadName, "Default session workload (OLTP, OLAP, DBA)")
+	fs.IntVar(&mysqlxServerPort, "mysqlx_server_port", mysqlxServerPort, "If set, also listen for MySQL X Protocol connections on this port, for the clients of the document store.")
+	fs.StringVar(&mysqlxServerBindAddress, "mysqlx_server_bind_address", mysqlxServerBindAddress, "Binds on this address when listening to MySQL X Protocol.")
 }
 
 // vtgateHandler implements the Listener interface.
@@ -354,7 +365,8 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		}
 	}()
 
-	if session.Options.Workload == querypb.ExecuteOptions_OLAP {
+	// With a cursor, the results are streamed as the client fetches them.
+	if session.Options.Workload == querypb.ExecuteOptions_OLAP || prepare.CursorType&mysql.CursorTypeReadOnly != 0 {
 		_, err := vh.vtg.StreamExecute(ctx, vh, session, prepare.PrepareStmt, prepare.BindVars, callback)
 		if err != nil {
 			return sqlerror.NewSQLErrorFromError(err)
@@ -453,19 +465,27 @@ func (vh *vtgateHandler) session(c *mysql.Conn) *vtgatepb.Session {
 type mysqlServer struct {
 	tcpListener  *mysql.Listener
 	unixListener *mysql.Listener
+	xListener    *mysqlx.Listener
 	sigChan      chan os.Signal
 	vtgateHandle *vtgateHandler
+	xHandle      *xHandler
 }
 
-// initTLSConfig inits tls config for the given mysql listener
+// initTLSConfig inits tls config for the mysql and mysqlx listeners of the server
 func initTLSConfig(ctx context.Context, srv *mysqlServer, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA string, mysqlServerRequireSecureTransport bool, mysqlMinTLSVersion uint16) error {
 	serverConfig, err := vttls.ServerConfig(mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlMinTLSVersion)
 	if err != nil {
 		log.Exitf("grpcutils.TLSServerConfig failed: %v", err)
 		return err
 	}
-	srv.tcpListener.TLSConfig.Store(serverConfig)
-	srv.tcpListener.RequireSecureTransport = mysqlServerRequireSecureTransport
+	if srv.tcpListener != nil {
+		srv.tcpListener.TLSConfig.Store(serverConfig)
+		srv.tcpListener.RequireSecureTransport = mysqlServerRequireSecureTransport
+	}
+	if srv.xListener != nil {
+		srv.xListener.TLSConfig.Store(serverConfig)
+		srv.xListener.RequireSecureTransport.Store(mysqlServerRequireSecureTransport)
+	}
 	srv.sigChan = make(chan os.Signal, 1)
 	signal.Notify(srv.sigChan, syscall.SIGHUP)
 	go func() {
@@ -479,7 +499,12 @@ func initTLSConfig(ctx context.Context, srv *mysqlServer, mysqlSslCert, mysqlSsl
 					log.Errorf("grpcutils.TLSServerConfig failed: %v", err)
 				} else {
 					log.Info("grpcutils.TLSServerConfig updated")
-					srv.tcpListener.TLSConfig.Store(serverConfig)
+					if srv.tcpListener != nil {
+						srv.tcpListener.TLSConfig.Store(serverConfig)
+					}
+					if srv.xListener != nil {
+						srv.xListener.TLSConfig.Store(serverConfig)
+					}
 				}
 			}
 		}
@@ -491,7 +516,7 @@ func initTLSConfig(ctx context.Context, srv *mysqlServer, mysqlSslCert, mysqlSsl
 // It should be called only once in a process.
 func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 	// Flag is not set, just return.
-	if mysqlServerPort < 0 && mysqlServerSocketPath == "" {
+	if mysqlServerPort < 0 && mysqlServerSocketPath == "" && mysqlxServerPort < 0 {
 		return nil
 	}
 
@@ -523,6 +548,21 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 	var err error
 	srv := &mysqlServer{}
 	srv.vtgateHandle = newVtgateHandler(vtgate)
+	if mysqlxServerPort >= 0 {
+		srv.xHandle = newXHandler(vtgate)
+		srv.xListener, err = mysqlx.NewListener(
+			mysqlTCPVersion,
+			net.JoinHostPort(mysqlxServerBindAddress, fmt.Sprintf("%v", mysqlxServerPort)),
+			authServer,
+			srv.xHandle,
+			mysqlConnReadTimeout,
+			mysqlConnWriteTimeout,
+		)
+		if err != nil {
+			log.Exitf("mysqlx.NewListener failed: %v", err)
+		}
+		srv.xListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+	}
 	if mysqlServerPort >= 0 {
 		srv.tcpListener, err = mysql.NewListener(
 			mysqlTCPVersion,
@@ -539,23 +579,37 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 		if err != nil {
 			log.Exitf("mysql.NewListener failed: %v", err)
 		}
-		if mysqlSslCert != "" && mysqlSslKey != "" {
-			tlsVersion, err := vttls.TLSVersionToNumber(mysqlTLSMinVersion)
+		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		for _, name := range mysqlServerCompressionAlgorithms {
+			algorithm, err := mysql.ParseCompressionAlgorithm(name)
 			if err != nil {
 				log.Exitf("mysql.NewListener failed: %v", err)
 			}
-
-			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
+			srv.tcpListener.CompressionAlgorithms = append(srv.tcpListener.CompressionAlgorithms, algorithm)
 		}
-		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		srv.tcpListener.ZlibCompressionLevel = mysqlServerZlibCompressionLevel
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
 			log.Infof("setting mysql slow connection threshold to %v", mysqlSlowConnectWarnThreshold)
 			srv.tcpListener.SlowConnectWarnThreshold.Store(mysqlSlowConnectWarnThreshold.Nanoseconds())
 		}
+	}
+	// The TLS config of the listeners is set before they accept any connection.
+	if mysqlSslCert != "" && mysqlSslKey != "" {
+		tlsVersion, err := vttls.TLSVersionToNumber(mysqlTLSMinVersion)
+		if err != nil {
+			log.Exitf("mysql.NewListener failed: %v", err)
+		}
+
+		_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
+	}
+	if srv.tcpListener != nil {
 		// Start listening for tcp
 		go srv.tcpListener.Accept()
 	}
+	if srv.xListener != nil {
+		go srv.xListener.Accept()
+	}
 
 	if mysqlServerSocketPath != "" {
 		err = setupUnixSocket(srv, authServer, mysqlServerSocketPath)
@@ -629,11 +683,15 @@ func (srv *mysqlServer) shutdownMysqlProtocolAndDrain() {
 		srv.unixListener.Shutdown()
 		srv.unixListener = nil
 	}
+	if srv.xListener != nil {
+		srv.xListener.Shutdown()
+		srv.xListener = nil
+	}
 	if srv.sigChan != nil {
 		signal.Stop(srv.sigChan)
 	}
 
-	if busy := srv.vtgateHandle.busyConnections.Load(); busy > 0 {
+	if busy := srv.busyConnections(); busy > 0 {
 		log.Infof("Waiting for all client connections to be idle (%d active)...", busy)
 		start := time.Now()
 		reported := start
@@ -644,11 +702,30 @@ func (srv *mysqlServer) shutdownMysqlProtocolAndDrain() {
 			}
 
 			time.Sleep(1 * time.Millisecond)
-			busy = srv.vtgateHandle.busyConnections.Load()
+			busy = srv.busyConnections()
 		}
 	}
 }
 
+// busyConnections returns the number of client connections, of all the
+// protocols, that are executing a query or are in a transaction.
+func (srv *mysqlServer) busyConnections() int32 {
+	busy := srv.vtgateHandle.busyConnections.Load()
+	if srv.xHandle != nil {
+		busy += srv.xHandle.busyConnections.Load()
+	}
+	return busy
+}
+
+// numConnections returns the number of open client connections of all the protocols.
+func (srv *mysqlServer) numConnections() int {
+	n := srv.vtgateHandle.numConnections()
+	if srv.xHandle != nil {
+		n += srv.xHandle.numConnections()
+	}
+	return n
+}
+
 func (srv *mysqlServer) rollbackAtShutdown() {
 	defer log.Flush()
 	if srv.vtgateHandle == nil {
@@ -670,11 +747,21 @@ func (srv *mysqlServer) rollbackAtShutdown() {
 			}
 		}
 	}()
+	func() {
+		if srv.xHandle != nil {
+			srv.xHandle.mu.Lock()
+			defer srv.xHandle.mu.Unlock()
+			for id, c := range srv.xHandle.connections {
+				log.Infof("Rolling back transactions associated with X Protocol connection ID: %v", id)
+				c.Close()
+			}
+		}
+	}()
 
 	// If vtgate is instead busy executing a query, the number of open conns
 	// will be non-zero. Give another second for those queries to finish.
 	for i := 0; i < 100; i++ {
-		if srv.vtgateHandle.numConnections() == 0 {
+		if srv.numConnections() == 0 {
 			log.Infof("All connections have been rolled back.")
 			return
 		}
@@ -31,6 +31,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/mdibaiee/vitess/go/mysql"
+	"github.com/mdibaiee/vitess/go/mysql/mysqlx"
 	"github.com/mdibaiee/vitess/go/mysql/replication"
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	"github.com/mdibaiee/vitess/go/test/utils"
@@ -307,6 +308,29 @@ func testInitTLSConfig(t *testing.T, serverCA bool) {
 	}
 }
 
+// TestInitTLSConfigMySQLXOnly tests that the mysqlx listener gets the TLS config when it is the only listener.
+func TestInitTLSConfigMySQLXOnly(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	root := t.TempDir()
+	tlstest.CreateCA(root)
+	tlstest.CreateCRL(root, tlstest.CA)
+	tlstest.CreateSignedCert(root, tlstest.CA, "01", "server", "server.example.com")
+
+	srv := &mysqlServer{xListener: &mysqlx.Listener{}}
+	err := initTLSConfig(ctx, srv, path.Join(root, "server-cert.pem"), path.Join(root, "server-key.pem"), path.Join(root, "ca-cert.pem"), path.Join(root, "ca-crl.pem"), "", true, tls.VersionTLS12)
+	require.NoError(t, err)
+
+	serverConfig := srv.xListener.TLSConfig.Load()
+	require.NotNil(t, serverConfig)
+	assert.True(t, srv.xListener.RequireSecureTransport.Load())
+
+	srv.sigChan <- syscall.SIGHUP
+	assert.Eventually(t, func() bool {
+		return srv.xListener.TLSConfig.Load() != serverConfig
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 // TestKillMethods test the mysql plugin for kill method calls.
 func TestKillMethods(t *testing.T) {
 	executor, _, _, _, _ := createExecutorEnv(t)
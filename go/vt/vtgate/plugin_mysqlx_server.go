@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"github.com/mdibaiee/vitess/go/mysql/mysqlx"
+	"github.com/mdibaiee/vitess/go/mysql/sqlerror"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/vt/callerid"
+	"github.com/mdibaiee/vitess/go/vt/log"
+	"github.com/mdibaiee/vitess/go/vt/topo/topoproto"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/vtgateservice"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	topodatapb "github.com/mdibaiee/vitess/go/vt/proto/topodata"
+	vtgatepb "github.com/mdibaiee/vitess/go/vt/proto/vtgate"
+)
+
+// xHandler implements the mysqlx.Handler interface, for the clients of the
+// X Protocol. Like vtgateHandler, it stores the Session in the ClientData of
+// a Connection.
+type xHandler struct {
+	mu sync.Mutex
+
+	vtg         vtgateservice.VTGateService
+	connections map[uint32]*mysqlx.Conn
+
+	busyConnections atomic.Int32
+}
+
+func newXHandler(vtg vtgateservice.VTGateService) *xHandler {
+	return &xHandler{
+		vtg:         vtg,
+		connections: make(map[uint32]*mysqlx.Conn),
+	}
+}
+
+func (xh *xHandler) NewConnection(c *mysqlx.Conn) {
+	xh.mu.Lock()
+	defer xh.mu.Unlock()
+	xh.connections[c.ConnectionID] = c
+}
+
+func (xh *xHandler) numConnections() int {
+	xh.mu.Lock()
+	defer xh.mu.Unlock()
+	return len(xh.connections)
+}
+
+func (xh *xHandler) ConnectionClosed(c *mysqlx.Conn) {
+	// Rollback if there is an ongoing transaction. Ignore error.
+	defer func() {
+		xh.mu.Lock()
+		delete(xh.connections, c.ConnectionID)
+		xh.mu.Unlock()
+	}()
+	xh.closeSession(c)
+}
+
+func (xh *xHandler) ResetSession(c *mysqlx.Conn) {
+	xh.closeSession(c)
+}
+
+// closeSession rolls back the transaction of the session, and clears it
+// for the next statements.
+func (xh *xHandler) closeSession(c *mysqlx.Conn) {
+	ctx := context.Background()
+	if mysqlQueryTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, mysqlQueryTimeout)
+		defer cancel()
+	}
+	session := xh.session(c)
+	if session.InTransaction {
+		defer xh.busyConnections.Add(-1)
+	}
+	if err := xh.vtg.CloseSession(ctx, session); err != nil {
+		log.Errorf("Error happened in transaction rollback: %v", err)
+	}
+	c.ClientData = nil
+}
+
+func (xh *xHandler) Execute(c *mysqlx.Conn, query string, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.UpdateCancelCtx(cancel)
+
+	if mysqlQueryTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, mysqlQueryTimeout)
+		defer cancel()
+	}
+
+	span, ctx, err := startSpan(ctx, query, "xHandler.Execute")
+	if err != nil {
+		return nil, err
+	}
+	defer span.Finish()
+
+	// Like for the MySQL protocol, the ImmediateCallerID is the UserData
+	// returned by the AuthServer plugin for that user.
+	im := c.UserData.Get()
+	ef := callerid.NewEffectiveCallerID(
+		c.User,                  /* principal: who */
+		c.RemoteAddr().String(), /* component: running client process */
+		"VTGate X Protocol Connector" /* subcomponent: part of the client */)
+	ctx = callerid.NewContext(ctx, ef, im)
+
+	session := xh.session(c)
+	if !session.InTransaction {
+		xh.busyConnections.Add(1)
+	}
+	defer func() {
+		if !session.InTransaction {
+			xh.busyConnections.Add(-1)
+		}
+	}()
+
+	if bindVars == nil {
+		bindVars = make(map[string]*querypb.BindVariable)
+	}
+	session, result, err := xh.vtg.Execute(ctx, xh, session, query, bindVars)
+	c.ClientData = session
+	if err != nil {
+		return nil, sqlerror.NewSQLErrorFromError(err)
+	}
+	return result, nil
+}
+
+func (xh *xHandler) Warnings(c *mysqlx.Conn) []*querypb.QueryWarning {
+	return xh.session(c).GetWarnings()
+}
+
+func (xh *xHandler) CurrentSchema(c *mysqlx.Conn) string {
+	keyspace, _, _, err := topoproto.ParseDestination(xh.session(c).TargetString, topodatapb.TabletType_PRIMARY)
+	if err != nil {
+		return ""
+	}
+	return keyspace
+}
+
+// KillConnection closes an open connection by connection ID.
+func (xh *xHandler) KillConnection(ctx context.Context, connectionID uint32) error {
+	xh.mu.Lock()
+	defer xh.mu.Unlock()
+
+	c, exists := xh.connections[connectionID]
+	if !exists {
+		return sqlerror.NewSQLError(sqlerror.ERNoSuchThread, sqlerror.SSUnknownSQLState, "Unknown thread id: %d", connectionID)
+	}
+	c.MarkForClose()
+	c.CancelCtx()
+	return nil
+}
+
+// KillQuery cancels any execution query on the provided connection ID.
+func (xh *xHandler) KillQuery(connectionID uint32) error {
+	xh.mu.Lock()
+	defer xh.mu.Unlock()
+
+	c, exists := xh.connections[connectionID]
+	if !exists {
+		return sqlerror.NewSQLError(sqlerror.ERNoSuchThread, sqlerror.SSUnknownSQLState, "Unknown thread id: %d", connectionID)
+	}
+	c.CancelCtx()
+	return nil
+}
+
+func (xh *xHandler) session(c *mysqlx.Conn) *vtgatepb.Session {
+	session, _ := c.ClientData.(*vtgatepb.Session)
+	if session == nil {
+		u, _ := uuid.NewUUID()
+		session = &vtgatepb.Session{
+			Options: &querypb.ExecuteOptions{
+				IncludedFields: querypb.ExecuteOptions_ALL,
+				Workload:       querypb.ExecuteOptions_Workload(mysqlDefaultWorkload),
+			},
+			Autocommit:           true,
+			DDLStrategy:          defaultDDLStrategy,
+			SessionUUID:          u.String(),
+			EnableSystemSettings: sysVarSetEnabled,
+			// The schema the client authenticated with.
+			TargetString: c.SchemaName,
+		}
+		c.ClientData = session
+	}
+	return session
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/mysql/mysqlx"
+)
+
+func TestXHandlerSession(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+	xh := newXHandler(&VTGate{executor: executor})
+
+	c := &mysqlx.Conn{SchemaName: "TestExecutor@replica"}
+	assert.Equal(t, "TestExecutor", xh.CurrentSchema(c))
+	assert.Equal(t, "TestExecutor@replica", xh.session(c).TargetString)
+	assert.Same(t, xh.session(c), c.ClientData)
+
+	// a reset clears the session
+	session := xh.session(c)
+	xh.ResetSession(c)
+	assert.NotSame(t, session, xh.session(c))
+
+	c = &mysqlx.Conn{}
+	assert.Empty(t, xh.CurrentSchema(c))
+}
+
+func TestXHandlerKillMethods(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+	xh := newXHandler(&VTGate{executor: executor})
+
+	err := xh.KillQuery(12345)
+	assert.ErrorContains(t, err, "Unknown thread id: 12345 (errno 1094) (sqlstate HY000)")
+	err = xh.KillConnection(context.Background(), 12345)
+	assert.ErrorContains(t, err, "Unknown thread id: 12345 (errno 1094) (sqlstate HY000)")
+
+	c := &mysqlx.Conn{ConnectionID: 1}
+	xh.NewConnection(c)
+	assert.Equal(t, 1, xh.numConnections())
+
+	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+	c.UpdateCancelCtx(cancelFunc)
+	require.NoError(t, xh.KillQuery(1))
+	require.EqualError(t, cancelCtx.Err(), "context canceled")
+	require.False(t, c.IsMarkedForClose())
+
+	cancelCtx, cancelFunc = context.WithCancel(context.Background())
+	c.UpdateCancelCtx(cancelFunc)
+	require.NoError(t, xh.KillConnection(context.Background(), 1))
+	require.EqualError(t, cancelCtx.Err(), "context canceled")
+	require.True(t, c.IsMarkedForClose())
+
+	xh.ConnectionClosed(c)
+	assert.Equal(t, 0, xh.numConnections())
+}
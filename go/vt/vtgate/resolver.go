@@ -21,6 +21,7 @@ import (
 
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	"github.com/mdibaiee/vitess/go/vt/key"
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
 	topodatapb "github.com/mdibaiee/vitess/go/vt/proto/topodata"
 	"github.com/mdibaiee/vitess/go/vt/srvtopo"
 )
@@ -66,6 +67,26 @@ func (res *Resolver) MessageStream(ctx context.Context, keyspace string, shard s
 	return res.scatterConn.MessageStream(ctx, rss, name, callback)
 }
 
+// MessageInspect returns the dead-lettered messages of a message table
+// from the primaries of all the shards of the keyspace.
+func (res *Resolver) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	rss, err := res.resolver.ResolveDestination(ctx, keyspace, topodatapb.TabletType_PRIMARY, key.DestinationAllShards{})
+	if err != nil {
+		return nil, err
+	}
+	return res.scatterConn.MessageInspect(ctx, rss, name, limit)
+}
+
+// MessageRequeue requeues dead-lettered messages of a message table
+// on the primaries of all the shards of the keyspace.
+func (res *Resolver) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	rss, err := res.resolver.ResolveDestination(ctx, keyspace, topodatapb.TabletType_PRIMARY, key.DestinationAllShards{})
+	if err != nil {
+		return 0, err
+	}
+	return res.scatterConn.MessageRequeue(ctx, rss, name, ids)
+}
+
 // GetGatewayCacheStatus returns a displayable version of the Gateway cache.
 func (res *Resolver) GetGatewayCacheStatus() TabletCacheStatusList {
 	return res.scatterConn.GetGatewayCacheStatus()
@@ -0,0 +1,318 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mdibaiee/vitess/go/cache/theine"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/stats"
+	"github.com/mdibaiee/vitess/go/vt/callerid"
+	"github.com/mdibaiee/vitess/go/vt/log"
+	"github.com/mdibaiee/vitess/go/vt/servenv"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
+	"github.com/mdibaiee/vitess/go/vt/vthash"
+
+	binlogdatapb "github.com/mdibaiee/vitess/go/vt/proto/binlogdata"
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	topodatapb "github.com/mdibaiee/vitess/go/vt/proto/topodata"
+	vtgatepb "github.com/mdibaiee/vitess/go/vt/proto/vtgate"
+)
+
+var (
+	resultCacheHits          = stats.NewCounter("ResultCacheHits", "Number of queries answered from the vtgate result cache")
+	resultCacheMisses        = stats.NewCounter("ResultCacheMisses", "Number of cacheable queries that were not found in the vtgate result cache")
+	resultCacheInvalidations = stats.NewCountersWithSingleLabel("ResultCacheInvalidations", "Number of times the cached results of a table were invalidated by a row event", "Table")
+	resultCacheStreamErrors  = stats.NewCountersWithSingleLabel("ResultCacheStreamErrors", "Number of times the stream of row events used to invalidate the result cache failed", "Keyspace")
+)
+
+// resultCacheRetryDelay is how long the result cache waits before restarting a failed stream of row events.
+var resultCacheRetryDelay = 5 * time.Second
+
+// ResultCacheKey is the key of a result in the result cache.
+type ResultCacheKey = theine.HashKey256
+
+// resultCacheStreamer streams the row events of a keyspace. It is implemented by the vstreamManager.
+type resultCacheStreamer interface {
+	VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error
+}
+
+// resultCache caches the results of read-only queries against tables that rarely change.
+//
+// Cached results are never updated in place. Every table has a generation that is part of the
+// key of the results that read from it: the row events of a table, which the cache follows with
+// one VStream per keyspace, bump its generation so that the stale results can no longer be found
+// and are eventually evicted. Results are only cached once the stream of their keyspace is running.
+type resultCache struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	store    *theine.Store[ResultCacheKey, *sqltypes.Result]
+	streamer resultCacheStreamer
+
+	mu sync.Mutex
+	// generations is keyed by keyspace for the generation of a whole keyspace,
+	// and by keyspace.table for the generation of a single table.
+	generations map[string]uint64
+	// streams tells whether the stream of row events of each keyspace is running.
+	streams map[string]bool
+}
+
+func newResultCache(ctx context.Context, streamer resultCacheStreamer, maxMemory int64) *resultCache {
+	ctx, cancel := context.WithCancel(ctx)
+	// when being endtoend tested, disable the doorkeeper to ensure reproducible results
+	doorkeeper := !servenv.TestingEndtoend
+	return &resultCache{
+		ctx:         ctx,
+		cancel:      cancel,
+		store:       theine.NewStore[ResultCacheKey, *sqltypes.Result](maxMemory, doorkeeper),
+		streamer:    streamer,
+		generations: make(map[string]uint64),
+		streams:     make(map[string]bool),
+	}
+}
+
+// Close stops following the row events and releases the cached results.
+func (rc *resultCache) Close() {
+	rc.cancel()
+	rc.store.Close()
+}
+
+// resultCacheable returns whether the results of the plan may be cached. Only the SELECTs
+// against tables of the vschema qualify, if they were asked to with the RESULT_CACHE directive
+// or if all their tables have the result_cache setting in the vschema.
+func resultCacheable(stmt sqlparser.Statement, plan *engine.Plan, vschema *vindexes.VSchema) bool {
+	sel, ok := stmt.(sqlparser.SelectStatement)
+	if !ok || plan.Type != sqlparser.StmtSelect || len(plan.TablesUsed) == 0 || vschema == nil {
+		return false
+	}
+	if sel.GetLock() != sqlparser.NoLock || !deterministic(sel) || needsSessionValues(plan.BindVarNeeds) {
+		return false
+	}
+	if s, ok := sel.(*sqlparser.Select); ok && s.Cache != nil && !*s.Cache {
+		return false
+	}
+
+	directive := sqlparser.ResultCacheDirective(stmt)
+	for _, name := range plan.TablesUsed {
+		ksName, tblName, found := strings.Cut(name, ".")
+		if !found {
+			return false
+		}
+		ks := vschema.Keyspaces[ksName]
+		if ks == nil {
+			return false
+		}
+		if directive {
+			continue
+		}
+		if tbl := ks.Tables[tblName]; tbl == nil || !tbl.ResultCache {
+			return false
+		}
+	}
+	return true
+}
+
+// deterministic returns false if the statement calls a function whose result changes
+// from one execution to the next, or depends on the session or on the time.
+func deterministic(stmt sqlparser.SelectStatement) bool {
+	result := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.CurTimeFuncExpr, *sqlparser.LockingFunc, *sqlparser.Variable:
+			result = false
+		case *sqlparser.FuncExpr:
+			switch node.Name.Lowered() {
+			case "rand", "uuid", "uuid_short", "sleep",
+				"now", "sysdate", "unix_timestamp", "utc_date", "curdate", "current_date", "curtime", "current_time", "utc_time",
+				"last_insert_id", "database", "schema", "user", "current_user", "session_user", "system_user",
+				"connection_id", "found_rows", "row_count":
+				result = false
+			}
+		}
+		return result, nil
+	}, stmt)
+	return result
+}
+
+// needsSessionValues returns true if the planner rewrote expressions of the statement into bind
+// variables that vtgate fills from the session, like last_insert_id(), database() or found_rows().
+func needsSessionValues(bvNeeds *sqlparser.BindVarNeeds) bool {
+	return bvNeeds != nil && (len(bvNeeds.NeedFunctionResult) > 0 || len(bvNeeds.NeedSystemVariable) > 0 || len(bvNeeds.NeedUserDefinedVariables) > 0)
+}
+
+// key returns the key of the results of the plan for the given bind variables, and
+// whether they can be cached: results are only cached once the streams of row events
+// of their keyspaces are running, and never inside a transaction.
+//
+// Only the results read from the primaries are cached: the row events are streamed from
+// the primaries, so a lagging replica could be read after the invalidation of its stale rows.
+func (rc *resultCache) key(ctx context.Context, vcursor *vcursorImpl, plan *engine.Plan, bindVars map[string]*querypb.BindVariable) (key ResultCacheKey, ok bool) {
+	if vcursor.tabletType != topodatapb.TabletType_PRIMARY {
+		return key, false
+	}
+	session := vcursor.safeSession
+	if session.InTransaction() || session.InReservedConn() || session.InLockSession() {
+		return key, false
+	}
+
+	hasher := vthash.New256()
+	var buf [8]byte
+	writeUint := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		_, _ = hasher.Write(buf[:])
+	}
+
+	ok = true
+	rc.mu.Lock()
+	for _, table := range plan.TablesUsed {
+		keyspace, _, _ := strings.Cut(table, ".")
+		if !rc.streams[keyspace] {
+			ok = false
+			if _, started := rc.streams[keyspace]; !started {
+				rc.streams[keyspace] = false
+				go rc.follow(keyspace)
+			}
+		}
+		_, _ = hasher.WriteString(table)
+		writeUint(rc.generations[keyspace])
+		writeUint(rc.generations[table])
+	}
+	rc.mu.Unlock()
+	if !ok {
+		return key, false
+	}
+
+	vcursor.keyForPlan(ctx, plan.Original, hasher)
+
+	names := make([]string, 0, len(bindVars))
+	for name := range bindVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, err := bindVars[name].MarshalVT()
+		if err != nil {
+			return key, false
+		}
+		_, _ = hasher.WriteString("+BindVar:")
+		_, _ = hasher.WriteString(name)
+		writeUint(uint64(len(value)))
+		_, _ = hasher.Write(value)
+	}
+
+	var sysVars []string
+	session.GetSystemVariables(func(k, v string) {
+		sysVars = append(sysVars, k+"="+v)
+	})
+	sort.Strings(sysVars)
+	for _, sysVar := range sysVars {
+		_, _ = hasher.WriteString("+SysVar:")
+		_, _ = hasher.WriteString(sysVar)
+	}
+
+	// results are not shared between users, the tablets may not let all of them read the same tables
+	_, _ = hasher.WriteString("+Caller:")
+	_, _ = hasher.WriteString(callerid.GetPrincipal(callerid.EffectiveCallerIDFromContext(ctx)))
+	_, _ = hasher.WriteString("/")
+	_, _ = hasher.WriteString(callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx)))
+	_, _ = hasher.WriteString("+Fields:")
+	_, _ = hasher.WriteString(session.GetOptions().GetIncludedFields().String())
+
+	hasher.Sum(key[:0])
+	return key, true
+}
+
+// get returns a copy of the cached result for the key.
+func (rc *resultCache) get(key ResultCacheKey) (*sqltypes.Result, bool) {
+	qr, ok := rc.store.Get(key, 0)
+	if !ok {
+		resultCacheMisses.Add(1)
+		return nil, false
+	}
+	resultCacheHits.Add(1)
+	return qr.Copy(), true
+}
+
+// set caches a copy of the result for the key.
+func (rc *resultCache) set(key ResultCacheKey, qr *sqltypes.Result) {
+	rc.store.Set(key, qr.Copy(), 0, 0)
+}
+
+// follow streams the row events of the keyspace until the cache is closed,
+// and invalidates the results of the tables the events modify.
+func (rc *resultCache) follow(keyspace string) {
+	for {
+		err := rc.streamer.VStream(rc.ctx, topodatapb.TabletType_PRIMARY, &binlogdatapb.VGtid{
+			ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: keyspace, Gtid: "current"}},
+		}, &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{Match: "/.*"}},
+		}, &vtgatepb.VStreamFlags{
+			HeartbeatInterval: 1,
+		}, func(events []*binlogdatapb.VEvent) error {
+			rc.process(keyspace, events)
+			return nil
+		})
+
+		// the events that happened while the stream was down are lost: stop caching the
+		// results of the keyspace and forget the ones that were cached until it runs again
+		rc.mu.Lock()
+		rc.streams[keyspace] = false
+		rc.generations[keyspace]++
+		rc.mu.Unlock()
+
+		if rc.ctx.Err() != nil {
+			return
+		}
+		resultCacheStreamErrors.Add(keyspace, 1)
+		log.Warningf("result cache: stream of row events for keyspace %s failed, results of the keyspace will not be cached until it restarts: %v", keyspace, err)
+
+		select {
+		case <-rc.ctx.Done():
+			return
+		case <-time.After(resultCacheRetryDelay):
+		}
+	}
+}
+
+// process invalidates the results of the tables modified by the events. The first
+// events of a stream, at the latest a heartbeat, tell the stream is running.
+func (rc *resultCache) process(keyspace string, events []*binlogdatapb.VEvent) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.streams[keyspace] = true
+	for _, event := range events {
+		switch event.Type {
+		case binlogdatapb.VEventType_ROW:
+			// the vstream manager qualifies the table names with their keyspace
+			table := event.RowEvent.GetTableName()
+			rc.generations[table]++
+			resultCacheInvalidations.Add(table, 1)
+		case binlogdatapb.VEventType_DDL, binlogdatapb.VEventType_JOURNAL:
+			rc.generations[keyspace]++
+			resultCacheInvalidations.Add(keyspace, 1)
+		}
+	}
+}
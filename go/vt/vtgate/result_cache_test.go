@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/cache/theine"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/engine"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
+
+	binlogdatapb "github.com/mdibaiee/vitess/go/vt/proto/binlogdata"
+	topodatapb "github.com/mdibaiee/vitess/go/vt/proto/topodata"
+	vtgatepb "github.com/mdibaiee/vitess/go/vt/proto/vtgate"
+)
+
+// fakeResultCacheStreamer hands the events pushed by the tests to the result cache.
+type fakeResultCacheStreamer struct {
+	keyspaces chan string
+	events    chan []*binlogdatapb.VEvent
+	processed chan struct{}
+	fail      chan error
+}
+
+func newFakeResultCacheStreamer() *fakeResultCacheStreamer {
+	return &fakeResultCacheStreamer{
+		keyspaces: make(chan string, 10),
+		events:    make(chan []*binlogdatapb.VEvent),
+		processed: make(chan struct{}),
+		fail:      make(chan error),
+	}
+}
+
+func (f *fakeResultCacheStreamer) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error {
+	f.keyspaces <- vgtid.ShardGtids[0].Keyspace
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-f.fail:
+			return err
+		case events := <-f.events:
+			if err := send(events); err != nil {
+				return err
+			}
+			f.processed <- struct{}{}
+		}
+	}
+}
+
+// send waits for the result cache to process the events.
+func (f *fakeResultCacheStreamer) send(t *testing.T, events ...*binlogdatapb.VEvent) {
+	select {
+	case f.events <- events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the result cache is not streaming")
+	}
+	<-f.processed
+}
+
+func TestResultCache(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+	streamer := newFakeResultCacheStreamer()
+	executor.results = newResultCache(ctx, streamer, 1024*1024)
+	// disable the doorkeeper so that results are cached the first time they are seen
+	executor.results.store.Close()
+	executor.results.store = theine.NewStore[ResultCacheKey, *sqltypes.Result](1024*1024, false)
+
+	session := &vtgatepb.Session{TargetString: KsTestUnsharded, Autocommit: true}
+	exec := func(sql string, wantExecCount int64) {
+		t.Helper()
+		before := sbclookup.ExecCount.Load()
+		_, err := executorExec(ctx, executor, session, sql, nil)
+		require.NoError(t, err)
+		assert.EqualValues(t, wantExecCount, sbclookup.ExecCount.Load()-before, sql)
+	}
+	query := "select /*vt+ RESULT_CACHE */ id from main1 where id = 1"
+
+	// the first query starts the stream of row events, results are not cached until it runs
+	exec(query, 1)
+	select {
+	case ks := <-streamer.keyspaces:
+		assert.Equal(t, KsTestUnsharded, ks)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the result cache did not start streaming")
+	}
+	exec(query, 1)
+	streamer.send(t, &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_HEARTBEAT})
+
+	exec(query, 1)
+	exec(query, 0)
+	exec("select /*vt+ RESULT_CACHE */ id from main1 where id = 2", 1)
+	exec("select id from main1 where id = 1", 1)
+
+	// the row events of other tables do not invalidate the results
+	streamer.send(t, &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: KsTestUnsharded + ".user_msgs"}})
+	exec(query, 0)
+	streamer.send(t, &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: KsTestUnsharded + ".main1"}})
+	exec(query, 1)
+	exec(query, 0)
+	streamer.send(t, &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_DDL})
+	exec(query, 1)
+	exec(query, 0)
+
+	// nothing is cached in transactions
+	_, err := executorExec(ctx, executor, session, "begin", nil)
+	require.NoError(t, err)
+	exec(query, 1)
+	exec(query, 1)
+	_, err = executorExec(ctx, executor, session, "rollback", nil)
+	require.NoError(t, err)
+
+	// the results read from replicas are not cached, they may lag behind the invalidations
+	session.TargetString = KsTestUnsharded + "@replica"
+	hits := resultCacheHits.Get()
+	for i := 0; i < 2; i++ {
+		_, err = executorExec(ctx, executor, session, query, nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, hits, resultCacheHits.Get())
+	session.TargetString = KsTestUnsharded
+
+	// the vschema setting caches the results without the directive
+	executor.VSchema().Keyspaces[KsTestUnsharded].Tables["main1"].ResultCache = true
+	executor.ClearPlans()
+	exec("select id from main1 where id = 1", 1)
+	exec("select id from main1 where id = 1", 0)
+
+	// when the stream fails, the cached results are forgotten until it runs again
+	resultCacheRetryDelay = 0
+	defer func() { resultCacheRetryDelay = 5 * time.Second }()
+	streamer.fail <- errors.New("stream failed")
+	<-streamer.keyspaces
+	exec(query, 1)
+	streamer.send(t, &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_HEARTBEAT})
+	exec(query, 1)
+	exec(query, 0)
+}
+
+func TestResultCacheable(t *testing.T) {
+	vschema := &vindexes.VSchema{Keyspaces: map[string]*vindexes.KeyspaceSchema{
+		"ks": {Tables: map[string]*vindexes.Table{
+			"cached":   {ResultCache: true},
+			"uncached": {},
+		}},
+	}}
+	tests := []struct {
+		query      string
+		tablesUsed []string
+		want       bool
+	}{
+		{"select * from cached", []string{"ks.cached"}, true},
+		{"select * from cached join uncached", []string{"ks.cached", "ks.uncached"}, false},
+		{"select /*vt+ RESULT_CACHE */ * from cached join uncached", []string{"ks.cached", "ks.uncached"}, true},
+		{"select /*vt+ RESULT_CACHE */ * from unknown", []string{"other.unknown"}, false},
+		{"select /*vt+ RESULT_CACHE */ 1 from dual", nil, false},
+		{"select * from cached for update", []string{"ks.cached"}, false},
+		{"select sql_no_cache * from cached", []string{"ks.cached"}, false},
+		{"select now() from cached", []string{"ks.cached"}, false},
+		{"select rand() from cached", []string{"ks.cached"}, false},
+		{"select @@sql_mode from cached", []string{"ks.cached"}, false},
+		{"select sysdate() from cached", []string{"ks.cached"}, false},
+		{"select current_timestamp() from cached", []string{"ks.cached"}, false},
+		{"select last_insert_id() from cached", []string{"ks.cached"}, false},
+		{"select * from cached where name = database()", []string{"ks.cached"}, false},
+		{"select schema() from cached", []string{"ks.cached"}, false},
+		{"select user(), current_user from cached", []string{"ks.cached"}, false},
+		{"select found_rows() from cached", []string{"ks.cached"}, false},
+		{"select row_count() from cached", []string{"ks.cached"}, false},
+		{"select * from cached union select * from cached", []string{"ks.cached"}, true},
+		{"update cached set a = 1", []string{"ks.cached"}, false},
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tt.query)
+			require.NoError(t, err)
+			plan := &engine.Plan{
+				Type:         sqlparser.ASTToStatementType(stmt),
+				TablesUsed:   tt.tablesUsed,
+				BindVarNeeds: &sqlparser.BindVarNeeds{},
+			}
+			assert.Equal(t, tt.want, resultCacheable(stmt, plan, vschema))
+		})
+	}
+}
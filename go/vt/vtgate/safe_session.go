@@ -19,6 +19,7 @@ package vtgate
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -593,6 +594,22 @@ func (session *SafeSession) ForeignKeyChecks() *bool {
 	return nil
 }
 
+// GroupConcatMaxLen returns the group_concat_max_len stored in system_variables map in the session.
+func (session *SafeSession) GroupConcatMaxLen() *int64 {
+	session.mu.Lock()
+	val, ok := session.SystemVariables[sysvars.GroupConcatMaxLen]
+	session.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	maxLen, err := strconv.ParseInt(strings.Trim(val, "'"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &maxLen
+}
+
 // SetOptions sets the options
 func (session *SafeSession) SetOptions(options *querypb.ExecuteOptions) {
 	session.mu.Lock()
@@ -550,6 +550,42 @@ func (stc *ScatterConn) MessageStream(ctx context.Context, rss []*srvtopo.Resolv
 	return allErrors.AggrError(vterrors.Aggregate)
 }
 
+// MessageInspect returns the dead-lettered messages of the message table
+// from all the shards, up to limit messages per shard.
+func (stc *ScatterConn) MessageInspect(ctx context.Context, rss []*srvtopo.ResolvedShard, name string, limit int64) (*sqltypes.Result, error) {
+	var mu sync.Mutex
+	qr := &sqltypes.Result{}
+	allErrors := stc.multiGo("MessageInspect", rss, func(rs *srvtopo.ResolvedShard, i int) error {
+		innerqr, err := rs.Gateway.MessageInspect(ctx, rs.Target, name, limit)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		qr.AppendResult(innerqr)
+		return nil
+	})
+	if err := allErrors.AggrError(vterrors.Aggregate); err != nil {
+		return nil, err
+	}
+	return qr, nil
+}
+
+// MessageRequeue requeues the dead-lettered messages with the given ids
+// in all the shards, and returns how many of them were requeued.
+func (stc *ScatterConn) MessageRequeue(ctx context.Context, rss []*srvtopo.ResolvedShard, name string, ids []*querypb.Value) (int64, error) {
+	var count atomic.Int64
+	allErrors := stc.multiGo("MessageRequeue", rss, func(rs *srvtopo.ResolvedShard, i int) error {
+		innerCount, err := rs.Gateway.MessageRequeue(ctx, rs.Target, name, ids)
+		if err != nil {
+			return err
+		}
+		count.Add(innerCount)
+		return nil
+	})
+	return count.Load(), allErrors.AggrError(vterrors.Aggregate)
+}
+
 // Close closes the underlying Gateway.
 func (stc *ScatterConn) Close() error {
 	return stc.gateway.Close(context.Background())
@@ -54,6 +54,9 @@ type (
 		tracked      map[keyspaceStr]*updateController
 		consumeDelay time.Duration
 
+		// statsInterval is how often the table statistics are refreshed, they are not collected when it is zero
+		statsInterval time.Duration
+
 		parser *sqlparser.Parser
 	}
 )
@@ -61,6 +64,13 @@ type (
 // defaultConsumeDelay is the default time, the updateController will wait before checking the schema fetch request queue.
 const defaultConsumeDelay = 1 * time.Second
 
+// statsQuery returns the estimated number of rows of every table in the keyspace,
+// together with the cardinality of the columns that are the first column of an index
+const statsQuery = "select t.table_name, t.table_rows, s.column_name, s.cardinality " +
+	"from information_schema.`tables` as t left join information_schema.statistics as s " +
+	"on s.table_schema = t.table_schema and s.table_name = t.table_name and s.seq_in_index = 1 " +
+	"where t.table_schema = database() and t.table_type = 'BASE TABLE'"
+
 // NewTracker creates the tracker object.
 func NewTracker(ch chan *discovery.TabletHealth, enableViews, enableUDFs bool, parser *sqlparser.Parser) *Tracker {
 	t := &Tracker{
@@ -81,6 +91,12 @@ func NewTracker(ch chan *discovery.TabletHealth, enableViews, enableUDFs bool, p
 	return t
 }
 
+// EnableTableStats makes the tracker collect the table statistics used by the planner,
+// and refresh them at the given interval. It must be called before Start.
+func (t *Tracker) EnableTableStats(interval time.Duration) {
+	t.statsInterval = interval
+}
+
 // LoadKeyspace loads the keyspace schema.
 func (t *Tracker) LoadKeyspace(conn queryservice.QueryService, target *querypb.Target) error {
 	err := t.loadTables(conn, target)
@@ -129,6 +145,58 @@ func (t *Tracker) loadTables(conn queryservice.QueryService, target *querypb.Tar
 	return nil
 }
 
+// loadStats loads the table statistics of the keyspace from the given tablet. It returns true if
+// the statistics of any table changed enough to make it worth planning the queries again.
+func (t *Tracker) loadStats(conn queryservice.QueryService, target *querypb.Target) (bool, error) {
+	if t.tables == nil {
+		// this can only happen in testing
+		return false, nil
+	}
+
+	qr, err := conn.Execute(t.ctx, target, statsQuery, nil, 0, 0, nil)
+	if err != nil {
+		return false, err
+	}
+
+	stats := make(map[tableNameStr]*vindexes.TableStats)
+	for _, row := range qr.Rows {
+		if len(row) != 4 {
+			continue
+		}
+		tbl := row[0].ToString()
+		ts := stats[tbl]
+		if ts == nil {
+			rows, _ := row[1].ToUint64()
+			ts = &vindexes.TableStats{Rows: rows}
+			stats[tbl] = ts
+		}
+		if row[2].IsNull() {
+			continue
+		}
+		cardinality, err := row[3].ToUint64()
+		if err != nil {
+			continue
+		}
+		if ts.Cardinality == nil {
+			ts.Cardinality = make(map[string]uint64)
+		}
+		col := strings.ToLower(row[2].ToString())
+		ts.Cardinality[col] = max(ts.Cardinality[col], cardinality)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tables.setStats(target.Keyspace, stats), nil
+}
+
+func (t *Tracker) refreshStats(th *discovery.TabletHealth) (bool, error) {
+	changed, err := t.loadStats(th.Conn, th.Target)
+	if err != nil {
+		log.Warningf("error fetching table statistics for keyspace %s: %v", th.Target.Keyspace, err)
+	}
+	return changed, err
+}
+
 func (t *Tracker) loadViews(conn queryservice.QueryService, target *querypb.Target) error {
 	if t.views == nil {
 		// This happens only when views are not enabled.
@@ -221,7 +289,12 @@ func (t *Tracker) getKeyspaceUpdateController(th *discovery.TabletHealth) *updat
 }
 
 func (t *Tracker) newUpdateController() *updateController {
-	return &updateController{update: t.updateSchema, reloadKeyspace: t.initKeyspace, signal: t.signal, consumeDelay: t.consumeDelay}
+	u := &updateController{update: t.updateSchema, reloadKeyspace: t.initKeyspace, signal: t.signal, consumeDelay: t.consumeDelay}
+	if t.statsInterval > 0 {
+		u.refreshStats = t.refreshStats
+		u.statsInterval = t.statsInterval
+	}
+	return u
 }
 
 func (t *Tracker) initKeyspace(th *discovery.TabletHealth) error {
@@ -384,7 +457,9 @@ func getColumns(tblSpec *sqlparser.TableSpec) []vindexes.Column {
 				Type:          column.Type.SQLType(),
 				CollationName: colCollation,
 				Default:       column.Type.Options.Default,
+				OnUpdate:      column.Type.Options.OnUpdate,
 				Invisible:     column.Type.Invisible(),
+				Generated:     column.Type.Options.As != nil,
 				Size:          int32(size),
 				Scale:         int32(scale),
 				Nullable:      nullable,
@@ -499,6 +574,41 @@ func (tm *tableMap) set(ks, tbl string, cols []vindexes.Column, fks []*sqlparser
 	m[tbl] = &vindexes.TableInfo{Columns: cols, ForeignKeys: fks, Indexes: indexes}
 }
 
+// setStats replaces the statistics of the tables in the keyspace, and returns true
+// if the statistics of any table changed significantly
+func (tm *tableMap) setStats(ks string, stats map[tableNameStr]*vindexes.TableStats) bool {
+	changed := false
+	for tbl, info := range tm.m[ks] {
+		ts := stats[tbl]
+		if statsChanged(info.Stats, ts) {
+			changed = true
+		}
+		// the table info can be in use by a vschema that is being built, so we replace it instead of changing it
+		tm.m[ks][tbl] = &vindexes.TableInfo{Columns: info.Columns, ForeignKeys: info.ForeignKeys, Indexes: info.Indexes, Stats: ts}
+	}
+	return changed
+}
+
+// statsChanged returns true if the estimates differ by more than a factor of two
+func statsChanged(a, b *vindexes.TableStats) bool {
+	if a == nil || b == nil {
+		return a != b
+	}
+	differs := func(x, y uint64) bool {
+		return x > 2*y || y > 2*x
+	}
+	if differs(a.Rows, b.Rows) || len(a.Cardinality) != len(b.Cardinality) {
+		return true
+	}
+	for col, card := range a.Cardinality {
+		other, ok := b.Cardinality[col]
+		if !ok || differs(card, other) {
+			return true
+		}
+	}
+	return false
+}
+
 func (tm *tableMap) get(ks, tbl string) *vindexes.TableInfo {
 	m := tm.m[ks]
 	if m == nil {
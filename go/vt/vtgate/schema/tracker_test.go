@@ -438,6 +438,60 @@ func TestUDFRetrieval(t *testing.T) {
 	testTracker(t, true, schemaDefResult, testcases)
 }
 
+// TestTableStatsRetrieval tests that the tracker loads the table statistics and only reports changes that matter to the planner.
+func TestTableStatsRetrieval(t *testing.T) {
+	target := &querypb.Target{Cell: cell, Keyspace: keyspace, Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY}
+	tablet := &topodatapb.Tablet{Keyspace: target.Keyspace, Shard: target.Shard, Type: target.TabletType}
+
+	sbc := sandboxconn.NewSandboxConn(tablet)
+	sbc.SetSchemaResult([]sandboxconn.SchemaResult{
+		tables(
+			tbl("t1", "create table t1(id bigint primary key, name varchar(50), key(name))"),
+			tbl("t2", "create table t2(id bigint primary key)"),
+		),
+		empty(),
+	})
+	fields := sqltypes.MakeTestFields("table_name|table_rows|column_name|cardinality", "varchar|uint64|varchar|int64")
+	sbc.SetResults([]*sqltypes.Result{
+		sqltypes.MakeTestResult(fields,
+			"t1|1000|id|1000",
+			"t1|1000|NAME|50",
+			"t2|10|null|null",
+		),
+		sqltypes.MakeTestResult(fields,
+			"t1|1500|id|1500",
+			"t1|1500|NAME|60",
+			"t2|10|null|null",
+		),
+		sqltypes.MakeTestResult(fields,
+			"t1|1500|id|1500",
+			"t1|1500|NAME|60",
+			"t2|100|null|null",
+		),
+	})
+
+	tracker := NewTracker(nil, false, false, sqlparser.NewTestParser())
+	require.NoError(t, tracker.AddNewKeyspace(sbc, target))
+
+	changed, err := tracker.loadStats(sbc, target)
+	require.NoError(t, err)
+	assert.True(t, changed, "first statistics")
+	tbls := tracker.Tables(keyspace)
+	assert.Equal(t, &vindexes.TableStats{Rows: 1000, Cardinality: map[string]uint64{"id": 1000, "name": 50}}, tbls["t1"].Stats)
+	assert.Equal(t, &vindexes.TableStats{Rows: 10}, tbls["t2"].Stats)
+	assert.Len(t, tbls["t1"].Columns, 2)
+
+	changed, err = tracker.loadStats(sbc, target)
+	require.NoError(t, err)
+	assert.False(t, changed, "small change in statistics")
+	assert.EqualValues(t, 1500, tracker.Tables(keyspace)["t1"].Stats.Rows)
+
+	changed, err = tracker.loadStats(sbc, target)
+	require.NoError(t, err)
+	assert.True(t, changed, "t2 grew tenfold")
+	assert.EqualValues(t, 100, tracker.Tables(keyspace)["t2"].Stats.Rows)
+}
+
 func udfs(udfs ...*querypb.UDFInfo) sandboxconn.SchemaResult {
 	return sandboxconn.SchemaResult{
 		TablesAndViews: map[string]string{},
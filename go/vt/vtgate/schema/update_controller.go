@@ -41,6 +41,12 @@ type (
 		signal         func()
 		loaded         bool
 
+		// refreshStats reloads the table statistics of the keyspace, it is nil when they are not collected.
+		// It returns true if the statistics changed enough to signal the change.
+		refreshStats  func(th *discovery.TabletHealth) (bool, error)
+		statsInterval time.Duration
+		statsLoadedAt time.Time
+
 		// we'll only log a failed keyspace loading once
 		ignore bool
 	}
@@ -60,11 +66,17 @@ func (u *updateController) consume() {
 		// todo: scan queue for multiple update from the same shard, be clever
 		item := u.getItemFromQueueLocked()
 		loaded := u.loaded
+		statsDue := u.statsDueLocked()
 		u.mu.Unlock()
 
+		// items without a schema change are only queued to refresh the table statistics
+		statsOnly := statsDue && !hasSchemaChange(item)
+
 		var success bool
 		if loaded {
-			success = u.update(item)
+			if !statsOnly {
+				success = u.update(item)
+			}
 		} else {
 			if err := u.reloadKeyspace(item); err == nil {
 				success = true
@@ -75,6 +87,12 @@ func (u *updateController) consume() {
 				success = false
 			}
 		}
+		if u.refreshStats != nil && (success || statsDue) {
+			// a failed refresh is only retried after the interval, the previous statistics are kept until then
+			changed, err := u.refreshStats(item)
+			u.setStatsLoaded(time.Now())
+			success = success || err == nil && changed
+		}
 		if success && u.signal != nil {
 			u.signal()
 		}
@@ -147,12 +165,13 @@ func (u *updateController) add(th *discovery.TabletHealth) {
 		return
 	}
 
-	// If the keyspace schema is loaded and there is no schema change detected. Then there is nothing to process.
-	if len(th.Stats.TableSchemaChanged) == 0 && len(th.Stats.ViewSchemaChanged) == 0 && !th.Stats.UdfsChanged && u.loaded {
+	// If the keyspace schema is loaded and there is no schema change detected. Then there is nothing to process,
+	// unless the table statistics need to be refreshed.
+	if !hasSchemaChange(th) && u.loaded && !u.statsDueLocked() {
 		return
 	}
 
-	if (len(th.Stats.TableSchemaChanged) > 0 || len(th.Stats.ViewSchemaChanged) > 0 || th.Stats.UdfsChanged) && u.ignore {
+	if hasSchemaChange(th) && u.ignore {
 		// we got an update for this keyspace - we need to stop ignoring it, and reload everything
 		u.ignore = false
 		u.loaded = false
@@ -170,6 +189,22 @@ func (u *updateController) add(th *discovery.TabletHealth) {
 	u.queue.items = append(u.queue.items, th)
 }
 
+// hasSchemaChange returns true if the health check reports a change in the tables, views or UDFs
+func hasSchemaChange(th *discovery.TabletHealth) bool {
+	return len(th.Stats.TableSchemaChanged) > 0 || len(th.Stats.ViewSchemaChanged) > 0 || th.Stats.UdfsChanged
+}
+
+// statsDueLocked returns true if the table statistics are collected and are older than the refresh interval
+func (u *updateController) statsDueLocked() bool {
+	return u.refreshStats != nil && time.Since(u.statsLoadedAt) >= u.statsInterval
+}
+
+func (u *updateController) setStatsLoaded(at time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.statsLoadedAt = at
+}
+
 func (u *updateController) setLoaded(loaded bool) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
@@ -314,3 +314,61 @@ func TestViewsUpdates(t *testing.T) {
 		})
 	}
 }
+
+// TestStatsRefresh tests that the table statistics are refreshed without a schema change once they are due.
+func TestStatsRefresh(t *testing.T) {
+	var updateNb, refreshNb, signalNb int
+	statsChanged := true
+	updateCont := updateController{
+		update: func(th *discovery.TabletHealth) bool {
+			updateNb++
+			return true
+		},
+		signal: func() {
+			signalNb++
+		},
+		refreshStats: func(th *discovery.TabletHealth) (bool, error) {
+			refreshNb++
+			return statsChanged, nil
+		},
+		statsInterval: time.Hour,
+		consumeDelay:  5 * time.Millisecond,
+		loaded:        true,
+	}
+
+	target := &querypb.Target{Keyspace: "ks", Shard: "0", TabletType: topodatapb.TabletType_PRIMARY}
+	tablet := &topodatapb.Tablet{Keyspace: target.Keyspace, Shard: target.Shard, Type: target.TabletType}
+	healthCheck := func(tables ...string) {
+		updateCont.add(&discovery.TabletHealth{
+			Tablet:  tablet,
+			Target:  target,
+			Serving: true,
+			Stats:   &querypb.RealtimeStats{TableSchemaChanged: tables},
+		})
+		for {
+			updateCont.mu.Lock()
+			done := updateCont.queue == nil
+			updateCont.mu.Unlock()
+			if done {
+				break
+			}
+		}
+	}
+
+	// the statistics have never been loaded, so they are due
+	healthCheck()
+	assert.Equal(t, 0, updateNb, "no schema change to update")
+	assert.Equal(t, 1, refreshNb, "statistics refreshed")
+	assert.Equal(t, 1, signalNb, "changed statistics are signalled")
+
+	// the statistics are fresh, so a health check without a schema change is ignored
+	healthCheck()
+	assert.Equal(t, 1, refreshNb, "statistics are not due")
+
+	// a schema change refreshes the statistics of the new tables, but only signals once
+	statsChanged = false
+	healthCheck("a")
+	assert.Equal(t, 1, updateNb, "schema change updated")
+	assert.Equal(t, 2, refreshNb, "statistics refreshed after the schema change")
+	assert.Equal(t, 2, signalNb, "schema change signalled")
+}
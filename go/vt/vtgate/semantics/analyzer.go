@@ -79,6 +79,7 @@ func (a *analyzer) lateInit() {
 		aliasMapCache:   map[*sqlparser.Select]map[string]exprContainer{},
 		reAnalyze:       a.reAnalyze,
 		tables:          a.tables,
+		recursiveCTEs:   map[*sqlparser.CommonTableExpr]any{},
 		aggrUDFs:        a.si.GetAggregateUDFs(),
 	}
 	a.fk = &fkManager{
@@ -456,7 +457,9 @@ func (a *analyzer) noteQuerySignature(node sqlparser.SQLNode) {
 			a.sig.Aggregation = true
 		}
 	case sqlparser.AggrFunc:
-		a.sig.Aggregation = true
+		if !sqlparser.IsWindowFunc(node) {
+			a.sig.Aggregation = true
+		}
 	case *sqlparser.Delete, *sqlparser.Update, *sqlparser.Insert:
 		a.sig.DML = true
 	}
@@ -873,8 +873,11 @@ func TestInvalidQueries(t *testing.T) {
 		sql:  "select is_free_lock('xyz') from user",
 		serr: "is_free_lock('xyz') allowed only with dual",
 	}, {
-		sql: "SELECT * FROM JSON_TABLE('[ {\"c1\": null} ]','$[*]' COLUMNS( c1 INT PATH '$.c1' ERROR ON ERROR )) as jt",
-		err: &JSONTablesError{},
+		sql:  "SELECT * FROM JSON_TABLE('[ {\"c1\": null} ]','$[*]' COLUMNS( c1 INT PATH '$.c1' ERROR ON ERROR ))",
+		serr: "VT03038: every table function must have an alias",
+	}, {
+		sql:  "SELECT * FROM JSON_TABLE('[1]','$[*]' COLUMNS( c1 INT PATH '$', c1 INT PATH '$' )) as jt",
+		serr: "Duplicate column name 'c1'",
 	}, {
 		sql:             "select does_not_exist from t1",
 		notUnshardedErr: "column 'does_not_exist' not found in table 't1'",
@@ -888,8 +891,11 @@ func TestInvalidQueries(t *testing.T) {
 		sql:  "select 1 from t1 where (id, id) in (select 1, 2, 3)",
 		serr: "Operand should contain 2 column(s)",
 	}, {
-		sql:  "WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cte WHERE n < 5) SELECT * FROM cte",
-		serr: "VT12001: unsupported: recursive common table expression",
+		sql:  "WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT max(n) + 1 FROM cte WHERE n < 5) SELECT * FROM cte",
+		serr: "VT03035: recursive common table expression 'cte' can contain neither aggregation nor window functions in recursive query block",
+	}, {
+		sql:  "WITH RECURSIVE cte (n) AS (SELECT n FROM cte UNION ALL SELECT n + 1 FROM cte WHERE n < 5) SELECT * FROM cte",
+		serr: "VT03035: recursive common table expression 'cte' should have one or more non-recursive query blocks followed by one recursive query block",
 	}, {
 		sql:  "with x as (select 1), x as (select 1) select * from x",
 		serr: "VT03013: not unique table/alias: 'x'",
@@ -168,6 +168,10 @@ func (b *binder) findDependentTableSet(current *scope, target sqlparser.TableNam
 		if tblName.Name.String() != target.Name.String() {
 			continue
 		}
+		if _, isDerived := table.(*DerivedTable); isDerived {
+			// this is also the case for common table expressions, which are turned into derived tables
+			return dependency{}, vterrors.VT03004(target.Name.String())
+		}
 		ts := b.org.tableSetFor(table.GetAliasedTableExpr())
 		c := createCertain(ts, ts, evalengine.Type{})
 		deps = deps.merge(c, false)
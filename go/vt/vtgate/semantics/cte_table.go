@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
+)
+
+// CTETable is the reference a recursive common table expression makes to itself in its recursive
+// query block. During evaluation, it contains the rows produced by the previous iteration.
+type CTETable struct {
+	tableName string
+	ASTNode   *sqlparser.AliasedTableExpr
+	columns   []ColumnInfo
+}
+
+// cteReference is collected by the early rewriter when expanding a recursive CTE,
+// so the table collector knows which table reference is the CTE referencing itself
+type cteReference struct {
+	name    string
+	columns sqlparser.Columns
+	seed    sqlparser.SelectStatement
+}
+
+var _ TableInfo = (*CTETable)(nil)
+
+// ColumnNames returns the names of the columns of the CTE
+func (cte *CTETable) ColumnNames() []string {
+	names := make([]string, 0, len(cte.columns))
+	for _, col := range cte.columns {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+// dependencies implements the TableInfo interface
+func (cte *CTETable) dependencies(colName string, org originable) (dependencies, error) {
+	ts := org.tableSetFor(cte.ASTNode)
+	for _, info := range cte.columns {
+		if strings.EqualFold(info.Name, colName) {
+			return createCertain(ts, ts, info.Type), nil
+		}
+	}
+	return &nothing{}, nil
+}
+
+// getTableSet implements the TableInfo interface
+func (cte *CTETable) getTableSet(org originable) TableSet {
+	return org.tableSetFor(cte.ASTNode)
+}
+
+// getExprFor implements the TableInfo interface
+func (cte *CTETable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Unknown column '%s' in 'field list'", s)
+}
+
+// IsInfSchema implements the TableInfo interface
+func (cte *CTETable) IsInfSchema() bool {
+	return false
+}
+
+// getColumns implements the TableInfo interface
+func (cte *CTETable) getColumns(bool) []ColumnInfo {
+	return cte.columns
+}
+
+// GetAliasedTableExpr implements the TableInfo interface
+func (cte *CTETable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return cte.ASTNode
+}
+
+func (cte *CTETable) canShortCut() shortCut {
+	return cannotShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (cte *CTETable) GetVindexTable() *vindexes.Table {
+	return nil
+}
+
+// Name implements the TableInfo interface
+func (cte *CTETable) Name() (sqlparser.TableName, error) {
+	return cte.ASTNode.TableName()
+}
+
+// authoritative implements the TableInfo interface
+func (cte *CTETable) authoritative() bool {
+	return true
+}
+
+// matches implements the TableInfo interface
+func (cte *CTETable) matches(name sqlparser.TableName) bool {
+	return cte.tableName == name.Name.String() && name.Qualifier.IsEmpty()
+}
+
+// createCTETable creates the table info for the reference a recursive CTE makes to itself.
+// The column names come from the column list of the CTE, or from the non-recursive query block,
+// and the types of the columns are decided by the non-recursive query block.
+func createCTETable(node *sqlparser.AliasedTableExpr, ref *cteReference, org originable) (*CTETable, error) {
+	firstSelect := sqlparser.GetFirstSelect(ref.seed)
+	var exprs []*sqlparser.AliasedExpr
+	for _, expr := range firstSelect.SelectExprs {
+		ae, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return nil, vterrors.VT09015()
+		}
+		exprs = append(exprs, ae)
+	}
+	if len(ref.columns) > 0 && len(ref.columns) != len(exprs) {
+		return nil, vterrors.VT03033()
+	}
+
+	typers := make([]evalengine.TypeAggregator, len(exprs))
+	err := sqlparser.VisitAllSelects(ref.seed, func(s *sqlparser.Select, idx int) error {
+		for i, expr := range s.SelectExprs {
+			ae, ok := expr.(*sqlparser.AliasedExpr)
+			if !ok || i >= len(typers) {
+				continue
+			}
+			_, _, typ := org.depsForExpr(ae.Expr)
+			if err := typers[i].Add(typ, org.collationEnv()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tbl := &CTETable{
+		tableName: node.As.String(),
+		ASTNode:   node,
+	}
+	if tbl.tableName == "" {
+		tbl.tableName = ref.name
+	}
+	for i, expr := range exprs {
+		name := expr.ColumnName()
+		if len(ref.columns) > 0 {
+			name = ref.columns[i].String()
+		}
+		tbl.columns = append(tbl.columns, ColumnInfo{
+			Name: name,
+			Type: typers[i].Type(),
+		})
+	}
+	return tbl, nil
+}
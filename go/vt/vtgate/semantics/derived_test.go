@@ -107,6 +107,17 @@ func TestScopingWDerivedTables(t *testing.T) {
 			query:         "select uu.count from (select count(*) as `count` from t1) uu",
 			directDeps:    TS1,
 			recursiveDeps: TS0,
+		}, {
+			query:         "select t.uid from user as u, lateral (select u.id as uid) as t",
+			directDeps:    TS2,
+			recursiveDeps: TS0,
+		}, {
+			query:         "select t.col from user as u join lateral (select m.col from music as m where m.user_id = u.id) as t",
+			directDeps:    TS2,
+			recursiveDeps: TS1,
+		}, {
+			query:        "select t.uid from user as u, (select u.id as uid) as t",
+			errorMessage: "column 'u.id' not found",
 		}}
 	for _, query := range queries {
 		t.Run(query.query, func(t *testing.T) {
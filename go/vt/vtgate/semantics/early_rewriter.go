@@ -37,6 +37,9 @@ type earlyRewriter struct {
 	aliasMapCache   map[*sqlparser.Select]map[string]exprContainer
 	tables          *tableCollector
 
+	// recursiveCTEs contains the CTEs defined using WITH RECURSIVE that reference themselves
+	recursiveCTEs map[*sqlparser.CommonTableExpr]any
+
 	// reAnalyze is used when we are running in the late stage, after the other parts of semantic analysis
 	// have happened, and we are introducing or changing the AST. We invoke it so all parts of the query have been
 	// typed, scoped and bound correctly
@@ -121,6 +124,10 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	if !ok || tbl.Qualifier.NotEmpty() {
 		return nil
 	}
+	if _, isSelfRef := r.tables.cteReferences[node]; isSelfRef {
+		// this is a recursive CTE referencing itself - it stays a table reference
+		return nil
+	}
 	scope := r.scoper.currentScope()
 	cte := scope.findCTE(tbl.Name.String())
 	if cte == nil {
@@ -129,6 +136,9 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	if node.As.IsEmpty() {
 		node.As = tbl.Name
 	}
+	if _, isRecursive := r.recursiveCTEs[cte]; isRecursive {
+		return r.expandRecursiveCTE(node, cte)
+	}
 	node.Expr = &sqlparser.DerivedTable{
 		Select: cte.Subquery.Select,
 	}
@@ -138,10 +148,85 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	return nil
 }
 
+// expandRecursiveCTE replaces the table reference with a derived table containing the CTE definition.
+// The reference the recursive query block makes to the CTE is recorded, so it can be treated as a table
+// that holds the rows produced by the previous iteration.
+func (r *earlyRewriter) expandRecursiveCTE(node *sqlparser.AliasedTableExpr, cte *sqlparser.CommonTableExpr) error {
+	name := cte.ID.String()
+	union, ok := cte.Subquery.Select.(*sqlparser.Union)
+	if !ok {
+		return vterrors.VT03035(name, "should contain a UNION")
+	}
+	if len(union.OrderBy) > 0 || union.Limit != nil {
+		return vterrors.VT12001("ORDER BY or LIMIT over UNION in recursive common table expression")
+	}
+	// every time the CTE is used, we need a new copy of the definition
+	union = sqlparser.Clone(union)
+	term, ok := union.Right.(*sqlparser.Select)
+	if !ok || usesCTEAlias(union.Left, name) {
+		return vterrors.VT03035(name, "should have one or more non-recursive query blocks followed by one recursive query block")
+	}
+	if term.GroupBy != nil || sqlparser.ContainsAggregation(term.SelectExprs) || sqlparser.ContainsWindowFunc(term.SelectExprs) {
+		return vterrors.VT03035(name, "can contain neither aggregation nor window functions in recursive query block")
+	}
+	if term.Distinct || len(term.OrderBy) > 0 || term.Limit != nil {
+		return vterrors.VT12001("DISTINCT, ORDER BY or LIMIT in recursive query block of common table expression")
+	}
+
+	refs, onInnerSide := findCTEReferences(term.From, name, false)
+	if onInnerSide {
+		return vterrors.VT03035(name, "must not be on the inner side of an outer join")
+	}
+	if len(refs) != 1 || countCTEAliasUses(term, name) != 1 {
+		return vterrors.VT03035(name, "must be referenced only once, and not in any subquery")
+	}
+	r.tables.cteReferences[refs[0]] = &cteReference{
+		name:    name,
+		columns: cte.Columns,
+		seed:    union.Left,
+	}
+
+	node.Expr = &sqlparser.DerivedTable{Select: union}
+	if len(cte.Columns) > 0 {
+		node.Columns = cte.Columns
+	}
+	return nil
+}
+
+// findCTEReferences returns the table expressions in the FROM clause that reference the CTE.
+// It also reports whether any of them is on the inner side of an outer join.
+func findCTEReferences(exprs sqlparser.TableExprs, name string, innerSide bool) (refs []*sqlparser.AliasedTableExpr, onInnerSide bool) {
+	for _, expr := range exprs {
+		switch expr := expr.(type) {
+		case *sqlparser.AliasedTableExpr:
+			tbl, ok := expr.Expr.(sqlparser.TableName)
+			if ok && tbl.Qualifier.IsEmpty() && tbl.Name.String() == name {
+				refs = append(refs, expr)
+				onInnerSide = onInnerSide || innerSide
+			}
+		case *sqlparser.ParenTableExpr:
+			found, inner := findCTEReferences(expr.Exprs, name, innerSide)
+			refs = append(refs, found...)
+			onInnerSide = onInnerSide || inner
+		case *sqlparser.JoinTableExpr:
+			lhs, lInner := findCTEReferences(sqlparser.TableExprs{expr.LeftExpr}, name, innerSide || expr.Join == sqlparser.RightJoinType)
+			rhs, rInner := findCTEReferences(sqlparser.TableExprs{expr.RightExpr}, name, innerSide || expr.Join == sqlparser.LeftJoinType)
+			refs = append(refs, lhs...)
+			refs = append(refs, rhs...)
+			onInnerSide = onInnerSide || lInner || rInner
+		}
+	}
+	return refs, onInnerSide
+}
+
 func (r *earlyRewriter) handleWith(node *sqlparser.With) error {
 	scope := r.scoper.currentScope()
 	for _, cte := range node.CTEs {
-		err := scope.addCTE(cte)
+		recursive := node.Recursive && usesCTEAlias(cte.Subquery.Select, cte.ID.String())
+		if recursive {
+			r.recursiveCTEs[cte] = nil
+		}
+		err := scope.addCTE(cte, recursive)
 		if err != nil {
 			return err
 		}
@@ -1045,6 +1130,14 @@ func findOnlyOneTableInfoThatHasColumn(b *binder, tbl sqlparser.TableExpr, colum
 			}
 		}
 		return nil, nil
+	case *sqlparser.JSONTableExpr:
+		tblInfo := b.tc.jsonTableFor(tbl)
+		for _, info := range tblInfo.getColumns(false /* ignoreInvisibleCol */) {
+			if column.EqualString(info.Name) {
+				return []TableInfo{tblInfo}, nil
+			}
+		}
+		return nil, nil
 	case *sqlparser.JoinTableExpr:
 		tblInfoR, err := findOnlyOneTableInfoThatHasColumn(b, tbl.RightExpr, column)
 		if err != nil {
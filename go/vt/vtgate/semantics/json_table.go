@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/ptr"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
+	"github.com/mdibaiee/vitess/go/vt/vtgate/vindexes"
+)
+
+// JSONTable is the table produced by a JSON_TABLE expression in the FROM clause.
+// Its columns are all the columns of the COLUMNS clause, including the ones of NESTED PATH clauses.
+type JSONTable struct {
+	tableName string
+	// ASTNode stands in for the JSON_TABLE expression, so the table can be
+	// looked up the same way as tables coming from aliased table expressions
+	ASTNode       *sqlparser.AliasedTableExpr
+	JSONTableExpr *sqlparser.JSONTableExpr
+	columns       []ColumnInfo
+}
+
+var _ TableInfo = (*JSONTable)(nil)
+
+func createJSONTable(node *sqlparser.JSONTableExpr, env *collations.Environment) (*JSONTable, error) {
+	if node.Alias.IsEmpty() {
+		return nil, vterrors.VT03038()
+	}
+	jt := &JSONTable{
+		tableName:     node.Alias.String(),
+		ASTNode:       &sqlparser.AliasedTableExpr{Expr: sqlparser.NewTableName(node.Alias.String())},
+		JSONTableExpr: node,
+	}
+	jt.addColumns(node.Columns, env, false)
+
+	for i, col := range jt.columns {
+		for _, other := range jt.columns[:i] {
+			if strings.EqualFold(col.Name, other.Name) {
+				return nil, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.DupFieldName, "Duplicate column name '%s'", col.Name)
+			}
+		}
+	}
+	return jt, nil
+}
+
+func (jt *JSONTable) addColumns(defs []*sqlparser.JtColumnDefinition, env *collations.Environment, nested bool) {
+	for _, def := range defs {
+		switch {
+		case def.JtOrdinal != nil:
+			// ordinality columns are only NULL for nested paths that did not produce a row
+			jt.columns = append(jt.columns, ColumnInfo{
+				Name: def.JtOrdinal.Name.String(),
+				Type: evalengine.NewTypeEx(sqltypes.Uint32, collations.CollationBinaryID, nested, 0, 0, nil),
+			})
+		case def.JtPath != nil:
+			jt.columns = append(jt.columns, ColumnInfo{
+				Name: def.JtPath.Name.String(),
+				Type: JSONTableColumnType(def.JtPath, env),
+			})
+		case def.JtNestedPath != nil:
+			jt.addColumns(def.JtNestedPath.Columns, env, true)
+		}
+	}
+}
+
+// JSONTableColumnType returns the type of a PATH column of a JSON_TABLE
+func JSONTableColumnType(col *sqlparser.JtPathColDef, env *collations.Environment) evalengine.Type {
+	if col.JtColExists {
+		// EXISTS PATH columns are 1 or 0, converted to the type of the column
+		return evalengine.NewType(col.Type.SQLType(), collations.CollationBinaryID)
+	}
+	vcol := vindexes.Column{
+		Type:     col.Type.SQLType(),
+		Size:     int32(ptr.Unwrap(col.Type.Length, 0)),
+		Scale:    int32(ptr.Unwrap(col.Type.Scale, 0)),
+		Nullable: true,
+		Values:   col.Type.EnumValues,
+	}
+	if col.Type.Options != nil {
+		vcol.CollationName = col.Type.Options.Collate
+	}
+	if sqltypes.IsText(vcol.Type) && vcol.CollationName == "" {
+		coll := env.DefaultConnectionCharset()
+		return evalengine.NewTypeEx(vcol.Type, coll, true, vcol.Size, vcol.Scale, ptr.Of(evalengine.EnumSetValues(vcol.Values)))
+	}
+	return vcol.ToEvalengineType(env)
+}
+
+// ColumnNames returns the names of the columns of the JSON table
+func (jt *JSONTable) ColumnNames() []string {
+	names := make([]string, 0, len(jt.columns))
+	for _, col := range jt.columns {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+// dependencies implements the TableInfo interface
+func (jt *JSONTable) dependencies(colName string, org originable) (dependencies, error) {
+	ts := org.tableSetFor(jt.ASTNode)
+	for _, info := range jt.columns {
+		if strings.EqualFold(info.Name, colName) {
+			return createCertain(ts, ts, info.Type), nil
+		}
+	}
+	return &nothing{}, nil
+}
+
+// getTableSet implements the TableInfo interface
+func (jt *JSONTable) getTableSet(org originable) TableSet {
+	return org.tableSetFor(jt.ASTNode)
+}
+
+// getExprFor implements the TableInfo interface
+func (jt *JSONTable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Unknown column '%s' in 'field list'", s)
+}
+
+// IsInfSchema implements the TableInfo interface
+func (jt *JSONTable) IsInfSchema() bool {
+	return false
+}
+
+// getColumns implements the TableInfo interface
+func (jt *JSONTable) getColumns(bool) []ColumnInfo {
+	return jt.columns
+}
+
+// GetAliasedTableExpr implements the TableInfo interface
+func (jt *JSONTable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return jt.ASTNode
+}
+
+// canShortCut implements the TableInfo interface.
+// JSON tables are not stored in any keyspace, so they don't change where the query can be sent.
+func (jt *JSONTable) canShortCut() shortCut {
+	return canShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (jt *JSONTable) GetVindexTable() *vindexes.Table {
+	return nil
+}
+
+// Name implements the TableInfo interface
+func (jt *JSONTable) Name() (sqlparser.TableName, error) {
+	return jt.ASTNode.TableName()
+}
+
+// authoritative implements the TableInfo interface
+func (jt *JSONTable) authoritative() bool {
+	return true
+}
+
+// matches implements the TableInfo interface
+func (jt *JSONTable) matches(name sqlparser.TableName) bool {
+	return jt.tableName == name.Name.String() && name.Qualifier.IsEmpty()
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+)
+
+func TestJSONTableDependencies(t *testing.T) {
+	query := "select jt.id, jt.name, jt.tag from t2 as u, json_table(u.textcol, '$[*]' columns(id for ordinality, name varchar(10) path '$.name', nested path '$.tags[*]' columns(tag int path '$'))) as jt"
+	stmt, semTable := parseAndAnalyze(t, query, "d")
+	sel := stmt.(*sqlparser.Select)
+
+	jtExpr := sel.From[1].(*sqlparser.JSONTableExpr)
+	jt, ts := semTable.JSONTableFor(jtExpr)
+	require.NotNil(t, jt)
+	assert.Equal(t, TS1, ts)
+	assert.Equal(t, []string{"id", "name", "tag"}, jt.ColumnNames())
+
+	// the JSON document comes from the table before the JSON_TABLE
+	assert.Equal(t, TS0, semTable.RecursiveDeps(jtExpr.Expr))
+
+	expectedTypes := []sqltypes.Type{sqltypes.Uint32, sqltypes.VarChar, sqltypes.Int32}
+	for i, typ := range expectedTypes {
+		expr := extract(sel, i)
+		assert.Equal(t, TS1, semTable.DirectDeps(expr), sqlparser.String(expr))
+		assert.Equal(t, TS1, semTable.RecursiveDeps(expr), sqlparser.String(expr))
+		exprType, found := semTable.TypeForExpr(expr)
+		require.True(t, found, sqlparser.String(expr))
+		assert.Equal(t, typ, exprType.Type(), sqlparser.String(expr))
+	}
+}
+
+func TestJSONTableStarExpansion(t *testing.T) {
+	query := "select * from json_table('[1, 2]', '$[*]' columns(i for ordinality, v int path '$')) as jt"
+	stmt, _ := parseAndAnalyze(t, query, "d")
+	assert.Equal(t, "select i, v from json_table('[1, 2]', '$[*]' columns(\n\ti for ordinality,\n\tv int path '$' \n\t)\n) as jt", sqlparser.String(stmt))
+}
@@ -172,6 +172,11 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 		// To create this special context, we will find the parent scope of the select statement involved.
 		currScope := s.currentScope()
 		stmtScope := currScope.findParentScopeOfStatement()
+		if isLateral(cursor.Node()) {
+			// LATERAL derived tables and JSON_TABLE expressions are the exception,
+			// since they can see the tables that come before them in the FROM clause.
+			stmtScope = currScope
+		}
 		nScope := newScope(stmtScope)
 		if stmtScope == nil {
 			// TODO: this feels hacky. revisit with a better plan
@@ -182,6 +187,18 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 	}
 }
 
+// isLateral returns true for table expressions that are allowed to reference the tables preceding them
+func isLateral(node sqlparser.SQLNode) bool {
+	switch node := node.(type) {
+	case *sqlparser.JSONTableExpr:
+		return true
+	case *sqlparser.AliasedTableExpr:
+		dt, ok := node.Expr.(*sqlparser.DerivedTable)
+		return ok && dt.Lateral
+	}
+	return false
+}
+
 func (s *scoper) pushSelectScope(node *sqlparser.Select) {
 	currScope := newScope(s.currentScope())
 	currScope.stmtScope = true
@@ -342,14 +359,17 @@ func newScope(parent *scope) *scope {
 	}
 }
 
-func (s *scope) addCTE(cte *sqlparser.CommonTableExpr) error {
+// addCTE adds the CTE to the scope. Only recursive CTEs are allowed to reference their own name.
+func (s *scope) addCTE(cte *sqlparser.CommonTableExpr, recursive bool) error {
 	name := cte.ID.String()
 	_, exists := s.ctes[name]
 	if exists {
 		return vterrors.VT03013(name)
 	}
-	if err := checkForInvalidAliasUse(cte, name); err != nil {
-		return err
+	if !recursive {
+		if err := checkForInvalidAliasUse(cte, name); err != nil {
+			return err
+		}
 	}
 	s.ctes[name] = cte
 	return nil
@@ -371,6 +391,28 @@ func checkForInvalidAliasUse(cte *sqlparser.CommonTableExpr, name string) (err e
 	return err
 }
 
+// usesCTEAlias returns true if the given node references a table with the name of the CTE
+func usesCTEAlias(node sqlparser.SQLNode, name string) bool {
+	return countCTEAliasUses(node, name) > 0
+}
+
+// countCTEAliasUses returns the number of table expressions in the given node that reference the CTE
+func countCTEAliasUses(node sqlparser.SQLNode, name string) (count int) {
+	down := func(node sqlparser.SQLNode, parent sqlparser.SQLNode) bool {
+		aliasedTable, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true
+		}
+		tbl, ok := aliasedTable.Expr.(sqlparser.TableName)
+		if ok && tbl.Qualifier.IsEmpty() && tbl.Name.String() == name {
+			count++
+		}
+		return true
+	}
+	_ = sqlparser.CopyOnRewrite(node, down, nil, nil)
+	return count
+}
+
 func (s *scope) addTable(info TableInfo) error {
 	name, err := info.Name()
 	if err != nil {
@@ -576,6 +576,17 @@ func (st *SemTable) TableSetFor(t *sqlparser.AliasedTableExpr) TableSet {
 	return EmptyTableSet()
 }
 
+// JSONTableFor returns the table info and the table set of the given JSON_TABLE expression
+func (st *SemTable) JSONTableFor(node *sqlparser.JSONTableExpr) (*JSONTable, TableSet) {
+	for idx, t := range st.Tables {
+		jt, ok := t.(*JSONTable)
+		if ok && jt.JSONTableExpr == node {
+			return jt, SingleTableSet(idx)
+		}
+	}
+	return nil, EmptyTableSet()
+}
+
 // ReplaceTableSetFor replaces the given single TabletSet with the new *sqlparser.AliasedTableExpr
 func (st *SemTable) ReplaceTableSetFor(id TableSet, t *sqlparser.AliasedTableExpr) {
 	if st == nil {
@@ -40,6 +40,9 @@ type tableCollector struct {
 	org       originable
 	unionInfo map[*sqlparser.Union]unionInfo
 	done      map[*sqlparser.AliasedTableExpr]TableInfo
+
+	// cteReferences contains the table expressions where a recursive CTE references itself
+	cteReferences map[*sqlparser.AliasedTableExpr]*cteReference
 }
 
 type earlyTableCollector struct {
@@ -81,13 +84,14 @@ func (etc *earlyTableCollector) visitAliasedTableExpr(aet *sqlparser.AliasedTabl
 
 func (etc *earlyTableCollector) newTableCollector(scoper *scoper, org originable) *tableCollector {
 	return &tableCollector{
-		Tables:    etc.Tables,
-		scoper:    scoper,
-		si:        etc.si,
-		currentDb: etc.currentDb,
-		unionInfo: map[*sqlparser.Union]unionInfo{},
-		done:      etc.done,
-		org:       org,
+		Tables:        etc.Tables,
+		scoper:        scoper,
+		si:            etc.si,
+		currentDb:     etc.currentDb,
+		unionInfo:     map[*sqlparser.Union]unionInfo{},
+		done:          etc.done,
+		org:           org,
+		cteReferences: map[*sqlparser.AliasedTableExpr]*cteReference{},
 	}
 }
 
@@ -115,6 +119,8 @@ func (tc *tableCollector) up(cursor *sqlparser.Cursor) error {
 		return tc.visitAliasedTableExpr(node)
 	case *sqlparser.Union:
 		return tc.visitUnion(node)
+	case *sqlparser.JSONTableExpr:
+		return tc.visitJSONTableExpr(node)
 	case *sqlparser.RowAlias:
 		ins, ok := cursor.Parent().(*sqlparser.Insert)
 		if !ok {
@@ -136,6 +142,26 @@ func (tc *tableCollector) visitAliasedTableExpr(node *sqlparser.AliasedTableExpr
 	return nil
 }
 
+func (tc *tableCollector) visitJSONTableExpr(node *sqlparser.JSONTableExpr) error {
+	tableInfo, err := createJSONTable(node, tc.org.collationEnv())
+	if err != nil {
+		return err
+	}
+	tc.Tables = append(tc.Tables, tableInfo)
+	scope := tc.scoper.currentScope()
+	return scope.addTable(tableInfo)
+}
+
+// jsonTableFor returns the table info created for the given JSON_TABLE expression
+func (tc *tableCollector) jsonTableFor(node *sqlparser.JSONTableExpr) *JSONTable {
+	for _, t := range tc.Tables {
+		if jt, ok := t.(*JSONTable); ok && jt.JSONTableExpr == node {
+			return jt
+		}
+	}
+	return nil
+}
+
 func (tc *tableCollector) visitUnion(union *sqlparser.Union) error {
 	firstSelect := sqlparser.GetFirstSelect(union)
 	expanded, selectExprs := getColumnNames(firstSelect.SelectExprs)
@@ -306,7 +332,11 @@ func (tc *tableCollector) handleTableName(node *sqlparser.AliasedTableExpr, t sq
 
 	tableInfo, found = tc.done[node]
 	if !found {
-		tableInfo, err = getTableInfo(node, t, tc.si, tc.currentDb)
+		if ref, isCTE := tc.cteReferences[node]; isCTE {
+			tableInfo, err = createCTETable(node, ref, tc.org)
+		} else {
+			tableInfo, err = getTableInfo(node, t, tc.si, tc.currentDb)
+		}
 		if err != nil {
 			return err
 		}
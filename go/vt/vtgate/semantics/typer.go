@@ -18,6 +18,7 @@ package semantics
 
 import (
 	"github.com/mdibaiee/vitess/go/mysql/collations"
+	"github.com/mdibaiee/vitess/go/sqltypes"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/engine/opcode"
 	"github.com/mdibaiee/vitess/go/vt/vtgate/evalengine"
@@ -61,10 +62,34 @@ func (t *typer) up(cursor *sqlparser.Cursor) error {
 			}
 		}
 		t.m[node] = code.ResolveType(inputType, t.collationEnv)
+	case *sqlparser.ArgumentLessWindowExpr:
+		typ := sqltypes.Uint64
+		if node.Type == sqlparser.CumeDistExprType || node.Type == sqlparser.PercentRankExprType {
+			typ = sqltypes.Float64
+		}
+		t.m[node] = evalengine.NewTypeEx(typ, collations.CollationBinaryID, false, 0, 0, nil)
+	case *sqlparser.NtileExpr:
+		t.m[node] = evalengine.NewTypeEx(sqltypes.Uint64, collations.CollationBinaryID, true, 0, 0, nil)
+	case *sqlparser.LagLeadExpr:
+		t.setWindowValueType(node, node.Expr)
+	case *sqlparser.FirstOrLastValueExpr:
+		t.setWindowValueType(node, node.Expr)
+	case *sqlparser.NTHValueExpr:
+		t.setWindowValueType(node, node.Expr)
 	}
 	return nil
 }
 
+// setWindowValueType types window functions that return a value of their argument.
+// The result is nullable, since the row may fall outside the partition or the frame.
+func (t *typer) setWindowValueType(node, arg sqlparser.Expr) {
+	tt, ok := t.m[arg]
+	if !ok {
+		return
+	}
+	t.m[node] = evalengine.NewTypeEx(tt.Type(), tt.Collation(), true, tt.Size(), tt.Scale(), tt.Values())
+}
+
 func (t *typer) setTypeFor(node *sqlparser.ColName, typ evalengine.Type) {
 	t.m[node] = typ
 }
@@ -22,6 +22,7 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -114,16 +115,20 @@ type vcursorImpl struct {
 	// A nil value represents that no foreign_key_checks value was provided.
 	fkChecksState       *bool
 	ignoreMaxMemoryRows bool
-	vschema             *vindexes.VSchema
-	vm                  VSchemaOperator
-	semTable            *semantics.SemTable
-	warnShardedOnly     bool // when using sharded only features, a warning will be warnings field
+	// queryFingerprint is the fingerprint of the statement before it was rewritten, used to find its plan baseline
+	queryFingerprint string
+	vschema          *vindexes.VSchema
+	vm               VSchemaOperator
+	semTable         *semantics.SemTable
+	warnShardedOnly  bool // when using sharded only features, a warning will be warnings field
 
 	warnings []*querypb.QueryWarning // any warnings that are accumulated during the planning phase are stored here
 	pv       plancontext.PlannerVersion
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+
+	spillBudget *engine.DiskBudget
 }
 
 // newVcursorImpl creates a vcursorImpl. Before creating this object, you have to separate out any marginComments that came with
@@ -191,9 +196,23 @@ func newVCursorImpl(
 		pv:                  pv,
 		warmingReadsPercent: warmingReadsPct,
 		warmingReadsChannel: warmingReadsChan,
+		spillBudget:         newQuerySpillBudget(),
 	}, nil
 }
 
+// processSpillBudget is the disk budget shared by all the queries that spill rows to disk
+var processSpillBudget = sync.OnceValue(func() *engine.DiskBudget {
+	return engine.NewDiskBudget(spillDir, spillMaxProcessBytes, nil)
+})
+
+// newQuerySpillBudget returns the disk budget of a single query, or nil when spilling to disk is disabled
+func newQuerySpillBudget() *engine.DiskBudget {
+	if spillMaxQueryBytes <= 0 {
+		return nil
+	}
+	return engine.NewDiskBudget(spillDir, spillMaxQueryBytes, processSpillBudget())
+}
+
 // HasSystemVariables returns whether the session has set system variables or not
 func (vc *vcursorImpl) HasSystemVariables() bool {
 	return vc.safeSession.HasSystemVariables()
@@ -224,6 +243,15 @@ func (vc *vcursorImpl) SQLMode() string {
 	return config.DefaultSQLMode
 }
 
+// GroupConcatMaxLen returns the group_concat_max_len of the session. It returns 0 if the session did not
+// set it, as the value configured on the tablets is not known to vtgate.
+func (vc *vcursorImpl) GroupConcatMaxLen() int64 {
+	if maxLen := vc.safeSession.GroupConcatMaxLen(); maxLen != nil {
+		return *maxLen
+	}
+	return 0
+}
+
 // MaxMemoryRows returns the maxMemoryRows flag value.
 func (vc *vcursorImpl) MaxMemoryRows() int {
 	return maxMemoryRows
@@ -235,6 +263,16 @@ func (vc *vcursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
 	return !vc.ignoreMaxMemoryRows && numRows > maxMemoryRows
 }
 
+// CTEMaxRecursionDepth returns the cteMaxRecursionDepth flag value.
+func (vc *vcursorImpl) CTEMaxRecursionDepth() int {
+	return cteMaxRecursionDepth
+}
+
+// SpillBudget returns the disk budget of the query, or nil when spilling to disk is disabled.
+func (vc *vcursorImpl) SpillBudget() *engine.DiskBudget {
+	return vc.spillBudget
+}
+
 // SetIgnoreMaxMemoryRows sets the ignoreMaxMemoryRows value.
 func (vc *vcursorImpl) SetIgnoreMaxMemoryRows(ignoreMaxMemoryRows bool) {
 	vc.ignoreMaxMemoryRows = ignoreMaxMemoryRows
@@ -1349,6 +1387,7 @@ func (vc *vcursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCurso
 		warnShardedOnly:     vc.warnShardedOnly,
 		warnings:            vc.warnings,
 		pv:                  vc.pv,
+		spillBudget:         newQuerySpillBudget(),
 	}
 
 	v.marginComments.Trailing += "/* warming read */"
@@ -1374,3 +1413,13 @@ func (vc *vcursorImpl) UpdateForeignKeyChecksState(fkStateFromQuery *bool) {
 func (vc *vcursorImpl) GetForeignKeyChecksState() *bool {
 	return vc.fkChecksState
 }
+
+// SetQueryFingerprint sets the fingerprint of the statement before it was rewritten.
+func (vc *vcursorImpl) SetQueryFingerprint(fingerprint string) {
+	vc.queryFingerprint = fingerprint
+}
+
+// GetQueryFingerprint returns the fingerprint of the statement before it was rewritten.
+func (vc *vcursorImpl) GetQueryFingerprint() string {
+	return vc.queryFingerprint
+}
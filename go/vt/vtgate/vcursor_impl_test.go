@@ -259,6 +259,27 @@ func TestSetTarget(t *testing.T) {
 	}
 }
 
+func TestSpillBudget(t *testing.T) {
+	save := spillMaxQueryBytes
+	defer func() { spillMaxQueryBytes = save }()
+
+	r, _, _, _, _ := createExecutorEnv(t)
+	newVCursor := func() *vcursorImpl {
+		vc, err := newVCursorImpl(NewSafeSession(&vtgatepb.Session{}), sqlparser.MarginComments{}, r, nil, &fakeVSchemaOperator{vschema: vschemaWith1KS}, vschemaWith1KS, nil, nil, false, querypb.ExecuteOptions_Gen4)
+		require.NoError(t, err)
+		return vc
+	}
+
+	spillMaxQueryBytes = 0
+	require.Nil(t, newVCursor().SpillBudget(), "spilling to disk is disabled")
+
+	spillMaxQueryBytes = 1024
+	vc1, vc2 := newVCursor(), newVCursor()
+	require.NotNil(t, vc1.SpillBudget())
+	require.NotSame(t, vc1.SpillBudget(), vc2.SpillBudget(), "every query gets its own budget")
+	require.NotNil(t, vc1.CloneForReplicaWarming(context.Background()).SpillBudget())
+}
+
 func TestKeyForPlan(t *testing.T) {
 	type testCase struct {
 		vschema               *vindexes.VSchema
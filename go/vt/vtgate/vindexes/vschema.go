@@ -74,6 +74,8 @@ type VSchema struct {
 	Keyspaces            map[string]*KeyspaceSchema `json:"keyspaces"`
 	ShardRoutingRules    map[string]string          `json:"shard_routing_rules"`
 	KeyspaceRoutingRules map[string]string          `json:"keyspace_routing_rules"`
+	// PlanBaselines maps query fingerprints to the baseline that pins their plan.
+	PlanBaselines map[string]*vschemapb.PlanBaseline `json:"plan_baselines,omitempty"`
 	// created is the time when the VSchema object was created. Used to detect if a cached
 	// copy of the vschema is stale.
 	created time.Time
@@ -128,6 +130,22 @@ type Table struct {
 	// MySQL error message: ERROR 3756 (HY000): The primary key cannot be a functional index
 	PrimaryKey sqlparser.Columns `json:"primary_key,omitempty"`
 	UniqueKeys []sqlparser.Exprs `json:"unique_keys,omitempty"`
+
+	// Stats are the statistics collected by the schema tracker, nil if they are unknown
+	Stats *TableStats `json:"stats,omitempty"`
+
+	// ResultCache is set when vtgate may cache the results of read-only queries against the table
+	ResultCache bool `json:"result_cache,omitempty"`
+}
+
+// TableStats contains the estimates the planner uses to compare the costs of different join orders.
+// They are collected from a single shard, so the numbers are per shard.
+type TableStats struct {
+	// Rows is the estimated number of rows in the table
+	Rows uint64 `json:"rows"`
+	// Cardinality is the estimated number of distinct values of the columns that
+	// are the first column of an index, keyed by the lowercase column name
+	Cardinality map[string]uint64 `json:"cardinality,omitempty"`
 }
 
 // GetTableName gets the sqlparser.TableName for the vindex Table.
@@ -159,6 +177,7 @@ type TableInfo struct {
 	Columns     []Column
 	ForeignKeys []*sqlparser.ForeignKeyDefinition
 	Indexes     []*sqlparser.IndexDefinition
+	Stats       *TableStats
 }
 
 // IsUnique is used to tell whether the ColumnVindex
@@ -190,9 +209,13 @@ type Column struct {
 	Type          querypb.Type           `json:"type"`
 	CollationName string                 `json:"collation_name"`
 	Default       sqlparser.Expr         `json:"default,omitempty"`
+	// OnUpdate is the value MySQL assigns to this column when the row is updated, e.g. CURRENT_TIMESTAMP
+	OnUpdate sqlparser.Expr `json:"on_update,omitempty"`
 
 	// Invisible marks this as a column that will not be automatically included in `*` projections
-	Invisible bool  `json:"invisible,omitempty"`
+	Invisible bool `json:"invisible,omitempty"`
+	// Generated marks this as a column whose value is computed by MySQL, and can't be inserted
+	Generated bool  `json:"generated,omitempty"`
 	Size      int32 `json:"size,omitempty"`
 	Scale     int32 `json:"scale,omitempty"`
 	Nullable  bool  `json:"nullable,omitempty"`
@@ -206,7 +229,9 @@ func (col *Column) MarshalJSON() ([]byte, error) {
 		Name      string   `json:"name"`
 		Type      string   `json:"type,omitempty"`
 		Invisible bool     `json:"invisible,omitempty"`
+		Generated bool     `json:"generated,omitempty"`
 		Default   string   `json:"default,omitempty"`
+		OnUpdate  string   `json:"on_update,omitempty"`
 		Size      int32    `json:"size,omitempty"`
 		Scale     int32    `json:"scale,omitempty"`
 		Nullable  bool     `json:"nullable,omitempty"`
@@ -215,6 +240,7 @@ func (col *Column) MarshalJSON() ([]byte, error) {
 		Name:      col.Name.String(),
 		Type:      querypb.Type_name[int32(col.Type)],
 		Invisible: col.Invisible,
+		Generated: col.Generated,
 		Size:      col.Size,
 		Scale:     col.Scale,
 		Nullable:  col.Nullable,
@@ -223,6 +249,9 @@ func (col *Column) MarshalJSON() ([]byte, error) {
 	if col.Default != nil {
 		cj.Default = sqlparser.String(col.Default)
 	}
+	if col.OnUpdate != nil {
+		cj.OnUpdate = sqlparser.String(col.OnUpdate)
+	}
 	return json.Marshal(cj)
 }
 
@@ -338,6 +367,7 @@ func BuildVSchema(source *vschemapb.SrvVSchema, parser *sqlparser.Parser) (vsche
 	buildRoutingRule(source, vschema, parser)
 	buildShardRoutingRule(source, vschema)
 	buildKeyspaceRoutingRule(source, vschema)
+	buildPlanBaselines(source, vschema)
 	// Resolve auto-increments after routing rules are built since sequence tables also obey routing rules.
 	resolveAutoIncrement(source, vschema, parser)
 	return vschema
@@ -608,6 +638,7 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			Name:                    sqlparser.NewIdentifierCS(tname),
 			Keyspace:                keyspace,
 			ColumnListAuthoritative: table.ColumnListAuthoritative,
+			ResultCache:             table.ResultCache,
 		}
 		switch table.Type {
 		case "":
@@ -1025,6 +1056,22 @@ func buildKeyspaceRoutingRule(source *vschemapb.SrvVSchema, vschema *VSchema) {
 	vschema.KeyspaceRoutingRules = rulesMap
 }
 
+func buildPlanBaselines(source *vschemapb.SrvVSchema, vschema *VSchema) {
+	baselines := source.GetPlanBaselines().GetBaselines()
+	if len(baselines) == 0 {
+		return
+	}
+	vschema.PlanBaselines = make(map[string]*vschemapb.PlanBaseline, len(baselines))
+	for _, baseline := range baselines {
+		vschema.PlanBaselines[baseline.Fingerprint] = baseline
+	}
+}
+
+// FindPlanBaseline returns the plan baseline for the given query fingerprint, or nil if there is none.
+func (vschema *VSchema) FindPlanBaseline(fingerprint string) *vschemapb.PlanBaseline {
+	return vschema.PlanBaselines[fingerprint]
+}
+
 // FindTable returns a pointer to the Table. If a keyspace is specified, only tables
 // from that keyspace are searched. If the specified keyspace is unsharded
 // and no tables matched, it's considered valid: FindTable will construct a table
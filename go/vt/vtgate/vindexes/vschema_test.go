@@ -897,6 +897,25 @@ func TestVSchemaRoutingRules(t *testing.T) {
 	assert.Equal(t, string(wantb), string(gotb), string(gotb))
 }
 
+func TestVSchemaPlanBaselines(t *testing.T) {
+	baseline := &vschemapb.PlanBaseline{
+		Fingerprint: "0123456789abcdef",
+		JoinOrder:   []string{"t2", "t1"},
+	}
+	input := vschemapb.SrvVSchema{
+		PlanBaselines: &vschemapb.PlanBaselines{
+			Baselines: []*vschemapb.PlanBaseline{baseline},
+		},
+	}
+	got := BuildVSchema(&input, sqlparser.NewTestParser())
+	assert.Equal(t, baseline, got.FindPlanBaseline("0123456789abcdef"))
+	assert.Nil(t, got.FindPlanBaseline("fedcba9876543210"))
+
+	got = BuildVSchema(&vschemapb.SrvVSchema{}, sqlparser.NewTestParser())
+	assert.Nil(t, got.PlanBaselines)
+	assert.Nil(t, got.FindPlanBaseline("0123456789abcdef"))
+}
+
 func TestChooseVindexForType(t *testing.T) {
 	testcases := []struct {
 		in  querypb.Type
@@ -223,7 +223,8 @@ func (vm *VSchemaManager) updateTableInfo(vschema *vindexes.VSchema, ks *vindexe
 	// are created in the Vschema, so that later when we try to find the routed tables, we don't end up
 	// getting dummy tables.
 	for tblName, tblInfo := range m {
-		setColumns(ks, tblName, tblInfo.Columns)
+		vTbl := setColumns(ks, tblName, tblInfo.Columns)
+		vTbl.Stats = tblInfo.Stats
 	}
 
 	// Now that we have ensured that all the tables are created, we can start populating the foreign keys
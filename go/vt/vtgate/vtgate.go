@@ -68,11 +68,22 @@ var (
 	// plan cache related flag
 	queryPlanCacheMemory int64 = 32 * 1024 * 1024 // 32mb
 
+	// result cache related flag
+	resultCacheMemory int64 = 32 * 1024 * 1024 // 32mb
+
 	maxMemoryRows   = 300000
 	warnMemoryRows  = 30000
 	maxPayloadSize  int
 	warnPayloadSize int
 
+	// cteMaxRecursionDepth is the maximum number of iterations of a recursive CTE evaluated by vtgate
+	cteMaxRecursionDepth = 1000
+
+	// spill related flags, used by primitives that write rows that do not fit in memory to temporary files
+	spillDir             string
+	spillMaxQueryBytes   int64
+	spillMaxProcessBytes int64
+
 	noScatter          bool
 	enableShardRouting bool
 
@@ -100,6 +111,7 @@ var (
 	enableSchemaChangeSignal = true
 	enableViews              bool
 	enableUdfs               bool
+	tableStatsInterval       time.Duration
 
 	// vtgate views flags
 	queryTimeout int
@@ -126,7 +138,12 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&truncateErrorLen, "truncate-error-len", truncateErrorLen, "truncate errors sent to client if they are longer than this value (0 means do not truncate)")
 	fs.IntVar(&streamBufferSize, "stream_buffer_size", streamBufferSize, "the number of bytes sent from vtgate for each stream call. It's recommended to keep this value in sync with vttablet's query-server-config-stream-buffer-size.")
 	fs.Int64Var(&queryPlanCacheMemory, "gate_query_cache_memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
+	fs.Int64Var(&resultCacheMemory, "result-cache-memory", resultCacheMemory, "Maximum number of bytes of query results vtgate caches for the SELECTs with the RESULT_CACHE directive or against tables with result_cache set in the vschema. Cached results are invalidated by the row events of their tables. The result cache is disabled when this is 0.")
 	fs.IntVar(&maxMemoryRows, "max_memory_rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
+	fs.IntVar(&cteMaxRecursionDepth, "cte-max-recursion-depth", cteMaxRecursionDepth, "Maximum number of iterations of a recursive common table expression that is evaluated by vtgate.")
+	fs.StringVar(&spillDir, "spill-dir", spillDir, "Directory for the temporary files of queries that spill rows to disk. Defaults to the system temporary directory.")
+	fs.Int64Var(&spillMaxQueryBytes, "spill-max-query-bytes", spillMaxQueryBytes, "Maximum number of bytes a single query can write to temporary files when the operators evaluated by vtgate go over max_memory_rows. Spilling to disk is disabled when this is 0.")
+	fs.Int64Var(&spillMaxProcessBytes, "spill-max-process-bytes", spillMaxProcessBytes, "Maximum number of bytes all the queries together can write to temporary files when spilling rows to disk. 0 means no limit.")
 	fs.IntVar(&warnMemoryRows, "warn_memory_rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
 	fs.StringVar(&defaultDDLStrategy, "ddl_strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	fs.StringVar(&dbDDLPlugin, "dbddl_plugin", dbDDLPlugin, "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
@@ -150,6 +167,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&messageStreamGracePeriod, "message_stream_grace_period", messageStreamGracePeriod, "the amount of time to give for a vttablet to resume if it ends a message stream, usually because of a reparent.")
 	fs.BoolVar(&enableViews, "enable-views", enableViews, "Enable views support in vtgate.")
 	fs.BoolVar(&enableUdfs, "track-udfs", enableUdfs, "Track UDFs in vtgate.")
+	fs.DurationVar(&tableStatsInterval, "table-stats-refresh-interval", tableStatsInterval, "How often the schema tracker refreshes the table row counts and index cardinalities the planner uses to order joins. Statistics are not collected when this is 0.")
 	fs.BoolVar(&allowKillStmt, "allow-kill-statement", allowKillStmt, "Allows the execution of kill statement")
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
@@ -310,6 +328,7 @@ func Init(
 	var st *vtschema.Tracker
 	if enableSchemaChangeSignal {
 		st = vtschema.NewTracker(gw.hc.Subscribe(), enableViews, enableUdfs, env.Parser())
+		st.EnableTableStats(tableStatsInterval)
 		addKeyspacesToTracker(ctx, srvResolver, st, gw)
 		si = st
 	}
@@ -332,6 +351,10 @@ func Init(
 		warmingReadsPercent,
 	)
 
+	if resultCacheMemory > 0 {
+		executor.results = newResultCache(ctx, vsm, resultCacheMemory)
+	}
+
 	if err := executor.defaultQueryLogger(); err != nil {
 		log.Fatalf("error initializing query logger: %v", err)
 	}
@@ -584,6 +607,30 @@ handleError:
 	return session, nil, err
 }
 
+// MessageInspect returns the dead-lettered messages of a message table
+// from all the shards of its keyspace.
+func (vtg *VTGate) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	statsKey := []string{"MessageInspect", keyspace, topoproto.TabletTypeLString(topodatapb.TabletType_PRIMARY)}
+	defer vtg.timings.Record(statsKey, time.Now())
+	qr, err := vtg.executor.MessageInspect(ctx, keyspace, name, limit)
+	if err == nil {
+		vtg.rowsReturned.Add(statsKey, int64(len(qr.Rows)))
+	}
+	return qr, err
+}
+
+// MessageRequeue requeues dead-lettered messages of a message table
+// in all the shards of its keyspace.
+func (vtg *VTGate) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	statsKey := []string{"MessageRequeue", keyspace, topoproto.TabletTypeLString(topodatapb.TabletType_PRIMARY)}
+	defer vtg.timings.Record(statsKey, time.Now())
+	count, err := vtg.executor.MessageRequeue(ctx, keyspace, name, ids)
+	if err == nil {
+		vtg.rowsAffected.Add(statsKey, count)
+	}
+	return count, err
+}
+
 // VStream streams binlog events.
 func (vtg *VTGate) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error {
 	return vtg.vsm.VStream(ctx, tabletType, vgtid, filter, flags, send)
@@ -92,6 +92,17 @@ func (conn *VTGateConn) ResolveTransaction(ctx context.Context, dtid string) err
 	return conn.impl.ResolveTransaction(ctx, dtid)
 }
 
+// MessageInspect returns the dead-lettered messages of a message table,
+// up to limit messages from each shard of the keyspace.
+func (conn *VTGateConn) MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error) {
+	return conn.impl.MessageInspect(ctx, keyspace, name, limit)
+}
+
+// MessageRequeue requeues dead-lettered messages of a message table.
+func (conn *VTGateConn) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	return conn.impl.MessageRequeue(ctx, keyspace, name, ids)
+}
+
 // Close must be called for releasing resources.
 func (conn *VTGateConn) Close() {
 	conn.impl.Close()
@@ -181,6 +192,12 @@ type Impl interface {
 	// ResolveTransaction resolves the specified 2pc transaction.
 	ResolveTransaction(ctx context.Context, dtid string) error
 
+	// MessageInspect returns the dead-lettered messages of a message table.
+	MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error)
+
+	// MessageRequeue requeues dead-lettered messages of a message table.
+	MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error)
+
 	// VStream streams binlogevents
 	VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags) (VStreamReader, error)
 
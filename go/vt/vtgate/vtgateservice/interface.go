@@ -45,6 +45,10 @@ type VTGateService interface {
 	// 2PC support
 	ResolveTransaction(ctx context.Context, dtid string) error
 
+	// Messaging methods.
+	MessageInspect(ctx context.Context, keyspace string, name string, limit int64) (*sqltypes.Result, error)
+	MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error)
+
 	// Update Stream methods
 	VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error
 
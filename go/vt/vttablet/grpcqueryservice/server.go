@@ -319,6 +319,40 @@ func (q *query) MessageAck(ctx context.Context, request *querypb.MessageAckReque
 	}, nil
 }
 
+// MessageInspect is part of the queryservice.QueryServer interface
+func (q *query) MessageInspect(ctx context.Context, request *querypb.MessageInspectRequest) (response *querypb.MessageInspectResponse, err error) {
+	defer q.server.HandlePanic(&err)
+	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
+		request.EffectiveCallerId,
+		request.ImmediateCallerId,
+	)
+	qr, err := q.server.MessageInspect(ctx, request.Target, request.Name, request.Limit)
+	if err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &querypb.MessageInspectResponse{
+		Result: sqltypes.ResultToProto3(qr),
+	}, nil
+}
+
+// MessageRequeue is part of the queryservice.QueryServer interface
+func (q *query) MessageRequeue(ctx context.Context, request *querypb.MessageRequeueRequest) (response *querypb.MessageRequeueResponse, err error) {
+	defer q.server.HandlePanic(&err)
+	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
+		request.EffectiveCallerId,
+		request.ImmediateCallerId,
+	)
+	count, err := q.server.MessageRequeue(ctx, request.Target, request.Name, request.Ids)
+	if err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &querypb.MessageRequeueResponse{
+		Result: &querypb.QueryResult{
+			RowsAffected: uint64(count),
+		},
+	}, nil
+}
+
 // StreamHealth is part of the queryservice.QueryServer interface
 func (q *query) StreamHealth(request *querypb.StreamHealthRequest, stream queryservicepb.Query_StreamHealthServer) (err error) {
 	defer q.server.HandlePanic(&err)
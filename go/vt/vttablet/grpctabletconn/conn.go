@@ -616,6 +616,48 @@ func (conn *gRPCQueryClient) MessageAck(ctx context.Context, target *querypb.Tar
 	return int64(reply.Result.RowsAffected), nil
 }
 
+// MessageInspect returns dead-lettered messages.
+func (conn *gRPCQueryClient) MessageInspect(ctx context.Context, target *querypb.Target, name string, limit int64) (*sqltypes.Result, error) {
+	conn.mu.RLock()
+	defer conn.mu.RUnlock()
+	if conn.cc == nil {
+		return nil, tabletconn.ConnClosed
+	}
+	req := &querypb.MessageInspectRequest{
+		Target:            target,
+		EffectiveCallerId: callerid.EffectiveCallerIDFromContext(ctx),
+		ImmediateCallerId: callerid.ImmediateCallerIDFromContext(ctx),
+		Name:              name,
+		Limit:             limit,
+	}
+	reply, err := conn.c.MessageInspect(ctx, req)
+	if err != nil {
+		return nil, tabletconn.ErrorFromGRPC(err)
+	}
+	return sqltypes.Proto3ToResult(reply.Result), nil
+}
+
+// MessageRequeue requeues dead-lettered messages.
+func (conn *gRPCQueryClient) MessageRequeue(ctx context.Context, target *querypb.Target, name string, ids []*querypb.Value) (int64, error) {
+	conn.mu.RLock()
+	defer conn.mu.RUnlock()
+	if conn.cc == nil {
+		return 0, tabletconn.ConnClosed
+	}
+	req := &querypb.MessageRequeueRequest{
+		Target:            target,
+		EffectiveCallerId: callerid.EffectiveCallerIDFromContext(ctx),
+		ImmediateCallerId: callerid.ImmediateCallerIDFromContext(ctx),
+		Name:              name,
+		Ids:               ids,
+	}
+	reply, err := conn.c.MessageRequeue(ctx, req)
+	if err != nil {
+		return 0, tabletconn.ErrorFromGRPC(err)
+	}
+	return int64(reply.Result.RowsAffected), nil
+}
+
 // StreamHealth starts a streaming RPC for VTTablet health status updates.
 func (conn *gRPCQueryClient) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
 	// Please see comments in StreamExecute to see how this works.
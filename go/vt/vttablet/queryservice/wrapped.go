@@ -240,6 +240,24 @@ func (ws *wrappedService) MessageAck(ctx context.Context, target *querypb.Target
 	return count, err
 }
 
+func (ws *wrappedService) MessageInspect(ctx context.Context, target *querypb.Target, name string, limit int64) (qr *sqltypes.Result, err error) {
+	err = ws.wrapper(ctx, target, ws.impl, "MessageInspect", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+		var innerErr error
+		qr, innerErr = conn.MessageInspect(ctx, target, name, limit)
+		return canRetry(ctx, innerErr), innerErr
+	})
+	return qr, err
+}
+
+func (ws *wrappedService) MessageRequeue(ctx context.Context, target *querypb.Target, name string, ids []*querypb.Value) (count int64, err error) {
+	err = ws.wrapper(ctx, target, ws.impl, "MessageRequeue", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+		var innerErr error
+		count, innerErr = conn.MessageRequeue(ctx, target, name, ids)
+		return canRetry(ctx, innerErr), innerErr
+	})
+	return count, err
+}
+
 func (ws *wrappedService) VStream(ctx context.Context, request *binlogdatapb.VStreamRequest, send func([]*binlogdatapb.VEvent) error) error {
 	return ws.wrapper(ctx, request.Target, ws.impl, "VStream", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.VStream(ctx, request, send)
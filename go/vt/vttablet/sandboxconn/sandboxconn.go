@@ -474,6 +474,23 @@ func (sbc *SandboxConn) MessageAck(ctx context.Context, target *querypb.Target,
 	return int64(len(ids)), nil
 }
 
+// MessageInspect is part of the QueryService interface.
+func (sbc *SandboxConn) MessageInspect(ctx context.Context, target *querypb.Target, name string, limit int64) (*sqltypes.Result, error) {
+	if err := sbc.getError(); err != nil {
+		return nil, err
+	}
+	return sbc.getNextResult(nil), nil
+}
+
+// MessageRequeue is part of the QueryService interface.
+func (sbc *SandboxConn) MessageRequeue(ctx context.Context, target *querypb.Target, name string, ids []*querypb.Value) (count int64, err error) {
+	if err := sbc.getError(); err != nil {
+		return 0, err
+	}
+	sbc.MessageIDs = ids
+	return int64(len(ids)), nil
+}
+
 // SandboxSQRowCount is the default number of fake splits returned.
 var SandboxSQRowCount = int64(10)
 
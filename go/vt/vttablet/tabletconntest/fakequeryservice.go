@@ -647,6 +647,37 @@ func (f *FakeQueryService) MessageAck(ctx context.Context, target *querypb.Targe
 	return 1, nil
 }
 
+// MessageInspect is part of the queryservice.QueryService interface
+func (f *FakeQueryService) MessageInspect(ctx context.Context, target *querypb.Target, name string, limit int64) (*sqltypes.Result, error) {
+	if f.HasError {
+		return nil, f.TabletError
+	}
+	if f.Panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	if name != MessageName {
+		f.t.Errorf("name: %s, want %s", name, MessageName)
+	}
+	return MessageStreamResult, nil
+}
+
+// MessageRequeue is part of the queryservice.QueryService interface
+func (f *FakeQueryService) MessageRequeue(ctx context.Context, target *querypb.Target, name string, ids []*querypb.Value) (count int64, err error) {
+	if f.HasError {
+		return 0, f.TabletError
+	}
+	if f.Panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	if name != MessageName {
+		f.t.Errorf("name: %s, want %s", name, MessageName)
+	}
+	if !sqltypes.Proto3ValuesEqual(ids, MessageIDs) {
+		f.t.Errorf("ids: %v, want %v", ids, MessageIDs)
+	}
+	return 1, nil
+}
+
 // TestStreamHealthStreamHealthResponse is a test stream health response.
 var TestStreamHealthStreamHealthResponse = &querypb.StreamHealthResponse{
 	Target: &querypb.Target{
@@ -874,6 +874,70 @@ func testMessageAckPanics(t *testing.T, conn queryservice.QueryService, f *FakeQ
 	})
 }
 
+func testMessageInspect(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testMessageInspect")
+	ctx := context.Background()
+	ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
+	qr, err := conn.MessageInspect(ctx, TestTarget, MessageName, 10)
+	if err != nil {
+		t.Fatalf("MessageInspect failed: %v", err)
+	}
+	if !qr.Equal(MessageStreamResult) {
+		t.Errorf("Unexpected result from MessageInspect: got %v wanted %v", qr, MessageStreamResult)
+	}
+}
+
+func testMessageInspectError(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testMessageInspectError")
+	f.HasError = true
+	testErrorHelper(t, f, "MessageInspect", func(ctx context.Context) error {
+		ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
+		_, err := conn.MessageInspect(ctx, TestTarget, MessageName, 10)
+		return err
+	})
+	f.HasError = false
+}
+
+func testMessageInspectPanics(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testMessageInspectPanics")
+	testPanicHelper(t, f, "MessageInspect", func(ctx context.Context) error {
+		_, err := conn.MessageInspect(ctx, TestTarget, MessageName, 10)
+		return err
+	})
+}
+
+func testMessageRequeue(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testMessageRequeue")
+	ctx := context.Background()
+	ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
+	count, err := conn.MessageRequeue(ctx, TestTarget, MessageName, MessageIDs)
+	if err != nil {
+		t.Fatalf("MessageRequeue failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Unexpected result from MessageRequeue: got %v wanted 1", count)
+	}
+}
+
+func testMessageRequeueError(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testMessageRequeueError")
+	f.HasError = true
+	testErrorHelper(t, f, "MessageRequeue", func(ctx context.Context) error {
+		ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
+		_, err := conn.MessageRequeue(ctx, TestTarget, MessageName, MessageIDs)
+		return err
+	})
+	f.HasError = false
+}
+
+func testMessageRequeuePanics(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testMessageRequeuePanics")
+	testPanicHelper(t, f, "MessageRequeue", func(ctx context.Context) error {
+		_, err := conn.MessageRequeue(ctx, TestTarget, MessageName, MessageIDs)
+		return err
+	})
+}
+
 // this test is a bit of a hack: we write something on the channel
 // upon registration, and we also return an error, so the streaming query
 // ends right there. Otherwise we have no real way to trigger a real
@@ -942,6 +1006,8 @@ func TestSuite(ctx context.Context, t *testing.T, protocol string, tablet *topod
 		testBeginStreamExecute,
 		testMessageStream,
 		testMessageAck,
+		testMessageInspect,
+		testMessageRequeue,
 		testReserveStreamExecute,
 
 		// error test cases
@@ -966,6 +1032,8 @@ func TestSuite(ctx context.Context, t *testing.T, protocol string, tablet *topod
 		testReserveStreamExecuteErrorInExecute,
 		testMessageStreamError,
 		testMessageAckError,
+		testMessageInspectError,
+		testMessageRequeueError,
 
 		// panic test cases
 		testBeginPanics,
@@ -985,6 +1053,8 @@ func TestSuite(ctx context.Context, t *testing.T, protocol string, tablet *topod
 		testBeginStreamExecutePanics,
 		testMessageStreamPanics,
 		testMessageAckPanics,
+		testMessageInspectPanics,
+		testMessageRequeuePanics,
 	}
 
 	if !fake.TestingGateway {
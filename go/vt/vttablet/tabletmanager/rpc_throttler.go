@@ -18,6 +18,7 @@ package tabletmanager
 
 import (
 	"context"
+	"strings"
 
 	"github.com/mdibaiee/vitess/go/stats"
 	tabletmanagerdatapb "github.com/mdibaiee/vitess/go/vt/proto/tabletmanagerdata"
@@ -35,6 +36,7 @@ func (tm *TabletManager) CheckThrottler(ctx context.Context, req *tabletmanagerd
 	}
 	flags := &throttle.CheckFlags{
 		SkipRequestHeartbeats: true,
+		MultiMetricsEnabled:   req.MultiMetricsEnabled,
 	}
 	checkResult := tm.QueryServiceControl.CheckThrottler(ctx, req.AppName, flags)
 	if checkResult == nil {
@@ -50,5 +52,24 @@ func (tm *TabletManager) CheckThrottler(ctx context.Context, req *tabletmanagerd
 	if checkResult.Error != nil {
 		resp.Error = checkResult.Error.Error()
 	}
+	if len(checkResult.Metrics) > 0 {
+		resp.Metrics = make(map[string]*tabletmanagerdatapb.CheckThrottlerResponse_Metric, len(checkResult.Metrics))
+		for name, metricResult := range checkResult.Metrics {
+			// The metrics are scoped, e.g. "self/lag".
+			scope, metricName, _ := strings.Cut(name, "/")
+			metric := &tabletmanagerdatapb.CheckThrottlerResponse_Metric{
+				Name:       metricName,
+				StatusCode: int32(metricResult.StatusCode),
+				Value:      metricResult.Value,
+				Threshold:  metricResult.Threshold,
+				Message:    metricResult.Message,
+				Scope:      scope,
+			}
+			if metricResult.Error != nil {
+				metric.Error = metricResult.Error.Error()
+			}
+			resp.Metrics[name] = metric
+		}
+	}
 	return resp, nil
 }
@@ -25,7 +25,7 @@ func (cached *TabletPlan) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(128)
 	}
 	// field Plan *github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/planbuilder.Plan
 	size += cached.Plan.CachedSize(true)
@@ -40,5 +40,7 @@ func (cached *TabletPlan) CachedSize(alloc bool) int64 {
 			size += elem.CachedSize(true)
 		}
 	}
+	// field Fingerprint string
+	size += hack.RuntimeAllocSize(int64(len(cached.Fingerprint)))
 	return size
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand/v2"
+	"strings"
 	"sync"
 	"time"
 
@@ -33,12 +34,14 @@ import (
 	"github.com/mdibaiee/vitess/go/timer"
 	"github.com/mdibaiee/vitess/go/vt/log"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/schema"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 
 	binlogdatapb "github.com/mdibaiee/vitess/go/vt/proto/binlogdata"
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
 )
 
 var (
@@ -53,6 +56,9 @@ type QueryGenerator interface {
 	GenerateAckQuery(ids []string) (string, map[string]*querypb.BindVariable)
 	GeneratePostponeQuery(ids []string) (string, map[string]*querypb.BindVariable)
 	GeneratePurgeQuery(timeCutoff int64) (string, map[string]*querypb.BindVariable)
+	GenerateDeadLetterQueries(ids []string) []*querypb.BoundQuery
+	GenerateInspectQuery(limit int64) (string, map[string]*querypb.BindVariable, error)
+	GenerateRequeueQueries(ids []string) ([]*querypb.BoundQuery, error)
 }
 
 type messageReceiver struct {
@@ -166,6 +172,14 @@ type receiverWithStatus struct {
 // The Purge thread
 // This thread is mostly independent. It wakes up periodically
 // to delete old rows that were successfully acked.
+//
+// Dead-lettering
+// If the table has a maximum number of attempts, the poller does not
+// resend the messages that were already sent that many times. Instead,
+// it dead-letters them: they are either moved to the dead-letter table,
+// or they are flagged by clearing their time_next, so that they're no
+// longer sent nor purged. Dead-lettered messages can be inspected and
+// requeued through the MessageInspect and MessageRequeue RPCs.
 type messageManager struct {
 	tsv TabletService
 	vs  VStreamer
@@ -177,6 +191,7 @@ type messageManager struct {
 	minBackoff   time.Duration
 	maxBackoff   time.Duration
 	batchSize    int
+	maxAttempts  int64
 	pollerTicks  *timer.Timer
 	purgeTicks   *timer.Timer
 	postponeSema *semaphore.Weighted
@@ -236,6 +251,9 @@ type messageManager struct {
 	ackQuery                  *sqlparser.ParsedQuery
 	postponeQuery             *sqlparser.ParsedQuery
 	purgeQuery                *sqlparser.ParsedQuery
+	deadLetterQueries         []*sqlparser.ParsedQuery
+	inspectQuery              *sqlparser.ParsedQuery
+	requeueQueries            []*sqlparser.ParsedQuery
 }
 
 // newMessageManager creates a new message manager.
@@ -254,6 +272,7 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 		minBackoff:      table.MessageInfo.MinBackoff,
 		maxBackoff:      table.MessageInfo.MaxBackoff,
 		batchSize:       table.MessageInfo.BatchSize,
+		maxAttempts:     int64(table.MessageInfo.MaxAttempts),
 		cache:           newCache(table.MessageInfo.CacheSize),
 		pollerTicks:     timer.NewTimer(table.MessageInfo.PollInterval),
 		purgeTicks:      timer.NewTimer(table.MessageInfo.PollInterval),
@@ -282,10 +301,72 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 		"delete from %v where time_acked < %a limit 500", mm.name, ":time_acked")
 
 	mm.postponeQuery = buildPostponeQuery(mm.name, mm.minBackoff, mm.maxBackoff)
+	if mm.maxAttempts > 0 {
+		mm.buildDeadLetterQueries(table)
+	}
 
 	return mm
 }
 
+// buildDeadLetterQueries builds the queries that dead-letter, inspect
+// and requeue messages. Without a dead-letter table, dead-lettered
+// messages are the unacked ones with no time_next.
+func (mm *messageManager) buildDeadLetterQueries(table *schema.Table) {
+	if table.MessageInfo.DeadLetterTable == "" {
+		mm.deadLetterQueries = []*sqlparser.ParsedQuery{sqlparser.BuildParsedQuery(
+			"update %v set time_next = null where id in %a and time_acked is null and epoch >= %a",
+			mm.name, "::ids", ":max_attempts")}
+		mm.inspectQuery = sqlparser.BuildParsedQuery(
+			"select %s from %v where time_acked is null and time_next is null order by id limit %a",
+			buildFullColumnList(table, nil), mm.name, ":max")
+		mm.requeueQueries = []*sqlparser.ParsedQuery{sqlparser.BuildParsedQuery(
+			"update %v set time_next = %a, epoch = 0 where id in %a and time_acked is null and time_next is null",
+			mm.name, ":time_now", "::ids")}
+		return
+	}
+
+	dlq := sqlparser.NewIdentifierCS(table.MessageInfo.DeadLetterTable)
+	columnList := buildFullColumnList(table, nil)
+	mm.deadLetterQueries = []*sqlparser.ParsedQuery{
+		sqlparser.BuildParsedQuery(
+			"insert into %v(%s) select %s from %v where id in %a and time_acked is null and epoch >= %a",
+			dlq, columnList, columnList, mm.name, "::ids", ":max_attempts"),
+		sqlparser.BuildParsedQuery(
+			"delete from %v where id in %a and time_acked is null and epoch >= %a",
+			mm.name, "::ids", ":max_attempts"),
+	}
+	mm.inspectQuery = sqlparser.BuildParsedQuery(
+		"select %s from %v order by id limit %a",
+		columnList, dlq, ":max")
+	// Requeued messages start over with a fresh count of attempts.
+	mm.requeueQueries = []*sqlparser.ParsedQuery{
+		sqlparser.BuildParsedQuery(
+			"insert into %v(%s) select %s from %v where id in %a",
+			mm.name, columnList, buildFullColumnList(table, map[string]string{"time_next": ":time_now", "epoch": "0"}), dlq, "::ids"),
+		sqlparser.BuildParsedQuery(
+			"delete from %v where id in %a",
+			dlq, "::ids"),
+	}
+}
+
+// buildFullColumnList builds a 'select' list for all the columns of
+// the message table. The expressions in overrides replace the columns
+// they're keyed by.
+func buildFullColumnList(t *schema.Table, overrides map[string]string) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	for i, c := range t.Fields {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		if expr, ok := overrides[strings.ToLower(c.Name)]; ok {
+			buf.WriteString(expr)
+			continue
+		}
+		buf.Myprintf("%v", sqlparser.NewIdentifierCI(c.Name))
+	}
+	return buf.String()
+}
+
 func buildPostponeQuery(name sqlparser.IdentifierCS, minBackoff, maxBackoff time.Duration) *sqlparser.ParsedQuery {
 	var args []any
 
@@ -765,6 +846,11 @@ func (mm *messageManager) processRowEvent(fields []*querypb.Field, rowEvent *bin
 		if mr.TimeAcked != 0 || mr.TimeNext > now {
 			continue
 		}
+		// Messages that ran out of attempts are left to the poller,
+		// which dead-letters them.
+		if mm.exhausted(mr) {
+			continue
+		}
 		mm.Add(mr)
 	}
 	return nil
@@ -811,6 +897,14 @@ func (mm *messageManager) runPoller() {
 		// Wake up the sender.
 		defer mm.cond.Broadcast()
 	}
+	var deadIDs []string
+	defer func() {
+		if len(deadIDs) == 0 {
+			return
+		}
+		mm.wg.Add(1)
+		go mm.deadLetter(deadIDs) // calls the offsetting mm.wg.Done()
+	}()
 	for _, row := range qr.Rows {
 		mr, err := BuildMessageRow(row)
 		if err != nil {
@@ -818,6 +912,10 @@ func (mm *messageManager) runPoller() {
 			log.Errorf("messageManager (%v) - Error reading message row: %v", mm.name, err)
 			continue
 		}
+		if mm.exhausted(mr) {
+			deadIDs = append(deadIDs, mr.Row[0].ToString())
+			continue
+		}
 		if !mm.cache.Add(mr) {
 			mm.messagesPending = true
 			return
@@ -825,6 +923,35 @@ func (mm *messageManager) runPoller() {
 	}
 }
 
+// exhausted returns true if the message was already sent as many
+// times as the table allows.
+func (mm *messageManager) exhausted(mr *MessageRow) bool {
+	return mm.maxAttempts > 0 && mr.Epoch >= mm.maxAttempts
+}
+
+func (mm *messageManager) deadLetter(ids []string) {
+	defer func() {
+		mm.tsv.LogError()
+		mm.wg.Done()
+	}()
+
+	ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), mm.pollerTicks.Interval())
+	defer cancel()
+	// Dead-lettering competes with postponing for the same connections.
+	if err := mm.postponeSema.Acquire(ctx, 1); err != nil {
+		MessageStats.Add([]string{mm.name.String(), "DeadLetterFailed"}, 1)
+		return
+	}
+	defer mm.postponeSema.Release(1)
+	count, err := mm.tsv.DeadLetterMessages(ctx, nil, mm, ids)
+	if err != nil {
+		MessageStats.Add([]string{mm.name.String(), "DeadLetterFailed"}, 1)
+		log.Errorf("messageManager (%v) - Unable to dead-letter messages: %v", mm.name, err)
+		return
+	}
+	MessageStats.Add([]string{mm.name.String(), "DeadLettered"}, count)
+}
+
 func (mm *messageManager) runPurge() {
 	go func() {
 		ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), mm.purgeTicks.Interval())
@@ -850,41 +977,20 @@ func (mm *messageManager) runPurge() {
 
 // GenerateAckQuery returns the query and bind vars for acking a message.
 func (mm *messageManager) GenerateAckQuery(ids []string) (string, map[string]*querypb.BindVariable) {
-	idbvs := &querypb.BindVariable{
-		Type:   querypb.Type_TUPLE,
-		Values: make([]*querypb.Value, 0, len(ids)),
-	}
-	for _, id := range ids {
-		idbvs.Values = append(idbvs.Values, &querypb.Value{
-			Type:  querypb.Type_VARBINARY,
-			Value: []byte(id),
-		})
-	}
 	return mm.ackQuery.Query, map[string]*querypb.BindVariable{
 		"time_acked": sqltypes.Int64BindVariable(time.Now().UnixNano()),
-		"ids":        idbvs,
+		"ids":        idsBindVariable(ids),
 	}
 }
 
 // GeneratePostponeQuery returns the query and bind vars for postponing a message.
 func (mm *messageManager) GeneratePostponeQuery(ids []string) (string, map[string]*querypb.BindVariable) {
-	idbvs := &querypb.BindVariable{
-		Type:   querypb.Type_TUPLE,
-		Values: make([]*querypb.Value, 0, len(ids)),
-	}
-	for _, id := range ids {
-		idbvs.Values = append(idbvs.Values, &querypb.Value{
-			Type:  querypb.Type_VARBINARY,
-			Value: []byte(id),
-		})
-	}
-
 	bvs := map[string]*querypb.BindVariable{
 		"time_now":    sqltypes.Int64BindVariable(time.Now().UnixNano()),
 		"wait_time":   sqltypes.Int64BindVariable(int64(mm.ackWaitTime)),
 		"min_backoff": sqltypes.Int64BindVariable(int64(mm.minBackoff)),
 		"jitter":      sqltypes.Float64BindVariable(.666666 + rand.Float64()*.666666),
-		"ids":         idbvs,
+		"ids":         idsBindVariable(ids),
 	}
 
 	if mm.maxBackoff > 0 {
@@ -901,6 +1007,67 @@ func (mm *messageManager) GeneratePurgeQuery(timeCutoff int64) (string, map[stri
 	}
 }
 
+// GenerateDeadLetterQueries returns the queries for dead-lettering messages.
+// They have to be executed in a single transaction.
+func (mm *messageManager) GenerateDeadLetterQueries(ids []string) []*querypb.BoundQuery {
+	return generateQueries(mm.deadLetterQueries, map[string]*querypb.BindVariable{
+		"max_attempts": sqltypes.Int64BindVariable(mm.maxAttempts),
+		"ids":          idsBindVariable(ids),
+	})
+}
+
+// GenerateInspectQuery returns the query and bind vars for reading
+// dead-lettered messages.
+func (mm *messageManager) GenerateInspectQuery(limit int64) (string, map[string]*querypb.BindVariable, error) {
+	if mm.inspectQuery == nil {
+		return "", nil, mm.noDeadLetterPolicyError()
+	}
+	return mm.inspectQuery.Query, map[string]*querypb.BindVariable{
+		"max": sqltypes.Int64BindVariable(limit),
+	}, nil
+}
+
+// GenerateRequeueQueries returns the queries for requeueing dead-lettered
+// messages. They have to be executed in a single transaction.
+func (mm *messageManager) GenerateRequeueQueries(ids []string) ([]*querypb.BoundQuery, error) {
+	if mm.requeueQueries == nil {
+		return nil, mm.noDeadLetterPolicyError()
+	}
+	return generateQueries(mm.requeueQueries, map[string]*querypb.BindVariable{
+		"time_now": sqltypes.Int64BindVariable(time.Now().UnixNano()),
+		"ids":      idsBindVariable(ids),
+	}), nil
+}
+
+func (mm *messageManager) noDeadLetterPolicyError() error {
+	return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "message table %v does not dead-letter messages: vt_max_attempts is not set", mm.name)
+}
+
+func generateQueries(queries []*sqlparser.ParsedQuery, bvs map[string]*querypb.BindVariable) []*querypb.BoundQuery {
+	bqs := make([]*querypb.BoundQuery, 0, len(queries))
+	for _, query := range queries {
+		bqs = append(bqs, &querypb.BoundQuery{
+			Sql:           query.Query,
+			BindVariables: bvs,
+		})
+	}
+	return bqs
+}
+
+func idsBindVariable(ids []string) *querypb.BindVariable {
+	idbvs := &querypb.BindVariable{
+		Type:   querypb.Type_TUPLE,
+		Values: make([]*querypb.Value, 0, len(ids)),
+	}
+	for _, id := range ids {
+		idbvs.Values = append(idbvs.Values, &querypb.Value{
+			Type:  querypb.Type_VARBINARY,
+			Value: []byte(id),
+		})
+	}
+	return idbvs
+}
+
 // BuildMessageRow builds a MessageRow from a db row.
 func BuildMessageRow(row []sqltypes.Value) (*MessageRow, error) {
 	mr := &MessageRow{Row: row[4:]}
@@ -822,13 +822,138 @@ func TestMMGenerateWithBackoff(t *testing.T) {
 	}
 }
 
+func TestMMGenerateDeadLetter(t *testing.T) {
+	ti := newMMTable()
+	ti.Fields = []*querypb.Field{
+		{Name: "id"},
+		{Name: "priority"},
+		{Name: "time_next"},
+		{Name: "epoch"},
+		{Name: "time_acked"},
+		{Name: "message"},
+	}
+	ti.MessageInfo.MaxAttempts = 3
+	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), ti, semaphore.NewWeighted(1))
+
+	wantids := sqltypes.TestBindVariable([]any{[]byte{'1'}, []byte{'2'}})
+	bqs := mm.GenerateDeadLetterQueries([]string{"1", "2"})
+	utils.MustMatch(t, []*querypb.BoundQuery{{
+		Sql: "update foo set time_next = null where id in ::ids and time_acked is null and epoch >= :max_attempts",
+		BindVariables: map[string]*querypb.BindVariable{
+			"max_attempts": sqltypes.Int64BindVariable(3),
+			"ids":          wantids,
+		},
+	}}, bqs)
+
+	query, bv, err := mm.GenerateInspectQuery(10)
+	assert.NoError(t, err)
+	assert.Equal(t, "select id, priority, time_next, epoch, time_acked, message from foo where time_acked is null and time_next is null order by id limit :max", query)
+	utils.MustMatch(t, map[string]*querypb.BindVariable{"max": sqltypes.Int64BindVariable(10)}, bv)
+
+	bqs, err = mm.GenerateRequeueQueries([]string{"1", "2"})
+	assert.NoError(t, err)
+	assert.Len(t, bqs, 1)
+	assert.Equal(t, "update foo set time_next = :time_now, epoch = 0 where id in ::ids and time_acked is null and time_next is null", bqs[0].Sql)
+	assert.Contains(t, bqs[0].BindVariables, "time_now")
+	utils.MustMatch(t, wantids, bqs[0].BindVariables["ids"])
+
+	// With a dead-letter table, messages are moved back and forth.
+	ti.MessageInfo.DeadLetterTable = "foo_dlq"
+	mm = newMessageManager(newFakeTabletServer(), newFakeVStreamer(), ti, semaphore.NewWeighted(1))
+
+	var sqls []string
+	for _, bq := range mm.GenerateDeadLetterQueries([]string{"1", "2"}) {
+		sqls = append(sqls, bq.Sql)
+	}
+	assert.Equal(t, []string{
+		"insert into foo_dlq(id, priority, time_next, epoch, time_acked, message) select id, priority, time_next, epoch, time_acked, message from foo where id in ::ids and time_acked is null and epoch >= :max_attempts",
+		"delete from foo where id in ::ids and time_acked is null and epoch >= :max_attempts",
+	}, sqls)
+
+	query, _, err = mm.GenerateInspectQuery(10)
+	assert.NoError(t, err)
+	assert.Equal(t, "select id, priority, time_next, epoch, time_acked, message from foo_dlq order by id limit :max", query)
+
+	sqls = nil
+	bqs, err = mm.GenerateRequeueQueries([]string{"1", "2"})
+	assert.NoError(t, err)
+	for _, bq := range bqs {
+		sqls = append(sqls, bq.Sql)
+	}
+	assert.Equal(t, []string{
+		"insert into foo(id, priority, time_next, epoch, time_acked, message) select id, priority, :time_now, 0, time_acked, message from foo_dlq where id in ::ids",
+		"delete from foo_dlq where id in ::ids",
+	}, sqls)
+
+	// Without a retry policy, nothing can be inspected nor requeued.
+	mm = newMessageManager(newFakeTabletServer(), newFakeVStreamer(), newMMTable(), semaphore.NewWeighted(1))
+	_, _, err = mm.GenerateInspectQuery(10)
+	assert.EqualError(t, err, "message table foo does not dead-letter messages: vt_max_attempts is not set")
+	_, err = mm.GenerateRequeueQueries([]string{"1"})
+	assert.EqualError(t, err, "message table foo does not dead-letter messages: vt_max_attempts is not set")
+}
+
+func TestMessageManagerDeadLetter(t *testing.T) {
+	tsv := newFakeTabletServer()
+	ch := make(chan string, 20)
+	tsv.SetChannel(ch)
+
+	ti := newMMTable()
+	ti.MessageInfo.PollInterval = 20 * time.Second
+	ti.MessageInfo.MaxAttempts = 2
+	fvs := newFakeVStreamer()
+	exhaustedRow := sqltypes.RowToProto3([]sqltypes.Value{
+		sqltypes.NewInt64(1),
+		sqltypes.NewInt64(1),
+		sqltypes.NewInt64(2),
+		sqltypes.NULL,
+		sqltypes.NewInt64(2),
+		sqltypes.NewVarBinary("2"),
+	})
+	fvs.setPollerResponse([]*binlogdatapb.VStreamResultsResponse{{
+		Fields: testDBFields,
+		Gtid:   "MySQL56/33333333-3333-3333-3333-333333333333:1-100",
+	}, {
+		Rows: []*querypb.Row{newMMRow(1), exhaustedRow},
+	}})
+	mm := newMessageManager(tsv, fvs, ti, semaphore.NewWeighted(1))
+	mm.Open()
+	defer mm.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r1 := newTestReceiver(1)
+	mm.Subscribe(ctx, r1.rcv)
+	<-r1.ch
+
+	// Only the message that has attempts left is sent.
+	qr := <-r1.ch
+	utils.MustMatch(t, [][]sqltypes.Value{{sqltypes.NewInt64(1), sqltypes.NewVarBinary("1")}}, qr.Rows)
+	for msg := <-ch; msg != "deadletter"; msg = <-ch {
+	}
+	tsv.mu.Lock()
+	assert.Equal(t, []string{"2"}, tsv.deadLettered)
+	tsv.mu.Unlock()
+
+	// Exhausted messages coming from the vstream are left to the poller.
+	err := mm.processRowEvent(testDBFields, &binlogdatapb.RowEvent{
+		TableName:  "foo",
+		RowChanges: []*binlogdatapb.RowChange{{After: exhaustedRow}, {After: newMMRow(3)}},
+	})
+	assert.NoError(t, err)
+	qr = <-r1.ch
+	utils.MustMatch(t, [][]sqltypes.Value{{sqltypes.NewInt64(3), sqltypes.NewVarBinary("3")}}, qr.Rows)
+}
+
 type fakeTabletServer struct {
 	tabletenv.Env
 	postponeCount atomic.Int64
 	purgeCount    atomic.Int64
 
-	mu sync.Mutex
-	ch chan string
+	mu            sync.Mutex
+	ch            chan string
+	deadLettered  []string
+	deadLetterErr error
 }
 
 func newFakeTabletServer() *fakeTabletServer {
@@ -868,6 +993,18 @@ func (fts *fakeTabletServer) PurgeMessages(ctx context.Context, target *querypb.
 	return 0, nil
 }
 
+func (fts *fakeTabletServer) DeadLetterMessages(ctx context.Context, target *querypb.Target, gen QueryGenerator, ids []string) (count int64, err error) {
+	fts.mu.Lock()
+	fts.deadLettered = append(fts.deadLettered, ids...)
+	ch := fts.ch
+	err = fts.deadLetterErr
+	fts.mu.Unlock()
+	if ch != nil {
+		ch <- "deadletter"
+	}
+	return int64(len(ids)), err
+}
+
 type fakeVStreamer struct {
 	streamInvocations atomic.Int64
 	mu                sync.Mutex
@@ -63,6 +63,10 @@ type TabletPlan struct {
 	Rules      *rules.Rules
 	Authorized []*tableacl.ACLResult
 
+	// Fingerprint identifies the shape of the query, for the rules and
+	// the query quarantine. It is not set for the message stream plans.
+	Fingerprint string
+
 	QueryCount   uint64
 	Time         uint64
 	MysqlTime    uint64
@@ -168,6 +172,9 @@ type QueryEngine struct {
 	// that we start more than one transaction per hot row (range).
 	// For implementation details, please see BeginExecute() in tabletserver.go.
 	txSerializer *txserializer.TxSerializer
+	// quarantine denies the queries that keep failing or being slow.
+	// It is nil if the query quarantine is disabled.
+	quarantine *queryQuarantine
 
 	// Vars
 	maxResultSize    atomic.Int64
@@ -237,6 +244,16 @@ func NewQueryEngine(env tabletenv.Env, se *schema.Engine) *QueryEngine {
 		log.Info("Stream consolidator is not enabled.")
 	}
 	qe.txSerializer = txserializer.New(env)
+	if config.QueryQuarantine.Enable {
+		qe.queryRuleSources.RegisterSource(queryQuarantineSource)
+		qe.quarantine = newQueryQuarantine(env, func(qrs *rules.Rules) {
+			if err := qe.queryRuleSources.SetRules(queryQuarantineSource, qrs); err != nil {
+				log.Errorf("Query quarantine: cannot set the query rules: %v", err)
+				return
+			}
+			qe.ClearQueryPlanCache()
+		})
+	}
 
 	qe.strictTableACL = config.StrictTableACL
 	qe.enableTableACLDryRun = config.EnableTableACLDryRun
@@ -342,6 +359,9 @@ func (qe *QueryEngine) Open() error {
 	qe.se.RegisterNotifier("qe", qe.schemaChanged, true)
 	qe.plans.EnsureOpen()
 	qe.settings.EnsureOpen()
+	if qe.quarantine != nil {
+		qe.quarantine.Open()
+	}
 	qe.isOpen.Store(true)
 	return nil
 }
@@ -354,6 +374,9 @@ func (qe *QueryEngine) Close() {
 		return
 	}
 	// Close in reverse order of Open.
+	if qe.quarantine != nil {
+		qe.quarantine.Close()
+	}
 	qe.se.UnregisterNotifier("qe")
 
 	qe.plans.Close()
@@ -375,8 +398,8 @@ func (qe *QueryEngine) getPlan(curSchema *currentSchema, sql string) (*TabletPla
 	if err != nil {
 		return nil, err
 	}
-	plan := &TabletPlan{Plan: splan, Original: sql}
-	plan.Rules = qe.queryRuleSources.FilterByPlan(sql, plan.PlanID, plan.TableNames()...)
+	plan := &TabletPlan{Plan: splan, Original: sql, Fingerprint: sqlparser.Fingerprint(statement)}
+	plan.Rules = qe.queryRuleSources.FilterByPlan(sql, plan.PlanID, plan.TableNames()...).FilterByFingerprint(plan.Fingerprint)
 	plan.buildAuthorized()
 	if sqlparser.CachePlan(statement) {
 		return plan, nil
@@ -421,8 +444,8 @@ func (qe *QueryEngine) getStreamPlan(curSchema *currentSchema, sql string) (*Tab
 		return nil, err
 	}
 
-	plan := &TabletPlan{Plan: splan, Original: sql}
-	plan.Rules = qe.queryRuleSources.FilterByPlan(sql, plan.PlanID, plan.TableName().String())
+	plan := &TabletPlan{Plan: splan, Original: sql, Fingerprint: sqlparser.Fingerprint(statement)}
+	plan.Rules = qe.queryRuleSources.FilterByPlan(sql, plan.PlanID, plan.TableName().String()).FilterByFingerprint(plan.Fingerprint)
 	plan.buildAuthorized()
 
 	if sqlparser.CachePlan(statement) {
@@ -576,6 +599,10 @@ func (qe *QueryEngine) AddStats(plan *TabletPlan, tableName, workload string, ta
 
 	qe.queryCountsWithTabletType.Add([]string{tableName, plan.PlanID.String(), tabletType.String()}, queryCount)
 
+	if qe.quarantine != nil {
+		qe.quarantine.record(plan.Fingerprint, queryCount, duration, errorCount)
+	}
+
 	// queryErrorCountsWithCode is similar to queryErrorCounts except we have an additional dimension
 	// of error code.
 	if errorCount > 0 {
@@ -154,6 +154,11 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 	if err = qre.checkPermissions(); err != nil {
 		return nil, err
 	}
+	release, err := qre.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	if qre.plan.PlanID == p.PlanNextval {
 		return qre.execNextval()
@@ -325,6 +330,11 @@ func (qre *QueryExecutor) Stream(callback StreamCallback) error {
 	if err := qre.checkPermissions(); err != nil {
 		return err
 	}
+	release, err := qre.admit()
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	switch qre.plan.PlanID {
 	case p.PlanSelectStream:
@@ -507,6 +517,24 @@ func (qre *QueryExecutor) checkPermissions() error {
 	return nil
 }
 
+// admit waits for the rate and concurrency limits of the query rules. The
+// returned function must be called once the query is done.
+func (qre *QueryExecutor) admit() (func(), error) {
+	// Skip the limits if the context is local.
+	if tabletenv.IsLocalContext(qre.ctx) {
+		return func() {}, nil
+	}
+
+	remoteAddr := ""
+	username := ""
+	ci, ok := callinfo.FromContext(qre.ctx)
+	if ok {
+		remoteAddr = ci.RemoteAddr()
+		username = ci.Username()
+	}
+	return qre.plan.Rules.Admit(qre.ctx, qre.plan.Fingerprint, remoteAddr, username, qre.bindVars, qre.marginComments)
+}
+
 func (qre *QueryExecutor) checkAccess(authorized *tableacl.ACLResult, tableName string, callerID *querypb.VTGateCallerID) error {
 	statsKey := []string{tableName, authorized.GroupName, qre.plan.PlanID.String(), callerID.Username}
 	if !authorized.IsMember(callerID) {
@@ -1242,6 +1242,43 @@ func TestQueryExecutorDenyListQRRetry(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorRateLimit(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	query := "select * from test_table limit 1000"
+	db.AddQuery(query, &sqltypes.Result{
+		Fields: getTestTableFields(),
+	})
+
+	rateRule := rules.NewQueryRule("slow down test_table", "rate limit test_table", rules.QRContinue)
+	require.NoError(t, rateRule.SetRateLimit(1, 0))
+	rateRule.AddTableCond("test_table")
+
+	rulesName := "rateLimitRules"
+	rules := rules.New()
+	rules.Add(rateRule)
+
+	ctx := callinfo.NewContext(context.Background(), &fakecallinfo.FakeCallInfo{})
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+	tsv.qe.queryRuleSources.RegisterSource(rulesName)
+	defer tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+
+	require.NoError(t, tsv.qe.queryRuleSources.SetRules(rulesName, rules))
+	defer tsv.StopService()
+
+	qre := newTestQueryExecutor(ctx, tsv, query, 0)
+	assert.NotEmpty(t, qre.plan.Fingerprint)
+	_, err := qre.Execute()
+	require.NoError(t, err)
+
+	// The second query within the same second is rejected.
+	qre = newTestQueryExecutor(ctx, tsv, query, 0)
+	_, err = qre.Execute()
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.ErrorContains(t, err, "rate limit of 1 queries per second exceeded due to rule: slow down test_table")
+}
+
 func TestReplaceSchemaName(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
 	defer db.Close()
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mdibaiee/vitess/go/mathstats"
+	"github.com/mdibaiee/vitess/go/stats"
+	"github.com/mdibaiee/vitess/go/timer"
+	"github.com/mdibaiee/vitess/go/vt/log"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/rules"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/tabletenv"
+)
+
+// queryQuarantineSource is the source of the query rules of the quarantine.
+const queryQuarantineSource = "QUERY_QUARANTINE"
+
+// maxQuarantineSamples is the number of latencies kept for each fingerprint
+// in an interval, to estimate its p99 latency.
+const maxQuarantineSamples = 1000
+
+// queryQuarantine denies the queries whose error rate or p99 latency stay
+// above the thresholds of the configuration for several consecutive
+// intervals. A quarantined query is denied by a QRFail rule on its
+// fingerprint, which is lifted after the cooldown.
+type queryQuarantine struct {
+	config tabletenv.QueryQuarantineConfig
+	// setRules replaces the rules of the quarantine.
+	setRules func(*rules.Rules)
+	ticks    *timer.Timer
+
+	quarantines *stats.Counter
+
+	mu sync.Mutex
+	// windows are the stats of the fingerprints in the current interval.
+	windows map[string]*quarantineWindow
+	// strikes are the numbers of consecutive intervals the fingerprints
+	// were above the thresholds.
+	strikes map[string]int
+	// quarantined are the quarantined fingerprints.
+	quarantined map[string]quarantinedQuery
+}
+
+// quarantineWindow is the stats of a fingerprint in an interval.
+type quarantineWindow struct {
+	queries, errors int64
+	// latencies is a uniform sample of the latencies of the queries, in
+	// seconds.
+	latencies []float64
+}
+
+type quarantinedQuery struct {
+	until  time.Time
+	reason string
+}
+
+func newQueryQuarantine(env tabletenv.Env, setRules func(*rules.Rules)) *queryQuarantine {
+	config := env.Config().QueryQuarantine
+	q := &queryQuarantine{
+		config:      config,
+		setRules:    setRules,
+		ticks:       timer.NewTimer(config.Interval),
+		windows:     make(map[string]*quarantineWindow),
+		strikes:     make(map[string]int),
+		quarantined: make(map[string]quarantinedQuery),
+	}
+	q.quarantines = env.Exporter().NewCounter("QueryQuarantines", "Number of queries quarantined")
+	env.Exporter().NewGaugeFunc("QueryQuarantined", "Number of queries currently quarantined", func() int64 {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return int64(len(q.quarantined))
+	})
+	return q
+}
+
+// Open starts measuring the queries.
+func (q *queryQuarantine) Open() {
+	q.ticks.Start(func() { q.evaluate(time.Now()) })
+}
+
+// Close stops measuring the queries. The quarantined queries stay
+// quarantined until the quarantine is open again and their cooldown ends.
+func (q *queryQuarantine) Close() {
+	q.ticks.Stop()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.windows = make(map[string]*quarantineWindow)
+	q.strikes = make(map[string]int)
+}
+
+// record adds the executions of a query to the current interval.
+func (q *queryQuarantine) record(fingerprint string, queryCount int64, duration time.Duration, errorCount int64) {
+	if fingerprint == "" || queryCount <= 0 {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.quarantined[fingerprint]; ok {
+		return
+	}
+	w := q.windows[fingerprint]
+	if w == nil {
+		w = &quarantineWindow{}
+		q.windows[fingerprint] = w
+	}
+	w.queries += queryCount
+	w.errors += errorCount
+	// Reservoir sampling of the latencies.
+	latency := duration.Seconds() / float64(queryCount)
+	if len(w.latencies) < maxQuarantineSamples {
+		w.latencies = append(w.latencies, latency)
+	} else if i := rand.Int64N(w.queries); i < maxQuarantineSamples {
+		w.latencies[i] = latency
+	}
+}
+
+// exceeded returns why the window is above the thresholds, or an empty
+// string if it is not.
+func (q *queryQuarantine) exceeded(w *quarantineWindow) string {
+	if w.queries < int64(q.config.MinQueries) {
+		return ""
+	}
+	if q.config.MaxErrorRate > 0 {
+		if rate := float64(w.errors) / float64(w.queries); rate > q.config.MaxErrorRate {
+			return fmt.Sprintf("error rate %.2f above %.2f", rate, q.config.MaxErrorRate)
+		}
+	}
+	if q.config.MaxP99Latency > 0 {
+		sample := mathstats.Sample{Xs: w.latencies}
+		if p99 := time.Duration(sample.Percentile(0.99) * float64(time.Second)); p99 > q.config.MaxP99Latency {
+			return fmt.Sprintf("p99 latency %v above %v", p99, q.config.MaxP99Latency)
+		}
+	}
+	return ""
+}
+
+// evaluate ends the current interval: it quarantines the fingerprints that
+// were above the thresholds for enough consecutive intervals, and lifts the
+// quarantines whose cooldown ended.
+func (q *queryQuarantine) evaluate(now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	changed := false
+	for fingerprint, quarantined := range q.quarantined {
+		if !now.Before(quarantined.until) {
+			log.Infof("Query quarantine: lifting the quarantine of fingerprint %s", fingerprint)
+			delete(q.quarantined, fingerprint)
+			changed = true
+		}
+	}
+
+	strikes := make(map[string]int)
+	for fingerprint, w := range q.windows {
+		reason := q.exceeded(w)
+		if reason == "" {
+			continue
+		}
+		strikes[fingerprint] = q.strikes[fingerprint] + 1
+		if strikes[fingerprint] < q.config.Windows {
+			continue
+		}
+		log.Warningf("Query quarantine: quarantining fingerprint %s for %v: %s", fingerprint, q.config.Cooldown, reason)
+		q.quarantined[fingerprint] = quarantinedQuery{
+			until:  now.Add(q.config.Cooldown),
+			reason: reason,
+		}
+		delete(strikes, fingerprint)
+		q.quarantines.Add(1)
+		changed = true
+	}
+	// The fingerprints that were not above the thresholds in this interval
+	// start over.
+	q.strikes = strikes
+	q.windows = make(map[string]*quarantineWindow)
+
+	if changed {
+		q.setRules(q.rules())
+	}
+}
+
+// rules returns the rules denying the quarantined fingerprints.
+func (q *queryQuarantine) rules() *rules.Rules {
+	fingerprints := make([]string, 0, len(q.quarantined))
+	for fingerprint := range q.quarantined {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+
+	qrs := rules.New()
+	for _, fingerprint := range fingerprints {
+		quarantined := q.quarantined[fingerprint]
+		qr := rules.NewQueryRule(
+			fmt.Sprintf("query quarantined until %s: %s", quarantined.until.UTC().Format(time.RFC3339), quarantined.reason),
+			"quarantine/"+fingerprint,
+			rules.QRFail)
+		qr.AddFingerprintCond(fingerprint)
+		qrs.Add(qr)
+	}
+	return qrs
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/mysql/fakesqldb"
+	"github.com/mdibaiee/vitess/go/sqltypes"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vtenv"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/rules"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/schema"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/schema/schematest"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/tabletenv"
+)
+
+func newTestQueryQuarantine(config tabletenv.QueryQuarantineConfig) (*queryQuarantine, *[]*rules.Rules) {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.QueryQuarantine = config
+	env := tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "QueryQuarantineTest")
+	var updates []*rules.Rules
+	q := newQueryQuarantine(env, func(qrs *rules.Rules) {
+		updates = append(updates, qrs)
+	})
+	return q, &updates
+}
+
+func TestQueryQuarantineErrorRate(t *testing.T) {
+	q, updates := newTestQueryQuarantine(tabletenv.QueryQuarantineConfig{
+		Enable:       true,
+		Interval:     time.Minute,
+		Windows:      2,
+		MinQueries:   10,
+		MaxErrorRate: 0.5,
+		Cooldown:     10 * time.Minute,
+	})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	quarantines := q.quarantines.Get()
+
+	failing := func(fingerprint string, queries, errors int) {
+		for i := 0; i < queries; i++ {
+			errorCount := int64(0)
+			if i < errors {
+				errorCount = 1
+			}
+			q.record(fingerprint, 1, time.Millisecond, errorCount)
+		}
+	}
+
+	// fp1 fails for two intervals in a row, fp2 has too few queries, and
+	// fp3 stops failing.
+	failing("fp1", 10, 6)
+	failing("fp2", 9, 9)
+	failing("fp3", 10, 6)
+	q.evaluate(now)
+	assert.Empty(t, *updates)
+	assert.Equal(t, map[string]int{"fp1": 1, "fp3": 1}, q.strikes)
+
+	now = now.Add(time.Minute)
+	failing("fp1", 10, 6)
+	failing("fp2", 9, 9)
+	failing("fp3", 10, 5)
+	q.evaluate(now)
+	require.Len(t, *updates, 1)
+	assert.Empty(t, q.strikes)
+	assert.EqualValues(t, 1, q.quarantines.Get()-quarantines)
+
+	want := rules.New()
+	qr := rules.NewQueryRule("query quarantined until 2024-01-01T00:11:00Z: error rate 0.60 above 0.50", "quarantine/fp1", rules.QRFail)
+	qr.AddFingerprintCond("fp1")
+	want.Add(qr)
+	assert.True(t, want.Equal((*updates)[0]), "got %s", marshalRules(t, (*updates)[0]))
+
+	// The quarantined queries are not measured.
+	failing("fp1", 10, 10)
+	assert.NotContains(t, q.windows, "fp1")
+
+	// The quarantine is lifted after the cooldown.
+	now = now.Add(9 * time.Minute)
+	q.evaluate(now)
+	require.Len(t, *updates, 1)
+	now = now.Add(time.Minute)
+	q.evaluate(now)
+	require.Len(t, *updates, 2)
+	assert.True(t, rules.New().Equal((*updates)[1]))
+	assert.Empty(t, q.quarantined)
+}
+
+func TestQueryQuarantineLatency(t *testing.T) {
+	q, updates := newTestQueryQuarantine(tabletenv.QueryQuarantineConfig{
+		Enable:        true,
+		Interval:      time.Minute,
+		Windows:       1,
+		MinQueries:    100,
+		MaxP99Latency: 100 * time.Millisecond,
+		Cooldown:      time.Minute,
+	})
+
+	// A few slow queries don't make the p99 latency.
+	for i := 0; i < maxQuarantineSamples; i++ {
+		latency := time.Millisecond
+		if i%200 == 0 {
+			latency = time.Second
+		}
+		q.record("fp1", 1, latency, 0)
+	}
+	q.evaluate(time.Now())
+	assert.Empty(t, *updates)
+
+	for i := 0; i < 5*maxQuarantineSamples; i++ {
+		latency := time.Millisecond
+		if i%10 == 0 {
+			latency = time.Second
+		}
+		q.record("fp1", 1, latency, 0)
+	}
+	assert.Len(t, q.windows["fp1"].latencies, maxQuarantineSamples)
+	q.evaluate(time.Now())
+	require.Len(t, *updates, 1)
+	assert.Contains(t, q.quarantined["fp1"].reason, "p99 latency")
+}
+
+func TestQueryEngineQuarantine(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	schematest.AddDefaultQueries(db)
+	db.AddQuery("select * from test_table_01 where 1 != 1", &sqltypes.Result{})
+	db.AddQuery("select * from test_table_02 where 1 != 1", &sqltypes.Result{})
+
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.DB = newDBConfigs(db)
+	cfg.QueryQuarantine = tabletenv.QueryQuarantineConfig{
+		Enable:       true,
+		Interval:     time.Hour,
+		Windows:      1,
+		MinQueries:   1,
+		MaxErrorRate: 0.5,
+		Cooldown:     time.Hour,
+	}
+	env := tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "TestQueryEngineQuarantine")
+	qe := NewQueryEngine(env, schema.NewEngine(env))
+	qe.se.InitDBConfig(cfg.DB.DbaWithDB())
+	qe.se.Open()
+	require.NoError(t, qe.Open())
+	defer qe.Close()
+
+	ctx := context.Background()
+	logStats := tabletenv.NewLogStats(ctx, "GetPlanStats")
+	plan, err := qe.GetPlan(ctx, logStats, "select * from test_table_01 where id = 1", false)
+	require.NoError(t, err)
+	require.NotEmpty(t, plan.Fingerprint)
+	qe.AddStats(plan, "test_table_01", "", 0, 1, time.Millisecond, time.Millisecond, 0, 0, 1, "UNKNOWN")
+	qe.quarantine.evaluate(time.Now())
+
+	// The plans of the same fingerprint are denied.
+	plan, err = qe.GetPlan(ctx, logStats, "select * from test_table_01 where id = 2", false)
+	require.NoError(t, err)
+	action, _, _, desc := plan.Rules.GetAction("", "", nil, sqlparser.MarginComments{})
+	assert.Equal(t, rules.QRFail, action)
+	assert.Contains(t, desc, "query quarantined until")
+
+	plan, err = qe.GetPlan(ctx, logStats, "select * from test_table_02", false)
+	require.NoError(t, err)
+	action, _, _, _ = plan.Rules.GetAction("", "", nil, sqlparser.MarginComments{})
+	assert.Equal(t, rules.QRContinue, action)
+}
+
+func marshalRules(t *testing.T, qrs *rules.Rules) string {
+	b, err := qrs.MarshalJSON()
+	require.NoError(t, err)
+	return string(b)
+}
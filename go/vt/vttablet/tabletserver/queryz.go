@@ -34,6 +34,7 @@ var (
 	queryzHeader = []byte(`<thead>
 		<tr>
 			<th>Query</th>
+			<th>Fingerprint</th>
 			<th>Table</th>
 			<th>Plan</th>
 			<th>Count</th>
@@ -53,6 +54,7 @@ var (
 	queryzTmpl = template.Must(template.New("example").Parse(`
 		<tr class="{{.Color}}">
 			<td>{{.Query}}</td>
+			<td>{{.Fingerprint}}</td>
 			<td>{{.Table}}</td>
 			<td>{{.Plan}}</td>
 			<td>{{.Count}}</td>
@@ -74,6 +76,7 @@ var (
 // using go's template.
 type queryzRow struct {
 	Query        string
+	Fingerprint  string
 	Table        string
 	Plan         planbuilder.PlanType
 	Count        uint64
@@ -156,9 +159,10 @@ func queryzHandler(qe *QueryEngine, w http.ResponseWriter, r *http.Request) {
 			return true
 		}
 		Value := &queryzRow{
-			Query: logz.Wrappable(qe.env.Environment().Parser().TruncateForUI(plan.Original)),
-			Table: plan.TableName().String(),
-			Plan:  plan.PlanID,
+			Query:       logz.Wrappable(qe.env.Environment().Parser().TruncateForUI(plan.Original)),
+			Fingerprint: plan.Fingerprint,
+			Table:       plan.TableName().String(),
+			Plan:        plan.PlanID,
 		}
 		Value.Count, Value.tm, Value.mysqlTime, Value.RowsAffected, Value.RowsReturned, Value.Errors = plan.Stats()
 		var timepq time.Duration
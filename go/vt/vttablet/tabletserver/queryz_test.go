@@ -39,7 +39,8 @@ func TestQueryzHandler(t *testing.T) {
 
 	const query1 = "select name from test_table"
 	plan1 := &TabletPlan{
-		Original: query1,
+		Original:    query1,
+		Fingerprint: "a5dc1ae5cf0b44d5",
 		Plan: &planbuilder.Plan{
 			Table:  &schema.Table{Name: sqlparser.NewIdentifierCS("test_table")},
 			PlanID: planbuilder.PlanSelect,
@@ -94,6 +95,7 @@ func TestQueryzHandler(t *testing.T) {
 	planPattern1 := []string{
 		`<tr class="high">`,
 		`<td>select name from test_table</td>`,
+		`<td>a5dc1ae5cf0b44d5</td>`,
 		`<td>test_table</td>`,
 		`<td>Select</td>`,
 		`<td>10</td>`,
@@ -112,6 +114,7 @@ func TestQueryzHandler(t *testing.T) {
 	planPattern2 := []string{
 		`<tr class="low">`,
 		`<td>insert into test_table values 1</td>`,
+		`<td></td>`,
 		`<td>test_table</td>`,
 		`<td>DDL</td>`,
 		`<td>1</td>`,
@@ -131,6 +134,7 @@ func TestQueryzHandler(t *testing.T) {
 		`<tr class="medium">`,
 		`<td>show tables</td>`,
 		`<td></td>`,
+		`<td></td>`,
 		`<td>OtherRead</td>`,
 		`<td>1</td>`,
 		`<td>0.075000</td>`,
@@ -149,6 +153,7 @@ func TestQueryzHandler(t *testing.T) {
 		`<tr class="low">`,
 		`<td>insert into test_table values .* \[TRUNCATED\][^<]*</td>`,
 		`<td></td>`,
+		`<td></td>`,
 		`<td>OtherRead</td>`,
 		`<td>1</td>`,
 		`<td>0.001000</td>`,
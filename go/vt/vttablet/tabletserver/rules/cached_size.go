@@ -45,7 +45,7 @@ func (cached *Rule) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(256)
+		size += int64(288)
 	}
 	// field Description string
 	size += hack.RuntimeAllocSize(int64(len(cached.Description)))
@@ -72,6 +72,13 @@ func (cached *Rule) CachedSize(alloc bool) int64 {
 			size += hack.RuntimeAllocSize(int64(len(elem)))
 		}
 	}
+	// field fingerprints []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.fingerprints)) * int64(16))
+		for _, elem := range cached.fingerprints {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
 	// field bindVarConds []github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/rules.BindVarCond
 	{
 		size += hack.RuntimeAllocSize(int64(cap(cached.bindVarConds)) * int64(48))
@@ -79,6 +86,8 @@ func (cached *Rule) CachedSize(alloc bool) int64 {
 			size += elem.CachedSize(false)
 		}
 	}
+	// field limiter *github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/rules.limiter
+	size += cached.limiter.CachedSize(true)
 	return size
 }
 func (cached *Rules) CachedSize(alloc bool) int64 {
@@ -112,6 +121,16 @@ func (cached *bvcre) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *limiter) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(96)
+	}
+	return size
+}
 func (cached *namedRegexp) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -0,0 +1,289 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/mdibaiee/vitess/go/stats"
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+
+	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
+)
+
+// limitCounts counts the queries subjected to the limit rules, by rule
+// and by result: Admitted right away, Queued before being admitted, or
+// Rejected.
+var limitCounts = stats.NewCountersWithMultiLabels(
+	"QueryRuleLimits",
+	"Queries subjected to the rate and concurrency limits of the query rules",
+	[]string{"Rule", "Result"})
+
+// limiterSweepInterval is how often the limiters remove the limits of the
+// fingerprints which are idle.
+var limiterSweepInterval = time.Minute
+
+// limiter enforces the limit of a QRRateLimit or QRConcurrencyLimit rule.
+// Each fingerprint of the queries matching the rule is limited separately.
+// A limiter is shared by the copies of its rule, so that the plans built
+// from the same rule share its limits.
+type limiter struct {
+	// maxQPS is the number of queries per second of a fingerprint,
+	// for QRRateLimit.
+	maxQPS float64
+	// maxConcurrency is the number of concurrent executions of a
+	// fingerprint, for QRConcurrencyLimit.
+	maxConcurrency int
+	// queueTimeout is how long the excess queries wait for the limit,
+	// before being rejected. They are rejected right away if it is 0.
+	queueTimeout time.Duration
+
+	// mu guards the users of the limits, and their removal.
+	mu sync.Mutex
+	// limits holds the *fingerprintLimit of each fingerprint. A fingerprint
+	// is removed once its limit is idle, so that the limits don't pile up
+	// with the fingerprints of all the queries ever run. It is a sync.Map
+	// so that CachedSize, which doesn't hold mu, never reads it.
+	limits    sync.Map
+	lastSweep time.Time
+}
+
+// fingerprintLimit is the limit of a fingerprint: rate for QRRateLimit, or
+// the slots of the running executions for QRConcurrencyLimit.
+type fingerprintLimit struct {
+	rate  *rate.Limiter
+	slots chan struct{}
+	// users is the number of queries being admitted or running with the
+	// limit, which can't be removed while they use it.
+	users int
+}
+
+// idle returns true if the limit is the same as a new one: no query uses
+// it, and the rate limiter has its full burst of queries available again.
+func (fl *fingerprintLimit) idle() bool {
+	if fl.users > 0 {
+		return false
+	}
+	if fl.rate != nil {
+		return fl.rate.Tokens() >= float64(fl.rate.Burst())
+	}
+	return len(fl.slots) == 0
+}
+
+func newLimiter(maxQPS float64, maxConcurrency int, queueTimeout time.Duration) *limiter {
+	return &limiter{
+		maxQPS:         maxQPS,
+		maxConcurrency: maxConcurrency,
+		queueTimeout:   queueTimeout,
+	}
+}
+
+// equal returns true if other has the same limits.
+func (l *limiter) equal(other *limiter) bool {
+	if l == nil || other == nil {
+		return l == nil && other == nil
+	}
+	return l.maxQPS == other.maxQPS && l.maxConcurrency == other.maxConcurrency && l.queueTimeout == other.queueTimeout
+}
+
+// acquire returns the limit of the fingerprint, which the caller uses until
+// it calls release.
+func (l *limiter) acquire(fingerprint string) *fingerprintLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now := time.Now(); now.Sub(l.lastSweep) >= limiterSweepInterval {
+		l.removeIdle()
+		l.lastSweep = now
+	}
+	var fl *fingerprintLimit
+	if value, ok := l.limits.Load(fingerprint); ok {
+		fl = value.(*fingerprintLimit)
+	} else {
+		fl = &fingerprintLimit{}
+		if l.maxConcurrency > 0 {
+			fl.slots = make(chan struct{}, l.maxConcurrency)
+		} else {
+			// A burst of one second of queries.
+			fl.rate = rate.NewLimiter(rate.Limit(l.maxQPS), int(math.Max(1, math.Ceil(l.maxQPS))))
+		}
+		l.limits.Store(fingerprint, fl)
+	}
+	fl.users++
+	return fl
+}
+
+// release stops using the limit of the fingerprint, and removes it if it is idle.
+func (l *limiter) release(fingerprint string, fl *fingerprintLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fl.users--
+	if fl.idle() {
+		l.limits.CompareAndDelete(fingerprint, fl)
+	}
+}
+
+// removeIdle removes the limits of the fingerprints which are idle. The
+// rate limiters only become idle some time after their last query, so they
+// are not removed when released. It must be called with mu held.
+func (l *limiter) removeIdle() {
+	l.limits.Range(func(fingerprint, fl any) bool {
+		if fl.(*fingerprintLimit).idle() {
+			l.limits.Delete(fingerprint)
+		}
+		return true
+	})
+}
+
+// admit waits for the limit of the rule for the fingerprint, and returns
+// the function to call once the query is done. ok is false if the query
+// exceeded the limit, and queued is true if it had to wait for it.
+func (l *limiter) admit(ctx context.Context, act Action, fingerprint string) (release func(), ok, queued bool) {
+	switch act {
+	case QRRateLimit:
+		fl := l.acquire(fingerprint)
+		defer l.release(fingerprint, fl)
+		if fl.rate.Allow() {
+			return func() {}, true, false
+		}
+		if l.queueTimeout == 0 {
+			return nil, false, false
+		}
+		ctx, cancel := context.WithTimeout(ctx, l.queueTimeout)
+		defer cancel()
+		// Wait fails right away if the wait would exceed the queue timeout.
+		if err := fl.rate.Wait(ctx); err != nil {
+			return nil, false, true
+		}
+		return func() {}, true, true
+	case QRConcurrencyLimit:
+		fl := l.acquire(fingerprint)
+		release = func() {
+			<-fl.slots
+			l.release(fingerprint, fl)
+		}
+		select {
+		case fl.slots <- struct{}{}:
+			return release, true, false
+		default:
+		}
+		if l.queueTimeout == 0 {
+			l.release(fingerprint, fl)
+			return nil, false, false
+		}
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		select {
+		case fl.slots <- struct{}{}:
+			return release, true, true
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		l.release(fingerprint, fl)
+		return nil, false, true
+	}
+	return func() {}, true, false
+}
+
+// isLimit returns true for the actions that limit the queries instead of
+// failing or buffering them.
+func (act Action) isLimit() bool {
+	return act == QRRateLimit || act == QRConcurrencyLimit
+}
+
+// SetRateLimit makes the rule a QRRateLimit rule, which limits each query
+// fingerprint matching it to maxQPS queries per second. The excess queries
+// wait up to queueTimeout for the limit, or are rejected.
+func (qr *Rule) SetRateLimit(maxQPS float64, queueTimeout time.Duration) error {
+	if maxQPS <= 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS must be positive for a RATE_LIMIT rule")
+	}
+	qr.act = QRRateLimit
+	qr.limiter = newLimiter(maxQPS, 0, queueTimeout)
+	return nil
+}
+
+// SetConcurrencyLimit makes the rule a QRConcurrencyLimit rule, which limits
+// each query fingerprint matching it to maxConcurrency concurrent executions.
+// The excess queries wait up to queueTimeout for an execution to finish, or
+// are rejected.
+func (qr *Rule) SetConcurrencyLimit(maxConcurrency int, queueTimeout time.Duration) error {
+	if maxConcurrency <= 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxConcurrency must be positive for a CONCURRENCY_LIMIT rule")
+	}
+	qr.act = QRConcurrencyLimit
+	qr.limiter = newLimiter(0, maxConcurrency, queueTimeout)
+	return nil
+}
+
+// HasLimits returns true if some of the rules limit the rate or the
+// concurrency of the queries.
+func (qrs *Rules) HasLimits() bool {
+	for _, qr := range qrs.rules {
+		if qr.limiter != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Admit applies the rate and concurrency limits of the rules matching the
+// query to its fingerprint. It waits for the limits if the rules queue the
+// excess queries, and returns an error if the query exceeded one of them.
+// Otherwise, the returned function must be called once the query is done.
+func (qrs *Rules) Admit(
+	ctx context.Context,
+	fingerprint,
+	ip,
+	user string,
+	bindVars map[string]*querypb.BindVariable,
+	marginComments sqlparser.MarginComments,
+) (func(), error) {
+	var releases []func()
+	release := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	for _, qr := range qrs.rules {
+		if qr.limiter == nil || qr.GetAction(ip, user, bindVars, marginComments) == QRContinue {
+			continue
+		}
+		r, ok, queued := qr.limiter.admit(ctx, qr.act, fingerprint)
+		if !ok {
+			limitCounts.Add([]string{qr.Name, "Rejected"}, 1)
+			release()
+			if qr.act == QRRateLimit {
+				return nil, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "rate limit of %v queries per second exceeded due to rule: %s", qr.limiter.maxQPS, qr.Description)
+			}
+			return nil, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "concurrency limit of %v queries exceeded due to rule: %s", qr.limiter.maxConcurrency, qr.Description)
+		}
+		if queued {
+			limitCounts.Add([]string{qr.Name, "Queued"}, 1)
+		} else {
+			limitCounts.Add([]string{qr.Name, "Admitted"}, 1)
+		}
+		releases = append(releases, r)
+	}
+	return release, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdibaiee/vitess/go/vt/sqlparser"
+	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/planbuilder"
+
+	vtrpcpb "github.com/mdibaiee/vitess/go/vt/proto/vtrpc"
+)
+
+func TestImportLimits(t *testing.T) {
+	jsondata := `[{
+		"Description": "slow down the reports",
+		"Name": "reports",
+		"TableNames": ["reports"],
+		"Action": "RATE_LIMIT",
+		"MaxQPS": 2.5,
+		"QueueTimeout": "1s"
+	},{
+		"Description": "cap the exports",
+		"Name": "exports",
+		"Action": "CONCURRENCY_LIMIT",
+		"MaxConcurrency": 4
+	}]`
+	qrs := New()
+	require.NoError(t, qrs.UnmarshalJSON([]byte(jsondata)))
+	assert.Equal(t, QRRateLimit, qrs.rules[0].act)
+	assert.Equal(t, 2.5, qrs.rules[0].limiter.maxQPS)
+	assert.Equal(t, time.Second, qrs.rules[0].limiter.queueTimeout)
+	assert.Equal(t, QRConcurrencyLimit, qrs.rules[1].act)
+	assert.Equal(t, 4, qrs.rules[1].limiter.maxConcurrency)
+	assert.Equal(t, compacted(jsondata), marshalled(qrs))
+	assert.True(t, qrs.HasLimits())
+
+	// The copies share the limits.
+	cpy := qrs.Copy()
+	assert.True(t, qrs.Equal(cpy))
+	assert.Same(t, qrs.rules[0].limiter, cpy.rules[0].limiter)
+
+	invalid := []struct {
+		input, err string
+	}{
+		{`[{"Action": "RATE_LIMIT"}]`, "MaxQPS must be positive for a RATE_LIMIT rule"},
+		{`[{"Action": "CONCURRENCY_LIMIT", "MaxConcurrency": 0}]`, "MaxConcurrency must be positive for a CONCURRENCY_LIMIT rule"},
+		{`[{"Action": "CONCURRENCY_LIMIT", "MaxConcurrency": 1.5}]`, "want integer for MaxConcurrency: 1.5"},
+		{`[{"Action": "RATE_LIMIT", "MaxQPS": "1"}]`, "want number for MaxQPS"},
+		{`[{"Action": "RATE_LIMIT", "MaxQPS": 1, "QueueTimeout": "soon"}]`, "invalid QueueTimeout: soon"},
+		{`[{"Action": "FAIL", "MaxQPS": 1}]`, "MaxQPS, MaxConcurrency and QueueTimeout are only valid for the RATE_LIMIT and CONCURRENCY_LIMIT actions"},
+	}
+	for _, tcase := range invalid {
+		err := New().UnmarshalJSON([]byte(tcase.input))
+		assert.EqualError(t, err, tcase.err, tcase.input)
+	}
+}
+
+func TestLimitsDoNotFail(t *testing.T) {
+	qrs := New()
+	qr := NewQueryRule("rate limit", "rate", QRContinue)
+	require.NoError(t, qr.SetRateLimit(1, 0))
+	qrs.Add(qr)
+	qrs.Add(NewQueryRule("fail", "fail", QRFail))
+
+	// The limit rules don't hide the next rules.
+	action, _, _, desc := qrs.GetAction("123", "user1", nil, sqlparser.MarginComments{})
+	assert.Equal(t, QRFail, action)
+	assert.Equal(t, "fail", desc)
+
+	assert.False(t, New().HasLimits())
+	assert.True(t, qrs.FilterByPlan("select * from t", planbuilder.PlanSelect).HasLimits())
+}
+
+func TestAdmitRateLimit(t *testing.T) {
+	qrs := New()
+	qr := NewQueryRule("rate limit", "TestAdmitRateLimit", QRContinue)
+	require.NoError(t, qr.SetRateLimit(2, 0))
+	require.NoError(t, qr.SetUserCond("user1"))
+	qrs.Add(qr)
+
+	admit := func(fingerprint, user string) error {
+		release, err := qrs.Admit(context.Background(), fingerprint, "123", user, nil, sqlparser.MarginComments{})
+		if err == nil {
+			release()
+		}
+		return err
+	}
+	require.NoError(t, admit("fp1", "user1"))
+	require.NoError(t, admit("fp1", "user1"))
+	err := admit("fp1", "user1")
+	assert.EqualError(t, err, "rate limit of 2 queries per second exceeded due to rule: rate limit")
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+
+	// The fingerprints are limited separately, and the rule only applies
+	// to the queries it matches.
+	require.NoError(t, admit("fp2", "user1"))
+	require.NoError(t, admit("fp1", "user2"))
+
+	assert.EqualValues(t, 3, limitCounts.Counts()["TestAdmitRateLimit.Admitted"])
+	assert.EqualValues(t, 1, limitCounts.Counts()["TestAdmitRateLimit.Rejected"])
+
+	// The excess queries can wait for the limit.
+	require.NoError(t, qr.SetRateLimit(10, time.Second))
+	for i := 0; i < 11; i++ {
+		require.NoError(t, admit("fp1", "user1"))
+	}
+	assert.EqualValues(t, 1, limitCounts.Counts()["TestAdmitRateLimit.Queued"])
+}
+
+func TestAdmitConcurrencyLimit(t *testing.T) {
+	qrs := New()
+	qr := NewQueryRule("concurrency limit", "TestAdmitConcurrencyLimit", QRContinue)
+	require.NoError(t, qr.SetConcurrencyLimit(1, 0))
+	qrs.Add(qr)
+
+	ctx := context.Background()
+	release, err := qrs.Admit(ctx, "fp1", "123", "user1", nil, sqlparser.MarginComments{})
+	require.NoError(t, err)
+	_, err = qrs.Admit(ctx, "fp1", "123", "user1", nil, sqlparser.MarginComments{})
+	assert.EqualError(t, err, "concurrency limit of 1 queries exceeded due to rule: concurrency limit")
+	release()
+	release, err = qrs.Admit(ctx, "fp1", "123", "user1", nil, sqlparser.MarginComments{})
+	require.NoError(t, err)
+	release()
+
+	// The excess queries wait for an execution to finish.
+	require.NoError(t, qr.SetConcurrencyLimit(1, 10*time.Second))
+	running, err := qrs.Admit(ctx, "fp1", "123", "user1", nil, sqlparser.MarginComments{})
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		running()
+	}()
+	release, err = qrs.Admit(ctx, "fp1", "123", "user1", nil, sqlparser.MarginComments{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, limitCounts.Counts()["TestAdmitConcurrencyLimit.Queued"])
+
+	// Or until their context is done.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = qrs.Admit(cancelCtx, "fp1", "123", "user1", nil, sqlparser.MarginComments{})
+	assert.ErrorContains(t, err, "concurrency limit of 1 queries exceeded")
+	release()
+}
+
+func TestLimitsRemovedWhenIdle(t *testing.T) {
+	defer func(interval time.Duration) { limiterSweepInterval = interval }(limiterSweepInterval)
+	limiterSweepInterval = 0
+
+	ctx := context.Background()
+	concurrency := newLimiter(0, 1, 0)
+	release, ok, _ := concurrency.admit(ctx, QRConcurrencyLimit, "fp1")
+	require.True(t, ok)
+	_, ok, _ = concurrency.admit(ctx, QRConcurrencyLimit, "fp1")
+	require.False(t, ok)
+	assert.Equal(t, 1, limitCount(concurrency))
+	// The slots of a fingerprint are removed once its last execution is done.
+	release()
+	assert.Zero(t, limitCount(concurrency))
+
+	rates := newLimiter(1000, 0, 0)
+	for _, fingerprint := range []string{"fp1", "fp2", "fp3"} {
+		_, ok, _ = rates.admit(ctx, QRRateLimit, fingerprint)
+		require.True(t, ok)
+	}
+	assert.Equal(t, 3, limitCount(rates))
+	// The rate limiters are removed once their burst is available again.
+	require.Eventually(t, func() bool {
+		rates.acquire("fp4")
+		return limitCount(rates) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The rate limiters which are not idle are kept.
+	slow := newLimiter(0.001, 0, 0)
+	_, ok, _ = slow.admit(ctx, QRRateLimit, "fp1")
+	require.True(t, ok)
+	slow.acquire("fp2")
+	assert.Equal(t, 2, limitCount(slow))
+}
+
+func limitCount(l *limiter) int {
+	count := 0
+	l.limits.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	return count
+}
@@ -169,6 +169,28 @@ func (qrs *Rules) FilterByPlan(query string, planid planbuilder.PlanType, tableN
 	return &Rules{newrules}
 }
 
+// FilterByFingerprint creates a new Rules by prefiltering the rules with
+// fingerprint conditions on the fingerprint of the query. It is applied to the
+// rules of a plan after FilterByPlan. In the new rules, the fingerprints
+// predicates are empty. The rules with fingerprint conditions never fire
+// unless they went through FilterByFingerprint.
+func (qrs *Rules) FilterByFingerprint(fingerprint string) (newqrs *Rules) {
+	var newrules []*Rule
+	for _, qr := range qrs.rules {
+		if qr.fingerprints == nil {
+			newrules = append(newrules, qr)
+			continue
+		}
+		if !fingerprintMatch(qr.fingerprints, fingerprint) {
+			continue
+		}
+		newrule := qr.Copy()
+		newrule.fingerprints = nil
+		newrules = append(newrules, newrule)
+	}
+	return &Rules{newrules}
+}
+
 // GetAction runs the input against the rules engine and returns the action to be performed.
 func (qrs *Rules) GetAction(
 	ip,
@@ -181,6 +203,10 @@ func (qrs *Rules) GetAction(
 	timeout time.Duration,
 	desc string) {
 	for _, qr := range qrs.rules {
+		// The limit rules are applied by Admit.
+		if qr.act.isLimit() {
+			continue
+		}
 		if act := qr.GetAction(ip, user, bindVars, marginComments); act != QRContinue {
 			return act, qr.cancelCtx, qr.timeout, qr.Description
 		}
@@ -213,6 +239,9 @@ type Rule struct {
 	// Any matched tableNames will make this condition true (OR)
 	tableNames []string
 
+	// Any matched fingerprints will make this condition true (OR)
+	fingerprints []string
+
 	// All BindVar conditions have to be fulfilled to make this true (AND)
 	bindVarConds []BindVarCond
 
@@ -224,6 +253,9 @@ type Rule struct {
 
 	// a rule can timeout.
 	timeout time.Duration
+
+	// limiter enforces the limits of QRRateLimit and QRConcurrencyLimit.
+	limiter *limiter
 }
 
 type namedRegexp struct {
@@ -269,8 +301,10 @@ func (qr *Rule) Equal(other *Rule) bool {
 		qr.leadingComment.Equal(other.leadingComment) &&
 		qr.trailingComment.Equal(other.trailingComment) &&
 		qr.timeout == other.timeout &&
+		qr.limiter.equal(other.limiter) &&
 		reflect.DeepEqual(qr.plans, other.plans) &&
 		reflect.DeepEqual(qr.tableNames, other.tableNames) &&
+		reflect.DeepEqual(qr.fingerprints, other.fingerprints) &&
 		reflect.DeepEqual(qr.bindVarConds, other.bindVarConds) &&
 		qr.act == other.act)
 }
@@ -288,6 +322,7 @@ func (qr *Rule) Copy() (newqr *Rule) {
 		act:             qr.act,
 		cancelCtx:       qr.cancelCtx,
 		timeout:         qr.timeout,
+		limiter:         qr.limiter,
 	}
 	if qr.plans != nil {
 		newqr.plans = make([]planbuilder.PlanType, len(qr.plans))
@@ -297,6 +332,10 @@ func (qr *Rule) Copy() (newqr *Rule) {
 		newqr.tableNames = make([]string, len(qr.tableNames))
 		copy(newqr.tableNames, qr.tableNames)
 	}
+	if qr.fingerprints != nil {
+		newqr.fingerprints = make([]string, len(qr.fingerprints))
+		copy(newqr.fingerprints, qr.fingerprints)
+	}
 	if qr.bindVarConds != nil {
 		newqr.bindVarConds = make([]BindVarCond, len(qr.bindVarConds))
 		copy(newqr.bindVarConds, qr.bindVarConds)
@@ -330,6 +369,9 @@ func (qr *Rule) MarshalJSON() ([]byte, error) {
 	if qr.tableNames != nil {
 		safeEncode(b, `,"TableNames":`, qr.tableNames)
 	}
+	if qr.fingerprints != nil {
+		safeEncode(b, `,"Fingerprints":`, qr.fingerprints)
+	}
 	if qr.bindVarConds != nil {
 		safeEncode(b, `,"BindVarConds":`, qr.bindVarConds)
 	}
@@ -339,6 +381,17 @@ func (qr *Rule) MarshalJSON() ([]byte, error) {
 	if qr.timeout != 0 {
 		safeEncode(b, `,"Timeout":`, qr.timeout)
 	}
+	if qr.limiter != nil {
+		if qr.limiter.maxQPS != 0 {
+			safeEncode(b, `,"MaxQPS":`, qr.limiter.maxQPS)
+		}
+		if qr.limiter.maxConcurrency != 0 {
+			safeEncode(b, `,"MaxConcurrency":`, qr.limiter.maxConcurrency)
+		}
+		if qr.limiter.queueTimeout != 0 {
+			safeEncode(b, `,"QueueTimeout":`, qr.limiter.queueTimeout.String())
+		}
+	}
 	_, _ = b.WriteString("}")
 	return b.Bytes(), nil
 }
@@ -373,6 +426,14 @@ func (qr *Rule) AddTableCond(tableName string) {
 	qr.tableNames = append(qr.tableNames, tableName)
 }
 
+// AddFingerprintCond adds to the list of query fingerprints that can be
+// matched for the rule to fire. The fingerprints are the ones computed by
+// sqlparser.Fingerprint, as shown by /queryz.
+// This function acts as an OR: Any fingerprint match is considered a match.
+func (qr *Rule) AddFingerprintCond(fingerprint string) {
+	qr.fingerprints = append(qr.fingerprints, fingerprint)
+}
+
 // SetQueryCond adds a regular expression condition for the query.
 func (qr *Rule) SetQueryCond(pattern string) (err error) {
 	qr.query.name = pattern
@@ -487,6 +548,11 @@ func (qr *Rule) GetAction(
 	bindVars map[string]*querypb.BindVariable,
 	marginComments sqlparser.MarginComments,
 ) Action {
+	if qr.fingerprints != nil {
+		// The fingerprint of the query is unknown: the rule was not
+		// filtered by FilterByFingerprint.
+		return QRContinue
+	}
 	if qr.cancelCtx != nil {
 		select {
 		case <-qr.cancelCtx.Done():
@@ -549,6 +615,15 @@ func tableMatch(tableNames []string, otherNames []string) bool {
 	return false
 }
 
+func fingerprintMatch(fingerprints []string, fingerprint string) bool {
+	for _, f := range fingerprints {
+		if f == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
 func bvMatch(bvcond BindVarCond, bindVars map[string]*querypb.BindVariable) bool {
 	bv, ok := bindVars[bvcond.name]
 	if !ok {
@@ -573,6 +648,8 @@ const (
 	QRFail
 	QRFailRetry
 	QRBuffer
+	QRRateLimit
+	QRConcurrencyLimit
 )
 
 // MarshalJSON marshals to JSON.
@@ -586,6 +663,10 @@ func (act Action) MarshalJSON() ([]byte, error) {
 		str = "FAIL_RETRY"
 	case QRBuffer:
 		str = "BUFFER"
+	case QRRateLimit:
+		str = "RATE_LIMIT"
+	case QRConcurrencyLimit:
+		str = "CONCURRENCY_LIMIT"
 	default:
 		str = "INVALID"
 	}
@@ -871,21 +952,32 @@ func MapStrOperator(strop string) (op Operator, err error) {
 // BuildQueryRule builds a query rule from a ruleInfo.
 func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 	qr = NewQueryRule("", "", QRFail)
+	var (
+		maxQPS         float64
+		maxConcurrency int64
+		queueTimeout   time.Duration
+	)
 	for k, v := range ruleInfo {
 		var sv string
 		var lv []any
+		var nv json.Number
 		var ok bool
 		switch k {
-		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment":
+		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment", "QueueTimeout":
 			sv, ok = v.(string)
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want string for %s", k)
 			}
-		case "Plans", "BindVarConds", "TableNames":
+		case "Plans", "BindVarConds", "TableNames", "Fingerprints":
 			lv, ok = v.([]any)
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want list for %s", k)
 			}
+		case "MaxQPS", "MaxConcurrency":
+			nv, ok = v.(json.Number)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want number for %s", k)
+			}
 		default:
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized tag %s", k)
 		}
@@ -939,6 +1031,14 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 				}
 				qr.AddTableCond(tableName)
 			}
+		case "Fingerprints":
+			for _, f := range lv {
+				fingerprint, ok := f.(string)
+				if !ok {
+					return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want string for Fingerprints")
+				}
+				qr.AddFingerprintCond(fingerprint)
+			}
 		case "BindVarConds":
 			for _, bvc := range lv {
 				name, onAbsent, onMismatch, op, value, err := buildBindVarCondition(bvc)
@@ -958,11 +1058,43 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 				qr.act = QRFailRetry
 			case "BUFFER":
 				qr.act = QRBuffer
+			case "RATE_LIMIT":
+				qr.act = QRRateLimit
+			case "CONCURRENCY_LIMIT":
+				qr.act = QRConcurrencyLimit
 			default:
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid Action %s", sv)
 			}
+		case "MaxQPS":
+			maxQPS, err = nv.Float64()
+			if err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want number for MaxQPS: %s", nv)
+			}
+		case "MaxConcurrency":
+			maxConcurrency, err = nv.Int64()
+			if err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want integer for MaxConcurrency: %s", nv)
+			}
+		case "QueueTimeout":
+			queueTimeout, err = time.ParseDuration(sv)
+			if err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid QueueTimeout: %s", sv)
+			}
 		}
 	}
+	switch qr.act {
+	case QRRateLimit:
+		err = qr.SetRateLimit(maxQPS, queueTimeout)
+	case QRConcurrencyLimit:
+		err = qr.SetConcurrencyLimit(int(maxConcurrency), queueTimeout)
+	default:
+		if maxQPS != 0 || maxConcurrency != 0 || queueTimeout != 0 {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS, MaxConcurrency and QueueTimeout are only valid for the RATE_LIMIT and CONCURRENCY_LIMIT actions")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
 	return qr, nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mdibaiee/vitess/go/sqltypes"
 	"github.com/mdibaiee/vitess/go/vt/sqlparser"
@@ -278,6 +279,34 @@ func TestFilterByPlan(t *testing.T) {
 	}
 }
 
+func TestFilterByFingerprint(t *testing.T) {
+	qrs := New()
+	qr := NewQueryRule("fingerprint", "r1", QRFail)
+	qr.AddFingerprintCond("fp1")
+	qr.AddFingerprintCond("fp2")
+	qrs.Add(qr)
+	qrs.Add(NewQueryRule("all", "r2", QRFailRetry))
+
+	// The fingerprint rules don't fire until they are filtered.
+	action, _, _, desc := qrs.GetAction("123", "user1", nil, sqlparser.MarginComments{})
+	assert.Equal(t, QRFailRetry, action)
+	assert.Equal(t, "all", desc)
+
+	filtered := qrs.FilterByPlan("select * from t", planbuilder.PlanSelect, "t").FilterByFingerprint("fp2")
+	require.Len(t, filtered.rules, 2)
+	assert.Nil(t, filtered.rules[0].fingerprints)
+	action, _, _, desc = filtered.GetAction("123", "user1", nil, sqlparser.MarginComments{})
+	assert.Equal(t, QRFail, action)
+	assert.Equal(t, "fingerprint", desc)
+
+	filtered = qrs.FilterByFingerprint("fp3")
+	require.Len(t, filtered.rules, 1)
+	assert.Equal(t, "r2", filtered.rules[0].Name)
+
+	// The original rules are untouched.
+	assert.Equal(t, []string{"fp1", "fp2"}, qrs.rules[0].fingerprints)
+}
+
 func TestQueryRule(t *testing.T) {
 	qr := NewQueryRule("rule 1", "r1", QRFail)
 	err := qr.SetIPCond("123")
@@ -596,6 +625,7 @@ func TestImport(t *testing.T) {
 		"Query": "query",
 		"Plans": ["Select", "Insert"],
 		"TableNames":["a", "b"],
+		"Fingerprints":["0b5bc0bbd4f4b9aa"],
 		"BindVarConds": [{
 			"Name": "bvname1",
 			"OnAbsent": true,
@@ -715,6 +745,8 @@ var invalidjsons = []InvalidJSONCase{
 	{`[{"Plans": [1] }]`, "want string for Plans"},
 	{`[{"Plans": ["invalid"] }]`, "invalid plan name: invalid"},
 	{`[{"TableNames": [1] }]`, "want string for TableNames"},
+	{`[{"Fingerprints": 1 }]`, "want list for Fingerprints"},
+	{`[{"Fingerprints": [1] }]`, "want string for Fingerprints"},
 	{`[{"BindVarConds": [1] }]`, "want json object for bind var conditions"},
 	{`[{"BindVarConds": [{}] }]`, "Name missing in BindVarConds"},
 	{`[{"BindVarConds": [{"Name": 1}] }]`, "want string for Name in BindVarConds"},
@@ -107,6 +107,25 @@ func loadMessageInfo(ta *Table, comment string, collationEnv *collations.Environ
 
 	ta.MessageInfo.MaxBackoff, _ = getDuration(keyvals, "vt_max_backoff")
 
+	// the retry policy is optional: messages are retried until acked by default
+	if keyvals["vt_max_attempts"] != "" {
+		if ta.MessageInfo.MaxAttempts, err = getNum(keyvals, "vt_max_attempts"); err != nil {
+			return err
+		}
+		if ta.MessageInfo.MaxAttempts <= 0 {
+			return fmt.Errorf("vt_max_attempts must be positive: %s", ta.Name.String())
+		}
+	}
+	ta.MessageInfo.DeadLetterTable = strings.TrimSpace(keyvals["vt_dead_letter_table"])
+	if ta.MessageInfo.DeadLetterTable != "" {
+		if ta.MessageInfo.MaxAttempts == 0 {
+			return fmt.Errorf("vt_dead_letter_table requires vt_max_attempts: %s", ta.Name.String())
+		}
+		if strings.EqualFold(ta.MessageInfo.DeadLetterTable, ta.Name.String()) {
+			return fmt.Errorf("vt_dead_letter_table must be different from the message table: %s", ta.Name.String())
+		}
+	}
+
 	// these columns are required for message manager to function properly, but only
 	// id is required to be streamed to subscribers
 	requiredCols := []string{
@@ -125,6 +125,17 @@ type MessageInfo struct {
 	// MaxBackoff specifies the longest duration message manager
 	// should wait before rescheduling a message
 	MaxBackoff time.Duration
+
+	// MaxAttempts specifies how many times a message can be
+	// sent without being acked before it's dead-lettered.
+	// Zero means that the message is retried until acked.
+	MaxAttempts int
+
+	// DeadLetterTable specifies the table where dead-lettered
+	// messages are moved to. If empty, dead-lettered messages
+	// stay in the message table and are flagged by clearing
+	// their time_next.
+	DeadLetterTable string
 }
 
 // NewTable creates a new Table.
@@ -143,6 +143,11 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&currentConfig.OltpReadPool.Timeout, "queryserver-config-query-pool-timeout", defaultConfig.OltpReadPool.Timeout, "query server query pool timeout, it is how long vttablet waits for a connection from the query pool. If set to 0 (default) then the overall query timeout is used instead.")
 	fs.DurationVar(&currentConfig.OlapReadPool.Timeout, "queryserver-config-stream-pool-timeout", defaultConfig.OlapReadPool.Timeout, "query server stream pool timeout, it is how long vttablet waits for a connection from the stream pool. If set to 0 (default) then there is no timeout.")
 	fs.DurationVar(&currentConfig.TxPool.Timeout, "queryserver-config-txpool-timeout", defaultConfig.TxPool.Timeout, "query server transaction pool timeout, it is how long vttablet waits if tx pool is full")
+	fs.IntVar(&currentConfig.OltpReadPool.MaxWaiters, "queryserver-config-query-pool-max-waiters", defaultConfig.OltpReadPool.MaxWaiters, "query server query pool max waiters, it is how many requests can wait for a connection from the query pool. When it is reached, the requests with the lowest priority are shed first. If set to 0 (default) then there is no limit.")
+	fs.IntVar(&currentConfig.OlapReadPool.MaxWaiters, "queryserver-config-stream-pool-max-waiters", defaultConfig.OlapReadPool.MaxWaiters, "query server stream pool max waiters, it is how many requests can wait for a connection from the stream pool. When it is reached, the requests with the lowest priority are shed first. If set to 0 (default) then there is no limit.")
+	fs.IntVar(&currentConfig.TxPool.MaxWaiters, "queryserver-config-txpool-max-waiters", defaultConfig.TxPool.MaxWaiters, "query server transaction pool max waiters, it is how many requests can wait if tx pool is full. When it is reached, the requests with the lowest priority are shed first. If set to 0 (default) then there is no limit.")
+	fs.IntVar(&currentConfig.PoolPriority.DefaultPriority, "queryserver-config-pool-default-priority", defaultConfig.PoolPriority.DefaultPriority, "Priority, between 0 (highest) and 100 (lowest), with which the requests that lack priority information wait for a connection when the query server pools are exhausted.")
+	fs.StringToIntVar(&currentConfig.PoolPriority.WorkloadPriorities, "queryserver-config-pool-workload-priorities", defaultConfig.PoolPriority.WorkloadPriorities, "Priorities, between 0 (highest) and 100 (lowest), with which the requests of the given workload names wait for a connection when the query server pools are exhausted, e.g. 'checkout=0,export=100'. The PRIORITY query directive takes precedence over the workload name.")
 	fs.DurationVar(&currentConfig.OltpReadPool.IdleTimeout, "queryserver-config-idle-timeout", defaultConfig.OltpReadPool.IdleTimeout, "query server idle timeout, vttablet manages various mysql connection pools. This config means if a connection has not been used in given idle timeout, this connection will be removed from pool. This effectively manages number of connection objects and optimize the pool performance.")
 	fs.DurationVar(&currentConfig.OltpReadPool.MaxLifetime, "queryserver-config-pool-conn-max-lifetime", defaultConfig.OltpReadPool.MaxLifetime, "query server connection max lifetime, vttablet manages various mysql connection pools. This config means if a connection has lived at least this long, it connection will be removed from pool upon the next time it is returned to the pool.")
 
@@ -174,6 +179,14 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&currentConfig.HotRowProtection.MaxGlobalQueueSize, "hot_row_protection_max_global_queue_size", defaultConfig.HotRowProtection.MaxGlobalQueueSize, "Global queue limit across all row (ranges). Useful to prevent that the queue can grow unbounded.")
 	fs.IntVar(&currentConfig.HotRowProtection.MaxConcurrency, "hot_row_protection_concurrent_transactions", defaultConfig.HotRowProtection.MaxConcurrency, "Number of concurrent transactions let through to the txpool/MySQL for the same hot row. Should be > 1 to have enough 'ready' transactions in MySQL and benefit from a pipelining effect.")
 
+	fs.BoolVar(&currentConfig.QueryQuarantine.Enable, "enable-query-quarantine", defaultConfig.QueryQuarantine.Enable, "If true, the queries whose error rate or p99 latency stay above the thresholds for several consecutive intervals are denied by a query rule on their fingerprint, until the quarantine cooldown.")
+	fs.DurationVar(&currentConfig.QueryQuarantine.Interval, "query-quarantine-interval", defaultConfig.QueryQuarantine.Interval, "Interval over which the error rate and the p99 latency of the queries are measured by the query quarantine.")
+	fs.IntVar(&currentConfig.QueryQuarantine.Windows, "query-quarantine-windows", defaultConfig.QueryQuarantine.Windows, "Number of consecutive intervals over the thresholds after which a query is quarantined.")
+	fs.IntVar(&currentConfig.QueryQuarantine.MinQueries, "query-quarantine-min-queries", defaultConfig.QueryQuarantine.MinQueries, "Minimum number of executions of a query in an interval for the interval to count towards its quarantine.")
+	fs.Float64Var(&currentConfig.QueryQuarantine.MaxErrorRate, "query-quarantine-max-error-rate", defaultConfig.QueryQuarantine.MaxErrorRate, "Fraction of failed executions of a query in an interval above which the interval counts towards its quarantine. Setting to 0 disables the check.")
+	fs.DurationVar(&currentConfig.QueryQuarantine.MaxP99Latency, "query-quarantine-max-p99-latency", defaultConfig.QueryQuarantine.MaxP99Latency, "p99 latency of a query in an interval above which the interval counts towards its quarantine. Setting to 0 disables the check.")
+	fs.DurationVar(&currentConfig.QueryQuarantine.Cooldown, "query-quarantine-cooldown", defaultConfig.QueryQuarantine.Cooldown, "How long a query stays quarantined.")
+
 	fs.BoolVar(&currentConfig.EnableTransactionLimit, "enable_transaction_limit", defaultConfig.EnableTransactionLimit, "If true, limit on number of transactions open at the same time will be enforced for all users. User trying to open a new transaction after exhausting their limit will receive an error immediately, regardless of whether there are available slots or not.")
 	fs.BoolVar(&currentConfig.EnableTransactionLimitDryRun, "enable_transaction_limit_dry_run", defaultConfig.EnableTransactionLimitDryRun, "If true, limit on number of transactions open at the same time will be tracked for all users, but not enforced.")
 	fs.Float64Var(&currentConfig.TransactionLimitPerUser, "transaction_limit_per_user", defaultConfig.TransactionLimitPerUser, "Maximum number of transactions a single user is allowed to use at any time, represented as fraction of -transaction_cap.")
@@ -352,6 +365,10 @@ type TabletConfig struct {
 
 	TransactionLimitConfig `json:"-"`
 
+	QueryQuarantine QueryQuarantineConfig `json:"-"`
+
+	PoolPriority PoolPriorityConfig `json:"-"`
+
 	EnforceStrictTransTables bool `json:"-"`
 	EnableOnlineDDL          bool `json:"-"`
 	EnableSettingsPool       bool `json:"-"`
@@ -445,6 +462,7 @@ type ConnPoolConfig struct {
 	IdleTimeout        time.Duration `json:"idleTimeoutSeconds,omitempty"`
 	MaxLifetime        time.Duration `json:"maxLifetimeSeconds,omitempty"`
 	PrefillParallelism int           `json:"prefillParallelism,omitempty"`
+	MaxWaiters         int           `json:"maxWaiters,omitempty"`
 }
 
 func (cfg *ConnPoolConfig) MarshalJSON() ([]byte, error) {
@@ -481,6 +499,7 @@ func (cfg *ConnPoolConfig) UnmarshalJSON(data []byte) (err error) {
 		IdleTimeout        string `json:"idleTimeoutSeconds,omitempty"`
 		MaxLifetime        string `json:"maxLifetimeSeconds,omitempty"`
 		PrefillParallelism int    `json:"prefillParallelism,omitempty"`
+		MaxWaiters         int    `json:"maxWaiters,omitempty"`
 	}
 
 	if err := json.Unmarshal(data, &tmp); err != nil {
@@ -510,6 +529,7 @@ func (cfg *ConnPoolConfig) UnmarshalJSON(data []byte) (err error) {
 
 	cfg.Size = tmp.Size
 	cfg.PrefillParallelism = tmp.PrefillParallelism
+	cfg.MaxWaiters = tmp.MaxWaiters
 
 	return nil
 }
@@ -807,6 +827,35 @@ type TransactionLimitConfig struct {
 	TransactionLimitBySubcomponent bool
 }
 
+// QueryQuarantineConfig contains the config for the query quarantine, which
+// denies the queries that keep failing or being slow for a while.
+type QueryQuarantineConfig struct {
+	Enable bool
+	// Interval is the length of the intervals over which the queries are measured.
+	Interval time.Duration
+	// Windows is the number of consecutive intervals over the thresholds
+	// after which a query is quarantined.
+	Windows int
+	// MinQueries is the number of executions of a query below which an
+	// interval does not count.
+	MinQueries int
+	// MaxErrorRate and MaxP99Latency are the thresholds. They are ignored if 0.
+	MaxErrorRate  float64
+	MaxP99Latency time.Duration
+	// Cooldown is how long a query stays quarantined.
+	Cooldown time.Duration
+}
+
+// PoolPriorityConfig contains the config for the priorities with which the requests
+// wait for a connection when the connection pools are exhausted.
+type PoolPriorityConfig struct {
+	// DefaultPriority is the priority of the requests without a PRIORITY directive
+	// or a workload name in WorkloadPriorities.
+	DefaultPriority int
+	// WorkloadPriorities are the priorities of the requests by workload name.
+	WorkloadPriorities map[string]int
+}
+
 // RowStreamerConfig contains configuration parameters for a vstreamer (source) that is
 // copying the contents of a table to a target
 type RowStreamerConfig struct {
@@ -869,6 +918,12 @@ func (c *TabletConfig) Verify() error {
 	if err := c.verifyTxThrottlerConfig(); err != nil {
 		return err
 	}
+	if err := c.verifyQueryQuarantineConfig(); err != nil {
+		return err
+	}
+	if err := c.verifyPoolPriorityConfig(); err != nil {
+		return err
+	}
 	if v := c.HotRowProtection.MaxQueueSize; v <= 0 {
 		return fmt.Errorf("--hot_row_protection_max_queue_size must be > 0 (specified value: %v)", v)
 	}
@@ -988,6 +1043,60 @@ func (c *TabletConfig) verifyTxThrottlerConfig() error {
 	return nil
 }
 
+// verifyPoolPriorityConfig checks PoolPriorityConfig and the max waiters of the pools for sanity.
+func (c *TabletConfig) verifyPoolPriorityConfig() error {
+	if v := c.PoolPriority.DefaultPriority; v < 0 || v > sqlparser.MaxPriorityValue {
+		return fmt.Errorf("--queryserver-config-pool-default-priority must be between 0 and %d (specified value: %v)", sqlparser.MaxPriorityValue, v)
+	}
+	for workload, v := range c.PoolPriority.WorkloadPriorities {
+		if v < 0 || v > sqlparser.MaxPriorityValue {
+			return fmt.Errorf("--queryserver-config-pool-workload-priorities must be between 0 and %d (specified value for %s: %v)", sqlparser.MaxPriorityValue, workload, v)
+		}
+	}
+	for _, pool := range []struct {
+		flag string
+		cfg  ConnPoolConfig
+	}{
+		{"--queryserver-config-query-pool-max-waiters", c.OltpReadPool},
+		{"--queryserver-config-stream-pool-max-waiters", c.OlapReadPool},
+		{"--queryserver-config-txpool-max-waiters", c.TxPool},
+	} {
+		if v := pool.cfg.MaxWaiters; v < 0 {
+			return fmt.Errorf("%s must be >= 0 (specified value: %v)", pool.flag, v)
+		}
+	}
+	return nil
+}
+
+// verifyQueryQuarantineConfig checks QueryQuarantineConfig for sanity.
+func (c *TabletConfig) verifyQueryQuarantineConfig() error {
+	if !c.QueryQuarantine.Enable {
+		return nil
+	}
+	if v := c.QueryQuarantine.Interval; v <= 0 {
+		return fmt.Errorf("--query-quarantine-interval must be > 0 (specified value: %v)", v)
+	}
+	if v := c.QueryQuarantine.Windows; v <= 0 {
+		return fmt.Errorf("--query-quarantine-windows must be > 0 (specified value: %v)", v)
+	}
+	if v := c.QueryQuarantine.MinQueries; v <= 0 {
+		return fmt.Errorf("--query-quarantine-min-queries must be > 0 (specified value: %v)", v)
+	}
+	if v := c.QueryQuarantine.MaxErrorRate; v < 0 || v > 1 {
+		return fmt.Errorf("--query-quarantine-max-error-rate should be a fraction within range [0, 1] (specified value: %v)", v)
+	}
+	if v := c.QueryQuarantine.MaxP99Latency; v < 0 {
+		return fmt.Errorf("--query-quarantine-max-p99-latency must be >= 0 (specified value: %v)", v)
+	}
+	if c.QueryQuarantine.MaxErrorRate == 0 && c.QueryQuarantine.MaxP99Latency == 0 {
+		return errors.New("one of --query-quarantine-max-error-rate or --query-quarantine-max-p99-latency must be set when the query quarantine is enabled")
+	}
+	if v := c.QueryQuarantine.Cooldown; v <= 0 {
+		return fmt.Errorf("--query-quarantine-cooldown must be > 0 (specified value: %v)", v)
+	}
+	return nil
+}
+
 // Some of these values are for documentation purposes.
 // They actually get overwritten during Init.
 var defaultConfig = TabletConfig{
@@ -1066,6 +1175,19 @@ var defaultConfig = TabletConfig{
 
 	TransactionLimitConfig: defaultTransactionLimitConfig(),
 
+	PoolPriority: PoolPriorityConfig{
+		DefaultPriority: sqlparser.MaxPriorityValue / 2,
+	},
+
+	QueryQuarantine: QueryQuarantineConfig{
+		Enable:       false,
+		Interval:     time.Minute,
+		Windows:      3,
+		MinQueries:   100,
+		MaxErrorRate: 0.5,
+		Cooldown:     10 * time.Minute,
+	},
+
 	EnforceStrictTransTables: true,
 	EnableOnlineDDL:          true,
 	EnableTableGC:            true,
@@ -447,6 +447,46 @@ func TestVerifyTxThrottlerConfig(t *testing.T) {
 	}
 }
 
+func TestVerifyQueryQuarantineConfig(t *testing.T) {
+	config := defaultConfig
+	assert.NoError(t, config.verifyQueryQuarantineConfig())
+
+	config.QueryQuarantine.Enable = true
+	assert.NoError(t, config.verifyQueryQuarantineConfig())
+
+	config.QueryQuarantine.MaxErrorRate = 1.5
+	assert.EqualError(t, config.verifyQueryQuarantineConfig(), "--query-quarantine-max-error-rate should be a fraction within range [0, 1] (specified value: 1.5)")
+
+	config.QueryQuarantine.MaxErrorRate = 0
+	assert.EqualError(t, config.verifyQueryQuarantineConfig(), "one of --query-quarantine-max-error-rate or --query-quarantine-max-p99-latency must be set when the query quarantine is enabled")
+
+	config.QueryQuarantine.MaxP99Latency = time.Second
+	assert.NoError(t, config.verifyQueryQuarantineConfig())
+
+	config.QueryQuarantine.Windows = 0
+	assert.EqualError(t, config.verifyQueryQuarantineConfig(), "--query-quarantine-windows must be > 0 (specified value: 0)")
+}
+
+func TestVerifyPoolPriorityConfig(t *testing.T) {
+	config := defaultConfig
+	assert.NoError(t, config.verifyPoolPriorityConfig())
+
+	config.PoolPriority.WorkloadPriorities = map[string]int{"export": 100, "checkout": 0}
+	config.TxPool.MaxWaiters = 100
+	assert.NoError(t, config.verifyPoolPriorityConfig())
+
+	config.PoolPriority.WorkloadPriorities["report"] = 101
+	assert.EqualError(t, config.verifyPoolPriorityConfig(), "--queryserver-config-pool-workload-priorities must be between 0 and 100 (specified value for report: 101)")
+	delete(config.PoolPriority.WorkloadPriorities, "report")
+
+	config.PoolPriority.DefaultPriority = -1
+	assert.EqualError(t, config.verifyPoolPriorityConfig(), "--queryserver-config-pool-default-priority must be between 0 and 100 (specified value: -1)")
+	config.PoolPriority.DefaultPriority = 0
+
+	config.OlapReadPool.MaxWaiters = -1
+	assert.EqualError(t, config.verifyPoolPriorityConfig(), "--queryserver-config-stream-pool-max-waiters must be >= 0 (specified value: -1)")
+}
+
 func TestVerifyUnmanagedTabletConfig(t *testing.T) {
 	oldDisableActiveReparents := mysqlctl.DisableActiveReparents
 	defer func() {
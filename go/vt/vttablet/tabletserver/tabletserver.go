@@ -559,6 +559,26 @@ func (tsv *TabletServer) getPriorityFromOptions(options *querypb.ExecuteOptions)
 	return optionsPriority
 }
 
+// getPoolPriorityFromOptions returns the priority with which the request waits for a
+// connection when the pools are exhausted: the PRIORITY query directive, or else the
+// priority of its workload name, or else the default one.
+func (tsv *TabletServer) getPoolPriorityFromOptions(options *querypb.ExecuteOptions) int {
+	poolPriority := tsv.config.PoolPriority
+	if options == nil {
+		return poolPriority.DefaultPriority
+	}
+	if options.Priority != "" {
+		// The value for Priority has been validated in the vtgate already.
+		if priority, err := strconv.Atoi(options.Priority); err == nil {
+			return priority
+		}
+	}
+	if priority, ok := poolPriority.WorkloadPriorities[options.WorkloadName]; ok {
+		return priority
+	}
+	return poolPriority.DefaultPriority
+}
+
 // resolveTargetType returns the appropriate target tablet type for a
 // TabletServer request. If the caller has a local context then it's
 // an internal request and the target is the local tablet's current
@@ -1144,14 +1164,72 @@ func (tsv *TabletServer) PurgeMessages(ctx context.Context, target *querypb.Targ
 	})
 }
 
+// DeadLetterMessages dead-letters the list of messages for a given message table.
+// It returns the number of messages successfully dead-lettered.
+func (tsv *TabletServer) DeadLetterMessages(ctx context.Context, target *querypb.Target, querygen messager.QueryGenerator, ids []string) (count int64, err error) {
+	return tsv.execDMLs(ctx, target, func() ([]*querypb.BoundQuery, error) {
+		return querygen.GenerateDeadLetterQueries(ids), nil
+	})
+}
+
+// MessageInspect returns up to limit dead-lettered messages of a given message table.
+func (tsv *TabletServer) MessageInspect(ctx context.Context, target *querypb.Target, name string, limit int64) (*sqltypes.Result, error) {
+	if limit <= 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "limit must be positive: %d", limit)
+	}
+	querygen, err := tsv.messager.GetGenerator(name)
+	if err != nil {
+		return nil, err
+	}
+	query, bv, err := querygen.GenerateInspectQuery(limit)
+	if err != nil {
+		return nil, err
+	}
+	return tsv.Execute(ctx, target, query, bv, 0, 0, nil)
+}
+
+// MessageRequeue requeues the list of dead-lettered messages for a given message
+// table. The requeued messages start over with all their attempts.
+// It returns the number of messages successfully requeued.
+func (tsv *TabletServer) MessageRequeue(ctx context.Context, target *querypb.Target, name string, ids []*querypb.Value) (count int64, err error) {
+	sids := make([]string, 0, len(ids))
+	for _, val := range ids {
+		sids = append(sids, sqltypes.ProtoToValue(val).ToString())
+	}
+	querygen, err := tsv.messager.GetGenerator(name)
+	if err != nil {
+		return 0, err
+	}
+	count, err = tsv.execDMLs(ctx, target, func() ([]*querypb.BoundQuery, error) {
+		return querygen.GenerateRequeueQueries(sids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	messager.MessageStats.Add([]string{name, "Requeued"}, count)
+	return count, nil
+}
+
 func (tsv *TabletServer) execDML(ctx context.Context, target *querypb.Target, queryGenerator func() (string, map[string]*querypb.BindVariable, error)) (count int64, err error) {
+	return tsv.execDMLs(ctx, target, func() ([]*querypb.BoundQuery, error) {
+		query, bv, err := queryGenerator()
+		if err != nil {
+			return nil, err
+		}
+		return []*querypb.BoundQuery{{Sql: query, BindVariables: bv}}, nil
+	})
+}
+
+// execDMLs executes the generated queries in a single transaction, and returns
+// the number of rows affected by the last one.
+func (tsv *TabletServer) execDMLs(ctx context.Context, target *querypb.Target, queryGenerator func() ([]*querypb.BoundQuery, error)) (count int64, err error) {
 	if err = tsv.sm.StartRequest(ctx, target, false /* allowOnShutdown */); err != nil {
 		return 0, err
 	}
 	defer tsv.sm.EndRequest()
 	defer tsv.handlePanicAndSendLogStats("ack", nil, nil)
 
-	query, bv, err := queryGenerator()
+	queries, err := queryGenerator()
 	if err != nil {
 		return 0, err
 	}
@@ -1167,16 +1245,19 @@ func (tsv *TabletServer) execDML(ctx context.Context, target *querypb.Target, qu
 			tsv.Rollback(ctx, target, state.TransactionID)
 		}
 	}()
-	qr, err := tsv.Execute(ctx, target, query, bv, state.TransactionID, 0, nil)
-	if err != nil {
-		return 0, err
+	for _, query := range queries {
+		qr, err := tsv.Execute(ctx, target, query.Sql, query.BindVariables, state.TransactionID, 0, nil)
+		if err != nil {
+			return 0, err
+		}
+		count = int64(qr.RowsAffected)
 	}
 	if _, err = tsv.Commit(ctx, target, state.TransactionID); err != nil {
 		state.TransactionID = 0
 		return 0, err
 	}
 	state.TransactionID = 0
-	return int64(qr.RowsAffected), nil
+	return count, nil
 }
 
 // VStream streams VReplication events.
@@ -1553,6 +1634,7 @@ func (tsv *TabletServer) execRequest(
 		cancel()
 		tsv.sm.EndRequest()
 	}()
+	ctx = smartconnpool.NewContextWithPriority(ctx, tsv.getPoolPriorityFromOptions(options))
 
 	err = exec(ctx, logStats)
 	if err != nil {
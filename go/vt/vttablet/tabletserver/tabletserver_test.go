@@ -52,6 +52,7 @@ import (
 	"github.com/mdibaiee/vitess/go/vt/tableacl/simpleacl"
 	"github.com/mdibaiee/vitess/go/vt/topo/memorytopo"
 	"github.com/mdibaiee/vitess/go/vt/vterrors"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/messager"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/tabletenv"
 
 	querypb "github.com/mdibaiee/vitess/go/vt/proto/query"
@@ -1576,6 +1577,68 @@ func TestPurgeMessages(t *testing.T) {
 	require.EqualValues(t, 1, count)
 }
 
+type deadLetterGenerator struct {
+	messager.QueryGenerator
+	queries []*querypb.BoundQuery
+}
+
+func (gen *deadLetterGenerator) GenerateDeadLetterQueries(ids []string) []*querypb.BoundQuery {
+	return gen.queries
+}
+
+func TestDeadLetterMessages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, tsv, db := newTestTxExecutor(t, ctx)
+	defer db.Close()
+	defer tsv.StopService()
+	target := querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+
+	gen := &deadLetterGenerator{queries: []*querypb.BoundQuery{
+		{Sql: "insert into msg_dlq(id) select id from msg where id in (1, 2)"},
+		{Sql: "delete from msg where id in (1, 2)"},
+	}}
+	db.AddQueryPattern("insert into msg_dlq\\(id\\) select .*", &sqltypes.Result{RowsAffected: 1})
+	_, err := tsv.DeadLetterMessages(ctx, &target, gen, []string{"1", "2"})
+	want := "query: 'delete from msg where id in (1, 2)"
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), want)
+
+	// The count is the one of the last query.
+	db.AddQueryPattern("delete from msg where id in .*", &sqltypes.Result{RowsAffected: 2})
+	count, err := tsv.DeadLetterMessages(ctx, &target, gen, []string{"1", "2"})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+}
+
+func TestMessageInspectAndRequeue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, tsv, db := newTestTxExecutor(t, ctx)
+	defer db.Close()
+	defer tsv.StopService()
+	target := querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+
+	_, err := tsv.MessageInspect(ctx, &target, "msg", 0)
+	require.EqualError(t, err, "limit must be positive: 0")
+
+	_, err = tsv.MessageInspect(ctx, &target, "nonmsg", 10)
+	require.ErrorContains(t, err, "message table nonmsg not found in schema")
+
+	ids := []*querypb.Value{{
+		Type:  sqltypes.VarChar,
+		Value: []byte("1"),
+	}}
+	_, err = tsv.MessageRequeue(ctx, &target, "nonmsg", ids)
+	require.ErrorContains(t, err, "message table nonmsg not found in schema")
+
+	// The test message table has no retry policy.
+	_, err = tsv.MessageInspect(ctx, &target, "msg", 10)
+	require.ErrorContains(t, err, "message table msg does not dead-letter messages")
+	_, err = tsv.MessageRequeue(ctx, &target, "msg", ids)
+	require.ErrorContains(t, err, "message table msg does not dead-letter messages")
+}
+
 func TestHandleExecUnknownError(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -2801,3 +2864,14 @@ func addTabletServerSupportedQueries(db *fakesqldb.DB) {
 		}},
 	})
 }
+
+func TestGetPoolPriorityFromOptions(t *testing.T) {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.PoolPriority.WorkloadPriorities = map[string]int{"export": 100}
+	tsv := &TabletServer{config: cfg}
+
+	assert.Equal(t, 50, tsv.getPoolPriorityFromOptions(nil))
+	assert.Equal(t, 50, tsv.getPoolPriorityFromOptions(&querypb.ExecuteOptions{WorkloadName: "checkout"}))
+	assert.Equal(t, 100, tsv.getPoolPriorityFromOptions(&querypb.ExecuteOptions{WorkloadName: "export"}))
+	assert.Equal(t, 10, tsv.getPoolPriorityFromOptions(&querypb.ExecuteOptions{WorkloadName: "export", Priority: "10"}))
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetricName is the name of a metric checked by the throttler
+type MetricName string
+
+const (
+	// LagMetricName is the replication lag, in seconds, as measured by the heartbeats
+	LagMetricName MetricName = "lag"
+	// ThreadsRunningMetricName is the number of threads running in MySQL
+	ThreadsRunningMetricName MetricName = "threads_running"
+	// HistoryListLengthMetricName is the length of the InnoDB history list
+	HistoryListLengthMetricName MetricName = "history_list_length"
+	// LoadAvgMetricName is the 1 minute load average of the host, per CPU
+	LoadAvgMetricName MetricName = "loadavg"
+	// CustomMetricName is the result of the custom query of the throttler config
+	CustomMetricName MetricName = "custom"
+)
+
+// KnownMetricNames are the metrics the throttler can collect
+var KnownMetricNames = []MetricName{
+	LagMetricName,
+	ThreadsRunningMetricName,
+	HistoryListLengthMetricName,
+	LoadAvgMetricName,
+	CustomMetricName,
+}
+
+// String returns the name of the metric
+func (metricName MetricName) String() string {
+	return string(metricName)
+}
+
+// ParseMetricName returns the metric of the given name, or an error if the throttler does not
+// know about it
+func ParseMetricName(s string) (MetricName, error) {
+	for _, metricName := range KnownMetricNames {
+		if string(metricName) == s {
+			return metricName, nil
+		}
+	}
+	return "", fmt.Errorf("unknown throttler metric: %q", s)
+}
+
+// ScopedMetric is a metric checked on a given store: "self" is the tablet's own MySQL server, and "shard"
+// is the replicas of the shard as probed by the primary tablet. An empty StoreName means the metric is
+// checked on the store of the check itself, i.e. "shard" for /throttler/check and "self" for
+// /throttler/check-self.
+type ScopedMetric struct {
+	StoreName string
+	Name      MetricName
+}
+
+// String returns the metric name, prefixed by its store if any, e.g. "self/threads_running"
+func (scopedMetric ScopedMetric) String() string {
+	if scopedMetric.StoreName == "" {
+		return scopedMetric.Name.String()
+	}
+	return fmt.Sprintf("%s/%s", scopedMetric.StoreName, scopedMetric.Name)
+}
+
+// ParseScopedMetric parses a metric name optionally prefixed by its store, such as "lag" or "self/loadavg"
+func ParseScopedMetric(s string) (scopedMetric ScopedMetric, err error) {
+	name := s
+	if storeName, metricName, ok := strings.Cut(s, "/"); ok {
+		switch storeName {
+		case "self", "shard":
+		default:
+			return scopedMetric, fmt.Errorf("unknown throttler metric store %q in %q, expected 'self' or 'shard'", storeName, s)
+		}
+		scopedMetric.StoreName = storeName
+		name = metricName
+	}
+	scopedMetric.Name, err = ParseMetricName(name)
+	return scopedMetric, err
+}
+
+// ParseScopedMetrics parses a list of metric names optionally prefixed by their store
+func ParseScopedMetrics(names []string) ([]ScopedMetric, error) {
+	scopedMetrics := make([]ScopedMetric, 0, len(names))
+	for _, name := range names {
+		scopedMetric, err := ParseScopedMetric(name)
+		if err != nil {
+			return nil, err
+		}
+		scopedMetrics = append(scopedMetrics, scopedMetric)
+	}
+	return scopedMetrics, nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopedMetric(t *testing.T) {
+	tcases := []struct {
+		name   string
+		expect ScopedMetric
+		err    string
+	}{
+		{name: "lag", expect: ScopedMetric{Name: LagMetricName}},
+		{name: "self/threads_running", expect: ScopedMetric{StoreName: "self", Name: ThreadsRunningMetricName}},
+		{name: "shard/history_list_length", expect: ScopedMetric{StoreName: "shard", Name: HistoryListLengthMetricName}},
+		{name: "loadavg", expect: ScopedMetric{Name: LoadAvgMetricName}},
+		{name: "threads", err: `unknown throttler metric: "threads"`},
+		{name: "cluster/lag", err: `unknown throttler metric store "cluster" in "cluster/lag", expected 'self' or 'shard'`},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			scopedMetric, err := ParseScopedMetric(tcase.name)
+			if tcase.err != "" {
+				assert.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expect, scopedMetric)
+			assert.Equal(t, tcase.name, scopedMetric.String())
+		})
+	}
+
+	_, err := ParseScopedMetrics([]string{"lag", "self/custom", "shard/threads"})
+	assert.EqualError(t, err, `unknown throttler metric: "threads"`)
+}
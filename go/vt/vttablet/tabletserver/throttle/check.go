@@ -66,9 +66,10 @@ var (
 // CheckFlags provide hints for a check
 type CheckFlags struct {
 	ReadCheck             bool
-	OverrideThreshold     float64
+	OverrideThreshold     float64 // overrides the threshold of the default metric
 	OKIfNotExists         bool
 	SkipRequestHeartbeats bool
+	MultiMetricsEnabled   bool // checks all the collected metrics, for a primary tablet probing this tablet
 }
 
 // StandardCheckFlags have no special hints
@@ -86,11 +87,9 @@ func NewThrottlerCheck(throttler *Throttler) *ThrottlerCheck {
 	}
 }
 
-// checkAppMetricResult allows an app to check on a metric
-func (check *ThrottlerCheck) checkAppMetricResult(ctx context.Context, appName string, storeType string, storeName string, metricResultFunc base.MetricResultFunc, flags *CheckFlags) (checkResult *CheckResult) {
-	// Handle deprioritized app logic
-	denyApp := false
-	//
+// checkAppMetricResult allows an app to check on a metric. denyApp is whether the app is throttled, which is
+// decided once for all the metrics of a check.
+func (check *ThrottlerCheck) checkAppMetricResult(ctx context.Context, appName string, storeType string, storeName string, metricResultFunc base.MetricResultFunc, denyApp bool, flags *CheckFlags) (checkResult *CheckResult) {
 	metricResult, threshold := check.throttler.AppRequestMetricResult(ctx, appName, metricResultFunc, denyApp)
 	if flags.OverrideThreshold > 0 {
 		threshold = flags.OverrideThreshold
@@ -125,20 +124,54 @@ func (check *ThrottlerCheck) checkAppMetricResult(ctx context.Context, appName s
 
 // Check is the core function that runs when a user wants to check a metric
 func (check *ThrottlerCheck) Check(ctx context.Context, appName string, storeType string, storeName string, remoteAddr string, flags *CheckFlags) (checkResult *CheckResult) {
-	var metricResultFunc base.MetricResultFunc
-	switch storeType {
-	case "mysql":
-		{
-			metricResultFunc = func() (metricResult base.MetricResult, threshold float64) {
-				return check.throttler.getMySQLClusterMetrics(ctx, storeName)
-			}
+	var checkedMetrics []base.ScopedMetric
+	if flags.MultiMetricsEnabled {
+		for _, metricName := range check.throttler.collectedMetricNames() {
+			checkedMetrics = append(checkedMetrics, base.ScopedMetric{StoreName: storeName, Name: metricName})
 		}
+	} else {
+		checkedMetrics = check.throttler.checkedMetrics(appName)
 	}
-	if metricResultFunc == nil {
+	return check.checkMetrics(ctx, appName, storeType, storeName, remoteAddr, checkedMetrics, flags)
+}
+
+// checkMetrics checks the given metrics, on their store or on the given store if they have none. The result
+// is that of the first metric which is not OK, or of the first metric if they all are, except that of the
+// first metric is always the result when flags.MultiMetricsEnabled. Either way, the result has the results
+// of all the metrics.
+func (check *ThrottlerCheck) checkMetrics(ctx context.Context, appName string, storeType string, storeName string, remoteAddr string, checkedMetrics []base.ScopedMetric, flags *CheckFlags) (checkResult *CheckResult) {
+	if storeType != "mysql" || len(checkedMetrics) == 0 {
 		return NoSuchMetricCheckResult
 	}
 
-	checkResult = check.checkAppMetricResult(ctx, appName, storeType, storeName, metricResultFunc, flags)
+	defaultMetricName := check.throttler.defaultMetricName()
+	// An app throttled with a ratio is throttled with that probability per check, not per metric.
+	denyApp := check.throttler.IsAppThrottled(appName)
+	metricCheckResults := make(map[string]*CheckResult, len(checkedMetrics))
+	var worstCheckResult *CheckResult
+	for _, scopedMetric := range checkedMetrics {
+		if scopedMetric.StoreName == "" {
+			scopedMetric.StoreName = storeName
+		}
+		metricResultFunc := func() (metricResult base.MetricResult, threshold float64) {
+			return check.throttler.getMySQLStoreMetric(ctx, scopedMetric.StoreName, scopedMetric.Name)
+		}
+		metricFlags := flags
+		if scopedMetric.Name != defaultMetricName && flags.OverrideThreshold > 0 {
+			overrideFlags := *flags
+			overrideFlags.OverrideThreshold = 0
+			metricFlags = &overrideFlags
+		}
+		metricCheckResult := check.checkAppMetricResult(ctx, appName, storeType, scopedMetric.StoreName, metricResultFunc, denyApp, metricFlags)
+		metricCheckResults[scopedMetric.String()] = metricCheckResult
+		if worstCheckResult == nil || (worstCheckResult.StatusCode == http.StatusOK && !flags.MultiMetricsEnabled) {
+			worstCheckResult = metricCheckResult
+		}
+	}
+	result := *worstCheckResult
+	result.Metrics = metricCheckResults
+	checkResult = &result
+
 	check.throttler.markRecentApp(appName, remoteAddr)
 	if !throttlerapp.VitessName.Equals(appName) {
 		go func(statusCode int) {
@@ -154,42 +187,61 @@ func (check *ThrottlerCheck) Check(ctx context.Context, appName string, storeTyp
 	return checkResult
 }
 
-func (check *ThrottlerCheck) splitMetricTokens(metricName string) (storeType string, storeName string, err error) {
+// splitMetricTokens splits the name of an aggregated metric, such as "mysql/self" for the default metric of
+// the self store or "mysql/self/threads_running" for another metric, into its store type and scoped metric.
+func (check *ThrottlerCheck) splitMetricTokens(metricName string) (storeType string, scopedMetric base.ScopedMetric, err error) {
 	metricTokens := strings.Split(metricName, "/")
-	if len(metricTokens) != 2 {
-		return storeType, storeName, base.ErrNoSuchMetric
+	switch len(metricTokens) {
+	case 2:
+		scopedMetric.Name = check.throttler.defaultMetricName()
+	case 3:
+		scopedMetric.Name = base.MetricName(metricTokens[2])
+	default:
+		return storeType, scopedMetric, base.ErrNoSuchMetric
 	}
 	storeType = metricTokens[0]
-	storeName = metricTokens[1]
+	scopedMetric.StoreName = metricTokens[1]
 
-	return storeType, storeName, nil
+	return storeType, scopedMetric, nil
+}
+
+// statsName returns the name of an aggregated metric in the stats, such as "MysqlSelf" for the default
+// metric of the self store, or "MysqlSelfThreadsRunning" for another metric.
+func (check *ThrottlerCheck) statsName(storeType string, scopedMetric base.ScopedMetric) string {
+	name := textutil.SingleWordCamel(storeType) + textutil.SingleWordCamel(scopedMetric.StoreName)
+	if scopedMetric.Name != check.throttler.defaultMetricName() {
+		for _, token := range strings.Split(scopedMetric.Name.String(), "_") {
+			name += textutil.SingleWordCamel(token)
+		}
+	}
+	return name
 }
 
 // localCheck
 func (check *ThrottlerCheck) localCheck(ctx context.Context, metricName string) (checkResult *CheckResult) {
-	storeType, storeName, err := check.splitMetricTokens(metricName)
+	storeType, scopedMetric, err := check.splitMetricTokens(metricName)
 	if err != nil {
 		return NoSuchMetricCheckResult
 	}
-	checkResult = check.Check(ctx, throttlerapp.VitessName.String(), storeType, storeName, "local", StandardCheckFlags)
+	checkResult = check.checkMetrics(ctx, throttlerapp.VitessName.String(), storeType, scopedMetric.StoreName, "local", []base.ScopedMetric{scopedMetric}, StandardCheckFlags)
 
 	if checkResult.StatusCode == http.StatusOK {
 		check.throttler.markMetricHealthy(metricName)
 	}
 	if timeSinceHealthy, found := check.throttler.timeSinceMetricHealthy(metricName); found {
-		stats.GetOrNewGauge(fmt.Sprintf("ThrottlerCheck%s%sSecondsSinceHealthy", textutil.SingleWordCamel(storeType), textutil.SingleWordCamel(storeName)), fmt.Sprintf("seconds since last healthy cehck for %s.%s", storeType, storeName)).Set(int64(timeSinceHealthy.Seconds()))
+		stats.GetOrNewGauge(fmt.Sprintf("ThrottlerCheck%sSecondsSinceHealthy", check.statsName(storeType, scopedMetric)), fmt.Sprintf("seconds since last healthy cehck for %s", metricName)).Set(int64(timeSinceHealthy.Seconds()))
 	}
 
 	return checkResult
 }
 
 func (check *ThrottlerCheck) reportAggregated(metricName string, metricResult base.MetricResult) {
-	storeType, storeName, err := check.splitMetricTokens(metricName)
+	storeType, scopedMetric, err := check.splitMetricTokens(metricName)
 	if err != nil {
 		return
 	}
 	if value, err := metricResult.Get(); err == nil {
-		stats.GetOrNewGaugeFloat64(fmt.Sprintf("ThrottlerAggregated%s%s", textutil.SingleWordCamel(storeType), textutil.SingleWordCamel(storeName)), fmt.Sprintf("aggregated value for %s.%s", storeType, storeName)).Set(value)
+		stats.GetOrNewGaugeFloat64(fmt.Sprintf("ThrottlerAggregated%s", check.statsName(storeType, scopedMetric)), fmt.Sprintf("aggregated value for %s", metricName)).Set(value)
 	}
 }
 
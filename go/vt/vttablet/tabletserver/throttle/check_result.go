@@ -55,6 +55,8 @@ type CheckResult struct {
 	Error           error   `json:"-"`
 	Message         string  `json:"Message"`
 	RecentlyChecked bool    `json:"RecentlyChecked"`
+	// Metrics are the results of the checked metrics, by scoped metric name, e.g. "shard/lag"
+	Metrics map[string]*CheckResult `json:"Metrics,omitempty"`
 }
 
 // NewCheckResult returns a CheckResult
@@ -51,6 +51,7 @@ import (
 
 func aggregateMySQLProbes(
 	ctx context.Context,
+	metricName base.MetricName,
 	probes mysql.Probes,
 	clusterName string,
 	tabletResultsMap mysql.TabletResultMap,
@@ -62,7 +63,7 @@ func aggregateMySQLProbes(
 	// so it's safe to iterate it
 	probeValues := []float64{}
 	for _, probe := range probes {
-		tabletMetricResult, ok := tabletResultsMap[mysql.GetClusterTablet(clusterName, probe.Alias)]
+		tabletMetricResult, ok := tabletResultsMap[mysql.GetClusterTablet(clusterName, probe.Alias)][metricName]
 		if !ok {
 			return base.NoMetricResultYet
 		}
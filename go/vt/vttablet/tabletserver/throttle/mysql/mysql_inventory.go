@@ -56,8 +56,18 @@ func GetClusterTablet(clusterName string, alias string) ClusterTablet {
 	return ClusterTablet{ClusterName: clusterName, Alias: alias}
 }
 
-// TabletResultMap maps a cluster-tablet to a result
-type TabletResultMap map[ClusterTablet]base.MetricResult
+// TabletResultMap maps a cluster-tablet to the results of its metrics
+type TabletResultMap map[ClusterTablet]map[base.MetricName]base.MetricResult
+
+// Set sets the result of a metric of a cluster-tablet
+func (m TabletResultMap) Set(clusterTablet ClusterTablet, metricName base.MetricName, metricResult base.MetricResult) {
+	results, ok := m[clusterTablet]
+	if !ok {
+		results = make(map[base.MetricName]base.MetricResult)
+		m[clusterTablet] = results
+	}
+	results[metricName] = metricResult
+}
 
 // Inventory has the operational data about probes, their metrics, and relevant configuration
 type Inventory struct {
@@ -73,7 +83,7 @@ func NewInventory() *Inventory {
 		ClustersProbes:       make(map[string](Probes)),
 		IgnoreHostsCount:     make(map[string]int),
 		IgnoreHostsThreshold: make(map[string]float64),
-		TabletMetrics:        make(map[ClusterTablet]base.MetricResult),
+		TabletMetrics:        make(TabletResultMap),
 	}
 	return inventory
 }
@@ -49,6 +49,7 @@ import (
 	"github.com/patrickmn/go-cache"
 
 	"github.com/mdibaiee/vitess/go/stats"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/throttle/base"
 )
 
 // MetricsQueryType indicates the type of metrics query on MySQL backend. See following.
@@ -71,23 +72,25 @@ func getMySQLMetricCacheKey(probe *Probe) string {
 	return fmt.Sprintf("%s:%s", probe.Alias, probe.MetricQuery)
 }
 
-func cacheMySQLThrottleMetric(probe *Probe, mySQLThrottleMetric *MySQLThrottleMetric) *MySQLThrottleMetric {
-	if mySQLThrottleMetric.Err != nil {
-		return mySQLThrottleMetric
+func cacheMySQLThrottleMetrics(probe *Probe, mySQLThrottleMetrics MySQLThrottleMetrics) MySQLThrottleMetrics {
+	for _, metric := range mySQLThrottleMetrics {
+		if metric.Err != nil {
+			return mySQLThrottleMetrics
+		}
 	}
 	if probe.CacheMillis > 0 {
-		mysqlMetricCache.Set(getMySQLMetricCacheKey(probe), mySQLThrottleMetric, time.Duration(probe.CacheMillis)*time.Millisecond)
+		mysqlMetricCache.Set(getMySQLMetricCacheKey(probe), mySQLThrottleMetrics, time.Duration(probe.CacheMillis)*time.Millisecond)
 	}
-	return mySQLThrottleMetric
+	return mySQLThrottleMetrics
 }
 
-func getCachedMySQLThrottleMetric(probe *Probe) *MySQLThrottleMetric {
+func getCachedMySQLThrottleMetrics(probe *Probe) MySQLThrottleMetrics {
 	if probe.CacheMillis == 0 {
 		return nil
 	}
-	if metric, found := mysqlMetricCache.Get(getMySQLMetricCacheKey(probe)); found {
-		mySQLThrottleMetric, _ := metric.(*MySQLThrottleMetric)
-		return mySQLThrottleMetric
+	if metrics, found := mysqlMetricCache.Get(getMySQLMetricCacheKey(probe)); found {
+		mySQLThrottleMetrics, _ := metrics.(MySQLThrottleMetrics)
+		return mySQLThrottleMetrics
 	}
 	return nil
 }
@@ -108,12 +111,16 @@ func GetMetricsQueryType(query string) MetricsQueryType {
 
 // MySQLThrottleMetric has the probed metric for a tablet
 type MySQLThrottleMetric struct { // nolint:revive
+	Name        base.MetricName
 	ClusterName string
 	Alias       string
 	Value       float64
 	Err         error
 }
 
+// MySQLThrottleMetrics has the probed metrics for a tablet, by metric name
+type MySQLThrottleMetrics map[base.MetricName]*MySQLThrottleMetric // nolint:revive
+
 // NewMySQLThrottleMetric creates a new MySQLThrottleMetric
 func NewMySQLThrottleMetric() *MySQLThrottleMetric {
 	return &MySQLThrottleMetric{Value: 0}
@@ -129,29 +136,29 @@ func (metric *MySQLThrottleMetric) Get() (float64, error) {
 	return metric.Value, metric.Err
 }
 
-// ReadThrottleMetric returns a metric for the given probe. Either by explicit query
+// ReadThrottleMetrics returns the metrics for the given probe. Either by explicit query
 // or via SHOW REPLICA STATUS
-func ReadThrottleMetric(probe *Probe, clusterName string, overrideGetMetricFunc func() *MySQLThrottleMetric) (mySQLThrottleMetric *MySQLThrottleMetric) {
-	if mySQLThrottleMetric := getCachedMySQLThrottleMetric(probe); mySQLThrottleMetric != nil {
-		return mySQLThrottleMetric
+func ReadThrottleMetrics(probe *Probe, clusterName string, overrideGetMetricsFunc func() MySQLThrottleMetrics) (mySQLThrottleMetrics MySQLThrottleMetrics) {
+	if mySQLThrottleMetrics := getCachedMySQLThrottleMetrics(probe); mySQLThrottleMetrics != nil {
+		return mySQLThrottleMetrics
 		// On cached results we avoid taking latency metrics
 	}
 
 	started := time.Now()
-	mySQLThrottleMetric = NewMySQLThrottleMetric()
-	mySQLThrottleMetric.ClusterName = clusterName
-	mySQLThrottleMetric.Alias = probe.Alias
-
-	defer func(metric *MySQLThrottleMetric, started time.Time) {
+	defer func(started time.Time) {
+		metrics := mySQLThrottleMetrics
 		go func() {
 			stats.GetOrNewGauge("ThrottlerProbesLatency", "probes latency").Set(time.Since(started).Nanoseconds())
 			stats.GetOrNewCounter("ThrottlerProbesTotal", "total probes").Add(1)
-			if metric.Err != nil {
-				stats.GetOrNewCounter("ThrottlerProbesError", "total probes errors").Add(1)
+			for _, metric := range metrics {
+				if metric.Err != nil {
+					stats.GetOrNewCounter("ThrottlerProbesError", "total probes errors").Add(1)
+					return
+				}
 			}
 		}()
-	}(mySQLThrottleMetric, started)
+	}(started)
 
-	mySQLThrottleMetric = overrideGetMetricFunc()
-	return cacheMySQLThrottleMetric(probe, mySQLThrottleMetric)
+	mySQLThrottleMetrics = overrideGetMetricsFunc()
+	return cacheMySQLThrottleMetrics(probe, mySQLThrottleMetrics)
 }
@@ -68,48 +68,48 @@ func TestAggregateMySQLProbesNoErrors(t *testing.T) {
 	key4cluster := mysql.GetClusterTablet(clusterName, alias4)
 	key5cluster := mysql.GetClusterTablet(clusterName, alias5)
 	tabletResultsMap := mysql.TabletResultMap{
-		key1cluster: base.NewSimpleMetricResult(1.2),
-		key2cluster: base.NewSimpleMetricResult(1.7),
-		key3cluster: base.NewSimpleMetricResult(0.3),
-		key4cluster: base.NewSimpleMetricResult(0.6),
-		key5cluster: base.NewSimpleMetricResult(1.1),
+		key1cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.2)},
+		key2cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.7)},
+		key3cluster: {base.LagMetricName: base.NewSimpleMetricResult(0.3)},
+		key4cluster: {base.LagMetricName: base.NewSimpleMetricResult(0.6)},
+		key5cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.1)},
 	}
 	var probes mysql.Probes = map[string](*mysql.Probe){}
 	for clusterKey := range tabletResultsMap {
 		probes[clusterKey.Alias] = &mysql.Probe{Alias: clusterKey.Alias}
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 0, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 0, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.7)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 1, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 1, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.2)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 2, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 2, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.1)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 3, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 3, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 0.6)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 4, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 4, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 0.3)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 5, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 5, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 0.3)
@@ -125,48 +125,48 @@ func TestAggregateMySQLProbesNoErrorsIgnoreHostsThreshold(t *testing.T) {
 	key4cluster := mysql.GetClusterTablet(clusterName, alias4)
 	key5cluster := mysql.GetClusterTablet(clusterName, alias5)
 	tableteResultsMap := mysql.TabletResultMap{
-		key1cluster: base.NewSimpleMetricResult(1.2),
-		key2cluster: base.NewSimpleMetricResult(1.7),
-		key3cluster: base.NewSimpleMetricResult(0.3),
-		key4cluster: base.NewSimpleMetricResult(0.6),
-		key5cluster: base.NewSimpleMetricResult(1.1),
+		key1cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.2)},
+		key2cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.7)},
+		key3cluster: {base.LagMetricName: base.NewSimpleMetricResult(0.3)},
+		key4cluster: {base.LagMetricName: base.NewSimpleMetricResult(0.6)},
+		key5cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.1)},
 	}
 	var probes mysql.Probes = map[string](*mysql.Probe){}
 	for clusterKey := range tableteResultsMap {
 		probes[clusterKey.Alias] = &mysql.Probe{Alias: clusterKey.Alias}
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tableteResultsMap, 0, false, 1.0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tableteResultsMap, 0, false, 1.0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.7)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tableteResultsMap, 1, false, 1.0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tableteResultsMap, 1, false, 1.0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.2)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tableteResultsMap, 2, false, 1.0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tableteResultsMap, 2, false, 1.0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.1)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tableteResultsMap, 3, false, 1.0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tableteResultsMap, 3, false, 1.0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 0.6)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tableteResultsMap, 4, false, 1.0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tableteResultsMap, 4, false, 1.0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 0.6)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tableteResultsMap, 5, false, 1.0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tableteResultsMap, 5, false, 1.0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 0.6)
@@ -182,50 +182,50 @@ func TestAggregateMySQLProbesWithErrors(t *testing.T) {
 	key4cluster := mysql.GetClusterTablet(clusterName, alias4)
 	key5cluster := mysql.GetClusterTablet(clusterName, alias5)
 	tabletResultsMap := mysql.TabletResultMap{
-		key1cluster: base.NewSimpleMetricResult(1.2),
-		key2cluster: base.NewSimpleMetricResult(1.7),
-		key3cluster: base.NewSimpleMetricResult(0.3),
-		key4cluster: base.NoSuchMetric,
-		key5cluster: base.NewSimpleMetricResult(1.1),
+		key1cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.2)},
+		key2cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.7)},
+		key3cluster: {base.LagMetricName: base.NewSimpleMetricResult(0.3)},
+		key4cluster: {base.LagMetricName: base.NoSuchMetric},
+		key5cluster: {base.LagMetricName: base.NewSimpleMetricResult(1.1)},
 	}
 	var probes mysql.Probes = map[string](*mysql.Probe){}
 	for clusterKey := range tabletResultsMap {
 		probes[clusterKey.Alias] = &mysql.Probe{Alias: clusterKey.Alias}
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 0, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 0, false, 0)
 		_, err := worstMetric.Get()
 		assert.Error(t, err)
 		assert.Equal(t, err, base.ErrNoSuchMetric)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 1, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 1, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.7)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 2, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 2, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.2)
 	}
 
-	tabletResultsMap[key1cluster] = base.NoSuchMetric
+	tabletResultsMap.Set(key1cluster, base.LagMetricName, base.NoSuchMetric)
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 0, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 0, false, 0)
 		_, err := worstMetric.Get()
 		assert.Error(t, err)
 		assert.Equal(t, err, base.ErrNoSuchMetric)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 1, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 1, false, 0)
 		_, err := worstMetric.Get()
 		assert.Error(t, err)
 		assert.Equal(t, err, base.ErrNoSuchMetric)
 	}
 	{
-		worstMetric := aggregateMySQLProbes(ctx, probes, clusterName, tabletResultsMap, 2, false, 0)
+		worstMetric := aggregateMySQLProbes(ctx, base.LagMetricName, probes, clusterName, tabletResultsMap, 2, false, 0)
 		value, err := worstMetric.Get()
 		assert.NoError(t, err)
 		assert.Equal(t, value, 1.7)
@@ -48,6 +48,8 @@ import (
 	"math"
 	"math/rand/v2"
 	"net/http"
+	"os"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -101,6 +103,9 @@ const (
 	selfStoreName  = "self"
 
 	defaultReplicationLagQuery = "select unix_timestamp(now(6))-max(ts/1000000000) as replication_lag from %s.heartbeat"
+	threadsRunningQuery        = "show global status like 'threads_running'"
+	historyListLengthQuery     = "select count as history_len from information_schema.INNODB_METRICS where name = 'trx_rseg_history_len'"
+	loadAvgPath                = "/proc/loadavg"
 )
 
 var (
@@ -109,6 +114,14 @@ var (
 	throttleTabletTypes         = "replica"
 )
 
+// defaultMetricThresholds are the thresholds of the metrics other than the replication lag which are
+// assigned to an app, unless the throttler config sets them.
+var defaultMetricThresholds = map[base.MetricName]float64{
+	base.ThreadsRunningMetricName:    100,
+	base.HistoryListLengthMetricName: 1000000,
+	base.LoadAvgMetricName:           1,
+}
+
 var (
 	statsThrottlerHeartbeatRequests    = stats.NewCounter("ThrottlerHeartbeatRequests", "heartbeat requests")
 	statsThrottlerRecentlyChecked      = stats.NewCounter("ThrottlerRecentlyChecked", "recently checked")
@@ -181,7 +194,7 @@ type Throttler struct {
 
 	throttleTabletTypesMap map[topodatapb.TabletType]bool
 
-	mysqlThrottleMetricChan chan *mysql.MySQLThrottleMetric
+	mysqlThrottleMetricChan chan mysql.MySQLThrottleMetrics
 	mysqlInventoryChan      chan *mysql.Inventory
 	mysqlClusterProbesChan  chan *mysql.ClusterProbes
 	throttlerConfigChan     chan *topodatapb.ThrottlerConfig
@@ -191,6 +204,15 @@ type Throttler struct {
 	metricsQuery     atomic.Value
 	MetricsThreshold atomic.Uint64
 	checkAsCheckSelf atomic.Bool
+	// customMetric is true when the metrics query is the custom query of the throttler config, in which
+	// case the custom metric is the default metric instead of the replication lag.
+	customMetric atomic.Bool
+	// metricThresholds holds the configured thresholds of the metrics other than the default metric, as a
+	// map[base.MetricName]float64.
+	metricThresholds atomic.Value
+	// appCheckedMetrics holds the metrics checked by the apps which do not just check the default
+	// metric, as a map[string][]base.ScopedMetric.
+	appCheckedMetrics atomic.Value
 
 	mysqlClusterThresholds *cache.Cache
 	aggregatedMetrics      *cache.Cache
@@ -204,7 +226,7 @@ type Throttler struct {
 	cancelEnableContext context.CancelFunc
 	throttledAppsMutex  sync.Mutex
 
-	readSelfThrottleMetric func(context.Context, *mysql.Probe) *mysql.MySQLThrottleMetric // overwritten by unit test
+	readSelfThrottleMetrics func(context.Context, *mysql.Probe) mysql.MySQLThrottleMetrics // overwritten by unit test
 
 	httpClient *http.Client
 }
@@ -223,6 +245,9 @@ type ThrottlerStatus struct {
 	Query     string
 	Threshold float64
 
+	MetricThresholds  map[base.MetricName]float64
+	AppCheckedMetrics map[string]string
+
 	AggregatedMetrics map[string]base.MetricResult
 	MetricsHealth     base.MetricHealthMap
 }
@@ -242,7 +267,7 @@ func NewThrottler(env tabletenv.Env, srvTopoServer srvtopo.Server, ts *topo.Serv
 		}),
 	}
 
-	throttler.mysqlThrottleMetricChan = make(chan *mysql.MySQLThrottleMetric)
+	throttler.mysqlThrottleMetricChan = make(chan mysql.MySQLThrottleMetrics)
 	throttler.mysqlInventoryChan = make(chan *mysql.Inventory, 1)
 	throttler.mysqlClusterProbesChan = make(chan *mysql.ClusterProbes)
 	throttler.throttlerConfigChan = make(chan *topodatapb.ThrottlerConfig)
@@ -271,9 +296,9 @@ func NewThrottler(env tabletenv.Env, srvTopoServer srvtopo.Server, ts *topo.Serv
 		throttler.recentCheckDiff = 1
 	}
 
-	throttler.StoreMetricsThreshold(defaultThrottleLagThreshold.Seconds()) //default
-	throttler.readSelfThrottleMetric = func(ctx context.Context, p *mysql.Probe) *mysql.MySQLThrottleMetric {
-		return throttler.readSelfMySQLThrottleMetric(ctx, p)
+	throttler.storeMetricThresholds(defaultThrottleLagThreshold.Seconds(), nil) //default
+	throttler.readSelfThrottleMetrics = func(ctx context.Context, p *mysql.Probe) mysql.MySQLThrottleMetrics {
+		return throttler.readSelfMySQLThrottleMetrics(ctx, p)
 	}
 
 	return throttler
@@ -313,6 +338,141 @@ func (throttler *Throttler) GetMetricsThreshold() float64 {
 	return math.Float64frombits(throttler.MetricsThreshold.Load())
 }
 
+// defaultMetricName returns the metric checked by the apps which do not choose their metrics: the custom
+// metric if the throttler config has a custom query, and the replication lag otherwise.
+func (throttler *Throttler) defaultMetricName() base.MetricName {
+	if throttler.customMetric.Load() {
+		return base.CustomMetricName
+	}
+	return base.LagMetricName
+}
+
+// GetMetricThresholds returns the thresholds of the metrics other than the default metric: those set by
+// the throttler config, and the default thresholds of the other metrics which are assigned to an app.
+func (throttler *Throttler) GetMetricThresholds() map[base.MetricName]float64 {
+	configuredThresholds, _ := throttler.metricThresholds.Load().(map[base.MetricName]float64)
+	appCheckedMetrics, _ := throttler.appCheckedMetrics.Load().(map[string][]base.ScopedMetric)
+	defaultMetricName := throttler.defaultMetricName()
+	thresholds := make(map[base.MetricName]float64, len(configuredThresholds))
+	for metricName, threshold := range configuredThresholds {
+		thresholds[metricName] = threshold
+	}
+	for _, scopedMetrics := range appCheckedMetrics {
+		for _, scopedMetric := range scopedMetrics {
+			if _, ok := thresholds[scopedMetric.Name]; ok || scopedMetric.Name == defaultMetricName {
+				continue
+			}
+			if threshold, ok := defaultMetricThreshold(scopedMetric.Name); ok {
+				thresholds[scopedMetric.Name] = threshold
+			}
+		}
+	}
+	return thresholds
+}
+
+// defaultMetricThreshold returns the threshold of a metric which is not set by the throttler config. The
+// custom metric has no default threshold.
+func defaultMetricThreshold(metricName base.MetricName) (float64, bool) {
+	if metricName == base.LagMetricName {
+		return defaultThrottleLagThreshold.Seconds(), true
+	}
+	threshold, ok := defaultMetricThresholds[metricName]
+	return threshold, ok
+}
+
+// storeMetricThresholds stores the threshold of the default metric, and the thresholds of the other
+// metrics as configured. A configured threshold of the default metric overrides the given threshold.
+func (throttler *Throttler) storeMetricThresholds(threshold float64, configuredThresholds map[string]float64) {
+	thresholds := make(map[base.MetricName]float64, len(configuredThresholds))
+	for name, metricThreshold := range configuredThresholds {
+		metricName, err := base.ParseMetricName(name)
+		if err != nil {
+			log.Errorf("Throttler: ignoring threshold of metric: %v", err)
+			continue
+		}
+		thresholds[metricName] = metricThreshold
+	}
+	defaultMetricName := throttler.defaultMetricName()
+	if metricThreshold, ok := configuredThresholds[defaultMetricName.String()]; ok {
+		threshold = metricThreshold
+	}
+	delete(thresholds, defaultMetricName)
+	throttler.StoreMetricsThreshold(threshold)
+	throttler.metricThresholds.Store(thresholds)
+}
+
+// storeAppCheckedMetrics stores the metrics checked by the apps, as configured in the throttler config
+func (throttler *Throttler) storeAppCheckedMetrics(configuredMetrics map[string]*topodatapb.ThrottlerConfig_MetricNames) {
+	appCheckedMetrics := make(map[string][]base.ScopedMetric, len(configuredMetrics))
+	for appName, metricNames := range configuredMetrics {
+		scopedMetrics, err := base.ParseScopedMetrics(metricNames.GetNames())
+		if err != nil {
+			log.Errorf("Throttler: ignoring checked metrics of app %s: %v", appName, err)
+			continue
+		}
+		if len(scopedMetrics) > 0 {
+			appCheckedMetrics[appName] = scopedMetrics
+		}
+	}
+	throttler.appCheckedMetrics.Store(appCheckedMetrics)
+}
+
+// checkedMetrics returns the metrics checked by the given app: the metrics assigned to the app, or to one
+// of the app names it is a concatenation of, and otherwise the default metric.
+func (throttler *Throttler) checkedMetrics(appName string) []base.ScopedMetric {
+	appCheckedMetrics, _ := throttler.appCheckedMetrics.Load().(map[string][]base.ScopedMetric)
+	if scopedMetrics, ok := appCheckedMetrics[appName]; ok {
+		return scopedMetrics
+	}
+	for _, singleAppName := range strings.Split(appName, ":") {
+		if scopedMetrics, ok := appCheckedMetrics[singleAppName]; ok {
+			return scopedMetrics
+		}
+	}
+	return []base.ScopedMetric{{Name: throttler.defaultMetricName()}}
+}
+
+// collectedMetricNames returns the metrics collected by the throttler: the default metric first, then the
+// metrics which have a configured threshold or are assigned to an app. The custom metric is only collected as the default metric, since it
+// needs the custom query of the throttler config.
+func (throttler *Throttler) collectedMetricNames() []base.MetricName {
+	defaultMetricName := throttler.defaultMetricName()
+	thresholds := throttler.GetMetricThresholds()
+	metricNames := []base.MetricName{defaultMetricName}
+	for _, metricName := range base.KnownMetricNames {
+		if metricName == defaultMetricName || metricName == base.CustomMetricName {
+			continue
+		}
+		if _, ok := thresholds[metricName]; ok {
+			metricNames = append(metricNames, metricName)
+		}
+	}
+	return metricNames
+}
+
+// metricQuery returns the query reading the given metric other than the default metric, or an empty
+// string for the metrics which are not read from MySQL.
+func (throttler *Throttler) metricQuery(metricName base.MetricName) string {
+	switch metricName {
+	case base.LagMetricName:
+		return sqlparser.BuildParsedQuery(defaultReplicationLagQuery, sidecar.GetIdentifier()).Query
+	case base.ThreadsRunningMetricName:
+		return threadsRunningQuery
+	case base.HistoryListLengthMetricName:
+		return historyListLengthQuery
+	}
+	return ""
+}
+
+// aggregatedMetricName returns the name of the aggregated value of a metric in a MySQL cluster: "mysql/self"
+// or "mysql/shard" for the default metric, and e.g. "mysql/self/threads_running" for the other metrics.
+func (throttler *Throttler) aggregatedMetricName(clusterName string, metricName base.MetricName) string {
+	if metricName == throttler.defaultMetricName() {
+		return fmt.Sprintf("mysql/%s", clusterName)
+	}
+	return fmt.Sprintf("mysql/%s/%s", clusterName, metricName)
+}
+
 // initThrottler initializes config
 func (throttler *Throttler) initConfig() {
 	log.Infof("Throttler: initializing config")
@@ -395,10 +555,13 @@ func (throttler *Throttler) applyThrottlerConfig(ctx context.Context, throttlerC
 	log.Infof("Throttler: applying topo config: %+v", throttlerConfig)
 	if throttlerConfig.CustomQuery == "" {
 		throttler.metricsQuery.Store(sqlparser.BuildParsedQuery(defaultReplicationLagQuery, sidecar.GetIdentifier()).Query)
+		throttler.customMetric.Store(false)
 	} else {
 		throttler.metricsQuery.Store(throttlerConfig.CustomQuery)
+		throttler.customMetric.Store(true)
 	}
-	throttler.StoreMetricsThreshold(throttlerConfig.Threshold)
+	throttler.storeMetricThresholds(throttlerConfig.Threshold, throttlerConfig.MetricThresholds)
+	throttler.storeAppCheckedMetrics(throttlerConfig.AppCheckedMetrics)
 	throttler.checkAsCheckSelf.Store(throttlerConfig.CheckAsCheckSelf)
 	for _, appRule := range throttlerConfig.ThrottledApps {
 		throttler.ThrottleApp(appRule.Name, protoutil.TimeFromProto(appRule.ExpiresAt).UTC(), appRule.Ratio, appRule.Exempt)
@@ -617,54 +780,94 @@ func (throttler *Throttler) stimulatePrimaryThrottler(ctx context.Context, tmCli
 	return nil
 }
 
-func (throttler *Throttler) generateSelfMySQLThrottleMetricFunc(ctx context.Context, probe *mysql.Probe) func() *mysql.MySQLThrottleMetric {
-	f := func() *mysql.MySQLThrottleMetric {
-		return throttler.readSelfThrottleMetric(ctx, probe)
+func (throttler *Throttler) generateSelfMySQLThrottleMetricFunc(ctx context.Context, probe *mysql.Probe) func() mysql.MySQLThrottleMetrics {
+	f := func() mysql.MySQLThrottleMetrics {
+		return throttler.readSelfThrottleMetrics(ctx, probe)
 	}
 	return f
 }
 
-// readSelfMySQLThrottleMetric reads the mysql metric from thi very tablet's backend mysql.
-func (throttler *Throttler) readSelfMySQLThrottleMetric(ctx context.Context, probe *mysql.Probe) *mysql.MySQLThrottleMetric {
-	metric := &mysql.MySQLThrottleMetric{
-		ClusterName: selfStoreName,
-		Alias:       "",
-		Value:       0,
-		Err:         nil,
+// readSelfMySQLThrottleMetrics reads the collected metrics from this very tablet's backend mysql.
+func (throttler *Throttler) readSelfMySQLThrottleMetrics(ctx context.Context, probe *mysql.Probe) mysql.MySQLThrottleMetrics {
+	metrics := make(mysql.MySQLThrottleMetrics)
+	newMetric := func(metricName base.MetricName) *mysql.MySQLThrottleMetric {
+		metric := &mysql.MySQLThrottleMetric{
+			Name:        metricName,
+			ClusterName: selfStoreName,
+			Alias:       "",
+			Value:       0,
+			Err:         nil,
+		}
+		metrics[metricName] = metric
+		return metric
 	}
+	metricNames := throttler.collectedMetricNames()
+
 	conn, err := throttler.pool.Get(ctx, nil)
 	if err != nil {
-		metric.Err = err
-		return metric
+		for _, metricName := range metricNames {
+			newMetric(metricName).Err = err
+		}
+		return metrics
 	}
 	defer conn.Recycle()
 
-	tm, err := conn.Conn.Exec(ctx, probe.MetricQuery, 1, true)
+	for i, metricName := range metricNames {
+		metric := newMetric(metricName)
+		if metricName == base.LoadAvgMetricName {
+			metric.Value, metric.Err = readLoadAvg()
+			continue
+		}
+		// The default metric is read by the query of the probe.
+		query := probe.MetricQuery
+		if i > 0 {
+			query = throttler.metricQuery(metricName)
+		}
+		metric.Value, metric.Err = readSelfMySQLThrottleMetric(ctx, conn.Conn, query)
+	}
+	return metrics
+}
+
+// readSelfMySQLThrottleMetric reads a metric from this very tablet's backend mysql.
+func readSelfMySQLThrottleMetric(ctx context.Context, conn *connpool.Conn, query string) (float64, error) {
+	tm, err := conn.Exec(ctx, query, 1, true)
 	if err != nil {
-		metric.Err = err
-		return metric
+		return 0, err
 	}
 	row := tm.Named().Row()
 	if row == nil {
-		metric.Err = fmt.Errorf("no results for readSelfMySQLThrottleMetric")
-		return metric
+		return 0, fmt.Errorf("no results for readSelfMySQLThrottleMetric")
 	}
 
-	metricsQueryType := mysql.GetMetricsQueryType(throttler.GetMetricsQuery())
+	metricsQueryType := mysql.GetMetricsQueryType(query)
 	switch metricsQueryType {
 	case mysql.MetricsQueryTypeSelect:
 		// We expect a single row, single column result.
 		// The "for" iteration below is just a way to get first result without knowing column name
 		for k := range row {
-			metric.Value, metric.Err = row.ToFloat64(k)
+			return row.ToFloat64(k)
 		}
 	case mysql.MetricsQueryTypeShowGlobal:
-		metric.Value, metric.Err = strconv.ParseFloat(row["Value"].ToString(), 64)
-	default:
-		metric.Err = fmt.Errorf("Unsupported metrics query type for query: %s", throttler.GetMetricsQuery())
+		return strconv.ParseFloat(row["Value"].ToString(), 64)
 	}
+	return 0, fmt.Errorf("Unsupported metrics query type for query: %s", query)
+}
 
-	return metric
+// readLoadAvg reads the 1 minute load average of the host, and divides it by the number of CPUs.
+func readLoadAvg() (float64, error) {
+	content, err := os.ReadFile(loadAvgPath)
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected content in %s: %q", loadAvgPath, content)
+	}
+	loadAvg, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	return loadAvg / float64(runtime.NumCPU()), nil
 }
 
 // throttledAppsSnapshot returns a snapshot (a copy) of current throttled apps
@@ -815,9 +1018,11 @@ func (throttler *Throttler) Operate(ctx context.Context, wg *sync.WaitGroup) {
 						})
 					}
 				}
-			case metric := <-throttler.mysqlThrottleMetricChan:
-				// incoming MySQL metric, frequent, as result of collectMySQLMetrics()
-				throttler.mysqlInventory.TabletMetrics[metric.GetClusterTablet()] = metric
+			case metrics := <-throttler.mysqlThrottleMetricChan:
+				// incoming MySQL metrics, frequent, as result of collectMySQLMetrics()
+				for _, metric := range metrics {
+					throttler.mysqlInventory.TabletMetrics.Set(metric.GetClusterTablet(), metric.Name, metric)
+				}
 			case <-mysqlRefreshTicker.C:
 				// sparse
 				if throttler.IsOpen() {
@@ -841,38 +1046,59 @@ func (throttler *Throttler) Operate(ctx context.Context, wg *sync.WaitGroup) {
 	}()
 }
 
-func (throttler *Throttler) generateTabletProbeFunction(ctx context.Context, clusterName string, tmClient tmclient.TabletManagerClient, probe *mysql.Probe) (probeFunc func() *mysql.MySQLThrottleMetric) {
-	return func() *mysql.MySQLThrottleMetric {
+func (throttler *Throttler) generateTabletProbeFunction(ctx context.Context, clusterName string, tmClient tmclient.TabletManagerClient, probe *mysql.Probe) (probeFunc func() mysql.MySQLThrottleMetrics) {
+	return func() mysql.MySQLThrottleMetrics {
 		// Some reasonable timeout, to ensure we release connections even if they're hanging (otherwise grpc-go keeps polling those connections forever)
 		ctx, cancel := context.WithTimeout(ctx, 4*mysqlCollectInterval)
 		defer cancel()
 
-		// Hit a tablet's `check-self` via HTTP, and convert its CheckResult JSON output into a MySQLThrottleMetric
+		// Hit a tablet's `check-self` via gRPC, and convert its CheckThrottlerResponse into MySQLThrottleMetrics
+		defaultMetricName := throttler.defaultMetricName()
 		mySQLThrottleMetric := mysql.NewMySQLThrottleMetric()
+		mySQLThrottleMetric.Name = defaultMetricName
 		mySQLThrottleMetric.ClusterName = clusterName
 		mySQLThrottleMetric.Alias = probe.Alias
+		metrics := mysql.MySQLThrottleMetrics{defaultMetricName: mySQLThrottleMetric}
 
 		if probe.Tablet == nil {
-			mySQLThrottleMetric.Err = fmt.Errorf("found nil tablet reference for alias %v, hostname %v", probe.Alias, probe.Tablet.Hostname)
-			return mySQLThrottleMetric
+			mySQLThrottleMetric.Err = fmt.Errorf("found nil tablet reference for alias %v", probe.Alias)
+			return metrics
 		}
-		req := &tabletmanagerdatapb.CheckThrottlerRequest{} // We leave AppName empty; it will default to VitessName anyway, and we can save some proto space
+		// We leave AppName empty; it will default to VitessName anyway, and we can save some proto space.
+		// The tablet reports the values of all its metrics.
+		req := &tabletmanagerdatapb.CheckThrottlerRequest{MultiMetricsEnabled: true}
 		resp, gRPCErr := tmClient.CheckThrottler(ctx, probe.Tablet, req)
 		if gRPCErr != nil {
 			mySQLThrottleMetric.Err = fmt.Errorf("gRPC error accessing tablet %v. Err=%v", probe.Alias, gRPCErr)
-			return mySQLThrottleMetric
+			return metrics
 		}
 		mySQLThrottleMetric.Value = resp.Value
 		if resp.StatusCode == http.StatusInternalServerError {
 			mySQLThrottleMetric.Err = fmt.Errorf("Status code: %d", resp.StatusCode)
 		}
+		for _, respMetric := range resp.Metrics {
+			metricName := base.MetricName(respMetric.Name)
+			if metricName == defaultMetricName || respMetric.StatusCode == http.StatusNotFound {
+				continue
+			}
+			metric := &mysql.MySQLThrottleMetric{
+				Name:        metricName,
+				ClusterName: clusterName,
+				Alias:       probe.Alias,
+				Value:       respMetric.Value,
+			}
+			if respMetric.StatusCode == http.StatusInternalServerError {
+				metric.Err = fmt.Errorf("Status code: %d, error: %s", respMetric.StatusCode, respMetric.Error)
+			}
+			metrics[metricName] = metric
+		}
 		if resp.RecentlyChecked {
 			// We have just probed a tablet, and it reported back that someone just recently "check"ed it.
 			// We therefore renew the heartbeats lease.
 			throttler.requestHeartbeats()
 			statsThrottlerProbeRecentlyChecked.Add(1)
 		}
-		return mySQLThrottleMetric
+		return metrics
 	}
 }
 
@@ -894,15 +1120,15 @@ func (throttler *Throttler) collectMySQLMetrics(ctx context.Context, tmClient tm
 				}
 				defer atomic.StoreInt64(&probe.QueryInProgress, 0)
 
-				var throttleMetricFunc func() *mysql.MySQLThrottleMetric
+				var throttleMetricsFunc func() mysql.MySQLThrottleMetrics
 				if clusterName == selfStoreName {
 					// Throttler is probing its own tablet's metrics:
-					throttleMetricFunc = throttler.generateSelfMySQLThrottleMetricFunc(ctx, probe)
+					throttleMetricsFunc = throttler.generateSelfMySQLThrottleMetricFunc(ctx, probe)
 				} else {
 					// Throttler probing other tablets:
-					throttleMetricFunc = throttler.generateTabletProbeFunction(ctx, clusterName, tmClient, probe)
+					throttleMetricsFunc = throttler.generateTabletProbeFunction(ctx, clusterName, tmClient, probe)
 				}
-				throttleMetrics := mysql.ReadThrottleMetric(probe, clusterName, throttleMetricFunc)
+				throttleMetrics := mysql.ReadThrottleMetrics(probe, clusterName, throttleMetricsFunc)
 				select {
 				case <-ctx.Done():
 					return
@@ -1026,12 +1252,14 @@ func (throttler *Throttler) updateMySQLClusterProbes(ctx context.Context, cluste
 
 // synchronous aggregation of collected data
 func (throttler *Throttler) aggregateMySQLMetrics(ctx context.Context) error {
+	metricNames := throttler.collectedMetricNames()
 	for clusterName, probes := range throttler.mysqlInventory.ClustersProbes {
-		metricName := fmt.Sprintf("mysql/%s", clusterName)
 		ignoreHostsCount := throttler.mysqlInventory.IgnoreHostsCount[clusterName]
 		ignoreHostsThreshold := throttler.mysqlInventory.IgnoreHostsThreshold[clusterName]
-		aggregatedMetric := aggregateMySQLProbes(ctx, probes, clusterName, throttler.mysqlInventory.TabletMetrics, ignoreHostsCount, throttler.configSettings.Stores.MySQL.IgnoreDialTCPErrors, ignoreHostsThreshold)
-		throttler.aggregatedMetrics.Set(metricName, aggregatedMetric, cache.DefaultExpiration)
+		for _, metricName := range metricNames {
+			aggregatedMetric := aggregateMySQLProbes(ctx, metricName, probes, clusterName, throttler.mysqlInventory.TabletMetrics, ignoreHostsCount, throttler.configSettings.Stores.MySQL.IgnoreDialTCPErrors, ignoreHostsThreshold)
+			throttler.aggregatedMetrics.Set(throttler.aggregatedMetricName(clusterName, metricName), aggregatedMetric, cache.DefaultExpiration)
+		}
 	}
 	return nil
 }
@@ -1053,6 +1281,18 @@ func (throttler *Throttler) getMySQLClusterMetrics(ctx context.Context, clusterN
 	return base.NoSuchMetric, 0
 }
 
+// getMySQLStoreMetric returns the aggregated value of a metric in a MySQL store, and its threshold
+func (throttler *Throttler) getMySQLStoreMetric(ctx context.Context, storeName string, metricName base.MetricName) (base.MetricResult, float64) {
+	if metricName == throttler.defaultMetricName() {
+		return throttler.getMySQLClusterMetrics(ctx, storeName)
+	}
+	threshold, ok := throttler.GetMetricThresholds()[metricName]
+	if !ok {
+		return base.NoSuchMetric, 0
+	}
+	return throttler.getNamedMetric(throttler.aggregatedMetricName(storeName, metricName)), threshold
+}
+
 func (throttler *Throttler) aggregatedMetricsSnapshot() map[string]base.MetricResult {
 	snapshot := make(map[string]base.MetricResult)
 	for key, value := range throttler.aggregatedMetrics.Items() {
@@ -1233,14 +1473,12 @@ func (throttler *Throttler) metricsHealthSnapshot() base.MetricHealthMap {
 	return snapshot
 }
 
-// AppRequestMetricResult gets a metric result in the context of a specific app
+// AppRequestMetricResult gets a metric result in the context of a specific app. denyApp is whether the app
+// is throttled, see IsAppThrottled.
 func (throttler *Throttler) AppRequestMetricResult(ctx context.Context, appName string, metricResultFunc base.MetricResultFunc, denyApp bool) (metricResult base.MetricResult, threshold float64) {
 	if denyApp {
 		return base.AppDeniedMetric, 0
 	}
-	if throttler.IsAppThrottled(appName) {
-		return base.AppDeniedMetric, 0
-	}
 	return metricResultFunc()
 }
 
@@ -1308,6 +1546,32 @@ func (throttler *Throttler) CheckByType(ctx context.Context, appName string, rem
 	}
 }
 
+// metricThresholdsSnapshot returns the thresholds of all the metrics, including the default metric
+func (throttler *Throttler) metricThresholdsSnapshot() map[base.MetricName]float64 {
+	snapshot := map[base.MetricName]float64{
+		throttler.defaultMetricName(): throttler.GetMetricsThreshold(),
+	}
+	for metricName, threshold := range throttler.GetMetricThresholds() {
+		snapshot[metricName] = threshold
+	}
+	return snapshot
+}
+
+// appCheckedMetricsSnapshot returns the comma separated metrics checked by the apps which do not just
+// check the default metric
+func (throttler *Throttler) appCheckedMetricsSnapshot() map[string]string {
+	appCheckedMetrics, _ := throttler.appCheckedMetrics.Load().(map[string][]base.ScopedMetric)
+	snapshot := make(map[string]string, len(appCheckedMetrics))
+	for appName, scopedMetrics := range appCheckedMetrics {
+		names := make([]string, 0, len(scopedMetrics))
+		for _, scopedMetric := range scopedMetrics {
+			names = append(names, scopedMetric.String())
+		}
+		snapshot[appName] = strings.Join(names, ",")
+	}
+	return snapshot
+}
+
 // Status exports a status breakdown
 func (throttler *Throttler) Status() *ThrottlerStatus {
 	return &ThrottlerStatus{
@@ -1323,6 +1587,9 @@ func (throttler *Throttler) Status() *ThrottlerStatus {
 		Query:     throttler.GetMetricsQuery(),
 		Threshold: throttler.GetMetricsThreshold(),
 
+		MetricThresholds:  throttler.metricThresholdsSnapshot(),
+		AppCheckedMetrics: throttler.appCheckedMetricsSnapshot(),
+
 		AggregatedMetrics: throttler.aggregatedMetricsSnapshot(),
 		MetricsHealth:     throttler.metricsHealthSnapshot(),
 	}
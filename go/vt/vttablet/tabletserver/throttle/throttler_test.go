@@ -19,6 +19,7 @@ package throttle
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -33,6 +34,7 @@ import (
 	"github.com/mdibaiee/vitess/go/vt/vtenv"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/connpool"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/tabletenv"
+	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/throttle/base"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/throttle/config"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/throttle/mysql"
 	"github.com/mdibaiee/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
@@ -63,6 +65,17 @@ func (c *fakeTMClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Ta
 		Threshold:       1,
 		RecentlyChecked: false,
 	}
+	if request.MultiMetricsEnabled {
+		resp.Metrics = map[string]*tabletmanagerdatapb.CheckThrottlerResponse_Metric{
+			"self/threads_running": {
+				Name:       base.ThreadsRunningMetricName.String(),
+				StatusCode: http.StatusOK,
+				Value:      3,
+				Threshold:  100,
+				Scope:      selfStoreName,
+			},
+		}
+	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.appNames = append(c.appNames, request.AppName)
@@ -146,7 +159,7 @@ func newTestThrottler() *Throttler {
 		overrideTmClient:       &fakeTMClient{},
 	}
 	throttler.configSettings = configSettings
-	throttler.mysqlThrottleMetricChan = make(chan *mysql.MySQLThrottleMetric)
+	throttler.mysqlThrottleMetricChan = make(chan mysql.MySQLThrottleMetrics)
 	throttler.mysqlInventoryChan = make(chan *mysql.Inventory, 1)
 	throttler.mysqlClusterProbesChan = make(chan *mysql.ClusterProbes)
 	throttler.throttlerConfigChan = make(chan *topodatapb.ThrottlerConfig)
@@ -172,12 +185,15 @@ func newTestThrottler() *Throttler {
 	throttler.recentCheckDormantDiff = int64(throttler.dormantPeriod / recentCheckRateLimiterInterval)
 	throttler.recentCheckDiff = int64(3 * time.Second / recentCheckRateLimiterInterval)
 
-	throttler.readSelfThrottleMetric = func(ctx context.Context, p *mysql.Probe) *mysql.MySQLThrottleMetric {
-		return &mysql.MySQLThrottleMetric{
-			ClusterName: selfStoreName,
-			Alias:       "",
-			Value:       1,
-			Err:         nil,
+	throttler.readSelfThrottleMetrics = func(ctx context.Context, p *mysql.Probe) mysql.MySQLThrottleMetrics {
+		return mysql.MySQLThrottleMetrics{
+			base.LagMetricName: {
+				Name:        base.LagMetricName,
+				ClusterName: selfStoreName,
+				Alias:       "",
+				Value:       1,
+				Err:         nil,
+			},
 		}
 	}
 
@@ -607,3 +623,160 @@ func TestReplica(t *testing.T) {
 		}()
 	})
 }
+
+func TestCheckedMetrics(t *testing.T) {
+	throttler := newTestThrottler()
+	assert.Equal(t, []base.ScopedMetric{{Name: base.LagMetricName}}, throttler.checkedMetrics(throttlerapp.OnlineDDLName.String()))
+	assert.Equal(t, []base.MetricName{base.LagMetricName}, throttler.collectedMetricNames())
+
+	throttler.storeMetricThresholds(5, map[string]float64{"threads_running": 10, "lag": 2, "unknown": 1})
+	assert.Equal(t, 2.0, throttler.GetMetricsThreshold())
+	assert.Equal(t, map[base.MetricName]float64{base.ThreadsRunningMetricName: 10}, throttler.GetMetricThresholds())
+	assert.Equal(t, []base.MetricName{base.LagMetricName, base.ThreadsRunningMetricName}, throttler.collectedMetricNames())
+
+	throttler.storeAppCheckedMetrics(map[string]*topodatapb.ThrottlerConfig_MetricNames{
+		throttlerapp.OnlineDDLName.String(): {Names: []string{"lag", "self/threads_running"}},
+		throttlerapp.VCopierName.String():   {Names: []string{"loadavg"}},
+		"invalid":                           {Names: []string{"lag", "threads"}},
+	})
+	onlineDDLMetrics := []base.ScopedMetric{{Name: base.LagMetricName}, {StoreName: selfStoreName, Name: base.ThreadsRunningMetricName}}
+	assert.Equal(t, onlineDDLMetrics, throttler.checkedMetrics(throttlerapp.OnlineDDLName.String()))
+	assert.Equal(t, onlineDDLMetrics, throttler.checkedMetrics(throttlerapp.OnlineDDLName.ConcatenateString("ghost")))
+	assert.Equal(t, []base.ScopedMetric{{Name: base.LoadAvgMetricName}}, throttler.checkedMetrics(throttlerapp.VReplicationName.Concatenate(throttlerapp.VCopierName).String()))
+	assert.Equal(t, []base.ScopedMetric{{Name: base.LagMetricName}}, throttler.checkedMetrics("invalid"))
+	assert.Equal(t, map[string]string{
+		throttlerapp.OnlineDDLName.String(): "lag,self/threads_running",
+		throttlerapp.VCopierName.String():   "loadavg",
+	}, throttler.appCheckedMetricsSnapshot())
+	// The metrics assigned to an app are collected, with their default threshold unless one is configured.
+	assert.Equal(t, map[base.MetricName]float64{
+		base.ThreadsRunningMetricName: 10,
+		base.LoadAvgMetricName:        1,
+	}, throttler.GetMetricThresholds())
+	assert.Equal(t, []base.MetricName{base.LagMetricName, base.ThreadsRunningMetricName, base.LoadAvgMetricName}, throttler.collectedMetricNames())
+
+	// With a custom query, the custom metric is the default metric.
+	throttler.customMetric.Store(true)
+	throttler.storeMetricThresholds(7, nil)
+	assert.Equal(t, 7.0, throttler.GetMetricsThreshold())
+	assert.Equal(t, map[base.MetricName]float64{
+		base.LagMetricName:            5,
+		base.ThreadsRunningMetricName: 100,
+		base.LoadAvgMetricName:        1,
+	}, throttler.GetMetricThresholds())
+	assert.Equal(t, []base.MetricName{base.CustomMetricName, base.LagMetricName, base.ThreadsRunningMetricName, base.LoadAvgMetricName}, throttler.collectedMetricNames())
+	assert.Equal(t, "mysql/self", throttler.aggregatedMetricName(selfStoreName, base.CustomMetricName))
+	assert.Equal(t, "mysql/shard/lag", throttler.aggregatedMetricName(shardStoreName, base.LagMetricName))
+}
+
+// TestMultiMetrics runs a throttler which collects the threads_running metric besides the replication lag,
+// and checks that the apps obey the metrics they are assigned.
+func TestMultiMetrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	throttler := newTestThrottler()
+	for _, s := range throttler.configSettings.Stores.MySQL.Clusters {
+		s.ThrottleThreshold.Store(math.Float64bits(5))
+	}
+	throttler.storeMetricThresholds(5, map[string]float64{"threads_running": 10})
+	throttler.storeAppCheckedMetrics(map[string]*topodatapb.ThrottlerConfig_MetricNames{
+		throttlerapp.OnlineDDLName.String(): {Names: []string{"lag", "self/threads_running"}},
+		"app1":                              {Names: []string{"threads_running"}},
+	})
+	var threadsRunning atomic.Int64
+	threadsRunning.Store(3)
+	throttler.readSelfThrottleMetrics = func(ctx context.Context, p *mysql.Probe) mysql.MySQLThrottleMetrics {
+		return mysql.MySQLThrottleMetrics{
+			base.LagMetricName: {
+				Name:        base.LagMetricName,
+				ClusterName: selfStoreName,
+				Value:       1,
+			},
+			base.ThreadsRunningMetricName: {
+				Name:        base.ThreadsRunningMetricName,
+				ClusterName: selfStoreName,
+				Value:       float64(threadsRunning.Load()),
+			},
+		}
+	}
+
+	runThrottler(t, ctx, throttler, time.Minute, func(t *testing.T, ctx context.Context) {
+		aggr := throttler.aggregatedMetricsSnapshot()
+		for _, metricName := range []string{"mysql/self", "mysql/shard", "mysql/self/threads_running", "mysql/shard/threads_running"} {
+			require.Contains(t, aggr, metricName)
+			_, err := aggr[metricName].Get()
+			assert.NoError(t, err, metricName)
+		}
+		// Metrics which neither have a configured threshold nor are assigned to an app are not collected.
+		assert.NotContains(t, aggr, "mysql/self/loadavg")
+		assert.NotContains(t, aggr, "mysql/self/history_list_length")
+
+		flags := &CheckFlags{}
+		checkResult := throttler.CheckByType(ctx, throttlerapp.OnlineDDLName.String(), "", flags, ThrottleCheckPrimaryWrite)
+		assert.Equal(t, http.StatusOK, checkResult.StatusCode)
+		require.Len(t, checkResult.Metrics, 2)
+		assert.Equal(t, float64(0), checkResult.Metrics["shard/lag"].Value)
+		assert.Equal(t, float64(3), checkResult.Metrics["self/threads_running"].Value)
+
+		threadsRunning.Store(50)
+		require.Eventually(t, func() bool {
+			checkResult = throttler.CheckByType(ctx, throttlerapp.OnlineDDLName.String(), "", flags, ThrottleCheckPrimaryWrite)
+			return checkResult.StatusCode == http.StatusTooManyRequests
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, float64(50), checkResult.Value)
+		assert.Equal(t, float64(10), checkResult.Threshold)
+		assert.Equal(t, http.StatusOK, checkResult.Metrics["shard/lag"].StatusCode)
+
+		// The other apps check the replication lag.
+		checkResult = throttler.CheckByType(ctx, throttlerapp.VReplicationName.String(), "", flags, ThrottleCheckPrimaryWrite)
+		assert.Equal(t, http.StatusOK, checkResult.StatusCode)
+		assert.Len(t, checkResult.Metrics, 1)
+		assert.Contains(t, checkResult.Metrics, "shard/lag")
+
+		// Unscoped metrics are checked on the store of the check.
+		checkResult = throttler.CheckByType(ctx, "app1:sub", "", flags, ThrottleCheckPrimaryWrite)
+		assert.Equal(t, http.StatusOK, checkResult.StatusCode)
+		assert.Equal(t, float64(3), checkResult.Value)
+		checkResult = throttler.CheckByType(ctx, "app1", "", flags, ThrottleCheckSelf)
+		assert.Equal(t, http.StatusTooManyRequests, checkResult.StatusCode)
+
+		// A primary probing this tablet gets the default metric, and all the collected metrics.
+		checkResult = throttler.CheckByType(ctx, throttlerapp.VitessName.String(), "", &CheckFlags{MultiMetricsEnabled: true}, ThrottleCheckSelf)
+		assert.Equal(t, http.StatusOK, checkResult.StatusCode)
+		assert.Equal(t, float64(1), checkResult.Value)
+		assert.Len(t, checkResult.Metrics, 2)
+		assert.Equal(t, http.StatusTooManyRequests, checkResult.Metrics["self/threads_running"].StatusCode)
+
+		cancel() // end test early
+	})
+}
+
+func TestMultiMetricsThrottledAppRatio(t *testing.T) {
+	ctx := context.Background()
+	throttler := newTestThrottler()
+	throttler.ThrottleApp("app1", time.Now().Add(time.Hour), 0.5, false)
+
+	checkedMetrics := []base.ScopedMetric{
+		{Name: base.LagMetricName},
+		{Name: base.ThreadsRunningMetricName},
+		{Name: base.LoadAvgMetricName},
+		{Name: base.HistoryListLengthMetricName},
+	}
+	var denied, allowed int
+	for i := 0; i < 100; i++ {
+		checkResult := throttler.check.checkMetrics(ctx, "app1", "mysql", selfStoreName, "", checkedMetrics, &CheckFlags{})
+		require.Len(t, checkResult.Metrics, len(checkedMetrics))
+		// The app is either throttled on all the metrics of a check, or on none of them.
+		for _, metricCheckResult := range checkResult.Metrics {
+			assert.Equal(t, checkResult.StatusCode, metricCheckResult.StatusCode)
+		}
+		if checkResult.StatusCode == http.StatusExpectationFailed {
+			denied++
+		} else {
+			allowed++
+		}
+	}
+	assert.NotZero(t, denied)
+	assert.NotZero(t, allowed)
+}